@@ -58,6 +58,7 @@ func init() {
 	compactAliasCmd.Flags().IntVar(&compactTier, "tier", 1, "Compaction tier (1 or 2)")
 	compactAliasCmd.Flags().BoolVar(&compactAll, "all", false, "Process all candidates")
 	compactAliasCmd.Flags().StringVar(&compactID, "id", "", "Compact specific issue")
+	compactAliasCmd.Flags().StringVar(&compactEpic, "epic", "", "Roll up a closed epic and its descendants (Tier 2; previews without --auto)")
 	compactAliasCmd.Flags().BoolVar(&compactForce, "force", false, "Force compact (bypass checks, requires --id)")
 	compactAliasCmd.Flags().IntVar(&compactBatch, "batch-size", 10, "Issues per batch")
 	compactAliasCmd.Flags().IntVar(&compactWorkers, "workers", 5, "Parallel workers")
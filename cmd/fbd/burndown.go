@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/utils"
+)
+
+var burndownCmd = &cobra.Command{
+	Use:     "burndown <epic-id>",
+	GroupID: "views",
+	Short:   "Burndown chart of an epic's remaining issues",
+	Long: `Show an epic's scope and remaining issues at the end of each day.
+
+The epic's descendants (via parent-child dependencies) are replayed from
+the status changes in the event log, or from the Dolt commit history with
+--source history. Scope counts the issues created so far; remaining counts
+those not closed. If the epic has a due date, an ideal line runs from the
+first day's remaining count down to zero on that date.
+
+The chart starts on the day the epic was created unless --since is given.
+
+Output formats:
+  table (default)  Bar per day
+  json             Same as --json
+  csv              One row per day
+  svg              Line chart
+
+Examples:
+  fbd burndown bd-42
+  fbd burndown bd-42 --since 14d
+  fbd burndown bd-42 --format svg > burndown.svg`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		untilStr, _ := cmd.Flags().GetString("until")
+		source, _ := cmd.Flags().GetString("source")
+		width, _ := cmd.Flags().GetInt("width")
+
+		format = flowFormat(format)
+		if width < 10 {
+			FatalErrorRespectJSON("--width must be at least 10")
+		}
+		since, until := parseFlowWindow(sinceStr, untilStr)
+
+		if err := ensureDirectMode("burndown requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		requireFreshDB(ctx)
+
+		epicID, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+		}
+		epic, issues, err := loadEpicDescendants(ctx, store, epicID)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if since.IsZero() {
+			since = epic.CreatedAt
+		}
+		events, err := loadFlowEvents(ctx, store, source, issues)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		bd, err := metrics.ComputeBurndown(issues, events, since, until, epic.DueAt)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		switch format {
+		case "json":
+			outputJSON(bd)
+		case "csv":
+			if err := bd.WriteCSV(os.Stdout); err != nil {
+				FatalError("writing CSV: %v", err)
+			}
+		case "svg":
+			renderBurndownSVG(os.Stdout, fmt.Sprintf("Burndown %s: %s", epic.ID, epic.Title), bd)
+		default:
+			fmt.Printf("\n%s Burndown for %s: %s\n", ui.RenderAccent("📉"), ui.RenderID(epic.ID), epic.Title)
+			if len(issues) == 0 {
+				fmt.Printf("\nNo child issues\n\n")
+				return
+			}
+			last := bd.Days[len(bd.Days)-1]
+			fmt.Printf("  %d of %d issues remaining", last.Remaining, last.Scope)
+			if epic.DueAt != nil {
+				fmt.Printf(", due %s", epic.DueAt.Local().Format("2006-01-02"))
+			}
+			fmt.Printf("\n\n")
+			renderBurndownChart(os.Stdout, bd, width)
+			fmt.Println()
+		}
+	},
+}
+
+// loadEpicDescendants loads an epic and every issue below it in the
+// parent-child hierarchy.
+func loadEpicDescendants(ctx context.Context, s storage.Storage, epicID string) (*types.Issue, []*types.Issue, error) {
+	epic, err := s.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	if epic == nil {
+		return nil, nil, fmt.Errorf("issue %s not found", epicID)
+	}
+
+	seen := map[string]bool{epic.ID: true}
+	var descendants []*types.Issue
+	for queue := []string{epic.ID}; len(queue) > 0; queue = queue[1:] {
+		parentID := queue[0]
+		children, err := s.SearchIssues(ctx, "", types.IssueFilter{ParentID: &parentID})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load children of %s: %w", parentID, err)
+		}
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				descendants = append(descendants, child)
+				queue = append(queue, child.ID)
+			}
+		}
+	}
+	return epic, descendants, nil
+}
+
+func init() {
+	addFlowChartFlags(burndownCmd, "")
+	rootCmd.AddCommand(burndownCmd)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestLoadEpicDescendants(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newTestStore(t, filepath.Join(tmpDir, ".beads", "beads.db"))
+	ctx := context.Background()
+
+	create := func(title string, issueType types.IssueType) *types.Issue {
+		issue := &types.Issue{Title: title, Priority: 2, IssueType: issueType, Status: types.StatusOpen}
+		if err := s.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	addDep := func(from, to *types.Issue, depType types.DependencyType) {
+		dep := &types.Dependency{IssueID: from.ID, DependsOnID: to.ID, Type: depType}
+		if err := s.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency: %v", err)
+		}
+	}
+
+	epic := create("Epic", types.TypeEpic)
+	feature := create("Feature", types.TypeFeature)
+	task := create("Task", types.TypeTask)
+	blocker := create("Blocker elsewhere", types.TypeTask)
+	addDep(feature, epic, types.DepParentChild)
+	addDep(task, feature, types.DepParentChild)
+	addDep(task, blocker, types.DepBlocks)
+
+	gotEpic, issues, err := loadEpicDescendants(ctx, s, epic.ID)
+	if err != nil {
+		t.Fatalf("loadEpicDescendants: %v", err)
+	}
+	if gotEpic.ID != epic.ID {
+		t.Errorf("epic = %s, want %s", gotEpic.ID, epic.ID)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.ID)
+	}
+	want := []string{feature.ID, task.ID}
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("descendants = %v, want %v", got, want)
+	}
+
+	if _, _, err := loadEpicDescendants(ctx, s, "bd-missing"); err == nil {
+		t.Error("expected an error for a missing epic")
+	}
+}
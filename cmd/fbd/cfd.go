@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/timeparsing"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+// Sources of status history for the flow charts.
+const (
+	flowSourceEvents  = "events"
+	flowSourceHistory = "history"
+)
+
+var cfdCmd = &cobra.Command{
+	Use:     "cfd",
+	GroupID: "views",
+	Short:   "Cumulative flow diagram of daily status counts",
+	Long: `Show how many issues were in each status at the end of each day.
+
+Status counts are reconstructed by replaying the status changes in the
+event log. With --source history on the Dolt backend they are taken from
+the commit history instead, at commit granularity.
+
+Output formats:
+  table (default)  Stacked bar per day
+  json             Same as --json
+  csv              One row per day, one column per status
+  svg              Stacked area chart
+
+Examples:
+  fbd cfd                            # Last 30 days
+  fbd cfd --since 90d --type bug
+  fbd cfd --label backend --format svg > cfd.svg`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		untilStr, _ := cmd.Flags().GetString("until")
+		source, _ := cmd.Flags().GetString("source")
+		width, _ := cmd.Flags().GetInt("width")
+
+		format = flowFormat(format)
+		if width < 10 {
+			FatalErrorRespectJSON("--width must be at least 10")
+		}
+		since, until := parseFlowWindow(sinceStr, untilStr)
+		filter := flowFilterFromFlags(cmd)
+
+		if err := ensureDirectMode("cfd requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		requireFreshDB(ctx)
+
+		issues, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			FatalErrorRespectJSON("failed to load issues: %v", err)
+		}
+		events, err := loadFlowEvents(ctx, store, source, issues)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		flow, err := metrics.CumulativeFlow(issues, events, since, until)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		switch format {
+		case "json":
+			outputJSON(flow)
+		case "csv":
+			if err := flow.WriteCSV(os.Stdout); err != nil {
+				FatalError("writing CSV: %v", err)
+			}
+		case "svg":
+			renderFlowSVG(os.Stdout, "Cumulative flow", flow)
+		default:
+			fmt.Printf("\n%s Cumulative flow %s → %s (%d issues)\n\n", ui.RenderAccent("📊"),
+				flow.Since.Format("2006-01-02"), flow.Until.Format("2006-01-02"), len(issues))
+			renderFlowChart(os.Stdout, flow, width)
+			fmt.Println()
+		}
+	},
+}
+
+// flowFormat validates --format for the flow charts.
+func flowFormat(format string) string {
+	if jsonOutput {
+		return "json"
+	}
+	switch format {
+	case "table", "json", "csv", "svg":
+		return format
+	}
+	FatalErrorRespectJSON("invalid format %q (must be table, json, csv or svg)", format)
+	return ""
+}
+
+// parseFlowWindow parses --since and --until. An empty --since is returned
+// as the zero time; an empty --until means now.
+func parseFlowWindow(sinceStr, untilStr string) (time.Time, time.Time) {
+	now := time.Now()
+	var since time.Time
+	var err error
+	if sinceStr != "" {
+		if since, err = parseSinceFlag(sinceStr, now); err != nil {
+			FatalErrorRespectJSON("invalid --since: %v", err)
+		}
+	}
+	until := now
+	if untilStr != "" {
+		if until, err = timeparsing.ParseRelativeTime(untilStr, now); err != nil {
+			FatalErrorRespectJSON("invalid --until: %v", err)
+		}
+	}
+	return since, until
+}
+
+// loadFlowEvents loads the status history of issues from the events table,
+// or from the Dolt commit history for flowSourceHistory.
+func loadFlowEvents(ctx context.Context, s storage.Storage, source string, issues []*types.Issue) ([]*types.Event, error) {
+	switch source {
+	case flowSourceEvents:
+		return loadIssueEvents(ctx, s, issues)
+	case flowSourceHistory:
+		vs, ok := storage.AsVersioned(s)
+		if !ok {
+			return nil, fmt.Errorf("--source history requires Dolt backend (current backend does not support versioning)")
+		}
+		return historyEvents(ctx, vs, issues)
+	}
+	return nil, fmt.Errorf("invalid source %q (must be events or history)", source)
+}
+
+// historyEvents synthesizes status_changed events from the commits that
+// changed each issue's status. An issue starts open at its created_at, so
+// only commits that moved it off its previous status produce an event.
+func historyEvents(ctx context.Context, vs storage.VersionedStorage, issues []*types.Issue) ([]*types.Event, error) {
+	var events []*types.Event
+	for _, issue := range issues {
+		entries, err := vs.History(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history for %s: %w", issue.ID, err)
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].CommitDate.Before(entries[j].CommitDate)
+		})
+		prev := types.StatusOpen
+		for _, entry := range entries {
+			if entry.Issue == nil || entry.Issue.Status == prev {
+				continue
+			}
+			prev = entry.Issue.Status
+			value := fmt.Sprintf(`{"status":%q}`, prev)
+			events = append(events, &types.Event{
+				ID:        int64(len(events) + 1),
+				IssueID:   issue.ID,
+				EventType: types.EventStatusChanged,
+				Actor:     entry.Committer,
+				NewValue:  &value,
+				CreatedAt: entry.CommitDate,
+			})
+		}
+	}
+	return events, nil
+}
+
+// addFlowChartFlags registers the flags shared by cfd and burndown.
+func addFlowChartFlags(cmd *cobra.Command, defaultSince string) {
+	cmd.Flags().String("format", "table", "Output format: table, json, csv, svg")
+	cmd.Flags().String("since", defaultSince, "First day, e.g. 30d or 2026-01-01")
+	cmd.Flags().String("until", "", "Last day (default today)")
+	cmd.Flags().String("source", flowSourceEvents, "Status history source: events, or history (Dolt commits)")
+	cmd.Flags().Int("width", 60, "Chart width in columns")
+}
+
+func init() {
+	addFlowChartFlags(cfdCmd, "30d")
+	addFlowFilterFlags(cfdCmd)
+	rootCmd.AddCommand(cfdCmd)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -8,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/fastbeads/internal/hooks"
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/ui"
 	"github.com/steveyegge/fastbeads/internal/utils"
@@ -68,6 +72,124 @@ create, update, show, or close operation).`,
 			FatalErrorRespectJSON("--suggest-next only works when closing a single issue")
 		}
 
+		// --continue advances through the molecule graph, which needs direct access
+		if continueFlag && daemonClient != nil {
+			if err := ensureDirectMode("close --continue requires direct database access"); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		// Mail gates are checked against the local store, which needs direct access
+		if !force && daemonClient != nil && closesMailGate(args) {
+			if err := ensureDirectMode("closing a mail gate requires direct database access"); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		// If daemon is running, use RPC
+		if daemonClient != nil {
+			closedIssues := []*types.Issue{}
+			var unblocked []*types.Issue
+			var expandIDs []string // Closed steps with on_complete.for_each
+			for _, id := range args {
+				resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: id})
+				if err != nil {
+					FatalErrorRespectJSON("resolving ID %s: %v", id, err)
+				}
+				var resolvedID string
+				if err := json.Unmarshal(resp.Data, &resolvedID); err != nil {
+					FatalErrorRespectJSON("unmarshaling resolved ID: %v", err)
+				}
+
+				// Get issue for checks (the daemon checks templates and blockers itself)
+				resp, err = daemonClient.Show(&rpc.ShowArgs{ID: resolvedID})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", resolvedID, err)
+					continue
+				}
+				var issue types.Issue
+				if err := json.Unmarshal(resp.Data, &issue); err != nil {
+					fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", resolvedID, err)
+					continue
+				}
+				if err := validateIssueClosable(resolvedID, &issue, force); err != nil {
+					fmt.Fprintf(os.Stderr, "%s\n", err)
+					continue
+				}
+				if !force {
+					if err := checkGateSatisfaction(&issue); err != nil {
+						fmt.Fprintf(os.Stderr, "cannot close %s: %s\n", resolvedID, err)
+						continue
+					}
+				}
+
+				closeArgs := &rpc.CloseArgs{
+					ID:          resolvedID,
+					Reason:      reason,
+					Session:     session,
+					SuggestNext: suggestNext,
+					Force:       force,
+				}
+				resp, err = daemonClient.CloseIssue(closeArgs)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", resolvedID, err)
+					continue
+				}
+
+				var closedIssue *types.Issue
+				if suggestNext {
+					var result rpc.CloseResult
+					if err := json.Unmarshal(resp.Data, &result); err == nil {
+						closedIssue = result.Closed
+						unblocked = result.Unblocked
+					}
+				} else {
+					_ = json.Unmarshal(resp.Data, &closedIssue)
+				}
+
+				// Run close hook
+				if closedIssue != nil && hookRunner != nil {
+					hookRunner.Run(hooks.EventClose, closedIssue)
+				}
+				if oc, _, _ := parseStepMetadata(&issue); oc != nil {
+					expandIDs = append(expandIDs, resolvedID)
+				}
+
+				if jsonOutput {
+					if closedIssue != nil {
+						closedIssues = append(closedIssues, closedIssue)
+					}
+				} else {
+					fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), resolvedID, reason)
+				}
+			}
+
+			// Pouring on_complete bonds clones formulas, which needs direct access
+			var forEachResults []*ForEachResult
+			if len(expandIDs) > 0 {
+				if err := ensureDirectMode("close on_complete requires direct database access"); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not run on_complete: %v\n", err)
+				} else {
+					forEachResults = runOnCompleteAll(ctx, store, expandIDs)
+				}
+			}
+
+			if suggestNext && len(unblocked) > 0 {
+				if jsonOutput {
+					outputJSON(closeJSONResult(closedIssues, forEachResults, "unblocked", unblocked))
+					return
+				}
+				fmt.Printf("\nNewly unblocked:\n")
+				for _, issue := range unblocked {
+					fmt.Printf("  • %s %q (P%d)\n", issue.ID, issue.Title, issue.Priority)
+				}
+			}
+			if jsonOutput && len(closedIssues) > 0 {
+				outputJSON(closeJSONResult(closedIssues, forEachResults, "", nil))
+			}
+			return
+		}
+
 		// Resolve partial IDs first, handling cross-rig routing
 		var resolvedIDs []string
 		var routedArgs []string // IDs that need cross-repo routing (bypass daemon)
@@ -87,6 +209,7 @@ create, update, show, or close operation).`,
 		// Direct mode
 		closedIssues := []*types.Issue{}
 		closedCount := 0
+		var forEachResults []*ForEachResult
 
 		// Handle local IDs
 		for _, id := range resolvedIDs {
@@ -139,6 +262,7 @@ create, update, show, or close operation).`,
 			} else {
 				fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), id, reason)
 			}
+			forEachResults = append(forEachResults, runOnCompleteAll(ctx, store, []string{id})...)
 		}
 
 		// Handle routed IDs (cross-rig)
@@ -207,6 +331,7 @@ create, update, show, or close operation).`,
 			} else {
 				fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), result.ResolvedID, reason)
 			}
+			forEachResults = append(forEachResults, runOnCompleteAll(ctx, result.Store, []string{result.ResolvedID})...)
 			result.Close()
 		}
 
@@ -215,10 +340,7 @@ create, update, show, or close operation).`,
 			unblocked, err := store.GetNewlyUnblockedByClose(ctx, resolvedIDs[0])
 			if err == nil && len(unblocked) > 0 {
 				if jsonOutput {
-					outputJSON(map[string]interface{}{
-						"closed":    closedIssues,
-						"unblocked": unblocked,
-					})
+					outputJSON(closeJSONResult(closedIssues, forEachResults, "unblocked", unblocked))
 					return
 				}
 				fmt.Printf("\nNewly unblocked:\n")
@@ -237,10 +359,7 @@ create, update, show, or close operation).`,
 			} else if result != nil {
 				if jsonOutput {
 					// Include continue result in JSON output
-					outputJSON(map[string]interface{}{
-						"closed":   closedIssues,
-						"continue": result,
-					})
+					outputJSON(closeJSONResult(closedIssues, forEachResults, "continue", result))
 					return
 				}
 				PrintContinueResult(result)
@@ -248,11 +367,53 @@ create, update, show, or close operation).`,
 		}
 
 		if jsonOutput && len(closedIssues) > 0 {
-			outputJSON(closedIssues)
+			outputJSON(closeJSONResult(closedIssues, forEachResults, "", nil))
 		}
 	},
 }
 
+// runOnCompleteAll expands on_complete.for_each for each closed step.
+// Failures are reported as warnings: the steps are already closed, and
+// closing them again retries the expansion.
+func runOnCompleteAll(ctx context.Context, s storage.Storage, ids []string) []*ForEachResult {
+	var results []*ForEachResult
+	for _, id := range ids {
+		result, err := runOnComplete(ctx, s, id, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			fmt.Fprintf(os.Stderr, "  Fix the step output and close %s again to retry.\n", id)
+		}
+		if result == nil {
+			continue
+		}
+		results = append(results, result)
+		// Keep stdout to the closed issues in JSON mode
+		if jsonOutput {
+			printForEachResult(os.Stderr, result)
+		} else {
+			printForEachResult(os.Stdout, result)
+		}
+	}
+	return results
+}
+
+// closeJSONResult builds the JSON output of close: the closed issues alone,
+// or the object --suggest-next and --continue report with. on_complete
+// results only appear in that object; otherwise they go to stderr.
+func closeJSONResult(closed []*types.Issue, forEach []*ForEachResult, extraKey string, extra interface{}) interface{} {
+	if extraKey == "" {
+		return closed
+	}
+	out := map[string]interface{}{"closed": closed}
+	if len(forEach) > 0 {
+		out["for_each"] = forEach
+	}
+	if extraKey != "" {
+		out[extraKey] = extra
+	}
+	return out
+}
+
 func init() {
 	closeCmd.Flags().StringP("reason", "r", "", "Reason for closing")
 	closeCmd.Flags().String("resolution", "", "Alias for --reason (Jira CLI convention)")
@@ -280,6 +441,8 @@ func isMachineCheckableGate(issue *types.Issue) bool {
 		return true
 	case issue.AwaitType == "timer":
 		return true
+	case issue.AwaitType == "mail":
+		return true
 	case issue.AwaitType == "bead":
 		return true
 	default:
@@ -287,6 +450,34 @@ func isMachineCheckableGate(issue *types.Issue) bool {
 	}
 }
 
+// closesMailGate reports whether any of ids is a mail gate, looking the
+// issues up through the daemon. IDs that fail to resolve are left for the
+// close itself to report.
+func closesMailGate(ids []string) bool {
+	for _, id := range ids {
+		resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: id})
+		if err != nil {
+			continue
+		}
+		var resolvedID string
+		if err := json.Unmarshal(resp.Data, &resolvedID); err != nil {
+			continue
+		}
+		resp, err = daemonClient.Show(&rpc.ShowArgs{ID: resolvedID})
+		if err != nil {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(resp.Data, &issue); err != nil {
+			continue
+		}
+		if issue.IssueType == "gate" && issue.AwaitType == "mail" {
+			return true
+		}
+	}
+	return false
+}
+
 // checkGateSatisfaction checks whether a gate issue's condition is satisfied.
 // Returns nil if the gate is satisfied (or not a machine-checkable gate), or an error describing why it cannot be closed.
 func checkGateSatisfaction(issue *types.Issue) error {
@@ -306,6 +497,8 @@ func checkGateSatisfaction(issue *types.Issue) error {
 		resolved, escalated, reason, err = checkGHPR(issue)
 	case issue.AwaitType == "timer":
 		resolved, escalated, reason, err = checkTimer(issue, time.Now())
+	case issue.AwaitType == "mail":
+		resolved, reason, err = checkMailGate(rootCtx, store, issue)
 	case issue.AwaitType == "bead":
 		resolved, reason = checkBeadGate(rootCtx, issue.AwaitID)
 		if resolved {
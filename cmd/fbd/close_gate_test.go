@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
@@ -57,6 +60,14 @@ func TestIsMachineCheckableGate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "gate with mail await type",
+			issue: &types.Issue{
+				IssueType: "gate",
+				AwaitType: "mail",
+			},
+			want: true,
+		},
 		{
 			name: "gate with bead await type",
 			issue: &types.Issue{
@@ -201,3 +212,67 @@ func TestCheckGateSatisfaction_ErrorMessageFormat(t *testing.T) {
 		t.Errorf("error message should mention 'gate condition not satisfied', got: %s", errMsg)
 	}
 }
+
+// TestCloseMailGateViaDaemon closes mail gates while 'fbd serve' is running.
+// The daemon never opens the CLI's store, so the mail check must switch to
+// direct mode rather than query a nil store.
+func TestCloseMailGateViaDaemon(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping daemon test in short mode")
+	}
+	saveAndRestoreGlobals(t)
+	ensureCleanGlobalState(t)
+	beadsDir := setupServeTestEnv(t)
+	t.Chdir(filepath.Dir(beadsDir))
+
+	ctx := context.Background()
+	origCtx := rootCtx
+	rootCtx = ctx
+	t.Cleanup(func() { rootCtx = origCtx })
+
+	seed, err := sqlite.New(ctx, filepath.Join(beadsDir, "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newGate := func() *types.Issue {
+		t.Helper()
+		gate := &types.Issue{Title: "Await approval", Status: types.StatusOpen, Priority: 2,
+			IssueType: "gate", AwaitType: "mail", Waiters: []string{"mayor/"}}
+		if err := seed.CreateIssue(ctx, gate, "test"); err != nil {
+			t.Fatal(err)
+		}
+		return gate
+	}
+	replied, waiting := newGate(), newGate()
+	msg := &types.Issue{Title: "Re: approval", Status: types.StatusOpen, Priority: 2,
+		IssueType: "message", Sender: "mayor/"}
+	if err := seed.CreateIssue(ctx, msg, "test"); err != nil {
+		t.Fatal(err)
+	}
+	dep := &types.Dependency{IssueID: msg.ID, DependsOnID: replied.ID, Type: types.DepRepliesTo}
+	if err := seed.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatal(err)
+	}
+	_ = seed.Close()
+
+	for _, id := range []string{replied.ID, waiting.ID} {
+		store, storeActive = nil, false
+		if !connectToDaemon(closeCmd, []string{id}) {
+			t.Fatalf("expected close to route through daemon, status: %+v", daemonStatus)
+		}
+		closeCmd.Run(closeCmd, []string{id})
+	}
+
+	if store == nil {
+		t.Fatal("store not opened for the mail gate check")
+	}
+	for id, want := range map[string]types.Status{replied.ID: types.StatusClosed, waiting.ID: types.StatusOpen} {
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issue.Status != want {
+			t.Errorf("%s status = %q, want %q", id, issue.Status, want)
+		}
+	}
+}
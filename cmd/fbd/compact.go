@@ -21,6 +21,7 @@ var (
 	compactTier            int
 	compactAll             bool
 	compactID              string
+	compactEpic            string
 	compactForce           bool
 	compactBatch           int
 	compactWorkers         int
@@ -49,13 +50,28 @@ Modes:
   - Prune: Remove expired tombstones from issues.jsonl (no API key needed)
   - Analyze: Export candidates for agent review (no API key needed)
   - Apply: Accept agent-provided summary (no API key needed)
-  - Auto: AI-powered compaction (requires ANTHROPIC_API_KEY, legacy)
+  - Auto: AI-powered compaction (legacy; summarizer set by ai.provider)
   - Dolt: Run Dolt garbage collection (for Dolt-backend repositories)
 
+Summarizer providers (--auto):
+  Set with 'fbd config set ai.provider <name>':
+  - anthropic: Claude via the Anthropic API (default; needs ANTHROPIC_API_KEY)
+  - openai: any OpenAI-compatible endpoint, e.g. a local Ollama or llama.cpp
+    server (ai.base-url, ai.model; OPENAI_API_KEY if the endpoint needs one)
+  - extractive: deterministic summary from the issue's own sentences; no
+    network or model, suitable for air-gapped CI
+
 Tiers:
   - Tier 1: Semantic compression (30 days closed, 70% reduction)
   - Tier 2: Ultra compression (90 days closed, 95% reduction)
 
+Epic rollup (Tier 2):
+  A closed epic whose descendants have all been closed for compact_tier2_days
+  (default 90) can be collapsed into a single summary on the epic. The
+  descendants become tombstones that keep their IDs; 'fbd restore <id>'
+  recovers any of them from git. Dependencies on issues outside the epic
+  move to the epic. Without --auto, --epic only previews.
+
 Tombstone Cleanup:
   Tombstones are soft-delete markers that prevent resurrection of deleted issues.
 
@@ -97,12 +113,18 @@ Examples:
   fbd compact --auto --all                  # Compact all eligible issues
   fbd compact --auto --id bd-42             # Compact specific issue
 
+  # Epic rollup
+  fbd compact --tier 2 --epic bd-7          # Preview what would be rolled up
+  fbd compact --auto --tier 2 --epic bd-7   # Summarize and roll up the epic
+
   # Statistics
   fbd compact --stats                       # Show statistics
 `,
 	Run: func(_ *cobra.Command, _ []string) {
-		// Compact modifies data unless --stats or --analyze or --dry-run or --dolt with --dry-run
-		if !compactStats && !compactAnalyze && !compactDryRun && !(compactDolt && compactDryRun) {
+		// Compact modifies data unless --stats or --analyze or --dry-run or --dolt with --dry-run,
+		// or --epic without --auto (preview)
+		epicPreview := compactEpic != "" && !compactAuto
+		if !compactStats && !compactAnalyze && !compactDryRun && !(compactDolt && compactDryRun) && !epicPreview {
 			CheckReadonly("compact")
 		}
 		ctx := rootCtx
@@ -136,6 +158,27 @@ Examples:
 			return
 		}
 
+		// Handle epic rollup preview (no summarizer needed)
+		if compactEpic != "" {
+			if compactTier != 2 {
+				fmt.Fprintf(os.Stderr, "Error: --epic rolls up at Tier 2; use --tier 2\n")
+				os.Exit(1)
+			}
+			if compactID != "" || compactAll || compactAnalyze || compactApply {
+				fmt.Fprintf(os.Stderr, "Error: --epic cannot be combined with --id, --all, --analyze or --apply\n")
+				os.Exit(1)
+			}
+			if epicPreview || compactDryRun {
+				compactStore, ok := store.(storage.CompactableStorage)
+				if !ok {
+					fmt.Fprintf(os.Stderr, "Error: compact requires CompactableStorage (not supported by current backend)\n")
+					os.Exit(1)
+				}
+				runCompactEpicPreview(ctx, compactStore)
+				return
+			}
+		}
+
 		// Count active modes
 		activeModes := 0
 		if compactAnalyze {
@@ -209,17 +252,13 @@ Examples:
 				fmt.Fprintf(os.Stderr, "Error: --force requires --id\n")
 				os.Exit(1)
 			}
-			if compactID == "" && !compactAll && !compactDryRun {
-				fmt.Fprintf(os.Stderr, "Error: must specify --all, --id, or --dry-run\n")
+			if compactID == "" && compactEpic == "" && !compactAll && !compactDryRun {
+				fmt.Fprintf(os.Stderr, "Error: must specify --all, --id, --epic, or --dry-run\n")
 				os.Exit(1)
 			}
 
 			// Direct mode
-			apiKey := os.Getenv("ANTHROPIC_API_KEY")
-			if apiKey == "" && !compactDryRun {
-				fmt.Fprintf(os.Stderr, "Error: --auto mode requires ANTHROPIC_API_KEY environment variable\n")
-				os.Exit(1)
-			}
+			apiKey := requireCompactAPIKey()
 
 			compactStore, ok := store.(storage.CompactableStorage)
 			if !ok {
@@ -239,6 +278,10 @@ Examples:
 				os.Exit(1)
 			}
 
+			if compactEpic != "" {
+				runCompactEpic(ctx, compactor, compactStore, compactEpic)
+				return
+			}
 			if compactID != "" {
 				runCompactSingle(ctx, compactor, compactStore, compactID)
 				return
@@ -249,6 +292,22 @@ Examples:
 	},
 }
 
+// requireCompactAPIKey returns the API key for the configured summarizer,
+// exiting if the provider needs one and it isn't set. Only the Anthropic
+// provider requires a key; the others read their own (optional) keys.
+func requireCompactAPIKey() string {
+	if compact.ConfiguredProvider() != compact.ProviderAnthropic {
+		return ""
+	}
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" && !compactDryRun {
+		fmt.Fprintf(os.Stderr, "Error: --auto mode requires ANTHROPIC_API_KEY environment variable\n")
+		fmt.Fprintf(os.Stderr, "Hint: set ai.provider to openai or extractive to compact without it\n")
+		os.Exit(1)
+	}
+	return apiKey
+}
+
 func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store storage.CompactableStorage, issueID string) {
 	start := time.Now()
 
@@ -295,8 +354,11 @@ func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store s
 	var compactErr error
 	if compactTier == 1 {
 		compactErr = compactor.CompactTier1(ctx, issueID)
+	} else if issue.IssueType == types.TypeEpic {
+		runCompactEpic(ctx, compactor, store, issueID)
+		return
 	} else {
-		fmt.Fprintf(os.Stderr, "Error: Tier 2 compaction not yet implemented\n")
+		fmt.Fprintf(os.Stderr, "Error: Tier 2 compaction is only implemented for epics (see --epic)\n")
 		os.Exit(1)
 	}
 
@@ -345,6 +407,101 @@ func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store s
 	}
 }
 
+// runCompactEpicPreview shows what a Tier 2 rollup of --epic would cover.
+func runCompactEpicPreview(ctx context.Context, store storage.CompactableStorage) {
+	compactor, err := compact.New(store, "", &compact.Config{DryRun: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create compactor: %v\n", err)
+		os.Exit(1)
+	}
+	rollup, err := compactor.PlanEpicRollup(ctx, compactEpic)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		descendants := make([]map[string]interface{}, 0, len(rollup.Descendants))
+		for _, d := range rollup.Descendants {
+			descendants = append(descendants, map[string]interface{}{
+				"id":        d.ID,
+				"title":     d.Title,
+				"type":      d.IssueType,
+				"closed_at": d.ClosedAt,
+				"size":      len(d.Description) + len(d.Design) + len(d.Notes) + len(d.AcceptanceCriteria),
+			})
+		}
+		outputJSON(map[string]interface{}{
+			"dry_run":       true,
+			"tier":          2,
+			"epic_id":       rollup.Epic.ID,
+			"title":         rollup.Epic.Title,
+			"descendants":   descendants,
+			"original_size": rollup.OriginalSize,
+		})
+		return
+	}
+
+	fmt.Printf("DRY RUN - Tier 2 rollup\n\n")
+	fmt.Printf("Epic: %s %s\n", rollup.Epic.ID, rollup.Epic.Title)
+	fmt.Printf("Descendants to tombstone: %d\n", len(rollup.Descendants))
+	for _, d := range rollup.Descendants {
+		size := len(d.Description) + len(d.Design) + len(d.Notes) + len(d.AcceptanceCriteria)
+		fmt.Printf("  %s %s (%s)\n", d.ID, d.Title, formatBytes(int64(size)))
+	}
+	fmt.Printf("Original size: %d bytes\n", rollup.OriginalSize)
+	fmt.Printf("Estimated reduction: 90-95%%\n")
+	if !compactAuto {
+		fmt.Printf("\nRun with --auto to summarize and roll up.\n")
+	}
+}
+
+// runCompactEpic rolls a closed epic and its descendants up into one summary.
+func runCompactEpic(ctx context.Context, compactor *compact.Compactor, store storage.CompactableStorage, epicID string) {
+	start := time.Now()
+
+	rollup, err := compactor.PlanEpicRollup(ctx, epicID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := compactor.CompactTier2(ctx, epicID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	epic, err := store.GetIssue(ctx, epicID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to get updated issue: %v\n", err)
+		os.Exit(1)
+	}
+
+	compactedSize := len(epic.Description)
+	savingBytes := rollup.OriginalSize - compactedSize
+	elapsed := time.Since(start)
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"success":        true,
+			"tier":           2,
+			"epic_id":        epicID,
+			"rolled_up":      len(rollup.Descendants),
+			"original_size":  rollup.OriginalSize,
+			"compacted_size": compactedSize,
+			"saved_bytes":    savingBytes,
+			"reduction_pct":  float64(savingBytes) / float64(rollup.OriginalSize) * 100,
+			"elapsed_ms":     elapsed.Milliseconds(),
+		})
+		return
+	}
+
+	fmt.Printf("✓ Rolled up %s and %d descendants (Tier 2)\n", epicID, len(rollup.Descendants))
+	fmt.Printf("  %d → %d bytes (saved %d, %.1f%%)\n",
+		rollup.OriginalSize, compactedSize, savingBytes,
+		float64(savingBytes)/float64(rollup.OriginalSize)*100)
+	fmt.Printf("  Time: %v\n", elapsed)
+}
+
 func runCompactAll(ctx context.Context, compactor *compact.Compactor, store storage.CompactableStorage) {
 	start := time.Now()
 
@@ -899,6 +1056,7 @@ func init() {
 	compactCmd.Flags().IntVar(&compactTier, "tier", 1, "Compaction tier (1 or 2)")
 	compactCmd.Flags().BoolVar(&compactAll, "all", false, "Process all candidates")
 	compactCmd.Flags().StringVar(&compactID, "id", "", "Compact specific issue")
+	compactCmd.Flags().StringVar(&compactEpic, "epic", "", "Roll up a closed epic and its descendants (Tier 2; previews without --auto)")
 	compactCmd.Flags().BoolVar(&compactForce, "force", false, "Force compact (bypass checks, requires --id)")
 	compactCmd.Flags().IntVar(&compactBatch, "batch-size", 10, "Issues per batch")
 	compactCmd.Flags().IntVar(&compactWorkers, "workers", 5, "Parallel workers")
@@ -41,11 +41,7 @@ func runCompactRPC(_ context.Context) {
 		os.Exit(1)
 	}
 
-	apiKey := os.Getenv("ANTHROPIC_API_KEY")
-	if apiKey == "" && !compactDryRun {
-		fmt.Fprintf(os.Stderr, "Error: ANTHROPIC_API_KEY environment variable not set\n")
-		os.Exit(1)
-	}
+	apiKey := requireCompactAPIKey()
 
 	args := map[string]interface{}{
 		"tier":       compactTier,
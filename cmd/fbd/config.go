@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/steveyegge/fastbeads/cmd/fbd/doctor"
 	"github.com/steveyegge/fastbeads/internal/config"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/syncbranch"
 )
 
@@ -33,6 +34,8 @@ Common namespaces:
   - github.*     GitHub integration settings
   - custom.*     Custom integration settings
   - status.*     Issue status configuration
+  - wip.*        Work-in-progress limits enforced when claiming
+  - webhooks.*   Webhook endpoints (manage with 'fbd webhooks')
 
 Custom Status States:
   You can define custom status states for multi-step pipelines using the
@@ -44,6 +47,19 @@ Custom Status States:
   This enables issues to use statuses like 'awaiting_review' in addition to
   the built-in statuses (open, in_progress, blocked, deferred, closed).
 
+WIP Limits:
+  Cap the issues in progress per assignee, label or rig. Claims that would
+  exceed a limit fail, and 'fbd ready' hides work you couldn't claim.
+
+  Example:
+    fbd config set wip.assignee 3          # Default for every assignee
+    fbd config set wip.assignee.alice 5    # Override for one assignee
+    fbd config set wip.label.frontend 4
+    fbd config set wip.rig 10              # Also wip.rig.<name>
+
+  Rigs come from agent identities: work claimed by gastown/crew/max
+  counts toward rig gastown.
+
 Examples:
   fbd config set jira.url "https://company.atlassian.net"
   fbd config set jira.project "PROJ"
@@ -115,6 +131,14 @@ var configSetCmd = &cobra.Command{
 
 		ctx := rootCtx
 
+		// Reject malformed WIP limits, which would make every claim fail
+		if strings.HasPrefix(key, storage.WIPConfigPrefix) {
+			if err := storage.ValidateWIPConfig(key, value); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Special handling for sync.branch to apply validation
 		if strings.TrimSpace(key) == syncbranch.ConfigKey {
 			if err := syncbranch.Set(ctx, store, value); err != nil {
@@ -556,6 +556,10 @@ func processStepToIssue(step *formula.Step, parentID string) *types.Issue {
 		SourceLocation: step.SourceLocation, // Source tracing
 	}
 
+	// Keep the step ID, on_complete and loop control on the issue so they
+	// survive pour (see 'fbd mol advance' and on_complete expansion)
+	issue.Metadata = stepMetadata(step)
+
 	// Populate labels from step
 	issue.Labels = append(issue.Labels, step.Labels...)
 
@@ -608,13 +612,9 @@ func collectSteps(steps []*formula.Step, parentID string,
 			Type:        types.DepParentChild,
 		})
 
-		// Create gate issue if step has a Gate (bd-7zka.2)
-		if step.Gate != nil {
-			gateIssue := createGateIssue(step, parentID)
+		// attachGate adds a gate issue as a sibling of the step that blocks it
+		attachGate := func(gateIssue *types.Issue, gateKey string) {
 			*issues = append(*issues, gateIssue)
-
-			// Add gate to mapping (use gate-{step.ID} as key)
-			gateKey := fmt.Sprintf("gate-%s", step.ID)
 			idMapping[gateKey] = gateIssue.ID
 			if issueMap != nil {
 				issueMap[gateIssue.ID] = gateIssue
@@ -643,6 +643,16 @@ func collectSteps(steps []*formula.Step, parentID string,
 			})
 		}
 
+		// Create gate issue if step has a Gate (bd-7zka.2)
+		if step.Gate != nil {
+			attachGate(createGateIssue(step, parentID), fmt.Sprintf("gate-%s", step.ID))
+		}
+
+		// Create condition gate for compose.gate rules, closed by 'fbd mol advance'
+		if condition := gateConditionLabel(step.Labels); condition != "" {
+			attachGate(createConditionGateIssue(step, parentID, condition), fmt.Sprintf("cond-%s", step.ID))
+		}
+
 		// Recursively collect children
 		if len(step.Children) > 0 {
 			collectSteps(step.Children, issue.ID, idMapping, issueMap, issues, deps, labelHandler)
@@ -661,6 +671,19 @@ func resolveAndCookFormula(formulaName string, searchPaths []string) (*TemplateS
 // If conditionVars is provided, steps with conditions that evaluate to false are excluded.
 // Pass nil for conditionVars to include all steps (condition filtering skipped).
 func resolveAndCookFormulaWithVars(formulaName string, searchPaths []string, conditionVars map[string]string) (*TemplateSubgraph, error) {
+	resolved, err := resolveFormulaWithVars(formulaName, searchPaths, conditionVars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cook to in-memory subgraph, including variable definitions for default handling
+	return cookFormulaToSubgraphWithVars(resolved, resolved.Formula, resolved.Vars)
+}
+
+// resolveFormulaWithVars loads a formula by name and applies inheritance,
+// control flow, advice, expansions and aspects. If conditionVars is non-nil,
+// steps whose conditions evaluate to false are excluded.
+func resolveFormulaWithVars(formulaName string, searchPaths []string, conditionVars map[string]string) (*formula.Formula, error) {
 	// Create parser with search paths
 	parser := formula.NewParser(searchPaths...)
 
@@ -741,8 +764,7 @@ func resolveAndCookFormulaWithVars(formulaName string, searchPaths []string, con
 		resolved.Steps = filteredSteps
 	}
 
-	// Cook to in-memory subgraph, including variable definitions for default handling
-	return cookFormulaToSubgraphWithVars(resolved, resolved.Formula, resolved.Vars)
+	return resolved, nil
 }
 
 // cookFormulaToSubgraphWithVars creates an in-memory subgraph with variable info attached
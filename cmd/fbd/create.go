@@ -384,6 +384,10 @@ var createCmd = &cobra.Command{
 		// When routing to a different repo, we bypass daemon mode and use direct storage
 		var targetStore storage.Storage
 		if repoPath != "." {
+			// The daemon only serves this repo; routing needs direct access
+			if err := ensureDirectMode("routing to another repository requires direct database access"); err != nil {
+				FatalError("%v", err)
+			}
 			targetBeadsDir := routing.ExpandPath(repoPath)
 			debug.Logf("DEBUG: Routing to target repo: %s\n", targetBeadsDir)
 
@@ -488,6 +492,74 @@ var createCmd = &cobra.Command{
 			externalRefPtr = &externalRef
 		}
 
+		// If daemon is running, use RPC
+		if daemonClient != nil {
+			createArgs := &rpc.CreateArgs{
+				ID:                 explicitID,
+				Parent:             parentID,
+				Title:              title,
+				Description:        description,
+				IssueType:          string(types.IssueType(issueType).Normalize()),
+				Priority:           priority,
+				Design:             design,
+				AcceptanceCriteria: acceptance,
+				Notes:              notes,
+				SpecID:             specID,
+				Assignee:           assignee,
+				ExternalRef:        externalRef,
+				EstimatedMinutes:   estimatedMinutes,
+				Labels:             labels,
+				Dependencies:       deps,
+				WaitsFor:           waitsFor,
+				WaitsForGate:       waitsForGate,
+				Ephemeral:          wisp,
+				CreatedBy:          getActorWithGit(),
+				Owner:              getOwner(),
+				MolType:            string(molType),
+				WispType:           string(wispType),
+				Rig:                agentRig,
+				EventCategory:      eventCategory,
+				EventActor:         eventActor,
+				EventTarget:        eventTarget,
+				EventPayload:       eventPayload,
+			}
+			if dueAt != nil {
+				createArgs.DueAt = dueAt.Format(time.RFC3339)
+			}
+			if deferUntil != nil {
+				createArgs.DeferUntil = deferUntil.Format(time.RFC3339)
+			}
+
+			resp, err := daemonClient.Create(createArgs)
+			if err != nil {
+				FatalError("%v", err)
+			}
+			var issue types.Issue
+			if err := json.Unmarshal(resp.Data, &issue); err != nil {
+				FatalError("parsing response: %v", err)
+			}
+
+			// Run create hook
+			if hookRunner != nil {
+				hookRunner.Run(hooks.EventCreate, &issue)
+			}
+
+			if jsonOutput {
+				outputJSON(&issue)
+			} else if silent {
+				fmt.Println(issue.ID)
+			} else {
+				fmt.Printf("%s Created issue: %s\n", ui.RenderPass("✓"), issue.ID)
+				fmt.Printf("  Title: %s\n", issue.Title)
+				fmt.Printf("  Priority: P%d\n", issue.Priority)
+				fmt.Printf("  Status: %s\n", issue.Status)
+			}
+
+			// Track as last touched issue
+			SetLastTouchedID(issue.ID)
+			return
+		}
+
 		// Direct mode
 		issue := &types.Issue{
 			ID:                 explicitID, // Set explicit ID if provided (empty string if not)
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/beads"
+	"github.com/steveyegge/fastbeads/internal/configfile"
+	"github.com/steveyegge/fastbeads/internal/debug"
+	"github.com/steveyegge/fastbeads/internal/lockfile"
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/storage/factory"
+)
+
+// DaemonStatus describes how the current command reaches the database:
+// through a running 'fbd serve' daemon, or by opening it directly.
+type DaemonStatus struct {
+	Mode               string `json:"mode"`
+	Connected          bool   `json:"connected"`
+	Degraded           bool   `json:"degraded"`
+	SocketPath         string `json:"socket_path,omitempty"`
+	AutoStartEnabled   bool   `json:"auto_start_enabled"`
+	AutoStartAttempted bool   `json:"auto_start_attempted"`
+	AutoStartSucceeded bool   `json:"auto_start_succeeded"`
+	FallbackReason     string `json:"fallback_reason,omitempty"`
+	Detail             string `json:"detail,omitempty"`
+	Health             string `json:"health,omitempty"`
+}
+
+// Daemon modes reported in DaemonStatus.Mode.
+const (
+	DaemonModeDaemon = "daemon"
+	DaemonModeDirect = "direct"
+)
+
+// Reasons a command fell back to direct mode.
+const (
+	FallbackNone              = "none"
+	FallbackFlagNoDaemon      = "flag_no_daemon"
+	FallbackConnectFailed     = "connect_failed"
+	FallbackHealthFailed      = "health_failed"
+	FallbackWorktreeSafety    = "worktree_safety"
+	FallbackSingleProcessOnly = "single_process_only"
+	FallbackAutoStartDisabled = "auto_start_disabled"
+	FallbackAutoStartFailed   = "auto_start_failed"
+	FallbackDaemonUnsupported = "daemon_unsupported"
+	FallbackWispOperation     = "wisp_operation"
+)
+
+var (
+	// daemonClient is set when the current command is routed through a running
+	// 'fbd serve' daemon. When non-nil, the global store is not opened.
+	daemonClient *rpc.Client
+
+	// daemonStatus records whether the command connected to the daemon and,
+	// if not, why it fell back to direct mode.
+	daemonStatus DaemonStatus
+
+	// noDaemon is set by --no-daemon to bypass a running daemon.
+	noDaemon bool
+)
+
+// daemonRoutedCommands lists the top-level commands whose Run has a daemon
+// code path. Every other command opens the database directly, even while
+// 'fbd serve' is running.
+var daemonRoutedCommands = map[string]bool{
+	"close":  true,
+	"create": true,
+	"info":   true,
+	"ready":  true,
+	"show":   true,
+	"update": true,
+	"watch":  true,
+}
+
+// connectToDaemon routes the command through a running 'fbd serve' daemon
+// when possible. It returns true if daemonClient is connected and the caller
+// should skip opening the database. On any failure it records the fallback
+// reason in daemonStatus and returns false, so the command runs in direct mode.
+func connectToDaemon(cmd *cobra.Command, args []string) bool {
+	daemonStatus = DaemonStatus{Mode: DaemonModeDirect, FallbackReason: FallbackNone}
+
+	// Only top-level commands are routed ("fbd show", not "fbd gate show")
+	if !cmd.HasParent() || cmd.Parent().HasParent() || !daemonRoutedCommands[cmd.Name()] {
+		return false
+	}
+	if noDaemon {
+		daemonStatus.FallbackReason = FallbackFlagNoDaemon
+		return false
+	}
+	if noDb || singleProcessOnlyBackend() {
+		daemonStatus.FallbackReason = FallbackSingleProcessOnly
+		return false
+	}
+	// Cross-repo IDs are served by another database; the daemon only owns ours.
+	for _, arg := range args {
+		if needsRouting(arg) {
+			daemonStatus.FallbackReason = FallbackDaemonUnsupported
+			daemonStatus.Detail = fmt.Sprintf("%s routes to another repository", arg)
+			return false
+		}
+	}
+
+	socketPath := getSocketPath()
+	if socketPath == "" {
+		daemonStatus.FallbackReason = FallbackConnectFailed
+		return false
+	}
+	daemonStatus.SocketPath = socketPath
+
+	rpc.ClientVersion = Version
+	client, err := rpc.TryConnect(socketPath)
+	if err != nil || client == nil {
+		// No daemon running is the common case, not an error
+		daemonStatus.FallbackReason = FallbackConnectFailed
+		if err != nil {
+			daemonStatus.Detail = err.Error()
+		}
+		return false
+	}
+
+	health, err := client.Health()
+	if err != nil || !health.Compatible {
+		_ = client.Close()
+		daemonStatus.FallbackReason = FallbackHealthFailed
+		if err != nil {
+			daemonStatus.Detail = err.Error()
+		} else {
+			daemonStatus.Detail = fmt.Sprintf("daemon version %s is not compatible with client %s (restart with 'fbd serve stop && fbd serve')", health.Version, Version)
+		}
+		debug.Logf("daemon health check failed: %s", daemonStatus.Detail)
+		return false
+	}
+
+	client.SetDatabasePath(daemonExpectedDBPath())
+	client.SetActor(actor)
+
+	daemonClient = client
+	daemonStatus = DaemonStatus{
+		Mode:           DaemonModeDaemon,
+		Connected:      true,
+		SocketPath:     socketPath,
+		FallbackReason: FallbackNone,
+		Health:         health.Status,
+	}
+	debug.Logf("routing %s through daemon at %s", cmd.Name(), socketPath)
+	return true
+}
+
+// closeDaemonClient disconnects from the daemon, if connected.
+func closeDaemonClient() {
+	if daemonClient != nil {
+		_ = daemonClient.Close()
+		daemonClient = nil
+	}
+}
+
+// daemonBeadsDir returns the .beads directory the daemon for this workspace
+// lives in, or "" if none can be found.
+func daemonBeadsDir() string {
+	if dbPath != "" {
+		return filepath.Dir(dbPath)
+	}
+	return beads.FindBeadsDir()
+}
+
+// daemonExpectedDBPath returns the storage path the daemon reports for this
+// workspace, used to detect a socket that belongs to a different database.
+// Mirrors how PersistentPreRun opens the store: Dolt lives in .beads/dolt.
+func daemonExpectedDBPath() string {
+	beadsDir := daemonBeadsDir()
+	if factory.GetBackendFromConfig(beadsDir) == configfile.BackendDolt {
+		return filepath.Join(beadsDir, "dolt")
+	}
+	return dbPath
+}
+
+// getSocketPath returns the daemon socket path for the current workspace.
+func getSocketPath() string {
+	beadsDir := daemonBeadsDir()
+	if beadsDir == "" {
+		return ""
+	}
+	return rpc.ShortSocketPath(filepath.Dir(beadsDir))
+}
+
+// getPIDFilePath returns the daemon PID file path for the current workspace.
+func getPIDFilePath() (string, error) {
+	beadsDir := daemonBeadsDir()
+	if beadsDir == "" {
+		return "", fmt.Errorf("no .beads directory found")
+	}
+	return filepath.Join(beadsDir, "daemon.pid"), nil
+}
+
+// isDaemonRunning reports whether a daemon holds the lock in the PID file's
+// .beads directory, and its PID if known.
+func isDaemonRunning(pidFile string) (bool, int) {
+	return lockfile.TryDaemonLock(filepath.Dir(pidFile))
+}
+
+// stopDaemonQuiet asks the daemon on socketPath to shut down, ignoring errors.
+func stopDaemonQuiet(socketPath string) {
+	client, err := rpc.TryConnect(socketPath)
+	if err != nil || client == nil {
+		return
+	}
+	defer func() { _ = client.Close() }()
+	_ = client.Shutdown()
+}
@@ -1,83 +1,22 @@
 package main
 
-// daemon_deprecated.go provides stub variables for daemon-related code
-// that hasn't been fully removed yet from command files.
+// daemon_deprecated.go provides stubs for the parts of the old background
+// daemon that 'fbd serve' (see daemon.go, serve.go) did not bring back:
+// sync-branch pulls and single-process backend detection.
 //
-// The daemon subsystem has been removed. These variables exist only to
-// allow gradual cleanup of the 66+ files that still reference them.
-// All daemon branches are dead code (daemonClient is always nil,
-// daemonStatus is always zero-valued, noDaemon is always false).
-//
-// TODO: Remove this file after all `if daemonClient != nil` branches
-// are cleaned up from command files.
+// TODO: Remove these stubs once their remaining callers are cleaned up.
 
 import (
 	"context"
 	"fmt"
 	"log/slog"
 
-	"github.com/steveyegge/fastbeads/internal/rpc"
 	"github.com/steveyegge/fastbeads/internal/storage"
 )
 
-// DaemonStatus is a deprecated stub. The daemon has been removed.
-type DaemonStatus struct {
-	Mode               string `json:"mode"`
-	Connected          bool   `json:"connected"`
-	Degraded           bool   `json:"degraded"`
-	SocketPath         string `json:"socket_path,omitempty"`
-	AutoStartEnabled   bool   `json:"auto_start_enabled"`
-	AutoStartAttempted bool   `json:"auto_start_attempted"`
-	AutoStartSucceeded bool   `json:"auto_start_succeeded"`
-	FallbackReason     string `json:"fallback_reason,omitempty"`
-	Detail             string `json:"detail,omitempty"`
-	Health             string `json:"health,omitempty"`
-}
-
-// Deprecated fallback reason constants. The daemon has been removed.
-const (
-	FallbackNone              = "none"
-	FallbackFlagNoDaemon      = "flag_no_daemon"
-	FallbackConnectFailed     = "connect_failed"
-	FallbackHealthFailed      = "health_failed"
-	FallbackWorktreeSafety    = "worktree_safety"
-	FallbackSingleProcessOnly = "single_process_only"
-	FallbackAutoStartDisabled = "auto_start_disabled"
-	FallbackAutoStartFailed   = "auto_start_failed"
-	FallbackDaemonUnsupported = "daemon_unsupported"
-	FallbackWispOperation     = "wisp_operation"
-)
-
-var (
-	// daemonClient is always nil. The daemon has been removed.
-	daemonClient *rpc.Client
-
-	// daemonStatus is always zero-valued. The daemon has been removed.
-	daemonStatus DaemonStatus
-
-	// noDaemon is always false. The --no-daemon flag has been removed.
-	noDaemon bool
-)
-
-// Deprecated accessor stubs for context.go compatibility.
-func getDaemonClient() *rpc.Client   { return nil }
-func setDaemonClient(_ *rpc.Client)  {}
-func getDaemonStatus() DaemonStatus  { return DaemonStatus{} }
-func setDaemonStatus(_ DaemonStatus) {}
-func isNoDaemon() bool               { return false }
-func setNoDaemon(_ bool)             {}
-
-// Deprecated daemon lifecycle stubs.
-func isDaemonRunning(_ string) (bool, int) { return false, 0 }
-func stopDaemonQuiet(_ string)             {}
-func getPIDFilePath() (string, error)      { return "", nil }
-func getSocketPath() string                { return "" }
-func emitVerboseWarning()                  {}
-func shouldAutoStartDaemon() bool          { return false }
-func singleProcessOnlyBackend() bool       { return false }
-func shouldDisableDaemonForWorktree() bool { return false }
-func warnWorktreeDaemon(_ string)          {}
-func newSilentLogger() *slog.Logger        { return slog.New(slog.DiscardHandler) }
+// Deprecated daemon sync stubs.
+func singleProcessOnlyBackend() bool { return false }
+func newSilentLogger() *slog.Logger  { return slog.New(slog.DiscardHandler) }
 func syncBranchPull(_ context.Context, _ storage.Storage, _ *slog.Logger) (bool, error) {
 	return false, nil
 }
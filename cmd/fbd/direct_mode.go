@@ -17,15 +17,25 @@ import (
 )
 
 // ensureDirectMode makes sure the CLI is operating in direct-storage mode.
-func ensureDirectMode(_ string) error {
+// Commands routed through 'fbd serve' call this before using a feature the
+// daemon does not support; reason is recorded for 'fbd info'.
+func ensureDirectMode(reason string) error {
+	if daemonClient != nil {
+		debug.Logf("leaving daemon mode: %s", reason)
+		return fallbackToDirectMode(reason)
+	}
 	return ensureStoreActive()
 }
 
-// fallbackToDirectMode ensures a local store is ready.
-// With the daemon removed, this simply ensures the store is active.
-func fallbackToDirectMode(_ string) error {
-	daemonClient = nil
-	daemonStatus = DaemonStatus{}
+// fallbackToDirectMode disconnects from the daemon (if connected) and opens
+// the local store.
+func fallbackToDirectMode(reason string) error {
+	closeDaemonClient()
+	daemonStatus = DaemonStatus{
+		Mode:           DaemonModeDirect,
+		FallbackReason: FallbackDaemonUnsupported,
+		Detail:         reason,
+	}
 	return ensureStoreActive()
 }
 
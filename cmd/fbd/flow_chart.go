@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+// Terminal charts draw one bar per day. Each status gets its own glyph so
+// the stack stays readable without color.
+var flowGlyphs = []string{"█", "▓", "▒", "░", "▚", "▞", "■", "▪"}
+
+// SVG charts are drawn at a fixed size and scale to fit.
+const (
+	svgWidth  = 800
+	svgHeight = 400
+	svgMargin = 48
+)
+
+// flowColor picks a status's chart color from the UI palette, falling back
+// to accent colors for statuses the palette leaves uncolored.
+func flowColor(status types.Status, index int) lipgloss.AdaptiveColor {
+	switch status {
+	case types.StatusClosed:
+		return ui.ColorPass
+	case types.StatusInProgress:
+		return ui.ColorStatusInProgress
+	case types.StatusBlocked:
+		return ui.ColorStatusBlocked
+	case types.StatusPinned:
+		return ui.ColorStatusPinned
+	case types.StatusHooked:
+		return ui.ColorStatusHooked
+	case types.StatusDeferred:
+		return ui.ColorMuted
+	case types.StatusOpen:
+		return ui.ColorAccent
+	}
+	fallback := []lipgloss.AdaptiveColor{ui.ColorWarn, ui.ColorFail, ui.ColorStatusPinned}
+	return fallback[index%len(fallback)]
+}
+
+// scaleTo maps v in [0, max] onto [0, width] cells.
+func scaleTo(v float64, max, width int) int {
+	if max == 0 {
+		return 0
+	}
+	return int(math.Round(v * float64(width) / float64(max)))
+}
+
+// renderFlowChart prints a stacked bar per day, bottom status on the left.
+func renderFlowChart(w io.Writer, flow *metrics.Flow, width int) {
+	max := 0
+	for _, d := range flow.Days {
+		total := 0
+		for _, n := range d.Counts {
+			total += n
+		}
+		if total > max {
+			max = total
+		}
+	}
+
+	var legend []string
+	for i, status := range flow.Statuses {
+		style := lipgloss.NewStyle().Foreground(flowColor(status, i))
+		legend = append(legend, style.Render(flowGlyphs[i%len(flowGlyphs)])+" "+string(status))
+	}
+	fmt.Fprintf(w, "  %s\n\n", strings.Join(legend, "  "))
+
+	for _, d := range flow.Days {
+		var bar strings.Builder
+		cum, drawn := 0, 0
+		for i, status := range flow.Statuses {
+			cum += d.Counts[status]
+			cells := scaleTo(float64(cum), max, width) - drawn
+			if cells <= 0 {
+				continue
+			}
+			drawn += cells
+			style := lipgloss.NewStyle().Foreground(flowColor(status, i))
+			bar.WriteString(style.Render(strings.Repeat(flowGlyphs[i%len(flowGlyphs)], cells)))
+		}
+		fmt.Fprintf(w, "  %s  %s%s %d\n", d.Date.Format("01-02"), bar.String(), strings.Repeat(" ", width-drawn), cum)
+	}
+}
+
+// renderBurndownChart prints remaining work per day as a bar, with the
+// rest of the scope muted and the ideal line marked by a pipe.
+func renderBurndownChart(w io.Writer, bd *metrics.Burndown, width int) {
+	max := 0
+	for _, d := range bd.Days {
+		if d.Scope > max {
+			max = d.Scope
+		}
+	}
+	remainingStyle := lipgloss.NewStyle().Foreground(ui.ColorAccent)
+	legend := remainingStyle.Render("█") + " remaining  " + ui.RenderMuted("░") + " done"
+	if bd.DueAt != nil {
+		legend += "  " + ui.RenderWarn("|") + " ideal"
+	}
+	fmt.Fprintf(w, "  %s\n\n", legend)
+
+	for _, d := range bd.Days {
+		remaining := scaleTo(float64(d.Remaining), max, width)
+		scope := scaleTo(float64(d.Scope), max, width)
+		cells := make([]string, width+1)
+		for i := range cells {
+			switch {
+			case i < remaining:
+				cells[i] = remainingStyle.Render("█")
+			case i < scope:
+				cells[i] = ui.RenderMuted("░")
+			default:
+				cells[i] = " "
+			}
+		}
+		if d.Ideal != nil {
+			cells[scaleTo(*d.Ideal, max, width)] = ui.RenderWarn("|")
+		}
+		fmt.Fprintf(w, "  %s  %s %d/%d\n", d.Date.Format("01-02"), strings.Join(cells, ""), d.Remaining, d.Scope)
+	}
+}
+
+// svgPlot maps day indexes and counts onto the plot area of an SVG chart.
+type svgPlot struct {
+	days, max int
+}
+
+func (p svgPlot) x(day int) float64 {
+	if p.days < 2 {
+		return svgMargin
+	}
+	return svgMargin + float64(day)*float64(svgWidth-2*svgMargin)/float64(p.days-1)
+}
+
+func (p svgPlot) y(v float64) float64 {
+	if p.max == 0 {
+		return svgHeight - svgMargin
+	}
+	return svgHeight - svgMargin - v*float64(svgHeight-2*svgMargin)/float64(p.max)
+}
+
+// writeSVGFrame writes the header, axes, title and axis labels of a chart.
+// The caller writes the series and closes the svg element.
+func writeSVGFrame(w io.Writer, title string, p svgPlot, firstDay, lastDay string) {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(w, `<text x="%d" y="24" font-size="16">%s</text>`+"\n", svgMargin, html.EscapeString(title))
+	fmt.Fprintf(w, `<path d="M%d %d V%d H%d" fill="none" stroke="#828c99"/>`+"\n", svgMargin, svgMargin, svgHeight-svgMargin, svgWidth-svgMargin)
+	fmt.Fprintf(w, `<text x="%d" y="%.1f" text-anchor="end">%d</text>`+"\n", svgMargin-6, p.y(float64(p.max))+4, p.max)
+	fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">0</text>`+"\n", svgMargin-6, svgHeight-svgMargin+4)
+	fmt.Fprintf(w, `<text x="%d" y="%d">%s</text>`+"\n", svgMargin, svgHeight-svgMargin+18, firstDay)
+	fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", svgWidth-svgMargin, svgHeight-svgMargin+18, lastDay)
+}
+
+// writeSVGLegend writes one legend entry per series along the top right.
+func writeSVGLegend(w io.Writer, names, colors []string) {
+	x := svgWidth - svgMargin - 110*len(names)
+	for i, name := range names {
+		fmt.Fprintf(w, `<rect x="%d" y="14" width="10" height="10" fill="%s"/><text x="%d" y="24">%s</text>`+"\n",
+			x+110*i, colors[i], x+110*i+14, html.EscapeString(name))
+	}
+}
+
+// svgPoints formats polyline points.
+func svgPoints(p svgPlot, values []float64) string {
+	points := make([]string, len(values))
+	for i, v := range values {
+		points[i] = fmt.Sprintf("%.1f,%.1f", p.x(i), p.y(v))
+	}
+	return strings.Join(points, " ")
+}
+
+// renderFlowSVG writes a cumulative flow diagram as stacked areas.
+func renderFlowSVG(w io.Writer, title string, flow *metrics.Flow) {
+	p := svgPlot{days: len(flow.Days)}
+	for _, d := range flow.Days {
+		total := 0
+		for _, n := range d.Counts {
+			total += n
+		}
+		if total > p.max {
+			p.max = total
+		}
+	}
+	below := make([]float64, len(flow.Days))
+	first, last := "", ""
+	if len(flow.Days) > 0 {
+		first, last = flow.Days[0].Date.Format("2006-01-02"), flow.Days[len(flow.Days)-1].Date.Format("2006-01-02")
+	}
+	writeSVGFrame(w, title, p, first, last)
+
+	names := make([]string, len(flow.Statuses))
+	colors := make([]string, len(flow.Statuses))
+	for s, status := range flow.Statuses {
+		names[s], colors[s] = string(status), flowColor(status, s).Light
+		top := make([]float64, len(flow.Days))
+		for i, d := range flow.Days {
+			top[i] = below[i] + float64(d.Counts[status])
+		}
+		// Trace the top edge forward and the bottom edge back
+		points := svgPoints(p, top)
+		for i := len(below) - 1; i >= 0; i-- {
+			points += fmt.Sprintf(" %.1f,%.1f", p.x(i), p.y(below[i]))
+		}
+		fmt.Fprintf(w, `<polygon points="%s" fill="%s" fill-opacity="0.85"/>`+"\n", points, colors[s])
+		below = top
+	}
+	writeSVGLegend(w, names, colors)
+	fmt.Fprintln(w, "</svg>")
+}
+
+// renderBurndownSVG writes a burndown chart with scope, remaining and
+// ideal lines.
+func renderBurndownSVG(w io.Writer, title string, bd *metrics.Burndown) {
+	p := svgPlot{days: len(bd.Days)}
+	scope := make([]float64, len(bd.Days))
+	remaining := make([]float64, len(bd.Days))
+	var ideal []string
+	for i, d := range bd.Days {
+		scope[i], remaining[i] = float64(d.Scope), float64(d.Remaining)
+		if d.Scope > p.max {
+			p.max = d.Scope
+		}
+	}
+	for i, d := range bd.Days {
+		if d.Ideal != nil {
+			ideal = append(ideal, fmt.Sprintf("%.1f,%.1f", p.x(i), p.y(*d.Ideal)))
+		}
+	}
+	first, last := "", ""
+	if len(bd.Days) > 0 {
+		first, last = bd.Days[0].Date.Format("2006-01-02"), bd.Days[len(bd.Days)-1].Date.Format("2006-01-02")
+	}
+	writeSVGFrame(w, title, p, first, last)
+
+	names := []string{"scope", "remaining"}
+	colors := []string{ui.ColorMuted.Light, ui.ColorAccent.Light}
+	fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", svgPoints(p, scope), colors[0])
+	fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", svgPoints(p, remaining), colors[1])
+	if len(ideal) > 0 {
+		names, colors = append(names, "ideal"), append(colors, ui.ColorWarn.Light)
+		fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-dasharray="6 4"/>`+"\n", strings.Join(ideal, " "), colors[2])
+	}
+	writeSVGLegend(w, names, colors)
+	fmt.Fprintln(w, "</svg>")
+}
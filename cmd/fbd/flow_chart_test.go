@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// checkSVG fails the test unless out is well-formed XML.
+func checkSVG(t *testing.T, out string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestRenderFlowCharts(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	flow := &metrics.Flow{
+		Statuses: []types.Status{types.StatusClosed, types.StatusOpen},
+		Days: []*metrics.FlowDay{
+			{Date: day, Counts: map[types.Status]int{types.StatusOpen: 2}},
+			{Date: day.AddDate(0, 0, 1), Counts: map[types.Status]int{types.StatusClosed: 1, types.StatusOpen: 3}},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderFlowChart(&buf, flow, 20)
+	for _, want := range []string{"█ closed", "▓ open", "03-02  ▓▓▓▓▓▓▓▓▓▓           2\n", "03-03  █████▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓ 4\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("flow chart missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	renderFlowSVG(&buf, "Flow <all>", flow)
+	checkSVG(t, buf.String())
+	if n := strings.Count(buf.String(), "<polygon"); n != 2 {
+		t.Errorf("expected one area per status, got %d", n)
+	}
+
+	ideal := 0.0
+	bd := &metrics.Burndown{
+		DueAt: &day,
+		Days: []*metrics.BurndownDay{
+			{Date: day, Scope: 4, Remaining: 4},
+			{Date: day.AddDate(0, 0, 1), Scope: 4, Done: 2, Remaining: 2, Ideal: &ideal},
+		},
+	}
+	buf.Reset()
+	renderBurndownChart(&buf, bd, 20)
+	if want := "03-03  |█████████░░░░░░░░░░  2/4\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("burndown chart missing %q:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	renderBurndownSVG(&buf, "Burndown", bd)
+	checkSVG(t, buf.String())
+	if n := strings.Count(buf.String(), "<polyline"); n != 3 {
+		t.Errorf("expected scope, remaining and ideal lines, got %d", n)
+	}
+}
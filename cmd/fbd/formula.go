@@ -33,10 +33,16 @@ Search paths (in order):
   1. .beads/formulas/ (project)
   2. ~/.beads/formulas/ (user)
   3. $GT_ROOT/.beads/formulas/ (orchestrator, if GT_ROOT set)
+Each path's vendor/<package>/ directories are searched right after it.
 
 Commands:
-  list   List available formulas from all search paths
-  show   Show formula details, steps, and composition rules`,
+  list      List available formulas from all search paths
+  show      Show formula details, steps, and composition rules
+  test      Test a formula against golden snapshots and assertions
+  graph     Render a formula's step graph (DOT, Mermaid or ASCII)
+  install   Install a formula package into .beads/formulas/vendor/
+  outdated  Show installed packages with newer versions
+  update    Update installed packages`,
 }
 
 // formulaListCmd lists all available formulas.
@@ -49,6 +55,7 @@ Search paths (in order of priority):
   1. .beads/formulas/ (project - highest priority)
   2. ~/.beads/formulas/ (user)
   3. $GT_ROOT/.beads/formulas/ (orchestrator, if GT_ROOT set)
+Installed packages (vendor/<package>/) follow the path they are vendored in.
 
 Formulas in earlier paths shadow those with the same name in later paths.
 
@@ -94,8 +101,8 @@ type FormulaListEntry struct {
 func runFormulaList(cmd *cobra.Command, args []string) {
 	typeFilter, _ := cmd.Flags().GetString("type")
 
-	// Get all search paths
-	searchPaths := getFormulaSearchPaths()
+	// Get all search paths, including vendored packages
+	searchPaths := formula.WithVendorPaths(getFormulaSearchPaths())
 
 	// Track seen formulas (first occurrence wins)
 	seen := make(map[string]bool)
@@ -190,7 +197,7 @@ func runFormulaShow(cmd *cobra.Command, args []string) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintf(os.Stderr, "\nSearch paths:\n")
-		for _, p := range getFormulaSearchPaths() {
+		for _, p := range formula.WithVendorPaths(getFormulaSearchPaths()) {
 			fmt.Fprintf(os.Stderr, "  %s\n", p)
 		}
 		os.Exit(1)
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+var formulaGraphCmd = &cobra.Command{
+	Use:   "graph <formula>",
+	Short: "Render a formula's step graph (DOT, Mermaid or ASCII)",
+	Long: `Render the step graph of a formula as it would be cooked.
+
+The formula goes through the same pipeline as 'fbd cook' (extends, control
+flow, advice, inline expansions, compose expansions and aspects), so the graph
+shows the steps that would actually be created. Each step is annotated with
+the formula and location it came from (e.g. base-release@steps[2] or
+aspect-lint@advice[0].before).
+
+Formats:
+  dot        Graphviz DOT (default): fbd formula graph mol-x | dot -Tsvg > x.svg
+  mermaid    Mermaid flowchart, for Markdown and GitHub
+  svg-ascii  Boxes in the terminal, grouped into layers by dependency depth
+
+Edges point from a step to the steps that wait on it. Gates are drawn as
+hexagons; waits-for and nested (child) edges are dashed.
+
+Examples:
+  fbd formula graph mol-release
+  fbd formula graph mol-release --format mermaid
+  fbd formula graph mol-release --format svg-ascii
+  fbd formula graph mol-release --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runFormulaGraph,
+}
+
+// FormulaGraph is the cooked step graph of a formula.
+type FormulaGraph struct {
+	Formula string              `json:"formula"`
+	Nodes   []*FormulaGraphNode `json:"nodes"`
+	Edges   []*FormulaGraphEdge `json:"edges"`
+}
+
+// FormulaGraphNode is one cooked step.
+type FormulaGraphNode struct {
+	ID     string `json:"id"` // Step ID relative to the formula, e.g. "build" or "retry.iter1.attempt"
+	Title  string `json:"title"`
+	Type   string `json:"type"`
+	Parent string `json:"parent,omitempty"` // Enclosing step for nested steps
+	Source string `json:"source,omitempty"` // formula@location the step came from
+	Gate   string `json:"gate,omitempty"`   // Await type for gate steps
+}
+
+// FormulaGraphEdge runs from a step to a step that depends on it.
+type FormulaGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+func runFormulaGraph(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "dot" && format != "mermaid" && format != "svg-ascii" {
+		FatalErrorRespectJSON("invalid format %q (use dot, mermaid or svg-ascii)", format)
+	}
+
+	graph, err := buildFormulaGraph(args[0])
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+
+	if jsonOutput {
+		outputJSON(graph)
+		return
+	}
+	switch format {
+	case "dot":
+		renderFormulaGraphDOT(os.Stdout, graph)
+	case "mermaid":
+		renderFormulaGraphMermaid(os.Stdout, graph)
+	default:
+		renderFormulaGraphASCII(os.Stdout, graph)
+	}
+}
+
+// buildFormulaGraph cooks a formula in memory and returns its step graph.
+func buildFormulaGraph(name string) (*FormulaGraph, error) {
+	resolved, err := loadAndResolveFormula(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	subgraph, err := cookFormulaToSubgraph(resolved, resolved.Formula)
+	if err != nil {
+		return nil, err
+	}
+
+	rootID := subgraph.Root.ID
+	stepID := func(id string) string {
+		return strings.TrimPrefix(id, rootID+".")
+	}
+
+	graph := &FormulaGraph{Formula: resolved.Formula}
+	nodes := make(map[string]*FormulaGraphNode)
+	for _, issue := range subgraph.Issues {
+		if issue.ID == rootID {
+			continue
+		}
+		node := &FormulaGraphNode{
+			ID:    stepID(issue.ID),
+			Title: issue.Title,
+			Type:  string(issue.IssueType),
+		}
+		if issue.SourceFormula != "" || issue.SourceLocation != "" {
+			node.Source = issue.SourceFormula + "@" + issue.SourceLocation
+		}
+		if issue.IssueType == "gate" {
+			node.Gate = issue.AwaitType
+		}
+		nodes[issue.ID] = node
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, dep := range subgraph.Dependencies {
+		from, to := nodes[dep.DependsOnID], nodes[dep.IssueID]
+		if dep.Type == types.DepParentChild {
+			// Everything is a child of the root; only nesting below it is shown
+			if to != nil && from != nil {
+				to.Parent = from.ID
+				graph.Edges = append(graph.Edges, &FormulaGraphEdge{From: from.ID, To: to.ID, Type: string(dep.Type)})
+			}
+			continue
+		}
+		if from == nil || to == nil {
+			continue
+		}
+		graph.Edges = append(graph.Edges, &FormulaGraphEdge{From: from.ID, To: to.ID, Type: string(dep.Type)})
+	}
+
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Type < b.Type
+	})
+	return graph, nil
+}
+
+// formulaGraphLabel returns a node's label lines: title, step ID, source.
+func formulaGraphLabel(n *FormulaGraphNode) []string {
+	lines := []string{n.Title, n.ID}
+	if n.Gate != "" {
+		lines[1] = fmt.Sprintf("%s (gate: %s)", n.ID, n.Gate)
+	}
+	if n.Source != "" {
+		lines = append(lines, "from "+n.Source)
+	}
+	return lines
+}
+
+func renderFormulaGraphDOT(w io.Writer, g *FormulaGraph) {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+
+	fmt.Fprintf(w, "digraph %s {\n", quote(g.Formula))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+	for _, n := range g.Nodes {
+		label := strings.Join(formulaGraphLabel(n), `\n`)
+		attrs := []string{"label=" + strings.ReplaceAll(quote(label), `\\n`, `\n`)}
+		if n.Gate != "" {
+			attrs = append(attrs, "shape=hexagon")
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", quote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		switch e.Type {
+		case string(types.DepBlocks):
+		case string(types.DepParentChild):
+			attrs = append(attrs, "style=dashed", "arrowhead=none", `label="child"`)
+		default:
+			attrs = append(attrs, "style=dashed", "label="+quote(e.Type))
+		}
+		suffix := ""
+		if len(attrs) > 0 {
+			suffix = " [" + strings.Join(attrs, ", ") + "]"
+		}
+		fmt.Fprintf(w, "  %s -> %s%s;\n", quote(e.From), quote(e.To), suffix)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func renderFormulaGraphMermaid(w io.Writer, g *FormulaGraph) {
+	// Mermaid node IDs must be plain identifiers
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("s%d", i)
+	}
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+
+	fmt.Fprintln(w, "flowchart LR")
+	for _, n := range g.Nodes {
+		lines := formulaGraphLabel(n)
+		for i := range lines {
+			lines[i] = escape(lines[i])
+		}
+		label := `"` + strings.Join(lines, "<br/>") + `"`
+		if n.Gate != "" {
+			fmt.Fprintf(w, "    %s{{%s}}\n", ids[n.ID], label)
+		} else {
+			fmt.Fprintf(w, "    %s[%s]\n", ids[n.ID], label)
+		}
+	}
+	for _, e := range g.Edges {
+		switch e.Type {
+		case string(types.DepBlocks):
+			fmt.Fprintf(w, "    %s --> %s\n", ids[e.From], ids[e.To])
+		case string(types.DepParentChild):
+			fmt.Fprintf(w, "    %s -. child .- %s\n", ids[e.From], ids[e.To])
+		default:
+			fmt.Fprintf(w, "    %s -. %s .-> %s\n", ids[e.From], e.Type, ids[e.To])
+		}
+	}
+}
+
+// renderFormulaGraphASCII draws steps as boxes in dependency layers, using
+// the same layering as 'fbd graph'.
+func renderFormulaGraphASCII(w io.Writer, g *FormulaGraph) {
+	if len(g.Nodes) == 0 {
+		fmt.Fprintln(w, "Empty graph")
+		return
+	}
+
+	// Lay out with the issue graph's layering: blocking edges only
+	subgraph := &TemplateSubgraph{Root: &types.Issue{}, IssueMap: make(map[string]*types.Issue)}
+	nodes := make(map[string]*FormulaGraphNode, len(g.Nodes))
+	for _, n := range g.Nodes {
+		issue := &types.Issue{ID: n.ID, Title: n.Title}
+		subgraph.Issues = append(subgraph.Issues, issue)
+		subgraph.IssueMap[n.ID] = issue
+		nodes[n.ID] = n
+	}
+	waitsOn := make(map[string][]string)
+	for _, e := range g.Edges {
+		if e.Type == string(types.DepParentChild) {
+			continue
+		}
+		waitsOn[e.To] = append(waitsOn[e.To], e.From)
+		if e.Type == string(types.DepBlocks) {
+			subgraph.Dependencies = append(subgraph.Dependencies, &types.Dependency{IssueID: e.To, DependsOnID: e.From, Type: types.DepBlocks})
+		}
+	}
+	layout := computeLayout(subgraph)
+
+	width := 0
+	for _, n := range g.Nodes {
+		for _, line := range formulaGraphLabel(n) {
+			width = max(width, len([]rune(truncateTitle(line, 50))))
+		}
+	}
+	width += 2
+
+	fmt.Fprintf(w, "\n%s Step graph for %s:\n\n", ui.RenderAccent("📊"), g.Formula)
+	for layerIdx, layer := range layout.Layers {
+		fmt.Fprintf(w, "  Layer %d\n", layerIdx)
+		for _, id := range layer {
+			n := nodes[id]
+			fmt.Fprintf(w, "  ┌%s┐\n", strings.Repeat("─", width))
+			for i, line := range formulaGraphLabel(n) {
+				line = padRight(truncateTitle(line, 50), width-2)
+				if i > 0 {
+					line = ui.RenderMuted(line)
+				}
+				fmt.Fprintf(w, "  │ %s │\n", line)
+			}
+			after := append([]string(nil), waitsOn[id]...)
+			sort.Strings(after)
+			if len(after) > 0 {
+				fmt.Fprintf(w, "  │ %s │\n", padRight(truncateTitle("after: "+strings.Join(after, ", "), width-2), width-2))
+			}
+			if n.Parent != "" {
+				fmt.Fprintf(w, "  │ %s │\n", padRight(truncateTitle("in: "+n.Parent, width-2), width-2))
+			}
+			fmt.Fprintf(w, "  └%s┘\n", strings.Repeat("─", width))
+		}
+		if layerIdx < len(layout.Layers)-1 {
+			fmt.Fprintln(w, "      │")
+			fmt.Fprintln(w, "      ▼")
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "  Total: %d steps across %d layers\n\n", len(g.Nodes), len(layout.Layers))
+}
+
+func init() {
+	formulaGraphCmd.Flags().String("format", "dot", "Output format: dot, mermaid or svg-ascii")
+
+	formulaCmd.AddCommand(formulaGraphCmd)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/formula"
+)
+
+const graphWorkflow = `
+formula = "mol-deploy"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "release"
+title = "Release"
+type = "epic"
+needs = ["build"]
+
+[[steps.children]]
+id = "tag"
+title = "Tag"
+
+[[steps]]
+id = "ship"
+title = "Ship"
+needs = ["release"]
+[steps.gate]
+type = "human"
+
+[compose]
+aspects = ["aspect-lint"]
+`
+
+func TestBuildFormulaGraph(t *testing.T) {
+	dir := t.TempDir()
+	formulaDir := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"mol-deploy": graphWorkflow, "aspect-lint": harnessAspect} {
+		if err := os.WriteFile(filepath.Join(formulaDir, name+formula.FormulaExtTOML), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	graph, err := buildFormulaGraph("mol-deploy")
+	if err != nil {
+		t.Fatalf("buildFormulaGraph: %v", err)
+	}
+	nodes := make(map[string]*FormulaGraphNode)
+	for _, n := range graph.Nodes {
+		nodes[n.ID] = n
+	}
+	if _, ok := nodes["mol-deploy"]; ok {
+		t.Error("graph includes the root epic")
+	}
+	if n := nodes["lint-build"]; n == nil || !strings.HasPrefix(n.Source, "aspect-lint@advice[0]") {
+		t.Errorf("advice step = %+v, want source from aspect-lint", n)
+	}
+	if n := nodes["build"]; n == nil || n.Source != "mol-deploy@steps[0]" {
+		t.Errorf("build = %+v", n)
+	}
+	if n := nodes["release.tag"]; n == nil || n.Parent != "release" {
+		t.Errorf("nested step = %+v, want parent release", n)
+	}
+	var gate *FormulaGraphNode
+	for _, n := range graph.Nodes {
+		if n.Gate == "human" {
+			gate = n
+		}
+	}
+	if gate == nil {
+		t.Fatalf("no human gate in %+v", graph.Nodes)
+	}
+
+	edges := make(map[string]bool)
+	for _, e := range graph.Edges {
+		edges[e.From+" "+e.To+" "+e.Type] = true
+	}
+	for _, want := range []string{"lint-build build blocks", "build release blocks", gate.ID + " ship blocks", "release release.tag parent-child"} {
+		if !edges[want] {
+			t.Errorf("missing edge %q in %v", want, edges)
+		}
+	}
+
+	var dot, mermaid, ascii bytes.Buffer
+	renderFormulaGraphDOT(&dot, graph)
+	if !strings.Contains(dot.String(), `"lint-build" -> "build";`) || !strings.Contains(dot.String(), "shape=hexagon") {
+		t.Errorf("DOT output:\n%s", dot.String())
+	}
+	renderFormulaGraphMermaid(&mermaid, graph)
+	if !strings.HasPrefix(mermaid.String(), "flowchart LR\n") || !strings.Contains(mermaid.String(), "from aspect-lint@advice[0]") {
+		t.Errorf("Mermaid output:\n%s", mermaid.String())
+	}
+	renderFormulaGraphASCII(&ascii, graph)
+	if !strings.Contains(ascii.String(), "after: lint-build") {
+		t.Errorf("ASCII output:\n%s", ascii.String())
+	}
+}
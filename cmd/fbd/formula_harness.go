@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/formula"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/storage/memory"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+var formulaTestCmd = &cobra.Command{
+	Use:   "test <formula>",
+	Short: "Test a formula against golden snapshots and assertions",
+	Long: `Test a formula by cooking it into an in-memory database.
+
+Each test case cooks the formula with its vars (like 'fbd cook --persist
+--var'), snapshots the created issues, labels and dependencies as YAML, and
+compares the snapshot to the golden file next to the formula:
+
+  <formula-dir>/<formula>.<case>.golden.yaml
+
+Issues are keyed by proto ID (e.g. mol-release.ship), so snapshots are
+stable across runs. Use --update to write golden files after reviewing a
+change.
+
+Test cases and assertions live in the formula file:
+
+  [[tests]]
+  name = "with-docs"
+  vars = { component = "auth", docs = "true" }
+
+  [[tests.assert]]
+  issues = 6                      # Issues created, including the root
+
+  [[tests.assert]]
+  step = "ship"
+  depends_on = ["review", "docs"] # Also: not_depends_on, labels, absent
+
+A formula without tests runs a single "default" case with default vars.
+
+Examples:
+  fbd formula test mol-release
+  fbd formula test mol-release --update        # Rewrite golden files
+  fbd formula test mol-release --run with-docs # Run one case
+  fbd formula test mol-release --var env=prod  # Override vars in every case`,
+	Args: cobra.ExactArgs(1),
+	Run:  runFormulaTest,
+}
+
+// FormulaTestResult is the outcome of one test case.
+type FormulaTestResult struct {
+	Case     string   `json:"case"`
+	Golden   string   `json:"golden"`
+	Passed   bool     `json:"passed"`
+	Updated  bool     `json:"updated,omitempty"`
+	Failures []string `json:"failures,omitempty"`
+	Diff     string   `json:"diff,omitempty"`
+}
+
+// formulaSnapshot is the canonical form of a cooked formula.
+type formulaSnapshot struct {
+	Formula string            `yaml:"formula"`
+	Vars    map[string]string `yaml:"vars,omitempty"`
+	Issues  []*snapshotIssue  `yaml:"issues"`
+}
+
+type snapshotIssue struct {
+	ID          string        `yaml:"id"`
+	Title       string        `yaml:"title"`
+	Description string        `yaml:"description,omitempty"`
+	Type        string        `yaml:"type"`
+	Priority    int           `yaml:"priority"`
+	Assignee    string        `yaml:"assignee,omitempty"`
+	Labels      []string      `yaml:"labels,omitempty"`
+	DependsOn   []snapshotDep `yaml:"depends_on,omitempty"`
+	Metadata    interface{}   `yaml:"metadata,omitempty"`
+}
+
+type snapshotDep struct {
+	ID   string `yaml:"id"`
+	Type string `yaml:"type"`
+}
+
+func runFormulaTest(cmd *cobra.Command, args []string) {
+	update, _ := cmd.Flags().GetBool("update")
+	only, _ := cmd.Flags().GetString("run")
+	varFlags, _ := cmd.Flags().GetStringArray("var")
+
+	overrides := make(map[string]string)
+	for _, v := range varFlags {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			FatalErrorRespectJSON("invalid variable format '%s', expected 'key=value'", v)
+		}
+		overrides[key] = value
+	}
+
+	f, err := formula.NewParser().LoadByName(args[0])
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	cases := f.Tests
+	if len(cases) == 0 {
+		cases = []*formula.TestCase{{}}
+	}
+
+	var results []*FormulaTestResult
+	failed := 0
+	for _, tc := range cases {
+		name := tc.Name
+		if name == "" {
+			name = "default"
+		}
+		if only != "" && name != only {
+			continue
+		}
+		vars := make(map[string]string)
+		for k, v := range tc.Vars {
+			vars[k] = v
+		}
+		for k, v := range overrides {
+			vars[k] = v
+		}
+		result := runFormulaTestCase(rootCtx, f, name, vars, tc.Assert, update)
+		if !result.Passed {
+			failed++
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		FatalErrorRespectJSON("no test case named %q in %s", only, f.Formula)
+	}
+
+	if jsonOutput {
+		outputJSON(results)
+	} else {
+		printFormulaTestResults(f.Formula, results)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runFormulaTestCase cooks one case, checks its assertions and compares
+// (or, with update, writes) its golden file.
+func runFormulaTestCase(ctx context.Context, f *formula.Formula, name string, vars map[string]string, asserts []*formula.TestAssertion, update bool) *FormulaTestResult {
+	result := &FormulaTestResult{
+		Case:   name,
+		Golden: filepath.Join(filepath.Dir(f.Source), fmt.Sprintf("%s.%s.golden.yaml", f.Formula, name)),
+	}
+	fail := func(format string, args ...interface{}) *FormulaTestResult {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		return result
+	}
+
+	snapshot, err := cookFormulaSnapshot(ctx, f.Formula, vars)
+	if err != nil {
+		return fail("%v", err)
+	}
+	for i, a := range asserts {
+		if err := checkFormulaAssertion(snapshot, a); err != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("assert[%d]: %v", i, err))
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(snapshot); err != nil {
+		return fail("encoding snapshot: %v", err)
+	}
+	got := buf.Bytes()
+	// #nosec G304 -- golden path is derived from the formula's location
+	want, err := os.ReadFile(result.Golden)
+	switch {
+	case update:
+		if err != nil || !bytes.Equal(got, want) {
+			// #nosec G306 -- golden files are committed alongside formulas
+			if err := os.WriteFile(result.Golden, got, 0o644); err != nil {
+				return fail("writing golden file: %v", err)
+			}
+			result.Updated = true
+		}
+	case os.IsNotExist(err):
+		result.Failures = append(result.Failures, "golden file missing (run with --update to create it)")
+	case err != nil:
+		result.Failures = append(result.Failures, fmt.Sprintf("reading golden file: %v", err))
+	case !bytes.Equal(got, want):
+		result.Failures = append(result.Failures, "snapshot differs from golden file")
+		result.Diff = lineDiff(string(want), string(got))
+	}
+
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// cookFormulaSnapshot cooks a formula with vars into a fresh in-memory
+// store, as 'fbd cook --persist --var' would, and returns the canonical
+// snapshot of what was created.
+func cookFormulaSnapshot(ctx context.Context, name string, vars map[string]string) (*formulaSnapshot, error) {
+	resolved, err := resolveFormulaWithVars(name, nil, vars)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(vars))
+	for k, v := range vars {
+		values[k] = v
+	}
+	for k, def := range resolved.Vars {
+		if _, ok := values[k]; !ok && def != nil && def.Default != "" {
+			values[k] = def.Default
+		}
+	}
+	var missing []string
+	for _, v := range formula.ExtractVariables(resolved) {
+		// Undeclared {{handlebars}} are documentation, not inputs
+		if _, declared := resolved.Vars[v]; !declared {
+			continue
+		}
+		if _, ok := values[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required variables: %s (set them in the test case's vars)", strings.Join(missing, ", "))
+	}
+	substituteFormulaVars(resolved, values)
+
+	s := formulaTestStore{memory.New("")}
+	defer func() { _ = s.Close() }()
+	// Cooked gate steps become gate issues
+	if err := s.SetConfig(ctx, "types.custom", "gate"); err != nil {
+		return nil, err
+	}
+	if _, err := cookFormula(ctx, s, resolved, resolved.Formula); err != nil {
+		return nil, fmt.Errorf("cooking: %w", err)
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &formulaSnapshot{Formula: name, Vars: values}
+	for _, issue := range issues {
+		labels, err := s.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		deps, err := s.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		si := &snapshotIssue{
+			ID: issue.ID,
+			// The root's {{title}} and {{desc}} placeholders are left for pour
+			Title:       substituteVariables(issue.Title, values),
+			Description: substituteVariables(issue.Description, values),
+			Type:        string(issue.IssueType),
+			Priority:    issue.Priority,
+			Assignee:    issue.Assignee,
+			Labels:      labels,
+		}
+		sort.Strings(si.Labels)
+		for _, dep := range deps {
+			si.DependsOn = append(si.DependsOn, snapshotDep{ID: dep.DependsOnID, Type: string(dep.Type)})
+		}
+		sort.Slice(si.DependsOn, func(i, j int) bool {
+			if si.DependsOn[i].ID != si.DependsOn[j].ID {
+				return si.DependsOn[i].ID < si.DependsOn[j].ID
+			}
+			return si.DependsOn[i].Type < si.DependsOn[j].Type
+		})
+		if len(issue.Metadata) > 0 {
+			if err := json.Unmarshal(issue.Metadata, &si.Metadata); err != nil {
+				si.Metadata = string(issue.Metadata)
+			}
+		}
+		snapshot.Issues = append(snapshot.Issues, si)
+	}
+	sort.Slice(snapshot.Issues, func(i, j int) bool { return snapshot.Issues[i].ID < snapshot.Issues[j].ID })
+	return snapshot, nil
+}
+
+// formulaTestStore is a throwaway in-memory store for formula tests. Memory
+// storage refuses transactions because it cannot roll back; a test store is
+// discarded after one pour, so it runs them directly.
+type formulaTestStore struct {
+	*memory.MemoryStorage
+}
+
+func (s formulaTestStore) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) error {
+	return fn(s.MemoryStorage)
+}
+
+// find returns the snapshot issue for a step ID or full template ID.
+func (s *formulaSnapshot) find(step string) *snapshotIssue {
+	for _, issue := range s.Issues {
+		if issue.ID == step || issue.ID == s.Formula+"."+step {
+			return issue
+		}
+	}
+	return nil
+}
+
+// dependsOn reports whether issue depends on step other than as its child.
+func (s *formulaSnapshot) dependsOn(issue *snapshotIssue, step string) bool {
+	target := s.find(step)
+	for _, dep := range issue.DependsOn {
+		if dep.Type == string(types.DepParentChild) {
+			continue
+		}
+		if dep.ID == step || (target != nil && dep.ID == target.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormulaAssertion checks one assertion against a snapshot.
+func checkFormulaAssertion(s *formulaSnapshot, a *formula.TestAssertion) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if a.Issues != nil {
+		if len(s.Issues) != *a.Issues {
+			return fmt.Errorf("expected %d issues, got %d", *a.Issues, len(s.Issues))
+		}
+		return nil
+	}
+
+	issue := s.find(a.Step)
+	if a.Absent {
+		if issue != nil {
+			return fmt.Errorf("step %s should not be created", a.Step)
+		}
+	} else if issue == nil {
+		return fmt.Errorf("step %s not found", a.Step)
+	}
+
+	var problems []string
+	for _, dep := range a.DependsOn {
+		if !s.dependsOn(issue, dep) {
+			problems = append(problems, fmt.Sprintf("does not depend on %s", dep))
+		}
+	}
+	for _, dep := range a.NotDependsOn {
+		if issue != nil && s.dependsOn(issue, dep) {
+			problems = append(problems, fmt.Sprintf("depends on %s", dep))
+		}
+	}
+	for _, label := range a.Labels {
+		found := false
+		for _, l := range issue.Labels {
+			found = found || l == label
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("missing label %q", label))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("step %s %s", a.Step, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// lineDiff returns a minimal line diff of want -> got, prefixing removed
+// lines with "-" and added lines with "+".
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// Longest common subsequence table, filled from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "-%s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+%s\n", b[j])
+			j++
+		}
+	}
+	return out.String()
+}
+
+func printFormulaTestResults(name string, results []*FormulaTestResult) {
+	passed := 0
+	for _, r := range results {
+		switch {
+		case !r.Passed:
+			fmt.Printf("%s %s/%s\n", ui.RenderFail("✗"), name, r.Case)
+			for _, failure := range r.Failures {
+				fmt.Printf("    %s\n", failure)
+			}
+			if r.Diff != "" {
+				fmt.Printf("    --- %s\n    +++ cooked\n", r.Golden)
+				for _, line := range strings.Split(strings.TrimSuffix(r.Diff, "\n"), "\n") {
+					fmt.Printf("    %s\n", line)
+				}
+			}
+		case r.Updated:
+			passed++
+			fmt.Printf("%s %s/%s (golden updated: %s)\n", ui.RenderPass("✓"), name, r.Case, r.Golden)
+		default:
+			passed++
+			fmt.Printf("%s %s/%s\n", ui.RenderPass("✓"), name, r.Case)
+		}
+	}
+	fmt.Printf("\n%d passed, %d failed\n", passed, len(results)-passed)
+}
+
+func init() {
+	formulaTestCmd.Flags().Bool("update", false, "Write golden files from the cooked output")
+	formulaTestCmd.Flags().String("run", "", "Only run the named test case")
+	formulaTestCmd.Flags().StringArray("var", []string{}, "Variable override for every case (key=value)")
+
+	formulaCmd.AddCommand(formulaTestCmd)
+}
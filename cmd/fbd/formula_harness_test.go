@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/formula"
+)
+
+const harnessWorkflow = `
+formula = "mol-ship"
+type = "workflow"
+
+[vars.component]
+required = true
+
+[[steps]]
+id = "build"
+title = "Build {{component}}"
+labels = ["ci"]
+
+[[steps]]
+id = "ship"
+title = "Ship {{component}}"
+needs = ["build"]
+
+[compose]
+aspects = ["aspect-lint"]
+
+[[tests]]
+name = "auth"
+vars = { component = "auth" }
+
+[[tests.assert]]
+issues = 4
+
+[[tests.assert]]
+step = "ship"
+depends_on = ["build"]
+
+[[tests.assert]]
+step = "build"
+depends_on = ["lint-build"]
+labels = ["ci"]
+`
+
+const harnessAspect = `
+formula = "aspect-lint"
+type = "aspect"
+
+[[advice]]
+target = "build"
+[advice.before]
+id = "lint-{step.id}"
+title = "Lint before {step.id}"
+`
+
+func setupFormulaHarness(t *testing.T) *formula.Formula {
+	t.Helper()
+	dir := t.TempDir()
+	formulaDir := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"mol-ship": harnessWorkflow, "aspect-lint": harnessAspect} {
+		if err := os.WriteFile(filepath.Join(formulaDir, name+formula.FormulaExtTOML), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+	f, err := formula.NewParser().LoadByName("mol-ship")
+	if err != nil {
+		t.Fatalf("LoadByName: %v", err)
+	}
+	return f
+}
+
+func TestRunFormulaTestCase_Golden(t *testing.T) {
+	f := setupFormulaHarness(t)
+	ctx := context.Background()
+	tc := f.Tests[0]
+
+	result := runFormulaTestCase(ctx, f, tc.Name, tc.Vars, tc.Assert, false)
+	if result.Passed || !strings.Contains(strings.Join(result.Failures, "\n"), "golden file missing") {
+		t.Fatalf("without golden = %+v, want missing golden failure", result)
+	}
+
+	result = runFormulaTestCase(ctx, f, tc.Name, tc.Vars, tc.Assert, true)
+	if !result.Passed || !result.Updated {
+		t.Fatalf("update = %+v", result)
+	}
+	golden, err := os.ReadFile(result.Golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"id: mol-ship.lint-build", "title: Build auth", "- ci"} {
+		if !strings.Contains(string(golden), want) {
+			t.Errorf("golden file missing %q:\n%s", want, golden)
+		}
+	}
+
+	// Cooking is deterministic, so a second run matches
+	if result := runFormulaTestCase(ctx, f, tc.Name, tc.Vars, tc.Assert, false); !result.Passed {
+		t.Fatalf("rerun = %+v", result)
+	}
+
+	// Different vars change the snapshot
+	result = runFormulaTestCase(ctx, f, tc.Name, map[string]string{"component": "billing"}, nil, false)
+	if result.Passed || !strings.Contains(result.Diff, "-    title: Build auth") || !strings.Contains(result.Diff, "+    title: Build billing") {
+		t.Errorf("changed vars = %+v", result)
+	}
+}
+
+func TestRunFormulaTestCase_Assertions(t *testing.T) {
+	f := setupFormulaHarness(t)
+	three := 3
+	asserts := []*formula.TestAssertion{
+		{Issues: &three},
+		{Step: "build", DependsOn: []string{"ship"}},
+		{Step: "ship", NotDependsOn: []string{"build"}},
+		{Step: "lint-build", Absent: true},
+		{Step: "missing", Labels: []string{"ci"}},
+	}
+	result := runFormulaTestCase(context.Background(), f, "auth", map[string]string{"component": "auth"}, asserts, true)
+	if len(result.Failures) != len(asserts) {
+		t.Fatalf("failures = %v, want one per assertion", result.Failures)
+	}
+
+	result = runFormulaTestCase(context.Background(), f, "auth", nil, nil, true)
+	if result.Passed || !strings.Contains(result.Failures[0], "component") {
+		t.Errorf("missing var = %+v", result)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("a\nb\nc\n", "a\nx\nc\nd\n")
+	if got != "-b\n+x\n+d\n" {
+		t.Errorf("lineDiff = %q", got)
+	}
+	if got := lineDiff("same\n", "same\n"); got != "" {
+		t.Errorf("lineDiff of equal text = %q", got)
+	}
+}
@@ -0,0 +1,580 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/formula"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+var formulaInstallCmd = &cobra.Command{
+	Use:   "install [<git-url|path>[@<version>]]",
+	Short: "Install a formula package",
+	Long: `Install a formula package into .beads/formulas/vendor/<name>/.
+
+A package is a git repository whose tags are its versions (v1.2.3 or 1.2.3).
+Its formulas are taken from .beads/formulas/, formulas/, or the repository
+root, whichever exists first. The version may be an exact version or a range
+(^1.2, ~1.2.3, >=1.0 <2); without one, the latest release is installed and
+recorded as ^<version>.
+
+The installed version, commit and content hashes are recorded in
+.beads/formulas.lock. Commit it: running 'fbd formula install' with no
+arguments reinstalls exactly the locked packages and verifies their hashes.
+
+Vendored formulas are found by name like any other formula. To pin a version
+in extends, expand or compose rules, reference it as name@range:
+
+  extends = ["base-release@^1.2"]
+
+Examples:
+  fbd formula install https://github.com/acme/ops-formulas.git@^1.2
+  fbd formula install ../shared-formulas@1.0.0 --name shared
+  fbd formula install                # Restore packages from formulas.lock`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runFormulaInstall,
+}
+
+var formulaOutdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show installed formula packages with newer versions",
+	Long: `Show installed formula packages with newer versions.
+
+Wanted is the newest version allowed by the package's range in
+formulas.lock; latest is the newest release overall.
+
+Examples:
+  fbd formula outdated
+  fbd formula outdated --json`,
+	Args: cobra.NoArgs,
+	Run:  runFormulaOutdated,
+}
+
+var formulaUpdateCmd = &cobra.Command{
+	Use:   "update [package...]",
+	Short: "Update installed formula packages",
+	Long: `Update installed formula packages to the newest version their range allows.
+
+With --latest, move to the newest release even across major versions; the
+package's range becomes ^<latest>.
+
+Examples:
+  fbd formula update                 # Update all packages within their ranges
+  fbd formula update ops-formulas    # Update one package
+  fbd formula update --latest        # Also take new major versions`,
+	Run: runFormulaUpdate,
+}
+
+// FormulaPackageStatus is one package in install/outdated/update output.
+type FormulaPackageStatus struct {
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	Constraint string `json:"constraint"`
+	Current    string `json:"current,omitempty"`
+	Wanted     string `json:"wanted,omitempty"`
+	Latest     string `json:"latest,omitempty"`
+	Action     string `json:"action,omitempty"` // installed, updated, restored, unchanged
+	Error      string `json:"error,omitempty"`
+}
+
+// packageVersion is a release tag of a formula package.
+type packageVersion struct {
+	tag    string // Tag as written, e.g. "v1.2.3" or "1.2.3"
+	commit string
+}
+
+func runFormulaInstall(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("name")
+	formulaDir, lock := loadProjectLockfile()
+
+	if len(args) == 0 {
+		if name != "" {
+			FatalErrorRespectJSON("--name requires a package source")
+		}
+		restoreFormulaPackages(formulaDir, lock)
+		return
+	}
+
+	source, constraint := splitPackageSource(args[0])
+	if name == "" {
+		name = packageNameFromSource(source)
+	}
+	if err := formula.ValidatePackageName(name); err != nil {
+		FatalErrorRespectJSON("%v (use --name)", err)
+	}
+
+	status := &FormulaPackageStatus{Name: name, Source: source, Constraint: constraint, Action: "installed"}
+	if old := lock.Packages[name]; old != nil {
+		status.Current = old.Version
+	}
+	pkg, err := installFormulaPackage(formulaDir, name, source, constraint)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	lock.Packages[name] = pkg
+	if err := lock.Save(formula.LockfilePath(formulaDir)); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	status.Constraint, status.Wanted = pkg.Constraint, pkg.Version
+
+	if jsonOutput {
+		outputJSON(status)
+		return
+	}
+	fmt.Printf("%s Installed %s %s (%d formulas) from %s\n",
+		ui.RenderPass("✓"), name, pkg.Version, len(pkg.Files), source)
+	fmt.Printf("  Range: %s\n", pkg.Constraint)
+	fmt.Printf("  Path:  %s\n", filepath.Join(formula.VendorDir(formulaDir), name))
+}
+
+// restoreFormulaPackages installs the exact locked version of every package,
+// skipping packages whose vendored files already match the lock.
+func restoreFormulaPackages(formulaDir string, lock *formula.Lockfile) {
+	var results []*FormulaPackageStatus
+	failed := false
+	for _, name := range lock.PackageNames() {
+		pkg := lock.Packages[name]
+		status := &FormulaPackageStatus{Name: name, Source: pkg.Source, Constraint: pkg.Constraint, Current: pkg.Version}
+		action, err := restoreFormulaPackage(formulaDir, name, pkg)
+		if err != nil {
+			status.Error = err.Error()
+			failed = true
+		}
+		status.Action = action
+		results = append(results, status)
+	}
+
+	if jsonOutput {
+		outputJSON(results)
+	} else if len(results) == 0 {
+		fmt.Printf("No packages in %s\n", formula.LockfilePath(formulaDir))
+	} else {
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Printf("%s %s %s: %s\n", ui.RenderFail("✗"), r.Name, r.Current, r.Error)
+			case r.Action == "unchanged":
+				fmt.Printf("%s %s %s (up to date)\n", ui.RenderPass("✓"), r.Name, r.Current)
+			default:
+				fmt.Printf("%s %s %s restored\n", ui.RenderPass("✓"), r.Name, r.Current)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// restoreFormulaPackage makes the vendored copy of a package match the lock.
+func restoreFormulaPackage(formulaDir, name string, pkg *formula.LockedPackage) (string, error) {
+	if _, hash, err := formula.HashFormulaFiles(filepath.Join(formula.VendorDir(formulaDir), name)); err == nil && hash == pkg.Hash {
+		return "unchanged", nil
+	}
+
+	checkout, commit, cleanup, err := fetchFormulaPackage(pkg.Source, pkg.Version)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	if pkg.Commit != "" && commit != pkg.Commit {
+		return "", fmt.Errorf("tag %s now points to %s, locked at %s (tag was moved)", pkg.Version, shortCommit(commit), shortCommit(pkg.Commit))
+	}
+	if _, _, err := vendorFormulaPackage(formulaDir, name, checkout, pkg.Hash); err != nil {
+		return "", err
+	}
+	return "restored", nil
+}
+
+func runFormulaOutdated(cmd *cobra.Command, args []string) {
+	formulaDir, lock := loadProjectLockfile()
+
+	var results []*FormulaPackageStatus
+	for _, name := range lock.PackageNames() {
+		pkg := lock.Packages[name]
+		status := &FormulaPackageStatus{Name: name, Source: pkg.Source, Constraint: pkg.Constraint, Current: pkg.Version}
+		if wanted, latest, err := packageUpgrades(pkg); err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Wanted, status.Latest = wanted.tag, latest.tag
+		}
+		results = append(results, status)
+	}
+
+	if jsonOutput {
+		outputJSON(results)
+		return
+	}
+	if len(results) == 0 {
+		fmt.Printf("No packages in %s\n", formula.LockfilePath(formulaDir))
+		return
+	}
+	fmt.Printf("%-24s %-10s %-10s %-10s %s\n", "PACKAGE", "CURRENT", "WANTED", "LATEST", "RANGE")
+	outdated := 0
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("%-24s %-10s %s\n", r.Name, r.Current, ui.RenderFail(r.Error))
+			continue
+		}
+		line := fmt.Sprintf("%-24s %-10s %-10s %-10s %s", r.Name, r.Current, r.Wanted, r.Latest, r.Constraint)
+		if r.Latest != r.Current {
+			outdated++
+			line = ui.RenderWarn(line)
+		}
+		fmt.Println(line)
+	}
+	if outdated == 0 {
+		fmt.Printf("\n%s All packages are up to date\n", ui.RenderPass("✓"))
+	}
+}
+
+func runFormulaUpdate(cmd *cobra.Command, args []string) {
+	latest, _ := cmd.Flags().GetBool("latest")
+	formulaDir, lock := loadProjectLockfile()
+
+	names := args
+	if len(names) == 0 {
+		names = lock.PackageNames()
+	}
+
+	var results []*FormulaPackageStatus
+	failed := false
+	for _, name := range names {
+		pkg := lock.Packages[name]
+		if pkg == nil {
+			results = append(results, &FormulaPackageStatus{Name: name, Error: "not installed"})
+			failed = true
+			continue
+		}
+		status := &FormulaPackageStatus{Name: name, Source: pkg.Source, Constraint: pkg.Constraint, Current: pkg.Version}
+		results = append(results, status)
+
+		wanted, newest, err := packageUpgrades(pkg)
+		if err != nil {
+			status.Error, failed = err.Error(), true
+			continue
+		}
+		constraint := pkg.Constraint
+		if latest {
+			wanted = newest
+			constraint = "^" + strings.TrimPrefix(newest.tag, "v")
+		}
+		status.Wanted, status.Latest = wanted.tag, newest.tag
+		if wanted.tag == pkg.Version && constraint == pkg.Constraint {
+			status.Action = "unchanged"
+			continue
+		}
+
+		updated, err := installFormulaPackage(formulaDir, name, pkg.Source, "="+wanted.tag)
+		if err != nil {
+			status.Error, failed = err.Error(), true
+			continue
+		}
+		updated.Constraint = constraint
+		lock.Packages[name] = updated
+		status.Action, status.Constraint = "updated", constraint
+	}
+	if err := lock.Save(formula.LockfilePath(formulaDir)); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+
+	if jsonOutput {
+		outputJSON(results)
+	} else {
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Printf("%s %s: %s\n", ui.RenderFail("✗"), r.Name, r.Error)
+			case r.Action == "updated":
+				fmt.Printf("%s %s %s → %s\n", ui.RenderPass("✓"), r.Name, r.Current, r.Wanted)
+			default:
+				fmt.Printf("  %s %s (up to date within %s)\n", r.Name, r.Current, r.Constraint)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadProjectLockfile returns the project formulas directory and its lockfile.
+func loadProjectLockfile() (string, *formula.Lockfile) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	formulaDir := filepath.Join(cwd, ".beads", "formulas")
+	lock, err := formula.LoadLockfile(formula.LockfilePath(formulaDir))
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	return formulaDir, lock
+}
+
+// splitPackageSource splits "source@version". The "@" in scp-style git URLs
+// (git@host:repo) is not a version separator.
+func splitPackageSource(arg string) (source, constraint string) {
+	i := strings.LastIndex(arg, "@")
+	if i <= 0 || strings.ContainsAny(arg[i+1:], ":/") {
+		return arg, ""
+	}
+	return arg[:i], arg[i+1:]
+}
+
+// packageNameFromSource derives a package name from its source:
+// "https://github.com/acme/ops-formulas.git" -> "ops-formulas".
+func packageNameFromSource(source string) string {
+	source = strings.TrimRight(source, `/\`)
+	if i := strings.LastIndexAny(source, `/\:`); i >= 0 {
+		source = source[i+1:]
+	}
+	return strings.TrimSuffix(source, ".git")
+}
+
+// installFormulaPackage vendors the newest version of a package allowed by
+// the constraint and returns its lock entry. An empty constraint installs
+// the latest release and records ^<version>.
+func installFormulaPackage(formulaDir, name, source, constraint string) (*formula.LockedPackage, error) {
+	c, err := formula.ParseVersionConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := listPackageVersions(source)
+	if err != nil {
+		return nil, err
+	}
+	chosen, ok := latestPackageVersion(versions, c)
+	if !ok {
+		return nil, fmt.Errorf("no version of %s matches %q (available: %s)", source, constraint, describeVersions(versions))
+	}
+
+	checkout, commit, cleanup, err := fetchFormulaPackage(source, chosen.tag)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	files, hash, err := vendorFormulaPackage(formulaDir, name, checkout, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", source, chosen.tag, err)
+	}
+
+	if constraint == "" {
+		constraint = "^" + strings.TrimPrefix(chosen.tag, "v")
+	}
+	return &formula.LockedPackage{
+		Source:     source,
+		Constraint: constraint,
+		Version:    chosen.tag,
+		Commit:     commit,
+		Hash:       hash,
+		Files:      files,
+	}, nil
+}
+
+// packageUpgrades returns the newest version allowed by a package's range and
+// the newest release overall.
+func packageUpgrades(pkg *formula.LockedPackage) (wanted, latest packageVersion, err error) {
+	versions, err := listPackageVersions(pkg.Source)
+	if err != nil {
+		return wanted, latest, err
+	}
+	c, err := formula.ParseVersionConstraint(pkg.Constraint)
+	if err != nil {
+		return wanted, latest, err
+	}
+	anyVersion, _ := formula.ParseVersionConstraint("")
+	latest, _ = latestPackageVersion(versions, anyVersion)
+	wanted, ok := latestPackageVersion(versions, c)
+	if !ok {
+		// Nothing newer matches; the locked version is still wanted
+		wanted = packageVersion{tag: pkg.Version, commit: pkg.Commit}
+	}
+	return wanted, latest, nil
+}
+
+// latestPackageVersion picks the newest version allowed by the constraint.
+func latestPackageVersion(versions []packageVersion, c *formula.VersionConstraint) (packageVersion, bool) {
+	tags := make([]string, len(versions))
+	for i, v := range versions {
+		tags[i] = v.tag
+	}
+	best := c.LatestAllowed(tags)
+	for _, v := range versions {
+		if v.tag == best && best != "" {
+			return v, true
+		}
+	}
+	return packageVersion{}, false
+}
+
+func describeVersions(versions []packageVersion) string {
+	if len(versions) == 0 {
+		return "no version tags"
+	}
+	tags := make([]string, len(versions))
+	for i, v := range versions {
+		tags[i] = v.tag
+	}
+	semver.Sort(tags)
+	return strings.Join(tags, ", ")
+}
+
+// checkGitArgs rejects package sources and tags that git would parse as
+// options. Both can come from a committed lockfile.
+func checkGitArgs(args ...string) error {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("invalid package source or version %q", arg)
+		}
+	}
+	return nil
+}
+
+// listPackageVersions lists the version tags of a package repository.
+func listPackageVersions(source string) ([]packageVersion, error) {
+	if err := checkGitArgs(source); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("git", "ls-remote", "--tags", "--", source).Output() // #nosec G204 -- source is a user-provided repository
+	if err != nil {
+		return nil, fmt.Errorf("listing versions of %s: %w%s", source, err, gitStderr(err))
+	}
+	commits := make(map[string]string)
+	var tags []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		sha, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		tag := strings.TrimPrefix(ref, "refs/tags/")
+		if peeled, ok := strings.CutSuffix(tag, "^{}"); ok {
+			// Annotated tag: the peeled entry is the commit
+			commits[peeled] = sha
+			continue
+		}
+		if formula.CanonicalVersion(tag) == "" {
+			continue
+		}
+		if _, seen := commits[tag]; !seen {
+			tags = append(tags, tag)
+			commits[tag] = sha
+		}
+	}
+	versions := make([]packageVersion, len(tags))
+	for i, tag := range tags {
+		versions[i] = packageVersion{tag: tag, commit: commits[tag]}
+	}
+	return versions, nil
+}
+
+// fetchFormulaPackage checks out one tag of a package into a temporary
+// directory and returns the checkout and its commit.
+func fetchFormulaPackage(source, tag string) (string, string, func(), error) {
+	if err := checkGitArgs(source, tag); err != nil {
+		return "", "", nil, err
+	}
+	tmp, err := os.MkdirTemp("", "fbd-formula-*")
+	if err != nil {
+		return "", "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+
+	// #nosec G204 -- source and tag come from the user or the lockfile
+	clone := exec.Command("git", "-c", "advice.detachedHead=false", "clone", "--quiet", "--depth", "1", "--branch", tag, "--", source, tmp)
+	if out, err := clone.CombinedOutput(); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("fetching %s@%s: %w\n%s", source, tag, err, strings.TrimSpace(string(out)))
+	}
+	out, err := exec.Command("git", "-C", tmp, "rev-parse", "HEAD").Output()
+	if err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("reading commit of %s@%s: %w", source, tag, err)
+	}
+	return tmp, strings.TrimSpace(string(out)), cleanup, nil
+}
+
+// vendorFormulaPackage replaces .beads/formulas/vendor/<name> with the
+// formulas of a checkout and returns their hashes. A non-empty wantHash must
+// match the new content, otherwise the current copy is left in place.
+func vendorFormulaPackage(formulaDir, name, checkout, wantHash string) (map[string]string, string, error) {
+	src := checkout
+	for _, candidate := range []string{filepath.Join(checkout, ".beads", "formulas"), filepath.Join(checkout, "formulas")} {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			src = candidate
+			break
+		}
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := formula.ValidatePackageName(name); err != nil {
+		return nil, "", err
+	}
+	vendorDir := formula.VendorDir(formulaDir)
+	dest := filepath.Join(vendorDir, name)
+	if rel, err := filepath.Rel(vendorDir, dest); err != nil || rel != name {
+		return nil, "", fmt.Errorf("package %q would be vendored outside %s", name, vendorDir)
+	}
+	staging := dest + ".tmp"
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, "", err
+	}
+	if err := os.MkdirAll(staging, 0o755); err != nil {
+		return nil, "", err
+	}
+	copied := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !formula.IsFormulaFile(entry.Name()) {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, entry.Name()), filepath.Join(staging, entry.Name())); err != nil {
+			_ = os.RemoveAll(staging)
+			return nil, "", err
+		}
+		copied++
+	}
+	if copied == 0 {
+		_ = os.RemoveAll(staging)
+		return nil, "", fmt.Errorf("no formulas found")
+	}
+	files, hash, err := formula.HashFormulaFiles(staging)
+	if err != nil {
+		_ = os.RemoveAll(staging)
+		return nil, "", err
+	}
+	if wantHash != "" && hash != wantHash {
+		_ = os.RemoveAll(staging)
+		return nil, "", fmt.Errorf("content hash %s does not match locked %s", hash, wantHash)
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return nil, "", err
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		return nil, "", err
+	}
+	return files, hash, nil
+}
+
+// gitStderr returns a git command's stderr for error messages.
+func gitStderr(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return "\n" + strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return ""
+}
+
+func init() {
+	formulaInstallCmd.Flags().String("name", "", "Package name (default: derived from the source)")
+	formulaUpdateCmd.Flags().Bool("latest", false, "Update to the newest release, even across major versions")
+
+	formulaCmd.AddCommand(formulaInstallCmd)
+	formulaCmd.AddCommand(formulaOutdatedCmd)
+	formulaCmd.AddCommand(formulaUpdateCmd)
+}
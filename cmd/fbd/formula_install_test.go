@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/formula"
+)
+
+// newFormulaPackageRepo creates a git repository with one formula tagged at
+// each version.
+func newFormulaPackageRepo(t *testing.T, versions ...string) string {
+	t.Helper()
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "--quiet")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	if err := os.MkdirAll(filepath.Join(repo, "formulas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range versions {
+		content := `{"formula": "base-release", "description": "` + v + `", "steps": [{"id": "build", "title": "Build"}]}`
+		if err := os.WriteFile(filepath.Join(repo, "formulas", "base-release.formula.json"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "--quiet", "-m", v)
+		git("tag", v)
+	}
+	return repo
+}
+
+func TestInstallFormulaPackage(t *testing.T) {
+	repo := newFormulaPackageRepo(t, "v1.0.0", "v1.1.0", "v2.0.0")
+	formulaDir := filepath.Join(t.TempDir(), ".beads", "formulas")
+
+	pkg, err := installFormulaPackage(formulaDir, "shared", repo, "^1.0")
+	if err != nil {
+		t.Fatalf("installFormulaPackage: %v", err)
+	}
+	if pkg.Version != "v1.1.0" || pkg.Constraint != "^1.0" || pkg.Commit == "" {
+		t.Errorf("installed %+v, want v1.1.0 within ^1.0", pkg)
+	}
+	files, hash, err := formula.HashFormulaFiles(filepath.Join(formula.VendorDir(formulaDir), "shared"))
+	if err != nil || hash != pkg.Hash || len(files) != 1 {
+		t.Errorf("vendored hash = %s (%v), locked %s", hash, err, pkg.Hash)
+	}
+
+	wanted, latest, err := packageUpgrades(pkg)
+	if err != nil {
+		t.Fatalf("packageUpgrades: %v", err)
+	}
+	if wanted.tag != "v1.1.0" || latest.tag != "v2.0.0" {
+		t.Errorf("wanted %s latest %s, want v1.1.0 and v2.0.0", wanted.tag, latest.tag)
+	}
+
+	// Restoring an intact package is a no-op; a tampered one is refetched
+	if action, err := restoreFormulaPackage(formulaDir, "shared", pkg); err != nil || action != "unchanged" {
+		t.Errorf("restore intact = %s, %v", action, err)
+	}
+	vendored := filepath.Join(formula.VendorDir(formulaDir), "shared", "base-release.formula.json")
+	if err := os.WriteFile(vendored, []byte(`{"formula": "base-release"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if action, err := restoreFormulaPackage(formulaDir, "shared", pkg); err != nil || action != "restored" {
+		t.Errorf("restore tampered = %s, %v", action, err)
+	}
+
+	// Content that doesn't match the lock leaves the vendored copy alone
+	if err := os.WriteFile(vendored, []byte(`{"formula": "base-release"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mismatched := *pkg
+	mismatched.Hash = "sha256:0000"
+	if _, err := restoreFormulaPackage(formulaDir, "shared", &mismatched); err == nil {
+		t.Error("restore accepted content that does not match the locked hash")
+	}
+	if data, err := os.ReadFile(vendored); err != nil || string(data) != `{"formula": "base-release"}` {
+		t.Errorf("vendored file changed after a hash mismatch: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(formula.VendorDir(formulaDir), "shared.tmp")); !os.IsNotExist(err) {
+		t.Errorf("staging directory left behind: %v", err)
+	}
+
+	// A moved tag fails verification
+	moved := *pkg
+	moved.Commit = "0000000000000000000000000000000000000000"
+	if err := os.RemoveAll(formula.VendorDir(formulaDir)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restoreFormulaPackage(formulaDir, "shared", &moved); err == nil {
+		t.Error("restore accepted a moved tag")
+	}
+
+	// No constraint installs the latest release and records a caret range
+	pkg, err = installFormulaPackage(formulaDir, "shared", repo, "")
+	if err != nil {
+		t.Fatalf("installFormulaPackage latest: %v", err)
+	}
+	if pkg.Version != "v2.0.0" || pkg.Constraint != "^2.0.0" {
+		t.Errorf("installed %s with %s, want v2.0.0 with ^2.0.0", pkg.Version, pkg.Constraint)
+	}
+
+	if _, err := installFormulaPackage(formulaDir, "shared", repo, "^3"); err == nil {
+		t.Error("installed a version matching ^3")
+	}
+}
+
+func TestSplitPackageSource(t *testing.T) {
+	tests := []struct {
+		arg, source, constraint string
+	}{
+		{"https://github.com/acme/ops.git@^1.2", "https://github.com/acme/ops.git", "^1.2"},
+		{"../shared@1.0.0", "../shared", "1.0.0"},
+		{"git@github.com:acme/ops.git", "git@github.com:acme/ops.git", ""},
+		{"git@github.com:acme/ops.git@~1", "git@github.com:acme/ops.git", "~1"},
+		{"./local", "./local", ""},
+	}
+	for _, tt := range tests {
+		source, constraint := splitPackageSource(tt.arg)
+		if source != tt.source || constraint != tt.constraint {
+			t.Errorf("splitPackageSource(%q) = %q, %q", tt.arg, source, constraint)
+		}
+	}
+	if got := packageNameFromSource("https://github.com/acme/ops-formulas.git"); got != "ops-formulas" {
+		t.Errorf("packageNameFromSource = %q", got)
+	}
+	if got := packageNameFromSource("git@github.com:ops.git"); got != "ops" {
+		t.Errorf("packageNameFromSource = %q", got)
+	}
+}
+
+func TestFormulaPackageRejectsUnsafeLockEntries(t *testing.T) {
+	formulaDir := filepath.Join(t.TempDir(), ".beads", "formulas")
+	outside := filepath.Join(filepath.Dir(formulaDir), "keep")
+	if err := os.MkdirAll(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := listPackageVersions("--upload-pack=touch /tmp/pwned"); err == nil {
+		t.Error("listPackageVersions accepted an option as the source")
+	}
+	if _, _, _, err := fetchFormulaPackage(newFormulaPackageRepo(t, "v1.0.0"), "--upload-pack=x"); err == nil {
+		t.Error("fetchFormulaPackage accepted an option as the tag")
+	}
+
+	checkout := newFormulaPackageRepo(t, "v1.0.0")
+	if _, _, err := vendorFormulaPackage(formulaDir, "../../keep", checkout, ""); err == nil {
+		t.Error("vendorFormulaPackage accepted a name outside the vendor directory")
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("directory outside the vendor directory was touched: %v", err)
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +19,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/fastbeads/internal/beads"
 	"github.com/steveyegge/fastbeads/internal/configfile"
+	"github.com/steveyegge/fastbeads/internal/github"
 	"github.com/steveyegge/fastbeads/internal/routing"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/ui"
 )
@@ -34,6 +39,7 @@ They must be closed (manually or via watchers) for the blocked step to proceed.
 Gate types:
   human   - Requires manual fbd close (Phase 1)
   timer   - Expires after timeout (Phase 2)
+  mail    - Waits for a reply from one of the gate's waiters
   gh:run  - Waits for GitHub workflow (Phase 3)
   gh:pr   - Waits for PR merge (Phase 3)
   bead    - Waits for cross-rig bead to close (Phase 4)
@@ -45,6 +51,7 @@ Examples:
   fbd gate list --all     # Show all gates including closed
   fbd gate check          # Evaluate all open gates
   fbd gate check --type=bead  # Evaluate only bead gates
+  fbd gate watch          # Keep evaluating gates until interrupted
   fbd gate resolve <id>   # Close a gate manually`,
 }
 
@@ -340,10 +347,20 @@ Gate types:
   gh:run   - Check GitHub Actions workflow runs
   gh:pr    - Check pull request merge status
   timer    - Check timer gates (auto-expire based on timeout)
+  mail     - Check mail gates for replies from waiters
   bead     - Check cross-rig bead gates
+  human    - Check human gates for timeouts
   all      - Check all gate types
 
-GitHub gates use the 'gh' CLI to query status:
+GitHub gates query the GitHub REST API when a token is configured:
+  github.token / GITHUB_TOKEN / GH_TOKEN  - API token
+  github.url / GITHUB_API_URL             - API base URL for GitHub Enterprise
+                                            (e.g. https://ghe.example.com/api/v3)
+  github.repo / GITHUB_REPOSITORY         - owner/name (default: origin remote)
+Settings are read from 'fbd config', falling back to the environment.
+A gh:pr await_id given as a PR URL is looked up in the repository named
+by the URL, even when it differs from github.repo.
+Without a token they fall back to the 'gh' CLI:
   - gh:run checks 'gh run view <id> --json status,conclusion'
   - gh:pr checks 'gh pr view <id> --json state,merged'
 
@@ -351,161 +368,269 @@ A gate is resolved when:
   - gh:run: status=completed AND conclusion=success
   - gh:pr: state=MERGED
   - timer: current time > created_at + timeout
+  - mail: a bead replies to await_id (or to the gate itself when await_id
+    is empty) via a replies-to edge, sent by one of the gate's waiters
   - bead: target bead status=closed
 
 A gate is escalated when:
   - gh:run: status=completed AND conclusion in (failure, canceled)
   - gh:pr: state=CLOSED AND merged=false
+  - any type except timer: still unresolved after created_at + timeout
+
+With --escalate, an escalated gate is labeled gate:escalated and its waiters
+are notified through the mail delegate (see 'fbd mail'). --create-followup
+also creates a human gate to track the escalation. Gates that are already
+labeled gate:escalated are not escalated again.
 
 Examples:
   fbd gate check              # Check all gates
   fbd gate check --type=gh    # Check only GitHub gates
   fbd gate check --type=gh:run # Check only workflow run gates
   fbd gate check --type=timer # Check only timer gates
+  fbd gate check --type=mail  # Check only mail gates
   fbd gate check --type=bead  # Check only cross-rig bead gates
   fbd gate check --dry-run    # Show what would happen without changes
-  fbd gate check --escalate   # Escalate expired/failed gates`,
+  fbd gate check --escalate   # Escalate expired/failed gates
+  fbd gate check --escalate --create-followup`,
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckReadonly("gate check")
 
-		gateTypeFilter, _ := cmd.Flags().GetString("type")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		escalateFlag, _ := cmd.Flags().GetBool("escalate")
-		limit, _ := cmd.Flags().GetInt("limit")
+		opts := gateCheckOptionsFromFlags(cmd)
+		summary, err := runGateCheck(rootCtx, store, opts, time.Now(), os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if summary.Checked == 0 {
+			return
+		}
 
-		// Get open gates
-		gateType := types.IssueType("gate")
-		filter := types.IssueFilter{
-			IssueType:     &gateType,
-			ExcludeStatus: []types.Status{types.StatusClosed},
-			Limit:         limit,
+		// Summary
+		fmt.Println()
+		fmt.Printf("Checked %d gates: %d resolved, %d escalated, %d errors\n",
+			summary.Checked, summary.Resolved, summary.Escalated, summary.Errors)
+
+		if jsonOutput {
+			outputJSON(summary)
 		}
+	},
+}
 
-		ctx := rootCtx
-		var gates []*types.Issue
-		var err error
+// gateEscalatedLabel marks gates that have already been escalated, so repeated
+// checks (e.g. from 'fbd gate watch') don't notify waiters again.
+const gateEscalatedLabel = "gate:escalated"
+
+// gateCheckOptions controls a single pass over the open gates.
+type gateCheckOptions struct {
+	TypeFilter     string
+	DryRun         bool
+	Escalate       bool
+	CreateFollowup bool
+	Limit          int
+	Quiet          bool // Only report gates that changed (used by gate watch)
+
+	// Reported remembers the status each gate was last reported with across
+	// passes (used by gate watch). Gates reported with the same status again
+	// are marked AlreadyReported and not printed, since without --escalate
+	// or with --dry-run nothing records that they were handled.
+	Reported map[string]string
+}
 
-		gates, err = store.SearchIssues(ctx, "", filter)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+// gateCheckOptionsFromFlags reads the flags shared by gate check and gate watch.
+func gateCheckOptionsFromFlags(cmd *cobra.Command) gateCheckOptions {
+	var opts gateCheckOptions
+	opts.TypeFilter, _ = cmd.Flags().GetString("type")
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Escalate, _ = cmd.Flags().GetBool("escalate")
+	opts.CreateFollowup, _ = cmd.Flags().GetBool("create-followup")
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	return opts
+}
+
+// GateCheckResult is the outcome of evaluating one gate.
+type GateCheckResult struct {
+	ID       string `json:"id"`
+	Type     string `json:"await_type"`
+	Status   string `json:"status"` // resolved, escalated, pending or error
+	Reason   string `json:"reason"`
+	Followup string `json:"followup,omitempty"` // Follow-up gate created on escalation
+
+	AlreadyEscalated bool `json:"already_escalated,omitempty"` // Escalated by an earlier check
+	AlreadyReported  bool `json:"already_reported,omitempty"`  // Reported by an earlier pass of gate watch
+}
+
+// GateCheckSummary summarizes a pass over the open gates.
+type GateCheckSummary struct {
+	Checked   int                `json:"checked"`
+	Resolved  int                `json:"resolved"`
+	Escalated int                `json:"escalated"`
+	Errors    int                `json:"errors"`
+	DryRun    bool               `json:"dry_run"`
+	Gates     []*GateCheckResult `json:"gates,omitempty"`
+}
+
+// runGateCheck evaluates the open gates matching opts, closing resolved gates
+// and (with opts.Escalate) escalating failed or timed-out ones. Progress is
+// written to out; errors for individual gates go to stderr.
+func runGateCheck(ctx context.Context, s storage.Storage, opts gateCheckOptions, now time.Time, out io.Writer) (*GateCheckSummary, error) {
+	// Get open gates
+	gateType := types.IssueType("gate")
+	filter := types.IssueFilter{
+		IssueType:     &gateType,
+		ExcludeStatus: []types.Status{types.StatusClosed},
+		Limit:         opts.Limit,
+	}
+	gates, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by type if specified
+	var filteredGates []*types.Issue
+	for _, gate := range gates {
+		if shouldCheckGate(gate, opts.TypeFilter) {
+			filteredGates = append(filteredGates, gate)
 		}
+	}
 
-		// Filter by type if specified
-		var filteredGates []*types.Issue
-		for _, gate := range gates {
-			if shouldCheckGate(gate, gateTypeFilter) {
-				filteredGates = append(filteredGates, gate)
-			}
+	summary := &GateCheckSummary{DryRun: opts.DryRun}
+	if len(filteredGates) == 0 {
+		if opts.Quiet {
+			return summary, nil
+		}
+		if opts.TypeFilter != "" {
+			fmt.Fprintf(out, "No open gates of type '%s' found.\n", opts.TypeFilter)
+		} else {
+			fmt.Fprintln(out, "No open gates found.")
 		}
+		return summary, nil
+	}
 
-		if len(filteredGates) == 0 {
-			if gateTypeFilter != "" {
-				fmt.Printf("No open gates of type '%s' found.\n", gateTypeFilter)
-			} else {
-				fmt.Println("No open gates found.")
-			}
-			return
+	for _, gate := range filteredGates {
+		var resolved, escalated bool
+		var reason string
+		var checkErr error
+
+		switch {
+		case strings.HasPrefix(gate.AwaitType, "gh:run"):
+			resolved, escalated, reason, checkErr = checkGHRun(gate)
+		case strings.HasPrefix(gate.AwaitType, "gh:pr"):
+			resolved, escalated, reason, checkErr = checkGHPR(gate)
+		case gate.AwaitType == "timer":
+			resolved, escalated, reason, checkErr = checkTimer(gate, now)
+		case gate.AwaitType == "mail":
+			resolved, reason, checkErr = checkMailGate(ctx, s, gate)
+		case gate.AwaitType == "bead":
+			resolved, reason = checkBeadGate(ctx, gate.AwaitID)
+		case gate.Timeout > 0:
+			// Human gates need manual resolution, but still escalate on timeout
+			reason = "awaiting manual resolution"
+		default:
+			// Skip unsupported gate types (human gates need manual resolution)
+			continue
 		}
 
-		// Results tracking
-		type checkResult struct {
-			gate      *types.Issue
-			resolved  bool
-			escalated bool
-			reason    string
-			err       error
-		}
-		results := make([]checkResult, 0, len(filteredGates))
-
-		// Check each gate
-		now := time.Now()
-		for _, gate := range filteredGates {
-			result := checkResult{gate: gate}
-
-			switch {
-			case strings.HasPrefix(gate.AwaitType, "gh:run"):
-				result.resolved, result.escalated, result.reason, result.err = checkGHRun(gate)
-			case strings.HasPrefix(gate.AwaitType, "gh:pr"):
-				result.resolved, result.escalated, result.reason, result.err = checkGHPR(gate)
-			case gate.AwaitType == "timer":
-				result.resolved, result.escalated, result.reason, result.err = checkTimer(gate, now)
-			case gate.AwaitType == "bead":
-				result.resolved, result.reason = checkBeadGate(ctx, gate.AwaitID)
-			default:
-				// Skip unsupported gate types (human gates need manual resolution)
-				continue
+		if checkErr == nil && !resolved && !escalated {
+			if overdue, timeoutReason := checkGateTimeout(gate, now); overdue {
+				escalated = true
+				reason = fmt.Sprintf("%s (%s)", reason, timeoutReason)
 			}
-
-			results = append(results, result)
 		}
 
-		// Process results
-		resolvedCount := 0
-		escalatedCount := 0
-		errorCount := 0
+		result := &GateCheckResult{ID: gate.ID, Type: gate.AwaitType, Reason: reason}
+		summary.Gates = append(summary.Gates, result)
+		summary.Checked++
+		reportedBefore := func(status string) bool {
+			if opts.Reported == nil {
+				return false
+			}
+			if opts.Reported[gate.ID] == status {
+				result.AlreadyReported = true
+				return true
+			}
+			opts.Reported[gate.ID] = status
+			return false
+		}
 
-		for _, r := range results {
-			if r.err != nil {
-				errorCount++
-				fmt.Fprintf(os.Stderr, "%s %s: error checking - %v\n",
-					ui.RenderFail("✗"), r.gate.ID, r.err)
+		switch {
+		case checkErr != nil:
+			result.Status = "error"
+			result.Reason = checkErr.Error()
+			summary.Errors++
+			fmt.Fprintf(os.Stderr, "%s %s: error checking - %v\n",
+				ui.RenderFail("✗"), gate.ID, checkErr)
+
+		case resolved:
+			result.Status = "resolved"
+			summary.Resolved++
+			if opts.DryRun {
+				if reportedBefore(result.Status) {
+					continue
+				}
+				fmt.Fprintf(out, "%s %s: would resolve - %s\n",
+					ui.RenderPass("✓"), gate.ID, reason)
 				continue
 			}
-
-			if r.resolved {
-				resolvedCount++
-				if dryRun {
-					fmt.Printf("%s %s: would resolve - %s\n",
-						ui.RenderPass("✓"), r.gate.ID, r.reason)
-				} else {
-					// Close the gate
-					closeErr := closeGate(ctx, r.gate.ID, r.reason)
-					if closeErr != nil {
-						fmt.Fprintf(os.Stderr, "%s %s: error closing - %v\n",
-							ui.RenderFail("✗"), r.gate.ID, closeErr)
-						errorCount++
-					} else {
-						fmt.Printf("%s %s: resolved - %s\n",
-							ui.RenderPass("✓"), r.gate.ID, r.reason)
-					}
+			// Close the gate
+			if closeErr := s.CloseIssue(ctx, gate.ID, reason, actor, ""); closeErr != nil {
+				result.Status = "error"
+				summary.Errors++
+				fmt.Fprintf(os.Stderr, "%s %s: error closing - %v\n",
+					ui.RenderFail("✗"), gate.ID, closeErr)
+				continue
+			}
+			fmt.Fprintf(out, "%s %s: resolved - %s\n",
+				ui.RenderPass("✓"), gate.ID, reason)
+
+		case escalated:
+			result.Status = "escalated"
+			summary.Escalated++
+			if opts.DryRun {
+				if reportedBefore(result.Status) {
+					continue
 				}
-			} else if r.escalated {
-				escalatedCount++
-				if dryRun {
-					fmt.Printf("%s %s: would escalate - %s\n",
-						ui.RenderWarn("⚠"), r.gate.ID, r.reason)
-				} else {
-					fmt.Printf("%s %s: ESCALATE - %s\n",
-						ui.RenderWarn("⚠"), r.gate.ID, r.reason)
-					// Actually escalate if flag is set
-					if escalateFlag {
-						escalateGate(r.gate, r.reason)
-					}
+				fmt.Fprintf(out, "%s %s: would escalate - %s\n",
+					ui.RenderWarn("⚠"), gate.ID, reason)
+				continue
+			}
+			labels, _ := s.GetLabels(ctx, gate.ID)
+			if slices.Contains(labels, gateEscalatedLabel) {
+				result.AlreadyEscalated = true
+				if !opts.Quiet {
+					fmt.Fprintf(out, "%s %s: already escalated - %s\n",
+						ui.RenderWarn("⚠"), gate.ID, reason)
+				}
+				continue
+			}
+			if !opts.Escalate && reportedBefore(result.Status) {
+				continue
+			}
+			fmt.Fprintf(out, "%s %s: ESCALATE - %s\n",
+				ui.RenderWarn("⚠"), gate.ID, reason)
+			// Actually escalate if flag is set
+			if opts.Escalate {
+				followup, escErr := escalateGate(ctx, s, gate, reason, opts.CreateFollowup)
+				result.Followup = followup
+				if escErr != nil {
+					summary.Errors++
+					fmt.Fprintf(os.Stderr, "%s %s: error escalating - %v\n",
+						ui.RenderFail("✗"), gate.ID, escErr)
+				} else if followup != "" {
+					fmt.Fprintf(out, "  Created follow-up gate %s\n", followup)
 				}
-			} else {
-				// Still pending
-				fmt.Printf("%s %s: pending - %s\n",
-					ui.RenderAccent("○"), r.gate.ID, r.reason)
 			}
-		}
-
-		// Summary
-		fmt.Println()
-		fmt.Printf("Checked %d gates: %d resolved, %d escalated, %d errors\n",
-			len(results), resolvedCount, escalatedCount, errorCount)
 
-		if jsonOutput {
-			summary := map[string]interface{}{
-				"checked":   len(results),
-				"resolved":  resolvedCount,
-				"escalated": escalatedCount,
-				"errors":    errorCount,
-				"dry_run":   dryRun,
+		default:
+			// Still pending
+			result.Status = "pending"
+			if !opts.Quiet {
+				fmt.Fprintf(out, "%s %s: pending - %s\n",
+					ui.RenderAccent("○"), gate.ID, reason)
 			}
-			outputJSON(summary)
 		}
-	},
+	}
+
+	return summary, nil
 }
 
 // shouldCheckGate returns true if the gate matches the type filter
@@ -546,11 +671,15 @@ func isNumericID(s string) bool {
 	return true
 }
 
-// queryGitHubRunsForWorkflow queries recent runs for a specific workflow using gh CLI.
+// queryGitHubRunsForWorkflow queries recent runs for a specific workflow using
+// the GitHub API, or the gh CLI when the API isn't configured.
 // Returns runs sorted newest-first (GitHub API default).
 func queryGitHubRunsForWorkflow(workflow string, limit int) ([]GHWorkflowRun, error) {
+	if client := getGateGitHubClient(); client != nil {
+		return listGitHubRunsAPI(client, "", workflow, limit)
+	}
 	if _, err := exec.LookPath("gh"); err != nil {
-		return nil, fmt.Errorf("gh CLI not found: install from https://cli.github.com")
+		return nil, fmt.Errorf("gh CLI not found and no GitHub token configured: install from https://cli.github.com or set github.token")
 	}
 
 	args := []string{
@@ -618,28 +747,12 @@ func checkGHRun(gate *types.Issue) (resolved, escalated bool, reason string, err
 		runID = discoveredID
 	}
 
-	// Run: gh run view <id> --json status,conclusion,name
-	cmd := exec.Command("gh", "run", "view", runID, "--json", "status,conclusion,name") // #nosec G204 -- runID is a validated GitHub run ID
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if runErr := cmd.Run(); runErr != nil {
-		// Check if gh CLI is not found
-		if strings.Contains(stderr.String(), "command not found") ||
-			strings.Contains(runErr.Error(), "executable file not found") {
-			return false, false, "", fmt.Errorf("gh CLI not installed")
-		}
-		// Check if run not found
-		if strings.Contains(stderr.String(), "not found") {
-			return false, true, "workflow run not found", nil
-		}
-		return false, false, "", fmt.Errorf("gh run view failed: %s", stderr.String())
+	status, found, err := fetchGHRunStatus(runID)
+	if err != nil {
+		return false, false, "", err
 	}
-
-	var status ghRunStatus
-	if parseErr := json.Unmarshal(stdout.Bytes(), &status); parseErr != nil {
-		return false, false, "", fmt.Errorf("failed to parse gh output: %w", parseErr)
+	if !found {
+		return false, true, "workflow run not found", nil
 	}
 
 	// Evaluate status
@@ -670,28 +783,12 @@ func checkGHPR(gate *types.Issue) (resolved, escalated bool, reason string, err
 		return false, false, "no PR number specified", nil
 	}
 
-	// Run: gh pr view <id> --json state,merged,title
-	cmd := exec.Command("gh", "pr", "view", gate.AwaitID, "--json", "state,merged,title") // #nosec G204 -- gate.AwaitID is a validated GitHub PR number
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if runErr := cmd.Run(); runErr != nil {
-		// Check if gh CLI is not found
-		if strings.Contains(stderr.String(), "command not found") ||
-			strings.Contains(runErr.Error(), "executable file not found") {
-			return false, false, "", fmt.Errorf("gh CLI not installed")
-		}
-		// Check if PR not found
-		if strings.Contains(stderr.String(), "not found") || strings.Contains(stderr.String(), "Could not resolve") {
-			return false, true, "pull request not found", nil
-		}
-		return false, false, "", fmt.Errorf("gh pr view failed: %s", stderr.String())
+	status, found, err := fetchGHPRStatus(gate.AwaitID)
+	if err != nil {
+		return false, false, "", err
 	}
-
-	var status ghPRStatus
-	if parseErr := json.Unmarshal(stdout.Bytes(), &status); parseErr != nil {
-		return false, false, "", fmt.Errorf("failed to parse gh output: %w", parseErr)
+	if !found {
+		return false, true, "pull request not found", nil
 	}
 
 	// Evaluate status
@@ -710,6 +807,102 @@ func checkGHPR(gate *types.Issue) (resolved, escalated bool, reason string, err
 	}
 }
 
+// fetchGHRunStatus looks up a workflow run through the GitHub API when it
+// is configured, and through the gh CLI otherwise. found is false if the run
+// doesn't exist.
+func fetchGHRunStatus(runID string) (status *ghRunStatus, found bool, err error) {
+	if client := getGateGitHubClient(); client != nil {
+		id, parseErr := strconv.ParseInt(runID, 10, 64)
+		if parseErr != nil {
+			return nil, false, fmt.Errorf("invalid run ID %q", runID)
+		}
+		run, apiErr := client.GetWorkflowRun(gateGitHubContext(), id)
+		if github.IsNotFound(apiErr) {
+			return nil, false, nil
+		}
+		if apiErr != nil {
+			return nil, false, apiErr
+		}
+		return &ghRunStatus{Status: run.Status, Conclusion: run.Conclusion, Name: run.Name}, true, nil
+	}
+
+	// Run: gh run view <id> --json status,conclusion,name
+	cmd := exec.Command("gh", "run", "view", runID, "--json", "status,conclusion,name") // #nosec G204 -- runID is a validated GitHub run ID
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if runErr := cmd.Run(); runErr != nil {
+		// Check if gh CLI is not found
+		if strings.Contains(stderr.String(), "command not found") ||
+			strings.Contains(runErr.Error(), "executable file not found") {
+			return nil, false, fmt.Errorf("gh CLI not installed and no GitHub token configured (set github.token or GITHUB_TOKEN)")
+		}
+		// Check if run not found
+		if strings.Contains(stderr.String(), "not found") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("gh run view failed: %s", stderr.String())
+	}
+
+	status = &ghRunStatus{}
+	if parseErr := json.Unmarshal(stdout.Bytes(), status); parseErr != nil {
+		return nil, false, fmt.Errorf("failed to parse gh output: %w", parseErr)
+	}
+	return status, true, nil
+}
+
+// fetchGHPRStatus looks up a pull request through the GitHub API when it is
+// configured and prRef is a number or PR URL, and through the gh CLI
+// otherwise. found is false if the pull request doesn't exist.
+func fetchGHPRStatus(prRef string) (status *ghPRStatus, found bool, err error) {
+	client := getGateGitHubClient()
+	if repo, number, ok := parsePRRef(prRef); client != nil && ok {
+		// A PR URL may point at a different repository than github.repo
+		if repo != "" && !strings.EqualFold(repo, client.Repo) {
+			client = client.WithRepo(repo)
+		}
+		pr, apiErr := client.GetPullRequest(gateGitHubContext(), number)
+		if github.IsNotFound(apiErr) {
+			return nil, false, nil
+		}
+		if apiErr != nil {
+			return nil, false, apiErr
+		}
+		// Match gh's state names: MERGED, CLOSED or OPEN
+		state := strings.ToUpper(pr.State)
+		if pr.Merged {
+			state = "MERGED"
+		}
+		return &ghPRStatus{State: state, Merged: pr.Merged, Title: pr.Title}, true, nil
+	}
+
+	// Run: gh pr view <id> --json state,merged,title
+	cmd := exec.Command("gh", "pr", "view", prRef, "--json", "state,merged,title") // #nosec G204 -- prRef is a validated GitHub PR number
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if runErr := cmd.Run(); runErr != nil {
+		// Check if gh CLI is not found
+		if strings.Contains(stderr.String(), "command not found") ||
+			strings.Contains(runErr.Error(), "executable file not found") {
+			return nil, false, fmt.Errorf("gh CLI not installed and no GitHub token configured (set github.token or GITHUB_TOKEN)")
+		}
+		// Check if PR not found
+		if strings.Contains(stderr.String(), "not found") || strings.Contains(stderr.String(), "Could not resolve") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("gh pr view failed: %s", stderr.String())
+	}
+
+	status = &ghPRStatus{}
+	if parseErr := json.Unmarshal(stdout.Bytes(), status); parseErr != nil {
+		return nil, false, fmt.Errorf("failed to parse gh output: %w", parseErr)
+	}
+	return status, true, nil
+}
+
 // checkTimer checks a timer gate for expiration
 // Note: timers resolve but never escalate (escalated is always false by design)
 func checkTimer(gate *types.Issue, now time.Time) (resolved, escalated bool, reason string, err error) { //nolint:unparam // escalated intentionally always false
@@ -727,6 +920,59 @@ func checkTimer(gate *types.Issue, now time.Time) (resolved, escalated bool, rea
 	return false, false, fmt.Sprintf("expires in %s", remaining), nil
 }
 
+// checkMailGate checks a mail gate for a reply from one of its waiters.
+// A reply is any bead with a replies-to edge to await_id (the message the
+// gate is waiting on) or, when await_id is empty, to the gate itself. The
+// reply's sender (or creator, for beads without a sender) must be a waiter.
+func checkMailGate(ctx context.Context, s storage.Storage, gate *types.Issue) (resolved bool, reason string, err error) {
+	target := gate.AwaitID
+	if target == "" {
+		target = gate.ID
+	}
+	if len(gate.Waiters) == 0 {
+		return false, fmt.Sprintf("no waiters to reply to %s", target), nil
+	}
+
+	deps, err := s.GetDependentsWithMetadata(ctx, target)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get replies to %s: %w", target, err)
+	}
+
+	ignored := 0
+	for _, dep := range deps {
+		if dep.DependencyType != types.DepRepliesTo {
+			continue
+		}
+		from := dep.Issue.Sender
+		if from == "" {
+			from = dep.Issue.CreatedBy
+		}
+		if slices.Contains(gate.Waiters, from) {
+			return true, fmt.Sprintf("%s replied in %s", from, dep.Issue.ID), nil
+		}
+		ignored++
+	}
+
+	reason = fmt.Sprintf("waiting for a reply to %s from %s", target, strings.Join(gate.Waiters, ", "))
+	if ignored > 0 {
+		reason += fmt.Sprintf(" (%d other replies)", ignored)
+	}
+	return false, reason, nil
+}
+
+// checkGateTimeout reports whether a gate is still open past its timeout.
+// Timer gates are excluded: for them the timeout is the condition itself.
+func checkGateTimeout(gate *types.Issue, now time.Time) (overdue bool, reason string) {
+	if gate.Timeout == 0 || gate.AwaitType == "timer" {
+		return false, ""
+	}
+	deadline := gate.CreatedAt.Add(gate.Timeout)
+	if !now.After(deadline) {
+		return false, ""
+	}
+	return true, fmt.Sprintf("timed out %s ago", now.Sub(deadline).Round(time.Second))
+}
+
 // checkBeadGate checks if a cross-rig bead gate is satisfied.
 // await_id format: <rig>:<bead-id> (e.g., "gastown:gt-abc123")
 // Returns (satisfied, reason).
@@ -789,16 +1035,10 @@ func checkBeadGate(ctx context.Context, awaitID string) (bool, string) {
 	return false, fmt.Sprintf("target bead %s status is %q (waiting for closed)", beadID, status)
 }
 
-// closeGate closes a gate issue with the given reason
-func closeGate(_ interface{}, gateID, reason string) error {
-	if err := store.CloseIssue(rootCtx, gateID, reason, actor, ""); err != nil {
-		return err
-	}
-	return nil
-}
-
-// escalateGate sends an escalation for a failed/expired gate
-func escalateGate(gate *types.Issue, reason string) {
+// escalateGate escalates a failed or timed-out gate: it labels the gate
+// gate:escalated, notifies its waiters and, if createFollowup is set, creates
+// a human gate to track the escalation. Returns the follow-up gate's ID.
+func escalateGate(ctx context.Context, s storage.Storage, gate *types.Issue, reason string, createFollowup bool) (string, error) {
 	topic := fmt.Sprintf("Gate escalation: %s", gate.ID)
 	message := fmt.Sprintf("Gate %s needs attention.\nType: %s\nReason: %s\nCreated: %s",
 		gate.ID,
@@ -806,12 +1046,67 @@ func escalateGate(gate *types.Issue, reason string) {
 		reason,
 		gate.CreatedAt.Format(time.RFC3339))
 
+	if err := s.AddLabel(ctx, gate.ID, gateEscalatedLabel, actor); err != nil {
+		return "", fmt.Errorf("failed to label gate: %w", err)
+	}
+
+	notifyGateWaiters(gate, topic, message)
+
 	// Call gt escalate if available
-	escalateCmd := exec.Command("gt", "escalate", topic, "-s", "HIGH", "-m", message)
-	escalateCmd.Stdout = os.Stdout
-	escalateCmd.Stderr = os.Stderr
-	if err := escalateCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: escalation failed for %s: %v\n", gate.ID, err)
+	if _, err := exec.LookPath("gt"); err == nil {
+		escalateCmd := exec.Command("gt", "escalate", topic, "-s", "HIGH", "-m", message)
+		escalateCmd.Stdout = os.Stdout
+		escalateCmd.Stderr = os.Stderr
+		if err := escalateCmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: escalation failed for %s: %v\n", gate.ID, err)
+		}
+	}
+
+	if !createFollowup {
+		return "", nil
+	}
+	followup := &types.Issue{
+		Title:       fmt.Sprintf("Follow up on gate %s: %s", gate.ID, gate.Title),
+		Description: message,
+		Status:      types.StatusOpen,
+		Priority:    gate.Priority,
+		IssueType:   "gate",
+		AwaitType:   "human",
+		Waiters:     gate.Waiters,
+	}
+	if err := s.CreateIssue(ctx, followup, actor); err != nil {
+		return "", fmt.Errorf("failed to create follow-up: %w", err)
+	}
+	dep := &types.Dependency{
+		IssueID:     followup.ID,
+		DependsOnID: gate.ID,
+		Type:        types.DepDiscoveredFrom,
+	}
+	if err := s.AddDependency(ctx, dep, actor); err != nil {
+		return followup.ID, fmt.Errorf("failed to link follow-up %s: %w", followup.ID, err)
+	}
+	return followup.ID, nil
+}
+
+// notifyGateWaiters mails each of a gate's waiters through the configured
+// mail delegate (see 'fbd mail'). Failures are reported as warnings.
+func notifyGateWaiters(gate *types.Issue, subject, message string) {
+	if len(gate.Waiters) == 0 {
+		return
+	}
+	parts := strings.Fields(findMailDelegate())
+	if len(parts) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: no mail delegate configured; waiters on %s were not notified\n", gate.ID)
+		return
+	}
+	for _, waiter := range gate.Waiters {
+		args := append(parts[1:len(parts):len(parts)], "send", waiter, "-s", subject, "-m", message)
+		// #nosec G204 - the delegate comes from user configuration (mail.delegate)
+		mailCmd := exec.Command(parts[0], args...)
+		if output, err := mailCmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to notify %s about %s: %v %s\n",
+				waiter, gate.ID, err, strings.TrimSpace(string(output)))
+		}
 	}
 }
 
@@ -824,9 +1119,10 @@ func init() {
 	gateResolveCmd.Flags().StringP("reason", "r", "", "Reason for resolving the gate")
 
 	// gate check flags
-	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, bead, all)")
+	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, mail, bead, human, all)")
 	gateCheckCmd.Flags().Bool("dry-run", false, "Show what would happen without making changes")
 	gateCheckCmd.Flags().BoolP("escalate", "e", false, "Escalate failed/expired gates")
+	gateCheckCmd.Flags().Bool("create-followup", false, "With --escalate, create a human follow-up gate for each escalation")
 	gateCheckCmd.Flags().IntP("limit", "l", 100, "Limit results (default 100)")
 
 	// Issue ID completions
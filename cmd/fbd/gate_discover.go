@@ -18,6 +18,7 @@ import (
 )
 
 // GHWorkflowRun represents a GitHub workflow run from `gh run list --json`
+// (or the GitHub API, converted by ghRunFromAPI)
 type GHWorkflowRun struct {
 	DatabaseID   int64     `json:"databaseId"`
 	DisplayTitle string    `json:"displayTitle"`
@@ -47,6 +48,9 @@ queries recent GitHub workflow runs, and matches them using heuristics:
 Once matched, the gate's await_id is updated with the GitHub run ID, enabling
 subsequent polling to check the run's status.
 
+Runs are queried through the GitHub API (see 'fbd gate check --help' for
+configuration), or through the gh CLI when no token is configured.
+
 Examples:
   fbd gate discover           # Auto-discover run IDs for all matching gates
   fbd gate discover --dry-run # Preview what would be matched (no updates)
@@ -281,11 +285,16 @@ func getGitCommitForGateDiscovery() string {
 	return strings.TrimSpace(string(output))
 }
 
-// queryGitHubRuns queries recent workflow runs from GitHub using gh CLI
+// queryGitHubRuns queries recent workflow runs from GitHub using the API, or
+// the gh CLI when the API isn't configured
 func queryGitHubRuns(branch string, limit int) ([]GHWorkflowRun, error) {
+	if client := getGateGitHubClient(); client != nil {
+		return listGitHubRunsAPI(client, branch, "", limit)
+	}
+
 	// Check if gh CLI is available
 	if _, err := exec.LookPath("gh"); err != nil {
-		return nil, fmt.Errorf("gh CLI not found: install from https://cli.github.com")
+		return nil, fmt.Errorf("gh CLI not found and no GitHub token configured: install from https://cli.github.com or set github.token")
 	}
 
 	// Build gh run list command with JSON output
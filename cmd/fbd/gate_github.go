@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/beads"
+	"github.com/steveyegge/fastbeads/internal/github"
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
+)
+
+// GitHubConfig holds GitHub API connection settings.
+type GitHubConfig struct {
+	Token string // API token
+	URL   string // API base URL; empty means github.com
+	Repo  string // Repository as "owner/name"
+}
+
+// getGitHubConfig reads GitHub settings from the database config or the
+// environment. Without github.repo, the repository is taken from the git
+// remote "origin".
+func getGitHubConfig() GitHubConfig {
+	ctx := context.Background()
+	config := GitHubConfig{
+		Token: getGitHubConfigValue(ctx, "github.token"),
+		URL:   getGitHubConfigValue(ctx, "github.url"),
+		Repo:  getGitHubConfigValue(ctx, "github.repo"),
+	}
+	if config.Repo == "" {
+		config.Repo = getGitHubRepoFromRemote()
+	}
+	return config
+}
+
+// getGitHubConfigValue reads a GitHub configuration value from store or environment.
+func getGitHubConfigValue(ctx context.Context, key string) string {
+	// Try to read from store (works in direct mode)
+	if store != nil {
+		value, _ := store.GetConfig(ctx, key)
+		if value != "" {
+			return value
+		}
+	} else if dbPath != "" {
+		tempStore, err := sqlite.NewWithTimeout(ctx, dbPath, 5*time.Second)
+		if err == nil {
+			defer func() { _ = tempStore.Close() }()
+			value, _ := tempStore.GetConfig(ctx, key)
+			if value != "" {
+				return value
+			}
+		}
+	}
+
+	// Fall back to environment variables
+	for _, envKey := range githubConfigToEnvVars(key) {
+		if value := os.Getenv(envKey); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// githubConfigToEnvVars maps GitHub config keys to their environment variable
+// names, in priority order. The GITHUB_* names match those set on Actions runners.
+func githubConfigToEnvVars(key string) []string {
+	switch key {
+	case "github.token":
+		return []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	case "github.url":
+		return []string{"GITHUB_API_URL"}
+	case "github.repo":
+		return []string{"GITHUB_REPOSITORY"}
+	default:
+		return nil
+	}
+}
+
+// getGitHubRepoFromRemote returns "owner/name" from the origin remote of the
+// current repository, or "" if it can't be determined.
+func getGitHubRepoFromRemote() string {
+	rc, err := beads.GetRepoContext()
+	if err != nil {
+		return ""
+	}
+	output, err := rc.GitCmdCWD(context.Background(), "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	repo, _ := github.RepoFromRemoteURL(string(output))
+	return repo
+}
+
+// gateGitHubClient is the API client for GitHub gates, created on first use.
+// It stays nil when no token or repository is configured, in which case
+// gates fall back to the gh CLI.
+var (
+	gateGitHubClientOnce sync.Once
+	gateGitHubClient     *github.Client
+)
+
+// getGateGitHubClient returns the GitHub API client for gate evaluation, or
+// nil if the API isn't configured.
+func getGateGitHubClient() *github.Client {
+	gateGitHubClientOnce.Do(func() {
+		config := getGitHubConfig()
+		if config.Token != "" && config.Repo != "" {
+			gateGitHubClient = github.NewClient(config.Token, config.URL, config.Repo)
+		}
+	})
+	return gateGitHubClient
+}
+
+// gateGitHubContext returns the context for GitHub API calls made while
+// evaluating gates.
+func gateGitHubContext() context.Context {
+	if rootCtx != nil {
+		return rootCtx
+	}
+	return context.Background()
+}
+
+// ghRunFromAPI converts an API workflow run to the gh CLI's JSON shape.
+// Name holds the workflow file name so workflow hints like "release.yml" match.
+func ghRunFromAPI(run github.WorkflowRun) GHWorkflowRun {
+	name := run.Name
+	if run.Path != "" {
+		name = path.Base(run.Path)
+	}
+	return GHWorkflowRun{
+		DatabaseID:   run.ID,
+		DisplayTitle: run.DisplayTitle,
+		HeadBranch:   run.HeadBranch,
+		HeadSha:      run.HeadSHA,
+		Name:         name,
+		Status:       run.Status,
+		Conclusion:   run.Conclusion,
+		CreatedAt:    run.CreatedAt,
+		UpdatedAt:    run.UpdatedAt,
+		WorkflowName: run.Name,
+		URL:          run.HTMLURL,
+	}
+}
+
+// listGitHubRunsAPI lists recent workflow runs through the API. A workflow
+// hint that is a file name or numeric ID is filtered server-side; any other
+// hint is matched against workflow names.
+func listGitHubRunsAPI(client *github.Client, branch, workflowHint string, limit int) ([]GHWorkflowRun, error) {
+	opts := github.RunListOptions{Branch: branch, Limit: limit}
+	byName := false
+	if workflowHint != "" {
+		if isNumericID(workflowHint) || strings.HasSuffix(workflowHint, ".yml") || strings.HasSuffix(workflowHint, ".yaml") {
+			opts.Workflow = workflowHint
+		} else {
+			byName = true
+			opts.Limit = github.MaxPageSize
+		}
+	}
+
+	apiRuns, err := client.ListWorkflowRuns(gateGitHubContext(), opts)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]GHWorkflowRun, 0, len(apiRuns))
+	for _, apiRun := range apiRuns {
+		run := ghRunFromAPI(apiRun)
+		if byName && !workflowNameMatches(workflowHint, run.WorkflowName, run.Name) {
+			continue
+		}
+		runs = append(runs, run)
+		if limit > 0 && len(runs) == limit {
+			break
+		}
+	}
+	return runs, nil
+}
+
+// parsePRRef extracts a pull request number from a gh:pr await_id, which
+// may be a bare number or a pull request URL. For URLs it also returns the
+// repository ("owner/name") the pull request belongs to; bare numbers refer
+// to the configured repository and return an empty repo.
+func parsePRRef(awaitID string) (repo string, number int, ok bool) {
+	ref := strings.TrimSuffix(awaitID, "/")
+	if i := strings.LastIndex(ref, "/pull/"); i >= 0 {
+		parts := strings.Split(ref[:i], "/")
+		if len(parts) >= 2 {
+			repo = parts[len(parts)-2] + "/" + parts[len(parts)-1]
+		}
+		ref = ref[i+len("/pull/"):]
+	}
+	ref = strings.TrimPrefix(ref, "#")
+	n, err := strconv.Atoi(ref)
+	if err != nil || n <= 0 {
+		return "", 0, false
+	}
+	return repo, n, true
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/github"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// useGateGitHubAPI points gate evaluation at a fake GitHub API serving
+// the given paths.
+func useGateGitHubAPI(t *testing.T, responses map[string]interface{}) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	oldRootCtx := rootCtx
+	rootCtx = context.Background()
+	gateGitHubClientOnce = sync.Once{}
+	gateGitHubClientOnce.Do(func() {
+		gateGitHubClient = github.NewClient("test-token", server.URL, "acme/widgets")
+	})
+	t.Cleanup(func() {
+		rootCtx = oldRootCtx
+		gateGitHubClientOnce = sync.Once{}
+		gateGitHubClient = nil
+	})
+}
+
+func TestCheckGHRun_API(t *testing.T) {
+	useGateGitHubAPI(t, map[string]interface{}{
+		"/repos/acme/widgets/actions/runs/101": map[string]string{"name": "CI", "status": "completed", "conclusion": "success"},
+		"/repos/acme/widgets/actions/runs/102": map[string]string{"name": "CI", "status": "completed", "conclusion": "failure"},
+		"/repos/acme/widgets/actions/runs/103": map[string]string{"name": "CI", "status": "in_progress"},
+	})
+
+	tests := []struct {
+		awaitID             string
+		resolved, escalated bool
+		reason              string
+	}{
+		{"101", true, false, "succeeded"},
+		{"102", false, true, "failed"},
+		{"103", false, false, "in_progress"},
+		{"104", false, true, "not found"},
+	}
+	for _, tt := range tests {
+		gate := &types.Issue{ID: "gate-" + tt.awaitID, AwaitType: "gh:run", AwaitID: tt.awaitID}
+		resolved, escalated, reason, err := checkGHRun(gate)
+		if err != nil || resolved != tt.resolved || escalated != tt.escalated || !strings.Contains(reason, tt.reason) {
+			t.Errorf("checkGHRun(%s) = (%v, %v, %q, %v)", tt.awaitID, resolved, escalated, reason, err)
+		}
+	}
+}
+
+func TestCheckGHPR_API(t *testing.T) {
+	useGateGitHubAPI(t, map[string]interface{}{
+		"/repos/acme/widgets/pulls/7": map[string]interface{}{"title": "Add gates", "state": "closed", "merged": true},
+		"/repos/acme/widgets/pulls/8": map[string]interface{}{"title": "Drop gates", "state": "closed", "merged": false},
+		"/repos/acme/widgets/pulls/9": map[string]interface{}{"title": "WIP", "state": "open"},
+		"/repos/other/lib/pulls/7":    map[string]interface{}{"title": "Upstream fix", "state": "open"},
+	})
+
+	tests := []struct {
+		awaitID             string
+		resolved, escalated bool
+	}{
+		{"7", true, false},
+		{"https://github.com/acme/widgets/pull/7", true, false},
+		{"https://github.com/Acme/Widgets/pull/7", true, false},
+		// Cross-repo URLs must query the PR's own repository
+		{"https://github.com/other/lib/pull/7", false, false},
+		{"8", false, true},
+		{"9", false, false},
+	}
+	for _, tt := range tests {
+		gate := &types.Issue{ID: "gate", AwaitType: "gh:pr", AwaitID: tt.awaitID}
+		resolved, escalated, reason, err := checkGHPR(gate)
+		if err != nil || resolved != tt.resolved || escalated != tt.escalated {
+			t.Errorf("checkGHPR(%s) = (%v, %v, %q, %v)", tt.awaitID, resolved, escalated, reason, err)
+		}
+	}
+}
+
+func TestQueryGitHubRunsForWorkflow_API(t *testing.T) {
+	runs := map[string]interface{}{
+		"workflow_runs": []map[string]interface{}{
+			{"id": 3, "name": "Lint", "path": ".github/workflows/lint.yml"},
+			{"id": 2, "name": "Release", "path": ".github/workflows/release.yml"},
+			{"id": 1, "name": "Release", "path": ".github/workflows/release.yml"},
+		},
+	}
+	useGateGitHubAPI(t, map[string]interface{}{
+		"/repos/acme/widgets/actions/runs":                       runs,
+		"/repos/acme/widgets/actions/workflows/release.yml/runs": runs,
+	})
+
+	// A display-name hint is matched client-side
+	got, err := queryGitHubRunsForWorkflow("Release", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].DatabaseID != 2 || got[0].Name != "release.yml" {
+		t.Errorf("by name = %+v", got)
+	}
+
+	// A file name hint is filtered by the API
+	got, err = queryGitHubRunsForWorkflow("release.yml", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].DatabaseID != 3 {
+		t.Errorf("by file = %+v", got)
+	}
+}
+
+func TestParsePRRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		repo string
+		want int
+		ok   bool
+	}{
+		{"42", "", 42, true},
+		{"#42", "", 42, true},
+		{"https://github.com/acme/widgets/pull/42", "acme/widgets", 42, true},
+		{"https://github.com/acme/widgets/pull/42/", "acme/widgets", 42, true},
+		{"https://ghe.example.com/other/lib/pull/5", "other/lib", 5, true},
+		{"feature-branch", "", 0, false},
+		{"0", "", 0, false},
+	}
+	for _, tt := range tests {
+		repo, got, ok := parsePRRef(tt.ref)
+		if repo != tt.repo || got != tt.want || ok != tt.ok {
+			t.Errorf("parsePRRef(%q) = (%q, %d, %v), want (%q, %d, %v)", tt.ref, repo, got, ok, tt.repo, tt.want, tt.ok)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"database/sql"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -49,6 +52,8 @@ func TestShouldCheckGate(t *testing.T) {
 		{"timer filter does not match gh:run", "gh:run", "timer", false},
 		{"bead filter matches bead", "bead", "bead", true},
 		{"bead filter does not match timer", "timer", "bead", false},
+		{"mail filter matches mail", "mail", "mail", true},
+		{"mail filter does not match human", "human", "mail", false},
 	}
 
 	for _, tt := range tests {
@@ -199,6 +204,172 @@ func TestCheckBeadGate_TargetClosed(t *testing.T) {
 	t.Log("Full integration testing requires routes.jsonl setup")
 }
 
+func TestCheckGateTimeout(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-2 * time.Hour)
+	tests := []struct {
+		name string
+		gate *types.Issue
+		want bool
+	}{
+		{"no timeout", &types.Issue{AwaitType: "mail", CreatedAt: created}, false},
+		{"within timeout", &types.Issue{AwaitType: "mail", CreatedAt: created, Timeout: 3 * time.Hour}, false},
+		{"past timeout", &types.Issue{AwaitType: "mail", CreatedAt: created, Timeout: time.Hour}, true},
+		{"human past timeout", &types.Issue{AwaitType: "human", CreatedAt: created, Timeout: time.Hour}, true},
+		{"timer never times out", &types.Issue{AwaitType: "timer", CreatedAt: created, Timeout: time.Hour}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := checkGateTimeout(tt.gate, now); got != tt.want {
+				t.Errorf("checkGateTimeout() = %v (%q), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckMailGate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	gate := &types.Issue{Title: "Await approval", Status: types.StatusOpen, Priority: 2,
+		IssueType: "gate", AwaitType: "mail", Waiters: []string{"mayor/"}}
+	if err := s.CreateIssue(ctx, gate, "test"); err != nil {
+		t.Fatal(err)
+	}
+	reply := func(sender string) {
+		t.Helper()
+		msg := &types.Issue{Title: "Re: approval", Status: types.StatusOpen, Priority: 2,
+			IssueType: "message", Sender: sender}
+		if err := s.CreateIssue(ctx, msg, "test"); err != nil {
+			t.Fatal(err)
+		}
+		dep := &types.Dependency{IssueID: msg.ID, DependsOnID: gate.ID, Type: types.DepRepliesTo}
+		if err := s.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolved, reason, err := checkMailGate(ctx, s, gate)
+	if err != nil || resolved || !strings.Contains(reason, "mayor/") {
+		t.Fatalf("no replies = (%v, %q, %v)", resolved, reason, err)
+	}
+
+	// Replies from anyone but a waiter don't count
+	reply("gastown/polecats/Toast")
+	resolved, reason, _ = checkMailGate(ctx, s, gate)
+	if resolved || !strings.Contains(reason, "1 other replies") {
+		t.Fatalf("non-waiter reply = (%v, %q)", resolved, reason)
+	}
+
+	reply("mayor/")
+	resolved, reason, _ = checkMailGate(ctx, s, gate)
+	if !resolved || !strings.Contains(reason, "mayor/ replied") {
+		t.Fatalf("waiter reply = (%v, %q)", resolved, reason)
+	}
+}
+
+func TestRunGateCheck_Escalation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := newTestStore(t, filepath.Join(dir, ".beads", "beads.db"))
+
+	// Record what the mail delegate is asked to send
+	sent := filepath.Join(dir, "sent")
+	delegate := filepath.Join(dir, "mail.sh")
+	if err := os.WriteFile(delegate, []byte("#!/bin/sh\necho \"$@\" >> "+sent+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FBD_MAIL_DELEGATE", delegate)
+
+	created := time.Now().Add(-2 * time.Hour)
+	timer := &types.Issue{Title: "Cool down", Status: types.StatusOpen, Priority: 2,
+		IssueType: "gate", AwaitType: "timer", Timeout: time.Hour, CreatedAt: created}
+	approval := &types.Issue{Title: "Sign off", Status: types.StatusOpen, Priority: 1,
+		IssueType: "gate", AwaitType: "human", Timeout: time.Hour, CreatedAt: created,
+		Waiters: []string{"mayor/"}}
+	for _, gate := range []*types.Issue{timer, approval} {
+		if err := s.CreateIssue(ctx, gate, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := gateCheckOptions{Escalate: true, CreateFollowup: true, Limit: 100}
+	summary, err := runGateCheck(ctx, s, opts, time.Now(), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Checked != 2 || summary.Resolved != 1 || summary.Escalated != 1 || summary.Errors != 0 {
+		t.Fatalf("summary = %+v", summary)
+	}
+
+	if got, _ := s.GetIssue(ctx, timer.ID); got.Status != types.StatusClosed {
+		t.Errorf("timer gate status = %s, want closed", got.Status)
+	}
+	if labels, _ := s.GetLabels(ctx, approval.ID); !slices.Contains(labels, gateEscalatedLabel) {
+		t.Errorf("escalated gate labels = %v", labels)
+	}
+	if data, _ := os.ReadFile(sent); !strings.Contains(string(data), "send mayor/ -s Gate escalation: "+approval.ID) {
+		t.Errorf("notifications = %q", data)
+	}
+
+	var followupID string
+	for _, g := range summary.Gates {
+		if g.ID == approval.ID {
+			followupID = g.Followup
+		}
+	}
+	followup, err := s.GetIssue(ctx, followupID)
+	if err != nil || followup == nil {
+		t.Fatalf("follow-up %q: %v", followupID, err)
+	}
+	if followup.AwaitType != "human" || !strings.Contains(followup.Title, approval.ID) {
+		t.Errorf("follow-up = %+v", followup)
+	}
+
+	// A second pass doesn't escalate the same gate again
+	summary, err = runGateCheck(ctx, s, opts, time.Now(), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range summary.Gates {
+		if g.ID == approval.ID && (!g.AlreadyEscalated || g.Followup != "") {
+			t.Errorf("second pass = %+v", g)
+		}
+	}
+	if data, _ := os.ReadFile(sent); strings.Count(string(data), "send ") != 1 {
+		t.Errorf("notifications after second pass = %q", data)
+	}
+}
+
+func TestWatchGates_ReportsEscalationOnce(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	approval := &types.Issue{Title: "Sign off", Status: types.StatusOpen, Priority: 1,
+		IssueType: "gate", AwaitType: "human", Timeout: time.Hour, CreatedAt: time.Now().Add(-2 * time.Hour)}
+	if err := s.CreateIssue(ctx, approval, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []gateCheckOptions{{Limit: 100, Quiet: true}, {Escalate: true, DryRun: true, Limit: 100, Quiet: true}} {
+		watchCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		var out strings.Builder
+		err := watchGates(watchCtx, s, opts, 20*time.Millisecond, &out)
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("watchGates: %v", err)
+		}
+		if n := strings.Count(out.String(), approval.ID); n != 1 {
+			t.Errorf("with %+v the overdue gate was reported %d times:\n%s", opts, n, out.String())
+		}
+	}
+
+	summary := &GateCheckSummary{Gates: []*GateCheckResult{{ID: approval.ID, Status: "escalated", AlreadyReported: true}}}
+	if gateWatchChanged(summary) {
+		t.Error("a gate reported by an earlier pass counted as a change")
+	}
+}
+
 func TestIsNumericID(t *testing.T) {
 	tests := []struct {
 		input string
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+var gateWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Evaluate gates continuously until interrupted",
+	Long: `Run 'fbd gate check' every --interval until interrupted.
+
+Each pass closes resolved gates and reports escalations exactly like
+'fbd gate check' with the same flags. Pending gates are not reported,
+gates already labeled gate:escalated are not escalated again, and a gate
+is only reported again when its outcome changes, so a long-running watch
+only prints when something changes.
+
+With --json, each pass that changed something is printed as one JSON
+summary line.
+
+Examples:
+  fbd gate watch
+  fbd gate watch --interval 30s --type mail
+  fbd gate watch --escalate --create-followup
+  fbd gate watch --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("gate watch")
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			FatalError("--interval must be positive")
+		}
+		opts := gateCheckOptionsFromFlags(cmd)
+		opts.Quiet = true
+
+		if !jsonOutput {
+			fmt.Printf("%s Watching gates every %s (Ctrl+C to stop)\n",
+				ui.RenderAccent("⏳"), interval)
+		}
+		err := watchGates(rootCtx, store, opts, interval, os.Stdout)
+		if err != nil && rootCtx.Err() == nil {
+			FatalError("%v", err)
+		}
+	},
+}
+
+// watchGates runs gate checks every interval until ctx is cancelled.
+func watchGates(ctx context.Context, s storage.Storage, opts gateCheckOptions, interval time.Duration, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	opts.Reported = make(map[string]string)
+	checkOut := out
+	if jsonOutput {
+		checkOut = io.Discard
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		summary, err := runGateCheck(ctx, s, opts, time.Now(), checkOut)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to check gates: %w", err)
+		}
+		if jsonOutput && gateWatchChanged(summary) {
+			_ = encoder.Encode(summary)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// gateWatchChanged reports whether a pass resolved, escalated or failed on
+// any gate. Gates that were already escalated or reported don't count.
+func gateWatchChanged(summary *GateCheckSummary) bool {
+	for _, g := range summary.Gates {
+		if g.AlreadyReported {
+			continue
+		}
+		if g.Status == "resolved" || g.Status == "error" || (g.Status == "escalated" && !g.AlreadyEscalated) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	gateWatchCmd.Flags().Duration("interval", time.Minute, "How often to evaluate gates")
+	gateWatchCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, mail, bead, human, all)")
+	gateWatchCmd.Flags().Bool("dry-run", false, "Show what would happen without making changes")
+	gateWatchCmd.Flags().BoolP("escalate", "e", false, "Escalate failed/expired gates")
+	gateWatchCmd.Flags().Bool("create-followup", false, "With --escalate, create a human follow-up gate for each escalation")
+	gateWatchCmd.Flags().IntP("limit", "l", 100, "Limit gates per pass (default 100)")
+
+	gateCmd.AddCommand(gateWatchCmd)
+}
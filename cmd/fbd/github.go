@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/github"
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// githubCmd is the root command for GitHub Issues integration.
+var githubCmd = &cobra.Command{
+	Use:     "github",
+	GroupID: "advanced",
+	Short:   "GitHub Issues integration commands",
+	Long: `Synchronize issues between beads and GitHub Issues.
+
+Configuration:
+  fbd config set github.token "YOUR_TOKEN"
+  fbd config set github.repo "owner/name"      # Optional: defaults to the origin remote
+  fbd config set github.url "https://ghe.example.com/api/v3"  # Optional: GitHub Enterprise Server
+
+Environment variables (alternative to config):
+  GITHUB_TOKEN / GH_TOKEN - API token (needs issues read/write)
+  GITHUB_REPOSITORY       - Repository as owner/name
+  GITHUB_API_URL          - API base URL
+
+Data Mapping (optional, sensible defaults provided):
+  GitHub has no priority, type or workflow status, so beads reads them from
+  labels. Mapped labels are consumed; all other labels sync as-is.
+
+  Priority labels (defaults: P0-P4, critical/urgent, high, medium, low, backlog):
+    fbd config set github.priority_map.sev1 0
+
+  Type labels (defaults: bug, defect, feature, enhancement, epic, chore, task):
+    fbd config set github.label_type_map.kind/bug bug
+
+  Status labels for open issues (defaults: "in progress", wip, blocked):
+    fbd config set github.status_map.needs-info blocked
+
+  On push, beads writes P0-P4, the type name and "in progress"/"blocked".
+  The milestone syncs as a "milestone:<title>" label, and sub-issues become
+  parent-child dependencies.
+
+  ID generation (hash IDs, like Linear imports):
+    fbd config set github.hash_length "6"     # hash length 3-8 (default: 6)
+
+Examples:
+  fbd github sync --pull         # Import issues from GitHub
+  fbd github sync --push         # Export issues to GitHub
+  fbd github sync                # Bidirectional sync (pull then push)
+  fbd github sync --dry-run      # Preview sync without changes
+  fbd github status              # Show sync status`,
+}
+
+// githubSyncCmd handles synchronization with GitHub Issues.
+var githubSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize issues with GitHub",
+	Long: `Synchronize issues between beads and GitHub Issues.
+
+Modes:
+  --pull         Import issues from GitHub into beads
+  --push         Export issues from beads to GitHub
+  (no flags)     Bidirectional sync
+
+Only issues changed since the last pull are fetched. An issue that changed
+on one side since the last sync is synced in that direction; an issue that
+changed on both sides is a conflict.
+
+Type Filtering (applies to pushing):
+  --type task,feature       Only sync issues of these types
+  --exclude-type wisp       Exclude issues of these types
+  --include-ephemeral       Include ephemeral issues (wisps, etc.); default is to exclude
+
+Conflict Resolution:
+  By default, newer timestamp wins. Override with:
+  --prefer-local    Always prefer local beads version
+  --prefer-github   Always prefer GitHub version
+
+Only issues whose external_ref points at the configured repository are
+updated on push; issues linked to other trackers are left alone.
+
+Examples:
+  fbd github sync --pull                         # Import from GitHub
+  fbd github sync --pull --state open            # Import open issues only
+  fbd github sync --push --create-only           # Push new issues only
+  fbd github sync --push --type=bug,feature      # Push only bugs and features
+  fbd github sync --dry-run                      # Preview without changes
+  fbd github sync --prefer-local                 # Bidirectional, local wins`,
+	Run: runGitHubSync,
+}
+
+// githubStatusCmd shows the current sync status.
+var githubStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show GitHub sync status",
+	Long: `Show the current GitHub sync status, including:
+  - Last sync timestamp
+  - Configuration status
+  - Number of issues linked to the repository
+  - Issues pending push (no external_ref)`,
+	Run: runGitHubStatus,
+}
+
+func init() {
+	githubSyncCmd.Flags().Bool("pull", false, "Pull issues from GitHub")
+	githubSyncCmd.Flags().Bool("push", false, "Push issues to GitHub")
+	githubSyncCmd.Flags().Bool("dry-run", false, "Preview sync without making changes")
+	githubSyncCmd.Flags().Bool("prefer-local", false, "Prefer local version on conflicts")
+	githubSyncCmd.Flags().Bool("prefer-github", false, "Prefer GitHub version on conflicts")
+	githubSyncCmd.Flags().Bool("create-only", false, "Only create new issues, don't update existing")
+	githubSyncCmd.Flags().Bool("update-refs", true, "Update external_ref after creating GitHub issues")
+	githubSyncCmd.Flags().String("state", "all", "Issue state to sync: open, closed, all")
+	githubSyncCmd.Flags().StringSlice("type", nil, "Only sync issues of these types (can be repeated)")
+	githubSyncCmd.Flags().StringSlice("exclude-type", nil, "Exclude issues of these types (can be repeated)")
+	githubSyncCmd.Flags().Bool("include-ephemeral", false, "Include ephemeral issues (wisps, etc.) when pushing to GitHub")
+
+	githubCmd.AddCommand(githubSyncCmd)
+	githubCmd.AddCommand(githubStatusCmd)
+	rootCmd.AddCommand(githubCmd)
+}
+
+func runGitHubSync(cmd *cobra.Command, args []string) {
+	pull, _ := cmd.Flags().GetBool("pull")
+	push, _ := cmd.Flags().GetBool("push")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	preferLocal, _ := cmd.Flags().GetBool("prefer-local")
+	preferGitHub, _ := cmd.Flags().GetBool("prefer-github")
+	createOnly, _ := cmd.Flags().GetBool("create-only")
+	updateRefs, _ := cmd.Flags().GetBool("update-refs")
+	state, _ := cmd.Flags().GetString("state")
+	typeFilters, _ := cmd.Flags().GetStringSlice("type")
+	excludeTypes, _ := cmd.Flags().GetStringSlice("exclude-type")
+	includeEphemeral, _ := cmd.Flags().GetBool("include-ephemeral")
+
+	if !dryRun {
+		CheckReadonly("github sync")
+	}
+
+	if preferLocal && preferGitHub {
+		fmt.Fprintf(os.Stderr, "Error: cannot use both --prefer-local and --prefer-github\n")
+		os.Exit(1)
+	}
+
+	if err := ensureStoreActive(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: database not available: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := validateGitHubConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !pull && !push {
+		pull = true
+		push = true
+	}
+
+	opts := tracker.SyncOptions{
+		Pull:             pull,
+		Push:             push,
+		DryRun:           dryRun,
+		CreateOnly:       createOnly,
+		State:            state,
+		Strategy:         syncStrategy(preferLocal, preferGitHub),
+		Types:            typeFilters,
+		ExcludeTypes:     excludeTypes,
+		IncludeEphemeral: includeEphemeral,
+		NoLink:           !updateRefs,
+	}
+	if err := syncTracker("github", opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runGitHubStatus(cmd *cobra.Command, args []string) {
+	ctx := rootCtx
+
+	if err := ensureStoreActive(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	config := getGitHubConfig()
+	lastSync, _ := store.GetConfig(ctx, "github.last_sync")
+
+	configured := config.Token != "" && config.Repo != ""
+
+	allIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	withGitHubRef := 0
+	pendingPush := 0
+	for _, issue := range allIssues {
+		if issue.ExternalRef == nil {
+			pendingPush++
+		} else if _, ok := githubIssueNumber(issue, config.Repo); ok {
+			withGitHubRef++
+		}
+	}
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"configured":      configured,
+			"has_token":       config.Token != "",
+			"repo":            config.Repo,
+			"last_sync":       lastSync,
+			"total_issues":    len(allIssues),
+			"with_github_ref": withGitHubRef,
+			"pending_push":    pendingPush,
+		})
+		return
+	}
+
+	fmt.Println("GitHub Sync Status")
+	fmt.Println("==================")
+	fmt.Println()
+
+	if !configured {
+		fmt.Println("Status: Not configured")
+		fmt.Println()
+		fmt.Println("To configure GitHub integration:")
+		fmt.Println("  fbd config set github.token \"YOUR_TOKEN\"")
+		fmt.Println("  fbd config set github.repo \"owner/name\"")
+		fmt.Println()
+		fmt.Println("Or use environment variables:")
+		fmt.Println("  export GITHUB_TOKEN=\"YOUR_TOKEN\"")
+		fmt.Println("  export GITHUB_REPOSITORY=\"owner/name\"")
+		return
+	}
+
+	fmt.Printf("Repository:   %s\n", config.Repo)
+	fmt.Printf("Token:        %s\n", maskAPIKey(config.Token))
+	if config.URL != "" {
+		fmt.Printf("API URL:      %s\n", config.URL)
+	}
+	if lastSync != "" {
+		fmt.Printf("Last Sync:    %s\n", lastSync)
+	} else {
+		fmt.Println("Last Sync:    Never")
+	}
+	fmt.Println()
+	fmt.Printf("Total Issues: %d\n", len(allIssues))
+	fmt.Printf("With GitHub:  %d\n", withGitHubRef)
+	fmt.Printf("Local Only:   %d\n", pendingPush)
+
+	if pendingPush > 0 {
+		fmt.Println()
+		fmt.Printf("Run 'fbd github sync --push' to push %d local issue(s) to GitHub\n", pendingPush)
+	}
+}
+
+// validateGitHubConfig checks that required GitHub configuration is present.
+func validateGitHubConfig() error {
+	config := getGitHubConfig()
+	if config.Token == "" {
+		return fmt.Errorf("GitHub token not configured\nRun: fbd config set github.token \"YOUR_TOKEN\"\nOr: export GITHUB_TOKEN=YOUR_TOKEN")
+	}
+	if config.Repo == "" {
+		return fmt.Errorf("github.repo not configured and no GitHub origin remote found\nRun: fbd config set github.repo \"owner/name\"\nOr: export GITHUB_REPOSITORY=owner/name")
+	}
+	if strings.Count(config.Repo, "/") != 1 {
+		return fmt.Errorf("github.repo appears invalid (expected owner/name)\nCurrent value: %s", config.Repo)
+	}
+	return nil
+}
+
+// githubIssueNumber returns the issue number an issue is linked to in repo,
+// or false if its external_ref isn't an issue URL for that repository.
+func githubIssueNumber(issue *types.Issue, repo string) (int, bool) {
+	if issue.ExternalRef == nil {
+		return 0, false
+	}
+	refRepo, number, ok := github.ParseGitHubExternalRef(*issue.ExternalRef)
+	if !ok || !strings.EqualFold(refRepo, repo) {
+		return 0, false
+	}
+	return number, true
+}
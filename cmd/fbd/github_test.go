@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// fakeGitHubIssues serves a minimal GitHub issues API for acme/widgets and
+// records list queries and issue updates. failCreate makes creating an issue
+// return a server error.
+type fakeGitHubIssues struct {
+	mu         sync.Mutex
+	issues     map[int]map[string]interface{}
+	subs       map[int][]int
+	queries    []url.Values
+	created    []map[string]interface{}
+	patched    map[int]map[string]interface{}
+	failCreate bool
+}
+
+func (f *fakeGitHubIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	const prefix = "/repos/acme/widgets/issues"
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+	number, _ := strconv.Atoi(parts[0])
+	switch {
+	case r.URL.Path == prefix && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		f.queries = append(f.queries, query)
+		since, _ := time.Parse(time.RFC3339, query.Get("since"))
+		list := []map[string]interface{}{}
+		for n := 1; n <= len(f.issues); n++ {
+			updated, _ := time.Parse(time.RFC3339, f.issues[n]["updated_at"].(string))
+			if !updated.Before(since) {
+				list = append(list, f.issues[n])
+			}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.URL.Path == prefix && r.Method == http.MethodPost && f.failCreate:
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message": "Server Error"}`))
+	case r.URL.Path == prefix && r.Method == http.MethodPost:
+		var fields map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &fields)
+		f.created = append(f.created, fields)
+		n := 100 + len(f.created)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"number": n, "html_url": "https://github.com/acme/widgets/issues/" + strconv.Itoa(n),
+		})
+	case len(parts) == 2 && parts[1] == "sub_issues":
+		list := []map[string]interface{}{}
+		for _, n := range f.subs[number] {
+			list = append(list, f.issues[n])
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case len(parts) == 1 && f.issues[number] != nil:
+		if r.Method == http.MethodPatch {
+			var fields map[string]interface{}
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &fields)
+			f.patched[number] = fields
+		}
+		_ = json.NewEncoder(w).Encode(f.issues[number])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}
+}
+
+// useFakeGitHub sets up a test store configured for acme/widgets on a fake API.
+func useFakeGitHub(t *testing.T, fake *fakeGitHubIssues) context.Context {
+	t.Helper()
+	ensureCleanGlobalState(t)
+	if fake.patched == nil {
+		fake.patched = make(map[int]map[string]interface{})
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	testDBPath := filepath.Join(t.TempDir(), "test.db")
+	testStore := newTestStore(t, testDBPath)
+	ctx := context.Background()
+	for key, value := range map[string]string{
+		"github.token": "test-token",
+		"github.repo":  "acme/widgets",
+		"github.url":   server.URL,
+	} {
+		if err := testStore.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig %s: %v", key, err)
+		}
+	}
+
+	origStore, origDBPath, origActor, origCtx := store, dbPath, actor, rootCtx
+	origActive, origJSON := storeActive, jsonOutput
+	store, dbPath, actor, rootCtx = testStore, testDBPath, "test-actor", ctx
+	storeActive, jsonOutput = true, true
+	t.Cleanup(func() {
+		store, dbPath, actor, rootCtx = origStore, origDBPath, origActor, origCtx
+		storeActive, jsonOutput = origActive, origJSON
+	})
+	return ctx
+}
+
+func githubIssueJSON(number int, title, state string, labels []string, updated time.Time) map[string]interface{} {
+	labelObjs := []map[string]string{}
+	for _, l := range labels {
+		labelObjs = append(labelObjs, map[string]string{"name": l})
+	}
+	return map[string]interface{}{
+		"number":     number,
+		"title":      title,
+		"body":       title + " body",
+		"state":      state,
+		"labels":     labelObjs,
+		"html_url":   "https://github.com/acme/widgets/issues/" + strconv.Itoa(number),
+		"created_at": updated.Add(-time.Hour).Format(time.RFC3339),
+		"updated_at": updated.Format(time.RFC3339),
+	}
+}
+
+// githubSyncOptions returns a two-way sync of every issue state.
+func githubSyncOptions() tracker.SyncOptions {
+	return tracker.SyncOptions{Pull: true, Push: true, State: "all"}
+}
+
+func TestGitHubSyncPull(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	parent := githubIssueJSON(1, "Epic work", "open", []string{"epic", "P1"}, updated)
+	parent["sub_issues_summary"] = map[string]int{"total": 1}
+	parent["milestone"] = map[string]interface{}{"number": 2, "title": "v1"}
+	child := githubIssueJSON(2, "Child task", "open", []string{"in progress", "ui"}, updated)
+	child["assignee"] = map[string]string{"login": "octocat"}
+	fake := &fakeGitHubIssues{
+		issues: map[int]map[string]interface{}{1: parent, 2: child},
+		subs:   map[int][]int{1: {2}},
+	}
+	ctx := useFakeGitHub(t, fake)
+
+	opts := githubSyncOptions()
+	opts.Push = false
+	if err := syncTracker("github", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("imported %d issues, want 2", len(issues))
+	}
+	byNumber := make(map[int]*types.Issue)
+	for _, issue := range issues {
+		if n, ok := githubIssueNumber(issue, "acme/widgets"); ok {
+			byNumber[n] = issue
+		}
+	}
+	epic, task := byNumber[1], byNumber[2]
+	if epic == nil || task == nil {
+		t.Fatalf("imported issues = %v", byNumber)
+	}
+	if epic.IssueType != types.TypeEpic || epic.Priority != 1 || !slices.Contains(epic.Labels, "milestone:v1") {
+		t.Errorf("epic = %s/%d/%v", epic.IssueType, epic.Priority, epic.Labels)
+	}
+	if task.Status != types.StatusInProgress || task.Assignee != "octocat" || !slices.Equal(task.Labels, []string{"ui"}) {
+		t.Errorf("task = %s/%q/%v", task.Status, task.Assignee, task.Labels)
+	}
+
+	deps, err := store.GetDependencyRecords(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != epic.ID || deps[0].Type != types.DepParentChild {
+		t.Errorf("task deps = %+v", deps)
+	}
+
+	// A second sync updates in place rather than duplicating
+	if err := syncTracker("github", opts); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("after second sync: %d issues, want 2", len(issues))
+	}
+}
+
+func TestGitHubSyncPush(t *testing.T) {
+	remoteUpdated := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitHubIssues{
+		issues: map[int]map[string]interface{}{
+			1: githubIssueJSON(1, "Linked", "open", nil, remoteUpdated),
+		},
+	}
+	ctx := useFakeGitHub(t, fake)
+
+	newIssue := &types.Issue{Title: "Fresh bug", Priority: 0, IssueType: types.TypeBug, Status: types.StatusOpen}
+	if err := store.CreateIssue(ctx, newIssue, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	ref := "https://github.com/acme/widgets/issues/1"
+	linked := &types.Issue{Title: "Linked, edited", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &ref}
+	if err := store.CreateIssue(ctx, linked, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	otherRef := "https://github.com/other/repo/issues/1"
+	foreign := &types.Issue{Title: "Other repo", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &otherRef}
+	if err := store.CreateIssue(ctx, foreign, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncTracker("github", githubSyncOptions()); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+
+	if len(fake.created) != 1 || fake.created[0]["title"] != "Fresh bug" {
+		t.Fatalf("created = %+v", fake.created)
+	}
+	if labels := fake.created[0]["labels"]; !slices.Equal(toStrings(labels), []string{"P0", "bug"}) {
+		t.Errorf("created labels = %v", labels)
+	}
+	got, err := store.GetIssue(ctx, newIssue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExternalRef == nil || *got.ExternalRef != "https://github.com/acme/widgets/issues/101" {
+		t.Errorf("external_ref = %v", got.ExternalRef)
+	}
+
+	// The local edit is newer, so it wins the first-sync conflict
+	if fake.patched[1]["title"] != "Linked, edited" {
+		t.Errorf("patched = %+v", fake.patched)
+	}
+}
+
+func TestGitHubSyncPreferRemote(t *testing.T) {
+	remoteUpdated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitHubIssues{
+		issues: map[int]map[string]interface{}{
+			1: githubIssueJSON(1, "Edited remotely", "open", nil, remoteUpdated),
+		},
+	}
+	ctx := useFakeGitHub(t, fake)
+
+	ref := "https://github.com/acme/widgets/issues/1"
+	issue := &types.Issue{Title: "Edited locally", Description: "Edited locally body", Priority: 2,
+		IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &ref}
+	if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := githubSyncOptions()
+	opts.Strategy = syncStrategy(false, true)
+	if err := syncTracker("github", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+	if len(fake.patched) != 0 {
+		t.Errorf("patched = %+v, want remote to win", fake.patched)
+	}
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Edited remotely" {
+		t.Errorf("title = %q, want remote title", got.Title)
+	}
+}
+
+func TestGitHubSyncIncremental(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitHubIssues{
+		issues: map[int]map[string]interface{}{
+			1: githubIssueJSON(1, "First", "open", nil, updated),
+			2: githubIssueJSON(2, "Second", "open", nil, updated),
+		},
+	}
+	ctx := useFakeGitHub(t, fake)
+
+	if err := syncTracker("github", githubSyncOptions()); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if since := fake.queries[0].Get("since"); since != "" {
+		t.Errorf("first sync fetched since %s, want a full fetch", since)
+	}
+
+	later := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	fake.issues[2] = githubIssueJSON(2, "Second, renamed", "closed", nil, later)
+	if err := syncTracker("github", githubSyncOptions()); err != nil {
+		t.Fatalf("incremental sync: %v", err)
+	}
+	if since := fake.queries[len(fake.queries)-1].Get("since"); since == "" {
+		t.Error("incremental sync fetched without since")
+	}
+	got, err := store.GetIssueByExternalRef(ctx, "https://github.com/acme/widgets/issues/2")
+	if err != nil || got == nil {
+		t.Fatalf("GetIssueByExternalRef: %v", err)
+	}
+	if got.Title != "Second, renamed" || got.Status != types.StatusClosed {
+		t.Errorf("pulled %q/%s", got.Title, got.Status)
+	}
+	if len(fake.created) != 0 || len(fake.patched) != 0 {
+		t.Errorf("incremental pull wrote to GitHub: created %v, patched %v", fake.created, fake.patched)
+	}
+}
+
+func TestGitHubSyncPushError(t *testing.T) {
+	fake := &fakeGitHubIssues{issues: map[int]map[string]interface{}{}, failCreate: true}
+	ctx := useFakeGitHub(t, fake)
+	issue := &types.Issue{Title: "Unpushed", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen}
+	if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncTracker("github", githubSyncOptions()); err != nil {
+		t.Fatalf("a failed push should not fail the sync: %v", err)
+	}
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExternalRef != nil {
+		t.Errorf("issue linked to %s although its create failed", *got.ExternalRef)
+	}
+
+	fake.failCreate = false
+	if err := syncTracker("github", githubSyncOptions()); err != nil {
+		t.Fatalf("retry sync: %v", err)
+	}
+	if got, err = store.GetIssue(ctx, issue.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got.ExternalRef == nil || *got.ExternalRef != "https://github.com/acme/widgets/issues/101" {
+		t.Errorf("external_ref after retry = %v", got.ExternalRef)
+	}
+}
+
+func toStrings(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, _ := item.(string)
+		out = append(out, s)
+	}
+	return out
+}
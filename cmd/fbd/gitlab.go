@@ -11,7 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/fastbeads/internal/gitlab"
 	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
-	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/tracker"
 )
 
 // GitLabConfig holds GitLab connection configuration.
@@ -43,7 +43,12 @@ By default, performs bidirectional sync:
 - Pulls new/updated issues from GitLab to beads
 - Pushes local beads issues to GitLab
 
-Use --pull-only or --push-only to limit direction.`,
+Use --pull-only or --push-only to limit direction.
+
+Only issues changed since the last pull are fetched. An issue that changed
+on one side since the last sync is synced in that direction; an issue that
+changed on both sides is a conflict, resolved by --prefer-local,
+--prefer-gitlab or (default) --prefer-newer.`,
 	RunE: runGitLabSync,
 }
 
@@ -243,7 +248,6 @@ func runGitLabProjects(cmd *cobra.Command, args []string) error {
 }
 
 // runGitLabSync implements the gitlab sync command.
-// Uses SyncContext for thread-safe operations instead of global variables.
 func runGitLabSync(cmd *cobra.Command, args []string) error {
 	config := getGitLabConfig()
 	if err := validateGitLabConfig(config); err != nil {
@@ -258,174 +262,23 @@ func runGitLabSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot use both --pull-only and --push-only")
 	}
 
-	// Determine conflict strategy from flags
-	conflictStrategy, err := getConflictStrategy(gitlabPreferLocal, gitlabPreferGitLab, gitlabPreferNewer)
-	if err != nil {
-		return fmt.Errorf("%w (--prefer-local, --prefer-gitlab, --prefer-newer)", err)
-	}
-
-	if err := ensureStoreActive(); err != nil {
-		return fmt.Errorf("database not available: %w", err)
-	}
-
-	out := cmd.OutOrStdout()
-	client := getGitLabClient(config)
-	ctx := context.Background()
-	mappingConfig := gitlab.DefaultMappingConfig()
-
-	// Create SyncContext from globals for thread-safe operations
-	syncCtx := NewSyncContext()
-	syncCtx.SetStore(store)
-	syncCtx.SetActor(actor)
-	syncCtx.SetDBPath(dbPath)
-
-	if gitlabSyncDryRun {
-		_, _ = fmt.Fprintln(out, "Dry run mode - no changes will be made")
-		_, _ = fmt.Fprintln(out)
-	}
-
-	// Default: both pull and push
-	pull := !gitlabSyncPushOnly
-	push := !gitlabSyncPullOnly
-
-	result := &gitlab.SyncResult{Success: true}
-
-	// Pull from GitLab
-	if pull {
-		if gitlabSyncDryRun {
-			_, _ = fmt.Fprintln(out, "→ [DRY RUN] Would pull issues from GitLab")
-		} else {
-			_, _ = fmt.Fprintln(out, "→ Pulling issues from GitLab...")
-		}
-
-		pullStats, err := doPullFromGitLabWithContext(ctx, syncCtx, client, mappingConfig, gitlabSyncDryRun, "all", nil)
-		if err != nil {
-			result.Success = false
-			result.Error = err.Error()
-			_, _ = fmt.Fprintf(out, "Error pulling from GitLab: %v\n", err)
-			return err
-		}
-
-		result.Stats.Pulled = pullStats.Created + pullStats.Updated
-		result.Stats.Created += pullStats.Created
-		result.Stats.Updated += pullStats.Updated
-		result.Stats.Skipped += pullStats.Skipped
-
-		if !gitlabSyncDryRun {
-			_, _ = fmt.Fprintf(out, "✓ Pulled %d issues (%d created, %d updated)\n",
-				result.Stats.Pulled, pullStats.Created, pullStats.Updated)
+	// Conflict resolution flags are mutually exclusive
+	flagsSet := 0
+	for _, set := range []bool{gitlabPreferLocal, gitlabPreferGitLab, gitlabPreferNewer} {
+		if set {
+			flagsSet++
 		}
 	}
-
-	// Detect conflicts BEFORE push to prevent data loss.
-	// Conflict handling depends on strategy:
-	// - prefer-local: force push conflicting issues (local wins)
-	// - prefer-gitlab: skip conflicting issues, then update local from GitLab
-	// - prefer-newer: compare timestamps, force push if local newer, else skip
-	var conflicts []gitlab.Conflict
-	skipUpdateIDs := make(map[string]bool)
-	forceUpdateIDs := make(map[string]bool)
-
-	if pull && push && !gitlabSyncDryRun {
-		var localIssues []*types.Issue
-		if syncCtx.Store() != nil {
-			var err error
-			localIssues, err = syncCtx.Store().SearchIssues(ctx, "", types.IssueFilter{})
-			if err != nil {
-				_, _ = fmt.Fprintf(out, "Warning: failed to get local issues for conflict detection: %v\n", err)
-			} else {
-				conflicts, err = detectGitLabConflictsWithContext(ctx, syncCtx, client, localIssues)
-				if err != nil {
-					_, _ = fmt.Fprintf(out, "Warning: failed to detect conflicts: %v\n", err)
-				} else if len(conflicts) > 0 {
-					// Handle conflicts based on strategy
-					for _, c := range conflicts {
-						switch conflictStrategy {
-						case ConflictStrategyPreferLocal:
-							// Local wins: force push to GitLab
-							forceUpdateIDs[c.IssueID] = true
-						case ConflictStrategyPreferGitLab:
-							// GitLab wins: skip push, will update local in resolution
-							skipUpdateIDs[c.IssueID] = true
-						case ConflictStrategyPreferNewer:
-							// Use newer version
-							if c.LocalUpdated.After(c.GitLabUpdated) {
-								// Local is newer: force push
-								forceUpdateIDs[c.IssueID] = true
-							} else {
-								// GitLab is newer: skip push, will update local
-								skipUpdateIDs[c.IssueID] = true
-							}
-						}
-					}
-					_, _ = fmt.Fprintf(out, "→ Detected %d conflicts (strategy: %s)\n", len(conflicts), conflictStrategy)
-				}
-			}
-		}
+	if flagsSet > 1 {
+		return fmt.Errorf("cannot use multiple conflict resolution flags (--prefer-local, --prefer-gitlab, --prefer-newer)")
 	}
 
-	// Push to GitLab
-	if push {
-		if gitlabSyncDryRun {
-			_, _ = fmt.Fprintln(out, "→ [DRY RUN] Would push issues to GitLab")
-		} else {
-			_, _ = fmt.Fprintln(out, "→ Pushing issues to GitLab...")
-		}
-
-		// Get local issues to push
-		var localIssues []*types.Issue
-		if syncCtx.Store() != nil {
-			var err error
-			localIssues, err = syncCtx.Store().SearchIssues(ctx, "", types.IssueFilter{})
-			if err != nil {
-				return fmt.Errorf("failed to get local issues: %w", err)
-			}
-		}
-
-		// Pass both forceUpdateIDs (for prefer-local) and skipUpdateIDs (for prefer-gitlab)
-		pushStats, err := doPushToGitLabWithContext(ctx, syncCtx, client, mappingConfig, localIssues, gitlabSyncDryRun, false, forceUpdateIDs, skipUpdateIDs)
-		if err != nil {
-			result.Success = false
-			result.Error = err.Error()
-			_, _ = fmt.Fprintf(out, "Error pushing to GitLab: %v\n", err)
-			return err
-		}
-
-		result.Stats.Pushed = pushStats.Created + pushStats.Updated
-		result.Stats.Created += pushStats.Created
-		result.Stats.Updated += pushStats.Updated
-		result.Stats.Skipped += pushStats.Skipped
-
-		if !gitlabSyncDryRun {
-			_, _ = fmt.Fprintf(out, "✓ Pushed %d issues (%d created, %d updated)\n",
-				result.Stats.Pushed, pushStats.Created, pushStats.Updated)
-		}
+	opts := tracker.SyncOptions{
+		Pull:     !gitlabSyncPushOnly,
+		Push:     !gitlabSyncPullOnly,
+		DryRun:   gitlabSyncDryRun,
+		State:    "all",
+		Strategy: syncStrategy(gitlabPreferLocal, gitlabPreferGitLab),
 	}
-
-	// Resolve conflicts: update local from GitLab for issues where GitLab won
-	// (prefer-gitlab always, prefer-newer when GitLab was newer)
-	// For prefer-local, conflicts were force-pushed so no local update needed.
-	if len(skipUpdateIDs) > 0 && !gitlabSyncDryRun {
-		_, _ = fmt.Fprintf(out, "→ Updating %d local issues from GitLab...\n", len(skipUpdateIDs))
-		// Filter conflicts to only those where GitLab version should be applied locally
-		var conflictsToResolve []gitlab.Conflict
-		for _, c := range conflicts {
-			if skipUpdateIDs[c.IssueID] {
-				conflictsToResolve = append(conflictsToResolve, c)
-			}
-		}
-		if err := resolveGitLabConflictsWithContext(ctx, syncCtx, client, mappingConfig, conflictsToResolve, conflictStrategy); err != nil {
-			_, _ = fmt.Fprintf(out, "Warning: failed to resolve some conflicts: %v\n", err)
-		} else {
-			_, _ = fmt.Fprintf(out, "✓ Updated %d local issues\n", len(conflictsToResolve))
-		}
-		result.Stats.Conflicts = len(conflicts)
-	}
-
-	if gitlabSyncDryRun {
-		_, _ = fmt.Fprintln(out)
-		_, _ = fmt.Fprintln(out, "Run without --dry-run to apply changes")
-	}
-
-	return nil
+	return syncTracker("gitlab", opts)
 }
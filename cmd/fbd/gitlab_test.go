@@ -2,8 +2,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/gitlab"
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
 )
 
 // TestGitLabConfigFromEnv verifies config is read from environment variables.
@@ -186,3 +201,365 @@ func TestGitLabCmdRegistration(t *testing.T) {
 		t.Error("gitlabCmd missing 'projects' subcommand")
 	}
 }
+
+// fakeGitLabIssues serves a minimal GitLab issues API for the group/app
+// project, addressed by its path, and records list queries, created issues
+// and updates. failList and failCreate make those requests return a server
+// error.
+type fakeGitLabIssues struct {
+	mu         sync.Mutex
+	webURL     string
+	issues     map[int]*gitlab.Issue
+	queries    []url.Values
+	created    []map[string]interface{}
+	updated    map[int]map[string]interface{}
+	failList   bool
+	failCreate bool
+}
+
+func (f *fakeGitLabIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	readFields := func() map[string]interface{} {
+		var fields map[string]interface{}
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &fields)
+		return fields
+	}
+	apply := func(issue *gitlab.Issue, fields map[string]interface{}) {
+		issue.Title, _ = fields["title"].(string)
+		issue.Description, _ = fields["description"].(string)
+		if fields["state_event"] == "close" {
+			issue.State = "closed"
+		}
+		now := time.Now().UTC()
+		issue.UpdatedAt = &now
+	}
+
+	const prefix = "/api/v4/projects/group%2Fapp/issues"
+	path := r.URL.EscapedPath()
+	iid, _ := strconv.Atoi(strings.TrimPrefix(path, prefix+"/"))
+	switch {
+	case path == prefix && (f.failList && r.Method == http.MethodGet || f.failCreate && r.Method == http.MethodPost):
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message": "500 Internal Server Error"}`))
+	case path == prefix && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		f.queries = append(f.queries, query)
+		since, _ := time.Parse(time.RFC3339, query.Get("updated_after"))
+		list := []*gitlab.Issue{}
+		for _, issue := range f.issues {
+			if since.IsZero() || issue.UpdatedAt.After(since) {
+				list = append(list, issue)
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].IID < list[j].IID })
+		_ = json.NewEncoder(w).Encode(list)
+	case path == prefix && r.Method == http.MethodPost:
+		fields := readFields()
+		f.created = append(f.created, fields)
+		issue := &gitlab.Issue{IID: 100 + len(f.created), State: "opened"}
+		issue.WebURL = f.webURL + "/-/issues/" + strconv.Itoa(issue.IID)
+		apply(issue, fields)
+		f.issues[issue.IID] = issue
+		_ = json.NewEncoder(w).Encode(issue)
+	case f.issues[iid] != nil && r.Method == http.MethodPut:
+		fields := readFields()
+		f.updated[iid] = fields
+		apply(f.issues[iid], fields)
+		_ = json.NewEncoder(w).Encode(f.issues[iid])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "404 Not found"}`))
+	}
+}
+
+// useFakeGitLab sets up a test store configured for the path-based project
+// group/app on a fake API.
+func useFakeGitLab(t *testing.T, fake *fakeGitLabIssues) context.Context {
+	t.Helper()
+	ensureCleanGlobalState(t)
+	fake.updated = make(map[int]map[string]interface{})
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.webURL = server.URL + "/group/app"
+	for _, issue := range fake.issues {
+		issue.WebURL = fake.webURL + "/-/issues/" + strconv.Itoa(issue.IID)
+	}
+
+	testDBPath := filepath.Join(t.TempDir(), "test.db")
+	testStore := newTestStore(t, testDBPath)
+	ctx := context.Background()
+	for key, value := range map[string]string{
+		"gitlab.url":        server.URL,
+		"gitlab.token":      "test-token",
+		"gitlab.project_id": "group/app",
+	} {
+		if err := testStore.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig %s: %v", key, err)
+		}
+	}
+
+	origStore, origDBPath, origActor, origCtx := store, dbPath, actor, rootCtx
+	origActive, origJSON := storeActive, jsonOutput
+	store, dbPath, actor, rootCtx = testStore, testDBPath, "test-actor", ctx
+	storeActive, jsonOutput = true, true
+	t.Cleanup(func() {
+		store, dbPath, actor, rootCtx = origStore, origDBPath, origActor, origCtx
+		storeActive, jsonOutput = origActive, origJSON
+	})
+	return ctx
+}
+
+func gitlabIssueJSON(iid int, title, state string, labels []string, updated time.Time) *gitlab.Issue {
+	created := updated.Add(-time.Hour)
+	return &gitlab.Issue{IID: iid, Title: title, Description: title + " body", State: state,
+		Labels: labels, CreatedAt: &created, UpdatedAt: &updated}
+}
+
+func TestGitLabSyncRoundtrip(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitLabIssues{issues: map[int]*gitlab.Issue{
+		1: gitlabIssueJSON(1, "Open bug", "opened", []string{"type::bug", "priority::critical"}, updated),
+		2: gitlabIssueJSON(2, "Done task", "closed", nil, updated),
+	}}
+	ctx := useFakeGitLab(t, fake)
+
+	local := &types.Issue{Title: "Local feature", Priority: 2, IssueType: types.TypeFeature, Status: types.StatusOpen}
+	if err := store.CreateIssue(ctx, local, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := tracker.SyncOptions{Pull: true, Push: true, State: "all"}
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRef := make(map[string]*types.Issue)
+	for _, issue := range issues {
+		if issue.ExternalRef != nil {
+			byRef[*issue.ExternalRef] = issue
+		}
+	}
+	bug, done := byRef[fake.webURL+"/-/issues/1"], byRef[fake.webURL+"/-/issues/2"]
+	if bug == nil || done == nil {
+		t.Fatalf("imported refs = %v", byRef)
+	}
+	if bug.IssueType != types.TypeBug || bug.Priority != 0 || done.Status != types.StatusClosed {
+		t.Errorf("bug = %s/%d, done = %s", bug.IssueType, bug.Priority, done.Status)
+	}
+	if len(fake.created) != 1 || fake.created[0]["title"] != "Local feature" {
+		t.Fatalf("created = %+v", fake.created)
+	}
+	pushed, err := store.GetIssue(ctx, local.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushed.ExternalRef == nil || *pushed.ExternalRef != fake.webURL+"/-/issues/101" {
+		t.Errorf("external_ref = %v", pushed.ExternalRef)
+	}
+
+	// The next sync is incremental and finds nothing new to do
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if last := fake.queries[len(fake.queries)-1]; last.Get("updated_after") == "" {
+		t.Errorf("second sync query = %v, want updated_after", last)
+	}
+	if len(fake.created) != 1 || len(fake.updated) != 0 {
+		t.Errorf("second sync created = %d, updated = %v", len(fake.created), fake.updated)
+	}
+	after, err := store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(issues) {
+		t.Errorf("after second sync: %d issues, want %d", len(after), len(issues))
+	}
+}
+
+func TestGitLabSyncConflicts(t *testing.T) {
+	tests := []struct {
+		name          string
+		preferLocal   bool
+		preferRemote  bool
+		remoteUpdated time.Duration // Ago; the local edit is 30 minutes old
+		wantTitle     string
+	}{
+		{"newer wins", false, false, 10 * time.Minute, "Edited remotely"},
+		{"prefer local", true, false, 10 * time.Minute, "Edited locally"},
+		{"prefer remote", false, true, time.Hour, "Edited remotely"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteUpdated := time.Now().Add(-tt.remoteUpdated).UTC().Truncate(time.Second)
+			fake := &fakeGitLabIssues{issues: map[int]*gitlab.Issue{
+				1: gitlabIssueJSON(1, "Edited remotely", "opened", nil, remoteUpdated),
+			}}
+			ctx := useFakeGitLab(t, fake)
+
+			ref := fake.webURL + "/-/issues/1"
+			localUpdated := time.Now().Add(-30 * time.Minute).UTC()
+			issue := &types.Issue{Title: "Edited locally", Description: "Edited locally body", Priority: 2,
+				IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &ref,
+				CreatedAt: localUpdated, UpdatedAt: localUpdated}
+			if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+				t.Fatal(err)
+			}
+
+			opts := tracker.SyncOptions{Pull: true, Push: true, State: "all",
+				Strategy: syncStrategy(tt.preferLocal, tt.preferRemote)}
+			if err := syncTracker("gitlab", opts); err != nil {
+				t.Fatalf("syncTracker: %v", err)
+			}
+
+			got, err := store.GetIssue(ctx, issue.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != tt.wantTitle || fake.issues[1].Title != tt.wantTitle {
+				t.Errorf("local title = %q, GitLab title = %q, want %q", got.Title, fake.issues[1].Title, tt.wantTitle)
+			}
+			if pushed := len(fake.updated) > 0; pushed != (tt.wantTitle == "Edited locally") {
+				t.Errorf("updated = %v", fake.updated)
+			}
+		})
+	}
+}
+
+func TestGitLabSyncIncremental(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitLabIssues{issues: map[int]*gitlab.Issue{
+		1: gitlabIssueJSON(1, "Remote task", "opened", nil, updated),
+		2: gitlabIssueJSON(2, "Local task", "opened", nil, updated),
+	}}
+	ctx := useFakeGitLab(t, fake)
+
+	opts := tracker.SyncOptions{Pull: true, Push: true, State: "all"}
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if first := fake.queries[0]; first.Get("updated_after") != "" {
+		t.Errorf("first sync query = %v, want a full fetch", first)
+	}
+	localIDs := make(map[string]string)
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		localIDs[*issue.ExternalRef] = issue.ID
+	}
+
+	// Change one issue on each side and add a new GitLab issue
+	later := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	fake.issues[1].Title, fake.issues[1].UpdatedAt = "Remote task, renamed", &later
+	fake.issues[3] = gitlabIssueJSON(3, "New remote task", "opened", nil, later)
+	fake.issues[3].WebURL = fake.webURL + "/-/issues/3"
+	localID := localIDs[fake.webURL+"/-/issues/2"]
+	if err := store.UpdateIssue(ctx, localID, map[string]interface{}{"title": "Local task, renamed"}, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("incremental sync: %v", err)
+	}
+	if last := fake.queries[len(fake.queries)-1]; last.Get("updated_after") == "" {
+		t.Errorf("incremental sync query = %v, want updated_after", last)
+	}
+	renamed, err := store.GetIssue(ctx, localIDs[fake.webURL+"/-/issues/1"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Title != "Remote task, renamed" {
+		t.Errorf("pulled title = %q", renamed.Title)
+	}
+	if imported, err := store.GetIssueByExternalRef(ctx, fake.webURL+"/-/issues/3"); err != nil || imported == nil {
+		t.Errorf("new GitLab issue not imported: %v", err)
+	}
+	if len(fake.updated) != 1 || fake.updated[2]["title"] != "Local task, renamed" {
+		t.Errorf("updated = %+v, want only the local rename pushed", fake.updated)
+	}
+}
+
+func TestGitLabSyncDryRun(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitLabIssues{issues: map[int]*gitlab.Issue{
+		1: gitlabIssueJSON(1, "Remote task", "opened", nil, updated),
+	}}
+	ctx := useFakeGitLab(t, fake)
+	local := &types.Issue{Title: "Local task", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen}
+	if err := store.CreateIssue(ctx, local, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := tracker.SyncOptions{Pull: true, Push: true, DryRun: true, State: "all"}
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || len(fake.created) != 0 || len(fake.updated) != 0 {
+		t.Errorf("dry run changed something: %d local issues, created %v, updated %v", len(issues), fake.created, fake.updated)
+	}
+	if cursor, _ := store.GetConfig(ctx, "gitlab.last_sync"); cursor != "" {
+		t.Errorf("dry run saved gitlab.last_sync = %s", cursor)
+	}
+}
+
+func TestGitLabSyncErrors(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeGitLabIssues{issues: map[int]*gitlab.Issue{
+		1: gitlabIssueJSON(1, "Remote task", "opened", nil, updated),
+	}}
+	ctx := useFakeGitLab(t, fake)
+	local := &types.Issue{Title: "Local task", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen}
+	if err := store.CreateIssue(ctx, local, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	opts := tracker.SyncOptions{Pull: true, Push: true, State: "all"}
+
+	// A failed fetch fails the sync before anything changes
+	fake.failList = true
+	if err := syncTracker("gitlab", opts); err == nil {
+		t.Fatal("sync succeeded although GitLab could not be fetched")
+	}
+	if cursor, _ := store.GetConfig(ctx, "gitlab.last_sync"); cursor != "" {
+		t.Errorf("failed fetch saved gitlab.last_sync = %s", cursor)
+	}
+
+	// A failed push is reported but the rest of the sync goes through
+	fake.failList, fake.failCreate = false, true
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("sync with failing create: %v", err)
+	}
+	got, err := store.GetIssue(ctx, local.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExternalRef != nil {
+		t.Errorf("issue linked to %s although its create failed", *got.ExternalRef)
+	}
+	if pulled, err := store.GetIssueByExternalRef(ctx, fake.webURL+"/-/issues/1"); err != nil || pulled == nil {
+		t.Errorf("remote issue not pulled: %v", err)
+	}
+
+	// The next sync pushes and links the issue
+	fake.failCreate = false
+	if err := syncTracker("gitlab", opts); err != nil {
+		t.Fatalf("retry sync: %v", err)
+	}
+	if got, err = store.GetIssue(ctx, local.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.created) != 1 || got.ExternalRef == nil || *got.ExternalRef != fake.webURL+"/-/issues/101" {
+		t.Errorf("created = %d, external_ref = %v", len(fake.created), got.ExternalRef)
+	}
+}
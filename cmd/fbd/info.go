@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -36,6 +37,13 @@ Examples:
 		whatsNewFlag, _ := cmd.Flags().GetBool("whats-new")
 		thanksFlag, _ := cmd.Flags().GetBool("thanks")
 
+		// Schema details come from the store directly
+		if schemaFlag && daemonClient != nil {
+			if err := ensureDirectMode("info --schema requires direct database access"); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
 		// Handle --thanks flag
 		if thanksFlag {
 			printThanksPage()
@@ -60,8 +68,10 @@ Examples:
 			"mode":          daemonStatus.Mode,
 		}
 
-		// Direct mode
-		info["daemon_connected"] = false
+		info["daemon_connected"] = daemonStatus.Connected
+		if daemonStatus.SocketPath != "" {
+			info["socket_path"] = daemonStatus.SocketPath
+		}
 		if daemonStatus.FallbackReason != "" && daemonStatus.FallbackReason != FallbackNone {
 			info["daemon_fallback_reason"] = daemonStatus.FallbackReason
 		}
@@ -69,8 +79,19 @@ Examples:
 			info["daemon_detail"] = daemonStatus.Detail
 		}
 
-		// Get issue count from direct store
-		if store != nil {
+		if daemonStatus.Health != "" {
+			info["daemon_health"] = daemonStatus.Health
+		}
+
+		// Get issue count from the daemon, or from the direct store
+		if daemonClient != nil {
+			if resp, err := daemonClient.Stats(); err == nil {
+				var stats types.Statistics
+				if json.Unmarshal(resp.Data, &stats) == nil {
+					info["issue_count"] = stats.TotalIssues
+				}
+			}
+		} else if store != nil {
 			ctx := rootCtx
 
 			requireFreshDB(ctx)
@@ -153,7 +174,17 @@ Examples:
 		fmt.Printf("Mode: %s\n", daemonStatus.Mode)
 
 		fmt.Println("\nDaemon Status:")
-		fmt.Printf("  Connected: no\n")
+		if daemonStatus.Connected {
+			fmt.Printf("  Connected: yes\n")
+		} else {
+			fmt.Printf("  Connected: no\n")
+		}
+		if daemonStatus.SocketPath != "" {
+			fmt.Printf("  Socket: %s\n", daemonStatus.SocketPath)
+		}
+		if daemonStatus.Health != "" {
+			fmt.Printf("  Health: %s\n", daemonStatus.Health)
+		}
 		if daemonStatus.FallbackReason != "" && daemonStatus.FallbackReason != FallbackNone {
 			fmt.Printf("  Reason: %s\n", daemonStatus.FallbackReason)
 		}
@@ -1,7 +1,21 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
 )
 
 func TestIsJiraExternalRef(t *testing.T) {
@@ -84,211 +98,285 @@ func TestIsJiraExternalRef(t *testing.T) {
 	}
 }
 
-func TestJiraSyncStats(t *testing.T) {
-	// Test that stats struct initializes correctly
-	stats := JiraSyncStats{}
+// fakeJira serves a minimal Jira REST v2 API for project PROJ and records
+// created issues, field updates and transitions.
+type fakeJira struct {
+	mu          sync.Mutex
+	issues      map[string]map[string]interface{}
+	created     []map[string]interface{}
+	updated     map[string]map[string]interface{}
+	transitions map[string]string
+}
 
-	if stats.Pulled != 0 {
-		t.Errorf("expected Pulled to be 0, got %d", stats.Pulled)
-	}
-	if stats.Pushed != 0 {
-		t.Errorf("expected Pushed to be 0, got %d", stats.Pushed)
-	}
-	if stats.Created != 0 {
-		t.Errorf("expected Created to be 0, got %d", stats.Created)
-	}
-	if stats.Updated != 0 {
-		t.Errorf("expected Updated to be 0, got %d", stats.Updated)
-	}
-	if stats.Skipped != 0 {
-		t.Errorf("expected Skipped to be 0, got %d", stats.Skipped)
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	readFields := func() map[string]interface{} {
+		var body map[string]map[string]interface{}
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		return body["fields"]
 	}
-	if stats.Errors != 0 {
-		t.Errorf("expected Errors to be 0, got %d", stats.Errors)
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/2/"), "/")
+	switch {
+	case parts[0] == "search":
+		keys := make([]string, 0, len(f.issues))
+		for key := range f.issues {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		list := []map[string]interface{}{}
+		for _, key := range keys {
+			list = append(list, f.issues[key])
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"startAt": 0, "total": len(list), "issues": list})
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		fields := readFields()
+		f.created = append(f.created, fields)
+		key := "PROJ-" + strconv.Itoa(100+len(f.created))
+		summary, _ := fields["summary"].(string)
+		f.issues[key] = jiraIssueJSON(key, summary, "To Do", time.Now())
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "1", "key": key})
+	case len(parts) == 3 && parts[2] == "transitions" && r.Method == http.MethodGet:
+		_, _ = w.Write([]byte(`{"transitions": [{"id": "11", "to": {"name": "To Do"}},
+			{"id": "21", "to": {"name": "In Progress"}}, {"id": "31", "to": {"name": "Done"}}]}`))
+	case len(parts) == 3 && parts[2] == "transitions":
+		var body map[string]map[string]string
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		f.transitions[parts[1]] = body["transition"]["id"]
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && f.issues[parts[1]] != nil:
+		if r.Method == http.MethodPut {
+			f.updated[parts[1]] = readFields()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.issues[parts[1]])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages": ["Issue does not exist"]}`))
 	}
-	if stats.Conflicts != 0 {
-		t.Errorf("expected Conflicts to be 0, got %d", stats.Conflicts)
+}
+
+// useFakeJira sets up a test store configured for project PROJ on a fake API.
+func useFakeJira(t *testing.T, fake *fakeJira) (context.Context, string) {
+	t.Helper()
+	ensureCleanGlobalState(t)
+	fake.updated = make(map[string]map[string]interface{})
+	fake.transitions = make(map[string]string)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	testDBPath := filepath.Join(t.TempDir(), "test.db")
+	testStore := newTestStore(t, testDBPath)
+	ctx := context.Background()
+	for key, value := range map[string]string{
+		"jira.url":         server.URL,
+		"jira.project":     "PROJ",
+		"jira.api_token":   "test-token",
+		"jira.pull_prefix": "hippo",
+	} {
+		if err := testStore.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig %s: %v", key, err)
+		}
 	}
+
+	origStore, origDBPath, origActor, origCtx := store, dbPath, actor, rootCtx
+	origActive, origJSON := storeActive, jsonOutput
+	store, dbPath, actor, rootCtx = testStore, testDBPath, "test-actor", ctx
+	storeActive, jsonOutput = true, true
+	t.Cleanup(func() {
+		store, dbPath, actor, rootCtx = origStore, origDBPath, origActor, origCtx
+		storeActive, jsonOutput = origActive, origJSON
+	})
+	return ctx, server.URL
 }
 
-func TestJiraSyncResult(t *testing.T) {
-	// Test result struct initialization
-	result := JiraSyncResult{
-		Success: true,
-		Stats: JiraSyncStats{
-			Created: 5,
-			Updated: 3,
+func jiraIssueJSON(key, summary, status string, updated time.Time) map[string]interface{} {
+	const layout = "2006-01-02T15:04:05.000-0700"
+	return map[string]interface{}{
+		"key": key,
+		"fields": map[string]interface{}{
+			"summary":     summary,
+			"description": summary + " body",
+			"status":      map[string]string{"name": status},
+			"priority":    map[string]string{"name": "Medium"},
+			"issuetype":   map[string]string{"name": "Task"},
+			"labels":      []string{},
+			"created":     updated.Add(-time.Hour).Format(layout),
+			"updated":     updated.Format(layout),
 		},
-		LastSync: "2025-01-15T10:30:00Z",
 	}
+}
 
-	if !result.Success {
-		t.Error("expected Success to be true")
+func TestJiraSyncPull(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	story := jiraIssueJSON("PROJ-1", "Login page", "In Progress", updated)
+	fields := story["fields"].(map[string]interface{})
+	fields["issuetype"] = map[string]string{"name": "Story"}
+	fields["priority"] = map[string]string{"name": "Highest"}
+	fields["labels"] = []string{"ui"}
+	fields["assignee"] = map[string]string{"displayName": "Ada Lovelace"}
+	fields["issuelinks"] = []map[string]interface{}{
+		{"type": map[string]string{"name": "Blocks"}, "inwardIssue": map[string]string{"key": "PROJ-2"}},
 	}
-	if result.Stats.Created != 5 {
-		t.Errorf("expected Created to be 5, got %d", result.Stats.Created)
+	fake := &fakeJira{issues: map[string]map[string]interface{}{
+		"PROJ-1": story,
+		"PROJ-2": jiraIssueJSON("PROJ-2", "Auth service", "Done", updated),
+	}}
+	ctx, jiraURL := useFakeJira(t, fake)
+
+	opts := tracker.SyncOptions{Pull: true, State: "all"}
+	if err := syncTracker("jira", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
 	}
-	if result.Stats.Updated != 3 {
-		t.Errorf("expected Updated to be 3, got %d", result.Stats.Updated)
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
 	}
-	if result.LastSync != "2025-01-15T10:30:00Z" {
-		t.Errorf("unexpected LastSync value: %s", result.LastSync)
+	byKey := make(map[string]*types.Issue)
+	for _, issue := range issues {
+		if !strings.HasPrefix(issue.ID, "hippo-") {
+			t.Errorf("issue ID %q lacks pull prefix", issue.ID)
+		}
+		if issue.ExternalRef != nil {
+			byKey[strings.TrimPrefix(*issue.ExternalRef, jiraURL+"/browse/")] = issue
+		}
 	}
-	if result.Error != "" {
-		t.Errorf("expected Error to be empty, got %s", result.Error)
+	login, auth := byKey["PROJ-1"], byKey["PROJ-2"]
+	if login == nil || auth == nil {
+		t.Fatalf("imported issues = %v", byKey)
 	}
-	if len(result.Warnings) != 0 {
-		t.Errorf("expected Warnings to be empty, got %v", result.Warnings)
+	if login.IssueType != types.TypeFeature || login.Priority != 0 || login.Status != types.StatusInProgress ||
+		login.Assignee != "Ada Lovelace" || !slices.Equal(login.Labels, []string{"ui"}) {
+		t.Errorf("login = %s/%d/%s/%q/%v", login.IssueType, login.Priority, login.Status, login.Assignee, login.Labels)
+	}
+	if auth.Status != types.StatusClosed {
+		t.Errorf("auth status = %s", auth.Status)
 	}
-}
 
-func TestPullStats(t *testing.T) {
-	stats := PullStats{
-		Created: 10,
-		Updated: 5,
-		Skipped: 2,
+	deps, err := store.GetDependencyRecords(ctx, login.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != auth.ID || deps[0].Type != types.DepBlocks {
+		t.Errorf("login deps = %+v", deps)
 	}
 
-	if stats.Created != 10 {
-		t.Errorf("expected Created to be 10, got %d", stats.Created)
+	// A second sync updates in place rather than duplicating
+	if err := syncTracker("jira", opts); err != nil {
+		t.Fatalf("second sync: %v", err)
 	}
-	if stats.Updated != 5 {
-		t.Errorf("expected Updated to be 5, got %d", stats.Updated)
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
 	}
-	if stats.Skipped != 2 {
-		t.Errorf("expected Skipped to be 2, got %d", stats.Skipped)
+	if len(issues) != 2 {
+		t.Errorf("after second sync: %d issues, want 2", len(issues))
 	}
 }
 
-func TestPushStats(t *testing.T) {
-	stats := PushStats{
-		Created: 8,
-		Updated: 4,
-		Skipped: 1,
-		Errors:  2,
+func TestJiraSyncPush(t *testing.T) {
+	remoteUpdated := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	fake := &fakeJira{issues: map[string]map[string]interface{}{
+		"PROJ-1": jiraIssueJSON("PROJ-1", "Linked", "To Do", remoteUpdated),
+	}}
+	ctx, jiraURL := useFakeJira(t, fake)
+
+	newIssue := &types.Issue{Title: "Fresh bug", Priority: 0, IssueType: types.TypeBug, Status: types.StatusInProgress}
+	if err := store.CreateIssue(ctx, newIssue, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	ref := jiraURL + "/browse/PROJ-1"
+	linked := &types.Issue{Title: "Linked, edited", Description: "Linked body", Priority: 2,
+		IssueType: types.TypeTask, Status: types.StatusClosed, ExternalRef: &ref}
+	if err := store.CreateIssue(ctx, linked, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	otherRef := "https://github.com/acme/widgets/issues/1"
+	foreign := &types.Issue{Title: "Other tracker", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &otherRef}
+	if err := store.CreateIssue(ctx, foreign, "test-actor"); err != nil {
+		t.Fatal(err)
 	}
 
-	if stats.Created != 8 {
-		t.Errorf("expected Created to be 8, got %d", stats.Created)
+	if err := syncTracker("jira", tracker.SyncOptions{Pull: true, Push: true, State: "all"}); err != nil {
+		t.Fatalf("syncTracker: %v", err)
 	}
-	if stats.Updated != 4 {
-		t.Errorf("expected Updated to be 4, got %d", stats.Updated)
+
+	if len(fake.created) != 1 || fake.created[0]["summary"] != "Fresh bug" {
+		t.Fatalf("created = %+v", fake.created)
 	}
-	if stats.Skipped != 1 {
-		t.Errorf("expected Skipped to be 1, got %d", stats.Skipped)
+	issueType, _ := fake.created[0]["issuetype"].(map[string]interface{})
+	priority, _ := fake.created[0]["priority"].(map[string]interface{})
+	if issueType["name"] != "Bug" || priority["name"] != "Highest" {
+		t.Errorf("created type/priority = %v/%v", issueType, priority)
 	}
-	if stats.Errors != 2 {
-		t.Errorf("expected Errors to be 2, got %d", stats.Errors)
+	got, err := store.GetIssue(ctx, newIssue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExternalRef == nil || *got.ExternalRef != jiraURL+"/browse/PROJ-101" {
+		t.Errorf("external_ref = %v", got.ExternalRef)
 	}
-}
 
-func TestExtractJiraKey(t *testing.T) {
-	tests := []struct {
-		name        string
-		externalRef string
-		want        string
-	}{
-		{
-			name:        "standard Jira Cloud URL",
-			externalRef: "https://company.atlassian.net/browse/PROJ-123",
-			want:        "PROJ-123",
-		},
-		{
-			name:        "Jira Server URL",
-			externalRef: "https://jira.company.com/browse/ISSUE-456",
-			want:        "ISSUE-456",
-		},
-		{
-			name:        "URL with trailing path",
-			externalRef: "https://company.atlassian.net/browse/ABC-789/some/path",
-			want:        "ABC-789/some/path",
-		},
-		{
-			name:        "no browse pattern",
-			externalRef: "https://github.com/org/repo/issues/123",
-			want:        "",
-		},
-		{
-			name:        "empty string",
-			externalRef: "",
-			want:        "",
-		},
-		{
-			name:        "only browse",
-			externalRef: "https://example.com/browse/",
-			want:        "",
-		},
+	if fake.updated["PROJ-1"]["summary"] != "Linked, edited" {
+		t.Errorf("updated = %+v", fake.updated)
+	}
+	if fake.transitions["PROJ-101"] != "21" || fake.transitions["PROJ-1"] != "31" {
+		t.Errorf("transitions = %v", fake.transitions)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := extractJiraKey(tt.externalRef)
-			if got != tt.want {
-				t.Errorf("extractJiraKey(%q) = %q, want %q", tt.externalRef, got, tt.want)
-			}
-		})
+	// --create-only leaves linked issues alone
+	fake.updated = make(map[string]map[string]interface{})
+	linked.Title = "Linked, edited again"
+	if err := store.UpdateIssue(ctx, linked.ID, map[string]interface{}{"title": linked.Title}, "test-actor"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncTracker("jira", tracker.SyncOptions{Push: true, CreateOnly: true, State: "all"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.created) != 1 || len(fake.updated) != 0 {
+		t.Errorf("created = %d, updated = %v", len(fake.created), fake.updated)
 	}
 }
 
-func TestParseJiraTimestamp(t *testing.T) {
-	tests := []struct {
-		name      string
-		timestamp string
-		wantErr   bool
-		wantYear  int
-	}{
-		{
-			name:      "standard Jira Cloud format with milliseconds",
-			timestamp: "2024-01-15T10:30:00.000+0000",
-			wantErr:   false,
-			wantYear:  2024,
-		},
-		{
-			name:      "Jira format with Z suffix",
-			timestamp: "2024-01-15T10:30:00.000Z",
-			wantErr:   false,
-			wantYear:  2024,
-		},
-		{
-			name:      "without milliseconds",
-			timestamp: "2024-01-15T10:30:00+0000",
-			wantErr:   false,
-			wantYear:  2024,
-		},
-		{
-			name:      "RFC3339 format",
-			timestamp: "2024-01-15T10:30:00Z",
-			wantErr:   false,
-			wantYear:  2024,
-		},
-		{
-			name:      "empty string",
-			timestamp: "",
-			wantErr:   true,
-		},
-		{
-			name:      "invalid format",
-			timestamp: "not-a-timestamp",
-			wantErr:   true,
-		},
-		{
-			name:      "with negative timezone offset",
-			timestamp: "2024-06-15T10:30:00.000-0500",
-			wantErr:   false,
-			wantYear:  2024,
-		},
+func TestJiraSyncConflicts(t *testing.T) {
+	remoteUpdated := time.Now().Add(-30 * time.Minute).UTC().Truncate(time.Second)
+	fake := &fakeJira{issues: map[string]map[string]interface{}{
+		"PROJ-1": jiraIssueJSON("PROJ-1", "Edited remotely", "To Do", remoteUpdated),
+		"PROJ-2": jiraIssueJSON("PROJ-2", "Same", "To Do", remoteUpdated),
+	}}
+	ctx, jiraURL := useFakeJira(t, fake)
+
+	ids := make(map[string]string)
+	for key, title := range map[string]string{"PROJ-1": "Edited locally", "PROJ-2": "Same"} {
+		ref := jiraURL + "/browse/" + key
+		issue := &types.Issue{Title: title, Description: title + " body", Priority: 2,
+			IssueType: types.TypeTask, Status: types.StatusOpen, ExternalRef: &ref}
+		if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+			t.Fatal(err)
+		}
+		ids[key] = issue.ID
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseJiraTimestamp(tt.timestamp)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("parseJiraTimestamp(%q) error = %v, wantErr %v", tt.timestamp, err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr && got.Year() != tt.wantYear {
-				t.Errorf("parseJiraTimestamp(%q) year = %d, want %d", tt.timestamp, got.Year(), tt.wantYear)
-			}
-		})
+	// Preferring Jira re-imports the remote version of the conflict and
+	// leaves the matching issue alone
+	opts := tracker.SyncOptions{Pull: true, Push: true, State: "all", Strategy: syncStrategy(false, true)}
+	if err := syncTracker("jira", opts); err != nil {
+		t.Fatalf("syncTracker: %v", err)
+	}
+	if len(fake.updated) != 0 {
+		t.Errorf("updated = %v, want Jira to win", fake.updated)
+	}
+	got, err := store.GetIssue(ctx, ids["PROJ-1"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Edited remotely" {
+		t.Errorf("title = %q, want remote title", got.Title)
 	}
 }
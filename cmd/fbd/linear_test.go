@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/fastbeads/internal/linear"
+	"github.com/steveyegge/fastbeads/internal/tracker"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
@@ -1095,9 +1096,14 @@ func TestFetchIssueByIdentifierSendsNumericFilter(t *testing.T) {
 	}
 }
 
-func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
+// useFakeLinear sets up a test store configured for a Linear team and
+// routes GraphQL requests to respond, which returns the response data for a
+// query.
+func useFakeLinear(t *testing.T, respond func(gqlReq linear.GraphQLRequest) (string, error)) context.Context {
+	t.Helper()
+	ensureCleanGlobalState(t)
 	testStore, cleanup := setupTestDB(t)
-	defer cleanup()
+	t.Cleanup(cleanup)
 
 	ctx := context.Background()
 	if err := testStore.SetConfig(ctx, "linear.api_key", "test-api-key"); err != nil {
@@ -1107,26 +1113,6 @@ func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
 		t.Fatalf("SetConfig linear.team_id failed: %v", err)
 	}
 
-	localUpdated := time.Now().Add(-2 * time.Hour)
-	issue := &types.Issue{
-		Title:       "Local Issue",
-		Description: "Local description",
-		Priority:    2,
-		IssueType:   types.TypeTask,
-		Status:      types.StatusInProgress,
-		CreatedAt:   localUpdated,
-		UpdatedAt:   localUpdated,
-	}
-	externalRef := "https://linear.app/team/issue/TEAM-123/local-issue"
-	issue.ExternalRef = &externalRef
-	if err := testStore.CreateIssue(ctx, issue, "test-actor"); err != nil {
-		t.Fatalf("CreateIssue failed: %v", err)
-	}
-
-	remoteUpdated := time.Now().Add(-1 * time.Hour)
-	remoteUpdatedStr := remoteUpdated.UTC().Format(time.RFC3339)
-
-	updatedCalled := false
 	origTransport := http.DefaultTransport
 	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
 		body, err := io.ReadAll(r.Body)
@@ -1139,64 +1125,13 @@ func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
 		if err := json.Unmarshal(body, &gqlReq); err != nil {
 			return nil, fmt.Errorf("decode request body: %w", err)
 		}
-
-		var resp struct {
-			Data   json.RawMessage `json:"data"`
-			Errors []interface{}   `json:"errors,omitempty"`
-		}
-		switch {
-		case strings.Contains(gqlReq.Query, "TeamStates"):
-			resp.Data = json.RawMessage(`{
-				"team": {
-					"id": "team-123",
-					"states": {
-						"nodes": [
-							{"id": "state-started", "name": "In Progress", "type": "started"}
-						]
-					}
-				}
-			}`)
-		case strings.Contains(gqlReq.Query, "IssueByIdentifier"):
-			resp.Data = json.RawMessage(fmt.Sprintf(`{
-				"issues": {
-					"nodes": [
-						{
-							"id": "uuid-123",
-							"identifier": "TEAM-123",
-							"title": "Remote Issue",
-							"description": "Remote description",
-							"url": "https://linear.app/team/issue/TEAM-123/remote-issue",
-							"priority": 2,
-							"state": {"id": "state-started", "name": "In Progress", "type": "started"},
-							"labels": {"nodes": []},
-							"createdAt": "2025-01-01T00:00:00Z",
-							"updatedAt": "%s"
-						}
-					]
-				}
-			}`, remoteUpdatedStr))
-		case strings.Contains(gqlReq.Query, "UpdateIssue"):
-			updatedCalled = true
-			resp.Data = json.RawMessage(`{
-				"issueUpdate": {
-					"success": true,
-					"issue": {
-						"id": "uuid-123",
-						"identifier": "TEAM-123",
-						"title": "Updated Title",
-						"description": "Updated description",
-						"url": "https://linear.app/team/issue/TEAM-123/remote-issue",
-						"priority": 2,
-						"state": {"id": "state-started", "name": "In Progress", "type": "started"},
-						"updatedAt": "2025-01-02T00:00:00Z"
-					}
-				}
-			}`)
-		default:
-			return nil, fmt.Errorf("unexpected query: %s", gqlReq.Query)
+		data, err := respond(gqlReq)
+		if err != nil {
+			return nil, err
 		}
-
-		respBytes, err := json.Marshal(resp)
+		respBytes, err := json.Marshal(struct {
+			Data json.RawMessage `json:"data"`
+		}{Data: json.RawMessage(data)})
 		if err != nil {
 			return nil, fmt.Errorf("encode response: %w", err)
 		}
@@ -1208,97 +1143,146 @@ func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
 			Request:    r,
 		}, nil
 	})
-	t.Cleanup(func() { http.DefaultTransport = origTransport })
 
-	origStore := store
-	origActor := actor
-	store = testStore
-	actor = "test-actor"
+	origStore, origActor, origCtx := store, actor, rootCtx
+	origActive, origJSON := storeActive, jsonOutput
+	store, actor, rootCtx = testStore, "test-actor", ctx
+	storeActive, jsonOutput = true, true
 	t.Cleanup(func() {
-		store = origStore
-		actor = origActor
+		http.DefaultTransport = origTransport
+		store, actor, rootCtx = origStore, origActor, origCtx
+		storeActive, jsonOutput = origActive, origJSON
 	})
+	return ctx
+}
 
-	forceUpdateIDs := map[string]bool{issue.ID: true}
-	stats, err := doPushToLinear(ctx, false, false, true, forceUpdateIDs, nil, nil, nil, false)
-	if err != nil {
-		t.Fatalf("doPushToLinear failed: %v", err)
-	}
-	if !updatedCalled {
-		t.Fatal("expected UpdateIssue to be called when force-update is enabled")
-	}
-	if stats.Updated != 1 {
-		t.Fatalf("expected Updated=1, got %d", stats.Updated)
-	}
-	if stats.Skipped != 0 {
-		t.Fatalf("expected Skipped=0, got %d", stats.Skipped)
+const linearTeamStatesJSON = `{
+	"team": {
+		"id": "team-123",
+		"states": {
+			"nodes": [
+				{"id": "state-unstarted", "name": "Todo", "type": "unstarted"},
+				{"id": "state-started", "name": "In Progress", "type": "started"}
+			]
+		}
 	}
-}
+}`
+
+func TestLinearSyncPreferLocalPushesConflict(t *testing.T) {
+	remoteUpdated := time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339)
+	remoteIssue := fmt.Sprintf(`{
+		"id": "uuid-123",
+		"identifier": "TEAM-123",
+		"title": "Remote Issue",
+		"description": "Remote description",
+		"url": "https://linear.app/team/issue/TEAM-123/remote-issue",
+		"priority": 2,
+		"state": {"id": "state-started", "name": "In Progress", "type": "started"},
+		"labels": {"nodes": []},
+		"createdAt": "2025-01-01T00:00:00Z",
+		"updatedAt": "%s"
+	}`, remoteUpdated)
 
-func TestDoPushToLinearIncludeEphemeralFlag(t *testing.T) {
-	testStore, cleanup := setupTestDB(t)
-	defer cleanup()
+	updatedCalled := false
+	ctx := useFakeLinear(t, func(gqlReq linear.GraphQLRequest) (string, error) {
+		switch {
+		case strings.Contains(gqlReq.Query, "TeamStates"):
+			return linearTeamStatesJSON, nil
+		case strings.Contains(gqlReq.Query, "IssueByIdentifier"):
+			return `{"issues": {"nodes": [` + remoteIssue + `]}}`, nil
+		case strings.Contains(gqlReq.Query, "query Issues"):
+			return `{"issues": {"nodes": [` + remoteIssue + `], "pageInfo": {"hasNextPage": false}}}`, nil
+		case strings.Contains(gqlReq.Query, "UpdateIssue"):
+			updatedCalled = true
+			return `{"issueUpdate": {"success": true, "issue": ` + remoteIssue + `}}`, nil
+		default:
+			return "", fmt.Errorf("unexpected query: %s", gqlReq.Query)
+		}
+	})
 
-	ctx := context.Background()
-	if err := testStore.SetConfig(ctx, "linear.api_key", "test-api-key"); err != nil {
-		t.Fatalf("SetConfig linear.api_key failed: %v", err)
+	// The remote edit is newer, so only --prefer-local pushes the local one
+	localUpdated := time.Now().Add(-2 * time.Hour)
+	externalRef := "https://linear.app/team/issue/TEAM-123/local-issue"
+	issue := &types.Issue{
+		Title:       "Local Issue",
+		Description: "Local description",
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		Status:      types.StatusInProgress,
+		CreatedAt:   localUpdated,
+		UpdatedAt:   localUpdated,
+		ExternalRef: &externalRef,
 	}
-	if err := testStore.SetConfig(ctx, "linear.team_id", "12345678-1234-1234-1234-123456789abc"); err != nil {
-		t.Fatalf("SetConfig linear.team_id failed: %v", err)
+	if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
 	}
 
-	now := time.Now()
-	persistent := &types.Issue{
-		Title:     "Persistent issue",
-		Priority:  2,
-		IssueType: types.TypeTask,
-		Status:    types.StatusOpen,
-		CreatedAt: now,
-		UpdatedAt: now,
-		Ephemeral: false,
+	opts := tracker.SyncOptions{Pull: true, Push: true, State: "all", Strategy: syncStrategy(true, false)}
+	if err := syncTracker("linear", opts); err != nil {
+		t.Fatalf("syncTracker failed: %v", err)
 	}
-	if err := testStore.CreateIssue(ctx, persistent, "test-actor"); err != nil {
-		t.Fatalf("CreateIssue persistent failed: %v", err)
+	if !updatedCalled {
+		t.Fatal("expected UpdateIssue to be called when local is preferred")
 	}
-
-	ephemeral := &types.Issue{
-		Title:     "Ephemeral wisp",
-		Priority:  2,
-		IssueType: types.TypeTask,
-		Status:    types.StatusOpen,
-		CreatedAt: now,
-		UpdatedAt: now,
-		Ephemeral: true,
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if err := testStore.CreateIssue(ctx, ephemeral, "test-actor"); err != nil {
-		t.Fatalf("CreateIssue ephemeral failed: %v", err)
+	if got.Title != "Local Issue" {
+		t.Errorf("title = %q, want local title kept", got.Title)
 	}
+}
 
-	origStore := store
-	origActor := actor
-	store = testStore
-	actor = "test-actor"
-	t.Cleanup(func() {
-		store = origStore
-		actor = origActor
+func TestLinearSyncIncludeEphemeralFlag(t *testing.T) {
+	var created []string
+	ctx := useFakeLinear(t, func(gqlReq linear.GraphQLRequest) (string, error) {
+		switch {
+		case strings.Contains(gqlReq.Query, "TeamStates"):
+			return linearTeamStatesJSON, nil
+		case strings.Contains(gqlReq.Query, "query Issues"):
+			return `{"issues": {"nodes": [], "pageInfo": {"hasNextPage": false}}}`, nil
+		case strings.Contains(gqlReq.Query, "CreateIssue"):
+			input, _ := gqlReq.Variables["input"].(map[string]interface{})
+			title, _ := input["title"].(string)
+			created = append(created, title)
+			identifier := fmt.Sprintf("TEAM-%d", len(created))
+			return fmt.Sprintf(`{"issueCreate": {"success": true, "issue": {
+				"id": "uuid-%[1]s", "identifier": "%[1]s", "title": %[2]q,
+				"url": "https://linear.app/team/issue/%[1]s",
+				"state": {"id": "state-unstarted", "name": "Todo", "type": "unstarted"},
+				"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-01T00:00:00Z"
+			}}}`, identifier, title), nil
+		default:
+			return "", fmt.Errorf("unexpected query: %s", gqlReq.Query)
+		}
 	})
 
-	// Default (includeEphemeral=false): only non-ephemeral issues are pushed
-	statsExclude, err := doPushToLinear(ctx, true, false, false, nil, nil, nil, nil, false)
-	if err != nil {
-		t.Fatalf("doPushToLinear includeEphemeral=false failed: %v", err)
+	for _, issue := range []*types.Issue{
+		{Title: "Persistent issue", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen},
+		{Title: "Ephemeral wisp", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen, Ephemeral: true},
+	} {
+		if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+			t.Fatalf("CreateIssue %q failed: %v", issue.Title, err)
+		}
 	}
-	if statsExclude.Created != 1 {
-		t.Errorf("includeEphemeral=false: expected Created=1 (persistent only), got %d", statsExclude.Created)
+
+	// Default: only non-ephemeral issues are pushed
+	opts := tracker.SyncOptions{Push: true, State: "all"}
+	if err := syncTracker("linear", opts); err != nil {
+		t.Fatalf("sync without --include-ephemeral failed: %v", err)
+	}
+	if len(created) != 1 || created[0] != "Persistent issue" {
+		t.Fatalf("without --include-ephemeral: created %v, want the persistent issue only", created)
 	}
 
-	// includeEphemeral=true: both issues are pushed
-	statsInclude, err := doPushToLinear(ctx, true, false, false, nil, nil, nil, nil, true)
-	if err != nil {
-		t.Fatalf("doPushToLinear includeEphemeral=true failed: %v", err)
+	// --include-ephemeral pushes the wisp too; the persistent issue is
+	// already linked
+	opts.IncludeEphemeral = true
+	if err := syncTracker("linear", opts); err != nil {
+		t.Fatalf("sync with --include-ephemeral failed: %v", err)
 	}
-	if statsInclude.Created != 2 {
-		t.Errorf("includeEphemeral=true: expected Created=2 (persistent + ephemeral), got %d", statsInclude.Created)
+	if len(created) != 2 || created[1] != "Ephemeral wisp" {
+		t.Errorf("with --include-ephemeral: created %v, want the wisp as well", created)
 	}
 }
 
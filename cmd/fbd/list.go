@@ -14,6 +14,7 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/steveyegge/fastbeads/internal/config"
 	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
@@ -186,6 +187,13 @@ func watchIssues(ctx context.Context, store storage.Storage, filter types.IssueF
 	}
 }
 
+// issuePage is the --json output of a paginated list or ready query.
+// NextCursor is empty on the last page.
+type issuePage struct {
+	Issues     []*types.IssueWithCounts `json:"issues"`
+	NextCursor string                   `json:"next_cursor"`
+}
+
 // sortIssues sorts a slice of issues by the specified field and direction
 func sortIssues(issues []*types.Issue, sortBy string, reverse bool) {
 	if sortBy == "" {
@@ -244,6 +252,22 @@ var listCmd = &cobra.Command{
 	GroupID: "issues",
 	Short:   "List issues",
 	Run: func(cmd *cobra.Command, args []string) {
+		// Named view: the saved query replaces the filter flags
+		if viewName, _ := cmd.Flags().GetString("view"); viewName != "" {
+			var conflicts []string
+			cmd.Flags().Visit(func(f *pflag.Flag) {
+				if !listViewFlags[f.Name] {
+					conflicts = append(conflicts, "--"+f.Name)
+				}
+			})
+			if len(conflicts) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: --view cannot be combined with %s (edit the view's query instead)\n", strings.Join(conflicts, ", "))
+				os.Exit(1)
+			}
+			runView(cmd, viewName)
+			return
+		}
+
 		status, _ := cmd.Flags().GetString("status")
 		assignee, _ := cmd.Flags().GetString("assignee")
 		issueType, _ := cmd.Flags().GetString("type")
@@ -261,6 +285,8 @@ var listCmd = &cobra.Command{
 		longFormat, _ := cmd.Flags().GetBool("long")
 		sortBy, _ := cmd.Flags().GetString("sort")
 		reverse, _ := cmd.Flags().GetBool("reverse")
+		cursor, _ := cmd.Flags().GetString("cursor")
+		paginate := cmd.Flags().Changed("cursor")
 
 		// Pattern matching flags
 		titleContains, _ := cmd.Flags().GetString("title-contains")
@@ -375,8 +401,16 @@ var listCmd = &cobra.Command{
 			effectiveLimit = 20 // Agent mode default
 		}
 
+		// Cursor pages follow the storage order, so client-side sorting would
+		// reorder each page independently
+		if paginate && sortBy != "" {
+			fmt.Fprintf(os.Stderr, "Error: --cursor cannot be combined with --sort\n")
+			os.Exit(1)
+		}
+
 		filter := types.IssueFilter{
-			Limit: effectiveLimit,
+			Limit:  effectiveLimit,
+			Cursor: cursor,
 		}
 
 		// --ready flag: show only open issues (excludes hooked/in_progress/blocked/deferred) (bd-ihu31)
@@ -628,6 +662,8 @@ var listCmd = &cobra.Command{
 			}
 		}
 
+		nextCursor := filter.NextCursor(issues)
+
 		// Apply sorting
 		sortIssues(issues, sortBy, reverse)
 
@@ -709,6 +745,10 @@ var listCmd = &cobra.Command{
 					CommentCount:    commentCounts[issue.ID],
 				}
 			}
+			if paginate {
+				outputJSON(issuePage{Issues: issuesWithCounts, NextCursor: nextCursor})
+				return
+			}
 			outputJSON(issuesWithCounts)
 			return
 		}
@@ -759,7 +799,9 @@ var listCmd = &cobra.Command{
 		}
 
 		// Show truncation hint if we hit the limit (GH#788)
-		if effectiveLimit > 0 && len(issues) == effectiveLimit {
+		if paginate && nextCursor != "" {
+			fmt.Fprintf(os.Stderr, "\nNext page: fbd list --cursor %s\n", nextCursor)
+		} else if effectiveLimit > 0 && len(issues) == effectiveLimit {
 			fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", effectiveLimit)
 		}
 
@@ -781,6 +823,7 @@ func init() {
 	listCmd.Flags().String("spec", "", "Filter by spec_id prefix")
 	listCmd.Flags().String("id", "", "Filter by specific issue IDs (comma-separated, e.g., bd-1,bd-5,bd-10)")
 	listCmd.Flags().IntP("limit", "n", 50, "Limit results (default 50, use 0 for unlimited)")
+	listCmd.Flags().String("cursor", "", "Page cursor from a previous page's next_cursor (\"\" for the first page); with --json, outputs {issues, next_cursor}")
 	listCmd.Flags().String("format", "", "Output format: 'digraph' (for golang.org/x/tools/cmd/digraph), 'dot' (Graphviz), or Go template")
 	listCmd.Flags().Bool("all", false, "Show all issues including closed (overrides default filter)")
 	listCmd.Flags().Bool("long", false, "Show detailed multi-line output for each issue")
@@ -848,6 +891,9 @@ func init() {
 	// Ready filter: show only issues ready to be worked on (bd-ihu31)
 	listCmd.Flags().Bool("ready", false, "Show only ready issues (status=open, excludes hooked/in_progress/blocked/deferred)")
 
+	// Saved views
+	listCmd.Flags().String("view", "", "Run a saved view from .beads/views.yaml (see 'fbd view')")
+
 	// Note: --json flag is defined as a persistent flag in main.go, not here
 	rootCmd.AddCommand(listCmd)
 }
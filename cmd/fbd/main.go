@@ -201,7 +201,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&sandboxMode, "sandbox", false, "Sandbox mode: disables auto-sync")
 	rootCmd.PersistentFlags().BoolVar(&allowStale, "allow-stale", false, "Allow operations on potentially stale data (skip staleness check)")
 	rootCmd.PersistentFlags().BoolVar(&noDb, "no-db", false, "Use no-db mode: load from JSONL, no SQLite")
-	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false, "Deprecated (no-op): daemon has been removed")
+	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false, "Bypass a running 'fbd serve' daemon and open the database directly")
 	rootCmd.PersistentFlags().BoolVar(&readonlyMode, "readonly", false, "Read-only mode: block write operations (for worker sandboxes)")
 	rootCmd.PersistentFlags().StringVar(&doltAutoCommit, "dolt-auto-commit", "", "Dolt backend: auto-commit after write commands (off|on). Default: on for embedded, off for server mode. Override via config key dolt.auto-commit")
 	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", 30*time.Second, "SQLite busy timeout (0 = fail immediately if locked)")
@@ -403,6 +403,11 @@ var rootCmd = &cobra.Command{
 			return
 		}
 
+		// 'fbd serve status|stop' only talk to the daemon, which may hold the database lock
+		if cmd.HasParent() && cmd.Parent() == serveCmd {
+			return
+		}
+
 		// Skip for root command with no subcommand (just shows help)
 		if cmd.Parent() == nil && cmdName == cmd.Use {
 			return
@@ -597,6 +602,14 @@ var rootCmd = &cobra.Command{
 		// the database file (which breaks file watchers).
 		useReadOnly := isReadOnlyCommand(cmd.Name())
 
+		// Route through a running 'fbd serve' daemon when possible.
+		// The daemon owns the database, so skip migration and opening the store.
+		if connectToDaemon(cmd, args) {
+			hookRunner = hooks.NewRunner(filepath.Join(filepath.Dir(dbPath), "hooks"))
+			syncCommandContext()
+			return
+		}
+
 		// Auto-migrate database on version bump
 		// Skip for read-only commands - they can't write anyway
 		if !useReadOnly {
@@ -832,6 +845,7 @@ var rootCmd = &cobra.Command{
 		if store != nil {
 			_ = store.Close()
 		}
+		closeDaemonClient()
 
 		if profileFile != nil {
 			pprof.StopCPUProfile()
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/timeparsing"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/utils"
+)
+
+// metricsAgingLimit caps the aging rows shown per group in table output.
+const metricsAgingLimit = 10
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: "views",
+	Short:   "Flow metrics: throughput, cycle time, lead time and WIP",
+	Long: `Report flow metrics computed from the status changes in the event log.
+
+Per period (--period day, week or month) the report shows:
+  throughput   issues closed
+  cycle time   in_progress to closed, for issues closed in the period
+  lead time    created to closed, for issues closed in the period
+  WIP          issues in progress at the end of the period
+
+Cycle and lead times are in hours, as count, mean and 50th/85th/95th
+percentiles. The report also lists the age of work still in progress at
+the end of the window.
+
+Filter the issues with --type, --label, --assignee and --priority, and
+break the report down with --by type, label, assignee or priority.
+
+Output formats:
+  table (default)  Summary and period table per group
+  json             Same as --json
+  csv              One row per group and period
+
+Examples:
+  fbd metrics                                  # Last 4 weeks, weekly
+  fbd metrics --since -12w --by assignee
+  fbd metrics --since 2026-01-01 --period month --type bug
+  fbd metrics --by label --format csv > metrics.csv`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		untilStr, _ := cmd.Flags().GetString("until")
+		period, _ := cmd.Flags().GetString("period")
+		groupBy, _ := cmd.Flags().GetString("by")
+
+		if jsonOutput {
+			format = "json"
+		}
+		if format != "table" && format != "json" && format != "csv" {
+			FatalErrorRespectJSON("invalid format %q (must be table, json or csv)", format)
+		}
+
+		now := time.Now()
+		opts := metrics.Options{Period: period, GroupBy: groupBy, Until: now}
+		var err error
+		if opts.Since, err = parseSinceFlag(sinceStr, now); err != nil {
+			FatalErrorRespectJSON("invalid --since: %v", err)
+		}
+		if untilStr != "" {
+			if opts.Until, err = timeparsing.ParseRelativeTime(untilStr, now); err != nil {
+				FatalErrorRespectJSON("invalid --until: %v", err)
+			}
+		}
+
+		filter := flowFilterFromFlags(cmd)
+
+		if err := ensureDirectMode("metrics requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		requireFreshDB(ctx)
+
+		issues, events, err := loadMetricsInputs(ctx, store, filter, groupBy == metrics.GroupByLabel)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		report, err := metrics.Compute(issues, events, opts)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		switch format {
+		case "json":
+			outputJSON(report)
+		case "csv":
+			if err := report.WriteCSV(os.Stdout); err != nil {
+				FatalError("writing CSV: %v", err)
+			}
+		default:
+			renderMetricsTable(os.Stdout, report)
+		}
+	},
+}
+
+// loadMetricsInputs loads the issues matching filter, in any status, and
+// the events recorded for them.
+func loadMetricsInputs(ctx context.Context, s storage.Storage, filter types.IssueFilter, withLabels bool) ([]*types.Issue, []*types.Event, error) {
+	issues, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+	if withLabels {
+		idList := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			idList = append(idList, issue.ID)
+		}
+		labels, err := s.GetLabelsForIssues(ctx, idList)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load labels: %w", err)
+		}
+		for _, issue := range issues {
+			issue.Labels = labels[issue.ID]
+		}
+	}
+
+	events, err := loadIssueEvents(ctx, s, issues)
+	if err != nil {
+		return nil, nil, err
+	}
+	return issues, events, nil
+}
+
+// loadIssueEvents loads the events recorded for issues.
+func loadIssueEvents(ctx context.Context, s storage.Storage, issues []*types.Issue) ([]*types.Event, error) {
+	ids := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		ids[issue.ID] = true
+	}
+	all, err := s.GetAllEventsSince(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	events := make([]*types.Event, 0, len(all))
+	for _, e := range all {
+		if ids[e.IssueID] {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// parseSinceFlag parses a window start. A compact duration without a sign,
+// like "30d", looks back from now rather than ahead.
+func parseSinceFlag(s string, now time.Time) (time.Time, error) {
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		if t, err := timeparsing.ParseCompactDuration("-"+s, now); err == nil {
+			return t, nil
+		}
+	}
+	return timeparsing.ParseRelativeTime(s, now)
+}
+
+// addFlowFilterFlags registers the issue filters shared by the flow reports.
+func addFlowFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("type", "t", "", "Only issues of this type")
+	cmd.Flags().StringSliceP("label", "l", nil, "Only issues with all of these labels")
+	cmd.Flags().StringP("assignee", "a", "", "Only issues with this assignee")
+	cmd.Flags().IntP("priority", "p", 0, "Only issues with this priority")
+}
+
+// flowFilterFromFlags builds an issue filter, across all statuses, from the
+// flags registered by addFlowFilterFlags.
+func flowFilterFromFlags(cmd *cobra.Command) types.IssueFilter {
+	issueType, _ := cmd.Flags().GetString("type")
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	assignee, _ := cmd.Flags().GetString("assignee")
+
+	filter := types.IssueFilter{Labels: utils.NormalizeLabels(labels)}
+	if issueType != "" {
+		t := types.IssueType(utils.NormalizeIssueType(issueType))
+		filter.IssueType = &t
+	}
+	if assignee != "" {
+		filter.Assignee = &assignee
+	}
+	if cmd.Flags().Changed("priority") {
+		priority, _ := cmd.Flags().GetInt("priority")
+		filter.Priority = &priority
+	}
+	return filter
+}
+
+// renderMetricsTable prints each group's summary, period table and oldest
+// in-progress work.
+func renderMetricsTable(w io.Writer, report *metrics.Report) {
+	dateLayout := "2006-01-02"
+	fmt.Fprintf(w, "\n%s Flow metrics %s → %s (per %s)\n", ui.RenderAccent("📈"),
+		report.Since.Format(dateLayout), report.Until.Format(dateLayout), report.Period)
+	if len(report.Groups) == 0 {
+		fmt.Fprintln(w, "\nNo matching issues")
+		return
+	}
+
+	hours := func(h float64) string {
+		return formatCompactMinutes(int(h*60 + 0.5))
+	}
+	summary := func(s metrics.Summary) string {
+		if s.Count == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("n=%d  mean %s  p50 %s  p85 %s  p95 %s", s.Count, hours(s.Mean), hours(s.P50), hours(s.P85), hours(s.P95))
+	}
+	cell := func(s metrics.Summary, v float64) string {
+		if s.Count == 0 {
+			return "-"
+		}
+		return hours(v)
+	}
+
+	for _, g := range report.Groups {
+		name := g.Name
+		if report.GroupBy != "" {
+			name = report.GroupBy + " " + name
+		}
+		fmt.Fprintf(w, "\n%s  %d issues, %d closed in window\n", ui.RenderBold(name), g.Issues, g.Throughput)
+		fmt.Fprintf(w, "  Cycle time:  %s\n", summary(g.CycleTime))
+		fmt.Fprintf(w, "  Lead time:   %s\n\n", summary(g.LeadTime))
+
+		rows := [][]string{{"PERIOD", "CLOSED", "WIP", "CYCLE P50", "CYCLE P85", "LEAD P50", "LEAD P85"}}
+		for _, p := range g.Periods {
+			rows = append(rows, []string{
+				p.Start.Format(dateLayout),
+				fmt.Sprint(p.Throughput),
+				fmt.Sprint(p.WIP),
+				cell(p.CycleTime, p.CycleTime.P50),
+				cell(p.CycleTime, p.CycleTime.P85),
+				cell(p.LeadTime, p.LeadTime.P50),
+				cell(p.LeadTime, p.LeadTime.P85),
+			})
+		}
+		fmt.Fprint(w, formatTable(rows))
+
+		if len(g.Aging) > 0 {
+			fmt.Fprintf(w, "\n  In progress (oldest first):\n")
+			for i, item := range g.Aging {
+				if i == metricsAgingLimit {
+					fmt.Fprintf(w, "    ... and %d more\n", len(g.Aging)-metricsAgingLimit)
+					break
+				}
+				assignee := item.Assignee
+				if assignee == "" {
+					assignee = metrics.NoValue
+				}
+				fmt.Fprintf(w, "    %-6s %s  %s  %s\n", hours(item.AgeHours), ui.RenderID(item.ID), assignee, item.Title)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func init() {
+	metricsCmd.Flags().String("format", "table", "Output format: table, json, csv")
+	metricsCmd.Flags().String("since", "-4w", "Window start, e.g. 12w or 2026-01-01 (rounded down to the start of its period)")
+	metricsCmd.Flags().String("until", "", "Window end (default now)")
+	metricsCmd.Flags().String("period", metrics.PeriodWeek, "Reporting period: day, week, month")
+	metricsCmd.Flags().String("by", "", "Break down by: type, label, assignee, priority")
+	addFlowFilterFlags(metricsCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/metrics"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestLoadMetricsInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newTestStore(t, filepath.Join(tmpDir, ".beads", "beads.db"))
+	ctx := context.Background()
+
+	create := func(title string, issueType types.IssueType, labels ...string) *types.Issue {
+		issue := &types.Issue{Title: title, Priority: 2, IssueType: issueType, Status: types.StatusOpen}
+		if err := s.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		for _, label := range labels {
+			if err := s.AddLabel(ctx, issue.ID, label, "test-user"); err != nil {
+				t.Fatalf("AddLabel: %v", err)
+			}
+		}
+		return issue
+	}
+
+	task := create("Task", types.TypeTask, "backend")
+	bug := create("Bug", types.TypeBug)
+	if err := s.UpdateIssue(ctx, task.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test-user"); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if err := s.CloseIssue(ctx, task.ID, "done", "test-user", ""); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+
+	bugType := types.TypeBug
+	issues, events, err := loadMetricsInputs(ctx, s, types.IssueFilter{IssueType: &bugType}, false)
+	if err != nil {
+		t.Fatalf("loadMetricsInputs: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != bug.ID {
+		t.Fatalf("expected only the bug, got %d issues", len(issues))
+	}
+	for _, e := range events {
+		if e.IssueID != bug.ID {
+			t.Errorf("event for filtered-out issue %s", e.IssueID)
+		}
+	}
+
+	issues, events, err = loadMetricsInputs(ctx, s, types.IssueFilter{}, true)
+	if err != nil {
+		t.Fatalf("loadMetricsInputs: %v", err)
+	}
+	now := time.Now()
+	report, err := metrics.Compute(issues, events, metrics.Options{Since: now.Add(-time.Hour), Until: now.Add(time.Minute), GroupBy: metrics.GroupByLabel})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	got := map[string]int{}
+	for _, g := range report.Groups {
+		got[g.Name] = g.Throughput
+	}
+	if got["backend"] != 1 || got[metrics.NoValue] != 0 || len(got) != 2 {
+		t.Errorf("throughput by label = %v, want backend=1, %s=0", got, metrics.NoValue)
+	}
+	if report.Groups[0].CycleTime.Count != 1 {
+		t.Errorf("expected a cycle time from the status events, got %+v", report.Groups[0].CycleTime)
+	}
+}
@@ -53,7 +53,7 @@ func handleToDoltMigration(dryRun bool, autoYes bool) {
 	if isRunning, pid := isDaemonRunning(pidFile); isRunning {
 		exitWithError("daemon_running",
 			fmt.Sprintf("fbd daemon is running (PID %d). Stop it before migrating to avoid race conditions.", pid),
-			"run 'fbd daemon stop' first, then retry migration")
+			"run 'fbd serve stop' first, then retry migration")
 	}
 
 	// Load config
@@ -51,6 +51,7 @@ Commands:
   pour       Instantiate proto as persistent mol (liquid phase)
   wisp       Instantiate proto as ephemeral wisp (vapor phase)
   bond       Polymorphic combine: proto+proto, proto+mol, mol+mol
+  advance    Evaluate loop and gate conditions of a molecule
   squash     Condense molecule to digest
   burn       Discard wisp
   distill    Extract proto from ad-hoc epic
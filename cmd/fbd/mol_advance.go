@@ -0,0 +1,676 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/formula"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/utils"
+)
+
+// Runtime control flow recorded on cooked steps.
+//
+// Every cooked step stores its formula step ID under "step" in the issue
+// metadata, since poured issues get fresh IDs and conditions refer to steps
+// by their formula ID. The first step of each iteration of a conditional
+// loop also stores the loop's until/max under "loop". Gate rules become gate
+// issues of type "condition" that block the gated step.
+const (
+	stepIDMetadataKey = "step"
+	loopMetadataKey   = "loop"
+
+	// conditionGateType is the await type of gates created from compose.gate.
+	// The gate's AwaitID holds the condition expression.
+	conditionGateType = "condition"
+)
+
+// loopControl is the runtime part of a conditional loop (loop.until + max).
+type loopControl struct {
+	Until string `json:"until"`
+	Max   int    `json:"max"`
+}
+
+// loopIterationPattern splits the ID of a conditional loop's first step,
+// <loop>.iter<N>.<body>, into loop ID and iteration number.
+var loopIterationPattern = regexp.MustCompile(`^(.+)\.iter(\d+)\.[^.]+$`)
+
+// stepMetadata returns the metadata recorded on a cooked step issue: the
+// formula step ID, plus any on_complete spec and conditional loop control.
+func stepMetadata(step *formula.Step) json.RawMessage {
+	meta := map[string]interface{}{stepIDMetadataKey: step.ID}
+	if step.OnComplete != nil {
+		meta[onCompleteMetadataKey] = step.OnComplete
+	}
+	if loop := loopControlLabel(step.Labels); loop != nil {
+		meta[loopMetadataKey] = loop
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// loopControlLabel parses the loop:{...} label added to the first step of a
+// conditional loop by formula.ApplyLoops.
+func loopControlLabel(labels []string) *loopControl {
+	for _, label := range labels {
+		if data, ok := strings.CutPrefix(label, "loop:{"); ok {
+			var loop loopControl
+			if err := json.Unmarshal([]byte("{"+data), &loop); err == nil && loop.Until != "" {
+				return &loop
+			}
+		}
+	}
+	return nil
+}
+
+// gateConditionLabel parses the gate:{"condition": ...} label added to a step
+// by compose.gate. Plain gate:<value> labels from waits_for are ignored.
+func gateConditionLabel(labels []string) string {
+	for _, label := range labels {
+		if data, ok := strings.CutPrefix(label, "gate:{"); ok {
+			var gate struct {
+				Condition string `json:"condition"`
+			}
+			if err := json.Unmarshal([]byte("{"+data), &gate); err == nil && gate.Condition != "" {
+				return gate.Condition
+			}
+		}
+	}
+	return ""
+}
+
+// createConditionGateIssue creates the gate issue for a compose.gate rule.
+// ID: {parentID}.cond-{step.ID}
+func createConditionGateIssue(step *formula.Step, parentID, condition string) *types.Issue {
+	return &types.Issue{
+		ID:          fmt.Sprintf("%s.cond-%s", parentID, step.ID),
+		Title:       fmt.Sprintf("Gate: %s", condition),
+		Description: fmt.Sprintf("Condition gate for step %s (resolved by 'fbd mol advance')", step.ID),
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   "gate",
+		AwaitType:   conditionGateType,
+		AwaitID:     condition,
+		IsTemplate:  true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// runtimeStep is a poured step issue with its decoded control metadata.
+type runtimeStep struct {
+	issue  *types.Issue
+	stepID string
+	loop   *loopControl
+	state  *formula.StepState
+}
+
+// parseRuntimeStep decodes the step metadata of an issue. Returns nil for
+// issues that were not cooked from a formula step.
+func parseRuntimeStep(issue *types.Issue) *runtimeStep {
+	if len(issue.Metadata) == 0 {
+		return nil
+	}
+	var meta struct {
+		Step   string          `json:"step"`
+		Loop   *loopControl    `json:"loop"`
+		Output json.RawMessage `json:"output"`
+	}
+	if err := json.Unmarshal(issue.Metadata, &meta); err != nil || meta.Step == "" {
+		return nil
+	}
+	state := &formula.StepState{ID: meta.Step, Status: stepStateStatus(issue)}
+	if len(meta.Output) > 0 {
+		// Non-object output can't be addressed by output.<path>; leave it unset
+		_ = json.Unmarshal(meta.Output, &state.Output)
+	}
+	return &runtimeStep{issue: issue, stepID: meta.Step, loop: meta.Loop, state: state}
+}
+
+// stepStateStatus maps an issue's status to a condition step status.
+func stepStateStatus(issue *types.Issue) string {
+	switch issue.Status {
+	case types.StatusClosed:
+		if types.IsFailureClose(issue.CloseReason) {
+			return "failed"
+		}
+		return "complete"
+	case types.StatusInProgress:
+		return "in_progress"
+	default:
+		return "pending"
+	}
+}
+
+// moleculeRuntime is the live state of a poured molecule, as seen by
+// condition evaluation.
+type moleculeRuntime struct {
+	subgraph *TemplateSubgraph
+	steps    map[string]*formula.StepState // formula step ID -> state
+	byIssue  map[string]*runtimeStep       // issue ID -> step
+}
+
+// loadMoleculeRuntime builds step states from a molecule's descendants.
+// Children are linked through parent-child dependencies. If the same step ID
+// appears more than once (e.g. in bonded sub-molecules), the first wins.
+func loadMoleculeRuntime(ctx context.Context, s storage.Storage, moleculeID string) (*moleculeRuntime, error) {
+	subgraph, err := loadTemplateSubgraph(ctx, s, moleculeID)
+	if err != nil {
+		return nil, err
+	}
+	rt := &moleculeRuntime{
+		subgraph: subgraph,
+		steps:    make(map[string]*formula.StepState),
+		byIssue:  make(map[string]*runtimeStep),
+	}
+	for i, issue := range subgraph.Issues {
+		// Descendants come from dependency queries, which don't carry
+		// metadata or close reasons; reload the full issue
+		full, err := s.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", issue.ID, err)
+		}
+		if full == nil {
+			continue
+		}
+		issue = full
+		subgraph.Issues[i] = full
+		subgraph.IssueMap[full.ID] = full
+
+		step := parseRuntimeStep(issue)
+		if step == nil {
+			continue
+		}
+		rt.byIssue[issue.ID] = step
+		if _, exists := rt.steps[step.stepID]; !exists {
+			rt.steps[step.stepID] = step.state
+		}
+	}
+	for _, dep := range subgraph.Dependencies {
+		if dep.Type != types.DepParentChild {
+			continue
+		}
+		child, parent := rt.byIssue[dep.IssueID], rt.byIssue[dep.DependsOnID]
+		if child != nil && parent != nil {
+			parent.state.Children = append(parent.state.Children, child.state)
+		}
+	}
+	return rt, nil
+}
+
+// AdvanceAction is one gate or loop evaluated by 'fbd mol advance'.
+type AdvanceAction struct {
+	Kind      string   `json:"kind"` // "gate" or "loop"
+	ID        string   `json:"id"`   // Gate issue ID, or loop step ID
+	Condition string   `json:"condition"`
+	Result    string   `json:"result"` // closed, waiting, running, unrolled, done, max_reached, error
+	Reason    string   `json:"reason,omitempty"`
+	Iteration int      `json:"iteration,omitempty"` // Latest loop iteration before this run
+	Created   []string `json:"created,omitempty"`   // Issues created for the next iteration
+}
+
+// AdvanceResult is the outcome of 'fbd mol advance'.
+type AdvanceResult struct {
+	MoleculeID string           `json:"molecule_id"`
+	DryRun     bool             `json:"dry_run,omitempty"`
+	Actions    []*AdvanceAction `json:"actions"`
+}
+
+// Advance action results.
+const (
+	advanceClosed     = "closed"
+	advanceWaiting    = "waiting"
+	advanceRunning    = "running"
+	advanceUnrolled   = "unrolled"
+	advanceDone       = "done"
+	advanceMaxReached = "max_reached"
+	advanceError      = "error"
+)
+
+var molAdvanceCmd = &cobra.Command{
+	Use:   "advance <molecule-id>",
+	Short: "Evaluate loop and gate conditions of a molecule",
+	Long: `Evaluate the runtime conditions of a poured molecule and act on them.
+
+Conditions are evaluated against the molecule's steps: a step is pending,
+in_progress, complete or failed (closed with a failure reason), and its output
+is the "output" object in the step's metadata.
+
+Condition gates (compose.gate):
+  An open gate whose condition holds is closed, unblocking the gated step.
+  "step" refers to the gated step.
+
+Conditional loops (loop.until with max):
+  Once every step of the latest iteration is closed, the until condition is
+  evaluated. If it does not hold and fewer than max iterations have run, the
+  next iteration is created and chained after the previous one. "step" refers
+  to the latest iteration as a whole: its status is complete when all of its
+  steps are, output merges their outputs, and children are its steps.
+
+Advancing is idempotent: run it whenever steps close (e.g. from a patrol).
+
+Examples:
+  fbd mol advance bd-abc123
+  fbd mol advance bd-abc123 --dry-run
+  fbd mol advance bd-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMolAdvance,
+}
+
+func runMolAdvance(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !dryRun {
+		CheckReadonly("mol advance")
+	}
+	if err := ensureDirectMode("mol advance requires direct database access"); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	ctx := rootCtx
+
+	moleculeID, err := utils.ResolvePartialID(ctx, store, args[0])
+	if err != nil {
+		FatalErrorRespectJSON("molecule '%s' not found", args[0])
+	}
+
+	result, err := advanceMolecule(ctx, store, moleculeID, actor, dryRun)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if jsonOutput {
+		outputJSON(result)
+	} else {
+		printAdvanceResult(result)
+	}
+	for _, action := range result.Actions {
+		if action.Result == advanceError {
+			os.Exit(1)
+		}
+	}
+}
+
+// advanceMolecule evaluates every open condition gate and every conditional
+// loop of a molecule. Satisfied gates are closed; loops whose latest
+// iteration is complete either finish or get their next iteration.
+func advanceMolecule(ctx context.Context, s storage.Storage, moleculeID, actorName string, dryRun bool) (*AdvanceResult, error) {
+	rt, err := loadMoleculeRuntime(ctx, s, moleculeID)
+	if err != nil {
+		return nil, err
+	}
+	result := &AdvanceResult{MoleculeID: moleculeID, DryRun: dryRun, Actions: []*AdvanceAction{}}
+
+	for _, issue := range rt.subgraph.Issues {
+		if issue.AwaitType != conditionGateType || issue.Status == types.StatusClosed {
+			continue
+		}
+		action := rt.evaluateGate(issue)
+		if action.Result == advanceClosed && !dryRun {
+			reason := "Condition met: " + action.Reason
+			if err := s.CloseIssue(ctx, issue.ID, reason, actorName, ""); err != nil {
+				action.Result, action.Reason = advanceError, fmt.Sprintf("closing gate: %v", err)
+			}
+		}
+		result.Actions = append(result.Actions, action)
+	}
+
+	for _, loop := range rt.latestIterations() {
+		action := rt.evaluateLoop(loop)
+		if action.Result == advanceUnrolled && !dryRun {
+			created, err := rt.unrollIteration(ctx, s, loop, actorName)
+			if err != nil {
+				action.Result, action.Reason = advanceError, fmt.Sprintf("unrolling iteration %d: %v", loop.iteration+1, err)
+			}
+			action.Created = created
+		}
+		result.Actions = append(result.Actions, action)
+	}
+	return result, nil
+}
+
+// evaluateGate evaluates a condition gate. "step" refers to the step the gate
+// blocks.
+func (rt *moleculeRuntime) evaluateGate(gate *types.Issue) *AdvanceAction {
+	action := &AdvanceAction{Kind: "gate", ID: gate.ID, Condition: gate.AwaitID}
+	condCtx := &formula.ConditionContext{Steps: rt.steps}
+	for _, dep := range rt.subgraph.Dependencies {
+		if dep.DependsOnID == gate.ID && dep.Type == types.DepBlocks {
+			if step := rt.byIssue[dep.IssueID]; step != nil {
+				condCtx.CurrentStep = step.stepID
+				break
+			}
+		}
+	}
+	satisfied, reason, err := evaluateRuntimeCondition(gate.AwaitID, condCtx)
+	switch {
+	case err != nil:
+		action.Result, action.Reason = advanceError, err.Error()
+	case satisfied:
+		action.Result, action.Reason = advanceClosed, reason
+	default:
+		action.Result, action.Reason = advanceWaiting, reason
+	}
+	return action
+}
+
+// evaluateRuntimeCondition parses and evaluates a condition.
+func evaluateRuntimeCondition(expr string, condCtx *formula.ConditionContext) (bool, string, error) {
+	cond, err := formula.ParseCondition(expr)
+	if err != nil {
+		return false, "", err
+	}
+	res, err := cond.Evaluate(condCtx)
+	if err != nil {
+		return false, "", err
+	}
+	return res.Satisfied, res.Reason, nil
+}
+
+// loopIteration is the latest poured iteration of a conditional loop.
+type loopIteration struct {
+	loopID    string
+	iteration int
+	control   *loopControl
+	first     *runtimeStep   // Step carrying the loop control
+	members   []*types.Issue // Steps of the iteration and the gates blocking them
+}
+
+// contains reports whether an issue belongs to the iteration.
+func (loop *loopIteration) contains(issueID string) bool {
+	for _, issue := range loop.members {
+		if issue.ID == issueID {
+			return true
+		}
+	}
+	return false
+}
+
+// latestIterations finds the latest iteration of each conditional loop, in
+// molecule order.
+func (rt *moleculeRuntime) latestIterations() []*loopIteration {
+	latest := make(map[string]*loopIteration)
+	var order []string
+	for _, issue := range rt.subgraph.Issues {
+		step := rt.byIssue[issue.ID]
+		if step == nil || step.loop == nil {
+			continue
+		}
+		m := loopIterationPattern.FindStringSubmatch(step.stepID)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		cur, seen := latest[m[1]]
+		if !seen {
+			order = append(order, m[1])
+		}
+		if !seen || n > cur.iteration {
+			latest[m[1]] = &loopIteration{loopID: m[1], iteration: n, control: step.loop, first: step}
+		}
+	}
+
+	loops := make([]*loopIteration, 0, len(order))
+	for _, loopID := range order {
+		loop := latest[loopID]
+		prefix := fmt.Sprintf("%s.iter%d.", loop.loopID, loop.iteration)
+		inIteration := make(map[string]bool)
+		for _, issue := range rt.subgraph.Issues {
+			if step := rt.byIssue[issue.ID]; step != nil && strings.HasPrefix(step.stepID, prefix) {
+				loop.members = append(loop.members, issue)
+				inIteration[issue.ID] = true
+			}
+		}
+		// Gates blocking the iteration's steps are part of it too
+		for _, dep := range rt.subgraph.Dependencies {
+			gate := rt.subgraph.IssueMap[dep.DependsOnID]
+			if dep.Type == types.DepBlocks && inIteration[dep.IssueID] && gate != nil &&
+				gate.IssueType == "gate" && !inIteration[gate.ID] {
+				loop.members = append(loop.members, gate)
+				inIteration[gate.ID] = true
+			}
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
+
+// iterationState summarizes a loop iteration as a single step state: complete
+// once all of its steps are (failed if any failed), with their outputs merged
+// and its top-level steps as children.
+func (rt *moleculeRuntime) iterationState(loop *loopIteration) (*formula.StepState, bool) {
+	state := &formula.StepState{ID: loop.loopID, Status: "complete", Output: map[string]interface{}{}}
+	nested := make(map[string]bool)
+	for _, dep := range rt.subgraph.Dependencies {
+		if dep.Type == types.DepParentChild && loop.contains(dep.DependsOnID) {
+			nested[dep.IssueID] = true
+		}
+	}
+	closed := true
+	for _, issue := range loop.members {
+		step := rt.byIssue[issue.ID]
+		if issue.Status != types.StatusClosed {
+			closed = false
+		}
+		if step == nil {
+			continue
+		}
+		switch {
+		case step.state.Status == "failed":
+			state.Status = "failed"
+		case step.state.Status != "complete" && state.Status == "complete":
+			state.Status = step.state.Status
+		}
+		for k, v := range step.state.Output {
+			state.Output[k] = v
+		}
+		if !nested[issue.ID] {
+			state.Children = append(state.Children, step.state)
+		}
+	}
+	return state, closed
+}
+
+// evaluateLoop decides what to do with a loop's latest iteration.
+func (rt *moleculeRuntime) evaluateLoop(loop *loopIteration) *AdvanceAction {
+	action := &AdvanceAction{Kind: "loop", ID: loop.loopID, Condition: loop.control.Until, Iteration: loop.iteration}
+	state, closed := rt.iterationState(loop)
+	if !closed {
+		action.Result = advanceRunning
+		action.Reason = fmt.Sprintf("iteration %d still has open steps", loop.iteration)
+		return action
+	}
+
+	// "step" is the iteration; the loop ID doesn't name a poured step
+	steps := make(map[string]*formula.StepState, len(rt.steps)+1)
+	for id, st := range rt.steps {
+		steps[id] = st
+	}
+	steps[loop.loopID] = state
+	satisfied, reason, err := evaluateRuntimeCondition(loop.control.Until, &formula.ConditionContext{
+		Steps:       steps,
+		CurrentStep: loop.loopID,
+	})
+	switch {
+	case err != nil:
+		action.Result, action.Reason = advanceError, err.Error()
+	case satisfied:
+		action.Result, action.Reason = advanceDone, reason
+	case loop.iteration >= loop.control.Max:
+		action.Result = advanceMaxReached
+		action.Reason = fmt.Sprintf("until not met after %d iterations (%s)", loop.iteration, reason)
+	default:
+		action.Result, action.Reason = advanceUnrolled, reason
+	}
+	return action
+}
+
+// unrollIteration creates iteration N+1 of a loop by copying iteration N:
+// steps start open with their output cleared, internal dependencies are
+// remapped, the new iteration waits for the old one, and anything that
+// waited for the old iteration now waits for the new one as well.
+func (rt *moleculeRuntime) unrollIteration(ctx context.Context, s storage.Storage, loop *loopIteration, actorName string) ([]string, error) {
+	oldPrefix := fmt.Sprintf("%s.iter%d.", loop.loopID, loop.iteration)
+	newPrefix := fmt.Sprintf("%s.iter%d.", loop.loopID, loop.iteration+1)
+
+	inIteration := make(map[string]bool, len(loop.members))
+	for _, issue := range loop.members {
+		inIteration[issue.ID] = true
+	}
+	// Sinks are the old steps no other old step waits for
+	hasDependents := make(map[string]bool)
+	for _, dep := range rt.subgraph.Dependencies {
+		if inIteration[dep.IssueID] && inIteration[dep.DependsOnID] {
+			hasDependents[dep.DependsOnID] = true
+		}
+	}
+
+	// Same distinct prefixes as pour and wisp
+	prefix := types.IDPrefixMol
+	if loop.first.issue.Ephemeral {
+		prefix = types.IDPrefixWisp
+	}
+
+	idMapping := make(map[string]string, len(loop.members))
+	var created []string
+	err := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		for _, old := range loop.members {
+			metadata, err := nextIterationMetadata(old.Metadata, oldPrefix, newPrefix)
+			if err != nil {
+				return fmt.Errorf("metadata of %s: %w", old.ID, err)
+			}
+			issue := &types.Issue{
+				Title:              old.Title,
+				Description:        old.Description,
+				Design:             old.Design,
+				AcceptanceCriteria: old.AcceptanceCriteria,
+				Notes:              old.Notes,
+				Status:             types.StatusOpen,
+				Priority:           old.Priority,
+				IssueType:          old.IssueType,
+				Assignee:           old.Assignee,
+				EstimatedMinutes:   old.EstimatedMinutes,
+				Ephemeral:          old.Ephemeral,
+				IDPrefix:           prefix,
+				AwaitType:          old.AwaitType,
+				AwaitID:            old.AwaitID,
+				Timeout:            old.Timeout,
+				Metadata:           metadata,
+			}
+			if err := tx.CreateIssue(ctx, issue, actorName); err != nil {
+				return fmt.Errorf("failed to create issue from %s: %w", old.ID, err)
+			}
+			idMapping[old.ID] = issue.ID
+			created = append(created, issue.ID)
+		}
+
+		var deps []*types.Dependency
+		for _, dep := range rt.subgraph.Dependencies {
+			from, fromOld := idMapping[dep.IssueID]
+			to, toOld := idMapping[dep.DependsOnID]
+			switch {
+			case fromOld && toOld:
+				deps = append(deps, &types.Dependency{IssueID: from, DependsOnID: to, Type: dep.Type})
+			case fromOld:
+				// Parent and steps before the loop stay the same; the chain to
+				// the previous iteration is replaced below
+				if prev := rt.byIssue[dep.DependsOnID]; prev != nil && strings.HasPrefix(prev.stepID, loop.loopID+".iter") {
+					continue
+				}
+				deps = append(deps, &types.Dependency{IssueID: from, DependsOnID: dep.DependsOnID, Type: dep.Type})
+			case toOld && dep.Type != types.DepParentChild:
+				// Steps after the loop wait for the new iteration too
+				deps = append(deps, &types.Dependency{IssueID: dep.IssueID, DependsOnID: to, Type: dep.Type})
+			}
+		}
+		for _, old := range loop.members {
+			if !hasDependents[old.ID] {
+				deps = append(deps, &types.Dependency{
+					IssueID:     idMapping[loop.first.issue.ID],
+					DependsOnID: old.ID,
+					Type:        types.DepBlocks,
+				})
+			}
+		}
+		for _, dep := range deps {
+			if err := tx.AddDependency(ctx, dep, actorName); err != nil {
+				return fmt.Errorf("failed to link %s to %s: %w", dep.IssueID, dep.DependsOnID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// nextIterationMetadata rewrites a step's metadata for the next iteration:
+// the step ID moves to the new iteration and the output is dropped. Gates
+// and other issues without step metadata keep theirs unchanged.
+func nextIterationMetadata(metadata json.RawMessage, oldPrefix, newPrefix string) (json.RawMessage, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(metadata, &meta); err != nil {
+		return metadata, nil //nolint:nilerr // Free-form metadata is copied as-is
+	}
+	stepID, ok := meta[stepIDMetadataKey].(string)
+	if !ok {
+		return metadata, nil
+	}
+	if rest, ok := strings.CutPrefix(stepID, oldPrefix); ok {
+		meta[stepIDMetadataKey] = newPrefix + rest
+	}
+	delete(meta, stepOutputMetadataKey)
+	return json.Marshal(meta)
+}
+
+// printAdvanceResult prints what 'fbd mol advance' did (or would do).
+func printAdvanceResult(result *AdvanceResult) {
+	if len(result.Actions) == 0 {
+		fmt.Printf("No condition gates or conditional loops in %s\n", result.MoleculeID)
+		return
+	}
+	would := ""
+	if result.DryRun {
+		would = "would be "
+	}
+	for _, a := range result.Actions {
+		label := fmt.Sprintf("%s %s", a.Kind, a.ID)
+		switch a.Result {
+		case advanceClosed:
+			fmt.Printf("%s Gate %s %sclosed: %s\n", ui.RenderPass("✓"), a.ID, would, a.Reason)
+		case advanceUnrolled:
+			fmt.Printf("%s Loop %s: iteration %d %screated (%s)\n", ui.RenderPass("↻"), a.ID, a.Iteration+1, would, a.Reason)
+			for _, id := range a.Created {
+				fmt.Printf("  %s\n", id)
+			}
+		case advanceDone:
+			fmt.Printf("%s Loop %s done after %d iteration(s): %s\n", ui.RenderPass("✓"), a.ID, a.Iteration, a.Reason)
+		case advanceMaxReached:
+			fmt.Printf("%s Loop %s stopped: %s\n", ui.RenderWarn("⚠"), a.ID, a.Reason)
+		case advanceError:
+			fmt.Printf("%s %s: %s\n", ui.RenderFail("✗"), label, a.Reason)
+		default:
+			fmt.Printf("  %s %s: %s\n", label, a.Result, a.Reason)
+		}
+	}
+}
+
+func init() {
+	molAdvanceCmd.Flags().Bool("dry-run", false, "Show what would change without changing it")
+	molAdvanceCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	molCmd.AddCommand(molAdvanceCmd)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+const retryFormula = `{
+  "formula": "mol-retry",
+  "version": 1,
+  "type": "workflow",
+  "steps": [
+    {"id": "setup", "title": "Setup"},
+    {"id": "retry", "title": "Retry", "needs": ["setup"], "loop": {
+      "until": "step.output.ok == true",
+      "max": 3,
+      "body": [
+        {"id": "attempt", "title": "Attempt"},
+        {"id": "check", "title": "Check", "needs": ["attempt"]}
+      ]
+    }},
+    {"id": "ship", "title": "Ship", "needs": ["setup"]}
+  ],
+  "compose": {"gate": [{"before": "ship", "condition": "setup.output.approved == true"}]}
+}`
+
+func TestAdvanceMolecule(t *testing.T) {
+	dir := t.TempDir()
+	formulaDir := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(formulaDir, "mol-retry.formula.json"), []byte(retryFormula), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	if err := s.SetConfig(ctx, "types.custom", "gate"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	subgraph, err := resolveAndCookFormula("mol-retry", nil)
+	if err != nil {
+		t.Fatalf("cook: %v", err)
+	}
+	poured, err := spawnMolecule(ctx, s, subgraph, nil, "", "test", false, types.IDPrefixMol)
+	if err != nil {
+		t.Fatalf("pour: %v", err)
+	}
+	molID := poured.NewEpicID
+	gateID := poured.IDMapping["mol-retry.cond-ship"]
+	if gateID == "" {
+		t.Fatalf("no condition gate poured: %v", poured.IDMapping)
+	}
+
+	advance := func(dryRun bool) map[string]*AdvanceAction {
+		t.Helper()
+		result, err := advanceMolecule(ctx, s, molID, "test", dryRun)
+		if err != nil {
+			t.Fatalf("advanceMolecule: %v", err)
+		}
+		actions := make(map[string]*AdvanceAction)
+		for _, a := range result.Actions {
+			actions[a.ID] = a
+		}
+		return actions
+	}
+	closeStep := func(id, output string) {
+		t.Helper()
+		if output != "" {
+			issue, err := s.GetIssue(ctx, id)
+			if err != nil || issue == nil {
+				t.Fatalf("GetIssue(%s) = %v, %v", id, issue, err)
+			}
+			meta := string(issue.Metadata[:len(issue.Metadata)-1]) + `,"output":` + output + `}`
+			if err := s.UpdateIssue(ctx, id, map[string]interface{}{"metadata": meta}, "test"); err != nil {
+				t.Fatalf("UpdateIssue(%s): %v", id, err)
+			}
+		}
+		if err := s.CloseIssue(ctx, id, "done", "test", ""); err != nil {
+			t.Fatalf("CloseIssue(%s): %v", id, err)
+		}
+	}
+
+	actions := advance(false)
+	if a := actions[gateID]; a == nil || a.Result != advanceWaiting {
+		t.Fatalf("gate before setup output = %+v, want waiting", a)
+	}
+	if a := actions["retry"]; a == nil || a.Result != advanceRunning || a.Iteration != 1 {
+		t.Fatalf("loop before closing iteration 1 = %+v, want running", a)
+	}
+
+	closeStep(poured.IDMapping["mol-retry.setup"], `{"approved": true}`)
+	closeStep(poured.IDMapping["mol-retry.retry.iter1.attempt"], "")
+	closeStep(poured.IDMapping["mol-retry.retry.iter1.check"], `{"ok": false}`)
+
+	// Dry run reports without changing anything
+	if a := advance(true); a[gateID].Result != advanceClosed || a["retry"].Result != advanceUnrolled {
+		t.Fatalf("dry run = %+v / %+v", a[gateID], a["retry"])
+	}
+	if gate, _ := s.GetIssue(ctx, gateID); gate.Status == types.StatusClosed {
+		t.Fatal("dry run closed the gate")
+	}
+
+	actions = advance(false)
+	if gate, _ := s.GetIssue(ctx, gateID); gate.Status != types.StatusClosed {
+		t.Errorf("gate status = %s, want closed", gate.Status)
+	}
+	loop := actions["retry"]
+	if loop == nil || loop.Result != advanceUnrolled || len(loop.Created) != 2 {
+		t.Fatalf("loop after iteration 1 = %+v, want iteration 2 with 2 steps", loop)
+	}
+
+	// Iteration 2 waits for iteration 1 and keeps the loop control
+	rt, err := loadMoleculeRuntime(ctx, s, molID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attempt2, check2 string
+	for id, step := range rt.byIssue {
+		switch step.stepID {
+		case "retry.iter2.attempt":
+			attempt2 = id
+			if step.loop == nil || step.loop.Max != 3 {
+				t.Errorf("iteration 2 lost its loop control: %+v", step.loop)
+			}
+		case "retry.iter2.check":
+			check2 = id
+			if step.state.Output != nil {
+				t.Errorf("iteration 2 copied output %v", step.state.Output)
+			}
+		}
+	}
+	if attempt2 == "" || check2 == "" {
+		t.Fatalf("iteration 2 steps not found among %v", loop.Created)
+	}
+	blockers := map[string]bool{}
+	deps, _ := s.GetDependencyRecords(ctx, attempt2)
+	for _, dep := range deps {
+		if dep.Type == types.DepBlocks {
+			blockers[dep.DependsOnID] = true
+		}
+	}
+	if !blockers[poured.IDMapping["mol-retry.retry.iter1.check"]] || len(blockers) != 1 {
+		t.Errorf("iteration 2 attempt blocks on %v, want only iteration 1 check", blockers)
+	}
+
+	// Advancing again is a no-op while iteration 2 runs
+	if a := advance(false)["retry"]; a.Result != advanceRunning || a.Iteration != 2 {
+		t.Errorf("loop during iteration 2 = %+v, want running", a)
+	}
+
+	closeStep(attempt2, "")
+	closeStep(check2, `{"ok": true}`)
+	if a := advance(false)["retry"]; a.Result != advanceDone || a.Iteration != 2 {
+		t.Errorf("loop after ok iteration 2 = %+v, want done", a)
+	}
+}
+
+func TestAdvanceMolecule_MaxReached(t *testing.T) {
+	rt := &moleculeRuntime{subgraph: &TemplateSubgraph{}, byIssue: map[string]*runtimeStep{}}
+	issue := &types.Issue{ID: "bd-1", Status: types.StatusClosed}
+	step := parseRuntimeStep(&types.Issue{ID: "bd-1", Status: types.StatusClosed,
+		Metadata: []byte(`{"step": "retry.iter3.attempt", "loop": {"until": "step.output.ok == true", "max": 3}}`)})
+	rt.byIssue[issue.ID] = step
+
+	action := rt.evaluateLoop(&loopIteration{loopID: "retry", iteration: 3, control: step.loop, first: step, members: []*types.Issue{issue}})
+	if action.Result != advanceMaxReached {
+		t.Errorf("Result = %s, want %s", action.Result, advanceMaxReached)
+	}
+}
+
+func TestStepStateStatus(t *testing.T) {
+	for _, tt := range []struct {
+		issue *types.Issue
+		want  string
+	}{
+		{&types.Issue{Status: types.StatusOpen}, "pending"},
+		{&types.Issue{Status: types.StatusBlocked}, "pending"},
+		{&types.Issue{Status: types.StatusInProgress}, "in_progress"},
+		{&types.Issue{Status: types.StatusClosed, CloseReason: "done"}, "complete"},
+		{&types.Issue{Status: types.StatusClosed, CloseReason: "Tests failed"}, "failed"},
+	} {
+		if got := stepStateStatus(tt.issue); got != tt.want {
+			t.Errorf("stepStateStatus(%s, %q) = %s, want %s", tt.issue.Status, tt.issue.CloseReason, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/formula"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+// Step metadata keys used by on_complete expansion.
+//
+// Cooking a step with on_complete stores the spec under "on_complete" in the
+// step issue's metadata. Whoever completes the step records its result under
+// "output" before closing it, e.g.:
+//
+//	{"on_complete": {...}, "output": {"polecats": [{"name": "ace"}, {"name": "nux"}]}}
+const (
+	onCompleteMetadataKey = "on_complete"
+	stepOutputMetadataKey = "output"
+)
+
+// forEachPlaceholder matches {item}, {item.field.nested} and {index} in
+// on_complete vars. These use single braces so they survive {{var}}
+// substitution at pour time.
+var forEachPlaceholder = regexp.MustCompile(`\{(item(?:\.[A-Za-z0-9_-]+)*|index)\}`)
+
+// ForEachResult is the outcome of expanding a closed step's on_complete.
+type ForEachResult struct {
+	StepID     string   `json:"step_id"`
+	Bond       string   `json:"bond"`
+	Sequential bool     `json:"sequential,omitempty"`
+	Spawned    []string `json:"spawned,omitempty"`  // Root IDs of molecules created by this run
+	Existing   []string `json:"existing,omitempty"` // Root IDs already created by an earlier run
+}
+
+// parseStepMetadata returns the on_complete spec and output recorded on a
+// step issue. The spec is nil if the step has no for_each expansion.
+func parseStepMetadata(issue *types.Issue) (*formula.OnCompleteSpec, json.RawMessage, error) {
+	if issue == nil || len(issue.Metadata) == 0 {
+		return nil, nil, nil
+	}
+	var meta struct {
+		OnComplete *formula.OnCompleteSpec `json:"on_complete"`
+		Output     json.RawMessage         `json:"output"`
+	}
+	if err := json.Unmarshal(issue.Metadata, &meta); err != nil {
+		// Metadata is free-form; anything that isn't an object has no spec
+		return nil, nil, nil
+	}
+	if meta.OnComplete == nil || meta.OnComplete.ForEach == "" {
+		return nil, nil, nil
+	}
+	if meta.OnComplete.Bond == "" {
+		return nil, nil, fmt.Errorf("on_complete for %s has for_each but no bond", issue.ID)
+	}
+	return meta.OnComplete, meta.Output, nil
+}
+
+// forEachItems resolves an "output.<path>" expression against a step's
+// output and returns the collection it names.
+func forEachItems(output json.RawMessage, path string) ([]interface{}, error) {
+	rest, ok := strings.CutPrefix(path, stepOutputMetadataKey+".")
+	if !ok {
+		return nil, fmt.Errorf("for_each must start with 'output.' (got %q)", path)
+	}
+	if len(output) == 0 {
+		return nil, fmt.Errorf("step has no output (set metadata.%s before closing)", stepOutputMetadataKey)
+	}
+	var value interface{}
+	if err := json.Unmarshal(output, &value); err != nil {
+		return nil, fmt.Errorf("invalid step output: %w", err)
+	}
+	value, ok = lookupPath(value, strings.Split(rest, "."))
+	if !ok {
+		return nil, fmt.Errorf("step output has no %s", path)
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", path)
+	}
+	return items, nil
+}
+
+// lookupPath walks object fields by name.
+func lookupPath(value interface{}, fields []string) (interface{}, bool) {
+	for _, field := range fields {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[field]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// bindForEachVars substitutes {item}, {item.field} and {index} in the
+// on_complete vars for one item of the collection.
+func bindForEachVars(vars map[string]string, item interface{}, index int) (map[string]string, error) {
+	bound := make(map[string]string, len(vars))
+	for name, tmpl := range vars {
+		var bindErr error
+		bound[name] = forEachPlaceholder.ReplaceAllStringFunc(tmpl, func(match string) string {
+			ref := match[1 : len(match)-1]
+			if ref == "index" {
+				return strconv.Itoa(index)
+			}
+			value := item
+			if field, ok := strings.CutPrefix(ref, "item."); ok {
+				var found bool
+				if value, found = lookupPath(item, strings.Split(field, ".")); !found {
+					bindErr = fmt.Errorf("var %s: item %d has no field %q", name, index, field)
+					return match
+				}
+			}
+			return forEachValueString(value)
+		})
+		if bindErr != nil {
+			return nil, bindErr
+		}
+	}
+	return bound, nil
+}
+
+// forEachValueString renders a JSON value for substitution: strings as-is,
+// numbers and booleans in their JSON form, objects and lists as JSON.
+func forEachValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// forEachChildRef names the molecule bonded for one item. Bonded IDs are
+// deterministic (<step>.each-<index>), which is what makes replaying the
+// expansion safe.
+func forEachChildRef(index int) string {
+	return fmt.Sprintf("each-%d", index)
+}
+
+// runOnComplete expands a closed step's on_complete.for_each: the bond
+// formula is poured once per item of the step's output and attached to the
+// step. Sequential bonds are chained so each molecule waits for the previous
+// one; parallel bonds (the default) are independent.
+//
+// It is idempotent: molecules that already exist from an earlier run (e.g. a
+// replayed close) are kept, and only missing ones are created. Returns nil if
+// the step has no for_each expansion.
+func runOnComplete(ctx context.Context, s storage.Storage, stepID string, actorName string) (*ForEachResult, error) {
+	step, err := s.GetIssue(ctx, stepID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get step %s: %w", stepID, err)
+	}
+	if step == nil {
+		return nil, fmt.Errorf("step %s not found", stepID)
+	}
+	oc, output, err := parseStepMetadata(step)
+	if err != nil || oc == nil {
+		return nil, err
+	}
+	if step.Status != types.StatusClosed {
+		return nil, fmt.Errorf("step %s is not closed", stepID)
+	}
+
+	items, err := forEachItems(output, oc.ForEach)
+	if err != nil {
+		return nil, fmt.Errorf("on_complete for %s: %w", stepID, err)
+	}
+
+	result := &ForEachResult{StepID: step.ID, Bond: oc.Bond, Sequential: oc.Sequential}
+	var roots []string
+	for i, item := range items {
+		rootID := step.ID + "." + forEachChildRef(i)
+		if existing, err := s.GetIssue(ctx, rootID); err == nil && existing != nil {
+			result.Existing = append(result.Existing, rootID)
+			roots = append(roots, rootID)
+			continue
+		}
+
+		vars, err := bindForEachVars(oc.Vars, item, i)
+		if err != nil {
+			return result, fmt.Errorf("on_complete for %s: %w", stepID, err)
+		}
+		subgraph, err := resolveAndCookFormulaWithVars(oc.Bond, nil, vars)
+		if err != nil {
+			return result, fmt.Errorf("on_complete for %s: loading bond formula %s: %w", stepID, oc.Bond, err)
+		}
+		vars = applyVariableDefaults(vars, subgraph)
+		var missing []string
+		for _, v := range extractRequiredVariables(subgraph) {
+			if _, ok := vars[v]; !ok {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			return result, fmt.Errorf("on_complete for %s: bond %s is missing variables: %s (add them to on_complete.vars)",
+				stepID, oc.Bond, strings.Join(missing, ", "))
+		}
+
+		spawned, err := spawnMoleculeWithOptions(ctx, s, subgraph, CloneOptions{
+			Vars:      vars,
+			Actor:     actorName,
+			Ephemeral: step.Ephemeral,
+			ParentID:  step.ID,
+			ChildRef:  forEachChildRef(i),
+		})
+		if err != nil {
+			return result, fmt.Errorf("on_complete for %s: spawning item %d: %w", stepID, i, err)
+		}
+		result.Spawned = append(result.Spawned, spawned.NewEpicID)
+		roots = append(roots, spawned.NewEpicID)
+	}
+
+	// Wiring is checked against existing edges so a replay after a partial
+	// run fills in whatever is missing.
+	for i, rootID := range roots {
+		if err := ensureDependency(ctx, s, rootID, step.ID, types.DepParentChild, actorName); err != nil {
+			return result, err
+		}
+		if oc.Sequential && i > 0 {
+			if err := ensureDependency(ctx, s, rootID, roots[i-1], types.DepBlocks, actorName); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// ensureDependency adds a dependency unless an identical one already exists.
+func ensureDependency(ctx context.Context, s storage.Storage, issueID, dependsOnID string, depType types.DependencyType, actorName string) error {
+	deps, err := s.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("could not get dependencies of %s: %w", issueID, err)
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == dependsOnID && dep.Type == depType {
+			return nil
+		}
+	}
+	dep := &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType}
+	if err := s.AddDependency(ctx, dep, actorName); err != nil {
+		return fmt.Errorf("could not link %s to %s: %w", issueID, dependsOnID, err)
+	}
+	return nil
+}
+
+// printForEachResult writes the molecules bonded by a step's on_complete to w.
+func printForEachResult(w io.Writer, result *ForEachResult) {
+	if result == nil {
+		return
+	}
+	total := len(result.Spawned) + len(result.Existing)
+	mode := "parallel"
+	if result.Sequential {
+		mode = "sequential"
+	}
+	if len(result.Spawned) == 0 {
+		if total == 0 {
+			fmt.Fprintf(w, "  on_complete: %s output is empty, nothing to bond\n", result.StepID)
+		} else {
+			fmt.Fprintf(w, "  on_complete: %d %s molecule(s) already bonded\n", total, result.Bond)
+		}
+		return
+	}
+	fmt.Fprintf(w, "%s Bonded %d %s molecule(s) to %s (%s)\n", ui.RenderPass("✓"), len(result.Spawned), result.Bond, result.StepID, mode)
+	for _, id := range result.Spawned {
+		fmt.Fprintf(w, "  %s\n", id)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/formula"
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestBindForEachVars(t *testing.T) {
+	item := map[string]interface{}{
+		"name": "ace",
+		"rig":  map[string]interface{}{"id": "r1", "cores": 8.0},
+		"tags": []interface{}{"a", "b"},
+	}
+	vars := map[string]string{
+		"polecat_name": "{item.name}",
+		"arm":          "arm-{index}-{item.rig.id}",
+		"cores":        "{item.rig.cores}",
+		"tags":         "{item.tags}",
+		"parent":       "{{rig}}", // Pour-time placeholder, left alone
+	}
+	got, err := bindForEachVars(vars, item, 3)
+	if err != nil {
+		t.Fatalf("bindForEachVars: %v", err)
+	}
+	want := map[string]string{
+		"polecat_name": "ace",
+		"arm":          "arm-3-r1",
+		"cores":        "8",
+		"tags":         `["a","b"]`,
+		"parent":       "{{rig}}",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	if got, _ := bindForEachVars(map[string]string{"v": "{item}"}, "plain", 0); got["v"] != "plain" {
+		t.Errorf("{item} for a primitive = %q, want %q", got["v"], "plain")
+	}
+	if _, err := bindForEachVars(map[string]string{"v": "{item.missing}"}, item, 0); err == nil {
+		t.Error("expected error for a missing item field")
+	}
+}
+
+func TestForEachItems(t *testing.T) {
+	output := json.RawMessage(`{"workers": {"list": [1, 2]}, "name": "x"}`)
+
+	items, err := forEachItems(output, "output.workers.list")
+	if err != nil || len(items) != 2 {
+		t.Fatalf("forEachItems = %v, %v", items, err)
+	}
+
+	for path, wantErr := range map[string]string{
+		"output.name":    "not a list",
+		"output.missing": "has no output.missing",
+		"workers.list":   "must start with 'output.'",
+	} {
+		if _, err := forEachItems(output, path); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("forEachItems(%q) error = %v, want %q", path, err, wantErr)
+		}
+	}
+	if _, err := forEachItems(nil, "output.workers"); err == nil {
+		t.Error("expected error for a step without output")
+	}
+}
+
+func TestProcessStepToIssue_OnCompleteSurvivesPour(t *testing.T) {
+	step := &formula.Step{
+		ID:    "survey",
+		Title: "Survey",
+		OnComplete: &formula.OnCompleteSpec{
+			ForEach: "output.workers",
+			Bond:    "mol-arm",
+			Vars:    map[string]string{"name": "{item.name}", "rig": "{{rig}}"},
+		},
+	}
+	issue := processStepToIssue(step, "proto")
+	poured := substituteMetadataVariables(issue.Metadata, map[string]string{"rig": "east"})
+
+	oc, _, err := parseStepMetadata(&types.Issue{ID: "bd-1", Metadata: poured})
+	if err != nil || oc == nil {
+		t.Fatalf("parseStepMetadata = %v, %v", oc, err)
+	}
+	if oc.Bond != "mol-arm" || oc.Vars["rig"] != "east" || oc.Vars["name"] != "{item.name}" {
+		t.Errorf("unexpected spec after pour: %+v", oc)
+	}
+}
+
+func TestRunOnComplete(t *testing.T) {
+	dir := t.TempDir()
+	formulaDir := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	arm := `{
+  "formula": "mol-arm",
+  "version": 1,
+  "type": "workflow",
+  "vars": {"name": {"required": true}},
+  "steps": [{"id": "work", "title": "Work for {{name}}"}]
+}`
+	if err := os.WriteFile(filepath.Join(formulaDir, "mol-arm.formula.json"), []byte(arm), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	meta, _ := json.Marshal(map[string]interface{}{
+		"on_complete": &formula.OnCompleteSpec{
+			ForEach:    "output.workers",
+			Bond:       "mol-arm",
+			Vars:       map[string]string{"name": "{item.name}"},
+			Sequential: true,
+		},
+		"output": map[string]interface{}{
+			"workers": []interface{}{
+				map[string]interface{}{"name": "ace"},
+				map[string]interface{}{"name": "nux"},
+			},
+		},
+	})
+	step := &types.Issue{Title: "Survey", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, Metadata: meta}
+	if err := s.CreateIssue(ctx, step, "test"); err != nil {
+		t.Fatalf("Failed to create step: %v", err)
+	}
+	if _, err := runOnComplete(ctx, s, step.ID, "test"); err == nil {
+		t.Error("expected error expanding an open step")
+	}
+	if err := s.CloseIssue(ctx, step.ID, "done", "test", ""); err != nil {
+		t.Fatalf("Failed to close step: %v", err)
+	}
+
+	result, err := runOnComplete(ctx, s, step.ID, "test")
+	if err != nil {
+		t.Fatalf("runOnComplete: %v", err)
+	}
+	first, second := step.ID+".each-0", step.ID+".each-1"
+	if strings.Join(result.Spawned, ",") != first+","+second {
+		t.Fatalf("Spawned = %v, want [%s %s]", result.Spawned, first, second)
+	}
+
+	root, err := s.GetIssue(ctx, second)
+	if err != nil || root == nil {
+		t.Fatalf("GetIssue(%s) = %v, %v", second, root, err)
+	}
+	children, err := findHierarchicalChildren(ctx, s, second)
+	if err != nil || len(children) != 1 || children[0].Title != "Work for nux" {
+		t.Errorf("expected one 'Work for nux' step under %s, got %v (%v)", second, children, err)
+	}
+
+	countDeps := func(id string) map[types.DependencyType]int {
+		deps, err := s.GetDependencyRecords(ctx, id)
+		if err != nil {
+			t.Fatalf("GetDependencyRecords(%s): %v", id, err)
+		}
+		counts := make(map[types.DependencyType]int)
+		for _, dep := range deps {
+			counts[dep.Type]++
+			if dep.Type == types.DepBlocks && dep.DependsOnID != first {
+				t.Errorf("%s blocks on %s, want %s", id, dep.DependsOnID, first)
+			}
+		}
+		return counts
+	}
+	if deps := countDeps(second); deps[types.DepParentChild] != 1 || deps[types.DepBlocks] != 1 {
+		t.Errorf("second arm deps = %v, want one parent-child and one blocks", deps)
+	}
+
+	// Replaying the close changes nothing
+	again, err := runOnComplete(ctx, s, step.ID, "test")
+	if err != nil {
+		t.Fatalf("runOnComplete (replay): %v", err)
+	}
+	if len(again.Spawned) != 0 || len(again.Existing) != 2 {
+		t.Errorf("replay spawned %v, existing %v; want nothing new", again.Spawned, again.Existing)
+	}
+	if deps := countDeps(second); deps[types.DepParentChild] != 1 || deps[types.DepBlocks] != 1 {
+		t.Errorf("replay duplicated dependencies: %v", deps)
+	}
+}
+
+func TestRunOnComplete_NoSpec(t *testing.T) {
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Plain", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2,
+		Metadata: json.RawMessage(`{"files": ["a.go"]}`)}
+	if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := runOnComplete(ctx, s, issue.ID, "test"); result != nil || err != nil {
+		t.Errorf("runOnComplete = %v, %v; want nil, nil", result, err)
+	}
+}
+
+func TestCloseJSONResult(t *testing.T) {
+	closed := []*types.Issue{{ID: "bd-1", Title: "Survey"}}
+	forEach := []*ForEachResult{{StepID: "bd-1", Bond: "mol-arm", Spawned: []string{"bd-1.each-0"}}}
+
+	// Bonded molecules don't change the plain close output
+	if _, ok := closeJSONResult(closed, forEach, "", nil).([]*types.Issue); !ok {
+		t.Error("close --json with on_complete results is no longer an array")
+	}
+
+	out, ok := closeJSONResult(closed, forEach, "continue", "next").(map[string]interface{})
+	if !ok {
+		t.Fatal("close --json --continue is not an object")
+	}
+	if out["continue"] != "next" || out["for_each"] == nil {
+		t.Errorf("continue object = %v", out)
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/schedule"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/timeparsing"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/utils"
+)
+
+// planTimeLayout is how plan dates are shown in tables and Gantt charts.
+const planTimeLayout = "2006-01-02 15:04"
+
+var planCmd = &cobra.Command{
+	Use:     "plan <epic-id>",
+	GroupID: "views",
+	Short:   "Forecast an epic's schedule and critical path",
+	Long: `Forecast when an epic's issues will finish.
+
+The plan covers every descendant of the epic (parent-child edges). Each
+open issue takes its estimate (--estimate on create/update); blocking
+dependencies order the work, a parent finishes when its last child does,
+and closed issues take no time.
+
+For each issue the plan shows the earliest and latest start and finish
+and the slack between them. Issues with no slack are on the critical path:
+any delay to them delays the epic. Issues projected to finish after their
+due date, or the epic's, are flagged late.
+
+Estimates are working time, converted to calendar time at --hours-per-day.
+By default each assignee can work on all of their ready issues at once;
+--capacity sets an assignee's hours per day and has them work on one issue
+at a time.
+
+Output formats:
+  table (default)  One row per issue
+  json             Same as --json
+  mermaid          Mermaid Gantt chart
+
+Examples:
+  fbd plan bd-a3f8
+  fbd plan bd-a3f8 --capacity alice=6,bob=4
+  fbd plan bd-a3f8 --default-estimate 120 --start "next monday"
+  fbd plan bd-a3f8 --format mermaid > plan.mmd`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		capacityFlags, _ := cmd.Flags().GetStringSlice("capacity")
+		hoursPerDay, _ := cmd.Flags().GetFloat64("hours-per-day")
+		defaultEstimate, _ := cmd.Flags().GetInt("default-estimate")
+		startStr, _ := cmd.Flags().GetString("start")
+
+		if jsonOutput {
+			format = "json"
+		}
+		if format != "table" && format != "json" && format != "mermaid" {
+			FatalErrorRespectJSON("invalid format %q (must be table, json or mermaid)", format)
+		}
+		if hoursPerDay <= 0 || hoursPerDay > 24 {
+			FatalErrorRespectJSON("--hours-per-day must be between 0 and 24")
+		}
+		if defaultEstimate < 0 {
+			FatalErrorRespectJSON("--default-estimate cannot be negative")
+		}
+		opts := schedule.Options{
+			HoursPerDay:     hoursPerDay,
+			DefaultEstimate: defaultEstimate,
+		}
+		var err error
+		if opts.Capacity, err = parseCapacity(capacityFlags); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if startStr != "" {
+			if opts.Start, err = timeparsing.ParseRelativeTime(startStr, time.Now()); err != nil {
+				FatalErrorRespectJSON("invalid --start: %v", err)
+			}
+		}
+
+		if err := ensureDirectMode("plan requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		requireFreshDB(ctx)
+
+		epicID, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("issue '%s' not found", args[0])
+		}
+		epic, issues, deps, err := loadPlanInputs(ctx, store, epicID)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		plan, err := schedule.Build(epic, issues, deps, opts)
+		if err != nil {
+			FatalErrorRespectJSON("planning %s: %v", epicID, err)
+		}
+
+		switch format {
+		case "json":
+			outputJSON(plan)
+		case "mermaid":
+			renderPlanGantt(os.Stdout, plan)
+		default:
+			renderPlanTable(os.Stdout, plan)
+		}
+	},
+}
+
+// parseCapacity parses --capacity values of the form assignee=hours.
+func parseCapacity(values []string) (map[string]float64, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	capacity := make(map[string]float64, len(values))
+	for _, v := range values {
+		name, hoursStr, ok := strings.Cut(v, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --capacity %q (expected assignee=hours)", v)
+		}
+		hours, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(hoursStr), "h"), 64)
+		if err != nil || hours <= 0 || hours > 24 {
+			return nil, fmt.Errorf("invalid --capacity %q: hours per day must be a number between 0 and 24", v)
+		}
+		capacity[name] = hours
+	}
+	return capacity, nil
+}
+
+// planTreeDepth bounds how far below the epic the plan looks. It matches the
+// default --max-depth of "fbd dep tree"; not every backend treats 0 as a default.
+const planTreeDepth = 50
+
+// loadPlanInputs loads an epic with everything below it in the dependents
+// tree and their dependency records. Blockers outside the tree are loaded
+// too, so the plan can tell which of them are already closed.
+func loadPlanInputs(ctx context.Context, s storage.Storage, epicID string) (*types.Issue, []*types.Issue, map[string][]*types.Dependency, error) {
+	epic, err := s.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	if epic == nil {
+		return nil, nil, nil, fmt.Errorf("issue %s not found", epicID)
+	}
+
+	tree, err := s.GetDependencyTree(ctx, epicID, planTreeDepth, false, true)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get dependency tree: %w", err)
+	}
+	seen := make(map[string]bool, len(tree))
+	var ids []string
+	for _, node := range tree {
+		if !seen[node.ID] {
+			seen[node.ID] = true
+			ids = append(ids, node.ID)
+		}
+	}
+	deps, err := s.GetDependencyRecordsForIssues(ctx, ids)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	for _, records := range deps {
+		for _, dep := range records {
+			if !seen[dep.DependsOnID] {
+				seen[dep.DependsOnID] = true
+				ids = append(ids, dep.DependsOnID)
+			}
+		}
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{IDs: ids})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+	return epic, issues, deps, nil
+}
+
+// renderPlanTable prints a plan summary followed by one row per issue.
+func renderPlanTable(w io.Writer, plan *schedule.Plan) {
+	fmt.Fprintf(w, "\n%s Plan for %s: %s\n\n", ui.RenderAccent("📅"), ui.RenderID(plan.EpicID), plan.Title)
+	fmt.Fprintf(w, "  Start:             %s\n", plan.Start.Local().Format(planTimeLayout))
+	finish := plan.Finish.Local().Format(planTimeLayout)
+	if plan.Late {
+		finish += " " + ui.RenderFail(fmt.Sprintf("(late, due %s)", plan.DueAt.Local().Format(planTimeLayout)))
+	} else if plan.DueAt != nil {
+		finish += fmt.Sprintf(" (due %s)", plan.DueAt.Local().Format(planTimeLayout))
+	}
+	fmt.Fprintf(w, "  Projected finish:  %s\n", finish)
+	if len(plan.CriticalPath) > 0 {
+		fmt.Fprintf(w, "  Critical path:     %s\n", strings.Join(plan.CriticalPath, " → "))
+	}
+	fmt.Fprintln(w)
+
+	if len(plan.Tasks) == 0 {
+		fmt.Fprintf(w, "%s has no children to plan\n", plan.EpicID)
+	} else {
+		rows := [][]string{{"ID", "ASSIGNEE", "EST", "START", "FINISH", "SLACK", "DUE", "FLAGS", "TITLE"}}
+		for _, t := range plan.Tasks {
+			estimate := formatCompactMinutes(t.EstimateMinutes)
+			if t.Container {
+				estimate = "-"
+			} else if !t.Estimated && t.Status != types.StatusClosed {
+				estimate += "?"
+			}
+			due := ""
+			if t.DueAt != nil {
+				due = t.DueAt.Local().Format(planTimeLayout)
+			}
+			var flags []string
+			if t.Status == types.StatusClosed {
+				flags = append(flags, "done")
+			}
+			if t.Critical {
+				flags = append(flags, "critical")
+			}
+			if t.Late {
+				flags = append(flags, "late")
+			}
+			rows = append(rows, []string{
+				t.ID,
+				t.Assignee,
+				estimate,
+				t.EarliestStart.Local().Format(planTimeLayout),
+				t.EarliestFinish.Local().Format(planTimeLayout),
+				formatCompactMinutes(t.SlackMinutes),
+				due,
+				strings.Join(flags, ","),
+				t.Title,
+			})
+		}
+		fmt.Fprint(w, formatTable(rows))
+	}
+
+	if len(plan.Warnings) > 0 {
+		fmt.Fprintln(w)
+		for _, warning := range plan.Warnings {
+			fmt.Fprintf(w, "%s %s\n", ui.RenderWarn("⚠"), warning)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// formatCompactMinutes formats a duration in minutes compactly: 45m, 1.5h, 2.5d.
+func formatCompactMinutes(minutes int) string {
+	trim := func(f float64) string {
+		return strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0")
+	}
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes < 24*60:
+		return trim(float64(minutes)/60) + "h"
+	default:
+		return trim(float64(minutes)/(24*60)) + "d"
+	}
+}
+
+// renderPlanGantt writes a plan as a Mermaid Gantt chart with a section per
+// assignee. Parents and zero-length issues are drawn as milestones.
+func renderPlanGantt(w io.Writer, plan *schedule.Plan) {
+	// Colons, semicolons and hashes are syntax in Gantt task lines
+	escape := strings.NewReplacer(":", " -", ";", ",", "#", "").Replace
+
+	fmt.Fprintln(w, "gantt")
+	fmt.Fprintf(w, "    title %s\n", escape(plan.EpicID+" "+plan.Title))
+	fmt.Fprintln(w, "    dateFormat YYYY-MM-DD HH:mm")
+	_, _ = io.WriteString(w, "    axisFormat %m-%d\n")
+
+	var sections []string
+	bySection := make(map[string][]*schedule.Task)
+	for _, t := range plan.Tasks {
+		section := t.Assignee
+		if section == "" {
+			section = "unassigned"
+		}
+		if _, ok := bySection[section]; !ok {
+			sections = append(sections, section)
+		}
+		bySection[section] = append(bySection[section], t)
+	}
+	n := 0
+	for _, section := range sections {
+		fmt.Fprintf(w, "    section %s\n", escape(section))
+		for _, t := range bySection[section] {
+			var tags []string
+			switch {
+			case t.Status == types.StatusClosed:
+				tags = append(tags, "done")
+			case t.Status == types.StatusInProgress:
+				tags = append(tags, "active")
+			}
+			if t.Critical {
+				tags = append(tags, "crit")
+			}
+			label := t.ID + " " + t.Title
+			if t.Late {
+				label += " (late)"
+			}
+			start := t.EarliestStart.Local().Format(planTimeLayout)
+			end := t.EarliestFinish.Local().Format(planTimeLayout)
+			// Mermaid task IDs must be plain identifiers
+			n++
+			id := fmt.Sprintf("t%d", n)
+			if t.Container || !t.EarliestFinish.After(t.EarliestStart) {
+				tags = append(tags, "milestone")
+				fmt.Fprintf(w, "    %s :%s, %s, 0d\n", escape(label), strings.Join(append(tags, id), ", "), end)
+				continue
+			}
+			fmt.Fprintf(w, "    %s :%s, %s, %s\n", escape(label), strings.Join(append(tags, id), ", "), start, end)
+		}
+	}
+	if plan.DueAt != nil {
+		fmt.Fprintln(w, "    section Due")
+		fmt.Fprintf(w, "    %s due :milestone, due, %s, 0d\n", escape(plan.EpicID), plan.DueAt.Local().Format(planTimeLayout))
+	}
+}
+
+func init() {
+	planCmd.Flags().String("format", "table", "Output format: table, json, mermaid")
+	planCmd.Flags().StringSlice("capacity", nil, "Assignee hours per day, e.g. alice=6,bob=4 (listed assignees work one issue at a time)")
+	planCmd.Flags().Float64("hours-per-day", schedule.DefaultHoursPerDay, "Working hours per day for assignees without --capacity")
+	planCmd.Flags().Int("default-estimate", 0, "Minutes to assume for open issues without an estimate")
+	planCmd.Flags().String("start", "", "Plan start time (default now), e.g. 2026-03-02 or \"next monday\"")
+	rootCmd.AddCommand(planCmd)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/schedule"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestLoadPlanInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newTestStore(t, filepath.Join(tmpDir, ".beads", "beads.db"))
+	ctx := context.Background()
+
+	create := func(title string, issueType types.IssueType, status types.Status, minutes int) *types.Issue {
+		issue := &types.Issue{Title: title, Priority: 2, IssueType: issueType, Status: status, EstimatedMinutes: &minutes}
+		if status == types.StatusClosed {
+			now := time.Now()
+			issue.ClosedAt = &now
+		}
+		if err := s.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	addDep := func(from, to *types.Issue, depType types.DependencyType) {
+		dep := &types.Dependency{IssueID: from.ID, DependsOnID: to.ID, Type: depType}
+		if err := s.AddDependency(ctx, dep, "test-user"); err != nil {
+			t.Fatalf("AddDependency: %v", err)
+		}
+	}
+
+	epic := create("Epic", types.TypeEpic, types.StatusOpen, 0)
+	design := create("Design", types.TypeTask, types.StatusOpen, 480)
+	build := create("Build", types.TypeTask, types.StatusOpen, 960)
+	prereq := create("Prerequisite", types.TypeTask, types.StatusClosed, 60)
+	unrelated := create("Related elsewhere", types.TypeTask, types.StatusOpen, 60)
+	addDep(design, epic, types.DepParentChild)
+	addDep(build, epic, types.DepParentChild)
+	addDep(build, design, types.DepBlocks)
+	addDep(design, prereq, types.DepBlocks)
+	addDep(unrelated, epic, types.DepRelated)
+
+	// Not every backend reads a zero depth as "use the default"
+	gotEpic, issues, deps, err := loadPlanInputs(ctx, literalDepthStore{s}, epic.ID)
+	if err != nil {
+		t.Fatalf("loadPlanInputs: %v", err)
+	}
+	plan, err := schedule.Build(gotEpic, issues, deps, schedule.Options{})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if want := []string{design.ID, build.ID}; !reflect.DeepEqual(plan.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", plan.CriticalPath, want)
+	}
+	if len(plan.Tasks) != 2 {
+		t.Errorf("expected only the epic's children in the plan, got %d tasks", len(plan.Tasks))
+	}
+	if len(plan.Warnings) != 0 {
+		t.Errorf("closed blocker outside the epic should not warn: %v", plan.Warnings)
+	}
+}
+
+// literalDepthStore returns no tree for a depth below one instead of
+// applying a default limit, the way the Dolt backend used to.
+type literalDepthStore struct {
+	storage.Storage
+}
+
+func (s literalDepthStore) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	if maxDepth < 1 {
+		return nil, nil
+	}
+	return s.Storage.GetDependencyTree(ctx, issueID, maxDepth, showAllPaths, reverse)
+}
+
+func TestParseCapacity(t *testing.T) {
+	got, err := parseCapacity([]string{"alice=6", "bob = 4.5h"})
+	if err != nil {
+		t.Fatalf("parseCapacity: %v", err)
+	}
+	if want := map[string]float64{"alice": 6, "bob": 4.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCapacity = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"alice", "=4", "alice=0", "alice=25", "alice=lots"} {
+		if _, err := parseCapacity([]string{bad}); err == nil {
+			t.Errorf("parseCapacity(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFormatCompactMinutes(t *testing.T) {
+	for minutes, want := range map[int]string{0: "0m", 45: "45m", 60: "1h", 90: "1.5h", 24 * 60: "1d", 36 * 60: "1.5d"} {
+		if got := formatCompactMinutes(minutes); got != want {
+			t.Errorf("formatCompactMinutes(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
+
+func TestRenderPlanGantt(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	due := start.Add(48 * time.Hour)
+	plan := &schedule.Plan{
+		EpicID: "bd-1",
+		Title:  "Launch",
+		DueAt:  &due,
+		Tasks: []*schedule.Task{
+			{ID: "bd-1.1", Title: "Design: API", Assignee: "alice", Critical: true,
+				EarliestStart: start, EarliestFinish: start.Add(24 * time.Hour)},
+			{ID: "bd-1.2", Title: "Parent", Container: true, Late: true,
+				EarliestStart: start.Add(24 * time.Hour), EarliestFinish: start.Add(72 * time.Hour)},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderPlanGantt(&buf, plan)
+	out := buf.String()
+	for _, want := range []string{
+		"gantt\n",
+		"    section alice\n",
+		"    bd-1.1 Design - API :crit, t1, 2026-03-02 09:00, 2026-03-03 09:00\n",
+		"    section unassigned\n",
+		"    bd-1.2 Parent (late) :milestone, t2, 2026-03-05 09:00, 0d\n",
+		"    bd-1 due :milestone, due, 2026-03-04 09:00, 0d\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Gantt output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -37,6 +38,11 @@ Boolean operators (case-insensitive):
   NOT expr          Negates the condition
   (expr)            Grouping with parentheses
 
+Any combination of fields and operators may be used. Parts of the query that
+map onto database filters run in SQL; the rest (e.g. OR across different
+fields, priority!=, owner) are evaluated in memory on the SQL results. Use
+--explain to see the plan.
+
 Supported fields:
   status            Issue status (open, in_progress, blocked, deferred, closed)
   priority          Priority level (0-4)
@@ -58,6 +64,14 @@ Supported fields:
   parent            Parent issue ID
   mol_type          Molecule type (swarm, patrol, work)
 
+Dependency graph operators (= and != only):
+  blocked_by        Issues transitively blocked by the given issue
+  blocks            Issues the given issue is transitively blocked by
+  descendant_of     Transitive children of the given issue (parent-child)
+  ancestor_of       Transitive parents of the given issue (parent-child)
+  dep_type          Issues with an outgoing dependency of the given type
+  has_dependents    Boolean: whether other issues depend on the issue
+
 Date values:
   Relative durations: 7d (7 days ago), 24h (24 hours ago), 2w (2 weeks ago)
   Absolute dates: 2025-01-15, 2025-01-15T10:00:00Z
@@ -72,7 +86,11 @@ Examples:
   fbd query "assignee=none AND type=task"
   fbd query "created>30d AND status!=closed"
   fbd query "label=frontend OR label=backend"
-  fbd query "title=authentication AND priority=0"`,
+  fbd query "title=authentication AND priority=0"
+  fbd query "owner=alice OR (assignee=bob AND priority!=4)" --explain
+  fbd query "type=bug AND blocked_by=bd-a3f8"
+  fbd query "descendant_of=bd-epic AND has_dependents=false"
+  fbd query "dep_type=discovered-from AND closed>7d" --all`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get query from args
 		if len(args) == 0 {
@@ -92,6 +110,7 @@ Examples:
 		sortBy, _ := cmd.Flags().GetString("sort")
 		reverse, _ := cmd.Flags().GetBool("reverse")
 		parseOnly, _ := cmd.Flags().GetBool("parse-only")
+		explain, _ := cmd.Flags().GetBool("explain")
 
 		// Parse the query
 		node, err := query.Parse(queryStr)
@@ -106,26 +125,20 @@ Examples:
 			return
 		}
 
-		// Evaluate the query to get filter and/or predicate
-		eval := query.NewEvaluator(time.Now())
-		result, err := eval.Evaluate(node)
+		ctx := rootCtx
+
+		result, excludeClosed, err := evaluateQuery(ctx, node, allFlag)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error evaluating query: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Apply limit if specified
-		if limit > 0 && !result.RequiresPredicate {
-			result.Filter.Limit = limit
-		}
-
-		// By default exclude closed issues unless --all is specified or query explicitly filters by status
-		if !allFlag && result.Filter.Status == nil && !hasExplicitStatusFilter(node) {
-			result.Filter.ExcludeStatus = append(result.Filter.ExcludeStatus, types.StatusClosed)
+		// If --explain, show which parts of the query run in SQL vs in memory
+		if explain {
+			outputQueryPlan(node, result, excludeClosed)
+			return
 		}
 
-		ctx := rootCtx
-
 		requireFreshDB(ctx)
 
 		// Direct mode
@@ -134,117 +147,196 @@ Examples:
 			os.Exit(1)
 		}
 
-		// If we need predicate filtering, we may need to fetch more results
-		// to ensure we get enough after filtering
-		searchFilter := result.Filter
-		if result.RequiresPredicate && limit > 0 {
-			// Fetch more to account for predicate filtering
-			searchFilter.Limit = limit * 3
-			if searchFilter.Limit < 100 {
-				searchFilter.Limit = 100
-			}
-		}
-
-		issues, err := store.SearchIssues(ctx, "", searchFilter)
+		issues, err := runQuery(ctx, result, limit)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// If no issues found, check if git has issues and auto-import
-		if len(issues) == 0 {
-			if checkAndAutoImport(ctx, store) {
-				issues, err = store.SearchIssues(ctx, "", searchFilter)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-			}
-		}
-
-		// Apply predicate filter if needed (for OR queries)
-		if result.RequiresPredicate && result.Predicate != nil {
-			// For predicate filtering, we need labels populated on issues
-			if store != nil {
-				issueIDs := make([]string, len(issues))
-				for i, issue := range issues {
-					issueIDs[i] = issue.ID
-				}
-				labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
-				for _, issue := range issues {
-					issue.Labels = labelsMap[issue.ID]
-				}
-			}
-
-			filtered := make([]*types.Issue, 0, len(issues))
-			for _, issue := range issues {
-				if result.Predicate(issue) {
-					filtered = append(filtered, issue)
-				}
-			}
-			issues = filtered
-
-			// Apply limit after predicate filtering
-			if limit > 0 && len(issues) > limit {
-				issues = issues[:limit]
-			}
-		}
-
 		// Apply sorting
 		sortIssues(issues, sortBy, reverse)
 
 		// Output results
 		if jsonOutput {
-			// Get labels and dependency counts
-			if store != nil {
-				issueIDs := make([]string, len(issues))
-				for i, issue := range issues {
-					issueIDs[i] = issue.ID
-				}
-				labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
-				depCounts, _ := store.GetDependencyCounts(ctx, issueIDs)
-				commentCounts, _ := store.GetCommentCounts(ctx, issueIDs)
-
-				for _, issue := range issues {
-					issue.Labels = labelsMap[issue.ID]
-				}
-
-				issuesWithCounts := make([]*types.IssueWithCounts, len(issues))
-				for i, issue := range issues {
-					counts := depCounts[issue.ID]
-					if counts == nil {
-						counts = &types.DependencyCounts{DependencyCount: 0, DependentCount: 0}
-					}
-					issuesWithCounts[i] = &types.IssueWithCounts{
-						Issue:           issue,
-						DependencyCount: counts.DependencyCount,
-						DependentCount:  counts.DependentCount,
-						CommentCount:    commentCounts[issue.ID],
-					}
-				}
-				outputJSON(issuesWithCounts)
-			} else {
-				outputJSON(issues)
-			}
+			outputIssuesWithCounts(ctx, issues)
 			return
 		}
 
 		// Load labels for display
-		if store != nil && !result.RequiresPredicate {
-			issueIDs := make([]string, len(issues))
-			for i, issue := range issues {
-				issueIDs[i] = issue.ID
-			}
-			labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
-			for _, issue := range issues {
-				issue.Labels = labelsMap[issue.ID]
-			}
+		if !result.RequiresLabels {
+			loadIssueLabels(ctx, issues)
 		}
 
 		outputQueryResults(issues, queryStr, longFormat)
 	},
 }
 
+// evaluateQuery compiles a parsed query into a SQL filter plus an in-memory
+// predicate. Graph operators resolve against the store's dependency records.
+// Closed issues are excluded unless includeClosed is set or the query filters
+// on status explicitly; the second return value reports whether they were.
+func evaluateQuery(ctx context.Context, node query.Node, includeClosed bool) (*query.QueryResult, bool, error) {
+	eval := query.NewEvaluator(time.Now())
+	if store != nil {
+		eval = eval.WithGraph(ctx, store)
+	}
+	result, err := eval.Evaluate(node)
+	if err != nil {
+		return nil, false, err
+	}
+
+	excludeClosed := !includeClosed && result.Filter.Status == nil && !hasExplicitStatusFilter(node)
+	if excludeClosed {
+		result.Filter.ExcludeStatus = append(result.Filter.ExcludeStatus, types.StatusClosed)
+	}
+	return result, excludeClosed, nil
+}
+
+// runQuery executes an evaluated query against the store. With an in-memory
+// predicate, the SQL filter only narrows the candidate set, so it runs without
+// a limit and the limit is applied after filtering.
+func runQuery(ctx context.Context, result *query.QueryResult, limit int) ([]*types.Issue, error) {
+	searchFilter := result.Filter
+	if limit > 0 && !result.RequiresPredicate {
+		searchFilter.Limit = limit
+	}
+
+	issues, err := store.SearchIssues(ctx, "", searchFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// If no issues found, check if git has issues and auto-import
+	if len(issues) == 0 {
+		if checkAndAutoImport(ctx, store) {
+			issues, err = store.SearchIssues(ctx, "", searchFilter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !result.RequiresPredicate || result.Predicate == nil {
+		return issues, nil
+	}
+
+	// Apply predicate filter for the parts of the query that couldn't run in SQL
+	issueIDs := make([]string, len(issues))
+	for i, issue := range issues {
+		issueIDs[i] = issue.ID
+	}
+	if result.RequiresLabels {
+		labelsMap, err := store.GetLabelsForIssues(ctx, issueIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels: %w", err)
+		}
+		for _, issue := range issues {
+			issue.Labels = labelsMap[issue.ID]
+		}
+	}
+	if result.RequiresDependencies {
+		depsMap, err := store.GetDependencyRecordsForIssues(ctx, issueIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies: %w", err)
+		}
+		for _, issue := range issues {
+			issue.Dependencies = depsMap[issue.ID]
+		}
+	}
+
+	filtered := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if result.Predicate(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+
+	// Apply limit after predicate filtering
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
+// loadIssueLabels populates Labels on each issue in one bulk query.
+func loadIssueLabels(ctx context.Context, issues []*types.Issue) {
+	issueIDs := make([]string, len(issues))
+	for i, issue := range issues {
+		issueIDs[i] = issue.ID
+	}
+	labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
+	for _, issue := range issues {
+		issue.Labels = labelsMap[issue.ID]
+	}
+}
+
+// outputIssuesWithCounts writes issues as JSON with labels and dependency and
+// comment counts, matching fbd list --json.
+func outputIssuesWithCounts(ctx context.Context, issues []*types.Issue) {
+	issueIDs := make([]string, len(issues))
+	for i, issue := range issues {
+		issueIDs[i] = issue.ID
+	}
+	labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
+	depCounts, _ := store.GetDependencyCounts(ctx, issueIDs)
+	commentCounts, _ := store.GetCommentCounts(ctx, issueIDs)
+
+	for _, issue := range issues {
+		issue.Labels = labelsMap[issue.ID]
+	}
+
+	issuesWithCounts := make([]*types.IssueWithCounts, len(issues))
+	for i, issue := range issues {
+		counts := depCounts[issue.ID]
+		if counts == nil {
+			counts = &types.DependencyCounts{DependencyCount: 0, DependentCount: 0}
+		}
+		issuesWithCounts[i] = &types.IssueWithCounts{
+			Issue:           issue,
+			DependencyCount: counts.DependencyCount,
+			DependentCount:  counts.DependentCount,
+			CommentCount:    commentCounts[issue.ID],
+		}
+	}
+	outputJSON(issuesWithCounts)
+}
+
+// outputQueryPlan prints the query plan: the conjuncts pushed down into the
+// SQL filter and those evaluated in memory.
+func outputQueryPlan(node query.Node, result *query.QueryResult, excludeClosed bool) {
+	if jsonOutput {
+		plan := struct {
+			Query                string   `json:"query"`
+			SQL                  []string `json:"sql"`
+			InMemory             []string `json:"in_memory"`
+			ExcludeClosed        bool     `json:"exclude_closed"`
+			RequiresLabels       bool     `json:"requires_labels"`
+			RequiresDependencies bool     `json:"requires_dependencies"`
+		}{
+			Query:                node.String(),
+			SQL:                  []string{},
+			InMemory:             []string{},
+			ExcludeClosed:        excludeClosed,
+			RequiresLabels:       result.RequiresLabels,
+			RequiresDependencies: result.RequiresDependencies,
+		}
+		for _, n := range result.Pushed {
+			plan.SQL = append(plan.SQL, n.String())
+		}
+		for _, n := range result.InMemory {
+			plan.InMemory = append(plan.InMemory, n.String())
+		}
+		outputJSON(plan)
+		return
+	}
+
+	fmt.Printf("Query: %s\n", node.String())
+	fmt.Print(result.Explain())
+	if excludeClosed {
+		fmt.Println("Default: status!=closed added to SQL (use --all to include closed)")
+	}
+}
+
 // hasExplicitStatusFilter checks if the query contains an explicit status comparison
 func hasExplicitStatusFilter(node query.Node) bool {
 	switch n := node.(type) {
@@ -421,6 +513,7 @@ func init() {
 	queryCmd.Flags().String("sort", "", "Sort by field: priority, created, updated, closed, status, id, title, type, assignee")
 	queryCmd.Flags().BoolP("reverse", "r", false, "Reverse sort order")
 	queryCmd.Flags().Bool("parse-only", false, "Only parse the query and show the AST (for debugging)")
+	queryCmd.Flags().Bool("explain", false, "Show which parts of the query run in SQL and which run in memory, without executing it")
 
 	rootCmd.AddCommand(queryCmd)
 }
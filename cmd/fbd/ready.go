@@ -1,11 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/fastbeads/internal/config"
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/ui"
 	"github.com/steveyegge/fastbeads/internal/utils"
@@ -21,6 +24,9 @@ GetReadyWork API which applies blocker-aware semantics to find truly claimable w
 
 Note: 'fbd list --ready' is NOT equivalent - it only filters by status=open.
 
+Unassigned work that you couldn't claim without exceeding a WIP limit
+(see 'fbd config set wip.*') is hidden; --ignore-wip shows it.
+
 Use --mol to filter to a specific molecule's steps:
   fbd ready --mol bd-patrol   # Show ready steps within molecule
 
@@ -32,6 +38,9 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		// Handle --gated flag (gate-resume discovery)
 		gated, _ := cmd.Flags().GetBool("gated")
 		if gated {
+			if err := ensureDirectMode("ready --gated requires direct database access"); err != nil {
+				FatalError("%v", err)
+			}
 			runMolReadyGated(cmd, args)
 			return
 		}
@@ -39,6 +48,9 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		// Handle molecule-specific ready query
 		molID, _ := cmd.Flags().GetString("mol")
 		if molID != "" {
+			if err := ensureDirectMode("ready --mol requires direct database access"); err != nil {
+				FatalError("%v", err)
+			}
 			runMoleculeReady(cmd, molID)
 			return
 		}
@@ -55,6 +67,9 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		molTypeStr, _ := cmd.Flags().GetString("mol-type")
 		prettyFormat, _ := cmd.Flags().GetBool("pretty")
 		includeDeferred, _ := cmd.Flags().GetBool("include-deferred")
+		cursor, _ := cmd.Flags().GetString("cursor")
+		paginate := cmd.Flags().Changed("cursor")
+		ignoreWIP, _ := cmd.Flags().GetBool("ignore-wip")
 		var molType *types.MolType
 		if molTypeStr != "" {
 			mt := types.MolType(molTypeStr)
@@ -86,6 +101,7 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			Labels:          labels,
 			LabelsAny:       labelsAny,
 			IncludeDeferred: includeDeferred, // GH#820: respect --include-deferred flag
+			Cursor:          cursor,
 		}
 		// Use Changed() to properly handle P0 (priority=0)
 		if cmd.Flags().Changed("priority") {
@@ -106,27 +122,109 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			fmt.Fprintf(os.Stderr, "Error: invalid sort policy '%s'. Valid values: hybrid, priority, oldest\n", sortPolicy)
 			os.Exit(1)
 		}
+		// If daemon is running, use RPC
+		if daemonClient != nil {
+			readyArgs := &rpc.ReadyArgs{
+				Status:          string(filter.Status),
+				Assignee:        assignee,
+				Unassigned:      unassigned,
+				Priority:        filter.Priority,
+				Type:            issueType,
+				Limit:           limit,
+				SortPolicy:      sortPolicy,
+				Labels:          labels,
+				LabelsAny:       labelsAny,
+				ParentID:        parentID,
+				MolType:         molTypeStr,
+				IncludeDeferred: includeDeferred,
+				Cursor:          cursor,
+				IgnoreWIP:       ignoreWIP,
+				ReportHidden:    true,
+			}
+			resp, err := daemonClient.Ready(readyArgs)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			var result rpc.ReadyResult
+			if err := json.Unmarshal(resp.Data, &result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+				os.Exit(1)
+			}
+			issuesWithCounts, hiddenByWIP := result.Issues, result.HiddenByWIP
+			issues := make([]*types.Issue, len(issuesWithCounts))
+			for i, iwc := range issuesWithCounts {
+				issues[i] = iwc.Issue
+			}
+			nextCursor := filter.NextCursor(issues)
+			if jsonOutput {
+				if issuesWithCounts == nil {
+					issuesWithCounts = []*types.IssueWithCounts{}
+				}
+				if paginate {
+					outputJSON(issuePage{Issues: issuesWithCounts, NextCursor: nextCursor})
+					return
+				}
+				outputJSON(issuesWithCounts)
+				return
+			}
+			maybeShowUpgradeNotification()
+
+			if len(issues) == 0 {
+				if hiddenByWIP > 0 {
+					printNoClaimableWork(hiddenByWIP)
+					return
+				}
+				hasOpenIssues := false
+				if statsResp, statsErr := daemonClient.Stats(); statsErr == nil {
+					var stats types.Statistics
+					if json.Unmarshal(statsResp.Data, &stats) == nil {
+						hasOpenIssues = stats.OpenIssues > 0 || stats.InProgressIssues > 0
+					}
+				}
+				printNoReadyWork(hasOpenIssues)
+				return
+			}
+			printReadyIssues(issues, prettyFormat)
+			if paginate {
+				printNextReadyPage(nextCursor)
+			}
+			if hiddenByWIP > 0 {
+				printHiddenByWIP(hiddenByWIP)
+			}
+			return
+		}
+
 		// Direct mode
 		ctx := rootCtx
 
 		requireFreshDB(ctx)
 
-		issues, err := store.GetReadyWork(ctx, filter)
+		// Hide work the caller can't claim under the WIP limits
+		getReadyWork := func() ([]*types.Issue, int, error) {
+			if ignoreWIP {
+				issues, err := store.GetReadyWork(ctx, filter)
+				return issues, 0, err
+			}
+			return storage.GetClaimableReadyWork(ctx, store, filter, actor)
+		}
+		issues, hiddenByWIP, err := getReadyWork()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 		// If no ready work found, check if git has issues and auto-import
-		if len(issues) == 0 {
+		if len(issues) == 0 && hiddenByWIP == 0 {
 			if checkAndAutoImport(ctx, store) {
 				// Re-run the query after import
-				issues, err = store.GetReadyWork(ctx, filter)
+				issues, hiddenByWIP, err = getReadyWork()
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
 				}
 			}
 		}
+		nextCursor := filter.NextCursor(issues)
 		if jsonOutput {
 			// Always output array, even if empty
 			if issues == nil {
@@ -144,6 +242,10 @@ This is useful for agents executing molecules to see which steps can run next.`,
 					CommentCount: commentCounts[issue.ID],
 				}
 			}
+			if paginate {
+				outputJSON(issuePage{Issues: issuesWithCounts, NextCursor: nextCursor})
+				return
+			}
 			outputJSON(issuesWithCounts)
 			return
 		}
@@ -151,44 +253,86 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		maybeShowUpgradeNotification()
 
 		if len(issues) == 0 {
+			if hiddenByWIP > 0 {
+				printNoClaimableWork(hiddenByWIP)
+				return
+			}
 			// Check if there are any open issues at all
 			hasOpenIssues := false
 			if stats, statsErr := store.GetStatistics(ctx); statsErr == nil {
 				hasOpenIssues = stats.OpenIssues > 0 || stats.InProgressIssues > 0
 			}
-			if hasOpenIssues {
-				fmt.Printf("\n%s No ready work found (all issues have blocking dependencies)\n\n",
-					ui.RenderWarn("✨"))
-			} else {
-				fmt.Printf("\n%s No open issues\n\n", ui.RenderPass("✨"))
-			}
+			printNoReadyWork(hasOpenIssues)
 			// Show tip even when no ready work found
 			maybeShowTip(store)
 			return
 		}
-		if prettyFormat {
-			displayPrettyList(issues, false)
-		} else {
-			fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", ui.RenderAccent("📋"), len(issues))
-			for i, issue := range issues {
-				fmt.Printf("%d. [%s] [%s] %s: %s\n", i+1,
-					ui.RenderPriority(issue.Priority),
-					ui.RenderType(string(issue.IssueType)),
-					ui.RenderID(issue.ID), issue.Title)
-				if issue.EstimatedMinutes != nil {
-					fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
-				}
-				if issue.Assignee != "" {
-					fmt.Printf("   Assignee: %s\n", issue.Assignee)
-				}
-			}
-			fmt.Println()
+		printReadyIssues(issues, prettyFormat)
+		if paginate {
+			printNextReadyPage(nextCursor)
+		}
+		if hiddenByWIP > 0 {
+			printHiddenByWIP(hiddenByWIP)
 		}
 
 		// Show tip after successful ready (direct mode only)
 		maybeShowTip(store)
 	},
 }
+
+// printNoReadyWork explains an empty ready list.
+func printNoReadyWork(hasOpenIssues bool) {
+	if hasOpenIssues {
+		fmt.Printf("\n%s No ready work found (all issues have blocking dependencies)\n\n",
+			ui.RenderWarn("✨"))
+	} else {
+		fmt.Printf("\n%s No open issues\n\n", ui.RenderPass("✨"))
+	}
+}
+
+// printNoClaimableWork explains a ready list emptied by WIP limits.
+func printNoClaimableWork(hidden int) {
+	fmt.Printf("\n%s No ready work you can claim under the current WIP limits\n\n", ui.RenderWarn("✨"))
+	printHiddenByWIP(hidden)
+}
+
+// printHiddenByWIP notes ready issues hidden because claiming them would
+// exceed a WIP limit.
+func printHiddenByWIP(hidden int) {
+	fmt.Fprintf(os.Stderr, "%s %d ready issue(s) hidden by WIP limits (see 'fbd status'; --ignore-wip shows them)\n",
+		ui.RenderWarn("⚠"), hidden)
+}
+
+// printNextReadyPage shows how to fetch the page after a cursor-paginated
+// ready list.
+func printNextReadyPage(nextCursor string) {
+	if nextCursor != "" {
+		fmt.Fprintf(os.Stderr, "Next page: fbd ready --cursor %s\n", nextCursor)
+	}
+}
+
+// printReadyIssues renders ready work as a numbered list, or as a tree with --pretty.
+func printReadyIssues(issues []*types.Issue, prettyFormat bool) {
+	if prettyFormat {
+		displayPrettyList(issues, false)
+		return
+	}
+	fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", ui.RenderAccent("📋"), len(issues))
+	for i, issue := range issues {
+		fmt.Printf("%d. [%s] [%s] %s: %s\n", i+1,
+			ui.RenderPriority(issue.Priority),
+			ui.RenderType(string(issue.IssueType)),
+			ui.RenderID(issue.ID), issue.Title)
+		if issue.EstimatedMinutes != nil {
+			fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
+		}
+		if issue.Assignee != "" {
+			fmt.Printf("   Assignee: %s\n", issue.Assignee)
+		}
+	}
+	fmt.Println()
+}
+
 var blockedCmd = &cobra.Command{
 	Use:   "blocked",
 	Short: "Show blocked issues",
@@ -363,6 +507,7 @@ type MoleculeReadyOutput struct {
 
 func init() {
 	readyCmd.Flags().IntP("limit", "n", 10, "Maximum issues to show")
+	readyCmd.Flags().String("cursor", "", "Page cursor from a previous page's next_cursor (\"\" for the first page); with --json, outputs {issues, next_cursor}")
 	readyCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	readyCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
 	readyCmd.Flags().BoolP("unassigned", "u", false, "Show only unassigned issues")
@@ -376,6 +521,7 @@ func init() {
 	readyCmd.Flags().Bool("pretty", false, "Display issues in a tree format with status/priority symbols")
 	readyCmd.Flags().Bool("include-deferred", false, "Include issues with future defer_until timestamps")
 	readyCmd.Flags().Bool("gated", false, "Find molecules ready for gate-resume dispatch")
+	readyCmd.Flags().Bool("ignore-wip", false, "Show work that claiming would put over a WIP limit")
 	rootCmd.AddCommand(readyCmd)
 	blockedCmd.Flags().String("parent", "", "Filter to descendants of this bead/epic")
 	rootCmd.AddCommand(blockedCmd)
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/utils"
 	"github.com/steveyegge/fastbeads/internal/validation"
@@ -15,7 +17,13 @@ var searchCmd = &cobra.Command{
 	Use:     "search [query]",
 	GroupID: "issues",
 	Short:   "Search issues by text query",
-	Long: `Search issues across title, description, and ID.
+	Long: `Search issues across title, description, design, acceptance criteria,
+notes, comments, and ID.
+
+On SQLite, text is matched against a full-text index: every word must appear
+(words match as prefixes) and results are ranked by relevance. With --json,
+each result includes a "rank" and "highlights" with matched terms wrapped in
+<mark></mark>. Partial issue IDs are matched as substrings.
 
 Examples:
   fbd search "authentication bug"
@@ -205,7 +213,7 @@ Examples:
 
 		// Direct mode - search using store
 		// The query parameter in SearchIssues already searches across title, description, and id
-		issues, err := store.SearchIssues(ctx, query, filter)
+		issues, hits, err := searchIssuesWithHits(ctx, query, filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -215,7 +223,7 @@ Examples:
 		if len(issues) == 0 {
 			if checkAndAutoImport(ctx, store) {
 				// Re-run the search after import
-				issues, err = store.SearchIssues(ctx, query, filter)
+				issues, hits, err = searchIssuesWithHits(ctx, query, filter)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
@@ -253,21 +261,27 @@ Examples:
 				issue.Labels = labelsMap[issue.ID]
 			}
 
-			// Build response with counts
-			issuesWithCounts := make([]*types.IssueWithCounts, len(issues))
+			// Build response with counts and match highlights
+			results := make([]*searchResultJSON, len(issues))
 			for i, issue := range issues {
 				counts := depCounts[issue.ID]
 				if counts == nil {
 					counts = &types.DependencyCounts{DependencyCount: 0, DependentCount: 0}
 				}
-				issuesWithCounts[i] = &types.IssueWithCounts{
-					Issue:           issue,
-					DependencyCount: counts.DependencyCount,
-					DependentCount:  counts.DependentCount,
-					CommentCount:    commentCounts[issue.ID],
+				results[i] = &searchResultJSON{
+					IssueWithCounts: &types.IssueWithCounts{
+						Issue:           issue,
+						DependencyCount: counts.DependencyCount,
+						DependentCount:  counts.DependentCount,
+						CommentCount:    commentCounts[issue.ID],
+					},
+				}
+				if hit := hits[issue.ID]; hit != nil {
+					results[i].Rank = hit.Rank
+					results[i].Highlights = hit.Highlights
 				}
 			}
-			outputJSON(issuesWithCounts)
+			outputJSON(results)
 			return
 		}
 
@@ -285,6 +299,39 @@ Examples:
 	},
 }
 
+// searchResultJSON is the --json shape for a search result: the issue with its
+// counts, plus the relevance rank and highlights when the backend has a
+// full-text index.
+type searchResultJSON struct {
+	*types.IssueWithCounts
+	Rank       float64           `json:"rank,omitempty"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// searchIssuesWithHits runs a search, using ranked full-text search when the
+// backend supports it. The returned map holds rank/highlight data by issue ID
+// and is empty for backends without a text index.
+func searchIssuesWithHits(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, map[string]*types.SearchHit, error) {
+	hitsByID := make(map[string]*types.SearchHit)
+
+	ts, ok := store.(storage.TextSearcher)
+	if !ok {
+		issues, err := store.SearchIssues(ctx, query, filter)
+		return issues, hitsByID, err
+	}
+
+	hits, err := ts.SearchIssuesRanked(ctx, query, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	issues := make([]*types.Issue, len(hits))
+	for i, hit := range hits {
+		issues[i] = hit.Issue
+		hitsByID[hit.ID] = hit
+	}
+	return issues, hitsByID, nil
+}
+
 // outputSearchResults formats and displays search results
 func outputSearchResults(issues []*types.Issue, query string, longFormat bool) {
 	if len(issues) == 0 {
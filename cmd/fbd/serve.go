@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/beads"
+	"github.com/steveyegge/fastbeads/internal/lockfile"
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/webhooks"
+)
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	GroupID: "advanced",
+	Short:   "Run a local daemon that other fbd commands route through",
+	Long: `Run a local RPC daemon for this workspace in the foreground.
+
+The daemon opens the database once and serves it on a unix socket
+(.beads/bd.sock). While it is running, create, update, close, ready and show
+connect to it instead of opening the database themselves, so many short-lived
+fbd processes (e.g. parallel agents) don't contend for database locks.
+
+Routing is transparent and health-checked: if the daemon is not running,
+is unhealthy, or runs an incompatible version, commands fall back to direct
+database access. Use --no-daemon to bypass a running daemon, and 'fbd info'
+to see which mode a command used.
+
+Other commands still open the database directly. With the embedded Dolt
+backend the daemon holds the database lock, so those commands wait for it;
+stop the daemon before maintenance such as migrations.
+
+Stop the daemon with Ctrl-C, SIGTERM, or 'fbd serve stop'.
+
+HTTP gateway:
+  --http exposes the same operations as HTTP/JSON for tools that can't use
+  the unix socket (dashboards, scripts in other languages):
+
+    POST /v1/rpc               {"operation": "...", "args": {...}}
+    POST /v1/ops/<operation>   args as the body, e.g. /v1/ops/create
+    GET  /v1/issues[/<id>], /v1/ready, /v1/stats, /v1/health
+    GET  /v1/events            mutation events as Server-Sent Events
+
+  Responses use the RPC envelope {"success", "data", "error"}. Set a bearer
+  token with --http-token or BEADS_HTTP_TOKEN (event streams also accept
+  ?access_token=), and allow browser UIs on other origins with --cors-origin,
+  which requires a token. Browser requests from other origins, and requests
+  addressed to a host name other than localhost or the --http host, are
+  rejected. The shutdown operation is only available over the socket.
+
+Webhooks:
+  The daemon delivers issue events to the endpoints configured with
+  'fbd webhooks add', checking for new events every few seconds.
+
+Examples:
+  fbd serve &                                  # Start the daemon in the background
+  fbd serve --http 127.0.0.1:7681              # Also serve HTTP on localhost
+  fbd serve --http :7681 --http-token $TOKEN   # HTTP on all interfaces, with auth
+  fbd serve status                             # Show daemon status
+  fbd serve stop                               # Stop the daemon`,
+	Run: runServe,
+}
+
+var serveStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show status of the running daemon",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := connectToServe()
+		if client == nil {
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"running": false})
+				return
+			}
+			fmt.Println("Daemon is not running")
+			return
+		}
+		defer func() { _ = client.Close() }()
+
+		status, err := client.Status()
+		if err != nil {
+			FatalErrorRespectJSON("failed to get daemon status: %v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"running": true,
+				"status":  status,
+			})
+			return
+		}
+
+		fmt.Printf("%s Daemon is running (PID %d)\n", ui.RenderPass("✓"), status.PID)
+		fmt.Printf("  Version:   %s\n", status.Version)
+		fmt.Printf("  Database:  %s\n", status.DatabasePath)
+		fmt.Printf("  Socket:    %s\n", status.SocketPath)
+		fmt.Printf("  Uptime:    %s\n", formatUptime(status.UptimeSeconds))
+		if status.LastActivityTime != "" {
+			fmt.Printf("  Last used: %s\n", status.LastActivityTime)
+		}
+	},
+}
+
+var serveStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running daemon",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := connectToServe()
+		if client == nil {
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"stopped": false, "running": false})
+				return
+			}
+			fmt.Println("Daemon is not running")
+			return
+		}
+		defer func() { _ = client.Close() }()
+
+		if err := client.Shutdown(); err != nil {
+			FatalErrorRespectJSON("failed to stop daemon: %v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"stopped": true})
+			return
+		}
+		fmt.Printf("%s Daemon stopped\n", ui.RenderPass("✓"))
+	},
+}
+
+var (
+	serveHTTPAddr    string
+	serveHTTPToken   string
+	serveCORSOrigins []string
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http", "", "Also serve the HTTP/JSON gateway on this address (e.g. 127.0.0.1:7681)")
+	serveCmd.Flags().StringVar(&serveHTTPToken, "http-token", "", "Bearer token required by the HTTP gateway (default: $BEADS_HTTP_TOKEN)")
+	serveCmd.Flags().StringSliceVar(&serveCORSOrigins, "cors-origin", nil, "Origin allowed to call the HTTP gateway from a browser (repeatable, '*' for any)")
+	serveCmd.AddCommand(serveStatusCmd)
+	serveCmd.AddCommand(serveStopCmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe hosts the RPC server on the workspace socket until it is stopped.
+// The store opened by PersistentPreRun is handed to the server, which closes it.
+func runServe(cmd *cobra.Command, _ []string) {
+	if noDb {
+		FatalError("fbd serve requires a database (not available with --no-db)")
+	}
+	st := getStore()
+	if st == nil {
+		FatalError("no database found (run 'fbd init' first)")
+	}
+	// Errors are reported only after serve has released the daemon lock and
+	// removed daemon.pid; FatalError exits without running deferred cleanup.
+	if err := serve(st); err != nil {
+		FatalError("%v", err)
+	}
+	fmt.Printf("%s Daemon stopped\n", ui.RenderPass("✓"))
+}
+
+// serve runs the RPC server and optional HTTP gateway on st until they stop,
+// holding the daemon lock throughout.
+func serve(st storage.Storage) error {
+	beadsDir := filepath.Dir(dbPath)
+	workspace := filepath.Dir(beadsDir)
+	socketPath := rpc.ShortSocketPath(workspace)
+
+	lock, err := acquireServeLock(beadsDir)
+	if err != nil {
+		return err
+	}
+	defer releaseServeLock(beadsDir, lock)
+
+	rpc.ServerVersion = Version
+	server := rpc.NewServer(socketPath, st, workspace, daemonExpectedDBPath())
+
+	// The server owns the store from here on; keep PersistentPostRun from
+	// closing it a second time.
+	lockStore()
+	setStore(nil)
+	setStoreActive(false)
+	unlockStore()
+
+	// Deliver webhooks until the server stops. The dispatcher is stopped
+	// from within server.Stop, so it is done with the store before the
+	// server closes it, whichever way the server is stopped.
+	dispatchCtx, cancelDispatch := context.WithCancel(rootCtx)
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		webhooks.NewDispatcher(st).Run(dispatchCtx, webhooks.DefaultPollInterval, func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, format+"\n", args...)
+		})
+	}()
+	server.OnStop(func() {
+		cancelDispatch()
+		<-dispatchDone
+	})
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Start(rootCtx) }()
+
+	select {
+	case <-server.WaitReady():
+	case err := <-errCh:
+		_ = server.Stop()
+		return fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	fmt.Printf("%s Serving %s\n", ui.RenderPass("✓"), dbPath)
+	fmt.Printf("  Socket: %s\n", socketPath)
+	if serveHTTPAddr != "" {
+		addr, err := startServeHTTP(server)
+		if err != nil {
+			_ = server.Stop()
+			return err
+		}
+		fmt.Printf("  HTTP:   http://%s/v1/\n", addr)
+	}
+	fmt.Printf("  PID:    %d\n", os.Getpid())
+	fmt.Println("Press Ctrl-C or run 'fbd serve stop' to stop.")
+
+	// The server stops itself on SIGINT/SIGTERM and on a shutdown request
+	select {
+	case err = <-errCh:
+	case <-rootCtx.Done():
+		_ = server.Stop()
+		err = <-errCh
+	}
+	_ = server.Stop()
+	if err != nil {
+		return fmt.Errorf("daemon stopped: %w", err)
+	}
+	return nil
+}
+
+// startServeHTTP starts the HTTP gateway configured by --http.
+func startServeHTTP(server *rpc.Server) (net.Addr, error) {
+	token := serveHTTPToken
+	if token == "" {
+		token = os.Getenv("BEADS_HTTP_TOKEN")
+	}
+	if token == "" && len(serveCORSOrigins) > 0 {
+		return nil, fmt.Errorf("--cors-origin requires --http-token or BEADS_HTTP_TOKEN")
+	}
+	if token == "" && !isLoopbackAddr(serveHTTPAddr) {
+		fmt.Fprintf(os.Stderr, "Warning: HTTP gateway on %s has no token; anyone who can reach it can modify issues\n", serveHTTPAddr)
+		fmt.Fprintf(os.Stderr, "  Set --http-token or BEADS_HTTP_TOKEN, or bind to 127.0.0.1\n")
+	}
+
+	addr, err := server.StartHTTP(serveHTTPAddr, rpc.HTTPOptions{
+		Token:          token,
+		AllowedOrigins: serveCORSOrigins,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start HTTP gateway: %w", err)
+	}
+	return addr, nil
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local
+// connections. An empty host (":7681") listens on all interfaces.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// acquireServeLock takes the exclusive daemon lock in beadsDir and records
+// this process in daemon.lock and daemon.pid, so clients and 'fbd doctor'
+// can tell a daemon is running.
+func acquireServeLock(beadsDir string) (*os.File, error) {
+	lockPath := filepath.Join(beadsDir, "daemon.lock")
+	// #nosec G304 - controlled path from config
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open daemon lock: %w", err)
+	}
+	if err := lockfile.FlockExclusiveNonBlocking(f); err != nil {
+		_ = f.Close()
+		if lockfile.IsLocked(err) {
+			if running, pid := lockfile.TryDaemonLock(beadsDir); running && pid > 0 {
+				return nil, fmt.Errorf("daemon is already running (PID %d); stop it with 'fbd serve stop'", pid)
+			}
+			return nil, fmt.Errorf("daemon is already running; stop it with 'fbd serve stop'")
+		}
+		return nil, fmt.Errorf("failed to lock daemon lock: %w", err)
+	}
+
+	info := lockfile.LockInfo{
+		PID:       os.Getpid(),
+		ParentPID: os.Getppid(),
+		Database:  dbPath,
+		Version:   Version,
+		StartedAt: time.Now().UTC(),
+	}
+	data, _ := json.Marshal(info)
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt(data, 0)
+	}
+
+	pidPath := filepath.Join(beadsDir, "daemon.pid")
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write %s: %v\n", pidPath, err)
+	}
+	return f, nil
+}
+
+// releaseServeLock removes daemon.pid and releases the daemon lock.
+func releaseServeLock(beadsDir string, f *os.File) {
+	_ = os.Remove(filepath.Join(beadsDir, "daemon.pid"))
+	_ = lockfile.FlockUnlock(f)
+	_ = f.Close()
+}
+
+// connectToServe connects to the daemon for this workspace for 'fbd serve'
+// subcommands. Returns nil if no healthy daemon is running.
+func connectToServe() *rpc.Client {
+	if dbPath == "" {
+		dbPath = beads.FindDatabasePath()
+	}
+	socketPath := getSocketPath()
+	if socketPath == "" {
+		FatalErrorRespectJSON("no .beads directory found (run 'fbd init' first)")
+	}
+
+	rpc.ClientVersion = Version
+	client, err := rpc.TryConnect(socketPath)
+	if err != nil || client == nil {
+		return nil
+	}
+	client.SetDatabasePath(daemonExpectedDBPath())
+	return client
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/rpc"
+)
+
+// setupServeTestEnv starts an RPC server for a fresh workspace the way
+// 'fbd serve' does and points the CLI globals at it.
+func setupServeTestEnv(t *testing.T) (beadsDir string) {
+	t.Helper()
+
+	// Keep the socket path short (unix sockets are limited to ~104 bytes)
+	workspace, err := os.MkdirTemp("", "fbd-serve-")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(workspace) })
+
+	beadsDir = filepath.Join(workspace, ".beads")
+	testDBPath := filepath.Join(beadsDir, "beads.db")
+	st := newTestStore(t, testDBPath)
+
+	oldDBPath, oldNoDaemon, oldNoDb := dbPath, noDaemon, noDb
+	dbPath, noDaemon, noDb = testDBPath, false, false
+	t.Cleanup(func() {
+		closeDaemonClient()
+		dbPath, noDaemon, noDb = oldDBPath, oldNoDaemon, oldNoDb
+	})
+
+	rpc.ServerVersion = Version
+	server := rpc.NewServer(rpc.ShortSocketPath(workspace), st, workspace, testDBPath)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		_ = server.Stop()
+		<-errCh
+	})
+
+	select {
+	case <-server.WaitReady():
+	case err := <-errCh:
+		t.Fatalf("server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not become ready")
+	}
+	return beadsDir
+}
+
+func TestConnectToDaemon_RoutesWhenServing(t *testing.T) {
+	setupServeTestEnv(t)
+
+	if !connectToDaemon(showCmd, []string{"test-1"}) {
+		t.Fatalf("expected show to route through daemon, status: %+v", daemonStatus)
+	}
+	if daemonClient == nil {
+		t.Fatal("daemonClient not set")
+	}
+	if daemonStatus.Mode != DaemonModeDaemon || !daemonStatus.Connected {
+		t.Errorf("unexpected status: %+v", daemonStatus)
+	}
+	if _, err := daemonClient.Stats(); err != nil {
+		t.Errorf("Stats via daemon failed: %v", err)
+	}
+}
+
+func TestConnectToDaemon_Fallbacks(t *testing.T) {
+	setupServeTestEnv(t)
+
+	tests := []struct {
+		name     string
+		setup    func()
+		route    func() bool
+		expected string
+	}{
+		{
+			name:     "no-daemon flag",
+			setup:    func() { noDaemon = true },
+			route:    func() bool { return connectToDaemon(showCmd, nil) },
+			expected: FallbackFlagNoDaemon,
+		},
+		{
+			name:     "unrouted command",
+			route:    func() bool { return connectToDaemon(listCmd, nil) },
+			expected: FallbackNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noDaemon = false
+			if tt.setup != nil {
+				tt.setup()
+			}
+			if tt.route() {
+				closeDaemonClient()
+				t.Fatal("expected direct mode")
+			}
+			if daemonStatus.Mode != DaemonModeDirect {
+				t.Errorf("mode = %q, want %q", daemonStatus.Mode, DaemonModeDirect)
+			}
+			if daemonStatus.FallbackReason != tt.expected {
+				t.Errorf("fallback = %q, want %q", daemonStatus.FallbackReason, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConnectToDaemon_NotRunning(t *testing.T) {
+	workspace := t.TempDir()
+	oldDBPath, oldNoDaemon := dbPath, noDaemon
+	dbPath, noDaemon = filepath.Join(workspace, ".beads", "beads.db"), false
+	defer func() { dbPath, noDaemon = oldDBPath, oldNoDaemon }()
+
+	if connectToDaemon(showCmd, nil) {
+		closeDaemonClient()
+		t.Fatal("expected fallback with no daemon running")
+	}
+	if daemonStatus.FallbackReason != FallbackConnectFailed {
+		t.Errorf("fallback = %q, want %q", daemonStatus.FallbackReason, FallbackConnectFailed)
+	}
+}
+
+func TestAcquireServeLock(t *testing.T) {
+	beadsDir := t.TempDir()
+
+	lock, err := acquireServeLock(beadsDir)
+	if err != nil {
+		t.Fatalf("acquireServeLock: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "daemon.pid")); err != nil {
+		t.Errorf("daemon.pid not written: %v", err)
+	}
+
+	if second, err := acquireServeLock(beadsDir); err == nil {
+		releaseServeLock(beadsDir, second)
+		t.Fatal("expected second acquireServeLock to fail while locked")
+	}
+
+	releaseServeLock(beadsDir, lock)
+	if _, err := os.Stat(filepath.Join(beadsDir, "daemon.pid")); !os.IsNotExist(err) {
+		t.Errorf("daemon.pid not removed: %v", err)
+	}
+
+	lock, err = acquireServeLock(beadsDir)
+	if err != nil {
+		t.Fatalf("acquireServeLock after release: %v", err)
+	}
+	releaseServeLock(beadsDir, lock)
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:7681": true,
+		"localhost:7681": true,
+		"[::1]:7681":     true,
+		":7681":          false,
+		"0.0.0.0:7681":   false,
+		"10.0.0.5:7681":  false,
+		"not-an-addr":    false,
+	}
+	for addr, want := range tests {
+		if got := isLoopbackAddr(addr); got != want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
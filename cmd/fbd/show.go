@@ -1,19 +1,21 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/rpc"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/ui"
 )
 
 var showCmd = &cobra.Command{
 	Use:     "show [id...] [--id=<id>...]",
-	Aliases: []string{"view"},
 	GroupID: "issues",
 	Short:   "Show issue details",
 	Args:    cobra.ArbitraryArgs, // Allow zero positional args when --id is used
@@ -45,6 +47,14 @@ var showCmd = &cobra.Command{
 			FatalErrorRespectJSON("at least one issue ID is required (use positional args or --id flag)")
 		}
 
+		// History, thread and relationship views read the database directly,
+		// as do IDs (from --id) that route to another repository
+		if daemonClient != nil && (asOfRef != "" || showThread || showRefs || showChildren || anyNeedsRouting(args)) {
+			if err := ensureDirectMode("show option requires direct database access"); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
 		// Handle --as-of flag: show issue at a specific point in history
 		if asOfRef != "" {
 			showIssueAsOf(ctx, args, asOfRef, shortMode)
@@ -95,47 +105,27 @@ var showCmd = &cobra.Command{
 			return
 		}
 
-		// Direct mode - use routed resolution for cross-repo lookups
 		allDetails := []interface{}{}
 		foundCount := 0
 		for idx, id := range args {
-			// Resolve and get issue with routing (e.g., gt-xyz routes to gastown)
-			result, err := resolveAndGetIssueWithRouting(ctx, store, id)
-			if err != nil {
-				if result != nil {
-					result.Close()
-				}
-				fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
-				continue
+			var details *types.IssueDetails
+			if daemonClient != nil {
+				details = getIssueDetailsViaDaemon(id)
+			} else {
+				details = getIssueDetails(ctx, id, !shortMode)
 			}
-			if result == nil || result.Issue == nil {
-				if result != nil {
-					result.Close()
-				}
-				fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+			if details == nil {
 				continue
 			}
-			issue := result.Issue
-			issueStore := result.Store // Use the store that contains this issue
-			// Note: result.Close() called at end of loop iteration
+			issue := &details.Issue
 			foundCount++
 
 			if shortMode {
 				fmt.Println(formatShortIssue(issue))
-				result.Close()
 				continue
 			}
 
 			if jsonOutput {
-				// Include labels, dependencies (with metadata), dependents (with metadata), and comments in JSON output
-				details := &types.IssueDetails{Issue: *issue}
-				details.Labels, _ = issueStore.GetLabels(ctx, issue.ID)
-
-				// Get dependencies with metadata (dependency_type field)
-				details.Dependencies, _ = issueStore.GetDependenciesWithMetadata(ctx, issue.ID)
-				details.Dependents, _ = issueStore.GetDependentsWithMetadata(ctx, issue.ID)
-
-				details.Comments, _ = issueStore.GetIssueComments(ctx, issue.ID)
 				// Compute parent from dependencies
 				for _, dep := range details.Dependencies {
 					if dep.DependencyType == types.DepParentChild {
@@ -144,7 +134,6 @@ var showCmd = &cobra.Command{
 					}
 				}
 				allDetails = append(allDetails, details)
-				result.Close() // Close before continuing to next iteration
 				continue
 			}
 			if idx > 0 {
@@ -186,9 +175,8 @@ var showCmd = &cobra.Command{
 			}
 
 			// Show labels
-			labels, _ := issueStore.GetLabels(ctx, issue.ID)
-			if len(labels) > 0 {
-				fmt.Printf("\n%s %s\n", ui.RenderBold("LABELS:"), strings.Join(labels, ", "))
+			if len(details.Labels) > 0 {
+				fmt.Printf("\n%s %s\n", ui.RenderBold("LABELS:"), strings.Join(details.Labels, ", "))
 			}
 
 			// Collect related issues from both directions for deduplication
@@ -196,8 +184,7 @@ var showCmd = &cobra.Command{
 			relatedSeen := make(map[string]*types.IssueWithDependencyMetadata)
 
 			// Show dependencies - grouped by dependency type for clarity
-			depsWithMeta, _ := issueStore.GetDependenciesWithMetadata(ctx, issue.ID)
-			if len(depsWithMeta) > 0 {
+			if depsWithMeta := details.Dependencies; len(depsWithMeta) > 0 {
 				// Group by dependency type
 				var blocks, parent, discovered []*types.IssueWithDependencyMetadata
 				for _, dep := range depsWithMeta {
@@ -236,8 +223,7 @@ var showCmd = &cobra.Command{
 			}
 
 			// Show dependents - grouped by dependency type for clarity
-			dependentsWithMeta, _ := issueStore.GetDependentsWithMetadata(ctx, issue.ID)
-			if len(dependentsWithMeta) > 0 {
+			if dependentsWithMeta := details.Dependents; len(dependentsWithMeta) > 0 {
 				// Group by dependency type
 				var blocks, children, discovered []*types.IssueWithDependencyMetadata
 				for _, dep := range dependentsWithMeta {
@@ -284,10 +270,9 @@ var showCmd = &cobra.Command{
 			}
 
 			// Show comments
-			comments, _ := issueStore.GetIssueComments(ctx, issue.ID)
-			if len(comments) > 0 {
+			if len(details.Comments) > 0 {
 				fmt.Printf("\n%s\n", ui.RenderBold("COMMENTS"))
-				for _, comment := range comments {
+				for _, comment := range details.Comments {
 					fmt.Printf("  %s %s\n", ui.RenderMuted(formatTime(comment.CreatedAt)), comment.Author)
 					rendered := ui.RenderMarkdown(comment.Text)
 					// TrimRight removes trailing newlines that Glamour adds, preventing extra blank lines
@@ -298,7 +283,6 @@ var showCmd = &cobra.Command{
 			}
 
 			fmt.Println()
-			result.Close() // Close routed storage after each iteration
 		}
 
 		if jsonOutput {
@@ -311,8 +295,10 @@ var showCmd = &cobra.Command{
 				FatalErrorRespectJSON("no issues found matching the provided IDs")
 			}
 		} else if foundCount > 0 {
-			// Show tip after successful show (non-JSON mode)
-			maybeShowTip(store)
+			// Show tip after successful show (non-JSON, direct mode only)
+			if daemonClient == nil {
+				maybeShowTip(store)
+			}
 		} else {
 			os.Exit(1)
 		}
@@ -336,3 +322,70 @@ func init() {
 	showCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(showCmd)
 }
+
+// getIssueDetails resolves id (following cross-repo routes) and loads the
+// issue. Labels, dependencies and comments are loaded only when full is set.
+// Errors are reported to stderr and yield nil.
+func getIssueDetails(ctx context.Context, id string, full bool) *types.IssueDetails {
+	result, err := resolveAndGetIssueWithRouting(ctx, store, id)
+	if result != nil {
+		defer result.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+		return nil
+	}
+	if result == nil || result.Issue == nil {
+		fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+		return nil
+	}
+
+	issue := result.Issue
+	issueStore := result.Store // Use the store that contains this issue
+	details := &types.IssueDetails{Issue: *issue}
+	if full {
+		details.Labels, _ = issueStore.GetLabels(ctx, issue.ID)
+		details.Dependencies, _ = issueStore.GetDependenciesWithMetadata(ctx, issue.ID)
+		details.Dependents, _ = issueStore.GetDependentsWithMetadata(ctx, issue.ID)
+		details.Comments, _ = issueStore.GetIssueComments(ctx, issue.ID)
+	}
+	return details
+}
+
+// getIssueDetailsViaDaemon resolves id and loads the issue with its labels,
+// dependencies and comments from the daemon. Errors are reported to stderr
+// and yield nil.
+func getIssueDetailsViaDaemon(id string) *types.IssueDetails {
+	resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: id})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+		return nil
+	}
+	var resolvedID string
+	if err := json.Unmarshal(resp.Data, &resolvedID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+		return nil
+	}
+
+	resp, err = daemonClient.Show(&rpc.ShowArgs{ID: resolvedID})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+		return nil
+	}
+	var details types.IssueDetails
+	if err := json.Unmarshal(resp.Data, &details); err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+		return nil
+	}
+	return &details
+}
+
+// anyNeedsRouting reports whether any of ids routes to another repository.
+func anyNeedsRouting(ids []string) bool {
+	for _, id := range ids {
+		if needsRouting(id) {
+			return true
+		}
+	}
+	return false
+}
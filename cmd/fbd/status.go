@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/fastbeads/internal/beads"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 	"github.com/steveyegge/fastbeads/internal/ui"
 )
@@ -18,6 +20,7 @@ import (
 // StatusOutput represents the complete status output
 type StatusOutput struct {
 	Summary        *types.Statistics      `json:"summary"`
+	WIP            []*storage.WIPBucket   `json:"wip,omitempty"` // Utilization of configured WIP limits
 	RecentActivity *RecentActivitySummary `json:"recent_activity,omitempty"`
 }
 
@@ -41,7 +44,8 @@ var statusCmd = &cobra.Command{
 
 This command provides a summary of issue counts by state (open, in_progress,
 blocked, closed), ready work, extended statistics (tombstones, pinned issues,
-average lead time), and recent activity over the last 24 hours from git history.
+average lead time), utilization of WIP limits, and recent activity over the
+last 24 hours from git history.
 
 Similar to how 'git status' shows working tree state, 'fbd status' gives you
 a quick overview of your issue database without needing multiple queries.
@@ -96,8 +100,14 @@ Examples:
 			recentActivity = getGitActivity(24)
 		}
 
+		wip, err := storage.WIPUtilization(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
 		output := &StatusOutput{
 			Summary:        stats,
+			WIP:            wip,
 			RecentActivity: recentActivity,
 		}
 
@@ -136,6 +146,20 @@ Examples:
 			}
 		}
 
+		if len(wip) > 0 {
+			fmt.Printf("\nWIP Limits:\n")
+			for _, b := range wip {
+				usage := fmt.Sprintf("%d/%d", b.InProgress, b.Limit)
+				switch {
+				case b.InProgress >= b.Limit:
+					usage = ui.RenderFail(usage + " (full)")
+				case b.InProgress > 0:
+					usage = ui.RenderWarn(usage)
+				}
+				fmt.Printf("  %-24s%s\n", b.Scope+" "+b.Name+":", usage)
+			}
+		}
+
 		if recentActivity != nil {
 			fmt.Printf("\nRecent Activity (last %d hours):\n", recentActivity.HoursTracked)
 			fmt.Printf("  Commits:                %d\n", recentActivity.CommitCount)
@@ -820,6 +820,40 @@ func substituteVariables(text string, vars map[string]string) string {
 	})
 }
 
+// substituteMetadataVariables replaces {{variable}} patterns in the string
+// values of issue metadata, leaving keys and non-string values alone.
+// Metadata that isn't valid JSON is returned unchanged.
+func substituteMetadataVariables(metadata json.RawMessage, vars map[string]string) json.RawMessage {
+	if len(metadata) == 0 || len(vars) == 0 || !variablePattern.Match(metadata) {
+		return metadata
+	}
+	var value interface{}
+	if err := json.Unmarshal(metadata, &value); err != nil {
+		return metadata
+	}
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			return substituteVariables(v, vars)
+		case map[string]interface{}:
+			for k, child := range v {
+				v[k] = walk(child)
+			}
+		case []interface{}:
+			for i, child := range v {
+				v[i] = walk(child)
+			}
+		}
+		return v
+	}
+	out, err := json.Marshal(walk(value))
+	if err != nil {
+		return metadata
+	}
+	return out
+}
+
 // generateBondedID creates a custom ID for dynamically bonded molecules.
 // When bonding a proto to a parent molecule, this generates IDs like:
 //   - Root: parent.childref (e.g., "patrol-x7k.arm-ace")
@@ -934,6 +968,8 @@ func cloneSubgraph(ctx context.Context, s storage.Storage, subgraph *TemplateSub
 				AwaitType: oldIssue.AwaitType,
 				AwaitID:   substituteVariables(oldIssue.AwaitID, opts.Vars),
 				Timeout:   oldIssue.Timeout,
+				// Step metadata (e.g. on_complete)
+				Metadata:  substituteMetadataVariables(oldIssue.Metadata, opts.Vars),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			}
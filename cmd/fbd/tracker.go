@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/tracker"
+)
+
+// trackerCmd is the root command for the shared tracker sync engine.
+var trackerCmd = &cobra.Command{
+	Use:     "tracker",
+	GroupID: "advanced",
+	Short:   "Sync with external trackers through the shared sync engine",
+	Long: `Synchronize issues with an external tracker through the shared sync engine.
+
+Every registered tracker (jira, github, gitlab, linear) is a thin adapter
+over one engine that handles incremental fetches, three-way conflict
+detection against the last-synced snapshot, dry-run plans and stats.
+fbd jira|github|gitlab|linear sync run this same engine with their own
+flags, so the commands can be used interchangeably.
+
+An issue that changed on only one side since the last sync is synced in
+that direction. Only an issue that changed on both sides is a conflict.
+
+Examples:
+  fbd tracker list                      # Show registered trackers
+  fbd tracker sync jira --dry-run       # Show the sync plan
+  fbd tracker sync github               # Bidirectional sync
+  fbd tracker sync gitlab --pull-only   # Import only
+  fbd tracker sync linear --prefer-local`,
+}
+
+var trackerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered trackers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := tracker.Trackers()
+		if jsonOutput {
+			outputJSON(names)
+			return
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	},
+}
+
+var trackerSyncCmd = &cobra.Command{
+	Use:   "sync <tracker>",
+	Short: "Synchronize issues with a tracker",
+	Long: `Synchronize issues between beads and a registered tracker.
+
+Modes:
+  (no flags)     Bidirectional sync
+  --pull-only    Only apply tracker changes locally
+  --push-only    Only apply local changes to the tracker
+  --create-only  Push only creates tracker issues; never updates them
+
+Only issues changed since the last pull are fetched. Use --full to fetch
+everything, e.g. after changing field mappings.
+
+Conflict Resolution:
+  By default, the newer version wins. Override with:
+  --prefer-local    Always prefer the local version
+  --prefer-remote   Always prefer the tracker version
+
+JSON Output:
+  --json prints the plan, conflicts and per-action stats. The success,
+  last_sync and error fields and the pulled, pushed, created and updated
+  counts of the older per-tracker output are still included.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTrackerSync,
+}
+
+func init() {
+	trackerSyncCmd.Flags().Bool("dry-run", false, "Show the sync plan without making changes")
+	trackerSyncCmd.Flags().Bool("pull-only", false, "Only pull changes from the tracker")
+	trackerSyncCmd.Flags().Bool("push-only", false, "Only push changes to the tracker")
+	trackerSyncCmd.Flags().Bool("create-only", false, "Only create new tracker issues, don't update existing")
+	trackerSyncCmd.Flags().Bool("full", false, "Ignore the incremental cursor and fetch all issues")
+	trackerSyncCmd.Flags().Bool("prefer-local", false, "Prefer local version on conflicts")
+	trackerSyncCmd.Flags().Bool("prefer-remote", false, "Prefer tracker version on conflicts")
+	trackerSyncCmd.Flags().String("state", "all", "Tracker issue state to sync: open, closed, all")
+
+	trackerCmd.AddCommand(trackerListCmd)
+	trackerCmd.AddCommand(trackerSyncCmd)
+	rootCmd.AddCommand(trackerCmd)
+}
+
+func runTrackerSync(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	pullOnly, _ := cmd.Flags().GetBool("pull-only")
+	pushOnly, _ := cmd.Flags().GetBool("push-only")
+	createOnly, _ := cmd.Flags().GetBool("create-only")
+	full, _ := cmd.Flags().GetBool("full")
+	preferLocal, _ := cmd.Flags().GetBool("prefer-local")
+	preferRemote, _ := cmd.Flags().GetBool("prefer-remote")
+	state, _ := cmd.Flags().GetString("state")
+
+	if !dryRun {
+		CheckReadonly("tracker sync")
+	}
+
+	if pullOnly && pushOnly {
+		fmt.Fprintf(os.Stderr, "Error: cannot use both --pull-only and --push-only\n")
+		os.Exit(1)
+	}
+	if preferLocal && preferRemote {
+		fmt.Fprintf(os.Stderr, "Error: cannot use both --prefer-local and --prefer-remote\n")
+		os.Exit(1)
+	}
+
+	opts := tracker.SyncOptions{
+		Pull:       !pushOnly,
+		Push:       !pullOnly,
+		DryRun:     dryRun,
+		CreateOnly: createOnly,
+		Full:       full,
+		State:      state,
+		Strategy:   syncStrategy(preferLocal, preferRemote),
+	}
+	if err := syncTracker(args[0], opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// syncStrategy returns the conflict strategy selected by the prefer flags.
+func syncStrategy(preferLocal, preferRemote bool) tracker.ConflictStrategy {
+	switch {
+	case preferLocal:
+		return tracker.PreferLocal
+	case preferRemote:
+		return tracker.PreferRemote
+	default:
+		return tracker.PreferNewer
+	}
+}
+
+// syncTracker syncs the named tracker through the shared engine and prints
+// the plan or outcome. It backs fbd tracker sync and the per-tracker sync
+// commands, which differ only in their flags.
+func syncTracker(name string, opts tracker.SyncOptions) error {
+	if opts.State != "open" && opts.State != "closed" && opts.State != "all" {
+		return fmt.Errorf("invalid --state %q (expected open, closed or all)", opts.State)
+	}
+
+	if err := ensureStoreActive(); err != nil {
+		return fmt.Errorf("database not available: %w", err)
+	}
+
+	ctx := rootCtx
+	t, err := tracker.New(ctx, name, trackerConfig{store})
+	if err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		if opts.DryRun {
+			fmt.Printf("→ [DRY RUN] Planning sync with %s...\n", t.DisplayName())
+		} else {
+			fmt.Printf("→ Syncing with %s...\n", t.DisplayName())
+		}
+	}
+
+	result, err := tracker.NewEngine(t, store, actor).Sync(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("syncing with %s: %w", t.DisplayName(), err)
+		if jsonOutput {
+			outputJSON(trackerSyncJSON{Error: err.Error()})
+		}
+		return err
+	}
+
+	if jsonOutput {
+		outputJSON(newTrackerSyncJSON(result))
+		return nil
+	}
+	printTrackerSyncResult(t.DisplayName(), result)
+	return nil
+}
+
+// trackerSyncJSON is the --json output of a tracker sync: the engine result,
+// plus the success and error fields and the pulled/pushed/created/updated
+// counts the per-tracker sync commands reported before they used the engine.
+type trackerSyncJSON struct {
+	*tracker.Result
+	Success bool             `json:"success"`
+	Stats   trackerSyncStats `json:"stats"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type trackerSyncStats struct {
+	tracker.Stats
+	Pulled  int `json:"pulled"`
+	Pushed  int `json:"pushed"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+func newTrackerSyncJSON(result *tracker.Result) trackerSyncJSON {
+	s := result.Stats
+	return trackerSyncJSON{
+		Result:  result,
+		Success: true,
+		Stats: trackerSyncStats{
+			Stats:   s,
+			Pulled:  s.PullCreated + s.PullUpdated,
+			Pushed:  s.PushCreated + s.PushUpdated,
+			Created: s.PullCreated + s.PushCreated,
+			Updated: s.PullUpdated + s.PushUpdated,
+		},
+	}
+}
+
+// trackerConfig is the configuration adapters are created from: the store,
+// plus defaults the CLI infers, like github.repo from the origin remote.
+type trackerConfig struct {
+	storage.Storage
+}
+
+// GetConfig reads a config key. An unset github.repo falls back to
+// GITHUB_REPOSITORY and then to the origin remote, as in getGitHubConfig.
+func (c trackerConfig) GetConfig(ctx context.Context, key string) (string, error) {
+	value, err := c.Storage.GetConfig(ctx, key)
+	if err == nil && value == "" && key == "github.repo" && os.Getenv("GITHUB_REPOSITORY") == "" {
+		value = getGitHubRepoFromRemote()
+	}
+	return value, err
+}
+
+// printTrackerSyncResult prints a sync plan (dry run) or its outcome.
+func printTrackerSyncResult(displayName string, result *tracker.Result) {
+	if result.Incremental {
+		fmt.Printf("  Changes since %s\n", result.SyncedSince)
+	}
+	if result.DryRun {
+		for _, action := range result.Plan {
+			subject := action.IssueID
+			if subject == "" {
+				subject = action.Ref
+			}
+			fmt.Printf("  %-12s %s %s (%s)\n", action.Kind, subject, action.Title, action.Reason)
+		}
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Printf("  ⚠ conflict: %s (local %s, %s %s) → %s\n", conflict.IssueID,
+			conflict.LocalUpdated.Format("2006-01-02 15:04"), displayName,
+			conflict.RemoteUpdated.Format("2006-01-02 15:04"), conflict.Resolution)
+	}
+
+	s := result.Stats
+	prefix := "✓"
+	if result.DryRun {
+		prefix = "→ [DRY RUN] Would have"
+	}
+	fmt.Printf("%s pulled %d created, %d updated; pushed %d created, %d updated\n",
+		prefix, s.PullCreated, s.PullUpdated, s.PushCreated, s.PushUpdated)
+	var extra []string
+	if s.Unchanged > 0 {
+		extra = append(extra, fmt.Sprintf("%d unchanged", s.Unchanged))
+	}
+	if s.Skipped > 0 {
+		extra = append(extra, fmt.Sprintf("%d skipped", s.Skipped))
+	}
+	if s.Conflicts > 0 {
+		extra = append(extra, fmt.Sprintf("%d conflicts", s.Conflicts))
+	}
+	if s.Dependencies > 0 {
+		extra = append(extra, fmt.Sprintf("%d dependencies", s.Dependencies))
+	}
+	if s.Errors > 0 {
+		extra = append(extra, fmt.Sprintf("%d errors", s.Errors))
+	}
+	if len(extra) > 0 {
+		fmt.Printf("  %s\n", strings.Join(extra, ", "))
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  ⚠ %s\n", w)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+)
+
+func TestTrackerSyncJSONKeepsLegacyFields(t *testing.T) {
+	result := &tracker.Result{
+		Tracker:  "linear",
+		LastSync: "2026-01-02T03:04:05Z",
+		Stats:    tracker.Stats{PullCreated: 2, PullUpdated: 1, PushCreated: 3, Skipped: 4, Errors: 1},
+	}
+	data, err := json.Marshal(newTrackerSyncJSON(result))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Success  bool           `json:"success"`
+		Tracker  string         `json:"tracker"`
+		LastSync string         `json:"last_sync"`
+		Stats    map[string]int `json:"stats"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Success || got.Tracker != "linear" || got.LastSync != result.LastSync {
+		t.Errorf("top-level fields = %s", data)
+	}
+	want := map[string]int{
+		"pulled": 3, "pushed": 3, "created": 5, "updated": 1, "skipped": 4, "errors": 1,
+		"pull_created": 2, "pull_updated": 1, "push_created": 3,
+	}
+	for key, n := range want {
+		if got.Stats[key] != n {
+			t.Errorf("stats.%s = %d, want %d", key, got.Stats[key], n)
+		}
+	}
+
+	data, err = json.Marshal(trackerSyncJSON{Error: "boom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed map[string]interface{}
+	if err := json.Unmarshal(data, &failed); err != nil {
+		t.Fatal(err)
+	}
+	if failed["success"] != false || failed["error"] != "boom" {
+		t.Errorf("failed sync = %s", data)
+	}
+}
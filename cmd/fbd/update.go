@@ -285,6 +285,9 @@ create, update, show, or close operation).`,
 				if status, ok := updates["status"].(string); ok {
 					updateArgs.Status = &status
 				}
+				if session, ok := updates["closed_by_session"].(string); ok {
+					updateArgs.ClosedBySession = &session
+				}
 				if priority, ok := updates["priority"].(int); ok {
 					updateArgs.Priority = &priority
 				}
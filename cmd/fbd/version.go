@@ -74,7 +74,7 @@ func showDaemonVersion() {
 	client, err := rpc.TryConnect(socketPath)
 	if err != nil || client == nil {
 		fmt.Fprintf(os.Stderr, "Error: daemon is not running\n")
-		fmt.Fprintf(os.Stderr, "Hint: start daemon with 'fbd daemon'\n")
+		fmt.Fprintf(os.Stderr, "Hint: start daemon with 'fbd serve'\n")
 		os.Exit(1)
 	}
 	defer func() { _ = client.Close() }()
@@ -98,7 +98,7 @@ func showDaemonVersion() {
 		if health.Compatible {
 			fmt.Printf("Compatibility: ✓ compatible\n")
 		} else {
-			fmt.Printf("Compatibility: ✗ incompatible (restart daemon recommended)\n")
+			fmt.Printf("Compatibility: ✗ incompatible (restart with 'fbd serve stop' and 'fbd serve')\n")
 		}
 		fmt.Printf("Daemon uptime: %.1f seconds\n", health.Uptime)
 	}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/beads"
+	"github.com/steveyegge/fastbeads/internal/query"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/views"
+)
+
+var viewCmd = &cobra.Command{
+	Use:     "view",
+	GroupID: "views",
+	Short:   "Manage saved queries (named views)",
+	Long: `Manage named views: saved query expressions with their sort, columns and
+output format.
+
+Views are stored in .beads/views.yaml. Commit that file to share the same
+triage views with the whole team.
+
+Examples:
+  fbd view save triage "status=open AND assignee=none" --sort priority
+  fbd view save my-bugs "type=bug AND assignee=alice" --format table --columns id,priority,title
+  fbd view list
+  fbd view run triage
+  fbd list --view triage --limit 10
+  fbd view delete triage
+
+The query syntax is the same as 'fbd query' (see 'fbd help query').
+
+For compatibility, 'fbd view <id>' still shows an issue like 'fbd show <id>'.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 'fbd view' used to be an alias for 'fbd show'
+		if ids, _ := cmd.Flags().GetStringArray("id"); len(args) > 0 || len(ids) > 0 {
+			showCmd.Run(cmd, args)
+			return
+		}
+		if err := cmd.Help(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error displaying help: %v\n", err)
+		}
+	},
+}
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save <name> <query>",
+	Short: "Save a query as a named view (replaces an existing view)",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		view := views.View{
+			Name:  args[0],
+			Query: strings.Join(args[1:], " "),
+		}
+		view.Description, _ = cmd.Flags().GetString("description")
+		view.Sort, _ = cmd.Flags().GetString("sort")
+		view.Reverse, _ = cmd.Flags().GetBool("reverse")
+		view.Columns, _ = cmd.Flags().GetStringSlice("columns")
+		view.Format, _ = cmd.Flags().GetString("format")
+		view.Limit, _ = cmd.Flags().GetInt("limit")
+		view.All, _ = cmd.Flags().GetBool("all")
+
+		beadsDir := requireViewsDir()
+		replaced, err := views.SaveView(beadsDir, view)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		view.Name = views.NormalizeName(view.Name)
+
+		if jsonOutput {
+			outputJSON(viewJSON(view))
+			return
+		}
+		verb := "Saved"
+		if replaced {
+			verb = "Updated"
+		}
+		fmt.Printf("%s %s view '%s'\n", ui.RenderPass("✓"), verb, view.Name)
+		fmt.Printf("  Run it with: fbd view run %s\n", view.Name)
+	},
+}
+
+var viewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved views",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		beadsDir := requireViewsDir()
+		names, err := views.ListViewNames(beadsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		all, _ := views.LoadViews(beadsDir)
+
+		if jsonOutput {
+			result := make([]viewOutput, 0, len(names))
+			for _, name := range names {
+				result = append(result, viewJSON(all[name]))
+			}
+			outputJSON(result)
+			return
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No saved views.")
+			fmt.Println("Use 'fbd view save <name> <query>' to create one.")
+			return
+		}
+
+		width := 0
+		for _, name := range names {
+			if len(name) > width {
+				width = len(name)
+			}
+		}
+		for _, name := range names {
+			view := all[name]
+			fmt.Printf("  %-*s  %s\n", width, name, view.Query)
+			if view.Description != "" {
+				fmt.Printf("  %-*s  %s\n", width, "", ui.RenderMuted(view.Description))
+			}
+		}
+	},
+}
+
+var viewRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved view",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runView(cmd, args[0])
+	},
+}
+
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := views.NormalizeName(args[0])
+		if err := views.DeleteView(requireViewsDir(), name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]string{"deleted": name})
+			return
+		}
+		fmt.Printf("%s Deleted view '%s'\n", ui.RenderPass("✓"), name)
+	},
+}
+
+// viewOutput is the JSON shape of a saved view.
+type viewOutput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Query       string   `json:"query"`
+	Sort        string   `json:"sort,omitempty"`
+	Reverse     bool     `json:"reverse,omitempty"`
+	Columns     []string `json:"columns,omitempty"`
+	Format      string   `json:"format"`
+	Limit       int      `json:"limit,omitempty"`
+	All         bool     `json:"all,omitempty"`
+}
+
+func viewJSON(v views.View) viewOutput {
+	return viewOutput{
+		Name:        v.Name,
+		Description: v.Description,
+		Query:       v.Query,
+		Sort:        v.Sort,
+		Reverse:     v.Reverse,
+		Columns:     v.Columns,
+		Format:      v.EffectiveFormat(),
+		Limit:       v.Limit,
+		All:         v.All,
+	}
+}
+
+// requireViewsDir returns the .beads directory holding views.yaml, exiting if
+// there is no beads project here.
+func requireViewsDir() string {
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: no .beads directory found (run 'fbd init' first)\n")
+		os.Exit(1)
+	}
+	return beadsDir
+}
+
+// listViewFlags are the fbd list flags that may be combined with --view.
+// Filter flags are rejected since the view's query is the filter.
+var listViewFlags = map[string]bool{
+	"view":     true,
+	"limit":    true,
+	"sort":     true,
+	"reverse":  true,
+	"long":     true,
+	"all":      true,
+	"no-pager": true,
+}
+
+// runView runs a saved view. The limit, sort, reverse, long and all flags on
+// cmd, when set, override the view's saved settings; --json overrides its
+// format.
+func runView(cmd *cobra.Command, name string) {
+	view, err := views.GetView(requireViewsDir(), name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("limit") {
+		view.Limit, _ = flags.GetInt("limit")
+	}
+	if flags.Changed("sort") {
+		view.Sort, _ = flags.GetString("sort")
+	}
+	if flags.Changed("reverse") {
+		view.Reverse, _ = flags.GetBool("reverse")
+	}
+	if flags.Changed("all") {
+		view.All, _ = flags.GetBool("all")
+	}
+	if long, _ := flags.GetBool("long"); long {
+		view.Format = views.FormatLong
+	}
+	if jsonOutput {
+		view.Format = views.FormatJSON
+	}
+
+	node, err := query.Parse(view.Query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing query for view %s: %v\n", view.Name, err)
+		os.Exit(1)
+	}
+
+	ctx := rootCtx
+	result, _, err := evaluateQuery(ctx, node, view.All)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error evaluating query for view %s: %v\n", view.Name, err)
+		os.Exit(1)
+	}
+
+	requireFreshDB(ctx)
+
+	if store == nil {
+		fmt.Fprintf(os.Stderr, "Error: no storage available\n")
+		os.Exit(1)
+	}
+
+	issues, err := runQuery(ctx, result, view.Limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	sortIssues(issues, view.Sort, view.Reverse)
+
+	switch view.EffectiveFormat() {
+	case views.FormatJSON:
+		outputIssuesWithCounts(ctx, issues)
+		return
+	case views.FormatTable:
+		loadIssueLabels(ctx, issues)
+		if len(issues) == 0 {
+			fmt.Printf("No issues found for view: %s\n", view.Name)
+			return
+		}
+		fmt.Print(formatIssueTable(issues, view.EffectiveColumns()))
+	default:
+		if !result.RequiresLabels {
+			loadIssueLabels(ctx, issues)
+		}
+		outputQueryResults(issues, view.Query, view.EffectiveFormat() == views.FormatLong)
+	}
+}
+
+// formatIssueTable renders issues as aligned columns with a header row.
+// The last column is not padded so long titles don't leave trailing spaces.
+func formatIssueTable(issues []*types.Issue, columns []string) string {
+	rows := make([][]string, 0, len(issues)+1)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = strings.ToUpper(col)
+	}
+	rows = append(rows, header)
+	for _, issue := range issues {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = issueColumnValue(issue, col)
+		}
+		rows = append(rows, row)
+	}
+	return formatTable(rows)
+}
+
+// formatTable renders rows as aligned columns; the first row is a bold
+// header. The last column is not padded.
+func formatTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var buf strings.Builder
+	for r, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			if i == len(row)-1 {
+				line.WriteString(cell)
+			} else {
+				line.WriteString(cell)
+				line.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))))
+			}
+		}
+		if r == 0 {
+			buf.WriteString(ui.RenderBold(line.String()))
+		} else {
+			buf.WriteString(line.String())
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// issueColumnValue returns the plain-text value of a table column.
+func issueColumnValue(issue *types.Issue, column string) string {
+	switch column {
+	case "id":
+		return issue.ID
+	case "title":
+		return issue.Title
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return fmt.Sprintf("P%d", issue.Priority)
+	case "type":
+		return string(issue.IssueType)
+	case "assignee":
+		return issue.Assignee
+	case "owner":
+		return issue.Owner
+	case "labels":
+		return strings.Join(issue.Labels, ",")
+	case "created":
+		return issue.CreatedAt.Format("2006-01-02")
+	case "updated":
+		return issue.UpdatedAt.Format("2006-01-02")
+	case "closed":
+		if issue.ClosedAt != nil {
+			return issue.ClosedAt.Format("2006-01-02")
+		}
+	case "due":
+		if issue.DueAt != nil {
+			return issue.DueAt.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+func init() {
+	viewSaveCmd.Flags().String("description", "", "Description shown in 'fbd view list'")
+	viewSaveCmd.Flags().String("sort", "", "Sort by field: "+strings.Join(views.ValidSorts, ", "))
+	viewSaveCmd.Flags().BoolP("reverse", "r", false, "Reverse sort order")
+	viewSaveCmd.Flags().StringSlice("columns", nil, "Columns for table format: "+strings.Join(views.ValidColumns, ", "))
+	viewSaveCmd.Flags().String("format", "", "Output format: "+strings.Join(views.ValidFormats, ", ")+" (default compact)")
+	viewSaveCmd.Flags().IntP("limit", "n", 0, "Limit results (0 = unlimited)")
+	viewSaveCmd.Flags().BoolP("all", "a", false, "Include closed issues")
+
+	viewRunCmd.Flags().IntP("limit", "n", 0, "Override the view's limit (0 = unlimited)")
+	viewRunCmd.Flags().String("sort", "", "Override the view's sort field")
+	viewRunCmd.Flags().BoolP("reverse", "r", false, "Override the view's sort order")
+	viewRunCmd.Flags().Bool("long", false, "Show detailed multi-line output for each issue")
+	viewRunCmd.Flags().BoolP("all", "a", false, "Include closed issues")
+
+	// Accept show's flags so 'fbd view <id> --short' keeps working
+	viewCmd.Flags().AddFlagSet(showCmd.Flags())
+	viewCmd.ValidArgsFunction = issueIDCompletion
+
+	viewCmd.AddCommand(viewSaveCmd)
+	viewCmd.AddCommand(viewListCmd)
+	viewCmd.AddCommand(viewRunCmd)
+	viewCmd.AddCommand(viewDeleteCmd)
+	rootCmd.AddCommand(viewCmd)
+}
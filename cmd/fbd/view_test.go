@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestFormatIssueTable(t *testing.T) {
+	closed := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Short", Priority: 1, Labels: []string{"a", "b"}},
+		{ID: "bd-100", Title: "A much longer title", Priority: 3, ClosedAt: &closed},
+	}
+
+	out := formatIssueTable(issues, []string{"id", "priority", "labels", "closed", "title"})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d lines:\n%s", len(lines), out)
+	}
+	if !strings.Contains(lines[0], "PRIORITY") {
+		t.Errorf("header missing PRIORITY: %q", lines[0])
+	}
+	if lines[1] != "bd-1    P1        a,b                 Short" {
+		t.Errorf("unexpected row 1: %q", lines[1])
+	}
+	if lines[2] != "bd-100  P3                2025-03-02  A much longer title" {
+		t.Errorf("unexpected row 2: %q", lines[2])
+	}
+}
+
+func TestListViewFlagsExcludeFilters(t *testing.T) {
+	// Every allowed override must exist on both list and view run
+	for name := range listViewFlags {
+		if listCmd.Flags().Lookup(name) == nil {
+			t.Errorf("list has no --%s flag", name)
+		}
+		if name != "view" && name != "no-pager" && viewRunCmd.Flags().Lookup(name) == nil {
+			t.Errorf("view run has no --%s flag", name)
+		}
+	}
+	if listViewFlags["status"] || listViewFlags["label"] {
+		t.Error("filter flags must not be combinable with --view")
+	}
+}
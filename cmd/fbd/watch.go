@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+	"github.com/steveyegge/fastbeads/internal/ui"
+)
+
+var watchCmd = &cobra.Command{
+	Use:     "watch",
+	GroupID: "views",
+	Short:   "Stream issue changes as they happen",
+	Long: `Print create, update, close and dependency events as they happen, until
+interrupted.
+
+With 'fbd serve' running, watch subscribes to the daemon's mutation stream.
+Otherwise it tails the database's audit events, polling every --interval.
+
+Every event carries a cursor. Pass the last one to --since to resume
+after a restart without missing events:
+  m:<unix ms>   daemon cursor; replayed from the daemon's recent mutations
+                (the last 100), with a warning if some may have been lost
+  e:<event id>  database cursor; replayed exactly from the audit log
+
+--filter takes a query expression (see 'fbd help query') and only shows
+events for issues that match it after the change.
+
+Examples:
+  fbd watch
+  fbd watch --json
+  fbd watch --filter "status=open AND assignee=none"
+  fbd watch --since m:1767225600000 --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filterExpr, _ := cmd.Flags().GetString("filter")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		since, err := parseWatchCursor(sinceStr)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		var filter *rpc.MutationFilter
+		if filterExpr != "" {
+			if filter, err = rpc.NewMutationFilter(filterExpr); err != nil {
+				FatalError("%v", err)
+			}
+		}
+		if interval <= 0 {
+			FatalError("--interval must be positive")
+		}
+
+		emit := printWatchEvent
+		if jsonOutput {
+			encoder := json.NewEncoder(os.Stdout)
+			emit = func(event watchEvent) { _ = encoder.Encode(event) }
+		}
+
+		ctx := rootCtx
+		if daemonClient != nil && since.kind != watchCursorEvent {
+			err = watchDaemon(ctx, filterExpr, since, emit)
+		} else {
+			if err := ensureDirectMode("watch: resuming from a database cursor"); err != nil {
+				FatalError("%v", err)
+			}
+			err = watchEvents(ctx, store, filter, since, interval, emit)
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			FatalError("%v", err)
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().String("filter", "", "Only show events for issues matching this query expression")
+	watchCmd.Flags().String("since", "", "Resume after this cursor (m:<unix ms> or e:<event id>)")
+	watchCmd.Flags().Duration("interval", time.Second, "Poll interval when no daemon is running")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchEvent is one line of 'fbd watch' output, from either the daemon's
+// mutation stream or the audit log.
+type watchEvent struct {
+	Cursor      string    `json:"cursor"`
+	Type        string    `json:"type"`
+	IssueID     string    `json:"issue_id"`
+	Title       string    `json:"title,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+	OldStatus   string    `json:"old_status,omitempty"`
+	NewStatus   string    `json:"new_status,omitempty"`
+	DependsOnID string    `json:"depends_on_id,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Watch event types beyond the rpc.Mutation* ones they are derived from
+const (
+	watchEventClose  = "close"
+	watchEventReopen = "reopen"
+)
+
+type watchCursorKind int
+
+const (
+	watchCursorNone     watchCursorKind = iota
+	watchCursorMutation                 // m:<unix ms>, daemon mutation timestamp
+	watchCursorEvent                    // e:<id>, audit event ID
+)
+
+type watchCursor struct {
+	kind  watchCursorKind
+	value int64
+}
+
+func parseWatchCursor(s string) (watchCursor, error) {
+	if s == "" {
+		return watchCursor{}, nil
+	}
+	prefix, value, ok := strings.Cut(s, ":")
+	n, err := strconv.ParseInt(value, 10, 64)
+	if !ok || err != nil || n < 0 {
+		return watchCursor{}, fmt.Errorf("invalid cursor %q (expected m:<unix ms> or e:<event id>)", s)
+	}
+	switch prefix {
+	case "m":
+		return watchCursor{kind: watchCursorMutation, value: n}, nil
+	case "e":
+		return watchCursor{kind: watchCursorEvent, value: n}, nil
+	}
+	return watchCursor{}, fmt.Errorf("invalid cursor %q (expected m:<unix ms> or e:<event id>)", s)
+}
+
+// watchDaemon prints events from the daemon's mutation stream until ctx is
+// cancelled or the daemon stops.
+func watchDaemon(ctx context.Context, filterExpr string, since watchCursor, emit func(watchEvent)) error {
+	args := &rpc.SubscribeArgs{Filter: filterExpr}
+	if since.kind == watchCursorMutation {
+		args.Since = &since.value
+	}
+	client := daemonClient
+	sub, err := client.Subscribe(args)
+	if err != nil {
+		return err
+	}
+	if sub.Result.Gap {
+		fmt.Fprintf(os.Stderr, "%s events since %s may have been missed (daemon restarted or buffer overflowed)\n",
+			ui.RenderWarn("Warning:"), formatWatchCursor(since))
+	}
+
+	// Closing the client unblocks Next on interrupt
+	go func() {
+		<-ctx.Done()
+		_ = client.Close()
+	}()
+
+	for {
+		event, err := sub.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("daemon closed the event stream")
+			}
+			return err
+		}
+		emit(watchEventFromMutation(*event))
+	}
+}
+
+// watchEvents prints audit events as they are recorded, polling every
+// interval, until ctx is cancelled. Without a cursor it starts at the
+// newest event.
+func watchEvents(ctx context.Context, s storage.Storage, filter *rpc.MutationFilter, since watchCursor, interval time.Duration, emit func(watchEvent)) error {
+	lastID := since.value
+	if since.kind != watchCursorEvent {
+		// Start at the newest event, or for a daemon cursor at the last event
+		// before the cursor's second. Audit timestamps have second precision,
+		// so this may repeat a few events but never drops one.
+		var before time.Time
+		if since.kind == watchCursorMutation {
+			before = time.UnixMilli(since.value).Truncate(time.Second)
+		}
+		var err error
+		if lastID, err = s.GetLastEventID(ctx, before); err != nil {
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		events, err := s.GetAllEventsSince(ctx, lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+		for _, event := range events {
+			lastID = event.ID
+			if filter != nil {
+				if ok, _ := filter.Match(ctx, s, event.IssueID); !ok {
+					continue
+				}
+			}
+			emit(watchEventFromAudit(ctx, s, event))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchEventFromMutation(event rpc.MutationEvent) watchEvent {
+	w := watchEvent{
+		Cursor:      formatWatchCursor(watchCursor{kind: watchCursorMutation, value: event.Timestamp.UnixMilli()}),
+		Type:        event.Type,
+		IssueID:     event.IssueID,
+		Title:       event.Title,
+		Assignee:    event.Assignee,
+		Actor:       event.Actor,
+		OldStatus:   event.OldStatus,
+		NewStatus:   event.NewStatus,
+		DependsOnID: event.DependsOnID,
+		Timestamp:   event.Timestamp,
+	}
+	if event.Type == rpc.MutationStatus {
+		switch {
+		case event.NewStatus == string(types.StatusClosed):
+			w.Type = watchEventClose
+		case event.OldStatus == string(types.StatusClosed):
+			w.Type = watchEventReopen
+		}
+	}
+	return w
+}
+
+func watchEventFromAudit(ctx context.Context, s storage.Storage, event *types.Event) watchEvent {
+	w := watchEvent{
+		Cursor:    formatWatchCursor(watchCursor{kind: watchCursorEvent, value: event.ID}),
+		Type:      string(event.EventType),
+		IssueID:   event.IssueID,
+		Actor:     event.Actor,
+		Timestamp: event.CreatedAt,
+	}
+	switch event.EventType {
+	case types.EventCreated:
+		w.Type = rpc.MutationCreate
+	case types.EventUpdated, types.EventLabelAdded, types.EventLabelRemoved:
+		w.Type = rpc.MutationUpdate
+	case types.EventStatusChanged:
+		w.Type = rpc.MutationStatus
+	case types.EventClosed:
+		w.Type = watchEventClose
+		w.NewStatus = string(types.StatusClosed)
+	case types.EventReopened:
+		w.Type = watchEventReopen
+		w.OldStatus = string(types.StatusClosed)
+	case types.EventCommented:
+		w.Type = rpc.MutationComment
+	case types.EventDependencyAdded:
+		w.Type = rpc.MutationDepAdd
+		if event.NewValue != nil {
+			w.DependsOnID = *event.NewValue
+		}
+	case types.EventDependencyRemoved:
+		w.Type = rpc.MutationDepRemove
+		if event.OldValue != nil {
+			w.DependsOnID = *event.OldValue
+		}
+	case "deleted":
+		w.Type = rpc.MutationDelete
+	}
+
+	// Audit events don't carry display context; look it up (best effort)
+	if issue, err := s.GetIssue(ctx, event.IssueID); err == nil && issue != nil {
+		w.Title = issue.Title
+		w.Assignee = issue.Assignee
+	}
+	return w
+}
+
+func formatWatchCursor(c watchCursor) string {
+	switch c.kind {
+	case watchCursorMutation:
+		return fmt.Sprintf("m:%d", c.value)
+	case watchCursorEvent:
+		return fmt.Sprintf("e:%d", c.value)
+	}
+	return "start"
+}
+
+func printWatchEvent(event watchEvent) {
+	var detail string
+	switch event.Type {
+	case rpc.MutationStatus:
+		detail = fmt.Sprintf("%s → %s", event.OldStatus, event.NewStatus)
+	case rpc.MutationDepAdd:
+		detail = "→ depends on " + ui.RenderID(event.DependsOnID)
+	case rpc.MutationDepRemove:
+		detail = "→ no longer depends on " + ui.RenderID(event.DependsOnID)
+	}
+
+	line := fmt.Sprintf("%s %-10s %s", ui.RenderMuted(event.Timestamp.Local().Format("15:04:05")),
+		event.Type, ui.RenderID(event.IssueID))
+	if event.Title != "" {
+		line += " " + event.Title
+	}
+	if detail != "" {
+		line += " " + detail
+	}
+	if event.Actor != "" {
+		line += ui.RenderMuted(" (" + event.Actor + ")")
+	}
+	fmt.Println(line)
+}
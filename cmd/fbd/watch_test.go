@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/rpc"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestParseWatchCursor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    watchCursor
+		wantErr bool
+	}{
+		{in: "", want: watchCursor{}},
+		{in: "m:1767225600000", want: watchCursor{kind: watchCursorMutation, value: 1767225600000}},
+		{in: "e:42", want: watchCursor{kind: watchCursorEvent, value: 42}},
+		{in: "42", wantErr: true},
+		{in: "x:42", wantErr: true},
+		{in: "e:-1", wantErr: true},
+		{in: "e:abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseWatchCursor(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseWatchCursor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseWatchCursor(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if !tt.wantErr && tt.in != "" && formatWatchCursor(got) != tt.in {
+			t.Errorf("formatWatchCursor(%+v) = %q, want %q", got, formatWatchCursor(got), tt.in)
+		}
+	}
+}
+
+func TestWatchEventFromMutation(t *testing.T) {
+	ts := time.UnixMilli(1767225600000)
+	tests := []struct {
+		event rpc.MutationEvent
+		want  string
+	}{
+		{rpc.MutationEvent{Type: rpc.MutationCreate}, rpc.MutationCreate},
+		{rpc.MutationEvent{Type: rpc.MutationStatus, OldStatus: "open", NewStatus: "closed"}, watchEventClose},
+		{rpc.MutationEvent{Type: rpc.MutationStatus, OldStatus: "closed", NewStatus: "open"}, watchEventReopen},
+		{rpc.MutationEvent{Type: rpc.MutationStatus, OldStatus: "open", NewStatus: "in_progress"}, rpc.MutationStatus},
+	}
+	for _, tt := range tests {
+		tt.event.Timestamp = ts
+		got := watchEventFromMutation(tt.event)
+		if got.Type != tt.want {
+			t.Errorf("%s %s->%s: type = %q, want %q", tt.event.Type, tt.event.OldStatus, tt.event.NewStatus, got.Type, tt.want)
+		}
+		if got.Cursor != "m:1767225600000" {
+			t.Errorf("cursor = %q", got.Cursor)
+		}
+	}
+}
+
+func TestWatchEvents_TailsAuditLog(t *testing.T) {
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	ctx := context.Background()
+
+	blocker := &types.Issue{Title: "Blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	blocked := &types.Issue{Title: "Blocked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{blocker, blocked} {
+		if err := s.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, "alice"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if err := s.CloseIssue(ctx, blocker.ID, "done", "bob", ""); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	if err := s.RemoveDependency(ctx, blocked.ID, blocker.ID, "bob"); err != nil {
+		t.Fatalf("RemoveDependency: %v", err)
+	}
+
+	collect := func(since watchCursor, filterExpr string) []watchEvent {
+		t.Helper()
+		var filter *rpc.MutationFilter
+		if filterExpr != "" {
+			var err error
+			if filter, err = rpc.NewMutationFilter(filterExpr); err != nil {
+				t.Fatalf("NewMutationFilter: %v", err)
+			}
+		}
+		// The first poll happens immediately; cancel before the second
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var got []watchEvent
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			cancel()
+		}()
+		err := watchEvents(watchCtx, s, filter, since, time.Hour, func(e watchEvent) { got = append(got, e) })
+		if err != context.Canceled {
+			t.Fatalf("watchEvents: %v", err)
+		}
+		return got
+	}
+
+	all := collect(watchCursor{kind: watchCursorEvent}, "")
+	var gotTypes []string
+	for _, e := range all {
+		gotTypes = append(gotTypes, e.Type)
+	}
+	want := []string{rpc.MutationCreate, rpc.MutationCreate, rpc.MutationDepAdd, watchEventClose, rpc.MutationDepRemove}
+	if len(gotTypes) != len(want) {
+		t.Fatalf("types = %v, want %v", gotTypes, want)
+	}
+	for i := range want {
+		if gotTypes[i] != want[i] {
+			t.Fatalf("types = %v, want %v", gotTypes, want)
+		}
+	}
+	if all[2].IssueID != blocked.ID || all[2].DependsOnID != blocker.ID {
+		t.Errorf("dep_add = %+v, want %s -> %s", all[2], blocked.ID, blocker.ID)
+	}
+	if all[3].Title != "Blocker" || all[3].Actor != "bob" {
+		t.Errorf("close = %+v", all[3])
+	}
+	if all[4].IssueID != blocked.ID || all[4].DependsOnID != blocker.ID {
+		t.Errorf("dep_remove = %+v, want %s -> %s", all[4], blocked.ID, blocker.ID)
+	}
+
+	// Resuming after the close only shows the dependency removal
+	resumed := collect(watchCursor{kind: watchCursorEvent, value: mustParseEventCursor(t, all[3].Cursor)}, "")
+	if len(resumed) != 1 || resumed[0].Type != rpc.MutationDepRemove {
+		t.Errorf("resumed = %+v, want just the dependency removal", resumed)
+	}
+
+	// The filter sees issues as they are now
+	filtered := collect(watchCursor{kind: watchCursorEvent}, "status=open")
+	for _, e := range filtered {
+		if e.IssueID != blocked.ID {
+			t.Errorf("filter let through %s %s", e.Type, e.IssueID)
+		}
+	}
+
+	// Without a cursor, only new events are shown
+	if fresh := collect(watchCursor{}, ""); len(fresh) != 0 {
+		t.Errorf("expected no events without a cursor, got %+v", fresh)
+	}
+
+	// A daemon cursor resumes from the events recorded in or after its second
+	if replayed := collect(watchCursor{kind: watchCursorMutation, value: time.Now().Add(time.Hour).UnixMilli()}, ""); len(replayed) != 0 {
+		t.Errorf("expected no events after a future cursor, got %+v", replayed)
+	}
+	if replayed := collect(watchCursor{kind: watchCursorMutation, value: all[0].Timestamp.UnixMilli()}, ""); len(replayed) != len(all) {
+		t.Errorf("expected all %d events from the first event's second, got %d", len(all), len(replayed))
+	}
+}
+
+func mustParseEventCursor(t *testing.T, s string) int64 {
+	t.Helper()
+	c, err := parseWatchCursor(s)
+	if err != nil || c.kind != watchCursorEvent {
+		t.Fatalf("bad event cursor %q: %v", s, err)
+	}
+	return c.value
+}
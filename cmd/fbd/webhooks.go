@@ -0,0 +1,340 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/fastbeads/internal/ui"
+	"github.com/steveyegge/fastbeads/internal/webhooks"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:     "webhooks",
+	GroupID: "advanced",
+	Short:   "Deliver issue events to HTTP webhooks",
+	Long: `Manage webhooks that receive issue lifecycle events.
+
+Every event recorded in the events table (created, updated, status_changed,
+closed, commented, ...) is POSTed as JSON to each configured endpoint. The
+events table is the outbox: each endpoint keeps a cursor of the last event it
+was offered, so deliveries survive restarts and nothing is missed.
+
+Delivery runs inside 'fbd serve'. Use 'fbd webhooks deliver' to flush the
+outbox once without the daemon (e.g. from cron or CI).
+
+Payload:
+  {"delivery": "<name>:<event id>", "event": {...}, "issue": {...}}
+
+Headers:
+  X-Fbd-Event           Event type
+  X-Fbd-Delivery        Delivery ID, stable across retries (use it to dedupe)
+  X-Fbd-Signature-256   sha256=<hex HMAC-SHA256 of the body>, if a secret is set
+
+Failed deliveries are retried with exponential backoff. 4xx responses other
+than 408 and 429 are not retried. Events that still fail are moved to the
+dead-letter list and can be re-sent with 'fbd webhooks replay'.
+
+Examples:
+  fbd webhooks add ci https://ci.example.com/hook --secret $SECRET
+  fbd webhooks add urgent https://chat.example.com/hook --filter "priority<=1" --events created,closed
+  fbd webhooks test ci
+  fbd webhooks list --dead-letters
+  fbd webhooks replay --endpoint ci`,
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured webhooks",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("webhooks list requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		if deadOnly, _ := cmd.Flags().GetBool("dead-letters"); deadOnly {
+			letters, err := webhooks.DeadLetters(ctx, store)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if jsonOutput {
+				if letters == nil {
+					letters = []*webhooks.DeadLetter{}
+				}
+				outputJSON(letters)
+				return
+			}
+			if len(letters) == 0 {
+				fmt.Println("No dead letters")
+				return
+			}
+			fmt.Printf("\nDead letters (%d):\n", len(letters))
+			for _, l := range letters {
+				fmt.Printf("  %d  %s  event %d (%s %s)  %d attempt(s), %s\n", l.ID, l.Endpoint, l.EventID, l.EventType, l.IssueID,
+					l.Attempts, l.FailedAt.Local().Format("2006-01-02 15:04"))
+				fmt.Printf("     %s\n", ui.RenderFail(l.Error))
+			}
+			fmt.Println("\nRe-send with 'fbd webhooks replay [id...]'.")
+			return
+		}
+
+		endpoints, err := webhooks.LoadEndpoints(ctx, store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		letters, err := webhooks.DeadLetters(ctx, store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		deadByEndpoint := make(map[string]int)
+		for _, l := range letters {
+			deadByEndpoint[l.Endpoint]++
+		}
+
+		type endpointInfo struct {
+			*webhooks.Endpoint
+			Signed      bool  `json:"signed"`
+			LastEventID int64 `json:"last_event_id"`
+			DeadLetters int   `json:"dead_letters"`
+		}
+		infos := make([]endpointInfo, 0, len(endpoints))
+		for _, ep := range endpoints {
+			cursor, err := webhooks.Cursor(ctx, store, ep.Name)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			infos = append(infos, endpointInfo{ep, ep.Secret != "", cursor, deadByEndpoint[ep.Name]})
+		}
+
+		if jsonOutput {
+			outputJSON(infos)
+			return
+		}
+		if len(infos) == 0 {
+			fmt.Println("No webhooks configured")
+			fmt.Println("Add one with 'fbd webhooks add <name> <url>'.")
+			return
+		}
+		fmt.Println("\nWebhooks:")
+		for _, info := range infos {
+			fmt.Printf("  %s  %s\n", ui.RenderBold(info.Name), info.URL)
+			if info.Signed {
+				fmt.Println("    secret:  ********")
+			}
+			if info.Filter != "" {
+				fmt.Printf("    filter:  %s\n", info.Filter)
+			}
+			if len(info.Events) > 0 {
+				fmt.Printf("    events:  %s\n", strings.Join(info.Events, ", "))
+			}
+			fmt.Printf("    cursor:  event %d\n", info.LastEventID)
+			if info.DeadLetters > 0 {
+				fmt.Printf("    %s\n", ui.RenderWarn(fmt.Sprintf("%d dead letter(s)", info.DeadLetters)))
+			}
+		}
+	},
+}
+
+var webhooksAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add or update a webhook",
+	Long: `Add a webhook, or update the one with the same name.
+
+A new webhook receives events recorded from now on; updating an existing
+webhook keeps its place in the outbox.
+
+--filter takes a query expression (see 'fbd query --help') matched against
+the event's issue at delivery time. Events for deleted issues never match a
+filter. --events limits delivery to the given event types.
+
+Examples:
+  fbd webhooks add ci https://ci.example.com/hook --secret $SECRET
+  fbd webhooks add bugs https://example.com/hook --filter "type=bug AND label=backend"
+  fbd webhooks add closes https://example.com/hook --events closed,reopened`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("webhooks add")
+		if err := ensureDirectMode("webhooks add requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		secret, _ := cmd.Flags().GetString("secret")
+		filter, _ := cmd.Flags().GetString("filter")
+		events, _ := cmd.Flags().GetStringSlice("events")
+		ep := &webhooks.Endpoint{Name: args[0], URL: args[1], Secret: secret, Filter: filter, Events: events}
+		if err := webhooks.SaveEndpoint(rootCtx, store, ep); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(ep)
+			return
+		}
+		fmt.Printf("%s Saved webhook %s → %s\n", ui.RenderPass("✓"), ep.Name, ep.URL)
+		fmt.Printf("  Check it with 'fbd webhooks test %s'.\n", ep.Name)
+	},
+}
+
+var webhooksRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("webhooks remove")
+		if err := ensureDirectMode("webhooks remove requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if err := webhooks.RemoveEndpoint(rootCtx, store, args[0]); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"name": args[0], "removed": true})
+			return
+		}
+		fmt.Printf("%s Removed webhook %s\n", ui.RenderPass("✓"), args[0])
+	},
+}
+
+var webhooksTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Send a test event to a webhook",
+	Long: `Send a single "webhook.test" event to a webhook, without retries,
+and report whether it was accepted. The outbox is not affected.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("webhooks test requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ep, err := webhooks.GetEndpoint(rootCtx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		sendErr := webhooks.NewDispatcher(store).Test(rootCtx, ep)
+		if jsonOutput {
+			result := map[string]interface{}{"name": ep.Name, "url": ep.URL, "ok": sendErr == nil}
+			if sendErr != nil {
+				result["error"] = sendErr.Error()
+			}
+			outputJSON(result)
+			if sendErr != nil {
+				os.Exit(1)
+			}
+			return
+		}
+		if sendErr != nil {
+			FatalError("test delivery to %s failed: %v", ep.Name, sendErr)
+		}
+		fmt.Printf("%s %s accepted the test event\n", ui.RenderPass("✓"), ep.Name)
+	},
+}
+
+var webhooksReplayCmd = &cobra.Command{
+	Use:   "replay [id...]",
+	Short: "Re-send dead-lettered events",
+	Long: `Re-send dead letters with their original payloads and delivery IDs.
+
+With no IDs, every dead letter is replayed (only those for --endpoint, if
+set). Delivered letters are removed from the list; failures stay, with their
+error updated.
+
+Examples:
+  fbd webhooks replay              # Replay everything
+  fbd webhooks replay 3 4          # Replay specific dead letters
+  fbd webhooks replay --endpoint ci`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("webhooks replay")
+		if err := ensureDirectMode("webhooks replay requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		ids := make([]int64, 0, len(args))
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				FatalErrorRespectJSON("invalid dead letter ID %q", arg)
+			}
+			ids = append(ids, id)
+		}
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+
+		results, err := webhooks.NewDispatcher(store).Replay(rootCtx, ids, endpoint)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.Error != "" {
+				failed++
+			}
+		}
+		if jsonOutput {
+			if results == nil {
+				results = []webhooks.ReplayResult{}
+			}
+			outputJSON(results)
+		} else if len(results) == 0 {
+			fmt.Println("No dead letters to replay")
+		} else {
+			for _, r := range results {
+				if r.Error != "" {
+					fmt.Printf("%s %d  %s  event %d: %s\n", ui.RenderFail("✗"), r.ID, r.Endpoint, r.EventID, r.Error)
+				} else {
+					fmt.Printf("%s %d  %s  event %d\n", ui.RenderPass("✓"), r.ID, r.Endpoint, r.EventID)
+				}
+			}
+			fmt.Printf("\nReplayed %d, failed %d\n", len(results)-failed, failed)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var webhooksDeliverCmd = &cobra.Command{
+	Use:   "deliver",
+	Short: "Deliver pending events once",
+	Long: `Deliver every pending event to the configured webhooks and exit.
+
+'fbd serve' does this continuously; use this command when no daemon is
+running, e.g. from cron or at the end of a CI job.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("webhooks deliver")
+		if err := ensureDirectMode("webhooks deliver requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		report, err := webhooks.NewDispatcher(store).DeliverPending(rootCtx)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		fmt.Printf("%s Delivered %d, skipped %d, dead-lettered %d\n", ui.RenderPass("✓"),
+			report.Delivered, report.Skipped, report.DeadLettered)
+		if report.DeadLettered > 0 {
+			fmt.Println("  See 'fbd webhooks list --dead-letters'.")
+		}
+	},
+}
+
+func init() {
+	webhooksListCmd.Flags().Bool("dead-letters", false, "List dead-lettered events instead of webhooks")
+	webhooksAddCmd.Flags().String("secret", "", "HMAC-SHA256 signing secret")
+	webhooksAddCmd.Flags().String("filter", "", "Query expression the event's issue must match")
+	webhooksAddCmd.Flags().StringSlice("events", nil, "Event types to deliver (default: all)")
+	webhooksReplayCmd.Flags().String("endpoint", "", "Only replay dead letters for this webhook")
+
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksAddCmd)
+	webhooksCmd.AddCommand(webhooksRemoveCmd)
+	webhooksCmd.AddCommand(webhooksTestCmd)
+	webhooksCmd.AddCommand(webhooksReplayCmd)
+	webhooksCmd.AddCommand(webhooksDeliverCmd)
+	rootCmd.AddCommand(webhooksCmd)
+}
@@ -1,39 +1,39 @@
 let allIssues = [];
-let ws = null;
-let wsConnected = false;
+let events = null;
+let eventsConnected = false;
 
-// WebSocket connection
-function connectWebSocket() {
-    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const wsUrl = protocol + '//' + window.location.host + '/ws';
+// Mutation event stream (Server-Sent Events from the fbd gateway).
+// EventSource reconnects on its own and resumes from the last event id.
+function connectEvents() {
+    events = new EventSource('/v1/events');
 
-    ws = new WebSocket(wsUrl);
-
-    ws.onopen = function() {
-        console.log('WebSocket connected');
-        wsConnected = true;
+    events.onopen = function() {
+        console.log('Event stream connected');
+        eventsConnected = true;
         updateConnectionStatus(true);
     };
 
-    ws.onmessage = function(event) {
-        console.log('WebSocket message:', event.data);
+    events.addEventListener('mutation', function(event) {
+        console.log('Mutation event:', event.data);
         const mutation = JSON.parse(event.data);
         handleMutation(mutation);
-    };
+    });
 
-    ws.onerror = function(error) {
-        console.error('WebSocket error:', error);
-        wsConnected = false;
+    events.onerror = function(error) {
+        console.error('Event stream error:', error);
+        eventsConnected = false;
         updateConnectionStatus(false);
     };
+}
 
-    ws.onclose = function() {
-        console.log('WebSocket disconnected');
-        wsConnected = false;
-        updateConnectionStatus(false);
-        // Reconnect after 5 seconds
-        setTimeout(connectWebSocket, 5000);
-    };
+// Call a gateway endpoint and unwrap the RPC response envelope
+async function fetchGateway(path) {
+    const response = await fetch(path);
+    const envelope = await response.json();
+    if (!response.ok || !envelope.success) {
+        throw new Error(envelope.error || response.statusText);
+    }
+    return envelope.data;
 }
 
 // Update connection status indicator
@@ -75,7 +75,7 @@ function showError(message) {
 
 // Handle mutation event
 function handleMutation(mutation) {
-    console.log('Mutation:', mutation.type, mutation.issue_id);
+    console.log('Mutation:', mutation.Type, mutation.IssueID);
     // Refresh data on mutation
     loadStats();
     loadIssues();
@@ -84,9 +84,7 @@ function handleMutation(mutation) {
 // Load statistics
 async function loadStats() {
     try {
-        const response = await fetch('/api/stats');
-        if (!response.ok) throw new Error('Failed to load statistics');
-        const stats = await response.json();
+        const stats = await fetchGateway('/v1/stats');
         document.getElementById('stat-total').textContent = stats.total_issues || 0;
         document.getElementById('stat-in-progress').textContent = stats.in_progress_issues || 0;
         document.getElementById('stat-open').textContent = stats.open_issues || 0;
@@ -100,9 +98,7 @@ async function loadStats() {
 // Load all issues
 async function loadIssues() {
     try {
-        const response = await fetch('/api/issues');
-        if (!response.ok) throw new Error('Failed to load issues');
-        allIssues = await response.json();
+        allIssues = await fetchGateway('/v1/issues') || [];
         filterIssues();
     } catch (error) {
         console.error('Error loading issues:', error);
@@ -204,9 +200,7 @@ async function showIssueDetail(issueId) {
     modalBody.innerHTML = '<div class="spinner"></div>';
 
     try {
-        const response = await fetch('/api/issues/' + issueId);
-        if (!response.ok) throw new Error('Issue not found');
-        const issue = await response.json();
+        const issue = await fetchGateway('/v1/issues/' + encodeURIComponent(issueId));
 
         modalTitle.textContent = issue.id + ': ' + issue.title;
         let html = '<p><strong>Status:</strong> ' + issue.status + '</p>';
@@ -329,13 +323,13 @@ document.getElementById('stat-item-closed').addEventListener('click', () => setS
 document.getElementById('reload-button').addEventListener('click', reloadData);
 
 // Initial load
-connectWebSocket();
+connectEvents();
 loadStats();
 loadIssues();
 
-// Fallback: Refresh every 30 seconds (WebSocket should handle real-time updates)
+// Fallback: Refresh every 30 seconds (the event stream should handle real-time updates)
 setInterval(() => {
-    if (!wsConnected) {
+    if (!eventsConnected) {
         loadStats();
         loadIssues();
     }
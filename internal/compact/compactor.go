@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/steveyegge/fastbeads/internal/types"
@@ -20,12 +21,18 @@ type Config struct {
 	DryRun       bool
 	AuditEnabled bool
 	Actor        string
+
+	// Provider, Model and BaseURL select the summarizer. Empty values come
+	// from the ai.provider, ai.model and ai.base-url config keys.
+	Provider string
+	Model    string
+	BaseURL  string
 }
 
 // Compactor handles issue compaction using AI summarization.
 type Compactor struct {
 	store      compactableStore
-	summarizer summarizer
+	summarizer Summarizer
 	config     *Config
 }
 
@@ -39,10 +46,8 @@ type compactableStore interface {
 	ApplyCompaction(ctx context.Context, issueID string, tier int, originalSize int, compactedSize int, commitHash string) error
 	AddComment(ctx context.Context, issueID, actor, comment string) error
 	MarkIssueDirty(ctx context.Context, issueID string) error
-}
-
-type summarizer interface {
-	SummarizeTier1(ctx context.Context, issue *types.Issue) (string, error)
+	GetEpicDescendants(ctx context.Context, epicID string) ([]*types.Issue, error)
+	ApplyEpicRollup(ctx context.Context, epicID string, descendantIDs []string, summary string, commitHash string, actor string) error
 }
 
 // New creates a new Compactor instance with the given configuration.
@@ -60,26 +65,38 @@ func New(store compactableStore, apiKey string, config *Config) (*Compactor, err
 		config.APIKey = apiKey
 	}
 
-	var haiClient summarizer
+	if config.Provider == "" {
+		config.Provider = ConfiguredProvider()
+	}
+	if config.Model == "" {
+		config.Model = configuredModel()
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = configuredBaseURL()
+	}
+
+	var sum Summarizer
 	var err error
 	if !config.DryRun {
-		haiClient, err = newHaikuClient(config.APIKey)
+		sum, err = NewSummarizer(config.Provider, ProviderConfig{
+			APIKey:       config.APIKey,
+			Model:        config.Model,
+			BaseURL:      config.BaseURL,
+			AuditEnabled: config.AuditEnabled,
+			AuditActor:   config.Actor,
+		})
 		if err != nil {
 			if errors.Is(err, errAPIKeyRequired) {
 				config.DryRun = true
 			} else {
-				return nil, fmt.Errorf("failed to create Haiku client: %w", err)
+				return nil, fmt.Errorf("failed to create %s summarizer: %w", config.Provider, err)
 			}
 		}
 	}
-	if hc, ok := haiClient.(*haikuClient); ok && hc != nil {
-		hc.auditEnabled = config.AuditEnabled
-		hc.auditActor = config.Actor
-	}
 
 	return &Compactor{
 		store:      store,
-		summarizer: haiClient,
+		summarizer: sum,
 		config:     config,
 	}, nil
 }
@@ -244,14 +261,70 @@ func (c *Compactor) CompactTier1Single(ctx context.Context, issueID string) (*Ba
 	}, nil
 }
 
-// CompactTier2 is a placeholder for future Tier 2 compaction.
-// Tier 2 would involve more aggressive summarization and archival.
+// EpicRollup describes a Tier 2 rollup: a closed epic and the descendants
+// that would be collapsed into its summary.
+type EpicRollup struct {
+	Epic         *types.Issue
+	Descendants  []*types.Issue
+	OriginalSize int // Text size of the epic and all descendants
+}
+
+// PlanEpicRollup checks that an epic is eligible for a Tier 2 rollup and
+// returns what the rollup would cover, without changing anything.
+func (c *Compactor) PlanEpicRollup(ctx context.Context, epicID string) (*EpicRollup, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	epic, err := c.store.GetIssue(ctx, epicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+	if epic == nil {
+		return nil, fmt.Errorf("issue %s not found", epicID)
+	}
+	if epic.IssueType != types.TypeEpic {
+		return nil, fmt.Errorf("%s is a %s, not an epic", epicID, epic.IssueType)
+	}
+
+	eligible, reason, err := c.store.CheckEligibility(ctx, epicID, 2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify eligibility: %w", err)
+	}
+	if !eligible {
+		if reason != "" {
+			return nil, fmt.Errorf("epic %s is not eligible for Tier 2 rollup: %s", epicID, reason)
+		}
+		return nil, fmt.Errorf("epic %s is not eligible for Tier 2 rollup", epicID)
+	}
+
+	descendants, err := c.store.GetEpicDescendants(ctx, epicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch descendants: %w", err)
+	}
+
+	rollup := &EpicRollup{Epic: epic, Descendants: descendants, OriginalSize: textSize(epic)}
+	for _, d := range descendants {
+		rollup.OriginalSize += textSize(d)
+	}
+	return rollup, nil
+}
+
+// CompactTier2 compacts an issue at Tier 2. A closed epic is rolled up: it
+// and all of its descendants are summarized into the epic's description, and
+// the descendants become tombstones that keep their IDs. The git commit is
+// recorded on every issue, so `fbd restore` can still recover each one.
+//
+// For other issues Tier 2 only records compaction metadata.
 func (c *Compactor) CompactTier2(ctx context.Context, issueID string) error {
 	// Get the issue
 	issue, err := c.store.GetIssue(ctx, issueID)
 	if err != nil {
 		return fmt.Errorf("failed to get issue: %w", err)
 	}
+	if issue != nil && issue.IssueType == types.TypeEpic {
+		return c.rollupEpic(ctx, issueID)
+	}
 
 	// Calculate original size
 	originalSize := len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
@@ -268,3 +341,54 @@ func (c *Compactor) CompactTier2(ctx context.Context, issueID string) error {
 
 	return nil
 }
+
+func (c *Compactor) rollupEpic(ctx context.Context, epicID string) error {
+	rollup, err := c.PlanEpicRollup(ctx, epicID)
+	if err != nil {
+		return err
+	}
+
+	if c.config.DryRun {
+		return fmt.Errorf("dry-run: would roll up %s and %d descendants (original size: %d bytes)",
+			epicID, len(rollup.Descendants), rollup.OriginalSize)
+	}
+
+	summary, err := c.summarizer.SummarizeEpic(ctx, rollup.Epic, rollup.Descendants)
+	if err != nil {
+		return fmt.Errorf("failed to summarize: %w", err)
+	}
+
+	// List every rolled-up ID so the tombstones can be found and restored
+	ids := make([]string, len(rollup.Descendants))
+	for i, d := range rollup.Descendants {
+		ids[i] = d.ID
+	}
+	summary = strings.TrimSpace(summary) + "\n\n**Rolled up:** " + strings.Join(ids, ", ")
+
+	compactedSize := len(summary)
+	if compactedSize >= rollup.OriginalSize {
+		warningMsg := fmt.Sprintf("Tier 2 rollup skipped: summary (%d bytes) not shorter than original (%d bytes)", compactedSize, rollup.OriginalSize)
+		if err := c.store.AddComment(ctx, epicID, "compactor", warningMsg); err != nil {
+			return fmt.Errorf("failed to record warning: %w", err)
+		}
+		return fmt.Errorf("rollup would increase size (%d → %d bytes), keeping original", rollup.OriginalSize, compactedSize)
+	}
+
+	commitHash := GetCurrentCommitHash()
+	if err := c.store.ApplyEpicRollup(ctx, epicID, ids, summary, commitHash, "compactor"); err != nil {
+		return fmt.Errorf("failed to apply rollup: %w", err)
+	}
+
+	comment := fmt.Sprintf("Tier 2 rollup: %d issues, %d → %d bytes (saved %d)",
+		len(ids)+1, rollup.OriginalSize, compactedSize, rollup.OriginalSize-compactedSize)
+	if err := c.store.AddComment(ctx, epicID, "compactor", comment); err != nil {
+		return fmt.Errorf("failed to add compaction comment: %w", err)
+	}
+
+	return nil
+}
+
+// textSize is the size of the issue text that compaction replaces.
+func textSize(issue *types.Issue) int {
+	return len(issue.Description) + len(issue.Design) + len(issue.Notes) + len(issue.AcceptanceCriteria)
+}
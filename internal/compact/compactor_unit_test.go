@@ -18,6 +18,8 @@ type stubStore struct {
 	applyCompactionFn  func(context.Context, string, int, int, int, string) error
 	addCommentFn       func(context.Context, string, string, string) error
 	markDirtyFn        func(context.Context, string) error
+	descendantsFn      func(context.Context, string) ([]*types.Issue, error)
+	applyRollupFn      func(context.Context, string, []string, string, string, string) error
 }
 
 func (s *stubStore) CheckEligibility(ctx context.Context, issueID string, tier int) (bool, string, error) {
@@ -62,6 +64,20 @@ func (s *stubStore) MarkIssueDirty(ctx context.Context, issueID string) error {
 	return nil
 }
 
+func (s *stubStore) GetEpicDescendants(ctx context.Context, epicID string) ([]*types.Issue, error) {
+	if s.descendantsFn != nil {
+		return s.descendantsFn(ctx, epicID)
+	}
+	return nil, nil
+}
+
+func (s *stubStore) ApplyEpicRollup(ctx context.Context, epicID string, descendantIDs []string, summary string, commitHash string, actor string) error {
+	if s.applyRollupFn != nil {
+		return s.applyRollupFn(ctx, epicID, descendantIDs, summary, commitHash, actor)
+	}
+	return nil
+}
+
 type stubSummarizer struct {
 	summary string
 	err     error
@@ -76,6 +92,10 @@ func (s *stubSummarizer) SummarizeTier1(ctx context.Context, issue *types.Issue)
 	return s.summary, s.err
 }
 
+func (s *stubSummarizer) SummarizeEpic(ctx context.Context, epic *types.Issue, descendants []*types.Issue) (string, error) {
+	return s.SummarizeTier1(ctx, epic)
+}
+
 func (s *stubSummarizer) getCalls() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -755,3 +775,95 @@ func TestCompactTier1Batch_MixedResults(t *testing.T) {
 		t.Fatalf("summarizer should run once; got %d", summary.calls)
 	}
 }
+
+// --- Tier 2 epic rollup tests ---
+
+func stubEpicStore(eligible bool, reason string) *stubStore {
+	epic := &types.Issue{ID: "bd-1", Title: "Auth overhaul", IssueType: types.TypeEpic,
+		Description: strings.Repeat("E", 100), Status: types.StatusClosed}
+	children := []*types.Issue{
+		{ID: "bd-1.1", Title: "Add OAuth", Description: strings.Repeat("A", 100), Status: types.StatusClosed},
+		{ID: "bd-1.2", Title: "Drop sessions", Description: strings.Repeat("B", 100), Status: types.StatusClosed},
+	}
+	return &stubStore{
+		checkEligibilityFn: func(_ context.Context, _ string, tier int) (bool, string, error) {
+			if tier != 2 {
+				return false, "wrong tier", nil
+			}
+			return eligible, reason, nil
+		},
+		getIssueFn:    func(context.Context, string) (*types.Issue, error) { return epic, nil },
+		descendantsFn: func(context.Context, string) ([]*types.Issue, error) { return children, nil },
+	}
+}
+
+func TestPlanEpicRollup(t *testing.T) {
+	c := &Compactor{store: stubEpicStore(true, ""), config: &Config{}}
+
+	rollup, err := c.PlanEpicRollup(context.Background(), "bd-1")
+	if err != nil {
+		t.Fatalf("PlanEpicRollup: %v", err)
+	}
+	if len(rollup.Descendants) != 2 || rollup.OriginalSize != 300 {
+		t.Errorf("unexpected plan: %d descendants, %d bytes", len(rollup.Descendants), rollup.OriginalSize)
+	}
+
+	c = &Compactor{store: stubEpicStore(false, "descendant bd-1.2 is open"), config: &Config{}}
+	if _, err := c.PlanEpicRollup(context.Background(), "bd-1"); err == nil || !strings.Contains(err.Error(), "bd-1.2 is open") {
+		t.Errorf("expected ineligible error, got %v", err)
+	}
+}
+
+func TestPlanEpicRollup_NotEpic(t *testing.T) {
+	store := &stubStore{getIssueFn: func(context.Context, string) (*types.Issue, error) { return stubIssue(), nil }}
+	c := &Compactor{store: store, config: &Config{}}
+
+	if _, err := c.PlanEpicRollup(context.Background(), "bd-123"); err == nil || !strings.Contains(err.Error(), "not an epic") {
+		t.Errorf("expected not-an-epic error, got %v", err)
+	}
+}
+
+func TestCompactTier2_RollsUpEpic(t *testing.T) {
+	t.Cleanup(withGitHash(t, "cafe\n"))
+
+	store := stubEpicStore(true, "")
+	var gotIDs []string
+	var gotSummary, gotHash string
+	store.applyRollupFn = func(_ context.Context, epicID string, ids []string, summary, hash, _ string) error {
+		gotIDs, gotSummary, gotHash = ids, summary, hash
+		return nil
+	}
+	var comment string
+	store.addCommentFn = func(_ context.Context, _, _, c string) error {
+		comment = c
+		return nil
+	}
+	c := &Compactor{store: store, summarizer: &stubSummarizer{summary: "**Summary:** Done."}, config: &Config{}}
+
+	if err := c.CompactTier2(context.Background(), "bd-1"); err != nil {
+		t.Fatalf("CompactTier2: %v", err)
+	}
+	if strings.Join(gotIDs, ",") != "bd-1.1,bd-1.2" || gotHash != "cafe" {
+		t.Errorf("unexpected rollup: ids=%v hash=%q", gotIDs, gotHash)
+	}
+	if !strings.HasSuffix(gotSummary, "**Rolled up:** bd-1.1, bd-1.2") {
+		t.Errorf("summary should list rolled-up IDs: %q", gotSummary)
+	}
+	if !strings.Contains(comment, "Tier 2 rollup: 3 issues") {
+		t.Errorf("unexpected comment %q", comment)
+	}
+}
+
+func TestCompactTier2_DryRun(t *testing.T) {
+	store := stubEpicStore(true, "")
+	store.applyRollupFn = func(context.Context, string, []string, string, string, string) error {
+		t.Fatal("dry run must not apply the rollup")
+		return nil
+	}
+	c := &Compactor{store: store, config: &Config{DryRun: true}}
+
+	err := c.CompactTier2(context.Background(), "bd-1")
+	if err == nil || !strings.Contains(err.Error(), "dry-run: would roll up bd-1 and 2 descendants") {
+		t.Fatalf("expected dry-run error, got %v", err)
+	}
+}
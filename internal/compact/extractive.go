@@ -0,0 +1,191 @@
+package compact
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+const (
+	extractiveSummarySentences = 2   // Sentences kept from the description
+	extractiveMaxDecisions     = 3   // Bullets kept from the design
+	extractiveMaxSentenceRunes = 200 // Longer sentences are truncated
+)
+
+func init() {
+	RegisterProvider(ProviderExtractive, func(ProviderConfig) (Summarizer, error) {
+		return extractiveSummarizer{}, nil
+	})
+}
+
+// extractiveSummarizer builds the summary format from the issue's own
+// sentences: the opening of the description, the first point of each design
+// paragraph, and the close reason. It needs no network or model, and the
+// same issue always yields the same summary.
+type extractiveSummarizer struct{}
+
+// SummarizeTier1 creates a structured summary of an issue (Summary, Key Decisions, Resolution).
+func (extractiveSummarizer) SummarizeTier1(ctx context.Context, issue *types.Issue) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	summary, decisions, resolution := extractSections(issue)
+	return formatSections(summary, decisions, nil, resolution), nil
+}
+
+// SummarizeEpic creates a rollup summary of an epic: the epic's own sections,
+// plus a Delivered list with each descendant's title and close reason.
+func (extractiveSummarizer) SummarizeEpic(ctx context.Context, epic *types.Issue, descendants []*types.Issue) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	summary, decisions, resolution := extractSections(epic)
+
+	delivered := make([]string, 0, len(descendants))
+	for _, d := range descendants {
+		item := d.ID + ": " + truncateRunes(d.Title, extractiveMaxSentenceRunes)
+		if s := sentences(d.CloseReason); len(s) > 0 {
+			item += " (" + strings.TrimSuffix(s[0], ".") + ")"
+		}
+		delivered = append(delivered, item)
+	}
+	return formatSections(summary, decisions, delivered, resolution), nil
+}
+
+// extractSections picks the summary sentences, key decisions and resolution
+// out of an issue's description, design and close reason.
+func extractSections(issue *types.Issue) (summary, decisions []string, resolution string) {
+	for _, para := range paragraphs(issue.Description) {
+		for _, sentence := range sentences(para) {
+			if len(summary) == extractiveSummarySentences {
+				break
+			}
+			summary = append(summary, sentence)
+		}
+	}
+	if len(summary) == 0 {
+		summary = []string{truncateRunes(issue.Title, extractiveMaxSentenceRunes)}
+	}
+
+	for _, para := range paragraphs(issue.Design) {
+		if len(decisions) == extractiveMaxDecisions {
+			break
+		}
+		if s := sentences(para); len(s) > 0 {
+			decisions = append(decisions, s[0])
+		}
+	}
+
+	resolution = "Closed."
+	if s := sentences(issue.CloseReason); len(s) > 0 {
+		resolution = s[0]
+	}
+	return summary, decisions, resolution
+}
+
+// formatSections renders the summary format shared with the LLM prompts.
+// Empty bullet sections are left out.
+func formatSections(summary, decisions, delivered []string, resolution string) string {
+	var sb strings.Builder
+	sb.WriteString("**Summary:** ")
+	sb.WriteString(strings.Join(summary, " "))
+	sb.WriteString("\n\n")
+	writeBullets(&sb, "Key Decisions", decisions)
+	writeBullets(&sb, "Delivered", delivered)
+	sb.WriteString("**Resolution:** ")
+	sb.WriteString(resolution)
+	return sb.String()
+}
+
+func writeBullets(sb *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString("**" + heading + ":**\n")
+	for _, item := range items {
+		sb.WriteString("- " + item + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// paragraphs splits markdown text into paragraphs and list items, with
+// headings, list markers and line wrapping removed.
+func paragraphs(text string) []string {
+	var out []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			out = append(out, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", strings.HasPrefix(line, "```"):
+			flush()
+		case strings.HasPrefix(line, "#"):
+			flush() // Headings label sections; they aren't content
+		case isListItem(line):
+			flush()
+			current = append(current, stripListMarker(line))
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return out
+}
+
+func isListItem(line string) bool {
+	return stripListMarker(line) != line
+}
+
+func stripListMarker(line string) string {
+	for _, marker := range []string{"- [ ] ", "- [x] ", "- ", "* ", "+ "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):])
+		}
+	}
+	// Numbered items: "1. ", "12) "
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	if i > 0 && i+1 < len(line) && (line[i] == '.' || line[i] == ')') && line[i+1] == ' ' {
+		return strings.TrimSpace(line[i+2:])
+	}
+	return line
+}
+
+// sentences splits a paragraph at sentence-ending punctuation followed by a
+// space. Each sentence is truncated to extractiveMaxSentenceRunes.
+func sentences(para string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(para); i++ {
+		switch para[i] {
+		case '.', '!', '?':
+			if i+1 == len(para) || para[i+1] == ' ' {
+				if s := strings.TrimSpace(para[start : i+1]); s != "" {
+					out = append(out, truncateRunes(s, extractiveMaxSentenceRunes))
+				}
+				start = i + 1
+			}
+		}
+	}
+	if s := strings.TrimSpace(para[start:]); s != "" {
+		out = append(out, truncateRunes(s, extractiveMaxSentenceRunes))
+	}
+	return out
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
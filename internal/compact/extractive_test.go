@@ -0,0 +1,96 @@
+package compact
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestExtractiveSummarizer(t *testing.T) {
+	issue := &types.Issue{
+		ID:    "bd-1",
+		Title: "Fix authentication bug",
+		Description: `## Problem
+Users can't log in with OAuth when the provider returns a
+redirect. The callback drops the state parameter. This affects every
+SSO customer.`,
+		Design: `- Preserve state through the redirect. Store it in the session.
+- Add a regression test for the callback.
+1. Log provider errors verbatim.
+- A fourth point that is dropped.`,
+		CloseReason: "Fixed in v2.3. Deployed to all regions.",
+	}
+
+	got, err := extractiveSummarizer{}.SummarizeTier1(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("SummarizeTier1: %v", err)
+	}
+	want := `**Summary:** Users can't log in with OAuth when the provider returns a redirect. The callback drops the state parameter.
+
+**Key Decisions:**
+- Preserve state through the redirect.
+- Add a regression test for the callback.
+- Log provider errors verbatim.
+
+**Resolution:** Fixed in v2.3.`
+	if got != want {
+		t.Errorf("summary mismatch\ngot:\n%s\n\nwant:\n%s", got, want)
+	}
+
+	again, _ := extractiveSummarizer{}.SummarizeTier1(context.Background(), issue)
+	if again != got {
+		t.Error("extractive summary is not deterministic")
+	}
+}
+
+func TestExtractiveSummarizer_SparseIssue(t *testing.T) {
+	got, err := extractiveSummarizer{}.SummarizeTier1(context.Background(), &types.Issue{Title: "Bump deps"})
+	if err != nil {
+		t.Fatalf("SummarizeTier1: %v", err)
+	}
+	if got != "**Summary:** Bump deps\n\n**Resolution:** Closed." {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
+
+func TestExtractiveSummarizer_TruncatesLongSentences(t *testing.T) {
+	issue := &types.Issue{Description: strings.Repeat("é", 500)}
+	got, _ := extractiveSummarizer{}.SummarizeTier1(context.Background(), issue)
+	summary := strings.TrimPrefix(strings.SplitN(got, "\n", 2)[0], "**Summary:** ")
+	if n := len([]rune(summary)); n != extractiveMaxSentenceRunes {
+		t.Errorf("summary is %d runes, want %d", n, extractiveMaxSentenceRunes)
+	}
+	if !strings.HasSuffix(summary, "…") {
+		t.Errorf("truncated summary should end with an ellipsis: %q", summary)
+	}
+}
+
+func TestExtractiveSummarizer_Epic(t *testing.T) {
+	epic := &types.Issue{
+		ID:          "bd-1",
+		Title:       "Auth overhaul",
+		Description: "Replace session cookies with OAuth.",
+		CloseReason: "Shipped in v3.",
+	}
+	children := []*types.Issue{
+		{ID: "bd-1.1", Title: "Add OAuth provider", CloseReason: "Merged. Follow-ups filed."},
+		{ID: "bd-1.2", Title: "Remove sessions"},
+	}
+
+	got, err := extractiveSummarizer{}.SummarizeEpic(context.Background(), epic, children)
+	if err != nil {
+		t.Fatalf("SummarizeEpic: %v", err)
+	}
+	want := `**Summary:** Replace session cookies with OAuth.
+
+**Delivered:**
+- bd-1.1: Add OAuth provider (Merged)
+- bd-1.2: Remove sessions
+
+**Resolution:** Shipped in v3.`
+	if got != want {
+		t.Errorf("summary mismatch\ngot:\n%s\n\nwant:\n%s", got, want)
+	}
+}
@@ -1,19 +1,18 @@
-// Package compact provides AI-powered issue compaction using Claude Haiku.
+// Package compact provides AI-powered issue compaction with pluggable
+// summarizer providers (Anthropic, OpenAI-compatible endpoints, or an
+// offline extractive summarizer).
 package compact
 
 import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"net"
 	"os"
-	"text/template"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
-	"github.com/steveyegge/fastbeads/internal/audit"
 	"github.com/steveyegge/fastbeads/internal/config"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
@@ -26,11 +25,26 @@ const (
 // errAPIKeyRequired is returned when an API key is needed but not provided.
 var errAPIKeyRequired = errors.New("API key required")
 
+func init() {
+	RegisterProvider(ProviderAnthropic, func(cfg ProviderConfig) (Summarizer, error) {
+		h, err := newHaikuClient(cfg.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Model != "" {
+			h.model = anthropic.Model(cfg.Model)
+		}
+		h.auditEnabled = cfg.AuditEnabled
+		h.auditActor = cfg.AuditActor
+		return h, nil
+	})
+}
+
 // haikuClient wraps the Anthropic API for issue summarization.
 type haikuClient struct {
+	prompter
 	client         anthropic.Client
 	model          anthropic.Model
-	tier1Template  *template.Template
 	maxRetries     int
 	initialBackoff time.Duration
 	auditEnabled   bool
@@ -49,15 +63,15 @@ func newHaikuClient(apiKey string) (*haikuClient, error) {
 
 	client := anthropic.NewClient(option.WithAPIKey(apiKey))
 
-	tier1Tmpl, err := template.New("tier1").Parse(tier1PromptTemplate)
+	p, err := newPrompter()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse tier1 template: %w", err)
+		return nil, err
 	}
 
 	return &haikuClient{
+		prompter:       p,
 		client:         client,
 		model:          anthropic.Model(config.DefaultAIModel()),
-		tier1Template:  tier1Tmpl,
 		maxRetries:     maxRetries,
 		initialBackoff: initialBackoff,
 	}, nil
@@ -72,25 +86,26 @@ func (h *haikuClient) SummarizeTier1(ctx context.Context, issue *types.Issue) (s
 
 	resp, callErr := h.callWithRetry(ctx, prompt)
 	if h.auditEnabled {
-		// Best-effort: never fail compaction because audit logging failed.
-		e := &audit.Entry{
-			Kind:     "llm_call",
-			Actor:    h.auditActor,
-			IssueID:  issue.ID,
-			Model:    string(h.model),
-			Prompt:   prompt,
-			Response: resp,
-		}
-		if callErr != nil {
-			e.Error = callErr.Error()
-		}
-		_, _ = audit.Append(e)
+		auditLLMCall(h.auditActor, issue.ID, string(h.model), prompt, resp, callErr)
+	}
+	return resp, callErr
+}
+
+// SummarizeEpic creates a single rollup summary of a closed epic and its descendants.
+func (h *haikuClient) SummarizeEpic(ctx context.Context, epic *types.Issue, descendants []*types.Issue) (string, error) {
+	prompt, err := h.renderRollupPrompt(epic, descendants)
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	resp, callErr := h.callWithRetry(ctx, prompt)
+	if h.auditEnabled {
+		auditLLMCall(h.auditActor, epic.ID, string(h.model), prompt, resp, callErr)
 	}
 	return resp, callErr
 }
 
 func (h *haikuClient) callWithRetry(ctx context.Context, prompt string) (string, error) {
-	var lastErr error
 	params := anthropic.MessageNewParams{
 		Model:     h.model,
 		MaxTokens: 1024,
@@ -99,41 +114,20 @@ func (h *haikuClient) callWithRetry(ctx context.Context, prompt string) (string,
 		},
 	}
 
-	for attempt := 0; attempt <= h.maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := h.initialBackoff * time.Duration(math.Pow(2, float64(attempt-1)))
-			select {
-			case <-time.After(backoff):
-			case <-ctx.Done():
-				return "", ctx.Err()
-			}
-		}
-
+	return callWithBackoff(ctx, h.maxRetries, h.initialBackoff, isRetryable, func() (string, error) {
 		message, err := h.client.Messages.New(ctx, params)
-
-		if err == nil {
-			if len(message.Content) > 0 {
-				content := message.Content[0]
-				if content.Type == "text" {
-					return content.Text, nil
-				}
-				return "", fmt.Errorf("unexpected response format: not a text block (type=%s)", content.Type)
-			}
-			return "", fmt.Errorf("unexpected response format: no content blocks")
-		}
-
-		lastErr = err
-
-		if ctx.Err() != nil {
-			return "", ctx.Err()
+		if err != nil {
+			return "", err
 		}
-
-		if !isRetryable(err) {
-			return "", fmt.Errorf("non-retryable error: %w", err)
+		if len(message.Content) > 0 {
+			content := message.Content[0]
+			if content.Type == "text" {
+				return content.Text, nil
+			}
+			return "", fmt.Errorf("unexpected response format: not a text block (type=%s)", content.Type)
 		}
-	}
-
-	return "", fmt.Errorf("failed after %d retries: %w", h.maxRetries+1, lastErr)
+		return "", fmt.Errorf("unexpected response format: no content blocks")
+	})
 }
 
 func isRetryable(err error) bool {
@@ -162,32 +156,6 @@ func isRetryable(err error) bool {
 	return false
 }
 
-type tier1Data struct {
-	Title              string
-	Description        string
-	Design             string
-	AcceptanceCriteria string
-	Notes              string
-}
-
-func (h *haikuClient) renderTier1Prompt(issue *types.Issue) (string, error) {
-	var buf []byte
-	w := &bytesWriter{buf: buf}
-
-	data := tier1Data{
-		Title:              issue.Title,
-		Description:        issue.Description,
-		Design:             issue.Design,
-		AcceptanceCriteria: issue.AcceptanceCriteria,
-		Notes:              issue.Notes,
-	}
-
-	if err := h.tier1Template.Execute(w, data); err != nil {
-		return "", err
-	}
-	return string(w.buf), nil
-}
-
 type bytesWriter struct {
 	buf []byte
 }
@@ -225,3 +193,43 @@ Provide a summary in this exact format:
 **Key Decisions:** [Brief bullet points of only the most important technical choices]
 
 **Resolution:** [One sentence on final outcome and lasting impact]`
+
+const rollupPromptTemplate = `You are summarizing a closed epic and all of the work beneath it for long-term storage. The child issues will be archived, so this single summary must record what the epic delivered. Your goal is to COMPRESS the content - the output MUST be much shorter than the input.
+
+**Epic:** {{.Title}}
+
+**Description:**
+{{.Description}}
+
+{{if .Design}}**Design:**
+{{.Design}}
+{{end}}
+
+{{if .AcceptanceCriteria}}**Acceptance Criteria:**
+{{.AcceptanceCriteria}}
+{{end}}
+
+{{if .Notes}}**Notes:**
+{{.Notes}}
+{{end}}
+
+{{if .CloseReason}}**Close Reason:** {{.CloseReason}}
+{{end}}
+
+**Child Issues:**
+{{range .Children}}
+- {{.ID}} ({{.Type}}): {{.Title}}{{if .CloseReason}} [closed: {{.CloseReason}}]{{end}}
+{{if .Description}}  {{.Description}}
+{{end}}{{end}}
+
+IMPORTANT: Your summary must be much shorter than the original. Be concise and eliminate redundancy.
+
+Provide a summary in this exact format:
+
+**Summary:** [2-3 concise sentences covering what the epic delivered and why]
+
+**Key Decisions:** [Brief bullet points of only the most important technical choices across the epic]
+
+**Delivered:** [One short bullet per significant child issue, prefixed with its ID; group minor ones]
+
+**Resolution:** [One sentence on final outcome and lasting impact]`
@@ -0,0 +1,208 @@
+package compact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// defaultOpenAIBaseURL is used when ai.base-url is not set. Point it at a
+// local server (e.g. http://localhost:11434/v1 for Ollama, or llama.cpp's
+// http://localhost:8080/v1) to compact without leaving the machine.
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIRequestTimeout bounds a single completion request; local models on
+// CPU can be slow.
+const openAIRequestTimeout = 5 * time.Minute
+
+func init() {
+	RegisterProvider(ProviderOpenAI, func(cfg ProviderConfig) (Summarizer, error) {
+		c, err := newOpenAIClient(cfg.BaseURL, cfg.APIKey, cfg.Model)
+		if err != nil {
+			return nil, err
+		}
+		c.auditEnabled = cfg.AuditEnabled
+		c.auditActor = cfg.AuditActor
+		return c, nil
+	})
+}
+
+// openAIClient summarizes issues with any OpenAI-compatible chat completions
+// endpoint.
+type openAIClient struct {
+	prompter
+	httpClient     *http.Client
+	baseURL        string
+	apiKey         string
+	model          string
+	maxRetries     int
+	initialBackoff time.Duration
+	auditEnabled   bool
+	auditActor     string
+}
+
+// newOpenAIClient creates an OpenAI-compatible client. The API key is
+// optional (local servers usually don't need one); OPENAI_API_KEY is used if
+// apiKey is empty. The model is required, since there is no sensible default
+// across servers.
+func newOpenAIClient(baseURL, apiKey, model string) (*openAIClient, error) {
+	if model == "" {
+		return nil, fmt.Errorf("the %s summarizer requires a model: fbd config set ai.model <name>", ProviderOpenAI)
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if apiKey == "" && baseURL == defaultOpenAIBaseURL {
+		return nil, fmt.Errorf("%w: set OPENAI_API_KEY or point ai.base-url at a local server", errAPIKeyRequired)
+	}
+
+	p, err := newPrompter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &openAIClient{
+		prompter:       p,
+		httpClient:     &http.Client{Timeout: openAIRequestTimeout},
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		apiKey:         apiKey,
+		model:          model,
+		maxRetries:     maxRetries,
+		initialBackoff: initialBackoff,
+	}, nil
+}
+
+// SummarizeTier1 creates a structured summary of an issue (Summary, Key Decisions, Resolution).
+func (c *openAIClient) SummarizeTier1(ctx context.Context, issue *types.Issue) (string, error) {
+	prompt, err := c.renderTier1Prompt(issue)
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	resp, callErr := callWithBackoff(ctx, c.maxRetries, c.initialBackoff, isOpenAIRetryable, func() (string, error) {
+		return c.complete(ctx, prompt)
+	})
+	if c.auditEnabled {
+		auditLLMCall(c.auditActor, issue.ID, c.model, prompt, resp, callErr)
+	}
+	return resp, callErr
+}
+
+// SummarizeEpic creates a single rollup summary of a closed epic and its descendants.
+func (c *openAIClient) SummarizeEpic(ctx context.Context, epic *types.Issue, descendants []*types.Issue) (string, error) {
+	prompt, err := c.renderRollupPrompt(epic, descendants)
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	resp, callErr := callWithBackoff(ctx, c.maxRetries, c.initialBackoff, isOpenAIRetryable, func() (string, error) {
+		return c.complete(ctx, prompt)
+	})
+	if c.auditEnabled {
+		auditLLMCall(c.auditActor, epic.ID, c.model, prompt, resp, callErr)
+	}
+	return resp, callErr
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// openAIStatusError is a non-2xx response from the endpoint.
+type openAIStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *openAIStatusError) Error() string {
+	return fmt.Sprintf("API returned %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *openAIClient) complete(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:     c.model,
+		Messages:  []openAIMessage{{Role: "user", Content: prompt}},
+		MaxTokens: 1024,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", &openAIStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	var chat openAIChatResponse
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return "", fmt.Errorf("unexpected response format: %w", err)
+	}
+	if len(chat.Choices) == 0 || chat.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("unexpected response format: no content")
+	}
+	return strings.TrimSpace(chat.Choices[0].Message.Content), nil
+}
+
+func isOpenAIRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var statusErr *openAIStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == 429 || statusErr.StatusCode >= 500
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package compact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestNewOpenAIClient_Validation(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	if _, err := newOpenAIClient("http://localhost:11434/v1", "", ""); err == nil {
+		t.Error("expected error without a model")
+	}
+
+	_, err := newOpenAIClient("", "", "gpt-4o-mini")
+	if !errors.Is(err, errAPIKeyRequired) {
+		t.Errorf("expected errAPIKeyRequired for the hosted endpoint without a key, got %v", err)
+	}
+
+	if _, err := newOpenAIClient("http://localhost:11434/v1", "", "llama3.2"); err != nil {
+		t.Errorf("local endpoint should not need a key: %v", err)
+	}
+}
+
+func TestOpenAIClient_SummarizeTier1(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req openAIChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Model != "llama3.2" || len(req.Messages) != 1 || !strings.Contains(req.Messages[0].Content, "Fix login") {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		// Fail once to exercise the retry
+		if calls.Add(1) == 1 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  **Summary:** Fixed login.  "}}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := newOpenAIClient(srv.URL+"/v1/", "sk-test", "llama3.2")
+	if err != nil {
+		t.Fatalf("newOpenAIClient: %v", err)
+	}
+	client.initialBackoff = time.Millisecond
+
+	summary, err := client.SummarizeTier1(context.Background(), &types.Issue{ID: "bd-1", Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("SummarizeTier1: %v", err)
+	}
+	if summary != "**Summary:** Fixed login." {
+		t.Errorf("summary = %q", summary)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls (one retry), got %d", calls.Load())
+	}
+}
+
+func TestOpenAIClient_NonRetryableError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client, err := newOpenAIClient(srv.URL, "", "missing")
+	if err != nil {
+		t.Fatalf("newOpenAIClient: %v", err)
+	}
+	client.initialBackoff = time.Millisecond
+
+	_, err = client.SummarizeTier1(context.Background(), &types.Issue{ID: "bd-1", Title: "x"})
+	var statusErr *openAIStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 status error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("error should include the response body: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("404 should not be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestIsOpenAIRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"context canceled", context.Canceled, false},
+		{"timeout error", timeoutErr{}, true},
+		{"429", &openAIStatusError{StatusCode: 429}, true},
+		{"502", &openAIStatusError{StatusCode: 502}, true},
+		{"401", &openAIStatusError{StatusCode: 401}, false},
+		{"generic error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOpenAIRetryable(tt.err); got != tt.expected {
+				t.Errorf("isOpenAIRetryable(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package compact
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/audit"
+	"github.com/steveyegge/fastbeads/internal/config"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// Built-in summarizer providers, selected with the ai.provider config key.
+const (
+	ProviderAnthropic  = "anthropic"  // Anthropic Messages API (default)
+	ProviderOpenAI     = "openai"     // Any OpenAI-compatible chat completions endpoint
+	ProviderExtractive = "extractive" // Deterministic, offline; no model involved
+)
+
+// Summarizer compresses closed issues into short structured summaries.
+type Summarizer interface {
+	// SummarizeTier1 summarizes a single issue.
+	SummarizeTier1(ctx context.Context, issue *types.Issue) (string, error)
+
+	// SummarizeEpic summarizes a closed epic together with all of its
+	// descendants, for a Tier 2 rollup.
+	SummarizeEpic(ctx context.Context, epic *types.Issue, descendants []*types.Issue) (string, error)
+}
+
+// ProviderConfig configures a summarizer provider. Providers ignore fields
+// they don't use.
+type ProviderConfig struct {
+	APIKey  string // Falls back to the provider's environment variable
+	Model   string // Empty means the provider's default
+	BaseURL string // API endpoint, for providers that take one
+
+	AuditEnabled bool   // Log prompts and responses to .beads/interactions.jsonl
+	AuditActor   string // Actor recorded in audit entries
+}
+
+// ProviderFactory creates a Summarizer for a provider. It returns an error
+// wrapping errAPIKeyRequired if the provider needs a key it wasn't given.
+type ProviderFactory func(cfg ProviderConfig) (Summarizer, error)
+
+// providerRegistry holds registered summarizer providers
+var providerRegistry = make(map[string]ProviderFactory)
+
+// RegisterProvider registers a summarizer provider, replacing any provider
+// of the same name.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerRegistry[name] = factory
+}
+
+// Providers returns the names of all registered providers, sorted.
+func Providers() []string {
+	names := make([]string, 0, len(providerRegistry))
+	for name := range providerRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSummarizer creates a summarizer using the named provider. An empty
+// name selects the Anthropic provider.
+func NewSummarizer(provider string, cfg ProviderConfig) (Summarizer, error) {
+	if provider == "" {
+		provider = ProviderAnthropic
+	}
+	factory, ok := providerRegistry[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown summarizer provider %q (available: %s)", provider, strings.Join(Providers(), ", "))
+	}
+	return factory(cfg)
+}
+
+// ConfiguredProvider returns the provider selected by the ai.provider config
+// key, defaulting to Anthropic.
+func ConfiguredProvider() string {
+	if provider := config.GetString("ai.provider"); provider != "" {
+		return provider
+	}
+	return ProviderAnthropic
+}
+
+// configuredModel returns ai.model only if it was set explicitly; its
+// default names an Anthropic model, which other providers can't use.
+func configuredModel() string {
+	if config.GetValueSource("ai.model") == config.SourceDefault {
+		return ""
+	}
+	return config.DefaultAIModel()
+}
+
+// configuredBaseURL returns the ai.base-url config key.
+func configuredBaseURL() string {
+	return config.GetString("ai.base-url")
+}
+
+// prompter renders the prompts shared by the LLM providers.
+type prompter struct {
+	tier1Template  *template.Template
+	rollupTemplate *template.Template
+}
+
+func newPrompter() (prompter, error) {
+	tier1, err := template.New("tier1").Parse(tier1PromptTemplate)
+	if err != nil {
+		return prompter{}, fmt.Errorf("failed to parse tier1 template: %w", err)
+	}
+	rollup, err := template.New("rollup").Parse(rollupPromptTemplate)
+	if err != nil {
+		return prompter{}, fmt.Errorf("failed to parse rollup template: %w", err)
+	}
+	return prompter{tier1Template: tier1, rollupTemplate: rollup}, nil
+}
+
+type tier1Data struct {
+	Title              string
+	Description        string
+	Design             string
+	AcceptanceCriteria string
+	Notes              string
+}
+
+func (p prompter) renderTier1Prompt(issue *types.Issue) (string, error) {
+	w := &bytesWriter{}
+	data := tier1Data{
+		Title:              issue.Title,
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+	}
+	if err := p.tier1Template.Execute(w, data); err != nil {
+		return "", err
+	}
+	return string(w.buf), nil
+}
+
+// rollupChildRunes caps each descendant's description in the rollup prompt,
+// so large epics still fit in the model's context.
+const rollupChildRunes = 600
+
+type rollupChild struct {
+	ID          string
+	Title       string
+	Type        string
+	Description string
+	CloseReason string
+}
+
+type rollupData struct {
+	tier1Data
+	CloseReason string
+	Children    []rollupChild
+}
+
+func (p prompter) renderRollupPrompt(epic *types.Issue, descendants []*types.Issue) (string, error) {
+	w := &bytesWriter{}
+	data := rollupData{
+		tier1Data: tier1Data{
+			Title:              epic.Title,
+			Description:        epic.Description,
+			Design:             epic.Design,
+			AcceptanceCriteria: epic.AcceptanceCriteria,
+			Notes:              epic.Notes,
+		},
+		CloseReason: epic.CloseReason,
+	}
+	for _, d := range descendants {
+		data.Children = append(data.Children, rollupChild{
+			ID:          d.ID,
+			Title:       d.Title,
+			Type:        string(d.IssueType),
+			Description: truncateRunes(strings.TrimSpace(d.Description), rollupChildRunes),
+			CloseReason: d.CloseReason,
+		})
+	}
+	if err := p.rollupTemplate.Execute(w, data); err != nil {
+		return "", err
+	}
+	return string(w.buf), nil
+}
+
+// callWithBackoff calls fn until it succeeds, returns a non-retryable error,
+// or maxRetries retries have failed, doubling the wait between attempts.
+func callWithBackoff(ctx context.Context, maxRetries int, initialBackoff time.Duration,
+	retryable func(error) bool, fn func() (string, error)) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := initialBackoff * time.Duration(math.Pow(2, float64(attempt-1)))
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		resp, err := fn()
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if !retryable(err) {
+			return "", fmt.Errorf("non-retryable error: %w", err)
+		}
+	}
+	return "", fmt.Errorf("failed after %d retries: %w", maxRetries+1, lastErr)
+}
+
+// auditLLMCall records a model call in the interactions log. Best-effort:
+// never fail compaction because audit logging failed.
+func auditLLMCall(actor, issueID, model, prompt, resp string, callErr error) {
+	e := &audit.Entry{
+		Kind:     "llm_call",
+		Actor:    actor,
+		IssueID:  issueID,
+		Model:    model,
+		Prompt:   prompt,
+		Response: resp,
+	}
+	if callErr != nil {
+		e.Error = callErr.Error()
+	}
+	_, _ = audit.Append(e)
+}
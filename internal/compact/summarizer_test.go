@@ -0,0 +1,90 @@
+package compact
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+type staticSummarizer string
+
+func (s staticSummarizer) SummarizeTier1(context.Context, *types.Issue) (string, error) {
+	return string(s), nil
+}
+
+func (s staticSummarizer) SummarizeEpic(context.Context, *types.Issue, []*types.Issue) (string, error) {
+	return string(s), nil
+}
+
+func TestProviders_BuiltIns(t *testing.T) {
+	got := strings.Join(Providers(), ",")
+	for _, name := range []string{ProviderAnthropic, ProviderExtractive, ProviderOpenAI} {
+		if !strings.Contains(got, name) {
+			t.Errorf("Providers() = %s, missing %s", got, name)
+		}
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	var gotCfg ProviderConfig
+	RegisterProvider("test-static", func(cfg ProviderConfig) (Summarizer, error) {
+		gotCfg = cfg
+		return staticSummarizer("short"), nil
+	})
+	defer delete(providerRegistry, "test-static")
+
+	c, err := New(&stubStore{}, "", &Config{Provider: "test-static", Model: "m1", BaseURL: "http://x", Actor: "ci"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if c.config.DryRun {
+		t.Error("custom provider should not fall back to dry run")
+	}
+	if gotCfg.Model != "m1" || gotCfg.BaseURL != "http://x" || gotCfg.AuditActor != "ci" {
+		t.Errorf("provider got config %+v", gotCfg)
+	}
+	if summary, _ := c.summarizer.SummarizeTier1(context.Background(), &types.Issue{}); summary != "short" {
+		t.Errorf("summary = %q", summary)
+	}
+}
+
+func TestNewSummarizer_UnknownProvider(t *testing.T) {
+	_, err := NewSummarizer("nope", ProviderConfig{})
+	if err == nil || !strings.Contains(err.Error(), "available:") {
+		t.Fatalf("expected unknown provider error listing providers, got %v", err)
+	}
+
+	if _, err := New(&stubStore{}, "", &Config{Provider: "nope"}); err == nil {
+		t.Error("New should fail for an unknown provider")
+	}
+}
+
+func TestNew_ExtractiveNeedsNoKey(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	c, err := New(&stubStore{}, "", &Config{Provider: ProviderExtractive})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if c.config.DryRun || c.summarizer == nil {
+		t.Error("extractive provider should be usable without an API key")
+	}
+}
+
+func TestNew_OpenAIWithoutKeyFallsToDryRun(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	c, err := New(&stubStore{}, "", &Config{Provider: ProviderOpenAI, Model: "gpt-4o-mini"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !c.config.DryRun {
+		t.Error("expected dry run without a key for the hosted endpoint")
+	}
+
+	_, err = NewSummarizer(ProviderOpenAI, ProviderConfig{BaseURL: "http://localhost:8080/v1"})
+	if err == nil || errors.Is(err, errAPIKeyRequired) {
+		t.Errorf("expected missing-model error, got %v", err)
+	}
+}
@@ -200,6 +200,8 @@ func Initialize() error {
 
 	// AI configuration defaults
 	v.SetDefault("ai.model", "claude-haiku-4-5-20251001")
+	v.SetDefault("ai.provider", "anthropic") // Compaction summarizer: anthropic, openai, extractive
+	v.SetDefault("ai.base-url", "")          // OpenAI-compatible endpoint (e.g. http://localhost:11434/v1)
 
 	// External projects for cross-project dependency resolution (bd-h807)
 	// Maps project names to paths for resolving external: blocked_by references
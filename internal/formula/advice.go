@@ -13,6 +13,7 @@
 package formula
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 )
@@ -93,21 +94,21 @@ func applyAdviceWithGuard(steps []*Step, advice []*AdviceRule, originalIDs map[s
 
 			// Collect before steps
 			if rule.Before != nil {
-				beforeSteps = append(beforeSteps, adviceStepToStep(rule.Before, step))
+				beforeSteps = append(beforeSteps, adviceStepToStep(rule.Before, step, rule, "before"))
 			}
 			if rule.Around != nil {
-				for _, as := range rule.Around.Before {
-					beforeSteps = append(beforeSteps, adviceStepToStep(as, step))
+				for i, as := range rule.Around.Before {
+					beforeSteps = append(beforeSteps, adviceStepToStep(as, step, rule, fmt.Sprintf("around.before[%d]", i)))
 				}
 			}
 
 			// Collect after steps
 			if rule.After != nil {
-				afterSteps = append(afterSteps, adviceStepToStep(rule.After, step))
+				afterSteps = append(afterSteps, adviceStepToStep(rule.After, step, rule, "after"))
 			}
 			if rule.Around != nil {
-				for _, as := range rule.Around.After {
-					afterSteps = append(afterSteps, adviceStepToStep(as, step))
+				for i, as := range rule.Around.After {
+					afterSteps = append(afterSteps, adviceStepToStep(as, step, rule, fmt.Sprintf("around.after[%d]", i)))
 				}
 			}
 		}
@@ -156,7 +157,8 @@ func applyAdviceWithGuard(steps []*Step, advice []*AdviceRule, originalIDs map[s
 
 // adviceStepToStep converts an AdviceStep to a Step.
 // Substitutes {step.id} placeholders with the target step's ID.
-func adviceStepToStep(as *AdviceStep, target *Step) *Step {
+// part names the advice step within its rule (e.g. "before").
+func adviceStepToStep(as *AdviceStep, target *Step, rule *AdviceRule, part string) *Step {
 	// Substitute {step.id} in ID and Title
 	id := substituteStepRef(as.ID, target)
 	title := substituteStepRef(as.Title, target)
@@ -165,14 +167,21 @@ func adviceStepToStep(as *AdviceStep, target *Step) *Step {
 	}
 	desc := substituteStepRef(as.Description, target)
 
+	// Trace back to the rule when the parser recorded where it came from;
+	// otherwise inherit the target's formula with a generic "advice" location
+	sourceFormula, sourceLocation := target.SourceFormula, "advice"
+	if rule.SourceFormula != "" {
+		sourceFormula = rule.SourceFormula
+		sourceLocation = rule.SourceLocation + "." + part
+	}
+
 	return &Step{
-		ID:            id,
-		Title:         title,
-		Description:   desc,
-		Type:          as.Type,
-		SourceFormula: target.SourceFormula, // Inherit source formula from target
-		// SourceLocation will be "advice" to indicate this came from advice transformation
-		SourceLocation: "advice",
+		ID:             id,
+		Title:          title,
+		Description:    desc,
+		Type:           as.Type,
+		SourceFormula:  sourceFormula,
+		SourceLocation: sourceLocation,
 	}
 }
 
@@ -373,3 +373,37 @@ func getStepIDs(steps []*Step) []string {
 	}
 	return ids
 }
+
+func TestApplyAdvice_SourceInfo(t *testing.T) {
+	p := NewParser()
+	aspect, err := p.Parse([]byte(`{"formula": "aspect-audit", "type": "aspect", "advice": [
+		{"target": "build", "before": {"id": "lint-{step.id}"}},
+		{"target": "build", "around": {"after": [{"id": "scan-{step.id}"}]}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetSourceInfo(aspect)
+
+	steps := []*Step{{ID: "build", SourceFormula: "mol-ci", SourceLocation: "steps[0]"}}
+	sources := make(map[string]string)
+	for _, s := range ApplyAdvice(steps, aspect.Advice) {
+		sources[s.ID] = s.SourceFormula + "@" + s.SourceLocation
+	}
+	want := map[string]string{
+		"lint-build": "aspect-audit@advice[0].before",
+		"build":      "mol-ci@steps[0]",
+		"scan-build": "aspect-audit@advice[1].around.after[0]",
+	}
+	for id, source := range want {
+		if sources[id] != source {
+			t.Errorf("%s source = %q, want %q", id, sources[id], source)
+		}
+	}
+
+	// Rules built in code fall back to the target's formula
+	inline := ApplyAdvice(steps, []*AdviceRule{{Target: "build", After: &AdviceStep{ID: "notify"}}})
+	if got := inline[1].SourceFormula + "@" + inline[1].SourceLocation; got != "mol-ci@advice" {
+		t.Errorf("inline advice source = %q", got)
+	}
+}
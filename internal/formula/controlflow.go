@@ -181,7 +181,7 @@ func expandLoopWithVars(step *Step, vars map[string]string) ([]*Step, error) {
 		}
 	} else {
 		// Conditional loop: expand once with loop metadata
-		// The runtime executor will re-run until condition is met or max reached
+		// `fbd mol advance` unrolls further iterations until the condition is met or max reached
 		iterSteps, err := expandLoopIteration(step, 1, nil)
 		if err != nil {
 			return nil, err
@@ -484,7 +484,7 @@ func applyBranchesWithMap(stepMap map[string]*Step, compose *ComposeRules) error
 // ApplyGates adds gate conditions to steps.
 // For each gate rule:
 //   - The target step gets a "gate:condition" label
-//   - At runtime, the patrol executor evaluates the condition
+//   - At runtime, `fbd mol advance` evaluates the condition and closes the gate
 //
 // Returns a new steps slice with gate labels added.
 // The original steps slice is not modified.
@@ -72,7 +72,7 @@ func defaultSearchPaths() []string {
 		paths = append(paths, filepath.Join(gtRoot, ".beads", "formulas"))
 	}
 
-	return paths
+	return WithVendorPaths(paths)
 }
 
 // ParseFile parses a formula from a file path.
@@ -239,12 +239,22 @@ func (p *Parser) Resolve(formula *Formula) (*Formula, error) {
 
 // loadFormula loads a formula by name from search paths.
 // Tries TOML first (.formula.toml), then falls back to JSON (.formula.json).
+// Versioned references (name@constraint) resolve against vendored packages.
 func (p *Parser) loadFormula(name string) (*Formula, error) {
 	// Check cache first
 	if cached, ok := p.cache[name]; ok {
 		return cached, nil
 	}
 
+	if base, constraint := SplitFormulaRef(name); constraint != "" {
+		f, err := p.loadVersioned(base, constraint)
+		if err != nil {
+			return nil, err
+		}
+		p.cache[name] = f
+		return f, nil
+	}
+
 	// Search for the formula file - try TOML first, then JSON
 	extensions := []string{FormulaExtTOML, FormulaExtJSON}
 	for _, dir := range p.searchPaths {
@@ -259,6 +269,42 @@ func (p *Parser) loadFormula(name string) (*Formula, error) {
 	return nil, fmt.Errorf("formula %q not found in search paths", name)
 }
 
+// loadVersioned loads a formula from the first vendored package that
+// provides it at a version satisfying the constraint. Packages are found
+// through the lockfile next to each search path.
+func (p *Parser) loadVersioned(name, constraint string) (*Formula, error) {
+	c, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []string
+	for _, dir := range p.searchPaths {
+		lock, err := LoadLockfile(LockfilePath(dir))
+		if err != nil {
+			return nil, err
+		}
+		for _, pkgName := range lock.PackageNames() {
+			pkg := lock.Packages[pkgName]
+			file, ok := pkg.FormulaFile(name)
+			if !ok {
+				continue
+			}
+			if !c.Allows(pkg.Version) {
+				mismatches = append(mismatches, fmt.Sprintf("%s %s", pkgName, pkg.Version))
+				continue
+			}
+			return p.ParseFile(filepath.Join(VendorDir(dir), pkgName, file))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return nil, fmt.Errorf("formula %s@%s: installed package(s) %s do not satisfy %s (run 'fbd formula update', or 'fbd formula update --latest' for a new major version)",
+			name, constraint, strings.Join(mismatches, ", "), constraint)
+	}
+	return nil, fmt.Errorf("formula %s@%s not found in installed packages (run 'fbd formula install')", name, constraint)
+}
+
 // LoadByName loads a formula by name from search paths.
 // This is the public API for loading formulas used by expansion operators.
 func (p *Parser) LoadByName(name string) (*Formula, error) {
@@ -440,6 +486,11 @@ func SetSourceInfo(formula *Formula) {
 	setSourceInfoRecursive(formula.Steps, formula.Formula, "steps")
 	// Also set source info on template steps for expansion formulas
 	setSourceInfoRecursive(formula.Template, formula.Formula, "template")
+	// Advice rules, so inserted steps can point back to the rule (e.g. in aspects)
+	for i, rule := range formula.Advice {
+		rule.SourceFormula = formula.Formula
+		rule.SourceLocation = fmt.Sprintf("advice[%d]", i)
+	}
 }
 
 // setSourceInfoRecursive recursively sets source info on steps.
@@ -1480,3 +1480,63 @@ title = "Test"
 		}
 	}
 }
+
+// TestParseTOML_Tests verifies test cases and assertions parse from TOML.
+func TestParseTOML_Tests(t *testing.T) {
+	tomlData := `
+formula = "mol-release"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[tests]]
+name = "prod"
+vars = { env = "prod" }
+
+[[tests.assert]]
+issues = 2
+
+[[tests.assert]]
+step = "build"
+not_depends_on = ["ship"]
+labels = ["ci"]
+`
+	p := NewParser()
+	formula, err := p.ParseTOML([]byte(tomlData))
+	if err != nil {
+		t.Fatalf("ParseTOML failed: %v", err)
+	}
+	if len(formula.Tests) != 1 {
+		t.Fatalf("len(Tests) = %d, want 1", len(formula.Tests))
+	}
+	tc := formula.Tests[0]
+	if tc.Name != "prod" || tc.Vars["env"] != "prod" || len(tc.Assert) != 2 {
+		t.Fatalf("test case = %+v", tc)
+	}
+	if tc.Assert[0].Issues == nil || *tc.Assert[0].Issues != 2 {
+		t.Errorf("Assert[0].Issues = %v, want 2", tc.Assert[0].Issues)
+	}
+	if a := tc.Assert[1]; a.Step != "build" || len(a.NotDependsOn) != 1 || len(a.Labels) != 1 {
+		t.Errorf("Assert[1] = %+v", a)
+	}
+	for i, a := range tc.Assert {
+		if err := a.Validate(); err != nil {
+			t.Errorf("Assert[%d].Validate() = %v", i, err)
+		}
+	}
+}
+
+func TestTestAssertion_Validate(t *testing.T) {
+	two := 2
+	for _, a := range []*TestAssertion{
+		{},
+		{Step: "build"},
+		{Issues: &two, Step: "build", Absent: true},
+		{Step: "build", Absent: true, DependsOn: []string{"setup"}},
+	} {
+		if err := a.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", a)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package formula
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Formula packages are sets of formulas shared across repositories. They are
+// vendored into <formulas>/vendor/<package>/ and recorded in a lockfile next
+// to the formulas directory (.beads/formulas.lock for .beads/formulas).
+const (
+	LockfileName    = "formulas.lock"
+	VendorDirName   = "vendor"
+	lockfileVersion = 1
+)
+
+// Lockfile records the exact version and content of each vendored package.
+type Lockfile struct {
+	Version  int                       `json:"lockfile_version"`
+	Packages map[string]*LockedPackage `json:"packages"`
+}
+
+// LockedPackage is one vendored formula package.
+type LockedPackage struct {
+	Source     string            `json:"source"`           // Git URL or path
+	Constraint string            `json:"constraint"`       // Range used by 'fbd formula update'
+	Version    string            `json:"version"`          // Installed version (the git tag)
+	Commit     string            `json:"commit,omitempty"` // Commit the tag pointed to
+	Hash       string            `json:"hash"`             // Hash over all files
+	Files      map[string]string `json:"files"`            // File name -> content hash
+}
+
+// LockfilePath returns the lockfile for a formulas directory.
+func LockfilePath(formulaDir string) string {
+	return filepath.Join(filepath.Dir(formulaDir), LockfileName)
+}
+
+// VendorDir returns the directory vendored packages live in.
+func VendorDir(formulaDir string) string {
+	return filepath.Join(formulaDir, VendorDirName)
+}
+
+// ValidatePackageName checks that a package name can be used as a directory
+// under the vendor directory: non-empty, without path separators, and not
+// starting with a dot.
+func ValidatePackageName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid package name %q", name)
+	}
+	return nil
+}
+
+// LoadLockfile reads a lockfile. A missing file is an empty lockfile.
+// Lockfiles are committed, so package names are validated before any of
+// them is used as a path.
+func LoadLockfile(path string) (*Lockfile, error) {
+	lock := &Lockfile{Version: lockfileVersion, Packages: make(map[string]*LockedPackage)}
+	// #nosec G304 -- path is derived from formula search paths
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if lock.Version > lockfileVersion {
+		return nil, fmt.Errorf("%s has lockfile_version %d, this fbd supports %d", path, lock.Version, lockfileVersion)
+	}
+	if lock.Packages == nil {
+		lock.Packages = make(map[string]*LockedPackage)
+	}
+	for name, pkg := range lock.Packages {
+		if err := ValidatePackageName(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if pkg == nil {
+			return nil, fmt.Errorf("%s: package %q has no entry", path, name)
+		}
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile.
+func (l *Lockfile) Save(path string) error {
+	l.Version = lockfileVersion
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { // #nosec G306 -- lockfile is meant to be committed
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// PackageNames returns the locked package names in sorted order.
+func (l *Lockfile) PackageNames() []string {
+	names := make([]string, 0, len(l.Packages))
+	for name := range l.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormulaFile returns the package file that defines a formula, if any.
+func (p *LockedPackage) FormulaFile(name string) (string, bool) {
+	for _, ext := range []string{FormulaExtTOML, FormulaExtJSON} {
+		if _, ok := p.Files[name+ext]; ok {
+			return name + ext, true
+		}
+	}
+	return "", false
+}
+
+// IsFormulaFile reports whether a file name is a formula file.
+func IsFormulaFile(name string) bool {
+	return strings.HasSuffix(name, FormulaExtTOML) || strings.HasSuffix(name, FormulaExtJSON)
+}
+
+// HashFormulaFiles hashes the formula files directly in dir. It returns each
+// file's hash and a hash over all of them, as recorded in the lockfile.
+func HashFormulaFiles(dir string) (map[string]string, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	files := make(map[string]string)
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsFormulaFile(entry.Name()) {
+			continue
+		}
+		// #nosec G304 -- entry comes from listing dir
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, "", err
+		}
+		sum := sha256.Sum256(data)
+		files[entry.Name()] = "sha256:" + hex.EncodeToString(sum[:])
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", files[name], name)
+	}
+	return files, "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WithVendorPaths adds each formulas directory's vendored packages right
+// after it, so project packages shadow user packages and so on.
+func WithVendorPaths(paths []string) []string {
+	var result []string
+	for _, dir := range paths {
+		result = append(result, dir)
+		entries, err := os.ReadDir(VendorDir(dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				result = append(result, filepath.Join(VendorDir(dir), entry.Name()))
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package formula
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeVendoredPackage vendors a one-formula package and locks it.
+func writeVendoredPackage(t *testing.T, formulaDir, pkg, version, formulaJSON string) {
+	t.Helper()
+	dir := filepath.Join(VendorDir(formulaDir), pkg)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "base.formula.json"), []byte(formulaJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, hash, err := HashFormulaFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := LoadLockfile(LockfilePath(formulaDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Packages[pkg] = &LockedPackage{Source: "/src/" + pkg, Constraint: "^" + version, Version: "v" + version, Hash: hash, Files: files}
+	if err := lock.Save(LockfilePath(formulaDir)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockfile_RoundTrip(t *testing.T) {
+	formulaDir := filepath.Join(t.TempDir(), ".beads", "formulas")
+	path := LockfilePath(formulaDir)
+	if filepath.Base(path) != LockfileName || filepath.Dir(path) != filepath.Dir(formulaDir) {
+		t.Fatalf("LockfilePath = %s", path)
+	}
+
+	lock, err := LoadLockfile(path)
+	if err != nil {
+		t.Fatalf("missing lockfile: %v", err)
+	}
+	if len(lock.Packages) != 0 {
+		t.Fatalf("missing lockfile has %d packages", len(lock.Packages))
+	}
+
+	writeVendoredPackage(t, formulaDir, "zeta", "1.0.0", `{"formula": "base", "steps": [{"id": "a", "title": "A"}]}`)
+	writeVendoredPackage(t, formulaDir, "alpha", "2.1.0", `{"formula": "base", "steps": [{"id": "b", "title": "B"}]}`)
+
+	lock, err = LoadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(lock.PackageNames(), ","); got != "alpha,zeta" {
+		t.Errorf("PackageNames = %s", got)
+	}
+	pkg := lock.Packages["alpha"]
+	if pkg.Version != "v2.1.0" || !strings.HasPrefix(pkg.Hash, "sha256:") {
+		t.Errorf("alpha = %+v", pkg)
+	}
+	if file, ok := pkg.FormulaFile("base"); !ok || file != "base.formula.json" {
+		t.Errorf("FormulaFile(base) = %q, %v", file, ok)
+	}
+	if _, ok := pkg.FormulaFile("other"); ok {
+		t.Error("FormulaFile(other) found a file")
+	}
+}
+
+func TestLockfile_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockfileName)
+	if err := os.WriteFile(path, []byte(`{"lockfile_version": 99, "packages": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLockfile(path); err == nil {
+		t.Error("LoadLockfile accepted a newer lockfile_version")
+	}
+}
+
+func TestLockfile_RejectsUnsafeNames(t *testing.T) {
+	for _, name := range []string{"../../..", "a/b", `a\\b`, ".hidden", ""} {
+		path := filepath.Join(t.TempDir(), LockfileName)
+		data := `{"lockfile_version": 1, "packages": {"` + name + `": {"source": "x", "version": "v1.0.0"}}}`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadLockfile(path); err == nil {
+			t.Errorf("LoadLockfile accepted package name %q", name)
+		}
+	}
+}
+
+func TestHashFormulaFiles_DetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.formula.json", `{"formula": "a"}`)
+	write("README.md", "not hashed")
+	files, before, err := HashFormulaFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("hashed %v, want only the formula", files)
+	}
+
+	write("README.md", "still not hashed")
+	if _, h, _ := HashFormulaFiles(dir); h != before {
+		t.Error("hash changed for a non-formula file")
+	}
+	write("a.formula.json", `{"formula": "a", "version": 2}`)
+	if _, h, _ := HashFormulaFiles(dir); h == before {
+		t.Error("hash unchanged after editing a formula")
+	}
+}
+
+func TestWithVendorPaths(t *testing.T) {
+	project := filepath.Join(t.TempDir(), "formulas")
+	user := filepath.Join(t.TempDir(), "formulas")
+	for _, dir := range []string{filepath.Join(VendorDir(project), "b"), filepath.Join(VendorDir(project), "a")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := WithVendorPaths([]string{project, user})
+	want := []string{project, filepath.Join(VendorDir(project), "a"), filepath.Join(VendorDir(project), "b"), user}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("WithVendorPaths = %v, want %v", got, want)
+	}
+}
+
+func TestLoadByName_Versioned(t *testing.T) {
+	formulaDir := filepath.Join(t.TempDir(), ".beads", "formulas")
+	writeVendoredPackage(t, formulaDir, "ops", "1.4.0", `{"formula": "base", "description": "ops", "steps": [{"id": "a", "title": "A"}]}`)
+	writeVendoredPackage(t, formulaDir, "ops2", "2.0.0", `{"formula": "base", "description": "ops2", "steps": [{"id": "a", "title": "A"}]}`)
+
+	p := NewParser(WithVendorPaths([]string{formulaDir})...)
+	for ref, want := range map[string]string{"base@^1.2": "ops", "base@2": "ops2", "base@>=1.0": "ops"} {
+		f, err := p.LoadByName(ref)
+		if err != nil {
+			t.Fatalf("LoadByName(%s): %v", ref, err)
+		}
+		if f.Description != want {
+			t.Errorf("LoadByName(%s) came from %s, want %s", ref, f.Description, want)
+		}
+	}
+
+	_, err := p.LoadByName("base@^3")
+	if err == nil || !strings.Contains(err.Error(), "do not satisfy") {
+		t.Errorf("LoadByName(base@^3) error = %v, want unsatisfied constraint", err)
+	}
+	if _, err := p.LoadByName("missing@^1"); err == nil {
+		t.Error("LoadByName(missing@^1) succeeded")
+	}
+}
+
+func TestResolve_VersionedExtends(t *testing.T) {
+	formulaDir := filepath.Join(t.TempDir(), ".beads", "formulas")
+	writeVendoredPackage(t, formulaDir, "ops", "1.4.0", `{"formula": "base", "steps": [{"id": "build", "title": "Build"}]}`)
+
+	p := NewParser(WithVendorPaths([]string{formulaDir})...)
+	child, err := p.Parse([]byte(`{"formula": "child", "extends": ["base@~1.4"], "steps": [{"id": "ship", "title": "Ship", "needs": ["build"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := p.Resolve(child)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(resolved.Steps) != 2 || resolved.Steps[0].ID != "build" {
+		t.Errorf("resolved steps = %+v", resolved.Steps)
+	}
+}
@@ -104,10 +104,80 @@ type Formula struct {
 	// Patrol and release workflows should typically use "vapor" since they're operational.
 	Phase string `json:"phase,omitempty"`
 
+	// Tests are cases run by 'fbd formula test'. Each cooks the formula with
+	// its vars, compares the result to a golden snapshot stored next to the
+	// formula, and checks its assertions. Tests are not inherited via extends.
+	Tests []*TestCase `json:"tests,omitempty"`
+
 	// Source tracks where this formula was loaded from (set by parser).
 	Source string `json:"source,omitempty"`
 }
 
+// TestCase is one 'fbd formula test' case.
+//
+//	[[tests]]
+//	name = "with-docs"
+//	vars = { component = "auth", docs = "true" }
+//
+//	[[tests.assert]]
+//	issues = 6
+//
+//	[[tests.assert]]
+//	step = "ship"
+//	depends_on = ["review", "docs"]
+type TestCase struct {
+	// Name identifies the case and its golden file
+	// (<formula>.<name>.golden.yaml). Defaults to "default".
+	Name string `json:"name,omitempty"`
+
+	// Vars are the variables to cook with. Formula defaults apply.
+	Vars map[string]string `json:"vars,omitempty"`
+
+	// Assert lists checks on the cooked issues.
+	Assert []*TestAssertion `json:"assert,omitempty"`
+}
+
+// TestAssertion is a check on a cooked formula. Set Issues, or Step with
+// any of the step checks.
+type TestAssertion struct {
+	// Issues is the expected number of issues, including the root.
+	Issues *int `json:"issues,omitempty"`
+
+	// Step selects the issue the remaining fields check, by step ID
+	// ("ship") or full template ID ("mol-release.ship").
+	Step string `json:"step,omitempty"`
+
+	// DependsOn lists steps the step must depend on (any type but parent-child).
+	DependsOn []string `json:"depends_on,omitempty" toml:"depends_on,omitempty"`
+
+	// NotDependsOn lists steps the step must not depend on.
+	NotDependsOn []string `json:"not_depends_on,omitempty" toml:"not_depends_on,omitempty"`
+
+	// Labels the step must have.
+	Labels []string `json:"labels,omitempty"`
+
+	// Absent asserts the step is not created (e.g. its condition is false).
+	Absent bool `json:"absent,omitempty"`
+}
+
+// Validate checks that the assertion checks something.
+func (a *TestAssertion) Validate() error {
+	hasStepChecks := len(a.DependsOn) > 0 || len(a.NotDependsOn) > 0 || len(a.Labels) > 0 || a.Absent
+	switch {
+	case a.Issues != nil && a.Step != "":
+		return fmt.Errorf("assertion sets both issues and step")
+	case a.Issues != nil:
+		return nil
+	case a.Step == "":
+		return fmt.Errorf("assertion needs issues or step")
+	case !hasStepChecks:
+		return fmt.Errorf("assertion on step %q checks nothing (set depends_on, not_depends_on, labels or absent)", a.Step)
+	case a.Absent && (len(a.DependsOn) > 0 || len(a.Labels) > 0):
+		return fmt.Errorf("assertion on step %q sets absent with depends_on or labels", a.Step)
+	}
+	return nil
+}
+
 // VarDef defines a template variable with optional validation.
 type VarDef struct {
 	// Description explains what this variable is for.
@@ -275,6 +345,7 @@ type LoopSpec struct {
 
 	// Until is a condition that ends the loop.
 	// Format matches condition evaluator syntax (e.g., "step.status == 'complete'").
+	// Only the first iteration is cooked; `fbd mol advance` adds the rest.
 	Until string `json:"until,omitempty"`
 
 	// Max is the maximum iterations for conditional loops.
@@ -355,7 +426,7 @@ type BranchRule struct {
 }
 
 // GateRule defines a condition that must be satisfied before a step proceeds.
-// Gates are evaluated at runtime by the patrol executor.
+// Gates are evaluated at runtime by `fbd mol advance` (or the patrol running it).
 type GateRule struct {
 	// Before is the step ID that the gate applies to.
 	// The condition must be satisfied before this step can start.
@@ -488,6 +559,11 @@ type AdviceRule struct {
 
 	// Around wraps the target with before and after steps.
 	Around *AroundAdvice `json:"around,omitempty"`
+
+	// SourceFormula and SourceLocation track where this rule was defined
+	// (e.g. "aspect-lint", "advice[0]"). Set by the parser.
+	SourceFormula  string `json:"-"`
+	SourceLocation string `json:"-"`
 }
 
 // AdviceStep defines a step to insert via advice.
@@ -0,0 +1,184 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Versioned formula references have the form name@constraint, e.g.
+// "mol-deploy@^1.2". They resolve against packages vendored by
+// 'fbd formula install' (see Lockfile).
+//
+// Constraints use the usual semver range forms:
+//
+//	1.2.3, =1.2.3   exactly that version
+//	^1.2            >=1.2.0 <2.0.0 (for 0.x: >=0.2.0 <0.3.0)
+//	~1.2.3          >=1.2.3 <1.3.0
+//	>=1.0 <2        comparisons; space or comma separated, all must hold
+//	*, latest       any release
+//
+// Pre-releases only match an exact constraint.
+
+// VersionConstraint is a parsed semver range.
+type VersionConstraint struct {
+	raw   string
+	terms []versionTerm
+}
+
+type versionTerm struct {
+	op      string // "=", ">", ">=", "<", "<="
+	version string // Canonical, with a "v" prefix
+}
+
+// SplitFormulaRef splits "name@constraint" into its parts. The constraint
+// is empty for plain names.
+func SplitFormulaRef(ref string) (name, constraint string) {
+	name, constraint, _ = strings.Cut(ref, "@")
+	return name, constraint
+}
+
+// CanonicalVersion normalizes a version or tag ("1.2", "v1.2.0") to
+// canonical semver ("v1.2.0"). Returns "" if it isn't a version.
+func CanonicalVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Canonical(v)
+}
+
+// ParseVersionConstraint parses a constraint. An empty constraint, "*" and
+// "latest" allow any release.
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	c := &VersionConstraint{raw: strings.TrimSpace(s)}
+	fields := strings.FieldsFunc(c.raw, func(r rune) bool { return r == ' ' || r == ',' })
+	for _, field := range fields {
+		if field == "*" || field == "latest" {
+			continue
+		}
+		terms, err := parseVersionTerm(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.terms = append(c.terms, terms...)
+	}
+	return c, nil
+}
+
+// parseVersionTerm expands one constraint field into comparisons.
+func parseVersionTerm(field string) ([]versionTerm, error) {
+	op := "="
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(field, prefix); ok {
+			op, field = prefix, rest
+			break
+		}
+	}
+	version := CanonicalVersion(field)
+	if version == "" {
+		return nil, fmt.Errorf("%q is not a version", field)
+	}
+	// Number of components given: "1" -> 1, "1.2" -> 2, "1.2.3" -> 3
+	parts := strings.Count(strings.SplitN(strings.TrimPrefix(field, "v"), "-", 2)[0], ".") + 1
+
+	switch op {
+	case "^":
+		return []versionTerm{{">=", version}, {"<", caretUpperBound(version, parts)}}, nil
+	case "~":
+		upper := bumpVersion(version, 1)
+		if parts == 1 {
+			upper = bumpVersion(version, 0)
+		}
+		return []versionTerm{{">=", version}, {"<", upper}}, nil
+	case "=":
+		if parts < 3 {
+			// "=1.2" means any 1.2.x
+			return []versionTerm{{">=", version}, {"<", bumpVersion(version, parts-1)}}, nil
+		}
+	}
+	return []versionTerm{{op, version}}, nil
+}
+
+// caretUpperBound returns the exclusive upper bound of ^version: the next
+// version that changes the leftmost non-zero component given.
+func caretUpperBound(version string, parts int) string {
+	major, minor, _ := versionParts(version)
+	switch {
+	case major != 0 || parts == 1:
+		return bumpVersion(version, 0)
+	case minor != 0 || parts == 2:
+		return bumpVersion(version, 1)
+	default:
+		return bumpVersion(version, 2)
+	}
+}
+
+// bumpVersion increments component i (0 major, 1 minor, 2 patch) and zeroes
+// the ones after it.
+func bumpVersion(version string, i int) string {
+	v := [3]int{}
+	v[0], v[1], v[2] = versionParts(version)
+	v[i]++
+	for j := i + 1; j < 3; j++ {
+		v[j] = 0
+	}
+	return fmt.Sprintf("v%d.%d.%d", v[0], v[1], v[2])
+}
+
+// versionParts returns the numeric components of a canonical version.
+func versionParts(version string) (major, minor, patch int) {
+	core := strings.SplitN(strings.TrimPrefix(version, "v"), "-", 2)[0]
+	_, _ = fmt.Sscanf(core, "%d.%d.%d", &major, &minor, &patch)
+	return major, minor, patch
+}
+
+// String returns the constraint as written.
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+// Allows reports whether a version satisfies the constraint.
+func (c *VersionConstraint) Allows(version string) bool {
+	version = CanonicalVersion(version)
+	if version == "" {
+		return false
+	}
+	exact := false
+	for _, t := range c.terms {
+		cmp := semver.Compare(version, t.version)
+		var ok bool
+		switch t.op {
+		case "=":
+			ok, exact = cmp == 0, true
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return exact || semver.Prerelease(version) == ""
+}
+
+// LatestAllowed returns the highest of versions that satisfies the
+// constraint, or "" if none does.
+func (c *VersionConstraint) LatestAllowed(versions []string) string {
+	best := ""
+	for _, v := range versions {
+		if !c.Allows(v) {
+			continue
+		}
+		if best == "" || semver.Compare(CanonicalVersion(v), CanonicalVersion(best)) > 0 {
+			best = v
+		}
+	}
+	return best
+}
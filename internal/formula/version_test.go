@@ -0,0 +1,85 @@
+package formula
+
+import (
+	"testing"
+)
+
+func TestVersionConstraint_Allows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "v3.1.4", true},
+		{"*", "0.0.1", true},
+		{"latest", "v1.0.0", true},
+		{"1.2.3", "v1.2.3", true},
+		{"=1.2.3", "1.2.4", false},
+		{"1.2", "v1.2.9", true},
+		{"1.2", "v1.3.0", false},
+		{"^1.2", "v1.9.0", true},
+		{"^1.2", "v2.0.0", false},
+		{"^1.2", "v1.1.9", false},
+		{"^0.2.3", "v0.2.9", true},
+		{"^0.2.3", "v0.3.0", false},
+		{"^0.0.3", "v0.0.4", false},
+		{"~1.2.3", "v1.2.9", true},
+		{"~1.2.3", "v1.3.0", false},
+		{"~1", "v1.9.0", true},
+		{">=1.0 <2", "v1.5.0", true},
+		{">=1.0, <2", "v2.0.0", false},
+		{">1.0.0", "v1.0.0", false},
+		{"<=1.0.0", "v1.0.0", true},
+		{"^1.0", "v1.1.0-rc.1", false},
+		{"1.1.0-rc.1", "v1.1.0-rc.1", true},
+		{"^1.0", "not-a-version", false},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseVersionConstraint(%q): %v", tt.constraint, err)
+		}
+		if got := c.Allows(tt.version); got != tt.want {
+			t.Errorf("%q.Allows(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionConstraint_Invalid(t *testing.T) {
+	for _, s := range []string{"^x", ">=", "1.2.3.4", "~banana"} {
+		if _, err := ParseVersionConstraint(s); err == nil {
+			t.Errorf("ParseVersionConstraint(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestVersionConstraint_LatestAllowed(t *testing.T) {
+	versions := []string{"v1.0.0", "1.4.2", "v1.10.0", "v2.0.0", "v2.1.0-beta"}
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"^1.0", "v1.10.0"},
+		{"~1.4", "1.4.2"},
+		{"", "v2.0.0"},
+		{"^3", ""},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.LatestAllowed(versions); got != tt.want {
+			t.Errorf("%q.LatestAllowed() = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestSplitFormulaRef(t *testing.T) {
+	if name, c := SplitFormulaRef("mol-deploy@^1.2"); name != "mol-deploy" || c != "^1.2" {
+		t.Errorf("SplitFormulaRef = %q, %q", name, c)
+	}
+	if name, c := SplitFormulaRef("mol-deploy"); name != "mol-deploy" || c != "" {
+		t.Errorf("SplitFormulaRef = %q, %q", name, c)
+	}
+}
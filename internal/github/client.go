@@ -0,0 +1,415 @@
+// Package github provides client and data types for the GitHub REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewClient creates a new GitHub client with the given token, API base URL
+// and repository ("owner/name"). An empty baseURL means github.com.
+func NewClient(token, baseURL, repo string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		Token:   token,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Repo:    repo,
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// WithHTTPClient returns a new client configured to use the specified HTTP client.
+// This is useful for testing or customizing timeouts and transport settings.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	return &Client{
+		Token:      c.Token,
+		BaseURL:    c.BaseURL,
+		Repo:       c.Repo,
+		HTTPClient: httpClient,
+	}
+}
+
+// WithEndpoint returns a new client configured to use a custom API endpoint.
+// This is useful for testing with mock servers or GitHub Enterprise Server.
+func (c *Client) WithEndpoint(endpoint string) *Client {
+	return &Client{
+		Token:      c.Token,
+		BaseURL:    strings.TrimSuffix(endpoint, "/"),
+		Repo:       c.Repo,
+		HTTPClient: c.HTTPClient,
+	}
+}
+
+// WithRepo returns a new client configured to use a different repository
+// ("owner/name") with the same credentials and endpoint.
+func (c *Client) WithRepo(repo string) *Client {
+	return &Client{
+		Token:      c.Token,
+		BaseURL:    c.BaseURL,
+		Repo:       repo,
+		HTTPClient: c.HTTPClient,
+	}
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// repoPath returns the API path prefix for the client's repository.
+func (c *Client) repoPath() string {
+	return "/repos/" + c.Repo
+}
+
+// buildURL constructs a full API URL from path and optional query parameters.
+func (c *Client) buildURL(path string, params map[string]string) string {
+	u := c.BaseURL + path
+
+	if len(params) > 0 {
+		values := url.Values{}
+		for k, v := range params {
+			values.Set(k, v)
+		}
+		u += "?" + values.Encode()
+	}
+
+	return u
+}
+
+// doRequest performs an HTTP request with authentication and retry logic.
+func (c *Client) doRequest(ctx context.Context, method, urlStr string, body interface{}) ([]byte, error) {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", APIVersion)
+		if jsonBody != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		// Limit response body to 50MB to prevent OOM from malformed responses.
+		const maxResponseSize = 50 * 1024 * 1024
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		// GitHub signals rate limiting with 429, or 403 with no remaining quota
+		rateLimited := resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0")
+		if rateLimited {
+			delay := RetryDelay * time.Duration(1<<attempt)
+			lastErr = fmt.Errorf("rate limited (attempt %d/%d)", attempt+1, MaxRetries+1)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+				continue
+			}
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(respBody)}
+		}
+
+		return respBody, nil
+	}
+
+	return nil, fmt.Errorf("max retries (%d) exceeded: %w", MaxRetries+1, lastErr)
+}
+
+// apiErrorMessage extracts the "message" field from a GitHub error body,
+// falling back to the raw body.
+func apiErrorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// GetWorkflowRun retrieves a single workflow run by ID.
+func (c *Client) GetWorkflowRun(ctx context.Context, runID int64) (*WorkflowRun, error) {
+	urlStr := c.buildURL(c.repoPath()+"/actions/runs/"+strconv.FormatInt(runID, 10), nil)
+	respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run %d: %w", runID, err)
+	}
+
+	var run WorkflowRun
+	if err := json.Unmarshal(respBody, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run response: %w", err)
+	}
+	return &run, nil
+}
+
+// ListWorkflowRuns retrieves recent workflow runs, newest first. With
+// opts.Workflow set, only runs of that workflow are listed.
+func (c *Client) ListWorkflowRuns(ctx context.Context, opts RunListOptions) ([]WorkflowRun, error) {
+	limit := opts.Limit
+	if limit <= 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	params := map[string]string{"per_page": strconv.Itoa(limit)}
+	if opts.Branch != "" {
+		params["branch"] = opts.Branch
+	}
+	if opts.Status != "" {
+		params["status"] = opts.Status
+	}
+
+	path := c.repoPath() + "/actions/runs"
+	if opts.Workflow != "" {
+		path = c.repoPath() + "/actions/workflows/" + url.PathEscape(opts.Workflow) + "/runs"
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodGet, c.buildURL(path, params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+
+	var page workflowRunsPage
+	if err := json.Unmarshal(respBody, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow runs response: %w", err)
+	}
+	return page.WorkflowRuns, nil
+}
+
+// GetPullRequest retrieves a pull request by number.
+func (c *Client) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	urlStr := c.buildURL(c.repoPath()+"/pulls/"+strconv.Itoa(number), nil)
+	respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", number, err)
+	}
+
+	var pr PullRequest
+	if err := json.Unmarshal(respBody, &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", err)
+	}
+	return &pr, nil
+}
+
+// ListIssues retrieves the repository's issues, oldest update first, following
+// pagination. Pull requests returned by the issues API are dropped.
+func (c *Client) ListIssues(ctx context.Context, opts IssueListOptions) ([]Issue, error) {
+	state := opts.State
+	if state == "" {
+		state = "all"
+	}
+
+	var allIssues []Issue
+	for page := 1; ; page++ {
+		if page > MaxPages {
+			return nil, fmt.Errorf("pagination limit exceeded: stopped after %d pages", MaxPages)
+		}
+
+		params := map[string]string{
+			"state":     state,
+			"sort":      "updated",
+			"direction": "asc",
+			"per_page":  strconv.Itoa(MaxPageSize),
+			"page":      strconv.Itoa(page),
+		}
+		if !opts.Since.IsZero() {
+			params["since"] = opts.Since.UTC().Format(time.RFC3339)
+		}
+
+		respBody, err := c.doRequest(ctx, http.MethodGet, c.buildURL(c.repoPath()+"/issues", params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		var issues []Issue
+		if err := json.Unmarshal(respBody, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse issues response: %w", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				allIssues = append(allIssues, issue)
+			}
+		}
+
+		// A short page is the last one
+		if len(issues) < MaxPageSize {
+			return allIssues, nil
+		}
+	}
+}
+
+// GetIssue retrieves an issue by number.
+func (c *Client) GetIssue(ctx context.Context, number int) (*Issue, error) {
+	urlStr := c.buildURL(c.repoPath()+"/issues/"+strconv.Itoa(number), nil)
+	respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d: %w", number, err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", err)
+	}
+	return &issue, nil
+}
+
+// CreateIssue creates an issue from the given fields (title, body, labels,
+// assignees, milestone).
+func (c *Client) CreateIssue(ctx context.Context, fields map[string]interface{}) (*Issue, error) {
+	urlStr := c.buildURL(c.repoPath()+"/issues", nil)
+	respBody, err := c.doRequest(ctx, http.MethodPost, urlStr, fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue updates the given fields of an issue.
+func (c *Client) UpdateIssue(ctx context.Context, number int, fields map[string]interface{}) (*Issue, error) {
+	urlStr := c.buildURL(c.repoPath()+"/issues/"+strconv.Itoa(number), nil)
+	respBody, err := c.doRequest(ctx, http.MethodPatch, urlStr, fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update issue #%d: %w", number, err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", err)
+	}
+	return &issue, nil
+}
+
+// ListSubIssues retrieves the sub-issues of an issue.
+func (c *Client) ListSubIssues(ctx context.Context, number int) ([]Issue, error) {
+	var allIssues []Issue
+	for page := 1; ; page++ {
+		if page > MaxPages {
+			return nil, fmt.Errorf("pagination limit exceeded: stopped after %d pages", MaxPages)
+		}
+
+		params := map[string]string{
+			"per_page": strconv.Itoa(MaxPageSize),
+			"page":     strconv.Itoa(page),
+		}
+		urlStr := c.buildURL(c.repoPath()+"/issues/"+strconv.Itoa(number)+"/sub_issues", params)
+		respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sub-issues of #%d: %w", number, err)
+		}
+
+		var issues []Issue
+		if err := json.Unmarshal(respBody, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse sub-issues response: %w", err)
+		}
+		allIssues = append(allIssues, issues...)
+
+		if len(issues) < MaxPageSize {
+			return allIssues, nil
+		}
+	}
+}
+
+// ListMilestones retrieves all milestones of the repository, open and closed.
+func (c *Client) ListMilestones(ctx context.Context) ([]Milestone, error) {
+	var allMilestones []Milestone
+	for page := 1; ; page++ {
+		if page > MaxPages {
+			return nil, fmt.Errorf("pagination limit exceeded: stopped after %d pages", MaxPages)
+		}
+
+		params := map[string]string{
+			"state":    "all",
+			"per_page": strconv.Itoa(MaxPageSize),
+			"page":     strconv.Itoa(page),
+		}
+		respBody, err := c.doRequest(ctx, http.MethodGet, c.buildURL(c.repoPath()+"/milestones", params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list milestones: %w", err)
+		}
+
+		var milestones []Milestone
+		if err := json.Unmarshal(respBody, &milestones); err != nil {
+			return nil, fmt.Errorf("failed to parse milestones response: %w", err)
+		}
+		allMilestones = append(allMilestones, milestones...)
+
+		if len(milestones) < MaxPageSize {
+			return allMilestones, nil
+		}
+	}
+}
+
+// RepoFromRemoteURL extracts "owner/name" from a git remote URL such as
+// git@github.com:owner/name.git or https://ghe.example.com/owner/name.
+func RepoFromRemoteURL(remote string) (string, bool) {
+	remote = strings.TrimSpace(remote)
+	var path string
+	switch {
+	case strings.Contains(remote, "://"):
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", false
+		}
+		path = u.Path
+	case strings.Contains(remote, ":"):
+		// scp-like syntax: [user@]host:owner/name.git
+		path = remote[strings.Index(remote, ":")+1:]
+	default:
+		return "", false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestNewClient verifies the constructor defaults.
+func TestNewClient(t *testing.T) {
+	client := NewClient("test-token", "", "acme/widgets")
+	if client.BaseURL != DefaultBaseURL {
+		t.Errorf("BaseURL = %q, want %q", client.BaseURL, DefaultBaseURL)
+	}
+	if client.HTTPClient == nil {
+		t.Error("HTTPClient is nil, want non-nil default client")
+	}
+
+	ghes := NewClient("test-token", "https://ghe.example.com/api/v3/", "acme/widgets")
+	if ghes.BaseURL != "https://ghe.example.com/api/v3" {
+		t.Errorf("GHES BaseURL = %q", ghes.BaseURL)
+	}
+}
+
+func TestGetWorkflowRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/actions/runs/42" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.Header.Get("X-GitHub-Api-Version"); got != APIVersion {
+			t.Errorf("X-GitHub-Api-Version = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"id": 42, "name": "CI", "path": ".github/workflows/ci.yml",
+			"status": "completed", "conclusion": "success", "head_branch": "main"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", server.URL, "acme/widgets")
+	run, err := client.GetWorkflowRun(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetWorkflowRun: %v", err)
+	}
+	if run.ID != 42 || run.Name != "CI" || run.Status != "completed" || run.Conclusion != "success" {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestGetWorkflowRun_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("t", server.URL, "acme/widgets").GetWorkflowRun(context.Background(), 1)
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+}
+
+func TestListWorkflowRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/actions/workflows/release.yml/runs" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("branch") != "main" || r.URL.Query().Get("per_page") != "5" {
+			t.Errorf("query = %q", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"total_count": 2, "workflow_runs": [{"id": 2}, {"id": 1}]}`))
+	}))
+	defer server.Close()
+
+	runs, err := NewClient("t", server.URL, "acme/widgets").ListWorkflowRuns(context.Background(),
+		RunListOptions{Workflow: "release.yml", Branch: "main", Limit: 5})
+	if err != nil {
+		t.Fatalf("ListWorkflowRuns: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != 2 {
+		t.Errorf("runs = %+v", runs)
+	}
+}
+
+func TestGetPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/pulls/7" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"number": 7, "title": "Add gates", "state": "closed", "merged": true}`))
+	}))
+	defer server.Close()
+
+	pr, err := NewClient("t", server.URL, "acme/widgets").GetPullRequest(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if pr.Number != 7 || pr.State != "closed" || !pr.Merged {
+		t.Errorf("pr = %+v", pr)
+	}
+}
+
+func TestDoRequest_RetriesRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"number": 1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("t", server.URL, "acme/widgets")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.GetPullRequest(ctx, 1); err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestRepoFromRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+		ok     bool
+	}{
+		{"git@github.com:acme/widgets.git", "acme/widgets", true},
+		{"https://github.com/acme/widgets", "acme/widgets", true},
+		{"https://ghe.example.com/acme/widgets.git/", "acme/widgets", true},
+		{"ssh://git@ghe.example.com:2222/acme/widgets.git", "acme/widgets", true},
+		{"/srv/git/widgets.git", "", false},
+		{"https://gitlab.com/group/sub/widgets", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RepoFromRemoteURL(tt.remote)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RepoFromRemoteURL(%q) = (%q, %v), want (%q, %v)", tt.remote, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("state") != "open" || q.Get("since") != "2025-01-02T03:04:05Z" {
+			t.Errorf("query = %q", r.URL.RawQuery)
+		}
+		pages = append(pages, q.Get("page"))
+		if q.Get("page") == "1" {
+			// A full page: 99 issues and one pull request
+			var items []string
+			for i := 1; i < MaxPageSize; i++ {
+				items = append(items, `{"number": `+strconv.Itoa(i)+`}`)
+			}
+			items = append(items, `{"number": 100, "pull_request": {"url": "x"}}`)
+			_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"number": 101, "sub_issues_summary": {"total": 2, "completed": 1}}]`))
+	}))
+	defer server.Close()
+
+	since := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	issues, err := NewClient("t", server.URL, "acme/widgets").ListIssues(context.Background(),
+		IssueListOptions{State: "open", Since: since})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("pages = %v, want 2 requests", pages)
+	}
+	if len(issues) != 100 {
+		t.Fatalf("len(issues) = %d, want 100 (pull request dropped)", len(issues))
+	}
+	last := issues[len(issues)-1]
+	if last.Number != 101 || last.SubIssuesSummary == nil || last.SubIssuesSummary.Total != 2 {
+		t.Errorf("last = %+v", last)
+	}
+}
+
+func TestCreateAndUpdateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues":
+			if !strings.Contains(string(body), `"title":"New"`) {
+				t.Errorf("create body = %s", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 5, "html_url": "https://github.com/acme/widgets/issues/5"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/widgets/issues/5":
+			if !strings.Contains(string(body), `"state":"closed"`) {
+				t.Errorf("update body = %s", body)
+			}
+			_, _ = w.Write([]byte(`{"number": 5, "state": "closed"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("t", server.URL, "acme/widgets")
+	created, err := client.CreateIssue(context.Background(), map[string]interface{}{"title": "New"})
+	if err != nil || created.Number != 5 {
+		t.Fatalf("CreateIssue = %+v, %v", created, err)
+	}
+	updated, err := client.UpdateIssue(context.Background(), 5, map[string]interface{}{"state": "closed"})
+	if err != nil || updated.State != "closed" {
+		t.Fatalf("UpdateIssue = %+v, %v", updated, err)
+	}
+}
+
+func TestListSubIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues/10/sub_issues" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[{"number": 11}, {"number": 12}]`))
+	}))
+	defer server.Close()
+
+	subs, err := NewClient("t", server.URL, "acme/widgets").ListSubIssues(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ListSubIssues: %v", err)
+	}
+	if len(subs) != 2 || subs[0].Number != 11 {
+		t.Errorf("subs = %+v", subs)
+	}
+}
@@ -0,0 +1,417 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// MilestoneLabelPrefix marks the beads label that carries an issue's GitHub
+// milestone, e.g. "milestone:v1.2".
+const MilestoneLabelPrefix = "milestone:"
+
+// MappingConfig holds configurable mappings between GitHub labels and Beads
+// fields. All maps use lowercase label names as keys for case-insensitive matching.
+// Labels consumed by a mapping are not copied to the beads issue.
+type MappingConfig struct {
+	// PriorityMap maps label names to Beads priority (0-4).
+	PriorityMap map[string]int
+
+	// LabelTypeMap maps label names to Beads issue types.
+	LabelTypeMap map[string]string
+
+	// StatusMap maps label names to Beads statuses for open issues.
+	// Closed issues are always "closed".
+	StatusMap map[string]string
+}
+
+// DefaultMappingConfig returns sensible default mappings. The label names
+// follow common GitHub conventions and match examples/github-import.
+func DefaultMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		PriorityMap: map[string]int{
+			"p0":       0,
+			"critical": 0,
+			"urgent":   0,
+			"p1":       1,
+			"high":     1,
+			"p2":       2,
+			"medium":   2,
+			"p3":       3,
+			"low":      3,
+			"p4":       4,
+			"backlog":  4,
+		},
+		LabelTypeMap: map[string]string{
+			"bug":         "bug",
+			"defect":      "bug",
+			"feature":     "feature",
+			"enhancement": "feature",
+			"epic":        "epic",
+			"chore":       "chore",
+			"maintenance": "chore",
+			"task":        "task",
+		},
+		StatusMap: map[string]string{
+			"in progress": "in_progress",
+			"in-progress": "in_progress",
+			"wip":         "in_progress",
+			"blocked":     "blocked",
+		},
+	}
+}
+
+// ConfigLoader is an interface for loading configuration values.
+// This allows the mapping package to be decoupled from the storage layer.
+type ConfigLoader interface {
+	GetAllConfig() (map[string]string, error)
+}
+
+// LoadMappingConfig loads mapping configuration from a config loader.
+// Config keys follow the pattern: github.<category>_map.<label> = <value>
+// Examples:
+//
+//	github.priority_map.sev1 = 0
+//	github.label_type_map.kind/bug = bug
+//	github.status_map.needs-info = blocked
+func LoadMappingConfig(loader ConfigLoader) *MappingConfig {
+	config := DefaultMappingConfig()
+
+	if loader == nil {
+		return config
+	}
+
+	allConfig, err := loader.GetAllConfig()
+	if err != nil {
+		return config
+	}
+
+	for key, value := range allConfig {
+		switch {
+		case strings.HasPrefix(key, "github.priority_map."):
+			label := strings.ToLower(strings.TrimPrefix(key, "github.priority_map."))
+			if priority, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				config.PriorityMap[label] = priority
+			}
+		case strings.HasPrefix(key, "github.label_type_map."):
+			label := strings.ToLower(strings.TrimPrefix(key, "github.label_type_map."))
+			config.LabelTypeMap[label] = value
+		case strings.HasPrefix(key, "github.status_map."):
+			label := strings.ToLower(strings.TrimPrefix(key, "github.status_map."))
+			config.StatusMap[label] = value
+		}
+	}
+
+	return config
+}
+
+// IssueToBeads converts a GitHub issue to a Beads issue. Labels that map to
+// priority, type or status are consumed; the rest are kept, and the milestone
+// becomes a "milestone:<title>" label. Sub-issue links are not part of the
+// issue payload; see SubIssueDependencies.
+func IssueToBeads(gi *Issue, config *MappingConfig) *IssueConversion {
+	issue := &types.Issue{
+		Title:       gi.Title,
+		Description: gi.Body,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		Status:      types.StatusOpen,
+		CreatedAt:   gi.CreatedAt,
+		UpdatedAt:   gi.UpdatedAt,
+	}
+
+	var priorityFound, typeFound, statusFound bool
+	for _, label := range gi.Labels {
+		key := strings.ToLower(label.Name)
+		if priority, ok := config.PriorityMap[key]; ok && !priorityFound {
+			issue.Priority = priority
+			priorityFound = true
+			continue
+		}
+		if issueType, ok := config.LabelTypeMap[key]; ok && !typeFound {
+			issue.IssueType = types.IssueType(issueType)
+			typeFound = true
+			continue
+		}
+		if status, ok := config.StatusMap[key]; ok && !statusFound {
+			issue.Status = types.Status(status)
+			statusFound = true
+			continue
+		}
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+
+	if gi.Milestone != nil && gi.Milestone.Title != "" {
+		issue.Labels = append(issue.Labels, MilestoneLabelPrefix+gi.Milestone.Title)
+	}
+
+	if gi.State == "closed" {
+		issue.Status = types.StatusClosed
+		if gi.ClosedAt != nil {
+			closedAt := *gi.ClosedAt
+			issue.ClosedAt = &closedAt
+		} else {
+			closedAt := gi.UpdatedAt
+			issue.ClosedAt = &closedAt
+		}
+	}
+
+	issue.Assignee = issueAssignee(gi)
+
+	externalRef := gi.HTMLURL
+	if canonical, ok := CanonicalizeGitHubExternalRef(externalRef); ok {
+		externalRef = canonical
+	}
+	issue.ExternalRef = &externalRef
+
+	return &IssueConversion{Issue: issue}
+}
+
+// issueAssignee returns the login of the issue's first assignee.
+func issueAssignee(gi *Issue) string {
+	if gi.Assignee != nil {
+		return gi.Assignee.Login
+	}
+	if len(gi.Assignees) > 0 {
+		return gi.Assignees[0].Login
+	}
+	return ""
+}
+
+// SubIssueDependencies returns the parent-child edges for the sub-issues of
+// the given parent issue.
+func SubIssueDependencies(parentNumber int, subIssues []Issue) []DependencyInfo {
+	deps := make([]DependencyInfo, 0, len(subIssues))
+	for _, sub := range subIssues {
+		deps = append(deps, DependencyInfo{
+			FromNumber: sub.Number,
+			ToNumber:   parentNumber,
+			Type:       "parent-child",
+		})
+	}
+	return deps
+}
+
+// BuildGitHubBody formats a Beads issue for GitHub's body field.
+// This mirrors the payload used during push to keep hash comparisons consistent.
+func BuildGitHubBody(issue *types.Issue) string {
+	body := issue.Description
+	if issue.AcceptanceCriteria != "" {
+		body += "\n\n## Acceptance Criteria\n" + issue.AcceptanceCriteria
+	}
+	if issue.Design != "" {
+		body += "\n\n## Design\n" + issue.Design
+	}
+	if issue.Notes != "" {
+		body += "\n\n## Notes\n" + issue.Notes
+	}
+	return body
+}
+
+// BuildGitHubIssueFields converts a Beads issue to GitHub create/update fields.
+// Priority, type and status are written back as labels so a later pull maps
+// them the same way. milestones maps milestone titles to numbers; a
+// milestone label with no matching milestone leaves the milestone unchanged.
+func BuildGitHubIssueFields(issue *types.Issue, config *MappingConfig, milestones map[string]int) map[string]interface{} {
+	fields := map[string]interface{}{
+		"title": issue.Title,
+		"body":  BuildGitHubBody(issue),
+	}
+
+	labels := make([]string, 0, len(issue.Labels)+3)
+	if label := priorityLabel(issue.Priority, config); label != "" {
+		labels = append(labels, label)
+	}
+	if label := typeLabel(issue.IssueType, config); label != "" {
+		labels = append(labels, label)
+	}
+	if label := statusLabel(issue.Status, config); label != "" {
+		labels = append(labels, label)
+	}
+
+	milestoneSet := false
+	for _, label := range issue.Labels {
+		if title, ok := strings.CutPrefix(label, MilestoneLabelPrefix); ok {
+			if number, found := milestones[title]; found && !milestoneSet {
+				fields["milestone"] = number
+				milestoneSet = true
+			}
+			continue
+		}
+		labels = append(labels, label)
+	}
+	if !milestoneSet && !hasMilestoneLabel(issue.Labels) {
+		fields["milestone"] = nil
+	}
+	fields["labels"] = labels
+
+	assignees := []string{}
+	if issue.Assignee != "" {
+		assignees = append(assignees, issue.Assignee)
+	}
+	fields["assignees"] = assignees
+
+	if issue.Status == types.StatusClosed {
+		fields["state"] = "closed"
+	} else {
+		fields["state"] = "open"
+	}
+
+	return fields
+}
+
+// hasMilestoneLabel reports whether labels include a milestone label.
+func hasMilestoneLabel(labels []string) bool {
+	for _, label := range labels {
+		if strings.HasPrefix(label, MilestoneLabelPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// priorityLabel returns the label written for a Beads priority. Medium (2) is
+// the default on pull, so it gets no label.
+func priorityLabel(priority int, config *MappingConfig) string {
+	if priority == 2 {
+		return ""
+	}
+	label := fmt.Sprintf("P%d", priority)
+	if p, ok := config.PriorityMap[strings.ToLower(label)]; ok && p == priority {
+		return label
+	}
+	return ""
+}
+
+// typeLabel returns the label written for a Beads issue type. Tasks are the
+// default on pull, so they get no label, and types without a same-named
+// label mapping are not written.
+func typeLabel(issueType types.IssueType, config *MappingConfig) string {
+	if issueType == "" || issueType == types.TypeTask {
+		return ""
+	}
+	if t, ok := config.LabelTypeMap[string(issueType)]; ok && t == string(issueType) {
+		return string(issueType)
+	}
+	return ""
+}
+
+// statusLabels are the labels written for statuses GitHub has no state for.
+var statusLabels = map[types.Status]string{
+	types.StatusInProgress: "in progress",
+	types.StatusBlocked:    "blocked",
+}
+
+// statusLabel returns the label written for a Beads status, if any.
+func statusLabel(status types.Status, config *MappingConfig) string {
+	label, ok := statusLabels[status]
+	if !ok || config.StatusMap[label] != string(status) {
+		return ""
+	}
+	return label
+}
+
+// NormalizeIssueForGitHubHash returns a copy of the issue reduced to the
+// fields GitHub stores, in the shape a pull would produce, to avoid false conflicts.
+func NormalizeIssueForGitHubHash(issue *types.Issue, config *MappingConfig) *types.Issue {
+	normalized := &types.Issue{
+		Title:       issue.Title,
+		Description: BuildGitHubBody(issue),
+		Priority:    issue.Priority,
+		IssueType:   issue.IssueType,
+		Status:      issue.Status,
+		Assignee:    issue.Assignee,
+	}
+	if priorityLabel(issue.Priority, config) == "" {
+		normalized.Priority = 2
+	}
+	if typeLabel(issue.IssueType, config) == "" {
+		normalized.IssueType = types.TypeTask
+	}
+	if issue.Status != types.StatusClosed && statusLabel(issue.Status, config) == "" {
+		normalized.Status = types.StatusOpen
+	}
+	if issue.ExternalRef != nil {
+		externalRef := *issue.ExternalRef
+		if canonical, ok := CanonicalizeGitHubExternalRef(externalRef); ok {
+			externalRef = canonical
+		}
+		normalized.ExternalRef = &externalRef
+	}
+	normalized.Labels = append([]string(nil), issue.Labels...)
+	sort.Strings(normalized.Labels)
+	return normalized
+}
+
+// ContentHash returns a hash of the fields synced with GitHub, including
+// labels. Equal hashes mean a push or pull would change nothing.
+func ContentHash(issue *types.Issue, config *MappingConfig) string {
+	normalized := NormalizeIssueForGitHubHash(issue, config)
+	h := sha256.New()
+	h.Write([]byte(normalized.ComputeContentHash()))
+	for _, label := range normalized.Labels {
+		h.Write([]byte{0})
+		h.Write([]byte(label))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BuildGitHubToLocalUpdates creates an updates map from a GitHub issue to
+// apply to a local Beads issue. This is used when GitHub wins a conflict.
+// Labels are not included; apply IssueToBeads(gi).Issue.Labels separately.
+func BuildGitHubToLocalUpdates(gi *Issue, config *MappingConfig) map[string]interface{} {
+	converted := IssueToBeads(gi, config).Issue
+	return map[string]interface{}{
+		"title":       converted.Title,
+		"description": converted.Description,
+		"priority":    converted.Priority,
+		"issue_type":  string(converted.IssueType),
+		"status":      string(converted.Status),
+		"assignee":    converted.Assignee,
+	}
+}
+
+// ParseGitHubExternalRef extracts the repository ("owner/name") and issue
+// number from a GitHub issue URL such as
+// https://github.com/owner/name/issues/42#issuecomment-1.
+func ParseGitHubExternalRef(externalRef string) (repo string, number int, ok bool) {
+	parsed, err := url.Parse(externalRef)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", 0, false
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) != 4 || segments[2] != "issues" || segments[0] == "" || segments[1] == "" {
+		return "", 0, false
+	}
+	number, err = strconv.Atoi(segments[3])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return segments[0] + "/" + segments[1], number, true
+}
+
+// IsGitHubExternalRef checks if an external_ref URL is a GitHub issue URL.
+// GitLab issue URLs ("/-/issues/") don't match.
+func IsGitHubExternalRef(externalRef string) bool {
+	_, _, ok := ParseGitHubExternalRef(externalRef)
+	return ok
+}
+
+// CanonicalizeGitHubExternalRef returns a stable GitHub issue URL without
+// query, fragment or trailing slash.
+// Example: https://github.com/o/r/issues/42#issuecomment-1 -> https://github.com/o/r/issues/42
+// Returns ok=false if the URL isn't a recognizable GitHub issue URL.
+func CanonicalizeGitHubExternalRef(externalRef string) (canonical string, ok bool) {
+	repo, number, ok := ParseGitHubExternalRef(externalRef)
+	if !ok {
+		return "", false
+	}
+	parsed, _ := url.Parse(externalRef)
+	return fmt.Sprintf("%s://%s/%s/issues/%d", parsed.Scheme, parsed.Host, repo, number), true
+}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestIssueToBeads(t *testing.T) {
+	closedAt := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	gi := &Issue{
+		Number:    42,
+		Title:     "Crash on start",
+		Body:      "Stack trace attached",
+		State:     "closed",
+		Labels:    []Label{{Name: "P1"}, {Name: "Bug"}, {Name: "blocked"}, {Name: "ui"}},
+		Assignees: []User{{Login: "octocat"}},
+		Milestone: &Milestone{Number: 3, Title: "v1.2"},
+		HTMLURL:   "https://github.com/acme/widgets/issues/42",
+		CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: closedAt,
+		ClosedAt:  &closedAt,
+	}
+
+	issue := IssueToBeads(gi, DefaultMappingConfig()).Issue
+	if issue.Priority != 1 || issue.IssueType != types.TypeBug {
+		t.Errorf("priority/type = %d/%s, want 1/bug", issue.Priority, issue.IssueType)
+	}
+	if issue.Status != types.StatusClosed || issue.ClosedAt == nil || !issue.ClosedAt.Equal(closedAt) {
+		t.Errorf("status = %s, closed_at = %v", issue.Status, issue.ClosedAt)
+	}
+	if issue.Assignee != "octocat" {
+		t.Errorf("assignee = %q", issue.Assignee)
+	}
+	if want := []string{"ui", "milestone:v1.2"}; !slices.Equal(issue.Labels, want) {
+		t.Errorf("labels = %v, want %v", issue.Labels, want)
+	}
+	if issue.ExternalRef == nil || *issue.ExternalRef != gi.HTMLURL {
+		t.Errorf("external_ref = %v", issue.ExternalRef)
+	}
+
+	// Open issues take their status from labels; unlabeled issues get defaults
+	gi.State = "open"
+	issue = IssueToBeads(gi, DefaultMappingConfig()).Issue
+	if issue.Status != types.StatusBlocked {
+		t.Errorf("open status = %s, want blocked", issue.Status)
+	}
+	plain := IssueToBeads(&Issue{Title: "Plain", State: "open"}, DefaultMappingConfig()).Issue
+	if plain.Priority != 2 || plain.IssueType != types.TypeTask || plain.Status != types.StatusOpen {
+		t.Errorf("defaults = %d/%s/%s", plain.Priority, plain.IssueType, plain.Status)
+	}
+}
+
+func TestBuildGitHubIssueFields_RoundTrip(t *testing.T) {
+	config := DefaultMappingConfig()
+	externalRef := "https://github.com/acme/widgets/issues/7"
+	local := &types.Issue{
+		Title:              "Add export",
+		Description:        "Export to CSV",
+		AcceptanceCriteria: "CSV opens in a spreadsheet",
+		Priority:           0,
+		IssueType:          types.TypeFeature,
+		Status:             types.StatusInProgress,
+		Assignee:           "octocat",
+		Labels:             []string{"api", "milestone:v2"},
+		ExternalRef:        &externalRef,
+	}
+
+	fields := BuildGitHubIssueFields(local, config, map[string]int{"v2": 5})
+	labels, _ := fields["labels"].([]string)
+	if want := []string{"P0", "feature", "in progress", "api"}; !slices.Equal(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+	if fields["milestone"] != 5 || fields["state"] != "open" {
+		t.Errorf("milestone/state = %v/%v", fields["milestone"], fields["state"])
+	}
+	if assignees, _ := fields["assignees"].([]string); !slices.Equal(assignees, []string{"octocat"}) {
+		t.Errorf("assignees = %v", fields["assignees"])
+	}
+
+	// Pulling the pushed issue back must not register as a change
+	remote := &Issue{
+		Title:     fields["title"].(string),
+		Body:      fields["body"].(string),
+		State:     "open",
+		Assignee:  &User{Login: "octocat"},
+		Milestone: &Milestone{Number: 5, Title: "v2"},
+		HTMLURL:   externalRef,
+	}
+	for _, name := range labels {
+		remote.Labels = append(remote.Labels, Label{Name: name})
+	}
+	pulled := IssueToBeads(remote, config).Issue
+	if ContentHash(local, config) != ContentHash(pulled, config) {
+		t.Errorf("round trip changed the issue:\nlocal  %+v\npulled %+v",
+			NormalizeIssueForGitHubHash(local, config), NormalizeIssueForGitHubHash(pulled, config))
+	}
+
+	// A label change is a content change
+	pulled.Labels = append(pulled.Labels, "extra")
+	if ContentHash(local, config) == ContentHash(pulled, config) {
+		t.Error("ContentHash ignores labels")
+	}
+}
+
+func TestBuildGitHubIssueFields_Milestone(t *testing.T) {
+	config := DefaultMappingConfig()
+
+	// No milestone label clears the milestone
+	fields := BuildGitHubIssueFields(&types.Issue{Title: "x"}, config, nil)
+	if v, ok := fields["milestone"]; !ok || v != nil {
+		t.Errorf("milestone = %v, %v; want explicit nil", v, ok)
+	}
+
+	// An unknown milestone leaves it unchanged
+	fields = BuildGitHubIssueFields(&types.Issue{Title: "x", Labels: []string{"milestone:nope"}}, config, nil)
+	if _, ok := fields["milestone"]; ok {
+		t.Errorf("milestone set for unknown title: %v", fields["milestone"])
+	}
+}
+
+func TestSubIssueDependencies(t *testing.T) {
+	deps := SubIssueDependencies(10, []Issue{{Number: 11}, {Number: 12}})
+	want := []DependencyInfo{
+		{FromNumber: 11, ToNumber: 10, Type: "parent-child"},
+		{FromNumber: 12, ToNumber: 10, Type: "parent-child"},
+	}
+	if !slices.Equal(deps, want) {
+		t.Errorf("deps = %+v", deps)
+	}
+}
+
+func TestLoadMappingConfig(t *testing.T) {
+	config := LoadMappingConfig(mapLoader{
+		"github.priority_map.Sev1":        "0",
+		"github.label_type_map.kind/bug":  "bug",
+		"github.status_map.needs-info":    "blocked",
+		"github.priority_map.not-a-level": "high",
+	})
+	if config.PriorityMap["sev1"] != 0 || config.LabelTypeMap["kind/bug"] != "bug" || config.StatusMap["needs-info"] != "blocked" {
+		t.Errorf("config = %+v", config)
+	}
+	if _, ok := config.PriorityMap["not-a-level"]; ok {
+		t.Error("non-numeric priority was loaded")
+	}
+	if config.PriorityMap["p3"] != 3 {
+		t.Error("defaults lost")
+	}
+}
+
+type mapLoader map[string]string
+
+func (m mapLoader) GetAllConfig() (map[string]string, error) { return m, nil }
+
+func TestParseGitHubExternalRef(t *testing.T) {
+	tests := []struct {
+		ref       string
+		repo      string
+		number    int
+		ok        bool
+		canonical string
+	}{
+		{"https://github.com/acme/widgets/issues/42", "acme/widgets", 42, true, "https://github.com/acme/widgets/issues/42"},
+		{"https://github.com/acme/widgets/issues/42/#issuecomment-1", "acme/widgets", 42, true, "https://github.com/acme/widgets/issues/42"},
+		{"https://ghe.example.com/acme/widgets/issues/7?x=1", "acme/widgets", 7, true, "https://ghe.example.com/acme/widgets/issues/7"},
+		{"https://github.com/acme/widgets/pull/42", "", 0, false, ""},
+		{"https://gitlab.com/acme/widgets/-/issues/42", "", 0, false, ""},
+		{"https://linear.app/team/issue/TEAM-1", "", 0, false, ""},
+		{"gh-42", "", 0, false, ""},
+	}
+	for _, tt := range tests {
+		repo, number, ok := ParseGitHubExternalRef(tt.ref)
+		if repo != tt.repo || number != tt.number || ok != tt.ok {
+			t.Errorf("ParseGitHubExternalRef(%q) = (%q, %d, %v)", tt.ref, repo, number, ok)
+		}
+		canonical, _ := CanonicalizeGitHubExternalRef(tt.ref)
+		if canonical != tt.canonical {
+			t.Errorf("CanonicalizeGitHubExternalRef(%q) = %q, want %q", tt.ref, canonical, tt.canonical)
+		}
+	}
+}
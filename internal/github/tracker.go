@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func init() {
+	tracker.Register("github", newTracker)
+}
+
+// Tracker adapts a GitHub repository's issues to tracker.Tracker.
+type Tracker struct {
+	client     *Client
+	config     *MappingConfig
+	milestones map[string]int // Loaded on first push that needs them
+}
+
+// NewTracker creates a tracker adapter for a GitHub repository.
+func NewTracker(client *Client, config *MappingConfig) *Tracker {
+	return &Tracker{client: client, config: config}
+}
+
+// newTracker creates the adapter from github.* config, falling back to
+// GITHUB_TOKEN (or GH_TOKEN), GITHUB_REPOSITORY and GITHUB_API_URL. It does
+// not infer the repository from the git remote; callers that can do so
+// supply github.repo through cfg.
+func newTracker(ctx context.Context, cfg tracker.Config) (tracker.Tracker, error) {
+	token := tracker.ConfigValue(ctx, cfg, "github.token", "GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	repo := tracker.ConfigValue(ctx, cfg, "github.repo", "GITHUB_REPOSITORY")
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("GitHub token not configured (github.token or GITHUB_TOKEN)")
+	case strings.Count(repo, "/") != 1:
+		return nil, fmt.Errorf("github.repo not configured (expected owner/name, got %q)", repo)
+	}
+	baseURL := tracker.ConfigValue(ctx, cfg, "github.url", "GITHUB_API_URL")
+	client := NewClient(token, baseURL, repo)
+	return NewTracker(client, LoadMappingConfig(tracker.MappingLoader(ctx, cfg))), nil
+}
+
+// Name returns "github".
+func (t *Tracker) Name() string { return "github" }
+
+// DisplayName returns "GitHub".
+func (t *Tracker) DisplayName() string { return "GitHub" }
+
+// Mapper returns the configured label mappings.
+func (t *Tracker) Mapper() tracker.FieldMapper { return fieldMapper{t.config} }
+
+// CanonicalRef accepts issue URLs in the configured repository.
+func (t *Tracker) CanonicalRef(ref string) (string, bool) {
+	repo, _, ok := ParseGitHubExternalRef(ref)
+	if !ok || !strings.EqualFold(repo, t.client.Repo) {
+		return "", false
+	}
+	return CanonicalizeGitHubExternalRef(ref)
+}
+
+// FetchIssues lists the repository's issues. Sub-issues of returned issues
+// become parent-child dependencies.
+func (t *Tracker) FetchIssues(ctx context.Context, opts tracker.FetchOptions) ([]*tracker.RemoteIssue, error) {
+	issues, err := t.client.ListIssues(ctx, IssueListOptions{State: opts.State, Since: opts.Since})
+	if err != nil {
+		return nil, err
+	}
+	remotes := make([]*tracker.RemoteIssue, 0, len(issues))
+	for i := range issues {
+		gi := &issues[i]
+		remote := t.remote(gi)
+		if gi.SubIssuesSummary != nil && gi.SubIssuesSummary.Total > 0 {
+			subIssues, err := t.client.ListSubIssues(ctx, gi.Number)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch sub-issues of #%d: %w", gi.Number, err)
+			}
+			for _, sub := range subIssues {
+				if ref, ok := CanonicalizeGitHubExternalRef(sub.HTMLURL); ok {
+					remote.Dependencies = append(remote.Dependencies, tracker.Dependency{
+						FromRef: ref,
+						ToRef:   remote.Ref,
+						Type:    types.DepParentChild,
+					})
+				}
+			}
+		}
+		remotes = append(remotes, remote)
+	}
+	return remotes, nil
+}
+
+// CreateIssue creates a GitHub issue, closing it afterwards if needed since
+// issues are always created open.
+func (t *Tracker) CreateIssue(ctx context.Context, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	fields, err := t.fields(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "state")
+	if fields["milestone"] == nil {
+		delete(fields, "milestone")
+	}
+	gi, err := t.client.CreateIssue(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Status == types.StatusClosed {
+		if gi, err = t.client.UpdateIssue(ctx, gi.Number, map[string]interface{}{"state": "closed"}); err != nil {
+			return nil, err
+		}
+	}
+	return t.remote(gi), nil
+}
+
+// UpdateIssue overwrites a GitHub issue's fields, labels and state.
+func (t *Tracker) UpdateIssue(ctx context.Context, ref string, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	_, number, ok := ParseGitHubExternalRef(ref)
+	if !ok {
+		return nil, fmt.Errorf("not a GitHub issue URL: %s", ref)
+	}
+	fields, err := t.fields(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+	gi, err := t.client.UpdateIssue(ctx, number, fields)
+	if err != nil {
+		return nil, err
+	}
+	return t.remote(gi), nil
+}
+
+// fields builds the issue payload, loading milestones the first time an
+// issue carries a milestone label.
+func (t *Tracker) fields(ctx context.Context, issue *types.Issue) (map[string]interface{}, error) {
+	if t.milestones == nil && hasMilestoneLabel(issue.Labels) {
+		list, err := t.client.ListMilestones(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch milestones: %w", err)
+		}
+		t.milestones = make(map[string]int, len(list))
+		for _, m := range list {
+			t.milestones[m.Title] = m.Number
+		}
+	}
+	return BuildGitHubIssueFields(issue, t.config, t.milestones), nil
+}
+
+// remote converts a GitHub issue for the sync engine.
+func (t *Tracker) remote(gi *Issue) *tracker.RemoteIssue {
+	issue := IssueToBeads(gi, t.config).Issue
+	return &tracker.RemoteIssue{
+		Ref:   *issue.ExternalRef,
+		Key:   fmt.Sprintf("#%d", gi.Number),
+		Issue: issue,
+	}
+}
+
+// fieldMapper maps fields to the labels (and open/closed state) GitHub
+// carries them in. An empty label is the pull default.
+type fieldMapper struct {
+	config *MappingConfig
+}
+
+func (m fieldMapper) StatusToTracker(status types.Status) string {
+	if status == types.StatusClosed {
+		return "closed"
+	}
+	if label := statusLabel(status, m.config); label != "" {
+		return label
+	}
+	return "open"
+}
+
+func (m fieldMapper) StatusToBeads(status string) types.Status {
+	switch status {
+	case "closed":
+		return types.StatusClosed
+	case "open", "":
+		return types.StatusOpen
+	}
+	if s, ok := m.config.StatusMap[strings.ToLower(status)]; ok {
+		return types.Status(s)
+	}
+	return types.StatusOpen
+}
+
+func (m fieldMapper) PriorityToTracker(priority int) string {
+	return priorityLabel(priority, m.config)
+}
+
+func (m fieldMapper) PriorityToBeads(priority string) int {
+	if p, ok := m.config.PriorityMap[strings.ToLower(priority)]; ok {
+		return p
+	}
+	return 2
+}
+
+func (m fieldMapper) TypeToTracker(issueType types.IssueType) string {
+	return typeLabel(issueType, m.config)
+}
+
+func (m fieldMapper) TypeToBeads(issueType string) types.IssueType {
+	if t, ok := m.config.LabelTypeMap[strings.ToLower(issueType)]; ok {
+		return types.IssueType(t)
+	}
+	return types.TypeTask
+}
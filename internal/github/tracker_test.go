@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// mapConfig is an in-memory tracker.Config.
+type mapConfig map[string]string
+
+func (m mapConfig) GetConfig(_ context.Context, key string) (string, error) { return m[key], nil }
+func (m mapConfig) GetAllConfig(_ context.Context) (map[string]string, error) {
+	return m, nil
+}
+
+func TestTrackerRegistered(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	ctx := context.Background()
+
+	if _, err := tracker.New(ctx, "github", mapConfig{"github.token": "t"}); err == nil {
+		t.Error("expected an error without github.repo")
+	}
+	trk, err := tracker.New(ctx, "github", mapConfig{"github.token": "t", "github.repo": "acme/widgets"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if trk.Name() != "github" {
+		t.Errorf("Name() = %q", trk.Name())
+	}
+}
+
+func TestTrackerCanonicalRef(t *testing.T) {
+	trk := NewTracker(NewClient("t", "", "acme/widgets"), DefaultMappingConfig())
+
+	tests := []struct {
+		ref  string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/acme/widgets/issues/7", "https://github.com/acme/widgets/issues/7", true},
+		{"https://github.com/Acme/Widgets/issues/7#issuecomment-1", "https://github.com/Acme/Widgets/issues/7", true},
+		{"https://github.com/acme/other/issues/7", "", false},
+		{"https://acme.atlassian.net/browse/PROJ-7", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := trk.CanonicalRef(tt.ref)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CanonicalRef(%q) = %q, %v; want %q, %v", tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTrackerFetchIssues_SubIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/widgets/issues":
+			_, _ = w.Write([]byte(`[{"number": 10, "title": "Epic", "state": "open",
+				"html_url": "https://github.com/acme/widgets/issues/10",
+				"labels": [{"name": "epic"}, {"name": "P1"}],
+				"sub_issues_summary": {"total": 1}}]`))
+		case "/repos/acme/widgets/issues/10/sub_issues":
+			_, _ = w.Write([]byte(`[{"number": 11, "html_url": "https://github.com/acme/widgets/issues/11"}]`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	trk := NewTracker(NewClient("t", server.URL, "acme/widgets"), DefaultMappingConfig())
+	remotes, err := trk.FetchIssues(context.Background(), tracker.FetchOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("FetchIssues: %v", err)
+	}
+	if len(remotes) != 1 {
+		t.Fatalf("len(remotes) = %d, want 1", len(remotes))
+	}
+	remote := remotes[0]
+	if remote.Key != "#10" || remote.Issue.IssueType != types.TypeEpic || remote.Issue.Priority != 1 {
+		t.Errorf("remote = %+v, issue = %+v", remote, remote.Issue)
+	}
+	want := tracker.Dependency{
+		FromRef: "https://github.com/acme/widgets/issues/11",
+		ToRef:   "https://github.com/acme/widgets/issues/10",
+		Type:    types.DepParentChild,
+	}
+	if len(remote.Dependencies) != 1 || remote.Dependencies[0] != want {
+		t.Errorf("Dependencies = %+v, want %+v", remote.Dependencies, want)
+	}
+}
+
+func TestTrackerCreateIssue_Closed(t *testing.T) {
+	var closed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost:
+			if strings.Contains(string(body), `"state"`) {
+				t.Errorf("create body has state: %s", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 5, "state": "open", "html_url": "https://github.com/acme/widgets/issues/5"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/widgets/issues/5":
+			closed = strings.Contains(string(body), `"state":"closed"`)
+			_, _ = w.Write([]byte(`{"number": 5, "state": "closed", "html_url": "https://github.com/acme/widgets/issues/5"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	trk := NewTracker(NewClient("t", server.URL, "acme/widgets"), DefaultMappingConfig())
+	remote, err := trk.CreateIssue(context.Background(), &types.Issue{
+		Title: "Done already", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask,
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if !closed {
+		t.Error("issue was not closed after creation")
+	}
+	if remote.Ref != "https://github.com/acme/widgets/issues/5" || remote.Issue.Status != types.StatusClosed {
+		t.Errorf("remote = %+v, issue = %+v", remote, remote.Issue)
+	}
+}
+
+func TestTrackerMapperRoundTrip(t *testing.T) {
+	m := NewTracker(NewClient("t", "", "acme/widgets"), DefaultMappingConfig()).Mapper()
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed} {
+		if got := m.StatusToBeads(m.StatusToTracker(status)); got != status {
+			t.Errorf("status %q round-trips to %q", status, got)
+		}
+	}
+	for p := 0; p <= 4; p++ {
+		if got := m.PriorityToBeads(m.PriorityToTracker(p)); got != p {
+			t.Errorf("priority %d round-trips to %d", p, got)
+		}
+	}
+	for _, it := range []types.IssueType{types.TypeBug, types.TypeFeature, types.TypeEpic, types.TypeChore, types.TypeTask} {
+		if got := m.TypeToBeads(m.TypeToTracker(it)); got != it {
+			t.Errorf("type %q round-trips to %q", it, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package github provides client and data types for the GitHub REST API.
+//
+// The client covers the parts of the API that beads uses: workflow runs and
+// pull requests for gh:run and gh:pr gates, and issues, milestones and
+// sub-issues for fbd github sync. It works against github.com and GitHub
+// Enterprise Server (GHES) through a configurable base URL.
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// API configuration constants.
+const (
+	// DefaultBaseURL is the REST API root for github.com.
+	// GHES instances serve the API under https://<host>/api/v3.
+	DefaultBaseURL = "https://api.github.com"
+
+	// APIVersion is the REST API version requested via X-GitHub-Api-Version.
+	APIVersion = "2022-11-28"
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// MaxRetries is the maximum number of retries for rate-limited requests.
+	MaxRetries = 3
+
+	// RetryDelay is the base delay between retries (exponential backoff).
+	RetryDelay = time.Second
+
+	// MaxPageSize is the maximum number of items to fetch per page.
+	MaxPageSize = 100
+
+	// MaxPages is the maximum number of pages to fetch before stopping pagination.
+	// This prevents infinite loops from malformed API responses.
+	MaxPages = 1000
+)
+
+// Client provides methods to interact with the GitHub REST API.
+type Client struct {
+	Token      string       // Personal access token, fine-grained token or GITHUB_TOKEN
+	BaseURL    string       // API root (e.g., "https://api.github.com" or "https://ghe.example.com/api/v3")
+	Repo       string       // Repository as "owner/name"
+	HTTPClient *http.Client // Optional custom HTTP client
+}
+
+// WorkflowRun represents a GitHub Actions workflow run.
+type WorkflowRun struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`          // Workflow name
+	DisplayTitle string    `json:"display_title"` // Usually the commit or PR title
+	Path         string    `json:"path"`          // Workflow file, e.g. ".github/workflows/ci.yml"
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	Event        string    `json:"event"`
+	Status       string    `json:"status"`     // "queued", "in_progress", "completed", ...
+	Conclusion   string    `json:"conclusion"` // "success", "failure", "cancelled", ... (empty until completed)
+	WorkflowID   int64     `json:"workflow_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	HTMLURL      string    `json:"html_url"`
+}
+
+// workflowRunsPage is the envelope returned by the workflow run list endpoints.
+type workflowRunsPage struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// RunListOptions filters a workflow run listing.
+type RunListOptions struct {
+	Workflow string // Workflow file name (e.g. "ci.yml") or numeric workflow ID
+	Branch   string
+	Status   string // Run status or conclusion, e.g. "in_progress" or "success"
+	Limit    int    // Maximum runs to return (at most MaxPageSize; 0 means MaxPageSize)
+}
+
+// PullRequest represents a GitHub pull request.
+type PullRequest struct {
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	State    string     `json:"state"` // "open" or "closed"
+	Merged   bool       `json:"merged"`
+	MergedAt *time.Time `json:"merged_at,omitempty"`
+	Draft    bool       `json:"draft"`
+	HTMLURL  string     `json:"html_url"`
+}
+
+// User represents a GitHub user.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+// Label represents a GitHub issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Milestone represents a GitHub milestone.
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // "open" or "closed"
+}
+
+// SubIssuesSummary counts the sub-issues of an issue.
+type SubIssuesSummary struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// Issue represents a GitHub issue. The issues API also returns pull
+// requests; those have PullRequest set.
+type Issue struct {
+	ID               int64             `json:"id"`
+	Number           int               `json:"number"`
+	Title            string            `json:"title"`
+	Body             string            `json:"body"`
+	State            string            `json:"state"`        // "open" or "closed"
+	StateReason      string            `json:"state_reason"` // "completed", "not_planned", "reopened" (may be empty)
+	Labels           []Label           `json:"labels"`
+	Assignee         *User             `json:"assignee"`
+	Assignees        []User            `json:"assignees"`
+	Milestone        *Milestone        `json:"milestone"`
+	User             *User             `json:"user"`
+	HTMLURL          string            `json:"html_url"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	ClosedAt         *time.Time        `json:"closed_at"`
+	PullRequest      *json.RawMessage  `json:"pull_request,omitempty"`
+	SubIssuesSummary *SubIssuesSummary `json:"sub_issues_summary,omitempty"`
+}
+
+// IsPullRequest reports whether the issue is a pull request.
+func (i *Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// IssueListOptions filters an issue listing.
+type IssueListOptions struct {
+	State string    // "open", "closed" or "all" (empty means "all")
+	Since time.Time // Only issues updated at or after this time (zero means no filter)
+}
+
+// IssueConversion holds the result of converting a GitHub issue to Beads.
+// It includes the issue and any dependencies that should be created.
+type IssueConversion struct {
+	Issue        *types.Issue
+	Dependencies []DependencyInfo
+}
+
+// DependencyInfo represents a dependency to be created after issue import.
+// Stored separately since we need all issues imported before linking dependencies.
+type DependencyInfo struct {
+	FromNumber int    // GitHub issue number of the dependent issue
+	ToNumber   int    // GitHub issue number of the dependency target
+	Type       string // Beads dependency type (parent-child)
+}
+
+// APIError is returned for non-2xx API responses.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s (status %d)", e.Message, e.StatusCode)
+}
@@ -0,0 +1,196 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func init() {
+	tracker.Register("gitlab", newTracker)
+}
+
+// Tracker adapts a GitLab project's issues to tracker.Tracker. Issue links
+// are not fetched.
+type Tracker struct {
+	client *Client
+	config *MappingConfig
+}
+
+// NewTracker creates a tracker adapter for a GitLab project.
+func NewTracker(client *Client, config *MappingConfig) *Tracker {
+	return &Tracker{client: client, config: config}
+}
+
+// newTracker creates the adapter from gitlab.* config, falling back to
+// GITLAB_URL, GITLAB_TOKEN and GITLAB_PROJECT_ID. The URL must use HTTPS
+// unless it is local.
+func newTracker(ctx context.Context, cfg tracker.Config) (tracker.Tracker, error) {
+	baseURL := tracker.ConfigValue(ctx, cfg, "gitlab.url", "GITLAB_URL")
+	token := tracker.ConfigValue(ctx, cfg, "gitlab.token", "GITLAB_TOKEN")
+	projectID := tracker.ConfigValue(ctx, cfg, "gitlab.project_id", "GITLAB_PROJECT_ID")
+	switch {
+	case baseURL == "":
+		return nil, fmt.Errorf("gitlab.url not configured")
+	case token == "":
+		return nil, fmt.Errorf("gitlab.token not configured")
+	case projectID == "":
+		return nil, fmt.Errorf("gitlab.project_id not configured")
+	case strings.HasPrefix(baseURL, "http://") &&
+		!strings.HasPrefix(baseURL, "http://localhost") &&
+		!strings.HasPrefix(baseURL, "http://127.0.0.1"):
+		return nil, fmt.Errorf("gitlab.url must use HTTPS (got %q)", baseURL)
+	}
+	return NewTracker(NewClient(token, strings.TrimSuffix(baseURL, "/"), projectID), DefaultMappingConfig()), nil
+}
+
+// Name returns "gitlab".
+func (t *Tracker) Name() string { return "gitlab" }
+
+// DisplayName returns "GitLab".
+func (t *Tracker) DisplayName() string { return "GitLab" }
+
+// Mapper returns the scoped-label mappings.
+func (t *Tracker) Mapper() tracker.FieldMapper { return fieldMapper{t.config} }
+
+// CanonicalRef accepts issue URLs (/-/issues/<iid>) on the configured
+// instance. For path-based project IDs the project must match too.
+func (t *Tracker) CanonicalRef(ref string) (string, bool) {
+	parsed, err := url.Parse(ref)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", false
+	}
+	if base, err := url.Parse(t.client.BaseURL); err != nil || !strings.EqualFold(base.Host, parsed.Host) {
+		return "", false
+	}
+	project, rest, ok := strings.Cut(strings.Trim(parsed.Path, "/"), "/-/issues/")
+	if !ok || project == "" {
+		return "", false
+	}
+	if strings.Contains(t.client.ProjectID, "/") && !strings.EqualFold(project, t.client.ProjectID) {
+		return "", false
+	}
+	iid, _, _ := strings.Cut(rest, "/")
+	if n, err := strconv.Atoi(iid); err != nil || n <= 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s://%s/%s/-/issues/%s", parsed.Scheme, parsed.Host, project, iid), true
+}
+
+// FetchIssues lists the project's issues.
+func (t *Tracker) FetchIssues(ctx context.Context, opts tracker.FetchOptions) ([]*tracker.RemoteIssue, error) {
+	state := opts.State
+	if state == "open" {
+		state = "opened"
+	}
+	var issues []Issue
+	var err error
+	if opts.Since.IsZero() {
+		issues, err = t.client.FetchIssues(ctx, state)
+	} else {
+		issues, err = t.client.FetchIssuesSince(ctx, state, opts.Since)
+	}
+	if err != nil {
+		return nil, err
+	}
+	remotes := make([]*tracker.RemoteIssue, 0, len(issues))
+	for i := range issues {
+		remotes = append(remotes, t.remote(&issues[i]))
+	}
+	return remotes, nil
+}
+
+// CreateIssue creates a GitLab issue, then applies the fields creation
+// doesn't take (state, weight).
+func (t *Tracker) CreateIssue(ctx context.Context, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	fields := BeadsIssueToGitLabFields(issue, t.config)
+	labels, _ := fields["labels"].([]string)
+	created, err := t.client.CreateIssue(ctx, issue.Title, issue.Description, labels)
+	if err != nil {
+		return nil, err
+	}
+	if _, closing := fields["state_event"]; closing || fields["weight"] != nil {
+		if created, err = t.client.UpdateIssue(ctx, created.IID, fields); err != nil {
+			return nil, err
+		}
+	}
+	return t.remote(created), nil
+}
+
+// UpdateIssue overwrites a GitLab issue's fields, labels and state.
+func (t *Tracker) UpdateIssue(ctx context.Context, ref string, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	canonical, ok := t.CanonicalRef(ref)
+	if !ok {
+		return nil, fmt.Errorf("not a GitLab issue URL: %s", ref)
+	}
+	iid, _ := strconv.Atoi(canonical[strings.LastIndex(canonical, "/")+1:])
+	fields := BeadsIssueToGitLabFields(issue, t.config)
+	if issue.Status != types.StatusClosed {
+		fields["state_event"] = "reopen"
+	}
+	updated, err := t.client.UpdateIssue(ctx, iid, fields)
+	if err != nil {
+		return nil, err
+	}
+	return t.remote(updated), nil
+}
+
+// remote converts a GitLab issue for the sync engine.
+func (t *Tracker) remote(gl *Issue) *tracker.RemoteIssue {
+	issue := GitLabIssueToBeads(gl, t.config).Issue
+	ref := gl.WebURL
+	if canonical, ok := t.CanonicalRef(ref); ok {
+		ref = canonical
+	}
+	issue.ExternalRef = &ref
+	return &tracker.RemoteIssue{
+		Ref:   ref,
+		Key:   fmt.Sprintf("#%d", gl.IID),
+		Issue: issue,
+	}
+}
+
+// fieldMapper maps fields to the scoped labels (and opened/closed state)
+// GitLab carries them in.
+type fieldMapper struct {
+	config *MappingConfig
+}
+
+func (m fieldMapper) StatusToTracker(status types.Status) string {
+	switch status {
+	case types.StatusClosed:
+		return "closed"
+	case types.StatusInProgress, types.StatusBlocked, types.StatusDeferred:
+		return "status::" + string(status)
+	default:
+		return "opened"
+	}
+}
+
+func (m fieldMapper) StatusToBeads(status string) types.Status {
+	if strings.HasPrefix(status, "status::") {
+		return types.Status(statusFromLabelsAndState([]string{status}, "opened", m.config))
+	}
+	return types.Status(statusFromLabelsAndState(nil, status, m.config))
+}
+
+func (m fieldMapper) PriorityToTracker(priority int) string {
+	return "priority::" + priorityToLabel(priority)
+}
+
+func (m fieldMapper) PriorityToBeads(priority string) int {
+	return priorityFromLabels([]string{priority}, m.config)
+}
+
+func (m fieldMapper) TypeToTracker(issueType types.IssueType) string {
+	return "type::" + string(issueType)
+}
+
+func (m fieldMapper) TypeToBeads(issueType string) types.IssueType {
+	return types.IssueType(typeFromLabels([]string{issueType}, m.config))
+}
@@ -0,0 +1,341 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// mapConfig is an in-memory tracker.Config.
+type mapConfig map[string]string
+
+func (m mapConfig) GetConfig(_ context.Context, key string) (string, error) { return m[key], nil }
+func (m mapConfig) GetAllConfig(_ context.Context) (map[string]string, error) {
+	return m, nil
+}
+
+func TestTrackerRegistered(t *testing.T) {
+	t.Setenv("GITLAB_URL", "")
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("GITLAB_PROJECT_ID", "")
+	ctx := context.Background()
+
+	cfg := mapConfig{"gitlab.url": "http://gitlab.example.com", "gitlab.token": "t", "gitlab.project_id": "42"}
+	if _, err := tracker.New(ctx, "gitlab", cfg); err == nil {
+		t.Error("expected an error for a plain-HTTP URL")
+	}
+	cfg["gitlab.url"] = "http://localhost:8080/"
+	trk, err := tracker.New(ctx, "gitlab", cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if trk.Name() != "gitlab" {
+		t.Errorf("Name() = %q", trk.Name())
+	}
+}
+
+func TestTrackerCanonicalRef(t *testing.T) {
+	byID := NewTracker(NewClient("t", "https://gitlab.example.com", "42"), DefaultMappingConfig())
+	byPath := NewTracker(NewClient("t", "https://gitlab.example.com", "group/app"), DefaultMappingConfig())
+
+	tests := []struct {
+		name string
+		trk  *Tracker
+		ref  string
+		want string
+		ok   bool
+	}{
+		{"numeric project", byID, "https://gitlab.example.com/group/app/-/issues/7", "https://gitlab.example.com/group/app/-/issues/7", true},
+		{"trailing path", byID, "https://gitlab.example.com/group/app/-/issues/7/designs", "https://gitlab.example.com/group/app/-/issues/7", true},
+		{"other host", byID, "https://gitlab.com/group/app/-/issues/7", "", false},
+		{"merge request", byID, "https://gitlab.example.com/group/app/-/merge_requests/7", "", false},
+		{"path project", byPath, "https://gitlab.example.com/Group/App/-/issues/7", "https://gitlab.example.com/Group/App/-/issues/7", true},
+		{"other project", byPath, "https://gitlab.example.com/group/other/-/issues/7", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.trk.CanonicalRef(tt.ref)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("CanonicalRef(%q) = %q, %v; want %q, %v", tt.ref, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTrackerMapperRoundTrip(t *testing.T) {
+	m := NewTracker(NewClient("t", "https://gitlab.example.com", "42"), DefaultMappingConfig()).Mapper()
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed} {
+		if got := m.StatusToBeads(m.StatusToTracker(status)); got != status {
+			t.Errorf("status %q round-trips to %q", status, got)
+		}
+	}
+	for p := 0; p <= 4; p++ {
+		if got := m.PriorityToBeads(m.PriorityToTracker(p)); got != p {
+			t.Errorf("priority %d round-trips to %d", p, got)
+		}
+	}
+	for _, it := range []types.IssueType{types.TypeBug, types.TypeFeature, types.TypeTask} {
+		if got := m.TypeToBeads(m.TypeToTracker(it)); got != it {
+			t.Errorf("type %q round-trips to %q", it, got)
+		}
+	}
+}
+
+// fakeGitLab serves a minimal GitLab issues API for one project and records
+// list queries, created issues and updates.
+type fakeGitLab struct {
+	mu      sync.Mutex
+	webURL  string // Project web URL issue links are built from
+	issues  map[int]*Issue
+	queries []url.Values
+	created []map[string]interface{}
+	updated map[int][]map[string]interface{}
+}
+
+// newFakeGitLab starts a fake API for project (an ID or a group/project
+// path) whose issues live under the server's /group/app path.
+func newFakeGitLab(t *testing.T, project string, issues ...*Issue) (*fakeGitLab, *Tracker) {
+	t.Helper()
+	fake := &fakeGitLab{issues: make(map[int]*Issue), updated: make(map[int][]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.webURL = server.URL + "/group/app"
+	for _, issue := range issues {
+		issue.WebURL = fake.webURL + "/-/issues/" + strconv.Itoa(issue.IID)
+		fake.issues[issue.IID] = issue
+	}
+	return fake, NewTracker(NewClient("test-token", server.URL, project), DefaultMappingConfig())
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	readFields := func() map[string]interface{} {
+		var fields map[string]interface{}
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &fields)
+		return fields
+	}
+
+	// Path-based project IDs must arrive escaped as one segment
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/")
+	project, rest, _ := strings.Cut(path, "/")
+	if project != "42" && project != "group%2Fapp" || !strings.HasPrefix(rest, "issues") {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "404 Project Not Found"}`))
+		return
+	}
+	iid, _ := strconv.Atoi(strings.TrimPrefix(rest, "issues/"))
+	issue := f.issues[iid]
+
+	switch {
+	case rest == "issues" && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		f.queries = append(f.queries, query)
+		since, _ := time.Parse(time.RFC3339, query.Get("updated_after"))
+		list := []*Issue{}
+		for _, issue := range f.issues {
+			if state := query.Get("state"); state != "" && issue.State != state {
+				continue
+			}
+			if !since.IsZero() && !issue.UpdatedAt.After(since) {
+				continue
+			}
+			list = append(list, issue)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].IID < list[j].IID })
+		_ = json.NewEncoder(w).Encode(list)
+	case rest == "issues" && r.Method == http.MethodPost:
+		fields := readFields()
+		f.created = append(f.created, fields)
+		now := time.Now().UTC()
+		issue := &Issue{IID: 100 + len(f.created), State: "opened", CreatedAt: &now, UpdatedAt: &now}
+		issue.WebURL = f.webURL + "/-/issues/" + strconv.Itoa(issue.IID)
+		f.issues[issue.IID] = issue
+		applyFields(issue, fields)
+		_ = json.NewEncoder(w).Encode(issue)
+	case issue != nil && r.Method == http.MethodPut:
+		fields := readFields()
+		f.updated[iid] = append(f.updated[iid], fields)
+		applyFields(issue, fields)
+		_ = json.NewEncoder(w).Encode(issue)
+	case issue != nil:
+		_ = json.NewEncoder(w).Encode(issue)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "404 Not found"}`))
+	}
+}
+
+// applyFields applies create or update fields to a fake issue.
+func applyFields(issue *Issue, fields map[string]interface{}) {
+	if title, ok := fields["title"].(string); ok {
+		issue.Title = title
+	}
+	if description, ok := fields["description"].(string); ok {
+		issue.Description = description
+	}
+	if labels, ok := fields["labels"].([]interface{}); ok {
+		issue.Labels = nil
+		for _, label := range labels {
+			issue.Labels = append(issue.Labels, label.(string))
+		}
+	}
+	if weight, ok := fields["weight"].(float64); ok {
+		issue.Weight = int(weight)
+	}
+	switch fields["state_event"] {
+	case "close":
+		issue.State = "closed"
+	case "reopen":
+		issue.State = "opened"
+	}
+	now := time.Now().UTC()
+	issue.UpdatedAt = &now
+}
+
+// gitlabIssue returns an issue last updated at updated.
+func gitlabIssue(iid int, title, state string, labels []string, updated time.Time) *Issue {
+	created := updated.Add(-time.Hour)
+	return &Issue{IID: iid, Title: title, Description: title + " body", State: state,
+		Labels: labels, CreatedAt: &created, UpdatedAt: &updated}
+}
+
+func TestTrackerFetchIssues(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake, trk := newFakeGitLab(t, "42",
+		gitlabIssue(1, "Open bug", "opened", []string{"type::bug", "priority::high", "status::in_progress", "ui"}, updated),
+		gitlabIssue(2, "Done task", "closed", nil, updated),
+	)
+	ctx := context.Background()
+
+	remotes, err := trk.FetchIssues(ctx, tracker.FetchOptions{State: "open"})
+	if err != nil {
+		t.Fatalf("FetchIssues: %v", err)
+	}
+	if got := fake.queries[0].Get("state"); got != "opened" {
+		t.Errorf("state query = %q, want opened", got)
+	}
+	if len(remotes) != 1 {
+		t.Fatalf("fetched %d issues, want 1", len(remotes))
+	}
+	remote := remotes[0]
+	if remote.Ref != fake.webURL+"/-/issues/1" || remote.Key != "#1" {
+		t.Errorf("ref, key = %q, %q", remote.Ref, remote.Key)
+	}
+	issue := remote.Issue
+	if issue.IssueType != types.TypeBug || issue.Priority != 1 || issue.Status != types.StatusInProgress {
+		t.Errorf("issue = %s/%d/%s", issue.IssueType, issue.Priority, issue.Status)
+	}
+	if !slices.Equal(issue.Labels, []string{"ui"}) {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+
+	// An incremental fetch asks only for issues updated since the cursor
+	remotes, err = trk.FetchIssues(ctx, tracker.FetchOptions{State: "all", Since: updated})
+	if err != nil {
+		t.Fatalf("FetchIssues since: %v", err)
+	}
+	query := fake.queries[1]
+	if query.Get("updated_after") != updated.Format(time.RFC3339) || query.Has("state") {
+		t.Errorf("incremental query = %v", query)
+	}
+	if len(remotes) != 0 {
+		t.Errorf("fetched %d issues since %s, want 0", len(remotes), updated)
+	}
+}
+
+func TestTrackerCreateIssue(t *testing.T) {
+	fake, trk := newFakeGitLab(t, "42")
+	ctx := context.Background()
+
+	open := &types.Issue{Title: "New feature", Priority: 2, IssueType: types.TypeFeature, Status: types.StatusOpen}
+	remote, err := trk.CreateIssue(ctx, open)
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if remote.Ref != fake.webURL+"/-/issues/101" {
+		t.Errorf("ref = %q", remote.Ref)
+	}
+	if len(fake.updated) != 0 {
+		t.Errorf("updated = %v, want no follow-up for an open issue", fake.updated)
+	}
+
+	// Creation can't set the state or weight, so a follow-up update does
+	estimate := 120
+	closed := &types.Issue{Title: "Already done", Priority: 2, IssueType: types.TypeTask,
+		Status: types.StatusClosed, EstimatedMinutes: &estimate}
+	remote, err = trk.CreateIssue(ctx, closed)
+	if err != nil {
+		t.Fatalf("CreateIssue closed: %v", err)
+	}
+	followUp := fake.updated[102]
+	if len(followUp) != 1 || followUp[0]["state_event"] != "close" || followUp[0]["weight"] != float64(2) {
+		t.Fatalf("follow-up updates = %v", followUp)
+	}
+	if remote.Issue.Status != types.StatusClosed {
+		t.Errorf("status = %q, want closed", remote.Issue.Status)
+	}
+}
+
+func TestTrackerUpdateIssue(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake, trk := newFakeGitLab(t, "42", gitlabIssue(7, "Closed remotely", "closed", nil, updated))
+	ctx := context.Background()
+
+	// A local issue that is open again reopens the GitLab issue
+	issue := &types.Issue{Title: "Reopened", Priority: 1, IssueType: types.TypeBug, Status: types.StatusOpen}
+	remote, err := trk.UpdateIssue(ctx, fake.webURL+"/-/issues/7", issue)
+	if err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	sent := fake.updated[7]
+	if len(sent) != 1 || sent[0]["state_event"] != "reopen" || sent[0]["title"] != "Reopened" {
+		t.Fatalf("updates = %v", sent)
+	}
+	if remote.Issue.Status != types.StatusOpen || fake.issues[7].State != "opened" {
+		t.Errorf("status = %q, state = %q", remote.Issue.Status, fake.issues[7].State)
+	}
+
+	if _, err := trk.UpdateIssue(ctx, "https://gitlab.com/group/app/-/issues/7", issue); err == nil {
+		t.Error("expected an error for another instance's issue")
+	}
+	if _, err := trk.UpdateIssue(ctx, fake.webURL+"/-/issues/404", issue); err == nil {
+		t.Error("expected an error for an issue GitLab doesn't have")
+	}
+}
+
+func TestTrackerPathProjectID(t *testing.T) {
+	updated := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake, trk := newFakeGitLab(t, "group/app", gitlabIssue(3, "Path project", "opened", nil, updated))
+	ctx := context.Background()
+
+	remotes, err := trk.FetchIssues(ctx, tracker.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchIssues: %v", err)
+	}
+	if len(remotes) != 1 || remotes[0].Ref != fake.webURL+"/-/issues/3" {
+		t.Fatalf("remotes = %+v", remotes)
+	}
+	issue := &types.Issue{Title: "Path project, edited", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen}
+	if _, err := trk.UpdateIssue(ctx, remotes[0].Ref, issue); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if fake.issues[3].Title != "Path project, edited" {
+		t.Errorf("title = %q", fake.issues[3].Title)
+	}
+}
@@ -146,56 +146,6 @@ type Namespace struct {
 	WebURL   string `json:"web_url,omitempty"`
 }
 
-// SyncStats tracks statistics for a GitLab sync operation.
-type SyncStats struct {
-	Pulled    int `json:"pulled"`
-	Pushed    int `json:"pushed"`
-	Created   int `json:"created"`
-	Updated   int `json:"updated"`
-	Skipped   int `json:"skipped"`
-	Errors    int `json:"errors"`
-	Conflicts int `json:"conflicts"`
-}
-
-// SyncResult represents the result of a GitLab sync operation.
-type SyncResult struct {
-	Success  bool      `json:"success"`
-	Stats    SyncStats `json:"stats"`
-	LastSync string    `json:"last_sync,omitempty"`
-	Error    string    `json:"error,omitempty"`
-	Warnings []string  `json:"warnings,omitempty"`
-}
-
-// PullStats tracks pull operation statistics.
-type PullStats struct {
-	Created     int
-	Updated     int
-	Skipped     int
-	Incremental bool     // Whether this was an incremental sync
-	SyncedSince string   // Timestamp we synced since (if incremental)
-	Warnings    []string // Non-fatal warnings encountered during pull
-}
-
-// PushStats tracks push operation statistics.
-type PushStats struct {
-	Created int
-	Updated int
-	Skipped int
-	Errors  int
-}
-
-// Conflict represents a conflict between local and GitLab versions.
-// A conflict occurs when both the local and GitLab versions have been modified
-// since the last sync.
-type Conflict struct {
-	IssueID           string    // Beads issue ID
-	LocalUpdated      time.Time // When the local version was last modified
-	GitLabUpdated     time.Time // When the GitLab version was last modified
-	GitLabExternalRef string    // URL to the GitLab issue
-	GitLabIID         int       // GitLab issue IID (project-scoped)
-	GitLabID          int       // GitLab's global issue ID
-}
-
 // IssueConversion holds the result of converting a GitLab issue to Beads.
 // It includes the issue and any dependencies that should be created.
 type IssueConversion struct {
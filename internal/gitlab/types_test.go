@@ -4,7 +4,6 @@ package gitlab
 import (
 	"encoding/json"
 	"testing"
-	"time"
 
 	"github.com/steveyegge/fastbeads/internal/types"
 )
@@ -191,38 +190,6 @@ func TestClientConfig(t *testing.T) {
 	}
 }
 
-// TestSyncStatsZeroValue verifies SyncStats initializes correctly.
-func TestSyncStatsZeroValue(t *testing.T) {
-	stats := SyncStats{}
-
-	if stats.Pulled != 0 {
-		t.Errorf("Pulled = %d, want 0", stats.Pulled)
-	}
-	if stats.Pushed != 0 {
-		t.Errorf("Pushed = %d, want 0", stats.Pushed)
-	}
-}
-
-// TestConflictFields verifies Conflict type has required fields.
-func TestConflictFields(t *testing.T) {
-	now := time.Now()
-	conflict := Conflict{
-		IssueID:           "bd-abc123",
-		LocalUpdated:      now,
-		GitLabUpdated:     now.Add(time.Hour),
-		GitLabExternalRef: "https://gitlab.example.com/group/project/-/issues/42",
-		GitLabIID:         42,
-		GitLabID:          123456,
-	}
-
-	if conflict.IssueID != "bd-abc123" {
-		t.Errorf("IssueID = %q, want %q", conflict.IssueID, "bd-abc123")
-	}
-	if conflict.GitLabIID != 42 {
-		t.Errorf("GitLabIID = %d, want 42", conflict.GitLabIID)
-	}
-}
-
 // TestStateMapping verifies GitLab states map to expected values.
 func TestStateMapping(t *testing.T) {
 	tests := []struct {
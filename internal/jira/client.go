@@ -0,0 +1,358 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewClient creates a new Jira client for the site at siteURL. An empty
+// apiVersion picks v3 for Jira Cloud and v2 for everything else.
+func NewClient(siteURL, username, token, apiVersion string) *Client {
+	siteURL = strings.TrimSuffix(siteURL, "/")
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion(siteURL)
+	}
+	return &Client{
+		URL:        siteURL,
+		Username:   username,
+		Token:      token,
+		APIVersion: apiVersion,
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// WithHTTPClient returns a new client configured to use the specified HTTP client.
+// This is useful for testing or customizing timeouts and transport settings.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	return &Client{
+		URL:        c.URL,
+		Username:   c.Username,
+		Token:      c.Token,
+		APIVersion: c.APIVersion,
+		HTTPClient: httpClient,
+	}
+}
+
+// IsCloud reports whether siteURL is a Jira Cloud site.
+func IsCloud(siteURL string) bool {
+	return strings.Contains(siteURL, ".atlassian.net")
+}
+
+// DefaultAPIVersion returns the REST API version to use for siteURL:
+// "3" for Jira Cloud, "2" for Server and Data Center.
+func DefaultAPIVersion(siteURL string) string {
+	if IsCloud(siteURL) {
+		return "3"
+	}
+	return "2"
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IssueURL returns the browse URL for an issue key, which beads stores as external_ref.
+func (c *Client) IssueURL(key string) string {
+	return c.URL + "/browse/" + key
+}
+
+// buildURL constructs a full REST API URL from path and optional query parameters.
+func (c *Client) buildURL(path string, params map[string]string) string {
+	u := c.URL + "/rest/api/" + c.APIVersion + path
+
+	if len(params) > 0 {
+		values := url.Values{}
+		for k, v := range params {
+			values.Set(k, v)
+		}
+		u += "?" + values.Encode()
+	}
+
+	return u
+}
+
+// doRequest performs an HTTP request with authentication and retry logic.
+func (c *Client) doRequest(ctx context.Context, method, urlStr string, body interface{}) ([]byte, error) {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Cloud uses email:api_token and Server accepts username:password,
+		// both as Basic auth; a bare token is a Server personal access token.
+		if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Token)
+		} else if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", UserAgent)
+		if jsonBody != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		// Limit response body to 50MB to prevent OOM from malformed responses.
+		const maxResponseSize = 50 * 1024 * 1024
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			delay := RetryDelay * time.Duration(1<<attempt)
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+				delay = time.Duration(seconds) * time.Second
+			}
+			lastErr = fmt.Errorf("rate limited (attempt %d/%d)", attempt+1, MaxRetries+1)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+				continue
+			}
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(respBody)}
+		}
+
+		return respBody, nil
+	}
+
+	return nil, fmt.Errorf("max retries (%d) exceeded: %w", MaxRetries+1, lastErr)
+}
+
+// apiErrorMessage joins the errorMessages and field errors of a Jira error
+// body, falling back to the raw body.
+func apiErrorMessage(body []byte) string {
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		messages := append([]string(nil), payload.ErrorMessages...)
+		fields := make([]string, 0, len(payload.Errors))
+		for field := range payload.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			messages = append(messages, field+": "+payload.Errors[field])
+		}
+		if len(messages) > 0 {
+			return strings.Join(messages, "; ")
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// BuildSearchJQL returns the JQL for a project's issues, optionally limited
+// to open or closed issues and to those updated since a time. Jira evaluates
+// JQL dates in the user's time zone with minute precision, so since is
+// widened by a day; re-fetching an unchanged issue is harmless.
+func BuildSearchJQL(project, state string, since time.Time) string {
+	clauses := []string{fmt.Sprintf("project = %q", project)}
+	switch state {
+	case "open":
+		clauses = append(clauses, "statusCategory != Done")
+	case "closed":
+		clauses = append(clauses, "statusCategory = Done")
+	}
+	if !since.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("updated >= %q", since.UTC().Add(-24*time.Hour).Format("2006-01-02 15:04")))
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY key ASC"
+}
+
+// SearchIssues retrieves all issues matching jql, following pagination.
+func (c *Client) SearchIssues(ctx context.Context, jql string) ([]Issue, error) {
+	var allIssues []Issue
+	startAt := 0
+	nextPageToken := ""
+
+	for page := 1; ; page++ {
+		if page > MaxPages {
+			return nil, fmt.Errorf("pagination limit exceeded: stopped after %d pages", MaxPages)
+		}
+
+		params := map[string]string{
+			"jql":        jql,
+			"maxResults": strconv.Itoa(MaxPageSize),
+			"fields":     "*all",
+		}
+		path := "/search"
+		if c.APIVersion == "2" {
+			params["startAt"] = strconv.Itoa(startAt)
+		} else {
+			// v3's /search was retired in favor of token-paged /search/jql
+			path = "/search/jql"
+			if nextPageToken != "" {
+				params["nextPageToken"] = nextPageToken
+			}
+		}
+
+		respBody, err := c.doRequest(ctx, http.MethodGet, c.buildURL(path, params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		var result searchPage
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse search response: %w", err)
+		}
+		allIssues = append(allIssues, result.Issues...)
+
+		if c.APIVersion == "2" {
+			startAt += len(result.Issues)
+			if len(result.Issues) == 0 || startAt >= result.Total {
+				break
+			}
+		} else {
+			if result.IsLast || result.NextPageToken == "" {
+				break
+			}
+			nextPageToken = result.NextPageToken
+		}
+	}
+
+	return allIssues, nil
+}
+
+// GetIssue retrieves a single issue by key.
+func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	urlStr := c.buildURL("/issue/"+url.PathEscape(key), nil)
+	respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", key, err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", err)
+	}
+	return &issue, nil
+}
+
+// CreateIssue creates an issue from the given fields. The returned issue
+// carries only the ID and key.
+func (c *Client) CreateIssue(ctx context.Context, fields map[string]interface{}) (*Issue, error) {
+	body := map[string]interface{}{"fields": fields}
+	respBody, err := c.doRequest(ctx, http.MethodPost, c.buildURL("/issue", nil), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse create response: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue sets the given fields on an issue.
+func (c *Client) UpdateIssue(ctx context.Context, key string, fields map[string]interface{}) error {
+	body := map[string]interface{}{"fields": fields}
+	urlStr := c.buildURL("/issue/"+url.PathEscape(key), nil)
+	if _, err := c.doRequest(ctx, http.MethodPut, urlStr, body); err != nil {
+		return fmt.Errorf("failed to update issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetTransitions lists the workflow transitions currently available on an issue.
+func (c *Client) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
+	urlStr := c.buildURL("/issue/"+url.PathEscape(key)+"/transitions", nil)
+	respBody, err := c.doRequest(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions of %s: %w", key, err)
+	}
+
+	var result transitionsResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse transitions response: %w", err)
+	}
+	return result.Transitions, nil
+}
+
+// TransitionIssue moves an issue through the transition with the given ID.
+func (c *Client) TransitionIssue(ctx context.Context, key, transitionID string) error {
+	body := map[string]interface{}{
+		"transition": map[string]string{"id": transitionID},
+	}
+	urlStr := c.buildURL("/issue/"+url.PathEscape(key)+"/transitions", nil)
+	if _, err := c.doRequest(ctx, http.MethodPost, urlStr, body); err != nil {
+		return fmt.Errorf("failed to transition issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// TransitionToStatus moves an issue into the named status, if the workflow
+// offers a transition there. It returns false when no transition matches.
+func (c *Client) TransitionToStatus(ctx context.Context, key, status string) (bool, error) {
+	transitions, err := c.GetTransitions(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	transition := FindTransition(transitions, status)
+	if transition == nil {
+		return false, nil
+	}
+	if err := c.TransitionIssue(ctx, key, transition.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindTransition picks the transition leading to the named status, preferring
+// an exact (case-insensitive) match over a partial one.
+func FindTransition(transitions []Transition, status string) *Transition {
+	target := strings.ToLower(status)
+	for i := range transitions {
+		if strings.ToLower(transitions[i].To.Name) == target {
+			return &transitions[i]
+		}
+	}
+	for i := range transitions {
+		if strings.Contains(strings.ToLower(transitions[i].To.Name), target) {
+			return &transitions[i]
+		}
+	}
+	return nil
+}
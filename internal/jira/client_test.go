@@ -0,0 +1,216 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestNewClient verifies the constructor defaults.
+func TestNewClient(t *testing.T) {
+	cloud := NewClient("https://acme.atlassian.net/", "me@acme.com", "token", "")
+	if cloud.URL != "https://acme.atlassian.net" || cloud.APIVersion != "3" {
+		t.Errorf("cloud client = %q v%s", cloud.URL, cloud.APIVersion)
+	}
+	if cloud.HTTPClient == nil {
+		t.Error("HTTPClient is nil, want non-nil default client")
+	}
+
+	server := NewClient("https://jira.example.com", "", "pat", "")
+	if server.APIVersion != "2" {
+		t.Errorf("server APIVersion = %q, want 2", server.APIVersion)
+	}
+	if forced := NewClient("https://jira.example.com", "", "pat", "3"); forced.APIVersion != "3" {
+		t.Errorf("explicit APIVersion = %q, want 3", forced.APIVersion)
+	}
+	if got := cloud.IssueURL("PROJ-1"); got != "https://acme.atlassian.net/browse/PROJ-1" {
+		t.Errorf("IssueURL = %q", got)
+	}
+}
+
+func TestDoRequest_Auth(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		if ua := r.Header.Get("User-Agent"); ua != UserAgent {
+			t.Errorf("User-Agent = %q", ua)
+		}
+		_, _ = w.Write([]byte(`{"key": "PROJ-1", "fields": {}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := NewClient(server.URL, "me@acme.com", "secret", "3").GetIssue(ctx, "PROJ-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(server.URL, "", "pat", "2").GetIssue(ctx, "PROJ-1"); err != nil {
+		t.Fatal(err)
+	}
+	// base64("me@acme.com:secret")
+	want := []string{"Basic bWVAYWNtZS5jb206c2VjcmV0", "Bearer pat"}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestDoRequest_RetriesRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key": "PROJ-1"}`))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	issue, err := NewClient(server.URL, "", "t", "2").GetIssue(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if issue.Key != "PROJ-1" || calls.Load() != 2 {
+		t.Errorf("key = %q after %d calls", issue.Key, calls.Load())
+	}
+	if time.Since(start) < RetryDelay {
+		t.Error("retry did not back off")
+	}
+}
+
+func TestGetIssue_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages": ["Issue does not exist"], "errors": {}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "", "t", "2").GetIssue(context.Background(), "PROJ-9")
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if !strings.Contains(err.Error(), "Issue does not exist") {
+		t.Errorf("err = %v, want Jira message", err)
+	}
+}
+
+func TestSearchIssues_V2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("jql") != "project = PROJ" {
+			t.Errorf("jql = %q", r.URL.Query().Get("jql"))
+		}
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			_, _ = w.Write([]byte(`{"startAt": 0, "total": 3, "issues": [{"key": "PROJ-1"}, {"key": "PROJ-2"}]}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"startAt": 2, "total": 3, "issues": [{"key": "PROJ-3"}]}`))
+		default:
+			t.Errorf("unexpected startAt %q", r.URL.Query().Get("startAt"))
+		}
+	}))
+	defer server.Close()
+
+	issues, err := NewClient(server.URL, "", "t", "2").SearchIssues(context.Background(), "project = PROJ")
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(issues) != 3 || issues[2].Key != "PROJ-3" {
+		t.Errorf("issues = %+v", issues)
+	}
+}
+
+func TestSearchIssues_V3(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		switch r.URL.Query().Get("nextPageToken") {
+		case "":
+			_, _ = w.Write([]byte(`{"issues": [{"key": "PROJ-1"}], "nextPageToken": "abc"}`))
+		case "abc":
+			_, _ = w.Write([]byte(`{"issues": [{"key": "PROJ-2"}], "isLast": true}`))
+		}
+	}))
+	defer server.Close()
+
+	issues, err := NewClient(server.URL, "me", "t", "3").SearchIssues(context.Background(), "project = PROJ")
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(issues) != 2 || issues[1].Key != "PROJ-2" {
+		t.Errorf("issues = %+v", issues)
+	}
+}
+
+func TestBuildSearchJQL(t *testing.T) {
+	if got := BuildSearchJQL("PROJ", "all", time.Time{}); got != `project = "PROJ" ORDER BY key ASC` {
+		t.Errorf("full JQL = %q", got)
+	}
+	since := time.Date(2025, 3, 2, 10, 30, 0, 0, time.UTC)
+	want := `project = "PROJ" AND statusCategory != Done AND updated >= "2025-03-01 10:30" ORDER BY key ASC`
+	if got := BuildSearchJQL("PROJ", "open", since); got != want {
+		t.Errorf("incremental JQL = %q, want %q", got, want)
+	}
+}
+
+func TestCreateUpdateAndTransition(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			var body map[string]interface{}
+			raw, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(raw, &body)
+			bodies = append(bodies, body)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			_, _ = w.Write([]byte(`{"id": "10001", "key": "PROJ-7"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/PROJ-7":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-7/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [
+				{"id": "11", "name": "Start", "to": {"name": "In Progress"}},
+				{"id": "31", "name": "Finish", "to": {"name": "Done"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-7/transitions":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL, "", "t", "2")
+	created, err := client.CreateIssue(ctx, map[string]interface{}{"summary": "New"})
+	if err != nil || created.Key != "PROJ-7" {
+		t.Fatalf("CreateIssue = %+v, %v", created, err)
+	}
+	if err := client.UpdateIssue(ctx, "PROJ-7", map[string]interface{}{"summary": "Renamed"}); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	moved, err := client.TransitionToStatus(ctx, "PROJ-7", "done")
+	if err != nil || !moved {
+		t.Fatalf("TransitionToStatus = %v, %v", moved, err)
+	}
+	if moved, _ := client.TransitionToStatus(ctx, "PROJ-7", "Blocked"); moved {
+		t.Error("transitioned to a status the workflow does not offer")
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("bodies = %+v", bodies)
+	}
+	if fields, _ := bodies[1]["fields"].(map[string]interface{}); fields["summary"] != "Renamed" {
+		t.Errorf("update body = %+v", bodies[1])
+	}
+	if transition, _ := bodies[2]["transition"].(map[string]interface{}); transition["id"] != "31" {
+		t.Errorf("transition body = %+v", bodies[2])
+	}
+}
@@ -0,0 +1,560 @@
+package jira
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// MappingConfig holds configurable mappings between Jira and Beads.
+// Maps keyed by Jira names use lowercase keys for case-insensitive matching.
+type MappingConfig struct {
+	// PriorityMap maps Jira priority names to Beads priority (0-4).
+	PriorityMap map[string]int
+
+	// StatusMap maps Jira status names to Beads statuses.
+	StatusMap map[string]string
+
+	// TypeMap maps Jira issue type names to Beads issue types.
+	TypeMap map[string]string
+
+	// LinkMap maps Jira issue link type names to Beads dependency types.
+	LinkMap map[string]string
+
+	// ReversePriorityMap maps Beads priority to the Jira priority name to push.
+	ReversePriorityMap map[int]string
+
+	// ReverseStatusMap maps Beads status to the Jira status to transition to.
+	ReverseStatusMap map[string]string
+
+	// ReverseTypeMap maps Beads issue type to the Jira issue type to create.
+	ReverseTypeMap map[string]string
+}
+
+// DefaultMappingConfig returns sensible default mappings.
+func DefaultMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		PriorityMap: map[string]int{
+			"highest":  0,
+			"critical": 0,
+			"blocker":  0,
+			"high":     1,
+			"major":    1,
+			"medium":   2,
+			"normal":   2,
+			"low":      3,
+			"minor":    3,
+			"lowest":   4,
+			"trivial":  4,
+		},
+		StatusMap: map[string]string{
+			"to do":            "open",
+			"todo":             "open",
+			"open":             "open",
+			"backlog":          "open",
+			"new":              "open",
+			"in progress":      "in_progress",
+			"in development":   "in_progress",
+			"in review":        "in_progress",
+			"review":           "in_progress",
+			"blocked":          "blocked",
+			"on hold":          "blocked",
+			"done":             "closed",
+			"closed":           "closed",
+			"resolved":         "closed",
+			"complete":         "closed",
+			"completed":        "closed",
+			"won't do":         "closed",
+			"won't fix":        "closed",
+			"duplicate":        "closed",
+			"cannot reproduce": "closed",
+		},
+		TypeMap: map[string]string{
+			"bug":            "bug",
+			"defect":         "bug",
+			"story":          "feature",
+			"feature":        "feature",
+			"new feature":    "feature",
+			"improvement":    "feature",
+			"enhancement":    "feature",
+			"task":           "task",
+			"sub-task":       "task",
+			"subtask":        "task",
+			"epic":           "epic",
+			"initiative":     "epic",
+			"technical task": "chore",
+			"technical debt": "chore",
+			"maintenance":    "chore",
+			"chore":          "chore",
+		},
+		LinkMap: map[string]string{
+			"blocks":    "blocks",
+			"duplicate": "duplicates",
+			"relates":   "related",
+			"cloners":   "related",
+		},
+		ReversePriorityMap: map[int]string{
+			0: "Highest",
+			1: "High",
+			2: "Medium",
+			3: "Low",
+			4: "Lowest",
+		},
+		ReverseStatusMap: map[string]string{
+			"open":        "To Do",
+			"in_progress": "In Progress",
+			"blocked":     "Blocked",
+			"closed":      "Done",
+		},
+		ReverseTypeMap: map[string]string{
+			"bug":     "Bug",
+			"feature": "Story",
+			"task":    "Task",
+			"epic":    "Epic",
+			"chore":   "Task",
+		},
+	}
+}
+
+// ConfigLoader is an interface for loading configuration values.
+// This allows the mapping package to be decoupled from the storage layer.
+type ConfigLoader interface {
+	GetAllConfig() (map[string]string, error)
+}
+
+// LoadMappingConfig loads mapping configuration from a config loader.
+// Config keys follow the pattern: jira.<category>_map.<key> = <value>
+// Examples:
+//
+//	jira.priority_map.blocker = 0
+//	jira.status_map.selected for development = open
+//	jira.type_map.spike = task
+//	jira.link_map.causes = blocks
+//	jira.reverse_status_map.closed = Resolved
+//
+// A custom status_map or type_map entry also becomes the push target for its
+// Beads value unless a reverse_*_map entry says otherwise.
+func LoadMappingConfig(loader ConfigLoader) *MappingConfig {
+	config := DefaultMappingConfig()
+
+	if loader == nil {
+		return config
+	}
+
+	allConfig, err := loader.GetAllConfig()
+	if err != nil {
+		return config
+	}
+
+	// Sorted so that inverting several Jira names onto one Beads value is deterministic
+	keys := make([]string, 0, len(allConfig))
+	for key := range allConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	invertedStatus := make(map[string]bool)
+	invertedType := make(map[string]bool)
+	for _, key := range keys {
+		value := allConfig[key]
+		switch {
+		case strings.HasPrefix(key, "jira.priority_map."):
+			name := strings.ToLower(strings.TrimPrefix(key, "jira.priority_map."))
+			if priority, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				config.PriorityMap[name] = priority
+			}
+		case strings.HasPrefix(key, "jira.status_map."):
+			name := strings.TrimPrefix(key, "jira.status_map.")
+			config.StatusMap[strings.ToLower(name)] = value
+			if !invertedStatus[value] {
+				config.ReverseStatusMap[value] = name
+				invertedStatus[value] = true
+			}
+		case strings.HasPrefix(key, "jira.type_map."):
+			name := strings.TrimPrefix(key, "jira.type_map.")
+			config.TypeMap[strings.ToLower(name)] = value
+			if !invertedType[value] {
+				config.ReverseTypeMap[value] = name
+				invertedType[value] = true
+			}
+		case strings.HasPrefix(key, "jira.link_map."):
+			name := strings.ToLower(strings.TrimPrefix(key, "jira.link_map."))
+			config.LinkMap[name] = value
+		}
+	}
+
+	// Explicit reverse mappings win over inverted forward ones
+	for _, key := range keys {
+		value := allConfig[key]
+		switch {
+		case strings.HasPrefix(key, "jira.reverse_priority_map."):
+			if priority, err := strconv.Atoi(strings.TrimPrefix(key, "jira.reverse_priority_map.")); err == nil {
+				config.ReversePriorityMap[priority] = value
+			}
+		case strings.HasPrefix(key, "jira.reverse_status_map."):
+			config.ReverseStatusMap[strings.TrimPrefix(key, "jira.reverse_status_map.")] = value
+		case strings.HasPrefix(key, "jira.reverse_type_map."):
+			config.ReverseTypeMap[strings.TrimPrefix(key, "jira.reverse_type_map.")] = value
+		}
+	}
+
+	return config
+}
+
+// PriorityToBeads maps a Jira priority to Beads priority (0-4).
+// Unknown or missing priorities map to medium (2).
+func PriorityToBeads(priority *Priority, config *MappingConfig) int {
+	if priority == nil {
+		return 2
+	}
+	if p, ok := config.PriorityMap[strings.ToLower(priority.Name)]; ok {
+		return p
+	}
+	return 2
+}
+
+// PriorityToJira returns the Jira priority name for a Beads priority.
+func PriorityToJira(priority int, config *MappingConfig) string {
+	if name, ok := config.ReversePriorityMap[priority]; ok {
+		return name
+	}
+	return "Medium"
+}
+
+// StatusToBeads maps a Jira status to a Beads status. Statuses missing from
+// the map fall back on their status category.
+func StatusToBeads(status *Status, config *MappingConfig) types.Status {
+	if status == nil {
+		return types.StatusOpen
+	}
+	if s, ok := config.StatusMap[strings.ToLower(status.Name)]; ok {
+		return types.Status(s)
+	}
+	if status.StatusCategory != nil {
+		switch status.StatusCategory.Key {
+		case "done":
+			return types.StatusClosed
+		case "indeterminate":
+			return types.StatusInProgress
+		}
+	}
+	return types.StatusOpen
+}
+
+// StatusToJira returns the Jira status a Beads status is pushed as.
+func StatusToJira(status types.Status, config *MappingConfig) string {
+	if name, ok := config.ReverseStatusMap[string(status)]; ok {
+		return name
+	}
+	return config.ReverseStatusMap[string(types.StatusOpen)]
+}
+
+// TypeToBeads maps a Jira issue type to a Beads issue type.
+func TypeToBeads(issueType *IssueType, config *MappingConfig) types.IssueType {
+	if issueType == nil {
+		return types.TypeTask
+	}
+	if t, ok := config.TypeMap[strings.ToLower(issueType.Name)]; ok {
+		return types.IssueType(t)
+	}
+	return types.TypeTask
+}
+
+// TypeToJira returns the Jira issue type name a Beads issue type is created as.
+func TypeToJira(issueType types.IssueType, config *MappingConfig) string {
+	if name, ok := config.ReverseTypeMap[string(issueType)]; ok {
+		return name
+	}
+	return "Task"
+}
+
+// LinkToBeadsDep maps a Jira link type name to a Beads dependency type.
+// Unmapped link types that mention blocking become blocks, others related.
+func LinkToBeadsDep(linkType string, config *MappingConfig) string {
+	name := strings.ToLower(linkType)
+	if depType, ok := config.LinkMap[name]; ok {
+		return depType
+	}
+	if strings.Contains(name, "block") {
+		return "blocks"
+	}
+	return "related"
+}
+
+// LinkDependencies returns the dependencies implied by an issue's links, parent
+// and Epic Link. Jira reports each link on both of its issues; blocks links are
+// read from either end, so a partial pull still sees them, while symmetric
+// related links are only read from the outward end.
+func LinkDependencies(ji *Issue, config *MappingConfig) []DependencyInfo {
+	var deps []DependencyInfo
+	for _, link := range ji.Fields.IssueLinks {
+		depType := LinkToBeadsDep(link.Type.Name, config)
+		switch {
+		case link.OutwardIssue != nil:
+			other := link.OutwardIssue.Key
+			if depType == "blocks" {
+				// This issue blocks the other one
+				deps = append(deps, DependencyInfo{FromKey: other, ToKey: ji.Key, Type: depType})
+			} else {
+				deps = append(deps, DependencyInfo{FromKey: ji.Key, ToKey: other, Type: depType})
+			}
+		case link.InwardIssue != nil:
+			other := link.InwardIssue.Key
+			switch depType {
+			case "blocks":
+				// The other issue blocks this one
+				deps = append(deps, DependencyInfo{FromKey: ji.Key, ToKey: other, Type: depType})
+			case "related":
+			default:
+				deps = append(deps, DependencyInfo{FromKey: other, ToKey: ji.Key, Type: depType})
+			}
+		}
+	}
+
+	if ji.Fields.Parent != nil && ji.Fields.Parent.Key != "" {
+		deps = append(deps, DependencyInfo{FromKey: ji.Key, ToKey: ji.Fields.Parent.Key, Type: "parent-child"})
+	} else if epic := ji.Fields.EpicKey(); epic != "" {
+		deps = append(deps, DependencyInfo{FromKey: ji.Key, ToKey: epic, Type: "parent-child"})
+	}
+	return deps
+}
+
+// IssueToBeads converts a Jira issue from the site at siteURL to a Beads issue.
+func IssueToBeads(ji *Issue, siteURL string, config *MappingConfig) *IssueConversion {
+	fields := &ji.Fields
+
+	createdAt, err := ParseTimestamp(fields.Created)
+	if err != nil {
+		createdAt = time.Now()
+	}
+	updatedAt, err := ParseTimestamp(fields.Updated)
+	if err != nil {
+		updatedAt = time.Now()
+	}
+
+	issue := &types.Issue{
+		Title:       fields.Summary,
+		Description: strings.TrimSpace(fields.DescriptionText()),
+		Priority:    PriorityToBeads(fields.Priority, config),
+		IssueType:   TypeToBeads(fields.IssueType, config),
+		Status:      StatusToBeads(fields.Status, config),
+		Assignee:    userName(fields.Assignee),
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+
+	if issue.Status == types.StatusClosed {
+		closedAt := updatedAt
+		if resolved, err := ParseTimestamp(fields.ResolutionDate); err == nil {
+			closedAt = resolved
+		}
+		issue.ClosedAt = &closedAt
+	}
+
+	for _, label := range fields.Labels {
+		if label != "" {
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+
+	externalRef := strings.TrimSuffix(siteURL, "/") + "/browse/" + ji.Key
+	issue.ExternalRef = &externalRef
+
+	return &IssueConversion{
+		Issue:        issue,
+		Dependencies: LinkDependencies(ji, config),
+	}
+}
+
+// userName returns the name beads records for a Jira user.
+func userName(user *User) string {
+	if user == nil {
+		return ""
+	}
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Name
+}
+
+// BuildJiraFields returns the fields to set on an existing Jira issue.
+// Issue type and assignee are not pushed: Jira often refuses type changes
+// outside its move wizard, and Cloud assigns by account ID. Status is pushed
+// separately as a workflow transition.
+func BuildJiraFields(issue *types.Issue, config *MappingConfig, apiVersion string) map[string]interface{} {
+	labels := make([]string, 0, len(issue.Labels))
+	labels = append(labels, issue.Labels...)
+
+	fields := map[string]interface{}{
+		"summary":  issue.Title,
+		"priority": map[string]string{"name": PriorityToJira(issue.Priority, config)},
+		"labels":   labels,
+	}
+	if apiVersion == "2" {
+		fields["description"] = issue.Description
+	} else {
+		fields["description"] = TextToADF(issue.Description)
+	}
+	return fields
+}
+
+// BuildJiraCreateFields returns the fields for creating a Jira issue in project.
+func BuildJiraCreateFields(issue *types.Issue, project string, config *MappingConfig, apiVersion string) map[string]interface{} {
+	fields := BuildJiraFields(issue, config, apiVersion)
+	fields["project"] = map[string]string{"key": project}
+	fields["issuetype"] = map[string]string{"name": TypeToJira(issue.IssueType, config)}
+	return fields
+}
+
+// NormalizeIssueForJiraHash returns the parts of an issue that survive a push
+// and pull through Jira, mapped the way a pull would see them. Issue type and
+// assignee are left out because they are not pushed.
+func NormalizeIssueForJiraHash(issue *types.Issue, config *MappingConfig) *types.Issue {
+	normalized := &types.Issue{
+		Title:       issue.Title,
+		Description: strings.TrimSpace(issue.Description),
+		Priority:    PriorityToBeads(&Priority{Name: PriorityToJira(issue.Priority, config)}, config),
+		Status:      StatusToBeads(&Status{Name: StatusToJira(issue.Status, config)}, config),
+	}
+	if issue.ExternalRef != nil {
+		externalRef := *issue.ExternalRef
+		normalized.ExternalRef = &externalRef
+	}
+	normalized.Labels = append([]string(nil), issue.Labels...)
+	sort.Strings(normalized.Labels)
+	return normalized
+}
+
+// ContentHash returns a hash of the synced content of an issue, including labels.
+func ContentHash(issue *types.Issue, config *MappingConfig) string {
+	normalized := NormalizeIssueForJiraHash(issue, config)
+	h := sha256.New()
+	h.Write([]byte(normalized.ComputeContentHash()))
+	for _, label := range normalized.Labels {
+		h.Write([]byte{0})
+		h.Write([]byte(label))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BuildJiraToLocalUpdates creates an updates map from a Jira issue to apply
+// to a local Beads issue. This is used when Jira wins a conflict.
+func BuildJiraToLocalUpdates(ji *Issue, siteURL string, config *MappingConfig) map[string]interface{} {
+	converted := IssueToBeads(ji, siteURL, config).Issue
+	return map[string]interface{}{
+		"title":       converted.Title,
+		"description": converted.Description,
+		"priority":    converted.Priority,
+		"issue_type":  string(converted.IssueType),
+		"status":      string(converted.Status),
+		"assignee":    converted.Assignee,
+	}
+}
+
+// ParseTimestamp parses Jira's timestamp format into a time.Time.
+// Jira uses ISO 8601 with timezone: 2024-01-15T10:30:00.000+0000 or 2024-01-15T10:30:00.000Z
+func ParseTimestamp(ts string) (time.Time, error) {
+	if ts == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+
+	formats := []string{
+		"2006-01-02T15:04:05.000-0700",
+		"2006-01-02T15:04:05.000Z",
+		"2006-01-02T15:04:05-0700",
+		"2006-01-02T15:04:05Z",
+		time.RFC3339,
+		time.RFC3339Nano,
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, ts); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized timestamp format: %s", ts)
+}
+
+// ADFToText converts an Atlassian Document Format node to plain text with
+// light markdown for headings, lists, code blocks and quotes.
+func ADFToText(node *ADFNode) string {
+	if node == nil {
+		return ""
+	}
+	if node.Type == "text" {
+		return node.Text
+	}
+
+	var children strings.Builder
+	for i := range node.Content {
+		children.WriteString(ADFToText(&node.Content[i]))
+	}
+	text := children.String()
+
+	switch node.Type {
+	case "doc":
+		return strings.TrimSpace(text)
+	case "paragraph":
+		return text + "\n\n"
+	case "heading":
+		level := 1
+		if l, ok := node.Attrs["level"].(float64); ok {
+			level = int(l)
+		}
+		return strings.Repeat("#", level) + " " + text + "\n\n"
+	case "listItem":
+		return "- " + strings.TrimSpace(text) + "\n"
+	case "codeBlock":
+		lang, _ := node.Attrs["language"].(string)
+		return "```" + lang + "\n" + text + "```\n\n"
+	case "blockquote":
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		for i, line := range lines {
+			lines[i] = "> " + line
+		}
+		return strings.Join(lines, "\n") + "\n\n"
+	case "hardBreak":
+		return "\n"
+	case "rule":
+		return "---\n\n"
+	case "inlineCard":
+		u, _ := node.Attrs["url"].(string)
+		return u
+	case "mention":
+		name, _ := node.Attrs["text"].(string)
+		return "@" + strings.TrimPrefix(name, "@")
+	default:
+		return text
+	}
+}
+
+// TextToADF wraps plain text in an ADF document: blank lines separate
+// paragraphs and single newlines become hard breaks. ADFToText reverses it.
+func TextToADF(text string) *ADFNode {
+	doc := &ADFNode{Version: 1, Type: "doc", Content: []ADFNode{}}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return doc
+	}
+
+	for _, block := range strings.Split(text, "\n\n") {
+		paragraph := ADFNode{Type: "paragraph", Content: []ADFNode{}}
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, ADFNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, ADFNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+	return doc
+}
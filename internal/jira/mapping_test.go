@@ -0,0 +1,280 @@
+package jira
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestIssueToBeads(t *testing.T) {
+	ji := &Issue{
+		Key: "PROJ-42",
+		Fields: IssueFields{
+			Summary:        "Crash on start",
+			Description:    json.RawMessage(`{"version": 1, "type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Stack trace attached"}]}]}`),
+			Status:         &Status{Name: "Won't Fix"},
+			Priority:       &Priority{Name: "Major"},
+			IssueType:      &IssueType{Name: "Defect"},
+			Assignee:       &User{AccountID: "abc", DisplayName: "Ada Lovelace"},
+			Labels:         []string{"ui", ""},
+			Created:        "2025-03-01T09:00:00.000+0000",
+			Updated:        "2025-03-02T09:00:00.000+0000",
+			ResolutionDate: "2025-03-02T08:00:00.000+0000",
+		},
+	}
+
+	issue := IssueToBeads(ji, "https://acme.atlassian.net/", DefaultMappingConfig()).Issue
+	if issue.Title != "Crash on start" || issue.Description != "Stack trace attached" {
+		t.Errorf("title/description = %q/%q", issue.Title, issue.Description)
+	}
+	if issue.Priority != 1 || issue.IssueType != types.TypeBug || issue.Assignee != "Ada Lovelace" {
+		t.Errorf("priority/type/assignee = %d/%s/%q", issue.Priority, issue.IssueType, issue.Assignee)
+	}
+	wantClosed := time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)
+	if issue.Status != types.StatusClosed || issue.ClosedAt == nil || !issue.ClosedAt.Equal(wantClosed) {
+		t.Errorf("status = %s, closed_at = %v", issue.Status, issue.ClosedAt)
+	}
+	if !slices.Equal(issue.Labels, []string{"ui"}) {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+	if issue.ExternalRef == nil || *issue.ExternalRef != "https://acme.atlassian.net/browse/PROJ-42" {
+		t.Errorf("external_ref = %v", issue.ExternalRef)
+	}
+
+	// v2 descriptions are plain strings; unknown statuses use their category
+	ji.Fields.Description = json.RawMessage(`"Plain *wiki* text"`)
+	ji.Fields.Status = &Status{Name: "Selected for Development", StatusCategory: &StatusCategory{Key: "indeterminate"}}
+	issue = IssueToBeads(ji, "https://jira.example.com", DefaultMappingConfig()).Issue
+	if issue.Description != "Plain *wiki* text" || issue.Status != types.StatusInProgress || issue.ClosedAt != nil {
+		t.Errorf("v2 issue = %q/%s/%v", issue.Description, issue.Status, issue.ClosedAt)
+	}
+
+	plain := IssueToBeads(&Issue{Key: "PROJ-1"}, "https://jira.example.com", DefaultMappingConfig()).Issue
+	if plain.Priority != 2 || plain.IssueType != types.TypeTask || plain.Status != types.StatusOpen {
+		t.Errorf("defaults = %d/%s/%s", plain.Priority, plain.IssueType, plain.Status)
+	}
+}
+
+func TestLinkDependencies(t *testing.T) {
+	ji := &Issue{
+		Key: "PROJ-2",
+		Fields: IssueFields{
+			IssueLinks: []IssueLink{
+				{Type: IssueLinkType{Name: "Blocks"}, OutwardIssue: &LinkedIssue{Key: "PROJ-3"}},
+				{Type: IssueLinkType{Name: "Blocks"}, InwardIssue: &LinkedIssue{Key: "PROJ-1"}},
+				{Type: IssueLinkType{Name: "Duplicate"}, InwardIssue: &LinkedIssue{Key: "PROJ-9"}},
+				{Type: IssueLinkType{Name: "Relates"}, OutwardIssue: &LinkedIssue{Key: "PROJ-4"}},
+				{Type: IssueLinkType{Name: "Relates"}, InwardIssue: &LinkedIssue{Key: "PROJ-5"}},
+			},
+			Parent:   &LinkedIssue{Key: "PROJ-100"},
+			EpicLink: json.RawMessage(`"PROJ-200"`),
+		},
+	}
+
+	want := []DependencyInfo{
+		{FromKey: "PROJ-3", ToKey: "PROJ-2", Type: "blocks"},
+		{FromKey: "PROJ-2", ToKey: "PROJ-1", Type: "blocks"},
+		{FromKey: "PROJ-9", ToKey: "PROJ-2", Type: "duplicates"},
+		{FromKey: "PROJ-2", ToKey: "PROJ-4", Type: "related"},
+		{FromKey: "PROJ-2", ToKey: "PROJ-100", Type: "parent-child"},
+	}
+	if deps := LinkDependencies(ji, DefaultMappingConfig()); !slices.Equal(deps, want) {
+		t.Errorf("deps = %+v\nwant %+v", deps, want)
+	}
+
+	// Without a parent the Epic Link is used; non-string values are ignored
+	ji.Fields.IssueLinks = nil
+	ji.Fields.Parent = nil
+	if deps := LinkDependencies(ji, DefaultMappingConfig()); len(deps) != 1 || deps[0].ToKey != "PROJ-200" {
+		t.Errorf("epic deps = %+v", deps)
+	}
+	ji.Fields.EpicLink = json.RawMessage(`{"id": 7}`)
+	if deps := LinkDependencies(ji, DefaultMappingConfig()); len(deps) != 0 {
+		t.Errorf("deps from object epic link = %+v", deps)
+	}
+}
+
+func TestBuildJiraFields_RoundTrip(t *testing.T) {
+	config := DefaultMappingConfig()
+	externalRef := "https://acme.atlassian.net/browse/PROJ-7"
+	local := &types.Issue{
+		Title:       "Add export",
+		Description: "Export to CSV.\nAlso TSV.\n\nSee the spec.",
+		Priority:    4,
+		IssueType:   types.TypeChore,
+		Status:      types.StatusBlocked,
+		Assignee:    "someone",
+		Labels:      []string{"api", "data"},
+		ExternalRef: &externalRef,
+	}
+
+	fields := BuildJiraCreateFields(local, "PROJ", config, "3")
+	if fields["issuetype"].(map[string]string)["name"] != "Task" || fields["project"].(map[string]string)["key"] != "PROJ" {
+		t.Errorf("create fields = %+v", fields)
+	}
+	if fields["priority"].(map[string]string)["name"] != "Lowest" {
+		t.Errorf("priority = %v", fields["priority"])
+	}
+	if _, ok := BuildJiraFields(local, config, "3")["issuetype"]; ok {
+		t.Error("update fields change the issue type")
+	}
+
+	// Pulling the pushed issue back must not register as a change
+	description, err := json.Marshal(fields["description"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &Issue{
+		Key: "PROJ-7",
+		Fields: IssueFields{
+			Summary:     fields["summary"].(string),
+			Description: description,
+			Status:      &Status{Name: StatusToJira(local.Status, config)},
+			Priority:    &Priority{Name: "Lowest"},
+			IssueType:   &IssueType{Name: "Task"},
+			Labels:      fields["labels"].([]string),
+		},
+	}
+	pulled := IssueToBeads(remote, "https://acme.atlassian.net", config).Issue
+	if pulled.Description != local.Description {
+		t.Errorf("ADF round trip: %q, want %q", pulled.Description, local.Description)
+	}
+	if ContentHash(local, config) != ContentHash(pulled, config) {
+		t.Errorf("round trip changed the issue:\nlocal  %+v\npulled %+v",
+			NormalizeIssueForJiraHash(local, config), NormalizeIssueForJiraHash(pulled, config))
+	}
+
+	pulled.Labels = append(pulled.Labels, "extra")
+	if ContentHash(local, config) == ContentHash(pulled, config) {
+		t.Error("ContentHash ignores labels")
+	}
+
+	// v2 sends the description as-is
+	if got := BuildJiraFields(local, config, "2")["description"]; got != local.Description {
+		t.Errorf("v2 description = %v", got)
+	}
+}
+
+func TestADFToText(t *testing.T) {
+	var doc ADFNode
+	raw := `{"type": "doc", "content": [
+		{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Steps"}]},
+		{"type": "bulletList", "content": [
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "one"}]}]},
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "two"}]}]}]},
+		{"type": "codeBlock", "attrs": {"language": "go"}, "content": [{"type": "text", "text": "x := 1\n"}]},
+		{"type": "paragraph", "content": [{"type": "mention", "attrs": {"text": "@ada"}}, {"type": "text", "text": " see "},
+			{"type": "inlineCard", "attrs": {"url": "https://example.com"}}]}]}`
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatal(err)
+	}
+	want := "## Steps\n\n- one\n- two\n```go\nx := 1\n```\n\n@ada see https://example.com"
+	if got := ADFToText(&doc); got != want {
+		t.Errorf("ADFToText = %q, want %q", got, want)
+	}
+
+	if empty := TextToADF("  "); empty.Version != 1 || len(empty.Content) != 0 {
+		t.Errorf("empty doc = %+v", empty)
+	}
+}
+
+func TestLoadMappingConfig(t *testing.T) {
+	config := LoadMappingConfig(mapLoader{
+		"jira.priority_map.P1":                     "0",
+		"jira.priority_map.not-a-level":            "high",
+		"jira.status_map.Selected for Development": "open",
+		"jira.type_map.Spike":                      "task",
+		"jira.link_map.Causes":                     "blocks",
+		"jira.reverse_type_map.task":               "Task",
+		"jira.reverse_priority_map.0":              "P1",
+	})
+	if config.PriorityMap["p1"] != 0 || config.LinkMap["causes"] != "blocks" {
+		t.Errorf("config = %+v", config)
+	}
+	if _, ok := config.PriorityMap["not-a-level"]; ok {
+		t.Error("non-numeric priority was loaded")
+	}
+	if StatusToBeads(&Status{Name: "selected for development"}, config) != types.StatusOpen {
+		t.Error("status_map not case-insensitive")
+	}
+	// Forward entries are inverted for push unless a reverse entry overrides them
+	if config.ReverseStatusMap["open"] != "Selected for Development" {
+		t.Errorf("reverse status = %q", config.ReverseStatusMap["open"])
+	}
+	if config.ReverseTypeMap["task"] != "Task" || PriorityToJira(0, config) != "P1" {
+		t.Errorf("reverse type/priority = %q/%q", config.ReverseTypeMap["task"], PriorityToJira(0, config))
+	}
+	if config.PriorityMap["minor"] != 3 || config.ReverseStatusMap["closed"] != "Done" {
+		t.Error("defaults lost")
+	}
+}
+
+type mapLoader map[string]string
+
+func (m mapLoader) GetAllConfig() (map[string]string, error) { return m, nil }
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		wantErr   bool
+		wantYear  int
+	}{
+		{
+			name:      "standard Jira Cloud format with milliseconds",
+			timestamp: "2024-01-15T10:30:00.000+0000",
+			wantErr:   false,
+			wantYear:  2024,
+		},
+		{
+			name:      "Jira format with Z suffix",
+			timestamp: "2024-01-15T10:30:00.000Z",
+			wantErr:   false,
+			wantYear:  2024,
+		},
+		{
+			name:      "without milliseconds",
+			timestamp: "2024-01-15T10:30:00+0000",
+			wantErr:   false,
+			wantYear:  2024,
+		},
+		{
+			name:      "RFC3339 format",
+			timestamp: "2024-01-15T10:30:00Z",
+			wantErr:   false,
+			wantYear:  2024,
+		},
+		{
+			name:      "empty string",
+			timestamp: "",
+			wantErr:   true,
+		},
+		{
+			name:      "invalid format",
+			timestamp: "not-a-timestamp",
+			wantErr:   true,
+		},
+		{
+			name:      "with negative timezone offset",
+			timestamp: "2024-06-15T10:30:00.000-0500",
+			wantErr:   false,
+			wantYear:  2024,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.timestamp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTimestamp(%q) error = %v, wantErr %v", tt.timestamp, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Year() != tt.wantYear {
+				t.Errorf("ParseTimestamp(%q) year = %d, want %d", tt.timestamp, got.Year(), tt.wantYear)
+			}
+		})
+	}
+}
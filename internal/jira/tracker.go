@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func init() {
+	tracker.Register("jira", newTracker)
+}
+
+// Tracker adapts a Jira project to tracker.Tracker.
+type Tracker struct {
+	client  *Client
+	project string
+	config  *MappingConfig
+}
+
+// NewTracker creates a tracker adapter for a Jira project.
+func NewTracker(client *Client, project string, config *MappingConfig) *Tracker {
+	return &Tracker{client: client, project: project, config: config}
+}
+
+// newTracker creates the adapter from jira.* config. The API token and
+// username fall back to JIRA_API_TOKEN and JIRA_USERNAME.
+func newTracker(ctx context.Context, cfg tracker.Config) (tracker.Tracker, error) {
+	siteURL := tracker.ConfigValue(ctx, cfg, "jira.url", "")
+	project := tracker.ConfigValue(ctx, cfg, "jira.project", "")
+	token := tracker.ConfigValue(ctx, cfg, "jira.api_token", "JIRA_API_TOKEN")
+	switch {
+	case siteURL == "":
+		return nil, fmt.Errorf("jira.url not configured")
+	case project == "":
+		return nil, fmt.Errorf("jira.project not configured")
+	case token == "":
+		return nil, fmt.Errorf("Jira API token not configured (jira.api_token or JIRA_API_TOKEN)")
+	}
+	username := tracker.ConfigValue(ctx, cfg, "jira.username", "JIRA_USERNAME")
+	apiVersion := tracker.ConfigValue(ctx, cfg, "jira.api_version", "")
+	client := NewClient(siteURL, username, token, apiVersion)
+	return NewTracker(client, project, LoadMappingConfig(tracker.MappingLoader(ctx, cfg))), nil
+}
+
+// Name returns "jira".
+func (t *Tracker) Name() string { return "jira" }
+
+// DisplayName returns "Jira".
+func (t *Tracker) DisplayName() string { return "Jira" }
+
+// Mapper returns the configured field mappings.
+func (t *Tracker) Mapper() tracker.FieldMapper { return fieldMapper{t.config} }
+
+// CanonicalRef accepts browse URLs on the configured site.
+func (t *Tracker) CanonicalRef(ref string) (string, bool) {
+	key := t.issueKey(ref)
+	if key == "" {
+		return "", false
+	}
+	return t.client.IssueURL(key), true
+}
+
+// issueKey extracts the issue key from a browse URL on the configured site.
+func (t *Tracker) issueKey(ref string) string {
+	rest, ok := strings.CutPrefix(ref, t.client.URL+"/browse/")
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// FetchIssues searches the project, newest changes included.
+func (t *Tracker) FetchIssues(ctx context.Context, opts tracker.FetchOptions) ([]*tracker.RemoteIssue, error) {
+	state := opts.State
+	if state == "" {
+		state = "all"
+	}
+	issues, err := t.client.SearchIssues(ctx, BuildSearchJQL(t.project, state, opts.Since))
+	if err != nil {
+		return nil, err
+	}
+	remotes := make([]*tracker.RemoteIssue, 0, len(issues))
+	for i := range issues {
+		remotes = append(remotes, t.remote(&issues[i]))
+	}
+	return remotes, nil
+}
+
+// CreateIssue creates a Jira issue and moves it to the mapped status.
+func (t *Tracker) CreateIssue(ctx context.Context, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	created, err := t.client.CreateIssue(ctx, BuildJiraCreateFields(issue, t.project, t.config, t.client.APIVersion))
+	if err != nil {
+		return nil, err
+	}
+	return t.transitionAndFetch(ctx, created.Key, issue.Status)
+}
+
+// UpdateIssue overwrites a Jira issue's fields and moves it to the mapped
+// status.
+func (t *Tracker) UpdateIssue(ctx context.Context, ref string, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	key := t.issueKey(ref)
+	if key == "" {
+		return nil, fmt.Errorf("not a Jira issue URL: %s", ref)
+	}
+	if err := t.client.UpdateIssue(ctx, key, BuildJiraFields(issue, t.config, t.client.APIVersion)); err != nil {
+		return nil, err
+	}
+	return t.transitionAndFetch(ctx, key, issue.Status)
+}
+
+// transitionAndFetch moves an issue to the Jira status mapped from status,
+// if it isn't there already, and returns the issue as Jira now stores it.
+func (t *Tracker) transitionAndFetch(ctx context.Context, key string, status types.Status) (*tracker.RemoteIssue, error) {
+	ji, err := t.client.GetIssue(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if StatusToBeads(ji.Fields.Status, t.config) == status {
+		return t.remote(ji), nil
+	}
+
+	target := StatusToJira(status, t.config)
+	moved, err := t.client.TransitionToStatus(ctx, key, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transition %s to %q: %w", key, target, err)
+	}
+	if !moved {
+		// The fields were saved; the returned status shows the mismatch
+		return t.remote(ji), nil
+	}
+	if ji, err = t.client.GetIssue(ctx, key); err != nil {
+		return nil, err
+	}
+	return t.remote(ji), nil
+}
+
+// remote converts a Jira issue for the sync engine.
+func (t *Tracker) remote(ji *Issue) *tracker.RemoteIssue {
+	conversion := IssueToBeads(ji, t.client.URL, t.config)
+	remote := &tracker.RemoteIssue{
+		Ref:   t.client.IssueURL(ji.Key),
+		Key:   ji.Key,
+		Issue: conversion.Issue,
+	}
+	for _, dep := range conversion.Dependencies {
+		remote.Dependencies = append(remote.Dependencies, tracker.Dependency{
+			FromRef: t.client.IssueURL(dep.FromKey),
+			ToRef:   t.client.IssueURL(dep.ToKey),
+			Type:    types.DependencyType(dep.Type),
+		})
+	}
+	return remote
+}
+
+// fieldMapper maps fields by Jira status, priority and issue type names.
+type fieldMapper struct {
+	config *MappingConfig
+}
+
+func (m fieldMapper) StatusToTracker(status types.Status) string {
+	return StatusToJira(status, m.config)
+}
+
+func (m fieldMapper) StatusToBeads(status string) types.Status {
+	return StatusToBeads(&Status{Name: status}, m.config)
+}
+
+func (m fieldMapper) PriorityToTracker(priority int) string {
+	return PriorityToJira(priority, m.config)
+}
+
+func (m fieldMapper) PriorityToBeads(priority string) int {
+	return PriorityToBeads(&Priority{Name: priority}, m.config)
+}
+
+func (m fieldMapper) TypeToTracker(issueType types.IssueType) string {
+	return TypeToJira(issueType, m.config)
+}
+
+func (m fieldMapper) TypeToBeads(issueType string) types.IssueType {
+	return TypeToBeads(&IssueType{Name: issueType}, m.config)
+}
@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// mapConfig is an in-memory tracker.Config.
+type mapConfig map[string]string
+
+func (m mapConfig) GetConfig(_ context.Context, key string) (string, error) { return m[key], nil }
+func (m mapConfig) GetAllConfig(_ context.Context) (map[string]string, error) {
+	return m, nil
+}
+
+func TestTrackerRegistered(t *testing.T) {
+	t.Setenv("JIRA_API_TOKEN", "")
+	ctx := context.Background()
+
+	if _, err := tracker.New(ctx, "jira", mapConfig{"jira.url": "https://acme.atlassian.net"}); err == nil {
+		t.Error("expected an error without jira.project")
+	}
+	trk, err := tracker.New(ctx, "jira", mapConfig{
+		"jira.url":       "https://acme.atlassian.net",
+		"jira.project":   "PROJ",
+		"jira.api_token": "t",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if ref, ok := trk.CanonicalRef("https://acme.atlassian.net/browse/PROJ-7?focusedCommentId=1"); !ok || ref != "https://acme.atlassian.net/browse/PROJ-7" {
+		t.Errorf("CanonicalRef = %q, %v", ref, ok)
+	}
+	if _, ok := trk.CanonicalRef("https://other.atlassian.net/browse/PROJ-7"); ok {
+		t.Error("accepted a ref on another site")
+	}
+}
+
+func TestTrackerUpdateIssue_Transitions(t *testing.T) {
+	status := "To Do"
+	var transitioned bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/PROJ-7":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-7":
+			_, _ = w.Write([]byte(`{"id": "10001", "key": "PROJ-7", "fields": {"summary": "Renamed",
+				"status": {"name": "` + status + `"}, "priority": {"name": "Medium"}, "issuetype": {"name": "Task"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-7/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "31", "name": "Finish", "to": {"name": "Done"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-7/transitions":
+			transitioned = true
+			status = "Done"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	trk := NewTracker(NewClient(server.URL, "", "t", "2"), "PROJ", DefaultMappingConfig())
+	remote, err := trk.UpdateIssue(context.Background(), server.URL+"/browse/PROJ-7", &types.Issue{
+		Title: "Renamed", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask,
+	})
+	if err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if !transitioned {
+		t.Error("issue was not transitioned")
+	}
+	if remote.Key != "PROJ-7" || remote.Ref != server.URL+"/browse/PROJ-7" || remote.Issue.Status != types.StatusClosed {
+		t.Errorf("remote = %+v, issue = %+v", remote, remote.Issue)
+	}
+
+	if _, err := trk.UpdateIssue(context.Background(), "https://example.com/PROJ-7", &types.Issue{}); err == nil {
+		t.Error("expected an error for a foreign ref")
+	}
+}
+
+func TestTrackerMapperRoundTrip(t *testing.T) {
+	m := NewTracker(NewClient("https://acme.atlassian.net", "", "t", ""), "PROJ", DefaultMappingConfig()).Mapper()
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusClosed} {
+		if got := m.StatusToBeads(m.StatusToTracker(status)); got != status {
+			t.Errorf("status %q round-trips to %q", status, got)
+		}
+	}
+	for p := 0; p <= 4; p++ {
+		if got := m.PriorityToBeads(m.PriorityToTracker(p)); got != p {
+			t.Errorf("priority %d round-trips to %d", p, got)
+		}
+	}
+	for _, it := range []types.IssueType{types.TypeBug, types.TypeFeature, types.TypeEpic, types.TypeTask} {
+		if got := m.TypeToBeads(m.TypeToTracker(it)); got != it {
+			t.Errorf("type %q round-trips to %q", it, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package jira provides client and data types for the Jira REST API.
+//
+// The client speaks REST API v3 (Jira Cloud, where descriptions are Atlassian
+// Document Format) and v2 (Jira Server and Data Center, where descriptions are
+// plain wiki text). Cloud authenticates with an email address and API token;
+// Server accepts a username and password or a personal access token.
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// API configuration constants.
+const (
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// MaxRetries is the maximum number of retries for rate-limited requests.
+	MaxRetries = 3
+
+	// RetryDelay is the base delay between retries (exponential backoff).
+	RetryDelay = time.Second
+
+	// MaxPageSize is the maximum number of issues to fetch per search page.
+	MaxPageSize = 100
+
+	// MaxPages is the maximum number of pages to fetch before stopping pagination.
+	// This prevents infinite loops from malformed API responses.
+	MaxPages = 1000
+
+	// UserAgent identifies beads to the Jira API.
+	UserAgent = "bd-jira-sync/1.0"
+)
+
+// Client provides methods to interact with the Jira REST API.
+type Client struct {
+	URL        string       // Site root (e.g., "https://company.atlassian.net")
+	Username   string       // Email (Cloud) or username (Server); empty means token is a PAT
+	Token      string       // API token, password or personal access token
+	APIVersion string       // "3" for Cloud, "2" for Server/Data Center
+	HTTPClient *http.Client // Optional custom HTTP client
+}
+
+// Issue represents an issue from the Jira API.
+type Issue struct {
+	ID     string      `json:"id"`
+	Key    string      `json:"key"` // e.g., "PROJ-123"
+	Self   string      `json:"self"`
+	Fields IssueFields `json:"fields"`
+}
+
+// IssueFields holds the issue fields beads reads.
+type IssueFields struct {
+	Summary        string          `json:"summary"`
+	Description    json.RawMessage `json:"description"` // string in v2, ADF document in v3
+	Status         *Status         `json:"status"`
+	Priority       *Priority       `json:"priority"`
+	IssueType      *IssueType      `json:"issuetype"`
+	Assignee       *User           `json:"assignee"`
+	Reporter       *User           `json:"reporter"`
+	Labels         []string        `json:"labels"`
+	IssueLinks     []IssueLink     `json:"issuelinks"`
+	Parent         *LinkedIssue    `json:"parent"`
+	EpicLink       json.RawMessage `json:"customfield_10014"` // Epic Link on classic projects
+	Created        string          `json:"created"`
+	Updated        string          `json:"updated"`
+	ResolutionDate string          `json:"resolutiondate"`
+}
+
+// DescriptionText returns the description as plain text, converting ADF if needed.
+func (f *IssueFields) DescriptionText() string {
+	raw := strings.TrimSpace(string(f.Description))
+	if raw == "" || raw == "null" {
+		return ""
+	}
+	if strings.HasPrefix(raw, `"`) {
+		var s string
+		if err := json.Unmarshal(f.Description, &s); err == nil {
+			return s
+		}
+		return ""
+	}
+	var node ADFNode
+	if err := json.Unmarshal(f.Description, &node); err != nil {
+		return ""
+	}
+	return ADFToText(&node)
+}
+
+// EpicKey returns the Epic Link field value, if it holds an issue key.
+func (f *IssueFields) EpicKey() string {
+	var key string
+	if len(f.EpicLink) == 0 || json.Unmarshal(f.EpicLink, &key) != nil {
+		return ""
+	}
+	return key
+}
+
+// Status represents a workflow status.
+type Status struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	StatusCategory *StatusCategory `json:"statusCategory"`
+}
+
+// StatusCategory groups statuses into "new", "indeterminate" and "done".
+type StatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Priority represents an issue priority.
+type Priority struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IssueType represents an issue type.
+type IssueType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Subtask bool   `json:"subtask"`
+}
+
+// User represents a Jira user. Cloud identifies users by AccountID,
+// Server by Name.
+type User struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// IssueLink represents a link between two issues. Exactly one of InwardIssue
+// and OutwardIssue is set, naming the issue at the other end.
+type IssueLink struct {
+	ID           string        `json:"id"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue"`
+}
+
+// IssueLinkType describes a link type, e.g. Name "Blocks" with
+// Inward "is blocked by" and Outward "blocks".
+type IssueLinkType struct {
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// LinkedIssue is the minimal issue reference used in links and parents.
+type LinkedIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// Transition represents a workflow transition available on an issue.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   Status `json:"to"`
+}
+
+// ADFNode is a node of an Atlassian Document Format document.
+type ADFNode struct {
+	Version int                    `json:"version,omitempty"` // 1 on the root "doc" node
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []ADFNode              `json:"content,omitempty"`
+}
+
+// searchPage is one page of search results. v2 pages by startAt and total;
+// v3's /search/jql pages by nextPageToken and isLast.
+type searchPage struct {
+	Issues        []Issue `json:"issues"`
+	StartAt       int     `json:"startAt"`
+	Total         int     `json:"total"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+}
+
+// transitionsResponse is the response of GET issue/{key}/transitions.
+type transitionsResponse struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+// IssueConversion holds the result of converting a Jira issue to Beads.
+// It includes the issue and any dependencies that should be created.
+type IssueConversion struct {
+	Issue        *types.Issue
+	Dependencies []DependencyInfo
+}
+
+// DependencyInfo represents a dependency to be created after issue import.
+// Stored separately since we need all issues imported before linking dependencies.
+type DependencyInfo struct {
+	FromKey string // Jira key of the dependent issue
+	ToKey   string // Jira key of the dependency target
+	Type    string // Beads dependency type (blocks, related, duplicates, parent-child)
+}
+
+// APIError is returned for non-2xx API responses.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s (status %d)", e.Message, e.StatusCode)
+}
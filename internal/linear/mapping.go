@@ -5,16 +5,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/steveyegge/fastbeads/internal/idgen"
+	"github.com/steveyegge/fastbeads/internal/tracker"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
 // IDGenerationOptions configures Linear hash ID generation.
-type IDGenerationOptions struct {
-	BaseLength int             // Starting hash length (3-8)
-	MaxLength  int             // Maximum hash length (3-8)
-	UsedIDs    map[string]bool // Pre-populated set to avoid collisions (e.g., DB IDs)
-}
+type IDGenerationOptions = tracker.IDGenerationOptions
 
 // BuildLinearDescription formats a Beads issue for Linear's description field.
 // This mirrors the payload used during push to keep hash comparisons consistent.
@@ -49,74 +45,9 @@ func NormalizeIssueForLinearHash(issue *types.Issue) *types.Issue {
 }
 
 // GenerateIssueIDs generates unique hash-based IDs for issues that don't have one.
-// Tracks used IDs to prevent collisions within the batch (and optionally against existing IDs).
-// The creator parameter is used as part of the hash input (e.g., "linear-import").
+// See tracker.GenerateIssueIDs.
 func GenerateIssueIDs(issues []*types.Issue, prefix, creator string, opts IDGenerationOptions) error {
-	usedIDs := opts.UsedIDs
-	if usedIDs == nil {
-		usedIDs = make(map[string]bool)
-	}
-
-	baseLength := opts.BaseLength
-	if baseLength == 0 {
-		baseLength = 6
-	}
-	maxLength := opts.MaxLength
-	if maxLength == 0 {
-		maxLength = 8
-	}
-	if baseLength < 3 {
-		baseLength = 3
-	}
-	if maxLength > 8 {
-		maxLength = 8
-	}
-	if baseLength > maxLength {
-		baseLength = maxLength
-	}
-
-	// First pass: record existing IDs
-	for _, issue := range issues {
-		if issue.ID != "" {
-			usedIDs[issue.ID] = true
-		}
-	}
-
-	// Second pass: generate IDs for issues without one
-	for _, issue := range issues {
-		if issue.ID != "" {
-			continue // Already has an ID
-		}
-
-		var generated bool
-		for length := baseLength; length <= maxLength && !generated; length++ {
-			for nonce := 0; nonce < 10; nonce++ {
-				candidate := idgen.GenerateHashID(
-					prefix,
-					issue.Title,
-					issue.Description,
-					creator,
-					issue.CreatedAt,
-					length,
-					nonce,
-				)
-
-				if !usedIDs[candidate] {
-					issue.ID = candidate
-					usedIDs[candidate] = true
-					generated = true
-					break
-				}
-			}
-		}
-
-		if !generated {
-			return fmt.Errorf("failed to generate unique ID for issue '%s' after trying lengths %d-%d with 10 nonces each",
-				issue.Title, baseLength, maxLength)
-		}
-	}
-
-	return nil
+	return tracker.GenerateIssueIDs(issues, prefix, creator, opts)
 }
 
 // MappingConfig holds configurable mappings between Linear and Beads.
@@ -0,0 +1,198 @@
+package linear
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func init() {
+	tracker.Register("linear", newTracker)
+}
+
+// Tracker adapts a Linear team (optionally one project) to tracker.Tracker.
+type Tracker struct {
+	client *Client
+	config *MappingConfig
+	states *StateCache // Loaded on first push
+}
+
+// NewTracker creates a tracker adapter for a Linear team.
+func NewTracker(client *Client, config *MappingConfig) *Tracker {
+	return &Tracker{client: client, config: config}
+}
+
+// newTracker creates the adapter from linear.* config. The API key and team
+// fall back to LINEAR_API_KEY and LINEAR_TEAM_ID.
+func newTracker(ctx context.Context, cfg tracker.Config) (tracker.Tracker, error) {
+	apiKey := tracker.ConfigValue(ctx, cfg, "linear.api_key", "LINEAR_API_KEY")
+	teamID := tracker.ConfigValue(ctx, cfg, "linear.team_id", "LINEAR_TEAM_ID")
+	switch {
+	case apiKey == "":
+		return nil, fmt.Errorf("Linear API key not configured (linear.api_key or LINEAR_API_KEY)")
+	case teamID == "":
+		return nil, fmt.Errorf("linear.team_id not configured")
+	}
+	client := NewClient(apiKey, teamID)
+	if endpoint := tracker.ConfigValue(ctx, cfg, "linear.api_endpoint", ""); endpoint != "" {
+		client = client.WithEndpoint(endpoint)
+	}
+	if projectID := tracker.ConfigValue(ctx, cfg, "linear.project_id", ""); projectID != "" {
+		client = client.WithProjectID(projectID)
+	}
+	return NewTracker(client, LoadMappingConfig(tracker.MappingLoader(ctx, cfg))), nil
+}
+
+// Name returns "linear".
+func (t *Tracker) Name() string { return "linear" }
+
+// DisplayName returns "Linear".
+func (t *Tracker) DisplayName() string { return "Linear" }
+
+// Mapper returns the configured field mappings.
+func (t *Tracker) Mapper() tracker.FieldMapper { return fieldMapper{t.config} }
+
+// CanonicalRef accepts Linear issue URLs.
+func (t *Tracker) CanonicalRef(ref string) (string, bool) {
+	return CanonicalizeLinearExternalRef(ref)
+}
+
+// FetchIssues lists the team's issues. Parents and relations become
+// dependencies.
+func (t *Tracker) FetchIssues(ctx context.Context, opts tracker.FetchOptions) ([]*tracker.RemoteIssue, error) {
+	state := opts.State
+	if state == "" {
+		state = "all"
+	}
+	var issues []Issue
+	var err error
+	if opts.Since.IsZero() {
+		issues, err = t.client.FetchIssues(ctx, state)
+	} else {
+		issues, err = t.client.FetchIssuesSince(ctx, state, opts.Since)
+	}
+	if err != nil {
+		return nil, err
+	}
+	remotes := make([]*tracker.RemoteIssue, 0, len(issues))
+	for i := range issues {
+		remotes = append(remotes, t.remote(&issues[i]))
+	}
+	return remotes, nil
+}
+
+// CreateIssue creates a Linear issue in the state mapped from its status.
+func (t *Tracker) CreateIssue(ctx context.Context, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	states, err := t.stateCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	li, err := t.client.CreateIssue(ctx, issue.Title, BuildLinearDescription(issue),
+		PriorityToLinear(issue.Priority, t.config), states.FindStateForBeadsStatus(issue.Status), nil)
+	if err != nil {
+		return nil, err
+	}
+	return t.remote(li), nil
+}
+
+// UpdateIssue overwrites a Linear issue's title, description, priority and
+// state.
+func (t *Tracker) UpdateIssue(ctx context.Context, ref string, issue *types.Issue) (*tracker.RemoteIssue, error) {
+	identifier := ExtractLinearIdentifier(ref)
+	if identifier == "" {
+		return nil, fmt.Errorf("not a Linear issue URL: %s", ref)
+	}
+	existing, err := t.client.FetchIssueByIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("Linear issue %s not found", identifier)
+	}
+	states, err := t.stateCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"title":       issue.Title,
+		"description": BuildLinearDescription(issue),
+	}
+	if priority := PriorityToLinear(issue.Priority, t.config); priority > 0 {
+		payload["priority"] = priority
+	}
+	if stateID := states.FindStateForBeadsStatus(issue.Status); stateID != "" {
+		payload["stateId"] = stateID
+	}
+	li, err := t.client.UpdateIssue(ctx, existing.ID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return t.remote(li), nil
+}
+
+// stateCache returns the team's workflow states, fetching them once.
+func (t *Tracker) stateCache(ctx context.Context) (*StateCache, error) {
+	if t.states == nil {
+		states, err := BuildStateCache(ctx, t.client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch team states: %w", err)
+		}
+		t.states = states
+	}
+	return t.states, nil
+}
+
+// remote converts a Linear issue for the sync engine. Linked issues are
+// referenced by identifier, so their refs are built from this issue's URL.
+func (t *Tracker) remote(li *Issue) *tracker.RemoteIssue {
+	conversion := IssueToBeads(li, t.config)
+	issue := conversion.Issue.(*types.Issue)
+	ref := *issue.ExternalRef
+	remote := &tracker.RemoteIssue{Ref: ref, Key: li.Identifier, Issue: issue}
+
+	base := ref[:strings.LastIndex(ref, "/")+1]
+	for _, dep := range conversion.Dependencies {
+		remote.Dependencies = append(remote.Dependencies, tracker.Dependency{
+			FromRef: base + dep.FromLinearID,
+			ToRef:   base + dep.ToLinearID,
+			Type:    types.DependencyType(dep.Type),
+		})
+	}
+	return remote
+}
+
+// fieldMapper maps statuses to Linear state types, priorities to Linear's
+// 0-4 scale and types to the labels they are inferred from.
+type fieldMapper struct {
+	config *MappingConfig
+}
+
+func (m fieldMapper) StatusToTracker(status types.Status) string {
+	return StatusToLinearStateType(status)
+}
+
+func (m fieldMapper) StatusToBeads(status string) types.Status {
+	return StateToBeadsStatus(&State{Type: status}, m.config)
+}
+
+func (m fieldMapper) PriorityToTracker(priority int) string {
+	return strconv.Itoa(PriorityToLinear(priority, m.config))
+}
+
+func (m fieldMapper) PriorityToBeads(priority string) int {
+	p, _ := strconv.Atoi(priority)
+	return PriorityToBeads(p, m.config)
+}
+
+func (m fieldMapper) TypeToTracker(issueType types.IssueType) string {
+	return string(issueType)
+}
+
+func (m fieldMapper) TypeToBeads(issueType string) types.IssueType {
+	return LabelToIssueType(&Labels{Nodes: []Label{{Name: issueType}}}, m.config)
+}
@@ -0,0 +1,80 @@
+package linear
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/tracker"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// mapConfig is an in-memory tracker.Config.
+type mapConfig map[string]string
+
+func (m mapConfig) GetConfig(_ context.Context, key string) (string, error) { return m[key], nil }
+func (m mapConfig) GetAllConfig(_ context.Context) (map[string]string, error) {
+	return m, nil
+}
+
+func TestTrackerRegistered(t *testing.T) {
+	t.Setenv("LINEAR_API_KEY", "")
+	t.Setenv("LINEAR_TEAM_ID", "")
+	ctx := context.Background()
+
+	if _, err := tracker.New(ctx, "linear", mapConfig{"linear.api_key": "k"}); err == nil {
+		t.Error("expected an error without linear.team_id")
+	}
+	trk, err := tracker.New(ctx, "linear", mapConfig{"linear.api_key": "k", "linear.team_id": "team"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if trk.Name() != "linear" {
+		t.Errorf("Name() = %q", trk.Name())
+	}
+}
+
+func TestTrackerRemote_ParentDependency(t *testing.T) {
+	trk := NewTracker(NewClient("k", "team"), DefaultMappingConfig())
+	remote := trk.remote(&Issue{
+		ID:         "uuid-456",
+		Identifier: "PROJ-456",
+		Title:      "Child Issue",
+		URL:        "https://linear.app/team/issue/PROJ-456/child-issue",
+		Priority:   3,
+		State:      &State{Type: "unstarted", Name: "Todo"},
+		Parent:     &Parent{ID: "uuid-123", Identifier: "PROJ-123"},
+		CreatedAt:  "2024-01-15T10:00:00Z",
+		UpdatedAt:  "2024-01-16T12:00:00Z",
+	})
+
+	if remote.Ref != "https://linear.app/team/issue/PROJ-456" || remote.Key != "PROJ-456" {
+		t.Errorf("remote = %+v", remote)
+	}
+	want := tracker.Dependency{
+		FromRef: "https://linear.app/team/issue/PROJ-456",
+		ToRef:   "https://linear.app/team/issue/PROJ-123",
+		Type:    types.DepParentChild,
+	}
+	if len(remote.Dependencies) != 1 || remote.Dependencies[0] != want {
+		t.Errorf("Dependencies = %+v, want %+v", remote.Dependencies, want)
+	}
+}
+
+func TestTrackerMapperRoundTrip(t *testing.T) {
+	m := NewTracker(NewClient("k", "team"), DefaultMappingConfig()).Mapper()
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusClosed} {
+		if got := m.StatusToBeads(m.StatusToTracker(status)); got != status {
+			t.Errorf("status %q round-trips to %q", status, got)
+		}
+	}
+	for p := 0; p <= 3; p++ {
+		if got := m.PriorityToBeads(m.PriorityToTracker(p)); got != p {
+			t.Errorf("priority %d round-trips to %d", p, got)
+		}
+	}
+	for _, it := range []types.IssueType{types.TypeBug, types.TypeFeature, types.TypeEpic, types.TypeTask} {
+		if got := m.TypeToBeads(m.TypeToTracker(it)); got != it {
+			t.Errorf("type %q round-trips to %q", it, got)
+		}
+	}
+}
@@ -166,55 +166,6 @@ type TeamResponse struct {
 	Team TeamStates `json:"team"`
 }
 
-// SyncStats tracks statistics for a Linear sync operation.
-type SyncStats struct {
-	Pulled    int `json:"pulled"`
-	Pushed    int `json:"pushed"`
-	Created   int `json:"created"`
-	Updated   int `json:"updated"`
-	Skipped   int `json:"skipped"`
-	Errors    int `json:"errors"`
-	Conflicts int `json:"conflicts"`
-}
-
-// SyncResult represents the result of a Linear sync operation.
-type SyncResult struct {
-	Success  bool      `json:"success"`
-	Stats    SyncStats `json:"stats"`
-	LastSync string    `json:"last_sync,omitempty"`
-	Error    string    `json:"error,omitempty"`
-	Warnings []string  `json:"warnings,omitempty"`
-}
-
-// PullStats tracks pull operation statistics.
-type PullStats struct {
-	Created     int
-	Updated     int
-	Skipped     int
-	Incremental bool   // Whether this was an incremental sync
-	SyncedSince string // Timestamp we synced since (if incremental)
-}
-
-// PushStats tracks push operation statistics.
-type PushStats struct {
-	Created int
-	Updated int
-	Skipped int
-	Errors  int
-}
-
-// Conflict represents a conflict between local and Linear versions.
-// A conflict occurs when both the local and Linear versions have been modified
-// since the last sync.
-type Conflict struct {
-	IssueID           string    // Beads issue ID
-	LocalUpdated      time.Time // When the local version was last modified
-	LinearUpdated     time.Time // When the Linear version was last modified
-	LinearExternalRef string    // URL to the Linear issue
-	LinearIdentifier  string    // Linear issue identifier (e.g., "TEAM-123")
-	LinearInternalID  string    // Linear's internal UUID (for API updates)
-}
-
 // IssueConversion holds the result of converting a Linear issue to Beads.
 // It includes the issue and any dependencies that should be created.
 type IssueConversion struct {
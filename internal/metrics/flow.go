@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// flowOrder stacks statuses in a cumulative flow diagram from the bottom
+// up: finished work first, backlog last. Other statuses go between
+// deferred and open, alphabetically.
+var flowOrder = []types.Status{
+	types.StatusClosed,
+	types.StatusHooked,
+	types.StatusInProgress,
+	types.StatusBlocked,
+	types.StatusPinned,
+	types.StatusDeferred,
+}
+
+// FlowDay counts issues by status at the end of a day.
+type FlowDay struct {
+	Date   time.Time            `json:"date"`
+	Counts map[types.Status]int `json:"counts"`
+}
+
+// Flow is a cumulative flow diagram: daily status counts.
+type Flow struct {
+	Since    time.Time      `json:"since"`
+	Until    time.Time      `json:"until"`
+	Statuses []types.Status `json:"statuses"` // Every status seen, bottom of the stack first
+	Days     []*FlowDay     `json:"days"`
+}
+
+// BurndownDay is the state of an epic's work at the end of a day.
+type BurndownDay struct {
+	Date      time.Time `json:"date"`
+	Scope     int       `json:"scope"` // Issues created so far
+	Done      int       `json:"done"`
+	Remaining int       `json:"remaining"`
+	Ideal     *float64  `json:"ideal,omitempty"` // Straight line from the remaining count on the first day with scope to zero at the due date
+}
+
+// Burndown tracks an epic's remaining work day by day.
+type Burndown struct {
+	Since time.Time      `json:"since"`
+	Until time.Time      `json:"until"`
+	DueAt *time.Time     `json:"due_at,omitempty"`
+	Days  []*BurndownDay `json:"days"`
+}
+
+// CumulativeFlow replays the status history of issues and counts them by
+// status at the end of each day from since to until. Tombstoned issues are
+// left out from the day they were deleted.
+func CumulativeFlow(issues []*types.Issue, events []*types.Event, since, until time.Time) (*Flow, error) {
+	flow := &Flow{Days: []*FlowDay{}}
+	seen := make(map[types.Status]bool)
+	var err error
+	flow.Since, flow.Until, err = replayDays(issues, events, since, until, func(day time.Time, statuses []types.Status) {
+		counts := make(map[types.Status]int)
+		for _, status := range statuses {
+			if status == "" || status == types.StatusTombstone {
+				continue
+			}
+			counts[status]++
+			seen[status] = true
+		}
+		flow.Days = append(flow.Days, &FlowDay{Date: day, Counts: counts})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[types.Status]int, len(flowOrder))
+	for i, status := range flowOrder {
+		rank[status] = i + 1
+	}
+	rank[types.StatusOpen] = len(flowOrder) + 2
+	for status := range seen {
+		flow.Statuses = append(flow.Statuses, status)
+	}
+	sort.Slice(flow.Statuses, func(i, j int) bool {
+		ri, rj := rank[flow.Statuses[i]], rank[flow.Statuses[j]]
+		if ri == 0 {
+			ri = len(flowOrder) + 1
+		}
+		if rj == 0 {
+			rj = len(flowOrder) + 1
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return flow.Statuses[i] < flow.Statuses[j]
+	})
+	return flow, nil
+}
+
+// ComputeBurndown replays the status history of an epic's issues and
+// tracks its scope and remaining work at the end of each day from since to
+// until. With a due date, each day from the first one with any scope also
+// gets an ideal remaining count.
+func ComputeBurndown(issues []*types.Issue, events []*types.Event, since, until time.Time, dueAt *time.Time) (*Burndown, error) {
+	bd := &Burndown{DueAt: dueAt, Days: []*BurndownDay{}}
+	var err error
+	bd.Since, bd.Until, err = replayDays(issues, events, since, until, func(day time.Time, statuses []types.Status) {
+		d := &BurndownDay{Date: day}
+		for _, status := range statuses {
+			switch status {
+			case "", types.StatusTombstone:
+				continue
+			case types.StatusClosed:
+				d.Done++
+			}
+			d.Scope++
+		}
+		d.Remaining = d.Scope - d.Done
+		bd.Days = append(bd.Days, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The ideal line starts on the first day with any scope
+	first := 0
+	for first < len(bd.Days) && bd.Days[first].Scope == 0 {
+		first++
+	}
+	if dueAt == nil || first == len(bd.Days) || !dueAt.After(bd.Days[first].Date) {
+		return bd, nil
+	}
+	start := bd.Days[first].Date
+	remaining := float64(bd.Days[first].Remaining)
+	total := dueAt.Sub(start).Hours()
+	for _, d := range bd.Days[first:] {
+		elapsed := d.Date.AddDate(0, 0, 1).Sub(start).Hours()
+		ideal := 0.0
+		if elapsed < total {
+			ideal = round(remaining * (1 - elapsed/total))
+		}
+		d.Ideal = &ideal
+	}
+	return bd, nil
+}
+
+// replayDays calls fn for each day from since's day to until's day with
+// the status of every issue at the end of that day (or at until, for the
+// last one). Issues not yet created have an empty status. It returns the
+// normalized window.
+func replayDays(issues []*types.Issue, events []*types.Event, since, until time.Time, fn func(day time.Time, statuses []types.Status)) (time.Time, time.Time, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since = periodStart(since, PeriodDay)
+	if !until.After(since) {
+		return since, until, fmt.Errorf("window end %s is not after its start %s", until.Format(time.RFC3339), since.Format(time.RFC3339))
+	}
+
+	eventsByIssue := make(map[string][]*types.Event)
+	for _, e := range events {
+		eventsByIssue[e.IssueID] = append(eventsByIssue[e.IssueID], e)
+	}
+	timelines := make([][]transition, len(issues))
+	for i, issue := range issues {
+		timelines[i] = timeline(issue, eventsByIssue[issue.ID])
+	}
+
+	statuses := make([]types.Status, len(issues))
+	for day := since; day.Before(until); day = nextPeriod(day, PeriodDay) {
+		at := nextPeriod(day, PeriodDay).Add(-time.Nanosecond)
+		if at.After(until) {
+			at = until
+		}
+		for i, tl := range timelines {
+			statuses[i], _ = statusAt(tl, at)
+		}
+		fn(day, statuses)
+	}
+	return since, until, nil
+}
+
+// WriteCSV writes one row per day with a column per status.
+func (f *Flow) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"date"}
+	for _, status := range f.Statuses {
+		header = append(header, string(status))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, d := range f.Days {
+		row := []string{d.Date.Format("2006-01-02")}
+		for _, status := range f.Statuses {
+			row = append(row, strconv.Itoa(d.Counts[status]))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSV writes one row per day. The ideal column is empty on days
+// without an ideal count.
+func (b *Burndown) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "scope", "done", "remaining", "ideal"}); err != nil {
+		return err
+	}
+	for _, d := range b.Days {
+		ideal := ""
+		if d.Ideal != nil {
+			ideal = strconv.FormatFloat(*d.Ideal, 'f', -1, 64)
+		}
+		row := []string{d.Date.Format("2006-01-02"), strconv.Itoa(d.Scope), strconv.Itoa(d.Done), strconv.Itoa(d.Remaining), ideal}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
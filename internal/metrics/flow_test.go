@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestCumulativeFlow(t *testing.T) {
+	h := &history{}
+	h.issue("a", types.TypeTask, "",
+		at(0, 9), types.EventCreated, "open",
+		at(1, 9), types.EventStatusChanged, "in_progress",
+		at(2, 9), types.EventClosed, "")
+	h.issue("b", types.TypeTask, "",
+		at(1, 9), types.EventCreated, "open",
+		at(2, 9), types.EventStatusChanged, "blocked")
+
+	flow, err := CumulativeFlow(h.issues, h.events, at(0, 12), at(3, 0))
+	if err != nil {
+		t.Fatalf("CumulativeFlow: %v", err)
+	}
+	if !flow.Since.Equal(monday) {
+		t.Errorf("Since = %v, want the start of the day %v", flow.Since, monday)
+	}
+	want := []types.Status{types.StatusClosed, types.StatusInProgress, types.StatusBlocked, types.StatusOpen}
+	if !reflect.DeepEqual(flow.Statuses, want) {
+		t.Errorf("Statuses = %v, want %v", flow.Statuses, want)
+	}
+	if len(flow.Days) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(flow.Days))
+	}
+	for i, wantCounts := range []map[types.Status]int{
+		{types.StatusOpen: 1},
+		{types.StatusInProgress: 1, types.StatusOpen: 1},
+		{types.StatusClosed: 1, types.StatusBlocked: 1},
+	} {
+		if !reflect.DeepEqual(flow.Days[i].Counts, wantCounts) {
+			t.Errorf("day %d counts = %v, want %v", i, flow.Days[i].Counts, wantCounts)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := flow.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	if want := "date,closed,in_progress,blocked,open\n2026-03-02,0,0,0,1\n"; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("CSV = %q, want prefix %q", buf.String(), want)
+	}
+}
+
+func TestComputeBurndown(t *testing.T) {
+	h := &history{}
+	h.issue("a", types.TypeTask, "", at(0, 1), types.EventCreated, "open", at(1, 1), types.EventClosed, "")
+	h.issue("b", types.TypeTask, "", at(0, 1), types.EventCreated, "open", at(2, 1), types.EventClosed, "")
+	// Scope grows on day 2
+	h.issue("c", types.TypeTask, "", at(2, 1), types.EventCreated, "open")
+
+	due := at(4, 0)
+	bd, err := ComputeBurndown(h.issues, h.events, monday, at(3, 12), &due)
+	if err != nil {
+		t.Fatalf("ComputeBurndown: %v", err)
+	}
+	if len(bd.Days) != 4 {
+		t.Fatalf("expected 4 days, got %d", len(bd.Days))
+	}
+	var got [][3]int
+	var ideal []float64
+	for _, d := range bd.Days {
+		got = append(got, [3]int{d.Scope, d.Done, d.Remaining})
+		if d.Ideal == nil {
+			t.Fatalf("day %s has no ideal with a due date", d.Date)
+		}
+		ideal = append(ideal, *d.Ideal)
+	}
+	if want := [][3]int{{2, 0, 2}, {2, 1, 1}, {3, 2, 1}, {3, 2, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scope/done/remaining = %v, want %v", got, want)
+	}
+	if want := []float64{1.5, 1, 0.5, 0}; !reflect.DeepEqual(ideal, want) {
+		t.Errorf("ideal = %v, want %v", ideal, want)
+	}
+
+	// Days before any scope get no ideal count
+	bd, err = ComputeBurndown(h.issues, h.events, monday.AddDate(0, 0, -2), at(1, 0), &due)
+	if err != nil {
+		t.Fatalf("ComputeBurndown: %v", err)
+	}
+	if len(bd.Days) != 3 || bd.Days[1].Ideal != nil || bd.Days[2].Ideal == nil || *bd.Days[2].Ideal != 1.5 {
+		t.Errorf("expected the ideal line to start on the first day with scope, got %+v", bd.Days)
+	}
+
+	bd, err = ComputeBurndown(h.issues, h.events, monday, at(1, 0), nil)
+	if err != nil {
+		t.Fatalf("ComputeBurndown: %v", err)
+	}
+	if len(bd.Days) != 1 || bd.Days[0].Ideal != nil {
+		t.Errorf("expected one day without an ideal line, got %+v", bd.Days)
+	}
+	if _, err := ComputeBurndown(h.issues, h.events, at(2, 0), at(1, 0), nil); err == nil {
+		t.Error("expected an error for an empty window")
+	}
+}
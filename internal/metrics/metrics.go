@@ -0,0 +1,428 @@
+// Package metrics computes flow metrics from the status transitions recorded
+// in the events table: throughput, cycle time (in_progress to closed), lead
+// time (created to closed), work in progress over time, and the age of work
+// still in progress.
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// Reporting periods for Options.Period.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week" // Weeks start on Monday
+	PeriodMonth = "month"
+)
+
+// Dimensions for Options.GroupBy.
+const (
+	GroupByType     = "type"
+	GroupByLabel    = "label" // An issue counts toward each of its labels
+	GroupByAssignee = "assignee"
+	GroupByPriority = "priority"
+)
+
+// AllGroup names the single group of an ungrouped report, and NoValue the
+// group of issues without a label or assignee.
+const (
+	AllGroup = "all"
+	NoValue  = "(none)"
+)
+
+// Options configures a report.
+type Options struct {
+	Since   time.Time // Window start, rounded down to the start of its period
+	Until   time.Time // Window end; aging is measured at this time
+	Period  string    // PeriodDay, PeriodWeek or PeriodMonth
+	GroupBy string    // Optional GroupBy* dimension
+}
+
+// Summary describes a set of durations, in hours.
+type Summary struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+}
+
+// Period holds the metrics for one reporting period. Durations are counted
+// in the period the issue closed.
+type Period struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Throughput int       `json:"throughput"` // Transitions to closed
+	WIP        int       `json:"wip"`        // Issues in progress at the end of the period
+	CycleTime  Summary   `json:"cycle_time_hours"`
+	LeadTime   Summary   `json:"lead_time_hours"`
+}
+
+// AgingItem is an issue still in progress at the end of the window.
+type AgingItem struct {
+	ID              string    `json:"id"`
+	Title           string    `json:"title"`
+	Assignee        string    `json:"assignee,omitempty"`
+	InProgressSince time.Time `json:"in_progress_since"`
+	AgeHours        float64   `json:"age_hours"`
+}
+
+// Group holds the metrics for the issues of one group.
+type Group struct {
+	Name       string       `json:"name"`
+	Issues     int          `json:"issues"`     // Issues in the group
+	Throughput int          `json:"throughput"` // Over the whole window
+	CycleTime  Summary      `json:"cycle_time_hours"`
+	LeadTime   Summary      `json:"lead_time_hours"`
+	Periods    []*Period    `json:"periods"`
+	Aging      []*AgingItem `json:"aging"` // Oldest first
+}
+
+// Report is a flow metrics report.
+type Report struct {
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	Period  string    `json:"period"`
+	GroupBy string    `json:"group_by,omitempty"`
+	Groups  []*Group  `json:"groups"`
+}
+
+// transition is a change of an issue's status.
+type transition struct {
+	at     time.Time
+	status types.Status
+}
+
+// Compute builds a report for issues from their events. Events for other
+// issues are ignored. For GroupByLabel, issues must have Labels populated.
+func Compute(issues []*types.Issue, events []*types.Event, opts Options) (*Report, error) {
+	if err := validate(&opts); err != nil {
+		return nil, err
+	}
+
+	eventsByIssue := make(map[string][]*types.Event)
+	for _, e := range events {
+		eventsByIssue[e.IssueID] = append(eventsByIssue[e.IssueID], e)
+	}
+	timelines := make(map[string][]transition, len(issues))
+	groups := make(map[string][]*types.Issue)
+	for _, issue := range issues {
+		timelines[issue.ID] = timeline(issue, eventsByIssue[issue.ID])
+		for _, name := range groupNames(issue, opts.GroupBy) {
+			groups[name] = append(groups[name], issue)
+		}
+	}
+
+	report := &Report{
+		Since:   opts.Since,
+		Until:   opts.Until,
+		Period:  opts.Period,
+		GroupBy: opts.GroupBy,
+		Groups:  []*Group{},
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == NoValue) != (names[j] == NoValue) {
+			return names[j] == NoValue
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 && opts.GroupBy == "" {
+		names = []string{AllGroup}
+	}
+	for _, name := range names {
+		report.Groups = append(report.Groups, computeGroup(name, groups[name], timelines, opts))
+	}
+	return report, nil
+}
+
+func validate(opts *Options) error {
+	switch opts.Period {
+	case "":
+		opts.Period = PeriodWeek
+	case PeriodDay, PeriodWeek, PeriodMonth:
+	default:
+		return fmt.Errorf("invalid period %q (must be day, week or month)", opts.Period)
+	}
+	switch opts.GroupBy {
+	case "", GroupByType, GroupByLabel, GroupByAssignee, GroupByPriority:
+	default:
+		return fmt.Errorf("invalid group %q (must be type, label, assignee or priority)", opts.GroupBy)
+	}
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+	opts.Since = periodStart(opts.Since, opts.Period)
+	if !opts.Until.After(opts.Since) {
+		return fmt.Errorf("window end %s is not after its start %s", opts.Until.Format(time.RFC3339), opts.Since.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func groupNames(issue *types.Issue, groupBy string) []string {
+	switch groupBy {
+	case GroupByType:
+		return []string{string(issue.IssueType)}
+	case GroupByPriority:
+		return []string{fmt.Sprintf("P%d", issue.Priority)}
+	case GroupByAssignee:
+		if issue.Assignee == "" {
+			return []string{NoValue}
+		}
+		return []string{issue.Assignee}
+	case GroupByLabel:
+		if len(issue.Labels) == 0 {
+			return []string{NoValue}
+		}
+		return issue.Labels
+	}
+	return []string{AllGroup}
+}
+
+// timeline reconstructs an issue's status history from its events, oldest
+// first. Issues without events (e.g. imported ones) fall back to their
+// created_at and closed_at.
+func timeline(issue *types.Issue, events []*types.Event) []transition {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	var out []transition
+	if len(events) == 0 || events[0].EventType != types.EventCreated {
+		out = append(out, transition{at: issue.CreatedAt, status: types.StatusOpen})
+	}
+	for _, e := range events {
+		status, ok := eventStatus(e)
+		if !ok || (len(out) > 0 && out[len(out)-1].status == status) {
+			continue
+		}
+		out = append(out, transition{at: e.CreatedAt, status: status})
+	}
+	if issue.Status == types.StatusClosed && issue.ClosedAt != nil && out[len(out)-1].status != types.StatusClosed {
+		out = append(out, transition{at: *issue.ClosedAt, status: types.StatusClosed})
+	}
+	return out
+}
+
+// eventStatus returns the status an event moved its issue to, if any.
+// Updates record the changed fields as a JSON object in NewValue.
+func eventStatus(e *types.Event) (types.Status, bool) {
+	var fields struct {
+		Status types.Status `json:"status"`
+	}
+	if e.NewValue != nil {
+		_ = json.Unmarshal([]byte(*e.NewValue), &fields)
+	}
+	switch e.EventType {
+	case types.EventCreated:
+		if fields.Status == "" {
+			return types.StatusOpen, true
+		}
+	case types.EventClosed:
+		return types.StatusClosed, true
+	case types.EventReopened:
+		if fields.Status == "" {
+			return types.StatusOpen, true
+		}
+	}
+	return fields.Status, fields.Status != ""
+}
+
+// statusAt returns an issue's status at t and when it entered that status.
+func statusAt(tl []transition, t time.Time) (types.Status, time.Time) {
+	var status types.Status
+	var since time.Time
+	for _, tr := range tl {
+		if tr.at.After(t) {
+			break
+		}
+		status, since = tr.status, tr.at
+	}
+	return status, since
+}
+
+func computeGroup(name string, issues []*types.Issue, timelines map[string][]transition, opts Options) *Group {
+	g := &Group{Name: name, Issues: len(issues), Periods: []*Period{}, Aging: []*AgingItem{}}
+	var starts []time.Time
+	for start := opts.Since; start.Before(opts.Until); start = nextPeriod(start, opts.Period) {
+		starts = append(starts, start)
+	}
+	cycle := make([][]float64, len(starts))
+	lead := make([][]float64, len(starts))
+	throughput := make([]int, len(starts))
+	var allCycle, allLead []float64
+
+	for _, issue := range issues {
+		tl := timelines[issue.ID]
+		var started time.Time
+		for _, tr := range tl {
+			switch tr.status {
+			case types.StatusInProgress:
+				if started.IsZero() {
+					started = tr.at
+				}
+				continue
+			case types.StatusClosed:
+			default:
+				continue
+			}
+			closedAt := tr.at
+			if !closedAt.Before(opts.Since) && closedAt.Before(opts.Until) {
+				p := sort.Search(len(starts), func(i int) bool { return starts[i].After(closedAt) }) - 1
+				throughput[p]++
+				leadHours := closedAt.Sub(issue.CreatedAt).Hours()
+				lead[p] = append(lead[p], leadHours)
+				allLead = append(allLead, leadHours)
+				if !started.IsZero() {
+					cycleHours := closedAt.Sub(started).Hours()
+					cycle[p] = append(cycle[p], cycleHours)
+					allCycle = append(allCycle, cycleHours)
+				}
+			}
+			started = time.Time{} // A reopened issue's next cycle starts afresh
+		}
+
+		if status, since := statusAt(tl, opts.Until); status == types.StatusInProgress {
+			g.Aging = append(g.Aging, &AgingItem{
+				ID:              issue.ID,
+				Title:           issue.Title,
+				Assignee:        issue.Assignee,
+				InProgressSince: since,
+				AgeHours:        round(opts.Until.Sub(since).Hours()),
+			})
+		}
+	}
+
+	for i, start := range starts {
+		end := nextPeriod(start, opts.Period)
+		at := end
+		if at.After(opts.Until) {
+			at = opts.Until
+		}
+		wip := 0
+		for _, issue := range issues {
+			if status, _ := statusAt(timelines[issue.ID], at); status == types.StatusInProgress {
+				wip++
+			}
+		}
+		g.Periods = append(g.Periods, &Period{
+			Start:      start,
+			End:        end,
+			Throughput: throughput[i],
+			WIP:        wip,
+			CycleTime:  summarize(cycle[i]),
+			LeadTime:   summarize(lead[i]),
+		})
+		g.Throughput += throughput[i]
+	}
+	g.CycleTime = summarize(allCycle)
+	g.LeadTime = summarize(allLead)
+	sort.Slice(g.Aging, func(i, j int) bool {
+		if g.Aging[i].AgeHours != g.Aging[j].AgeHours {
+			return g.Aging[i].AgeHours > g.Aging[j].AgeHours
+		}
+		return g.Aging[i].ID < g.Aging[j].ID
+	})
+	return g
+}
+
+// summarize computes the mean and nearest-rank percentiles of values.
+func summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return round(sorted[rank-1])
+	}
+	return Summary{
+		Count: len(sorted),
+		Mean:  round(sum / float64(len(sorted))),
+		P50:   percentile(50),
+		P85:   percentile(85),
+		P95:   percentile(95),
+	}
+}
+
+// round rounds hours to two decimal places.
+func round(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// periodStart returns the start of the period containing t, in t's location.
+func periodStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func nextPeriod(start time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"group", "period_start", "period_end", "throughput", "wip",
+	"cycle_count", "cycle_mean_h", "cycle_p50_h", "cycle_p85_h", "cycle_p95_h",
+	"lead_count", "lead_mean_h", "lead_p50_h", "lead_p85_h", "lead_p95_h",
+}
+
+// WriteCSV writes one row per group and period.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	num := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	for _, g := range r.Groups {
+		for _, p := range g.Periods {
+			row := []string{
+				g.Name, p.Start.Format("2006-01-02"), p.End.Format("2006-01-02"),
+				strconv.Itoa(p.Throughput), strconv.Itoa(p.WIP),
+			}
+			for _, s := range []Summary{p.CycleTime, p.LeadTime} {
+				row = append(row, strconv.Itoa(s.Count), num(s.Mean), num(s.P50), num(s.P85), num(s.P95))
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
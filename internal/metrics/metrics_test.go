@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// Monday 2026-03-02
+var monday = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+func at(days, hours float64) time.Time {
+	return monday.Add(time.Duration((days*24 + hours) * float64(time.Hour)))
+}
+
+// history builds an issue and its events from (time, event type, new status) steps.
+type history struct {
+	issues []*types.Issue
+	events []*types.Event
+}
+
+func (h *history) issue(id string, issueType types.IssueType, assignee string, steps ...interface{}) *types.Issue {
+	issue := &types.Issue{ID: id, Title: "Issue " + id, IssueType: issueType, Assignee: assignee, Status: types.StatusOpen}
+	for i := 0; i < len(steps); i += 3 {
+		when, eventType, status := steps[i].(time.Time), steps[i+1].(types.EventType), steps[i+2].(string)
+		e := &types.Event{ID: int64(len(h.events) + 1), IssueID: id, EventType: eventType, CreatedAt: when}
+		if status != "" {
+			v := `{"status":"` + status + `"}`
+			e.NewValue = &v
+			issue.Status = types.Status(status)
+		}
+		if eventType == types.EventCreated {
+			issue.CreatedAt = when
+		}
+		if eventType == types.EventClosed {
+			issue.Status = types.StatusClosed
+		}
+		h.events = append(h.events, e)
+	}
+	h.issues = append(h.issues, issue)
+	return issue
+}
+
+func TestCompute(t *testing.T) {
+	h := &history{}
+	// Closed in week 1: 24h cycle, 48h lead
+	h.issue("a", types.TypeTask, "alice",
+		at(0, 0), types.EventCreated, "open",
+		at(1, 0), types.EventStatusChanged, "in_progress",
+		at(2, 0), types.EventClosed, "")
+	// Closed in week 2 without being started: lead time only
+	h.issue("b", types.TypeBug, "bob",
+		at(0, 0), types.EventCreated, "open",
+		at(8, 0), types.EventClosed, "closed")
+	// Claimed in week 1 and still in progress
+	h.issue("c", types.TypeTask, "",
+		at(3, 0), types.EventCreated, "open",
+		at(4, 0), types.EventType("claimed"), "in_progress")
+	// Reopened: second cycle is measured from its own start
+	h.issue("d", types.TypeTask, "alice",
+		at(0, 0), types.EventCreated, "open",
+		at(0, 12), types.EventStatusChanged, "in_progress",
+		at(1, 0), types.EventClosed, "",
+		at(7, 0), types.EventReopened, "open",
+		at(9, 0), types.EventStatusChanged, "in_progress",
+		at(9, 6), types.EventClosed, "")
+
+	report, err := Compute(h.issues, h.events, Options{Since: at(2, 5), Until: at(10, 0)})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if !report.Since.Equal(monday) {
+		t.Errorf("Since = %v, want it rounded down to %v", report.Since, monday)
+	}
+	if len(report.Groups) != 1 || report.Groups[0].Name != AllGroup {
+		t.Fatalf("expected a single %q group, got %+v", AllGroup, report.Groups)
+	}
+	g := report.Groups[0]
+	if len(g.Periods) != 2 {
+		t.Fatalf("expected 2 weekly periods, got %d", len(g.Periods))
+	}
+	week1, week2 := g.Periods[0], g.Periods[1]
+	if week1.Throughput != 2 || week2.Throughput != 2 || g.Throughput != 4 {
+		t.Errorf("throughput = %d, %d (total %d), want 2, 2 (4)", week1.Throughput, week2.Throughput, g.Throughput)
+	}
+	if week1.CycleTime.Count != 2 || week1.CycleTime.P95 != 24 || week1.CycleTime.P50 != 12 {
+		t.Errorf("week 1 cycle time = %+v", week1.CycleTime)
+	}
+	if week2.CycleTime.Count != 1 || week2.CycleTime.Mean != 6 {
+		t.Errorf("week 2 cycle time = %+v, want one 6h cycle", week2.CycleTime)
+	}
+	// Lead time runs from creation, even after a reopen
+	if week2.LeadTime.Count != 2 || week2.LeadTime.P50 != 8*24 || week2.LeadTime.P95 != 9*24+6 {
+		t.Errorf("week 2 lead time = %+v", week2.LeadTime)
+	}
+	if week1.WIP != 1 || week2.WIP != 1 {
+		t.Errorf("WIP = %d, %d; want 1, 1", week1.WIP, week2.WIP)
+	}
+	if len(g.Aging) != 1 || g.Aging[0].ID != "c" || g.Aging[0].AgeHours != 6*24 {
+		t.Errorf("aging = %+v", g.Aging)
+	}
+}
+
+func TestComputeGroupBy(t *testing.T) {
+	h := &history{}
+	a := h.issue("a", types.TypeTask, "alice", at(0, 0), types.EventCreated, "open", at(1, 0), types.EventClosed, "")
+	a.Labels = []string{"backend", "api"}
+	h.issue("b", types.TypeBug, "", at(0, 0), types.EventCreated, "open", at(1, 0), types.EventClosed, "")
+
+	report, err := Compute(h.issues, h.events, Options{Since: monday, Until: at(7, 0), GroupBy: GroupByLabel})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	var names []string
+	for _, g := range report.Groups {
+		names = append(names, g.Name)
+		if g.Throughput != 1 {
+			t.Errorf("group %s throughput = %d, want 1", g.Name, g.Throughput)
+		}
+	}
+	if want := []string{"api", "backend", NoValue}; len(names) != 3 || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Errorf("groups = %v, want %v", names, want)
+	}
+
+	if _, err := Compute(h.issues, h.events, Options{Since: monday, Until: at(7, 0), GroupBy: "color"}); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+}
+
+func TestComputeWithoutEvents(t *testing.T) {
+	// Imported issues have no status events; fall back to created_at/closed_at
+	closedAt := at(3, 0)
+	issue := &types.Issue{ID: "x", Status: types.StatusClosed, CreatedAt: at(1, 0), ClosedAt: &closedAt}
+
+	report, err := Compute([]*types.Issue{issue}, nil, Options{Since: monday, Until: at(7, 0), Period: PeriodDay})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	g := report.Groups[0]
+	if len(g.Periods) != 7 || g.Periods[3].Throughput != 1 || g.LeadTime.Mean != 48 {
+		t.Errorf("unexpected report: throughput on day 3 = %d, lead = %+v", g.Periods[3].Throughput, g.LeadTime)
+	}
+	if g.CycleTime.Count != 0 {
+		t.Errorf("cycle time needs an in_progress transition: %+v", g.CycleTime)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	h := &history{}
+	h.issue("a", types.TypeTask, "", at(0, 0), types.EventCreated, "open", at(0, 2), types.EventStatusChanged, "in_progress", at(1, 0), types.EventClosed, "")
+	report, err := Compute(h.issues, h.events, Options{Since: monday, Until: at(14, 0)})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || len(rows[1]) != len(csvHeader) {
+		t.Fatalf("expected header + 2 rows of %d columns, got %v", len(csvHeader), rows)
+	}
+	if got := rows[1][:8]; got[0] != AllGroup || got[1] != "2026-03-02" || got[3] != "1" || got[7] != "22" {
+		t.Errorf("row = %v", rows[1])
+	}
+}
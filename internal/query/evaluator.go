@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,9 +12,12 @@ import (
 )
 
 // QueryResult contains the result of evaluating a query.
-// For simple queries, Filter will be populated and Predicate will be nil.
-// For complex queries with OR, Predicate will be set and Filter will contain
-// base filters that can pre-filter issues.
+//
+// Evaluation splits the query's top-level AND chain into conjuncts. Each
+// conjunct that can be expressed as an IssueFilter is pushed down into Filter
+// (and runs in SQL); everything else is compiled into Predicate and runs in
+// memory over the pre-filtered issues. Any combination of fields, operators,
+// AND/OR/NOT and parentheses is therefore supported.
 type QueryResult struct {
 	// Filter contains filters that can be passed to SearchIssues.
 	// This is always populated with at least base filters.
@@ -25,13 +29,61 @@ type QueryResult struct {
 	Predicate func(*types.Issue) bool
 
 	// RequiresPredicate indicates if in-memory filtering is needed.
-	// True when the query contains OR or complex NOT expressions.
+	// True when any part of the query could not be pushed down into Filter.
 	RequiresPredicate bool
+
+	// RequiresLabels indicates the Predicate reads issue.Labels, so callers
+	// must populate labels before applying it.
+	RequiresLabels bool
+
+	// RequiresDependencies indicates the Predicate reads issue.Dependencies
+	// (e.g. parent= inside an OR), so callers must populate dependency records
+	// before applying it.
+	RequiresDependencies bool
+
+	// Pushed lists the conjuncts compiled into Filter.
+	Pushed []Node
+
+	// InMemory lists the conjuncts evaluated by Predicate.
+	InMemory []Node
+}
+
+// Explain returns a human-readable description of the query plan, showing
+// which conjuncts run in SQL (via IssueFilter) and which run in memory.
+func (r *QueryResult) Explain() string {
+	var sb strings.Builder
+	sb.WriteString("SQL (IssueFilter):\n")
+	if len(r.Pushed) == 0 {
+		sb.WriteString("  (none - full scan)\n")
+	}
+	for _, n := range r.Pushed {
+		fmt.Fprintf(&sb, "  %s\n", n.String())
+	}
+	sb.WriteString("In-memory predicate:\n")
+	if len(r.InMemory) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	for _, n := range r.InMemory {
+		fmt.Fprintf(&sb, "  %s\n", n.String())
+	}
+	if r.RequiresLabels {
+		sb.WriteString("  (loads labels)\n")
+	}
+	if r.RequiresDependencies {
+		sb.WriteString("  (loads dependencies)\n")
+	}
+	return sb.String()
 }
 
 // Evaluator converts a query AST to an IssueFilter and/or predicate function.
 type Evaluator struct {
 	now time.Time
+
+	// Graph operator support (see graph.go); nil unless WithGraph was called.
+	ctx          context.Context
+	graphSource  GraphSource
+	graph        *dependencyGraph
+	graphMatches map[string]graphMatch
 }
 
 // NewEvaluator creates a new Evaluator with the given reference time.
@@ -39,70 +91,64 @@ func NewEvaluator(now time.Time) *Evaluator {
 	return &Evaluator{now: now}
 }
 
+// WithGraph enables graph operators (blocked_by, blocks, descendant_of,
+// ancestor_of, dep_type, has_dependents), resolved against src's dependency
+// records. The graph is loaded lazily, only if the query uses one of them.
+func (e *Evaluator) WithGraph(ctx context.Context, src GraphSource) *Evaluator {
+	e.ctx = ctx
+	e.graphSource = src
+	return e
+}
+
 // Evaluate evaluates the query AST and returns a QueryResult.
 func (e *Evaluator) Evaluate(node Node) (*QueryResult, error) {
+	// Compiling the whole query as a predicate validates every comparison
+	// (unknown fields, bad values). After this, a pushdown failure only means
+	// the conjunct isn't expressible as an IssueFilter, never that it's invalid.
+	if _, err := e.buildPredicate(node); err != nil {
+		return nil, err
+	}
+
 	result := &QueryResult{
 		Filter: types.IssueFilter{},
 	}
 
-	// Check if we can use Filter-only mode (simple AND chains)
-	if e.canUseFilterOnly(node) {
-		if err := e.buildFilter(node, &result.Filter); err != nil {
-			return nil, err
+	usedSlots := make(map[string]bool)
+	for _, conj := range splitAnd(node) {
+		if e.pushDown(conj, &result.Filter, usedSlots) {
+			result.Pushed = append(result.Pushed, conj)
+		} else {
+			result.InMemory = append(result.InMemory, conj)
 		}
+	}
+
+	if len(result.InMemory) == 0 {
 		return result, nil
 	}
 
-	// Complex query: build predicate and extract base filters
-	pred, err := e.buildPredicate(node)
+	pred, err := e.buildPredicate(joinAnd(result.InMemory))
 	if err != nil {
 		return nil, err
 	}
 	result.Predicate = pred
 	result.RequiresPredicate = true
-
-	// Extract base filters for pre-filtering (optional optimization)
-	e.extractBaseFilters(node, &result.Filter)
-
-	return result, nil
-}
-
-// canUseFilterOnly returns true if the query can be expressed as IssueFilter only.
-// This is true for:
-// - Simple comparisons
-// - AND chains of simple comparisons
-// - NOT with certain fields
-func (e *Evaluator) canUseFilterOnly(node Node) bool {
-	switch n := node.(type) {
-	case *ComparisonNode:
-		return true
-	case *AndNode:
-		return e.canUseFilterOnly(n.Left) && e.canUseFilterOnly(n.Right)
-	case *NotNode:
-		// NOT is only filter-compatible for certain fields
-		if comp, ok := n.Operand.(*ComparisonNode); ok {
-			switch comp.Field {
-			case "status":
-				return comp.Op == OpEquals
-			case "type":
-				return comp.Op == OpEquals
-			default:
-				return false
-			}
+	for _, n := range result.InMemory {
+		if referencesField(n, "label", "labels") {
+			result.RequiresLabels = true
+		}
+		if referencesField(n, "parent") {
+			result.RequiresDependencies = true
 		}
-		return false
-	case *OrNode:
-		// OR can be filter-compatible for labels
-		return e.canUseLabelsAnyOptimization(n)
-	default:
-		return false
 	}
+
+	return result, nil
 }
 
-// canUseLabelsAnyOptimization checks if an OR node can use LabelsAny.
-func (e *Evaluator) canUseLabelsAnyOptimization(node *OrNode) bool {
-	labels := e.collectOrLabels(node)
-	return len(labels) > 0
+// Matcher compiles the whole query into a single predicate, for testing
+// issues one at a time instead of searching. Issues passed to it must have
+// Labels and Dependencies populated.
+func (e *Evaluator) Matcher(node Node) (func(*types.Issue) bool, error) {
+	return e.buildPredicate(node)
 }
 
 // collectOrLabels collects label values from an OR chain of label=X comparisons.
@@ -110,7 +156,7 @@ func (e *Evaluator) canUseLabelsAnyOptimization(node *OrNode) bool {
 func (e *Evaluator) collectOrLabels(node Node) []string {
 	switch n := node.(type) {
 	case *ComparisonNode:
-		if (n.Field == "label" || n.Field == "labels") && n.Op == OpEquals {
+		if (n.Field == "label" || n.Field == "labels") && n.Op == OpEquals && !isNoneValue(n.Value) {
 			return []string{n.Value}
 		}
 		return nil
@@ -126,7 +172,8 @@ func (e *Evaluator) collectOrLabels(node Node) []string {
 	}
 }
 
-// buildFilter populates the IssueFilter from a filter-compatible AST.
+// buildFilter populates the IssueFilter from a single pushdown-compatible
+// conjunct. Returns an error if the conjunct can't be expressed as a filter.
 func (e *Evaluator) buildFilter(node Node, filter *types.IssueFilter) error {
 	switch n := node.(type) {
 	case *ComparisonNode:
@@ -139,7 +186,6 @@ func (e *Evaluator) buildFilter(node Node, filter *types.IssueFilter) error {
 	case *NotNode:
 		return e.applyNot(n, filter)
 	case *OrNode:
-		// Only reached for LabelsAny optimization
 		labels := e.collectOrLabels(n)
 		if labels != nil {
 			filter.LabelsAny = append(filter.LabelsAny, labels...)
@@ -153,6 +199,9 @@ func (e *Evaluator) buildFilter(node Node, filter *types.IssueFilter) error {
 
 // applyComparison applies a comparison to the filter.
 func (e *Evaluator) applyComparison(comp *ComparisonNode, filter *types.IssueFilter) error {
+	if graphFields[comp.Field] {
+		return e.applyGraphFilter(comp, filter)
+	}
 	switch comp.Field {
 	case "status":
 		return e.applyStatusFilter(comp, filter)
@@ -226,8 +275,8 @@ func (e *Evaluator) applyPriorityFilter(comp *ComparisonNode, filter *types.Issu
 	case OpEquals:
 		filter.Priority = &priority
 	case OpNotEquals:
-		// For != we need predicate filtering
-		return fmt.Errorf("priority != requires predicate filtering")
+		// IssueFilter has no priority exclusion; evaluated in memory
+		return fmt.Errorf("priority != cannot be expressed as a filter")
 	case OpLess:
 		// priority < X means PriorityMax = X-1
 		max := priority - 1
@@ -276,8 +325,8 @@ func (e *Evaluator) applyAssigneeFilter(comp *ComparisonNode, filter *types.Issu
 }
 
 func (e *Evaluator) applyOwnerFilter(comp *ComparisonNode, filter *types.IssueFilter) error {
-	// Owner filtering requires predicate
-	return fmt.Errorf("owner filtering requires predicate mode")
+	// IssueFilter has no owner field; evaluated in memory
+	return fmt.Errorf("owner cannot be expressed as a filter")
 }
 
 func (e *Evaluator) applyLabelFilter(comp *ComparisonNode, filter *types.IssueFilter) error {
@@ -513,24 +562,6 @@ func (e *Evaluator) parseDurationAgo(s string) (time.Time, error) {
 	return timeparsing.ParseCompactDuration(negated, e.now)
 }
 
-// extractBaseFilters extracts filter-compatible portions from a complex query.
-// This is used to pre-filter before applying the predicate.
-func (e *Evaluator) extractBaseFilters(node Node, filter *types.IssueFilter) {
-	switch n := node.(type) {
-	case *ComparisonNode:
-		// Try to apply, ignore errors
-		_ = e.applyComparison(n, filter)
-	case *AndNode:
-		e.extractBaseFilters(n.Left, filter)
-		e.extractBaseFilters(n.Right, filter)
-	case *NotNode:
-		_ = e.applyNot(n, filter)
-	case *OrNode:
-		// For OR, we can't safely extract base filters
-		// (extracting from either side would over-filter)
-	}
-}
-
 // buildPredicate builds a predicate function for complex queries.
 func (e *Evaluator) buildPredicate(node Node) (func(*types.Issue) bool, error) {
 	switch n := node.(type) {
@@ -575,6 +606,9 @@ func (e *Evaluator) buildPredicate(node Node) (func(*types.Issue) bool, error) {
 
 // buildComparisonPredicate builds a predicate for a single comparison.
 func (e *Evaluator) buildComparisonPredicate(comp *ComparisonNode) (func(*types.Issue) bool, error) {
+	if graphFields[comp.Field] {
+		return e.buildGraphPredicate(comp)
+	}
 	switch comp.Field {
 	case "status":
 		return e.buildStatusPredicate(comp)
@@ -610,6 +644,10 @@ func (e *Evaluator) buildComparisonPredicate(comp *ComparisonNode) (func(*types.
 		return e.buildBoolPredicate(comp, func(i *types.Issue) bool { return i.Ephemeral })
 	case "template":
 		return e.buildBoolPredicate(comp, func(i *types.Issue) bool { return i.IsTemplate })
+	case "parent":
+		return e.buildParentPredicate(comp)
+	case "mol_type":
+		return e.buildMolTypePredicate(comp)
 	default:
 		return nil, fmt.Errorf("unknown field: %s", comp.Field)
 	}
@@ -617,6 +655,9 @@ func (e *Evaluator) buildComparisonPredicate(comp *ComparisonNode) (func(*types.
 
 func (e *Evaluator) buildStatusPredicate(comp *ComparisonNode) (func(*types.Issue) bool, error) {
 	status := types.Status(strings.ToLower(comp.Value))
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid status: %s", comp.Value)
+	}
 	switch comp.Op {
 	case OpEquals:
 		return func(i *types.Issue) bool { return i.Status == status }, nil
@@ -632,6 +673,9 @@ func (e *Evaluator) buildPriorityPredicate(comp *ComparisonNode) (func(*types.Is
 	if err != nil {
 		return nil, fmt.Errorf("invalid priority: %s", comp.Value)
 	}
+	if priority < 0 || priority > 4 {
+		return nil, fmt.Errorf("priority must be between 0 and 4")
+	}
 	switch comp.Op {
 	case OpEquals:
 		return func(i *types.Issue) bool { return i.Priority == priority }, nil
@@ -888,6 +932,47 @@ func (e *Evaluator) buildSpecPredicate(comp *ComparisonNode) (func(*types.Issue)
 	}
 }
 
+// buildParentPredicate matches children of a parent issue, mirroring the SQL
+// ParentID filter: a parent-child dependency on the parent, or a dotted child
+// ID (e.g. "bd-a3f8.1" is a child of "bd-a3f8"). Requires issue.Dependencies.
+func (e *Evaluator) buildParentPredicate(comp *ComparisonNode) (func(*types.Issue) bool, error) {
+	parentID := comp.Value
+	isChild := func(i *types.Issue) bool {
+		if strings.HasPrefix(i.ID, parentID+".") {
+			return true
+		}
+		for _, dep := range i.Dependencies {
+			if dep.Type == types.DepParentChild && dep.DependsOnID == parentID {
+				return true
+			}
+		}
+		return false
+	}
+	switch comp.Op {
+	case OpEquals:
+		return isChild, nil
+	case OpNotEquals:
+		return func(i *types.Issue) bool { return !isChild(i) }, nil
+	default:
+		return nil, fmt.Errorf("parent does not support %s operator", comp.Op.String())
+	}
+}
+
+func (e *Evaluator) buildMolTypePredicate(comp *ComparisonNode) (func(*types.Issue) bool, error) {
+	mt := types.MolType(strings.ToLower(comp.Value))
+	if !mt.IsValid() {
+		return nil, fmt.Errorf("invalid mol_type: %s", comp.Value)
+	}
+	switch comp.Op {
+	case OpEquals:
+		return func(i *types.Issue) bool { return i.MolType == mt }, nil
+	case OpNotEquals:
+		return func(i *types.Issue) bool { return i.MolType != mt }, nil
+	default:
+		return nil, fmt.Errorf("mol_type does not support %s operator", comp.Op.String())
+	}
+}
+
 func (e *Evaluator) buildBoolPredicate(comp *ComparisonNode, getter func(*types.Issue) bool) (func(*types.Issue) bool, error) {
 	val := strings.ToLower(comp.Value)
 	var boolVal bool
@@ -0,0 +1,237 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// GraphSource provides the dependency records needed to evaluate graph
+// operators. storage.Storage satisfies this interface.
+type GraphSource interface {
+	GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error)
+}
+
+// graphFields are the dependency-graph operators. Each resolves to a set of
+// issue IDs using the full dependency graph:
+//
+//	blocked_by=X      issues transitively blocked by X (X must finish first)
+//	blocks=X          issues X is transitively blocked by
+//	descendant_of=X   transitive children of X (parent-child edges)
+//	ancestor_of=X     transitive parents of X (parent-child edges)
+//	dep_type=T        issues with an outgoing dependency of type T
+//	has_dependents=B  issues that other issues depend on (B = true/false)
+var graphFields = map[string]bool{
+	"blocked_by":     true,
+	"blocks":         true,
+	"descendant_of":  true,
+	"ancestor_of":    true,
+	"dep_type":       true,
+	"has_dependents": true,
+}
+
+// maxPushdownIDs caps how many resolved graph IDs are pushed into the SQL
+// filter as an IN list; larger sets are matched in memory instead.
+const maxPushdownIDs = 1000
+
+// isBlockingEdge reports whether a dependency type orders work, as opposed to
+// hierarchy (parent-child) or association edges.
+func isBlockingEdge(t types.DependencyType) bool {
+	return t == types.DepBlocks || t == types.DepConditionalBlocks || t == types.DepWaitsFor
+}
+
+func isParentChildEdge(t types.DependencyType) bool {
+	return t == types.DepParentChild
+}
+
+// dependencyGraph is an adjacency view of all dependency records.
+// Edges point from an issue to what it depends on (issue_id -> depends_on_id).
+type dependencyGraph struct {
+	out map[string][]*types.Dependency // issue_id -> its dependencies
+	in  map[string][]*types.Dependency // depends_on_id -> dependencies on it
+}
+
+func newDependencyGraph(records map[string][]*types.Dependency) *dependencyGraph {
+	g := &dependencyGraph{
+		out: records,
+		in:  make(map[string][]*types.Dependency),
+	}
+	for _, deps := range records {
+		for _, dep := range deps {
+			g.in[dep.DependsOnID] = append(g.in[dep.DependsOnID], dep)
+		}
+	}
+	return g
+}
+
+// walk returns every issue reachable from start (excluding start) following
+// edges accepted by keep. If reverse is true, edges are followed from
+// depends_on_id back to issue_id.
+func (g *dependencyGraph) walk(start string, reverse bool, keep func(types.DependencyType) bool) map[string]bool {
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		edges := g.out[id]
+		if reverse {
+			edges = g.in[id]
+		}
+		for _, dep := range edges {
+			if !keep(dep.Type) {
+				continue
+			}
+			next := dep.DependsOnID
+			if reverse {
+				next = dep.IssueID
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	delete(seen, start)
+	return seen
+}
+
+// loadGraph fetches the dependency graph once per evaluator.
+func (e *Evaluator) loadGraph() (*dependencyGraph, error) {
+	if e.graph != nil {
+		return e.graph, nil
+	}
+	if e.graphSource == nil {
+		return nil, fmt.Errorf("graph operators require dependency access (no storage available)")
+	}
+	records, err := e.graphSource.GetAllDependencyRecords(e.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	e.graph = newDependencyGraph(records)
+	return e.graph, nil
+}
+
+// graphMatch is a resolved graph comparison: the issues in ids match, or with
+// complement set, every issue except those in ids matches.
+type graphMatch struct {
+	ids        map[string]bool
+	complement bool
+}
+
+func (m graphMatch) matches(id string) bool {
+	return m.ids[id] != m.complement
+}
+
+// resolveGraph resolves a graph comparison (ignoring != negation) to the set
+// of issue IDs it matches. Results are cached since a query is compiled more
+// than once.
+func (e *Evaluator) resolveGraph(comp *ComparisonNode) (graphMatch, error) {
+	if comp.Op != OpEquals && comp.Op != OpNotEquals {
+		return graphMatch{}, fmt.Errorf("%s only supports = and != operators", comp.Field)
+	}
+	if comp.Value == "" {
+		return graphMatch{}, fmt.Errorf("%s requires a value", comp.Field)
+	}
+
+	key := comp.Field + "=" + comp.Value
+	if m, ok := e.graphMatches[key]; ok {
+		return m, nil
+	}
+
+	g, err := e.loadGraph()
+	if err != nil {
+		return graphMatch{}, err
+	}
+
+	m := graphMatch{ids: make(map[string]bool)}
+	switch comp.Field {
+	case "blocked_by":
+		m.ids = g.walk(comp.Value, true, isBlockingEdge)
+	case "blocks":
+		m.ids = g.walk(comp.Value, false, isBlockingEdge)
+	case "descendant_of":
+		m.ids = g.walk(comp.Value, true, isParentChildEdge)
+	case "ancestor_of":
+		m.ids = g.walk(comp.Value, false, isParentChildEdge)
+	case "dep_type":
+		depType := types.DependencyType(strings.ToLower(comp.Value))
+		for issueID, deps := range g.out {
+			for _, dep := range deps {
+				if dep.Type == depType {
+					m.ids[issueID] = true
+					break
+				}
+			}
+		}
+	case "has_dependents":
+		want, err := parseBool(comp.Value)
+		if err != nil {
+			return graphMatch{}, fmt.Errorf("invalid boolean value for has_dependents: %s", comp.Value)
+		}
+		for dependsOnID, deps := range g.in {
+			if len(deps) > 0 {
+				m.ids[dependsOnID] = true
+			}
+		}
+		m.complement = !want
+	default:
+		return graphMatch{}, fmt.Errorf("unknown graph field: %s", comp.Field)
+	}
+
+	if e.graphMatches == nil {
+		e.graphMatches = make(map[string]graphMatch)
+	}
+	e.graphMatches[key] = m
+	return m, nil
+}
+
+// buildGraphPredicate builds a membership predicate for a graph comparison.
+func (e *Evaluator) buildGraphPredicate(comp *ComparisonNode) (func(*types.Issue) bool, error) {
+	m, err := e.resolveGraph(comp)
+	if err != nil {
+		return nil, err
+	}
+	if comp.Op == OpNotEquals {
+		return func(i *types.Issue) bool { return !m.matches(i.ID) }, nil
+	}
+	return func(i *types.Issue) bool { return m.matches(i.ID) }, nil
+}
+
+// applyGraphFilter pushes a graph comparison down as an ID list. Only
+// non-empty, bounded, positive sets can be pushed; an empty IDs filter would
+// match everything instead of nothing.
+func (e *Evaluator) applyGraphFilter(comp *ComparisonNode, filter *types.IssueFilter) error {
+	m, err := e.resolveGraph(comp)
+	if err != nil {
+		return err
+	}
+	if comp.Op != OpEquals || m.complement {
+		return fmt.Errorf("%s%s%s cannot be expressed as a filter", comp.Field, comp.Op.String(), comp.Value)
+	}
+	if len(m.ids) == 0 || len(m.ids) > maxPushdownIDs {
+		return fmt.Errorf("%s matches %d issues; evaluated in memory", comp.Field, len(m.ids))
+	}
+	ids := make([]string, 0, len(m.ids))
+	for id := range m.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	filter.IDs = append(filter.IDs, ids...)
+	return nil
+}
+
+// parseBool parses the boolean spellings accepted by the query language.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean value: %s", value)
+	}
+}
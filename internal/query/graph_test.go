@@ -0,0 +1,167 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+type fakeGraph map[string][]*types.Dependency
+
+func (f fakeGraph) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	return f, nil
+}
+
+type failingGraph struct{}
+
+func (failingGraph) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	return nil, errors.New("boom")
+}
+
+func dep(from, to string, t types.DependencyType) *types.Dependency {
+	return &types.Dependency{IssueID: from, DependsOnID: to, Type: t}
+}
+
+// testGraph:
+//
+//	epic <- task1 <- task1.sub   (parent-child)
+//	epic <- task2                (parent-child)
+//	a blocks b blocks c; a blocks d (via waits-for)
+//	c discovered-from a
+func testGraph() fakeGraph {
+	g := fakeGraph{}
+	for _, d := range []*types.Dependency{
+		dep("task1", "epic", types.DepParentChild),
+		dep("task1.sub", "task1", types.DepParentChild),
+		dep("task2", "epic", types.DepParentChild),
+		dep("b", "a", types.DepBlocks),
+		dep("c", "b", types.DepBlocks),
+		dep("d", "a", types.DepWaitsFor),
+		dep("c", "a", types.DepDiscoveredFrom),
+	} {
+		g[d.IssueID] = append(g[d.IssueID], d)
+	}
+	return g
+}
+
+func TestGraphOperators(t *testing.T) {
+	all := []string{"epic", "task1", "task1.sub", "task2", "a", "b", "c", "d"}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"blocked_by=a", []string{"b", "c", "d"}},
+		{"blocked_by=b", []string{"c"}},
+		{"blocks=c", []string{"a", "b"}},
+		{"descendant_of=epic", []string{"task1", "task1.sub", "task2"}},
+		{"ancestor_of=task1.sub", []string{"epic", "task1"}},
+		{"dep_type=discovered-from", []string{"c"}},
+		{"has_dependents=true", []string{"a", "b", "epic", "task1"}},
+		{"has_dependents=false", []string{"c", "d", "task1.sub", "task2"}},
+		{"blocked_by!=a", []string{"a", "epic", "task1", "task1.sub", "task2"}},
+		{"blocked_by=a AND has_dependents=false", []string{"c", "d"}},
+		{"descendant_of=epic OR blocks=b", []string{"a", "task1", "task1.sub", "task2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			eval := NewEvaluator(time.Now()).WithGraph(context.Background(), testGraph())
+			result, err := eval.Evaluate(node)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			var got []string
+			for _, id := range all {
+				issue := &types.Issue{ID: id}
+				if matchesResult(result, issue) {
+					got = append(got, id)
+				}
+			}
+			sort.Strings(got)
+			want := append([]string(nil), tt.want...)
+			sort.Strings(want)
+			if !equalStrings(got, want) {
+				t.Errorf("matched %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// matchesResult applies both the pushed-down ID filter and the predicate.
+func matchesResult(result *QueryResult, issue *types.Issue) bool {
+	if len(result.Filter.IDs) > 0 {
+		found := false
+		for _, id := range result.Filter.IDs {
+			if id == issue.ID {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return result.Predicate == nil || result.Predicate(issue)
+}
+
+func TestGraphOperatorPushdown(t *testing.T) {
+	eval := NewEvaluator(time.Now()).WithGraph(context.Background(), testGraph())
+	node, _ := Parse("status=open AND blocked_by=a")
+	result, err := eval.Evaluate(node)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.RequiresPredicate {
+		t.Errorf("expected blocked_by to push down as IDs, plan:\n%s", result.Explain())
+	}
+	if !equalStrings(result.Filter.IDs, []string{"b", "c", "d"}) {
+		t.Errorf("Filter.IDs = %v, want [b c d]", result.Filter.IDs)
+	}
+
+	// An empty set can't push down (empty IDs means "no filter")
+	node, _ = Parse("blocked_by=c")
+	result, err = NewEvaluator(time.Now()).WithGraph(context.Background(), testGraph()).Evaluate(node)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !result.RequiresPredicate || result.Predicate(&types.Issue{ID: "a"}) {
+		t.Errorf("expected empty graph set to match nothing in memory")
+	}
+}
+
+func TestGraphOperatorErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		src   GraphSource
+	}{
+		{"no graph source", "blocked_by=a", nil},
+		{"unsupported operator", "blocked_by>a", testGraph()},
+		{"bad boolean", "has_dependents=maybe", testGraph()},
+		{"source error", "blocks=a", failingGraph{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval := NewEvaluator(time.Now())
+			if tt.src != nil {
+				eval = eval.WithGraph(context.Background(), tt.src)
+			}
+			node, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if _, err := eval.Evaluate(node); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
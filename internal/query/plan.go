@@ -0,0 +1,148 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// splitAnd flattens a tree of AndNodes into its conjuncts, left to right.
+func splitAnd(node Node) []Node {
+	if n, ok := node.(*AndNode); ok {
+		return append(splitAnd(n.Left), splitAnd(n.Right)...)
+	}
+	return []Node{node}
+}
+
+// joinAnd rebuilds a left-deep AND chain from conjuncts.
+func joinAnd(nodes []Node) Node {
+	result := nodes[0]
+	for _, n := range nodes[1:] {
+		result = &AndNode{Left: result, Right: n}
+	}
+	return result
+}
+
+// referencesField reports whether any comparison under node uses one of fields.
+func referencesField(node Node, fields ...string) bool {
+	switch n := node.(type) {
+	case *ComparisonNode:
+		for _, f := range fields {
+			if n.Field == f {
+				return true
+			}
+		}
+		return false
+	case *AndNode:
+		return referencesField(n.Left, fields...) || referencesField(n.Right, fields...)
+	case *OrNode:
+		return referencesField(n.Left, fields...) || referencesField(n.Right, fields...)
+	case *NotNode:
+		return referencesField(n.Operand, fields...)
+	default:
+		return false
+	}
+}
+
+// isNoneValue reports whether a comparison value means "empty/unset".
+func isNoneValue(value string) bool {
+	v := strings.ToLower(value)
+	return value == "" || v == "none" || v == "null"
+}
+
+// pushDown tries to compile a single conjunct into filter. It returns false
+// (leaving filter untouched) if the conjunct can't be expressed as an
+// IssueFilter, or if it needs a filter slot an earlier conjunct already
+// claimed - e.g. a second "status=" would overwrite the first rather than AND
+// with it, so it is evaluated in memory instead.
+func (e *Evaluator) pushDown(node Node, filter *types.IssueFilter, usedSlots map[string]bool) bool {
+	slots, ok := filterSlots(node)
+	if !ok {
+		return false
+	}
+	for _, slot := range slots {
+		if usedSlots[slot] {
+			return false
+		}
+	}
+
+	scratch := *filter
+	if err := e.buildFilter(node, &scratch); err != nil {
+		return false
+	}
+	*filter = scratch
+	for _, slot := range slots {
+		usedSlots[slot] = true
+	}
+	return true
+}
+
+// filterSlots returns the single-valued IssueFilter fields a conjunct writes.
+// Conjuncts that append to list fields with AND semantics (Labels,
+// ExcludeStatus, ExcludeTypes) need no slot. The second return value is false
+// for node shapes that can never be pushed down.
+func filterSlots(node Node) ([]string, bool) {
+	switch n := node.(type) {
+	case *ComparisonNode:
+		return comparisonSlots(n), true
+	case *NotNode:
+		// NOT status=X / NOT type=X become exclusions
+		return nil, true
+	case *OrNode:
+		// Only OR chains of labels (LabelsAny) can be pushed down
+		return []string{"labels_any"}, true
+	default:
+		return nil, false
+	}
+}
+
+// comparisonSlots returns the filter slots a single comparison writes.
+func comparisonSlots(comp *ComparisonNode) []string {
+	if graphFields[comp.Field] {
+		// Graph operators push down as an IDs list
+		return []string{"ids"}
+	}
+	switch comp.Field {
+	case "status", "type":
+		if comp.Op == OpNotEquals {
+			return nil
+		}
+		return []string{comp.Field}
+	case "priority":
+		switch comp.Op {
+		case OpLess, OpLessEq:
+			return []string{"priority_max"}
+		case OpGreater, OpGreaterEq:
+			return []string{"priority_min"}
+		default:
+			return []string{"priority"}
+		}
+	case "label", "labels":
+		if isNoneValue(comp.Value) {
+			return []string{"no_labels"}
+		}
+		return nil
+	case "created", "created_at", "updated", "updated_at", "closed", "closed_at":
+		base := strings.TrimSuffix(comp.Field, "_at")
+		switch comp.Op {
+		case OpGreater, OpGreaterEq:
+			return []string{base + "_after"}
+		case OpLess, OpLessEq:
+			return []string{base + "_before"}
+		default:
+			return []string{base + "_after", base + "_before"}
+		}
+	case "id":
+		// Multiple IDs in the filter mean IN (...), which is OR, not AND
+		if strings.HasSuffix(comp.Value, "*") {
+			return []string{"id_prefix"}
+		}
+		return []string{"ids"}
+	case "description", "desc":
+		return []string{"description"}
+	case "spec", "spec_id":
+		return []string{"spec"}
+	default:
+		return []string{comp.Field}
+	}
+}
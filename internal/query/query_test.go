@@ -617,3 +617,163 @@ func TestDurationParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluatorPlan(t *testing.T) {
+	now := time.Date(2025, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		pushed   []string
+		inMemory []string
+	}{
+		{
+			name:   "simple AND fully pushed down",
+			query:  "status=open AND priority>1",
+			pushed: []string{"status=open", "priority>1"},
+		},
+		{
+			name:     "priority != runs in memory",
+			query:    "type=bug AND priority!=2",
+			pushed:   []string{"type=bug"},
+			inMemory: []string{"priority!=2"},
+		},
+		{
+			name:     "owner runs in memory",
+			query:    "owner=alice",
+			inMemory: []string{"owner=alice"},
+		},
+		{
+			name:     "OR across fields runs in memory, rest pushed",
+			query:    "(status=open OR owner=bob) AND label=urgent",
+			pushed:   []string{"label=urgent"},
+			inMemory: []string{"(status=open OR owner=bob)"},
+		},
+		{
+			name:     "repeated scalar field is not overwritten",
+			query:    "status=open AND status=closed",
+			pushed:   []string{"status=open"},
+			inMemory: []string{"status=closed"},
+		},
+		{
+			name:     "repeated id is ANDed, not INed",
+			query:    "id=bd-1 AND id=bd-2",
+			pushed:   []string{"id=bd-1"},
+			inMemory: []string{"id=bd-2"},
+		},
+		{
+			name:     "closed equality runs in memory",
+			query:    `closed="2025-01-15"`,
+			inMemory: []string{"closed=2025-01-15"},
+		},
+		{
+			name:     "NOT over OR runs in memory",
+			query:    "NOT (parent=bd-1 OR label=x)",
+			inMemory: []string{"NOT (parent=bd-1 OR label=x)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateAt(tt.query, now)
+			if err != nil {
+				t.Fatalf("EvaluateAt() error = %v", err)
+			}
+			if got := nodeStrings(result.Pushed); !equalStrings(got, tt.pushed) {
+				t.Errorf("Pushed = %v, want %v", got, tt.pushed)
+			}
+			if got := nodeStrings(result.InMemory); !equalStrings(got, tt.inMemory) {
+				t.Errorf("InMemory = %v, want %v", got, tt.inMemory)
+			}
+			if result.RequiresPredicate != (len(tt.inMemory) > 0) {
+				t.Errorf("RequiresPredicate = %v, want %v", result.RequiresPredicate, len(tt.inMemory) > 0)
+			}
+		})
+	}
+}
+
+func TestEvaluatorPlanRequirements(t *testing.T) {
+	result, err := Evaluate("parent=bd-1 OR label=urgent")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !result.RequiresLabels || !result.RequiresDependencies {
+		t.Errorf("expected labels and dependencies to be required, got labels=%v deps=%v",
+			result.RequiresLabels, result.RequiresDependencies)
+	}
+
+	result, err = Evaluate("parent=bd-1 AND label=urgent")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.RequiresPredicate || result.RequiresLabels || result.RequiresDependencies {
+		t.Errorf("expected fully pushed-down plan, got %s", result.Explain())
+	}
+	if result.Filter.ParentID == nil || *result.Filter.ParentID != "bd-1" {
+		t.Errorf("expected ParentID filter bd-1")
+	}
+}
+
+func TestPredicateParentAndOwner(t *testing.T) {
+	depChild := &types.Issue{
+		ID:    "bd-7",
+		Owner: "alice@example.com",
+		Dependencies: []*types.Dependency{
+			{IssueID: "bd-7", DependsOnID: "bd-1", Type: types.DepParentChild},
+		},
+	}
+	dottedChild := &types.Issue{ID: "bd-1.2", Owner: "bob@example.com"}
+	blocker := &types.Issue{
+		ID: "bd-9",
+		Dependencies: []*types.Dependency{
+			{IssueID: "bd-9", DependsOnID: "bd-1", Type: types.DepBlocks},
+		},
+	}
+
+	tests := []struct {
+		query   string
+		issue   *types.Issue
+		matches bool
+	}{
+		{"parent=bd-1 OR owner=nobody", depChild, true},
+		{"parent=bd-1 OR owner=nobody", dottedChild, true},
+		{"parent=bd-1 OR owner=nobody", blocker, false},
+		{"parent!=bd-1 OR owner=nobody", blocker, true},
+		{`owner="bob@example.com" OR priority!=0`, dottedChild, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"/"+tt.issue.ID, func(t *testing.T) {
+			result, err := Evaluate(tt.query)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if result.Predicate == nil {
+				t.Fatalf("expected predicate for %q", tt.query)
+			}
+			if got := result.Predicate(tt.issue); got != tt.matches {
+				t.Errorf("predicate(%s) = %v, want %v", tt.issue.ID, got, tt.matches)
+			}
+		})
+	}
+}
+
+func nodeStrings(nodes []Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.String())
+	}
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 	"time"
@@ -80,6 +81,103 @@ func TestReady(t *testing.T) {
 	}
 }
 
+func TestReadyCursor(t *testing.T) {
+	_, client, cleanup := setupTestServer(t)
+	defer cleanup()
+	defer client.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.Create(&CreateArgs{Title: "Page Test", IssueType: "task", Priority: i % 2}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	filter := types.WorkFilter{Limit: 2}
+	for pages := 0; pages < 5; pages++ {
+		resp, err := client.Ready(&ReadyArgs{Limit: filter.Limit, Cursor: filter.Cursor})
+		if err != nil {
+			t.Fatalf("Ready failed: %v", err)
+		}
+		var issues []*types.Issue
+		if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			t.Fatalf("failed to unmarshal Ready response: %v", err)
+		}
+		for _, issue := range issues {
+			if seen[issue.ID] {
+				t.Errorf("issue %s returned on more than one page", issue.ID)
+			}
+			seen[issue.ID] = true
+		}
+		if filter.Cursor = filter.NextCursor(issues); filter.Cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected 5 issues across pages, got %d", len(seen))
+	}
+
+	if _, err := client.Ready(&ReadyArgs{Cursor: "not a cursor"}); err == nil {
+		t.Error("expected an error for a malformed cursor")
+	}
+}
+
+func TestReadyReportHidden(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+	defer client.Close()
+
+	if err := server.storage.SetConfig(context.Background(), "wip.assignee", "1"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	client.SetActor("alice")
+	create := func(title string) string {
+		t.Helper()
+		resp, err := client.Create(&CreateArgs{Title: title, IssueType: "task", Priority: 1})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(resp.Data, &issue); err != nil {
+			t.Fatalf("Failed to unmarshal: %v", err)
+		}
+		return issue.ID
+	}
+	claimed := create("Claimed")
+	status, assignee := string(types.StatusInProgress), "alice"
+	if _, err := client.Update(&UpdateArgs{ID: claimed, Status: &status, Assignee: &assignee}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	unclaimable := create("Over the limit")
+
+	resp, err := client.Ready(&ReadyArgs{ReportHidden: true})
+	if err != nil {
+		t.Fatalf("Ready failed: %v", err)
+	}
+	var result ReadyResult
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		t.Fatalf("failed to unmarshal ReadyResult: %v", err)
+	}
+	if result.HiddenByWIP != 1 {
+		t.Errorf("HiddenByWIP = %d, want 1", result.HiddenByWIP)
+	}
+	for _, issue := range result.Issues {
+		if issue.ID == unclaimable {
+			t.Errorf("%s returned despite the WIP limit", unclaimable)
+		}
+	}
+
+	// Without ReportHidden the response stays a plain array
+	resp, err = client.Ready(&ReadyArgs{})
+	if err != nil {
+		t.Fatalf("Ready failed: %v", err)
+	}
+	var issues []*types.IssueWithCounts
+	if err := json.Unmarshal(resp.Data, &issues); err != nil {
+		t.Fatalf("failed to unmarshal Ready response: %v", err)
+	}
+}
+
 func TestStats(t *testing.T) {
 	_, client, cleanup := setupTestServer(t)
 	defer cleanup()
@@ -0,0 +1,425 @@
+package rpc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP gateway
+//
+// The gateway exposes the same operations as the unix socket to tools that
+// can't speak its line-delimited framing (dashboards, scripts in other
+// languages). Requests go through handleRequest, so behaviour, metrics and
+// mutation events are identical to socket clients.
+//
+//	POST /v1/rpc                 Request envelope in, Response envelope out
+//	POST /v1/ops/{operation}     Operation args in, Response envelope out
+//	GET  /v1/health              health
+//	GET  /v1/stats               stats
+//	GET  /v1/issues              list (status, assignee, type, label, priority, query, limit)
+//	GET  /v1/issues/{id}         show
+//	GET  /v1/ready               ready (assignee, type, label, priority, limit)
+//	GET  /v1/events              MutationEvent stream (Server-Sent Events; since, filter)
+//
+// Failed operations, including unknown ones, return 422 with the error in
+// the Response envelope.
+//
+// Browser requests are only served from allowed origins, and only with a
+// Host header naming an IP address, localhost or an allowed host, so web
+// pages can't drive the gateway by cross-site requests or DNS rebinding.
+
+// HTTPOptions configures the HTTP gateway.
+type HTTPOptions struct {
+	// Token, if set, is required as "Authorization: Bearer <token>".
+	// Event streams may pass it as ?access_token= since browsers'
+	// EventSource cannot set headers.
+	Token string
+	// AllowedOrigins lists origins allowed to make cross-origin requests
+	// (e.g. "http://localhost:3000"). "*" allows any origin. Requests from
+	// any other origin are rejected. Requires Token.
+	AllowedOrigins []string
+	// AllowedHosts lists host names, besides localhost, that clients may
+	// send in the Host header. Hosts given as IP addresses are always
+	// accepted. StartHTTP adds the host name it listens on.
+	AllowedHosts []string
+}
+
+// httpBlockedOps can only be used over the unix socket.
+var httpBlockedOps = map[string]string{
+	OpShutdown:  "use 'fbd serve stop'",
+	OpSubscribe: "use GET /v1/events",
+}
+
+// StartHTTP serves the HTTP gateway on addr until the server is stopped.
+// It returns the bound address, which differs from addr when the port is 0.
+func (s *Server) StartHTTP(addr string, opts HTTPOptions) (net.Addr, error) {
+	if len(opts.AllowedOrigins) > 0 && opts.Token == "" {
+		return nil, fmt.Errorf("allowing cross-origin requests requires a bearer token")
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		opts.AllowedHosts = append(opts.AllowedHosts, host)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           s.HTTPHandler(opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		_ = listener.Close()
+		return nil, fmt.Errorf("server is shutting down")
+	}
+	s.httpServer = httpServer
+	s.mu.Unlock()
+
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Warning: HTTP gateway stopped: %v\n", err)
+		}
+	}()
+	return listener.Addr(), nil
+}
+
+// HTTPHandler returns the HTTP gateway handler for this server.
+func (s *Server) HTTPHandler(opts HTTPOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/rpc", s.handleHTTPRPC)
+	mux.HandleFunc("POST /v1/ops/{operation}", s.handleHTTPOp)
+	mux.HandleFunc("GET /v1/health", s.handleHTTPGet(OpHealth, nil))
+	mux.HandleFunc("GET /v1/stats", s.handleHTTPGet(OpStats, nil))
+	mux.HandleFunc("GET /v1/issues", s.handleHTTPGet(OpList, listArgsFromQuery))
+	mux.HandleFunc("GET /v1/issues/{id}", s.handleHTTPGet(OpShow, func(r *http.Request) (interface{}, error) {
+		return ShowArgs{ID: r.PathValue("id")}, nil
+	}))
+	mux.HandleFunc("GET /v1/ready", s.handleHTTPGet(OpReady, readyArgsFromQuery))
+	mux.HandleFunc("GET /v1/events", s.handleHTTPEvents)
+
+	return withHostCheck(opts.AllowedHosts, withCORS(opts.AllowedOrigins, withBearerAuth(opts.Token, mux)))
+}
+
+// handleHTTPRPC accepts a full Request envelope, JSON-RPC style.
+func (s *Server) handleHTTPRPC(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	body, err := readHTTPBody(w, r)
+	if err == nil {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	s.serveHTTPRequest(w, &req)
+}
+
+// handleHTTPOp accepts the operation's args as the body.
+func (s *Server) handleHTTPOp(w http.ResponseWriter, r *http.Request) {
+	body, err := readHTTPBody(w, r)
+	if err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	s.serveHTTPRequest(w, &Request{
+		Operation: r.PathValue("operation"),
+		Args:      body,
+		Actor:     r.Header.Get("X-Beads-Actor"),
+		RequestID: r.Header.Get("X-Request-ID"),
+	})
+}
+
+// handleHTTPGet serves a read-only operation with args built from the URL.
+func (s *Server) handleHTTPGet(op string, argsFn func(*http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var args interface{} = struct{}{}
+		if argsFn != nil {
+			var err error
+			if args, err = argsFn(r); err != nil {
+				writeHTTPResponse(w, http.StatusBadRequest, Response{Error: err.Error()})
+				return
+			}
+		}
+		data, _ := json.Marshal(args)
+		s.serveHTTPRequest(w, &Request{
+			Operation: op,
+			Args:      data,
+			Actor:     r.Header.Get("X-Beads-Actor"),
+			RequestID: r.Header.Get("X-Request-ID"),
+		})
+	}
+}
+
+// serveHTTPRequest runs req through the same path as socket requests.
+func (s *Server) serveHTTPRequest(w http.ResponseWriter, req *Request) {
+	if hint, blocked := httpBlockedOps[req.Operation]; blocked {
+		writeHTTPResponse(w, http.StatusForbidden, Response{
+			Error: fmt.Sprintf("operation %s is not available over HTTP (%s)", req.Operation, hint),
+		})
+		return
+	}
+	// The gateway is bound to this server, so the database binding holds by
+	// construction; HTTP clients don't know the storage path.
+	req.ExpectedDB = s.storage.Path()
+	req.ClientVersion = ""
+
+	resp := s.handleRequest(req)
+	status := http.StatusOK
+	if !resp.Success {
+		status = http.StatusUnprocessableEntity
+	}
+	writeHTTPResponse(w, status, resp)
+}
+
+// handleHTTPEvents streams mutation events as Server-Sent Events. Clients
+// resume with Last-Event-ID (sent automatically by EventSource) or ?since=
+// (Unix milliseconds); events still in the recent-mutations buffer after
+// that point are replayed first. ?filter= takes a query expression (see
+// 'fbd query') and only streams events for matching issues.
+func (s *Server) handleHTTPEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	since := int64(-1)
+	for _, v := range []string{r.Header.Get("Last-Event-ID"), r.URL.Query().Get("since")} {
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid event id %q", v), http.StatusBadRequest)
+			return
+		}
+		since = n
+		break
+	}
+
+	var filter *MutationFilter
+	if expr := r.URL.Query().Get("filter"); expr != "" {
+		var err error
+		if filter, err = NewMutationFilter(expr); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	_ = s.streamMutations(r.Context(), since, filter,
+		func() error {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			_, err := fmt.Fprint(w, ": connected\n\n")
+			flusher.Flush()
+			return err
+		},
+		func(event MutationEvent) error {
+			if err := writeSSEEvent(w, event); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		},
+		func() error {
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+}
+
+// writeSSEEvent writes one mutation event. The id is the event timestamp in
+// Unix milliseconds, the same cursor get_mutations uses.
+func writeSSEEvent(w io.Writer, event MutationEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: mutation\ndata: %s\n\n", event.Timestamp.UnixMilli(), data)
+	return err
+}
+
+// withBearerAuth rejects requests without the configured bearer token.
+// CORS preflight requests carry no credentials and are let through.
+func withBearerAuth(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && r.URL.Path == "/v1/events" {
+			got, ok = r.URL.Query().Get("access_token"), true
+		}
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="fbd"`)
+			writeHTTPResponse(w, http.StatusUnauthorized, Response{Error: "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withHostCheck rejects requests whose Host header names a host other than
+// localhost, an IP address or one of the allowed hosts. A DNS rebinding
+// attack reaches the gateway under the attacker's own domain name.
+func withHostCheck(allowed []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hostAllowed(allowed, r.Host) {
+			writeHTTPResponse(w, http.StatusForbidden, Response{Error: fmt.Sprintf("host %q not allowed", r.Host)})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hostAllowed(allowed []string, hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCORS adds CORS headers for allowed origins and answers preflight
+// requests. Requests from other origins are rejected whatever their method,
+// since simple requests (e.g. a text/plain POST) skip preflight. Requests
+// without an Origin header (non-browser clients) pass through unchanged.
+func withCORS(allowed []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		if !originAllowed(allowed, origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Last-Event-ID, X-Beads-Actor, X-Request-ID")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func readHTTPBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	return io.ReadAll(http.MaxBytesReader(w, r.Body, MaxMessageSize))
+}
+
+func writeHTTPResponse(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// listArgsFromQuery builds list args from GET /v1/issues query parameters.
+func listArgsFromQuery(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	args := ListArgs{
+		Query:     q.Get("query"),
+		Status:    q.Get("status"),
+		Assignee:  q.Get("assignee"),
+		IssueType: q.Get("type"),
+		Labels:    q["label"],
+		Cursor:    q.Get("cursor"),
+	}
+	var err error
+	if args.Priority, err = queryIntPtr(q.Get("priority"), "priority"); err != nil {
+		return nil, err
+	}
+	if args.Limit, err = queryInt(q.Get("limit"), "limit"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// readyArgsFromQuery builds ready args from GET /v1/ready query parameters.
+func readyArgsFromQuery(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	args := ReadyArgs{
+		Assignee: q.Get("assignee"),
+		Type:     q.Get("type"),
+		Labels:   q["label"],
+		Cursor:   q.Get("cursor"),
+	}
+	var err error
+	if args.Priority, err = queryIntPtr(q.Get("priority"), "priority"); err != nil {
+		return nil, err
+	}
+	if args.Limit, err = queryInt(q.Get("limit"), "limit"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func queryInt(v, name string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", name, v)
+	}
+	return n, nil
+}
+
+func queryIntPtr(v, name string) (*int, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, err := queryInt(v, name)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
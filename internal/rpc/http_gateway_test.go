@@ -0,0 +1,259 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func doHTTP(t *testing.T, method, url, body string, header map[string]string) (*http.Response, Response) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	var out Response
+	if resp.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return resp, out
+}
+
+func TestHTTPGateway_Operations(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.HTTPHandler(HTTPOptions{}))
+	defer ts.Close()
+
+	resp, out := doHTTP(t, "POST", ts.URL+"/v1/ops/create",
+		`{"title":"From HTTP","issue_type":"task","priority":1}`,
+		map[string]string{"X-Beads-Actor": "dashboard"})
+	if resp.StatusCode != http.StatusOK || !out.Success {
+		t.Fatalf("create: status %d, error %q", resp.StatusCode, out.Error)
+	}
+	var created types.Issue
+	if err := json.Unmarshal(out.Data, &created); err != nil {
+		t.Fatalf("unmarshal created issue: %v", err)
+	}
+
+	resp, out = doHTTP(t, "GET", ts.URL+"/v1/issues/"+created.ID, "", nil)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(out.Data), "From HTTP") {
+		t.Errorf("show: status %d, data %s", resp.StatusCode, out.Data)
+	}
+
+	resp, out = doHTTP(t, "GET", ts.URL+"/v1/issues?status=open&priority=1", "", nil)
+	var issues []*types.Issue
+	if err := json.Unmarshal(out.Data, &issues); err != nil || len(issues) != 1 {
+		t.Errorf("list: status %d, got %d issues (err %v)", resp.StatusCode, len(issues), err)
+	}
+
+	resp, _ = doHTTP(t, "GET", ts.URL+"/v1/issues?priority=high", "", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad query param: status %d, want 400", resp.StatusCode)
+	}
+
+	resp, out = doHTTP(t, "POST", ts.URL+"/v1/rpc", `{"operation":"stats","args":{}}`, nil)
+	if resp.StatusCode != http.StatusOK || !out.Success {
+		t.Errorf("rpc envelope: status %d, error %q", resp.StatusCode, out.Error)
+	}
+
+	resp, out = doHTTP(t, "POST", ts.URL+"/v1/ops/no_such_op", "", nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(out.Error, "unknown operation") {
+		t.Errorf("unknown op: status %d, error %q", resp.StatusCode, out.Error)
+	}
+
+	resp, _ = doHTTP(t, "POST", ts.URL+"/v1/ops/shutdown", "", nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("shutdown over HTTP: status %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestHTTPGateway_BearerAuth(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.HTTPHandler(HTTPOptions{Token: "s3cret"}))
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		header map[string]string
+		want   int
+	}{
+		{"missing token", "/v1/health", nil, http.StatusUnauthorized},
+		{"wrong token", "/v1/health", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"valid token", "/v1/health", map[string]string{"Authorization": "Bearer s3cret"}, http.StatusOK},
+		{"query token only for events", "/v1/health?access_token=s3cret", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := doHTTP(t, "GET", ts.URL+tt.url, "", tt.header)
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPGateway_CORS(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.HTTPHandler(HTTPOptions{
+		Token:          "s3cret",
+		AllowedOrigins: []string{"http://localhost:3000"},
+	}))
+	defer ts.Close()
+
+	preflight := map[string]string{
+		"Origin":                         "http://localhost:3000",
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "authorization",
+	}
+	resp, _ := doHTTP(t, "OPTIONS", ts.URL+"/v1/ops/create", "", preflight)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("preflight: status %d, want 204", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if !strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("Access-Control-Allow-Headers = %q", resp.Header.Get("Access-Control-Allow-Headers"))
+	}
+
+	preflight["Origin"] = "http://evil.example"
+	resp, _ = doHTTP(t, "OPTIONS", ts.URL+"/v1/ops/create", "", preflight)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("disallowed preflight: status %d, want 403", resp.StatusCode)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Error("disallowed origin must not get CORS headers")
+	}
+
+	// Simple requests skip preflight, so they must be rejected outright
+	for _, opts := range []HTTPOptions{{}, {Token: "s3cret", AllowedOrigins: []string{"http://localhost:3000"}}} {
+		gw := httptest.NewServer(server.HTTPHandler(opts))
+		resp, _ = doHTTP(t, "POST", gw.URL+"/v1/ops/create", `{"title":"csrf","issue_type":"task"}`, map[string]string{
+			"Origin":        "http://evil.example",
+			"Content-Type":  "text/plain",
+			"Authorization": "Bearer " + opts.Token,
+		})
+		gw.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("cross-origin POST with %+v: status %d, want 403", opts, resp.StatusCode)
+		}
+	}
+
+	if _, err := server.StartHTTP("127.0.0.1:0", HTTPOptions{AllowedOrigins: []string{"*"}}); err == nil {
+		t.Error("expected StartHTTP to require a token when cross-origin requests are allowed")
+	}
+}
+
+func TestHTTPGateway_HostCheck(t *testing.T) {
+	server, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.HTTPHandler(HTTPOptions{AllowedHosts: []string{"beads.internal"}}))
+	defer ts.Close()
+
+	for host, want := range map[string]int{
+		"":                    http.StatusOK, // the listener's IP address
+		"localhost:7681":      http.StatusOK,
+		"[::1]:7681":          http.StatusOK,
+		"beads.internal:7681": http.StatusOK,
+		"rebind.example:7681": http.StatusForbidden,
+		"localhost.example":   http.StatusForbidden,
+	} {
+		req, err := http.NewRequest("GET", ts.URL+"/v1/health", nil)
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		if host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET with Host %q: %v", host, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Host %q: status %d, want %d", host, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestHTTPGateway_Events(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.HTTPHandler(HTTPOptions{Token: "s3cret"}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/events?access_token=s3cret")
+	if err != nil {
+		t.Fatalf("GET /v1/events: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("events: status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	lines := make(chan string, 64)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// Wait for the stream to open before mutating
+	if line := <-lines; line != ": connected" {
+		t.Fatalf("first line = %q", line)
+	}
+
+	if _, err := client.Create(&CreateArgs{Title: "Streamed", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed before event")
+			}
+			if data, found := strings.CutPrefix(line, "data: "); found {
+				var event MutationEvent
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					t.Fatalf("unmarshal event: %v", err)
+				}
+				if event.Type != MutationCreate || event.Title != "Streamed" {
+					t.Errorf("unexpected event: %+v", event)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for mutation event")
+		}
+	}
+}
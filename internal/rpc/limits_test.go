@@ -282,6 +282,51 @@ func TestShutdownDrainsBeforeClosingStorage(t *testing.T) {
 	}
 }
 
+// TestStopRunsHooksBeforeClosingStorage verifies that OnStop hooks run
+// while the storage is still open, however Stop is triggered.
+func TestStopRunsHooksBeforeClosingStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, ".beads", "test.db")
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := sqlite.New(context.Background(), dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	srv := NewServer(newTestSocketPath(t), store, tmpDir, dbPath)
+	var hookErr error
+	hookRan := false
+	srv.OnStop(func() {
+		hookRan = true
+		hookErr = store.SetConfig(context.Background(), "hook.ran", "true")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Start(ctx) }()
+	select {
+	case <-srv.WaitReady():
+	case err := <-errCh:
+		t.Fatalf("server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not become ready")
+	}
+
+	_ = srv.Stop()
+	<-errCh
+	if !hookRan {
+		t.Fatal("OnStop hook did not run")
+	}
+	if hookErr != nil {
+		t.Errorf("hook could not use storage: %v", hookErr)
+	}
+}
+
 func TestHealthResponseIncludesLimits(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -43,6 +43,7 @@ const (
 	OpExport              = "export"
 	OpEpicStatus          = "epic_status"
 	OpGetMutations        = "get_mutations"
+	OpSubscribe           = "subscribe"
 	OpGetMoleculeProgress = "get_molecule_progress"
 	OpShutdown            = "shutdown"
 	OpDelete              = "delete"
@@ -127,6 +128,7 @@ type UpdateArgs struct {
 	Title              *string  `json:"title,omitempty"`
 	Description        *string  `json:"description,omitempty"`
 	Status             *string  `json:"status,omitempty"`
+	ClosedBySession    *string  `json:"closed_by_session,omitempty"` // Claude Code session ID when status=closed
 	Priority           *int     `json:"priority,omitempty"`
 	Design             *string  `json:"design,omitempty"`
 	AcceptanceCriteria *string  `json:"acceptance_criteria,omitempty"`
@@ -219,6 +221,7 @@ type ListArgs struct {
 	IDs          []string `json:"ids,omitempty"`            // Filter by specific issue IDs
 	SpecIDPrefix string   `json:"spec_id_prefix,omitempty"` // Filter by spec_id prefix
 	Limit        int      `json:"limit,omitempty"`
+	Cursor       string   `json:"cursor,omitempty"` // Resume after a page cursor (see types.IssueFilter.NextCursor)
 
 	// Pattern matching
 	TitleContains       string `json:"title_contains,omitempty"`
@@ -340,6 +343,16 @@ type ReadyArgs struct {
 	ParentID        string   `json:"parent_id,omitempty"`        // Filter to descendants of this bead/epic
 	MolType         string   `json:"mol_type,omitempty"`         // Filter by molecule type: swarm, patrol, or work
 	IncludeDeferred bool     `json:"include_deferred,omitempty"` // Include issues with future defer_until (GH#820)
+	Cursor          string   `json:"cursor,omitempty"`           // Resume after a page cursor (see types.WorkFilter.NextCursor)
+	IgnoreWIP       bool     `json:"ignore_wip,omitempty"`       // Include work the actor can't claim under WIP limits
+	ReportHidden    bool     `json:"report_hidden,omitempty"`    // Return a ReadyResult with the count hidden by WIP limits
+}
+
+// ReadyResult is returned when ReportHidden is true.
+// When ReportHidden is false, just the issues are returned for backward compatibility
+type ReadyResult struct {
+	Issues      []*types.IssueWithCounts `json:"issues"`
+	HiddenByWIP int                      `json:"hidden_by_wip,omitempty"` // Ready issues the actor can't claim under WIP limits
 }
 
 // BlockedArgs represents arguments for the blocked operation
@@ -526,6 +539,23 @@ type GetMutationsArgs struct {
 	Since int64 `json:"since"` // Unix timestamp in milliseconds (0 for all recent)
 }
 
+// SubscribeArgs represents arguments for the subscribe operation.
+// The connection becomes a one-way event stream after the first response.
+type SubscribeArgs struct {
+	Since  *int64 `json:"since,omitempty"`  // Replay buffered events after this Unix ms cursor; nil = new events only
+	Filter string `json:"filter,omitempty"` // Query expression; only events whose issue matches are sent
+}
+
+// SubscribeResult is the first response of a subscription. Every following
+// line on the connection is a Response whose Data is a MutationEvent, or has
+// no Data (a heartbeat).
+type SubscribeResult struct {
+	Cursor int64 `json:"cursor"` // Server time in Unix ms when the subscription started
+	// Gap is true when Since is older than the server's event buffer, so
+	// some events after Since may not be replayed.
+	Gap bool `json:"gap,omitempty"`
+}
+
 // Gate operations
 
 // GateCreateArgs represents arguments for creating a gate
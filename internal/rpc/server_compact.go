@@ -23,7 +23,7 @@ func (s *Server) handleCompact(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -246,7 +246,7 @@ func (s *Server) handleCompactStats(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
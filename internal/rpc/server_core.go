@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -59,6 +60,13 @@ type Server struct {
 	recentMutations   []MutationEvent
 	recentMutationsMu sync.RWMutex
 	maxMutationBuffer int
+	// Live mutation subscribers (HTTP event streams)
+	subscribers   map[chan MutationEvent]struct{}
+	subscribersMu sync.Mutex
+	// HTTP gateway, if started via StartHTTP
+	httpServer *http.Server
+	// Hooks run by Stop before the storage is closed (see OnStop)
+	stopHooks []func()
 	// Daemon configuration (set via SetConfig after creation)
 	autoCommit   bool
 	autoPush     bool
@@ -74,6 +82,9 @@ const (
 	MutationUpdate  = "update"
 	MutationDelete  = "delete"
 	MutationComment = "comment"
+	// Dependency edges; DependsOnID is set
+	MutationDepAdd    = "dep_add"
+	MutationDepRemove = "dep_remove"
 	// Molecule-specific event types for activity feed
 	MutationBonded   = "bonded"   // Molecule bonded to parent (dynamic bond)
 	MutationSquashed = "squashed" // Wisp squashed to digest
@@ -94,6 +105,8 @@ type MutationEvent struct {
 	NewStatus string `json:"new_status,omitempty"` // New status (for status events)
 	ParentID  string `json:"parent_id,omitempty"`  // Parent molecule (for bonded events)
 	StepCount int    `json:"step_count,omitempty"` // Number of steps (for bonded events)
+	// Target of a dependency (for dep_add/dep_remove events)
+	DependsOnID string `json:"depends_on_id,omitempty"`
 }
 
 // NewServer creates a new RPC server
@@ -138,6 +151,7 @@ func NewServer(socketPath string, store storage.Storage, workspacePath string, d
 		mutationChan:      make(chan MutationEvent, mutationBufferSize), // Configurable buffer
 		recentMutations:   make([]MutationEvent, 0, 100),
 		maxMutationBuffer: 100,
+		subscribers:       make(map[chan MutationEvent]struct{}),
 	}
 	s.lastActivityTime.Store(time.Now())
 	return s
@@ -174,6 +188,17 @@ func (s *Server) emitRichMutation(event MutationEvent) {
 		s.droppedEvents.Add(1)
 	}
 
+	// Fan out to live subscribers; a slow subscriber misses events rather
+	// than blocking the request that caused them
+	s.subscribersMu.Lock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	s.subscribersMu.Unlock()
+
 	// Store in recent mutations buffer for polling
 	s.recentMutationsMu.Lock()
 	s.recentMutations = append(s.recentMutations, event)
@@ -193,6 +218,30 @@ func (s *Server) MutationChan() <-chan MutationEvent {
 	return s.mutationChan
 }
 
+// subscribeMutations registers a live mutation subscriber with the given
+// buffer size. The returned function unregisters it.
+func (s *Server) subscribeMutations(buffer int) (<-chan MutationEvent, func()) {
+	ch := make(chan MutationEvent, buffer)
+	s.subscribersMu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.subscribersMu.Unlock()
+
+	return ch, func() {
+		s.subscribersMu.Lock()
+		delete(s.subscribers, ch)
+		s.subscribersMu.Unlock()
+	}
+}
+
+// OnStop registers fn to run when the server stops, after in-flight
+// requests have drained and before the storage is closed. Background work
+// that uses the server's storage stops here.
+func (s *Server) OnStop(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopHooks = append(s.stopHooks, fn)
+}
+
 // SetConfig sets the daemon configuration for status reporting
 func (s *Server) SetConfig(autoCommit, autoPush, autoPull, localMode bool, syncInterval, daemonMode string) {
 	s.mu.Lock()
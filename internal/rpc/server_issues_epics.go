@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -62,6 +63,9 @@ func updatesFromArgs(a UpdateArgs) (map[string]interface{}, error) {
 	if a.Status != nil {
 		u["status"] = *a.Status
 	}
+	if a.ClosedBySession != nil {
+		u["closed_by_session"] = *a.ClosedBySession
+	}
 	if a.Priority != nil {
 		u["priority"] = *a.Priority
 	}
@@ -227,7 +231,7 @@ func (s *Server) handleCreate(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 	ctx, cancel := s.reqCtx(req)
@@ -550,7 +554,7 @@ func (s *Server) handleUpdate(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -583,8 +587,8 @@ func (s *Server) handleUpdate(req *Request) Response {
 	// Handle claim operation atomically using compare-and-swap semantics
 	if updateArgs.Claim {
 		if err := store.ClaimIssue(ctx, updateArgs.ID, actor); err != nil {
-			// Check if it's an "already claimed" error and format appropriately
-			if strings.Contains(err.Error(), "already claimed") {
+			// Already-claimed and WIP limit errors are reported as-is
+			if strings.Contains(err.Error(), "already claimed") || errors.Is(err, storage.ErrWIPLimitExceeded) {
 				return Response{
 					Success: false,
 					Error:   err.Error(),
@@ -821,7 +825,7 @@ func (s *Server) handleClose(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -927,7 +931,7 @@ func (s *Server) handleDelete(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1096,12 +1100,13 @@ func (s *Server) handleList(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
 	filter := types.IssueFilter{
-		Limit: listArgs.Limit,
+		Limit:  listArgs.Limit,
+		Cursor: listArgs.Cursor,
 	}
 
 	// Normalize status: treat "" or "all" as unset (no filter)
@@ -1388,7 +1393,7 @@ func (s *Server) handleCount(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1620,7 +1625,7 @@ func (s *Server) handleResolveID(req *Request) Response {
 	if s.storage == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1654,7 +1659,7 @@ func (s *Server) handleShow(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1714,7 +1719,7 @@ func (s *Server) handleReady(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1728,6 +1733,7 @@ func (s *Server) handleReady(req *Request) Response {
 		Labels:          utils.NormalizeLabels(readyArgs.Labels),
 		LabelsAny:       utils.NormalizeLabels(readyArgs.LabelsAny),
 		IncludeDeferred: readyArgs.IncludeDeferred, // GH#820
+		Cursor:          readyArgs.Cursor,
 	}
 	if readyArgs.Assignee != "" && !readyArgs.Unassigned {
 		wf.Assignee = &readyArgs.Assignee
@@ -1742,7 +1748,15 @@ func (s *Server) handleReady(req *Request) Response {
 
 	ctx, cancel := s.reqCtx(req)
 	defer cancel()
-	issues, err := store.GetReadyWork(ctx, wf)
+	var issues []*types.Issue
+	var hiddenByWIP int
+	var err error
+	if readyArgs.IgnoreWIP {
+		issues, err = store.GetReadyWork(ctx, wf)
+	} else {
+		// Hide work the caller can't claim under the WIP limits
+		issues, hiddenByWIP, err = storage.GetClaimableReadyWork(ctx, store, wf, s.reqActor(req))
+	}
 	if err != nil {
 		return Response{
 			Success: false,
@@ -1764,7 +1778,12 @@ func (s *Server) handleReady(req *Request) Response {
 		}
 	}
 
-	data, _ := json.Marshal(issuesWithCounts)
+	var data []byte
+	if readyArgs.ReportHidden {
+		data, _ = json.Marshal(ReadyResult{Issues: issuesWithCounts, HiddenByWIP: hiddenByWIP})
+	} else {
+		data, _ = json.Marshal(issuesWithCounts)
+	}
 	return Response{
 		Success: true,
 		Data:    data,
@@ -1784,7 +1803,7 @@ func (s *Server) handleBlocked(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1823,7 +1842,7 @@ func (s *Server) handleStale(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1855,7 +1874,7 @@ func (s *Server) handleStats(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -1889,7 +1908,7 @@ func (s *Server) handleEpicStatus(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
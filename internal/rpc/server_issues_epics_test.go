@@ -824,3 +824,33 @@ func compareTimePtr(t *testing.T, name string, direct, daemon *time.Time) bool {
 	}
 	return true
 }
+
+// TestUpdateViaDaemon_ClosedBySession tests that closing through an update
+// records the session that closed the issue.
+func TestUpdateViaDaemon_ClosedBySession(t *testing.T) {
+	_, client, store, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	createResp, err := client.Create(&CreateArgs{Title: "Close with session", IssueType: "task", Priority: 1})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(createResp.Data, &issue); err != nil {
+		t.Fatalf("Failed to unmarshal issue: %v", err)
+	}
+
+	status, session := "closed", "session-123"
+	if _, err := client.Update(&UpdateArgs{ID: issue.ID, Status: &status, ClosedBySession: &session}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	var got string
+	if err := store.UnderlyingDB().QueryRowContext(context.Background(),
+		`SELECT closed_by_session FROM issues WHERE id = ?`, issue.ID).Scan(&got); err != nil {
+		t.Fatalf("Failed to read closed_by_session: %v", err)
+	}
+	if got != session {
+		t.Errorf("expected closed_by_session %q, got %q", session, got)
+	}
+}
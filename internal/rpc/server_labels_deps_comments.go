@@ -58,7 +58,7 @@ func (s *Server) handleDepAdd(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -79,7 +79,14 @@ func (s *Server) handleDepAdd(req *Request) Response {
 
 	// Emit mutation event for event-driven daemon
 	title, assignee := s.lookupIssueMeta(ctx, depArgs.FromID)
-	s.emitMutation(MutationUpdate, depArgs.FromID, title, assignee)
+	s.emitRichMutation(MutationEvent{
+		Type:        MutationDepAdd,
+		IssueID:     depArgs.FromID,
+		Title:       title,
+		Assignee:    assignee,
+		Actor:       s.reqActor(req),
+		DependsOnID: depArgs.ToID,
+	})
 
 	result := map[string]interface{}{
 		"status":        "added",
@@ -92,10 +99,11 @@ func (s *Server) handleDepAdd(req *Request) Response {
 }
 
 // Generic handler for simple store operations with standard error handling.
-// issueIDFunc is called after args are unmarshaled to extract the issue ID
-// for mutation events (passing the ID directly would evaluate before unmarshal).
+// eventFunc is called after args are unmarshaled to build the mutation event
+// (type, issue ID and any extra metadata); title, assignee and actor are
+// filled in here.
 func (s *Server) handleSimpleStoreOp(req *Request, argsPtr interface{}, argDesc string,
-	opFunc func(context.Context, storage.Storage, string) error, issueIDFunc func() string,
+	opFunc func(context.Context, storage.Storage, string) error, eventFunc func() MutationEvent,
 	responseData func() map[string]interface{}) Response {
 	if err := json.Unmarshal(req.Args, argsPtr); err != nil {
 		return Response{
@@ -108,7 +116,7 @@ func (s *Server) handleSimpleStoreOp(req *Request, argsPtr interface{}, argDesc
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -122,9 +130,10 @@ func (s *Server) handleSimpleStoreOp(req *Request, argsPtr interface{}, argDesc
 	}
 
 	// Emit mutation event for event-driven daemon
-	issueID := issueIDFunc()
-	title, assignee := s.lookupIssueMeta(ctx, issueID)
-	s.emitMutation(MutationUpdate, issueID, title, assignee)
+	event := eventFunc()
+	event.Title, event.Assignee = s.lookupIssueMeta(ctx, event.IssueID)
+	event.Actor = s.reqActor(req)
+	s.emitRichMutation(event)
 
 	if responseData != nil {
 		data, _ := json.Marshal(responseData())
@@ -146,7 +155,7 @@ func (s *Server) handleDepTree(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -175,7 +184,9 @@ func (s *Server) handleDepRemove(req *Request) Response {
 		func(ctx context.Context, store storage.Storage, actor string) error {
 			return store.RemoveDependency(ctx, depArgs.FromID, depArgs.ToID, actor)
 		},
-		func() string { return depArgs.FromID },
+		func() MutationEvent {
+			return MutationEvent{Type: MutationDepRemove, IssueID: depArgs.FromID, DependsOnID: depArgs.ToID}
+		},
 		func() map[string]interface{} {
 			return map[string]interface{}{
 				"status":        "removed",
@@ -190,14 +201,14 @@ func (s *Server) handleLabelAdd(req *Request) Response {
 	var labelArgs LabelAddArgs
 	return s.handleSimpleStoreOp(req, &labelArgs, "label add", func(ctx context.Context, store storage.Storage, actor string) error {
 		return store.AddLabel(ctx, labelArgs.ID, labelArgs.Label, actor)
-	}, func() string { return labelArgs.ID }, nil)
+	}, func() MutationEvent { return MutationEvent{Type: MutationUpdate, IssueID: labelArgs.ID} }, nil)
 }
 
 func (s *Server) handleLabelRemove(req *Request) Response {
 	var labelArgs LabelRemoveArgs
 	return s.handleSimpleStoreOp(req, &labelArgs, "label remove", func(ctx context.Context, store storage.Storage, actor string) error {
 		return store.RemoveLabel(ctx, labelArgs.ID, labelArgs.Label, actor)
-	}, func() string { return labelArgs.ID }, nil)
+	}, func() MutationEvent { return MutationEvent{Type: MutationUpdate, IssueID: labelArgs.ID} }, nil)
 }
 
 func (s *Server) handleCommentList(req *Request) Response {
@@ -213,7 +224,7 @@ func (s *Server) handleCommentList(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -247,7 +258,7 @@ func (s *Server) handleCommentAdd(req *Request) Response {
 	if store == nil {
 		return Response{
 			Success: false,
-			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'fbd daemon' in your project)",
+			Error:   "storage not available (start the daemon with 'fbd serve' in your project)",
 		}
 	}
 
@@ -219,7 +219,7 @@ func TestHandleSimpleStoreOp_MutationIssueID(t *testing.T) {
 		mutations := server.GetRecentMutations(checkpoint)
 		found := false
 		for _, m := range mutations {
-			if m.Type == MutationUpdate && m.IssueID == issueA.ID {
+			if m.Type == MutationDepRemove && m.IssueID == issueA.ID && m.DependsOnID == issueB.ID {
 				found = true
 				break
 			}
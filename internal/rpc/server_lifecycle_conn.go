@@ -142,6 +142,20 @@ func (s *Server) Stop() error {
 			}
 		}
 
+		// Stop the HTTP gateway; event streams exit on shutdownChan
+		s.mu.Lock()
+		httpServer := s.httpServer
+		s.httpServer = nil
+		s.mu.Unlock()
+
+		if httpServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if shutdownErr := httpServer.Shutdown(ctx); shutdownErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to stop HTTP gateway: %v\n", shutdownErr)
+			}
+			cancel()
+		}
+
 		// Wait for in-flight connection goroutines to drain (with timeout)
 		drainDone := make(chan struct{})
 		go func() {
@@ -155,7 +169,13 @@ func (s *Server) Stop() error {
 			// Timeout waiting for connections to drain - proceed with shutdown
 		}
 
-		// Close storage after in-flight requests complete
+		// Close storage after in-flight requests and stop hooks complete
+		s.mu.RLock()
+		hooks := s.stopHooks
+		s.mu.RUnlock()
+		for _, hook := range hooks {
+			hook()
+		}
 		if s.storage != nil {
 			if closeErr := s.storage.Close(); closeErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to close default storage: %v\n", closeErr)
@@ -256,6 +276,12 @@ func (s *Server) handleConnection(conn net.Conn) {
 			continue
 		}
 
+		// Subscriptions take over the connection until the client leaves
+		if req.Operation == OpSubscribe {
+			s.serveSubscription(conn, writer, &req)
+			return
+		}
+
 		// Set write deadline for the response
 		if err := conn.SetWriteDeadline(time.Now().Add(s.requestTimeout)); err != nil {
 			return
@@ -48,7 +48,7 @@ func (s *Server) checkVersionCompatibility(clientVersion string) error {
 		cmp := semver.Compare(serverVer, clientVer)
 		if cmp < 0 {
 			// Daemon is older - needs upgrade
-			return fmt.Errorf("incompatible major versions: client %s, daemon %s. Daemon is older; upgrade and restart daemon: 'fbd daemon stop . && fbd daemon start'",
+			return fmt.Errorf("incompatible major versions: client %s, daemon %s. Daemon is older; upgrade and restart daemon: 'fbd serve stop && fbd serve'",
 				clientVersion, ServerVersion)
 		}
 		// Daemon is newer - client needs upgrade
@@ -67,12 +67,12 @@ func (s *Server) checkVersionCompatibility(clientVersion string) error {
 
 		if serverMinor != clientMinor {
 			// Minor version mismatch - schema may be incompatible
-			return fmt.Errorf("version mismatch: client v%s requires daemon upgrade (daemon is v%s). The client may expect schema changes not present in this daemon version. Run: fbd daemons killall",
+			return fmt.Errorf("version mismatch: client v%s requires daemon upgrade (daemon is v%s). The client may expect schema changes not present in this daemon version. Restart daemon: 'fbd serve stop && fbd serve'",
 				clientVersion, ServerVersion)
 		}
 
 		// Patch version difference - usually safe but warn
-		return fmt.Errorf("version mismatch: daemon v%s is older than client v%s. Upgrade and restart daemon: fbd daemons killall",
+		return fmt.Errorf("version mismatch: daemon v%s is older than client v%s. Upgrade and restart daemon: 'fbd serve stop && fbd serve'",
 			ServerVersion, clientVersion)
 	}
 
@@ -220,6 +220,8 @@ func (s *Server) handleRequest(req *Request) Response {
 		resp = s.handleEpicStatus(req)
 	case OpGetMutations:
 		resp = s.handleGetMutations(req)
+	case OpSubscribe:
+		resp = s.handleSubscribe(req)
 	case OpGetMoleculeProgress:
 		resp = s.handleGetMoleculeProgress(req)
 	case OpGetWorkerStatus:
@@ -269,7 +271,7 @@ func skipAutoImport(op string) bool {
 	case OpShutdown:
 		// Importing during shutdown is counterproductive
 		return true
-	case OpGetMutations, OpGetMoleculeProgress, OpGetWorkerStatus, OpGetConfig, OpMolStale, OpCompactStats:
+	case OpGetMutations, OpSubscribe, OpGetMoleculeProgress, OpGetWorkerStatus, OpGetConfig, OpMolStale, OpCompactStats:
 		// Read-only diagnostic/monitoring operations
 		return true
 	case OpGateCreate, OpGateList, OpGateShow, OpGateClose, OpGateWait:
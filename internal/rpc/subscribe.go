@@ -0,0 +1,329 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/debug"
+	"github.com/steveyegge/fastbeads/internal/query"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// mutationStreamKeepalive is how often an idle event stream (subscription or
+// HTTP event stream) sends a heartbeat, so dead peers and proxies are noticed.
+const mutationStreamKeepalive = 15 * time.Second
+
+// mutationStreamBuffer is the per-stream mutation buffer. A subscriber that
+// falls further behind misses events and should refetch.
+const mutationStreamBuffer = 256
+
+// MutationFilter matches mutation events against a query expression,
+// evaluated against the event's issue as it is after the mutation. Events
+// for issues that no longer exist (deletes) never match.
+type MutationFilter struct {
+	node query.Node
+}
+
+// NewMutationFilter parses a query expression (see 'fbd query').
+func NewMutationFilter(expr string) (*MutationFilter, error) {
+	node, err := query.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	// Compile once up front so errors (unknown fields, bad values) surface
+	// at subscribe time rather than being swallowed per event
+	if _, err := query.NewEvaluator(time.Now()).Matcher(node); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &MutationFilter{node: node}, nil
+}
+
+// Match reports whether the issue issueID currently matches the filter.
+func (f *MutationFilter) Match(ctx context.Context, store storage.Storage, issueID string) (bool, error) {
+	if issueID == "" {
+		return false, nil
+	}
+	issue, err := store.GetIssue(ctx, issueID)
+	if err != nil || issue == nil || issue.Status == types.StatusTombstone {
+		return false, err
+	}
+	if issue.Labels, err = store.GetLabels(ctx, issueID); err != nil {
+		return false, err
+	}
+	if issue.Dependencies, err = store.GetDependencyRecords(ctx, issueID); err != nil {
+		return false, err
+	}
+
+	// A fresh evaluator per event: relative times ("updated>1h") and graph
+	// operators must see the current state, not the state at subscribe time
+	match, err := query.NewEvaluator(time.Now()).WithGraph(ctx, store).Matcher(f.node)
+	if err != nil {
+		return false, err
+	}
+	return match(issue), nil
+}
+
+// handleSubscribe validates a subscribe request. The stream itself is
+// served by serveSubscription, which owns the connection.
+func (s *Server) handleSubscribe(req *Request) Response {
+	var args SubscribeArgs
+	if len(req.Args) > 0 {
+		if err := json.Unmarshal(req.Args, &args); err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("invalid arguments: %v", err),
+			}
+		}
+	}
+	if args.Filter != "" {
+		if _, err := NewMutationFilter(args.Filter); err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+	}
+
+	result := SubscribeResult{Cursor: time.Now().UnixMilli()}
+	if args.Since != nil {
+		result.Gap = s.mutationGap(*args.Since)
+	}
+	data, _ := json.Marshal(result)
+	return Response{Success: true, Data: data}
+}
+
+// mutationGap reports whether events after since may have been lost: they
+// predate this server, or the recent-mutations buffer has overflowed past
+// since.
+func (s *Server) mutationGap(since int64) bool {
+	if since < s.startTime.UnixMilli() {
+		return true
+	}
+	s.recentMutationsMu.RLock()
+	defer s.recentMutationsMu.RUnlock()
+	return len(s.recentMutations) >= s.maxMutationBuffer &&
+		s.recentMutations[0].Timestamp.UnixMilli() > since
+}
+
+// serveSubscription streams mutation events on conn. The first line is the
+// handleSubscribe response; then one Response per event, with a heartbeat
+// (no Data) when idle. It returns when the client disconnects or the server
+// stops.
+func (s *Server) serveSubscription(conn net.Conn, writer *bufio.Writer, req *Request) {
+	var args SubscribeArgs
+	_ = json.Unmarshal(req.Args, &args) // Validated by handleSubscribe
+
+	write := func(resp Response) error {
+		if err := conn.SetWriteDeadline(time.Now().Add(s.requestTimeout)); err != nil {
+			return err
+		}
+		return s.writeResponse(writer, resp)
+	}
+
+	// The client sends nothing after subscribing; a read returning means it
+	// has gone away
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	since := int64(-1)
+	if args.Since != nil {
+		since = *args.Since
+	}
+	var filter *MutationFilter
+	if args.Filter != "" {
+		filter, _ = NewMutationFilter(args.Filter)
+	}
+
+	err := s.streamMutations(ctx, since, filter,
+		func() error {
+			resp := s.handleRequest(req)
+			if err := write(resp); err != nil {
+				return err
+			}
+			if !resp.Success {
+				return fmt.Errorf("subscribe rejected: %s", resp.Error)
+			}
+			_ = conn.SetReadDeadline(time.Time{})
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				cancel()
+			}()
+			return nil
+		},
+		func(event MutationEvent) error {
+			data, _ := json.Marshal(event)
+			return write(Response{Success: true, Data: data})
+		},
+		func() error {
+			return write(Response{Success: true})
+		})
+	if err != nil {
+		debug.Logf("subscription ended: %v", err)
+	}
+}
+
+// streamMutations delivers mutation events to send until ctx is done or the
+// server stops. It subscribes before calling ready (which typically writes
+// the stream header), so no event emitted after ready is missed. If since is
+// >= 0, buffered events after since are replayed first. Events whose issue
+// doesn't match filter (if non-nil) are skipped; heartbeat is called when
+// the stream has been idle for mutationStreamKeepalive.
+func (s *Server) streamMutations(ctx context.Context, since int64, filter *MutationFilter,
+	ready func() error, send func(MutationEvent) error, heartbeat func() error) error {
+	events, unsubscribe := s.subscribeMutations(mutationStreamBuffer)
+	defer unsubscribe()
+
+	if err := ready(); err != nil {
+		return err
+	}
+
+	deliver := func(event MutationEvent) error {
+		if filter != nil {
+			matchCtx, cancel := context.WithTimeout(ctx, s.requestTimeout)
+			ok, err := filter.Match(matchCtx, s.storage, event.IssueID)
+			cancel()
+			if err != nil {
+				debug.Logf("subscription filter failed for %s: %v", event.IssueID, err)
+			}
+			if !ok {
+				return nil
+			}
+		}
+		return send(event)
+	}
+
+	// Events emitted between subscribing and reading the buffer arrive both
+	// ways; the full-precision timestamp tells them apart
+	var lastReplayed time.Time
+	if since >= 0 {
+		for _, event := range s.GetRecentMutations(since) {
+			if err := deliver(event); err != nil {
+				return err
+			}
+			lastReplayed = event.Timestamp
+		}
+	}
+
+	keepalive := time.NewTicker(mutationStreamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if !event.Timestamp.After(lastReplayed) {
+				continue // Already replayed
+			}
+			if err := deliver(event); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.shutdownChan:
+			return nil
+		}
+	}
+}
+
+// Subscription is a live stream of mutation events from the daemon. It owns
+// the client's connection; close the Client when done.
+type Subscription struct {
+	// Result is the server's reply to the subscribe request.
+	Result SubscribeResult
+
+	client  *Client
+	scanner *bufio.Scanner
+}
+
+// Subscribe turns the connection into a stream of mutation events. After
+// this call the client can't be used for other requests.
+func (c *Client) Subscribe(args *SubscribeArgs) (*Subscription, error) {
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal args: %w", err)
+	}
+	req := Request{
+		Operation:     OpSubscribe,
+		Args:          argsJSON,
+		Actor:         c.actor,
+		ClientVersion: ClientVersion,
+		ExpectedDB:    c.dbPath,
+	}
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	}
+	if _, err := c.conn.Write(append(reqJSON, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+
+	sub := &Subscription{client: c, scanner: bufio.NewScanner(c.conn)}
+	sub.scanner.Buffer(make([]byte, 0, 64*1024), MaxMessageSize)
+
+	resp, err := sub.read()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("operation failed: %s", resp.Error)
+	}
+	if err := json.Unmarshal(resp.Data, &sub.Result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subscribe result: %w", err)
+	}
+	return sub, nil
+}
+
+// Next blocks until the next mutation event. It returns io.EOF when the
+// daemon closes the stream (e.g. on shutdown), and an error if the daemon
+// stops sending heartbeats.
+func (sub *Subscription) Next() (*MutationEvent, error) {
+	for {
+		// Heartbeats arrive every mutationStreamKeepalive; allow for slack
+		if err := sub.client.conn.SetReadDeadline(time.Now().Add(2*mutationStreamKeepalive + 5*time.Second)); err != nil {
+			return nil, err
+		}
+		resp, err := sub.read()
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("subscription failed: %s", resp.Error)
+		}
+		if len(resp.Data) == 0 {
+			continue // Heartbeat
+		}
+		var event MutationEvent
+		if err := json.Unmarshal(resp.Data, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		return &event, nil
+	}
+}
+
+func (sub *Subscription) read() (*Response, error) {
+	if !sub.scanner.Scan() {
+		if err := sub.scanner.Err(); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+		return nil, io.EOF
+	}
+	var resp Response
+	if err := json.Unmarshal(sub.scanner.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &resp, nil
+}
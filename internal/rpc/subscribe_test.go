@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// subscribe opens a second connection to server and subscribes on it.
+func subscribe(t *testing.T, server *Server, client *Client, args *SubscribeArgs) (*Subscription, error) {
+	t.Helper()
+	conn, err := TryConnect(server.socketPath)
+	if err != nil || conn == nil {
+		t.Fatalf("connect subscriber: %v", err)
+	}
+	conn.dbPath = client.dbPath
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn.Subscribe(args)
+}
+
+func nextEvent(t *testing.T, sub *Subscription) MutationEvent {
+	t.Helper()
+	type result struct {
+		event *MutationEvent
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		event, err := sub.Next()
+		ch <- result{event, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("Next: %v", r.err)
+		}
+		return *r.event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for mutation event")
+	}
+	return MutationEvent{}
+}
+
+func createTestIssue(t *testing.T, client *Client, title string, priority int) *types.Issue {
+	t.Helper()
+	resp, err := client.Create(&CreateArgs{Title: title, IssueType: "task", Priority: priority})
+	if err != nil {
+		t.Fatalf("create %q: %v", title, err)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(resp.Data, &issue); err != nil {
+		t.Fatalf("unmarshal issue: %v", err)
+	}
+	return &issue
+}
+
+func TestSubscribe_StreamsEvents(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	sub, err := subscribe(t, server, client, &SubscribeArgs{})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if sub.Result.Cursor == 0 || sub.Result.Gap {
+		t.Errorf("unexpected result: %+v", sub.Result)
+	}
+
+	issueA := createTestIssue(t, client, "First", 2)
+	event := nextEvent(t, sub)
+	if event.Type != MutationCreate || event.IssueID != issueA.ID {
+		t.Errorf("got %s %s, want create %s", event.Type, event.IssueID, issueA.ID)
+	}
+
+	issueB := createTestIssue(t, client, "Second", 2)
+	if _, err := client.AddDependency(&DepAddArgs{FromID: issueB.ID, ToID: issueA.ID, DepType: "blocks"}); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	nextEvent(t, sub) // create
+	event = nextEvent(t, sub)
+	if event.Type != MutationDepAdd || event.IssueID != issueB.ID || event.DependsOnID != issueA.ID {
+		t.Errorf("got %+v, want dep_add %s -> %s", event, issueB.ID, issueA.ID)
+	}
+}
+
+func TestSubscribe_Filter(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	sub, err := subscribe(t, server, client, &SubscribeArgs{Filter: "priority=0"})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	createTestIssue(t, client, "Routine", 2)
+	urgent := createTestIssue(t, client, "Urgent", 0)
+
+	if event := nextEvent(t, sub); event.IssueID != urgent.ID {
+		t.Errorf("got event for %s, want only %s", event.IssueID, urgent.ID)
+	}
+
+	if _, err := subscribe(t, server, client, &SubscribeArgs{Filter: "priority=="}); err == nil {
+		t.Error("expected invalid filter to be rejected")
+	}
+}
+
+func TestSubscribe_Replay(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	since := time.Now().UnixMilli()
+	issue := createTestIssue(t, client, "Before subscribing", 2)
+
+	sub, err := subscribe(t, server, client, &SubscribeArgs{Since: &since})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if sub.Result.Gap {
+		t.Error("unexpected gap for a cursor after server start")
+	}
+	if event := nextEvent(t, sub); event.Type != MutationCreate || event.IssueID != issue.ID {
+		t.Errorf("got %s %s, want replayed create %s", event.Type, event.IssueID, issue.ID)
+	}
+
+	// A cursor from before this server started may have missed events
+	old := server.startTime.Add(-time.Hour).UnixMilli()
+	sub, err = subscribe(t, server, client, &SubscribeArgs{Since: &old})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if !sub.Result.Gap {
+		t.Error("expected gap for a cursor before server start")
+	}
+}
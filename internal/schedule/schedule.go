@@ -0,0 +1,451 @@
+// Package schedule forecasts when an epic's issues will finish using the
+// critical path method. Estimates give each issue a duration, blocking and
+// parent-child dependencies order the work, and optional per-assignee
+// capacity limits how much of it runs in parallel.
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// DefaultHoursPerDay is the working time per calendar day assumed for
+// assignees without an explicit capacity.
+const DefaultHoursPerDay = 8.0
+
+// Options configures a forecast.
+type Options struct {
+	Start           time.Time // Plan start; zero means now
+	HoursPerDay     float64   // Working hours per calendar day; 0 means DefaultHoursPerDay
+	DefaultEstimate int       // Minutes assumed for open issues without an estimate
+
+	// Capacity maps assignees to working hours per day. A listed assignee
+	// works on one issue at a time; other assignees are assumed to work on
+	// all of their ready issues in parallel.
+	Capacity map[string]float64
+}
+
+// Task is the forecast for one issue of the epic.
+type Task struct {
+	ID              string       `json:"id"`
+	Title           string       `json:"title"`
+	Status          types.Status `json:"status"`
+	Priority        int          `json:"priority"`
+	Assignee        string       `json:"assignee,omitempty"`
+	EstimateMinutes int          `json:"estimate_minutes"`
+	Estimated       bool         `json:"estimated"`            // False if Options.DefaultEstimate was assumed
+	Container       bool         `json:"container,omitempty"`  // Has children; finishes when they do
+	BlockedBy       []string     `json:"blocked_by,omitempty"` // Blocking dependencies within the epic
+
+	EarliestStart  time.Time  `json:"earliest_start"`
+	EarliestFinish time.Time  `json:"earliest_finish"`
+	LatestStart    time.Time  `json:"latest_start"`
+	LatestFinish   time.Time  `json:"latest_finish"`
+	SlackMinutes   int        `json:"slack_minutes"`
+	Critical       bool       `json:"critical"`
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	Late           bool       `json:"late"` // Projected finish is past due_at or the epic's due_at
+
+	duration       time.Duration
+	es, ef, ls, lf time.Duration // Offsets from Plan.Start
+	preds, succs   []string      // Scheduling constraints, including hierarchy and capacity
+}
+
+// Plan is the forecast for an epic.
+type Plan struct {
+	EpicID       string     `json:"epic_id"`
+	Title        string     `json:"title"`
+	Start        time.Time  `json:"start"`
+	Finish       time.Time  `json:"finish"` // Projected finish of the last task
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	Late         bool       `json:"late"`
+	CriticalPath []string   `json:"critical_path"` // Task IDs, first to last
+	Tasks        []*Task    `json:"tasks"`         // Ordered by earliest start
+	Warnings     []string   `json:"warnings,omitempty"`
+}
+
+// isOrderingEdge reports whether a dependency type means the dependent
+// issue cannot start until the other finishes.
+func isOrderingEdge(t types.DependencyType) bool {
+	return t == types.DepBlocks || t == types.DepConditionalBlocks || t == types.DepWaitsFor
+}
+
+// Build forecasts the epic. issues must contain the epic's descendants and
+// may contain other issues (used to check blockers outside the epic); deps
+// maps issue IDs to their dependency records, as returned by
+// GetDependencyRecordsForIssues.
+//
+// Descendants are found through parent-child edges. A parent finishes when
+// its last child does, and its children cannot start until its blockers
+// finish. Closed issues take no time. Build returns an error if the blocking
+// dependencies within the epic form a cycle.
+func Build(epic *types.Issue, issues []*types.Issue, deps map[string][]*types.Dependency, opts Options) (*Plan, error) {
+	if opts.Start.IsZero() {
+		opts.Start = time.Now()
+	}
+	opts.Start = opts.Start.Truncate(time.Minute)
+	if opts.HoursPerDay <= 0 {
+		opts.HoursPerDay = DefaultHoursPerDay
+	}
+	for assignee, hours := range opts.Capacity {
+		if hours <= 0 || hours > 24 {
+			return nil, fmt.Errorf("capacity for %s must be between 0 and 24 hours per day, got %g", assignee, hours)
+		}
+	}
+
+	byID := make(map[string]*types.Issue, len(issues)+1)
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	byID[epic.ID] = epic
+
+	children := make(map[string][]string)
+	for id, records := range deps {
+		if byID[id] == nil {
+			continue
+		}
+		for _, dep := range records {
+			if dep.Type == types.DepParentChild && byID[dep.DependsOnID] != nil {
+				children[dep.DependsOnID] = append(children[dep.DependsOnID], id)
+			}
+		}
+	}
+
+	// Descendants of the epic, with each one's parent within the plan
+	parent := make(map[string]string)
+	queue := []string{epic.ID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if _, seen := parent[child]; seen || child == epic.ID {
+				continue
+			}
+			parent[child] = id
+			queue = append(queue, child)
+		}
+	}
+
+	plan := &Plan{
+		EpicID:       epic.ID,
+		Title:        epic.Title,
+		Start:        opts.Start,
+		DueAt:        epic.DueAt,
+		CriticalPath: []string{},
+		Tasks:        []*Task{},
+	}
+	tasks := make(map[string]*Task, len(parent))
+	var unestimated []string
+	for id := range parent {
+		issue := byID[id]
+		t := &Task{
+			ID:        id,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Priority:  issue.Priority,
+			Assignee:  issue.Assignee,
+			Container: len(children[id]) > 0,
+			DueAt:     issue.DueAt,
+			Estimated: issue.EstimatedMinutes != nil,
+		}
+		if issue.EstimatedMinutes != nil {
+			t.EstimateMinutes = *issue.EstimatedMinutes
+		}
+		if t.Status != types.StatusClosed && !t.Container {
+			if !t.Estimated {
+				t.EstimateMinutes = opts.DefaultEstimate
+				unestimated = append(unestimated, id)
+			}
+			t.duration = elapsed(t.EstimateMinutes, t.Assignee, opts)
+		}
+		tasks[id] = t
+		plan.Tasks = append(plan.Tasks, t)
+	}
+	if len(unestimated) > 0 {
+		sort.Strings(unestimated)
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d open issue(s) have no estimate, assumed %d min: %s",
+			len(unestimated), opts.DefaultEstimate, strings.Join(unestimated, ", ")))
+	}
+
+	// Blocking edges, including blockers inherited from ancestors
+	blockers := make(map[string][]string)
+	for _, id := range append([]string{epic.ID}, sortedKeys(tasks)...) {
+		for _, dep := range deps[id] {
+			if !isOrderingEdge(dep.Type) || dep.DependsOnID == epic.ID {
+				continue
+			}
+			if tasks[dep.DependsOnID] != nil {
+				blockers[id] = append(blockers[id], dep.DependsOnID)
+				continue
+			}
+			if blocker := byID[dep.DependsOnID]; blocker == nil || blocker.Status != types.StatusClosed {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is blocked by %s, which is outside %s and not scheduled",
+					id, dep.DependsOnID, epic.ID))
+			}
+		}
+	}
+	edges := make(map[[2]string]bool)
+	addEdge := func(from, to string) {
+		if from == to || edges[[2]string{from, to}] {
+			return
+		}
+		edges[[2]string{from, to}] = true
+		tasks[to].preds = append(tasks[to].preds, from)
+		tasks[from].succs = append(tasks[from].succs, to)
+	}
+	for _, id := range sortedKeys(tasks) {
+		tasks[id].BlockedBy = append(tasks[id].BlockedBy, blockers[id]...)
+		sort.Strings(tasks[id].BlockedBy)
+		for ancestor := id; ancestor != epic.ID; ancestor = parent[ancestor] {
+			for _, b := range blockers[ancestor] {
+				addEdge(b, id)
+			}
+		}
+		if p := parent[id]; p != epic.ID {
+			addEdge(id, p)
+		}
+	}
+
+	order, err := topoOrder(tasks)
+	if err != nil {
+		return nil, err
+	}
+	computeTimes(tasks, order)
+	order = levelCapacity(tasks, order, opts.Capacity, addEdge)
+	end := computeTimes(tasks, order)
+
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	for _, t := range plan.Tasks {
+		t.EarliestStart = opts.Start.Add(t.es)
+		t.EarliestFinish = opts.Start.Add(t.ef)
+		t.LatestStart = opts.Start.Add(t.ls)
+		t.LatestFinish = opts.Start.Add(t.lf)
+		t.SlackMinutes = int((t.ls - t.es) / time.Minute)
+		t.Critical = t.Status != types.StatusClosed && t.ls == t.es
+		if t.DueAt != nil && t.EarliestFinish.After(*t.DueAt) {
+			t.Late = true
+		}
+		if epic.DueAt != nil && t.EarliestFinish.After(*epic.DueAt) {
+			t.Late = true
+		}
+	}
+	sort.Slice(plan.Tasks, func(i, j int) bool {
+		a, b := plan.Tasks[i], plan.Tasks[j]
+		if a.es != b.es {
+			return a.es < b.es
+		}
+		return position[a.ID] < position[b.ID]
+	})
+
+	plan.Finish = opts.Start.Add(end)
+	plan.Late = epic.DueAt != nil && plan.Finish.After(*epic.DueAt)
+	plan.CriticalPath = criticalPath(tasks, order, end)
+	return plan, nil
+}
+
+// elapsed converts an estimate in working minutes to calendar time at the
+// assignee's hours per day.
+func elapsed(minutes int, assignee string, opts Options) time.Duration {
+	rate := opts.HoursPerDay
+	if c, ok := opts.Capacity[assignee]; ok && assignee != "" {
+		rate = c
+	}
+	return time.Duration(math.Round(float64(minutes)*24/rate)) * time.Minute
+}
+
+func sortedKeys(tasks map[string]*Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for id := range tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// topoOrder orders tasks so every task follows its predecessors, taking
+// ready tasks by priority then ID.
+func topoOrder(tasks map[string]*Task) ([]string, error) {
+	remaining := make(map[string]int, len(tasks))
+	var ready []string
+	for id, t := range tasks {
+		remaining[id] = len(t.preds)
+		if len(t.preds) == 0 {
+			ready = append(ready, id)
+		}
+	}
+	order := make([]string, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			a, b := tasks[ready[i]], tasks[ready[j]]
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+			return a.ID < b.ID
+		})
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, s := range tasks[id].succs {
+			if remaining[s]--; remaining[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+	if len(order) < len(tasks) {
+		var cycle []string
+		for id, n := range remaining {
+			if n > 0 {
+				cycle = append(cycle, id)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle among %s", strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
+
+// computeTimes runs the forward and backward passes over tasks in
+// topological order and returns the project duration.
+func computeTimes(tasks map[string]*Task, order []string) time.Duration {
+	var end time.Duration
+	for _, id := range order {
+		t := tasks[id]
+		t.es = 0
+		for _, p := range t.preds {
+			if ef := tasks[p].ef; ef > t.es {
+				t.es = ef
+			}
+		}
+		t.ef = t.es + t.duration
+		if t.ef > end {
+			end = t.ef
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		t := tasks[order[i]]
+		t.lf = end
+		for _, s := range t.succs {
+			if ls := tasks[s].ls; ls < t.lf {
+				t.lf = ls
+			}
+		}
+		t.ls = t.lf - t.duration
+	}
+	return end
+}
+
+// levelCapacity schedules the work of assignees with a capacity one task at
+// a time, taking ready tasks in order of latest start (from an unleveled
+// computeTimes pass), and records each assignee's sequence as extra edges.
+// It returns a topological order that includes them.
+func levelCapacity(tasks map[string]*Task, order []string, capacity map[string]float64, addEdge func(from, to string)) []string {
+	limited := func(t *Task) bool {
+		_, ok := capacity[t.Assignee]
+		return ok && t.Assignee != "" && t.duration > 0
+	}
+	leveled := false
+	for _, t := range tasks {
+		leveled = leveled || limited(t)
+	}
+	if !leveled {
+		return order
+	}
+
+	remaining := make(map[string]int, len(tasks))
+	var ready []string
+	for id, t := range tasks {
+		remaining[id] = len(t.preds)
+		if len(t.preds) == 0 {
+			ready = append(ready, id)
+		}
+	}
+	finish := make(map[string]time.Duration, len(tasks))
+	free := make(map[string]time.Duration)
+	last := make(map[string]string)
+	scheduled := make([]string, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			a, b := tasks[ready[i]], tasks[ready[j]]
+			if a.ls != b.ls {
+				return a.ls < b.ls
+			}
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+			return a.ID < b.ID
+		})
+		id := ready[0]
+		ready = ready[1:]
+		t := tasks[id]
+		var start time.Duration
+		for _, p := range t.preds {
+			if finish[p] > start {
+				start = finish[p]
+			}
+		}
+		if limited(t) {
+			if prev, ok := last[t.Assignee]; ok {
+				addEdge(prev, id)
+				if free[t.Assignee] > start {
+					start = free[t.Assignee]
+				}
+			}
+			free[t.Assignee] = start + t.duration
+			last[t.Assignee] = id
+		}
+		finish[id] = start + t.duration
+		scheduled = append(scheduled, id)
+		for _, s := range t.succs {
+			if remaining[s]--; remaining[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+	// Each added edge runs from an earlier scheduled task to the one being
+	// scheduled, so the scheduling order stays topological
+	return scheduled
+}
+
+// criticalPath walks back from the task finishing last through
+// predecessors with no slack that finish exactly when it starts. Parent
+// tasks and closed tasks are passed through but not listed.
+func criticalPath(tasks map[string]*Task, order []string, end time.Duration) []string {
+	var current *Task
+	for i := len(order) - 1; i >= 0; i-- {
+		if t := tasks[order[i]]; t.ef == end && t.ls == t.es {
+			current = t
+			break
+		}
+	}
+	var path []string
+	for current != nil {
+		if !current.Container && current.Status != types.StatusClosed {
+			path = append(path, current.ID)
+		}
+		var next *Task
+		preds := append([]string(nil), current.preds...)
+		sort.Strings(preds)
+		for _, p := range preds {
+			if t := tasks[p]; t.ef == current.es && t.ls == t.es {
+				next = t
+				break
+			}
+		}
+		current = next
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	if path == nil {
+		path = []string{}
+	}
+	return path
+}
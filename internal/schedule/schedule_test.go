@@ -0,0 +1,201 @@
+package schedule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+var start = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// fixture builds issues and dependency records for Build.
+type fixture struct {
+	epic   *types.Issue
+	issues []*types.Issue
+	deps   map[string][]*types.Dependency
+}
+
+func newFixture() *fixture {
+	epic := &types.Issue{ID: "e", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic}
+	return &fixture{epic: epic, deps: make(map[string][]*types.Dependency)}
+}
+
+// task adds an open child of parent with an estimate in hours (< 0 for none).
+func (f *fixture) task(id, parent string, hours int, assignee string) *types.Issue {
+	issue := &types.Issue{ID: id, Title: "Task " + id, Status: types.StatusOpen, Assignee: assignee}
+	if hours >= 0 {
+		minutes := hours * 60
+		issue.EstimatedMinutes = &minutes
+	}
+	f.issues = append(f.issues, issue)
+	f.dep(id, parent, types.DepParentChild)
+	return issue
+}
+
+func (f *fixture) dep(id, dependsOn string, t types.DependencyType) {
+	f.deps[id] = append(f.deps[id], &types.Dependency{IssueID: id, DependsOnID: dependsOn, Type: t})
+}
+
+func (f *fixture) build(t *testing.T, opts Options) (*Plan, map[string]*Task) {
+	t.Helper()
+	opts.Start = start
+	plan, err := Build(f.epic, f.issues, f.deps, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	byID := make(map[string]*Task)
+	for _, task := range plan.Tasks {
+		byID[task.ID] = task
+	}
+	return plan, byID
+}
+
+func day(n float64) time.Time {
+	return start.Add(time.Duration(n * float64(24*time.Hour)))
+}
+
+func TestBuildCriticalPath(t *testing.T) {
+	// a(1d) -> b(2d) -> d(1d)
+	// a(1d) -> c(1d) -> d
+	f := newFixture()
+	f.task("a", "e", 8, "")
+	f.task("b", "e", 16, "")
+	f.task("c", "e", 8, "")
+	f.task("d", "e", 8, "")
+	f.dep("b", "a", types.DepBlocks)
+	f.dep("c", "a", types.DepBlocks)
+	f.dep("d", "b", types.DepBlocks)
+	f.dep("d", "c", types.DepBlocks)
+
+	plan, tasks := f.build(t, Options{})
+
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(plan.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", plan.CriticalPath, want)
+	}
+	if !plan.Finish.Equal(day(4)) {
+		t.Errorf("Finish = %v, want %v", plan.Finish, day(4))
+	}
+	c := tasks["c"]
+	if !c.EarliestStart.Equal(day(1)) || !c.LatestStart.Equal(day(2)) {
+		t.Errorf("c: ES = %v, LS = %v", c.EarliestStart, c.LatestStart)
+	}
+	if c.SlackMinutes != 24*60 || c.Critical {
+		t.Errorf("c: slack = %d, critical = %v; want one day, not critical", c.SlackMinutes, c.Critical)
+	}
+	if got := []string{plan.Tasks[0].ID, plan.Tasks[len(plan.Tasks)-1].ID}; got[0] != "a" || got[1] != "d" {
+		t.Errorf("tasks not ordered by earliest start: %v", got)
+	}
+}
+
+func TestBuildNestedParents(t *testing.T) {
+	// Sub-epic s holds s1 and s2; x is blocked by s, and s is blocked by y
+	f := newFixture()
+	f.task("y", "e", 8, "")
+	f.task("s", "e", -1, "")
+	f.task("s1", "s", 8, "")
+	f.task("s2", "s", 16, "")
+	f.task("x", "e", 8, "")
+	f.dep("s", "y", types.DepBlocks)
+	f.dep("x", "s", types.DepBlocks)
+
+	plan, tasks := f.build(t, Options{})
+
+	if !tasks["s1"].EarliestStart.Equal(day(1)) {
+		t.Errorf("s1 should inherit s's blocker: ES = %v", tasks["s1"].EarliestStart)
+	}
+	if !tasks["s"].Container || !tasks["s"].EarliestFinish.Equal(day(3)) {
+		t.Errorf("s should finish with its last child: %+v", tasks["s"])
+	}
+	if !tasks["x"].EarliestStart.Equal(day(3)) {
+		t.Errorf("x ES = %v, want %v", tasks["x"].EarliestStart, day(3))
+	}
+	if want := []string{"y", "s2", "x"}; !reflect.DeepEqual(plan.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", plan.CriticalPath, want)
+	}
+	if len(plan.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", plan.Warnings)
+	}
+}
+
+func TestBuildCapacity(t *testing.T) {
+	// Three independent one-day tasks, two of them alice's
+	f := newFixture()
+	f.task("a1", "e", 8, "alice")
+	f.task("a2", "e", 8, "alice")
+	f.task("b1", "e", 8, "bob")
+
+	plan, _ := f.build(t, Options{})
+	if !plan.Finish.Equal(day(1)) {
+		t.Errorf("unlimited: Finish = %v, want %v", plan.Finish, day(1))
+	}
+
+	plan, tasks := f.build(t, Options{Capacity: map[string]float64{"alice": 4}})
+	if !plan.Finish.Equal(day(4)) {
+		t.Errorf("alice at 4h/day: Finish = %v, want %v", plan.Finish, day(4))
+	}
+	if !tasks["a2"].EarliestStart.Equal(day(2)) {
+		t.Errorf("a2 should wait for a1: ES = %v", tasks["a2"].EarliestStart)
+	}
+	if want := []string{"a1", "a2"}; !reflect.DeepEqual(plan.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", plan.CriticalPath, want)
+	}
+	if tasks["b1"].Critical {
+		t.Error("b1 should have slack")
+	}
+
+	if _, err := Build(f.epic, f.issues, f.deps, Options{Capacity: map[string]float64{"alice": 0}}); err == nil {
+		t.Error("expected an error for zero capacity")
+	}
+}
+
+func TestBuildDueDatesAndWarnings(t *testing.T) {
+	f := newFixture()
+	epicDue := day(2.5)
+	f.epic.DueAt = &epicDue
+	a := f.task("a", "e", 8, "")
+	aDue := day(0.5)
+	a.DueAt = &aDue
+	f.task("b", "e", -1, "")
+	f.task("c", "e", 16, "")
+	f.dep("c", "a", types.DepBlocks)
+	f.dep("c", "ext-1", types.DepBlocks)
+	done := f.task("done", "e", 80, "")
+	done.Status = types.StatusClosed
+
+	plan, tasks := f.build(t, Options{DefaultEstimate: 120})
+
+	if !tasks["a"].Late || tasks["b"].Late {
+		t.Errorf("a should be late against its own due date, b on time: a=%v b=%v", tasks["a"].Late, tasks["b"].Late)
+	}
+	if !tasks["c"].Late || !plan.Late {
+		t.Error("c finishes after the epic's due date")
+	}
+	if tasks["b"].Estimated || tasks["b"].EstimateMinutes != 120 {
+		t.Errorf("b should use the default estimate: %+v", tasks["b"])
+	}
+	if !tasks["done"].EarliestFinish.Equal(start) {
+		t.Errorf("closed issues take no time: %v", tasks["done"].EarliestFinish)
+	}
+	warnings := strings.Join(plan.Warnings, "\n")
+	for _, want := range []string{"no estimate", "b", "ext-1"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings missing %q: %v", want, plan.Warnings)
+		}
+	}
+}
+
+func TestBuildCycle(t *testing.T) {
+	f := newFixture()
+	f.task("a", "e", 8, "")
+	f.task("b", "e", 8, "")
+	f.dep("a", "b", types.DepBlocks)
+	f.dep("b", "a", types.DepBlocks)
+
+	_, err := Build(f.epic, f.issues, f.deps, Options{Start: start})
+	if err == nil || !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("expected a cycle error naming a and b, got %v", err)
+	}
+}
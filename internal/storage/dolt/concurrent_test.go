@@ -9,6 +9,7 @@ package dolt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -651,6 +652,71 @@ func TestConcurrentDependencyOperations(t *testing.T) {
 	t.Logf("Child blocked: %v, blockers: %v", blocked, blockers)
 }
 
+// =============================================================================
+// Test: Concurrent Claims Under a WIP Limit
+// Agents of one rig claim different issues simultaneously.
+// Verify: No more claims succeed than the rig's limit allows
+// =============================================================================
+
+func TestConcurrentClaimWIPLimit(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := concurrentTestContext(t)
+	defer cancel()
+
+	if err := store.SetConfig(ctx, "wip.rig.alpha", "2"); err != nil {
+		t.Fatalf("failed to set WIP limit: %v", err)
+	}
+
+	const numGoroutines = 8
+	ids := make([]string, numGoroutines)
+	for i := range ids {
+		issue := &types.Issue{
+			ID:        fmt.Sprintf("test-wip-%d", i),
+			Title:     fmt.Sprintf("WIP Issue %d", i),
+			Status:    types.StatusOpen,
+			Priority:  2,
+			IssueType: types.TypeTask,
+		}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("failed to create issue %d: %v", i, err)
+		}
+		ids[i] = issue.ID
+	}
+
+	var wg sync.WaitGroup
+	var successCount atomic.Int32
+	for i, id := range ids {
+		wg.Add(1)
+		go func(n int, id string) {
+			defer wg.Done()
+			// Each claim counts toward a different issue row and assignee, so
+			// only the rig bucket can keep them from all succeeding
+			err := store.ClaimIssue(ctx, id, fmt.Sprintf("alpha/polecats/p%d", n))
+			switch {
+			case err == nil:
+				successCount.Add(1)
+			case !errors.Is(err, storage.ErrWIPLimitExceeded):
+				t.Errorf("unexpected error claiming %s: %v", id, err)
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	if successCount.Load() != 2 {
+		t.Errorf("expected exactly 2 successful claims, got %d", successCount.Load())
+	}
+	status := types.StatusInProgress
+	inProgress, err := store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		t.Fatalf("failed to search issues: %v", err)
+	}
+	if len(inProgress) != 2 {
+		t.Errorf("expected 2 issues in progress, got %d", len(inProgress))
+	}
+}
+
 // =============================================================================
 // Test: High Contention Stress Test
 // Many goroutines performing various operations simultaneously
@@ -358,6 +358,9 @@ func (s *DoltStore) GetDependencyCounts(ctx context.Context, issueIDs []string)
 // GetDependencyTree returns a dependency tree for visualization
 func (s *DoltStore) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
 	// Simple implementation - can be optimized with CTE
+	if maxDepth <= 0 {
+		maxDepth = 50
+	}
 	visited := make(map[string]bool)
 	return s.buildDependencyTree(ctx, issueID, 0, maxDepth, reverse, visited)
 }
@@ -367,6 +367,35 @@ func TestGetDependencyTree_Reverse(t *testing.T) {
 	}
 }
 
+func TestGetDependencyTree_DefaultDepth(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	root := &types.Issue{ID: "dtree-root", Title: "Root", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	child := &types.Issue{ID: "dtree-child", Title: "Child", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{root, child} {
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("failed to create issue: %v", err)
+		}
+	}
+	dep := &types.Dependency{IssueID: child.ID, DependsOnID: root.ID, Type: types.DepParentChild}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	// A zero depth means the default limit, as in the other backends
+	tree, err := store.GetDependencyTree(ctx, root.ID, 0, false, true)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Errorf("expected 2 nodes with default depth, got %d", len(tree))
+	}
+}
+
 // =============================================================================
 // DetectCycles Tests
 // =============================================================================
@@ -64,6 +64,22 @@ func (s *DoltStore) GetEvents(ctx context.Context, issueID string, limit int) ([
 	return events, rows.Err()
 }
 
+// GetLastEventID returns the ID of the newest event, or of the newest event
+// created before before when it is non-zero. Returns 0 if there is none.
+func (s *DoltStore) GetLastEventID(ctx context.Context, before time.Time) (int64, error) {
+	query := `SELECT COALESCE(MAX(id), 0) FROM events`
+	var args []any
+	if !before.IsZero() {
+		query += ` WHERE created_at < ?`
+		args = append(args, before.UTC())
+	}
+	var id int64
+	if err := s.queryRowContext(ctx, func(row *sql.Row) error { return row.Scan(&id) }, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to get last event id: %w", err)
+	}
+	return id, nil
+}
+
 // GetAllEventsSince returns all events with ID greater than sinceID, ordered by ID ascending.
 func (s *DoltStore) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
 	rows, err := s.queryContext(ctx, `
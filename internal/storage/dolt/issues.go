@@ -385,7 +385,8 @@ func (s *DoltStore) UpdateIssue(ctx context.Context, id string, updates map[stri
 
 // ClaimIssue atomically claims an issue using compare-and-swap semantics.
 // It sets the assignee to actor and status to "in_progress" only if the issue
-// currently has no assignee. Returns storage.ErrAlreadyClaimed if already claimed.
+// currently has no assignee. Returns storage.ErrAlreadyClaimed if already claimed,
+// or a *storage.WIPLimitError if the claim would exceed a WIP limit.
 func (s *DoltStore) ClaimIssue(ctx context.Context, id string, actor string) error {
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
@@ -395,6 +396,21 @@ func (s *DoltStore) ClaimIssue(ctx context.Context, id string, actor string) err
 		return fmt.Errorf("issue %s not found", id)
 	}
 
+	// Concurrent claims in the same WIP bucket conflict at commit (see
+	// lockWIPBucket); retrying recounts with the other claim committed
+	for attempt := 1; ; attempt++ {
+		err = s.claimIssue(ctx, id, actor, oldIssue)
+		if attempt == maxClaimAttempts || !isTransactionConflict(err) {
+			return err
+		}
+	}
+}
+
+// maxClaimAttempts bounds how often ClaimIssue retries after a commit conflict.
+const maxClaimAttempts = 5
+
+// claimIssue runs one claim transaction for ClaimIssue.
+func (s *DoltStore) claimIssue(ctx context.Context, id, actor string, oldIssue *types.Issue) error {
 	now := time.Now().UTC()
 
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -430,6 +446,15 @@ func (s *DoltStore) ClaimIssue(ctx context.Context, id string, actor string) err
 		return fmt.Errorf("%w by %s", storage.ErrAlreadyClaimed, currentAssignee)
 	}
 
+	// Enforce WIP limits in the same transaction; the claimed issue itself
+	// is excluded from the counts
+	lock := func(b *storage.WIPBucket) error {
+		return lockWIPBucket(ctx, tx, b, id, now)
+	}
+	if err := storage.CheckWIPLimitsTx(ctx, tx, id, actor, lock); err != nil {
+		return err
+	}
+
 	// Record the claim event
 	oldData, _ := json.Marshal(oldIssue)
 	newUpdates := map[string]interface{}{
@@ -449,6 +474,31 @@ func (s *DoltStore) ClaimIssue(ctx context.Context, id string, actor string) err
 	return tx.Commit()
 }
 
+// lockWIPBucket writes the WIP bucket's lock row with a value unique to
+// this claim. Dolt transactions don't lock the rows they count, so two
+// claims could each see room under a limit; writing the same row makes
+// the later commit fail with a conflict instead.
+func lockWIPBucket(ctx context.Context, tx *sql.Tx, b *storage.WIPBucket, issueID string, now time.Time) error {
+	bucket := b.Scope + ":" + b.Name
+	claim := issueID + " " + now.Format(time.RFC3339Nano)
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO wip_locks (bucket, claim) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE claim = ?
+	`, bucket, claim, claim)
+	return err
+}
+
+// isTransactionConflict reports whether err is Dolt rejecting a commit
+// that conflicts with a concurrently committed transaction.
+func isTransactionConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "serialization failure") ||
+		strings.Contains(errStr, "conflicts with a committed transaction")
+}
+
 // CloseIssue closes an issue with a reason
 func (s *DoltStore) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
 	now := time.Now().UTC()
@@ -10,11 +10,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
 // SearchIssues finds issues matching query and filters
 func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	cursor, err := types.DecodeCursor(filter.Cursor, types.PageOrderSearch)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -228,6 +234,13 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 		args = append(args, time.Now().UTC().Format(time.RFC3339), types.StatusClosed)
 	}
 
+	// Keyset pagination: resume after the cursor's sort key
+	if cursor != nil {
+		clause, keysetArgs := storage.SearchKeyset(cursor, "", "")
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
@@ -242,7 +255,7 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 	querySQL := fmt.Sprintf(`
 		SELECT id FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY priority ASC, created_at DESC, id ASC
 		%s
 	`, whereSQL, limitSQL)
 
@@ -257,6 +270,12 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 
 // GetReadyWork returns issues that are ready to work on (not blocked)
 func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	sortPolicy := filter.EffectiveSortPolicy()
+	cursor, err := types.DecodeCursor(filter.Cursor, string(sortPolicy))
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -297,8 +316,32 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 		)
 	`)
 
+	// The hybrid sort's recent bucket is fixed by the first page's cutoff
+	cutoff := filter.HybridCutoff()
+
+	// Keyset pagination: resume after the cursor's sort key
+	if cursor != nil {
+		clause, keysetArgs := storage.ReadyKeyset(cursor, "", "", "created_at >= ?", []interface{}{cutoff})
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
+
 	whereSQL := "WHERE " + strings.Join(whereClauses, " AND ")
 
+	// Same orders as the SQLite backend, each ending with id for stable pagination
+	var orderSQL string
+	switch sortPolicy {
+	case types.SortPolicyPriority:
+		orderSQL = "priority ASC, created_at ASC, id ASC"
+	case types.SortPolicyOldest:
+		orderSQL = "created_at ASC, id ASC"
+	default:
+		orderSQL = `CASE WHEN created_at >= ? THEN 0 ELSE 1 END ASC,
+			CASE WHEN created_at >= ? THEN priority ELSE NULL END ASC,
+			created_at ASC, id ASC`
+		args = append(args, cutoff, cutoff)
+	}
+
 	limitSQL := ""
 	if filter.Limit > 0 {
 		limitSQL = fmt.Sprintf(" LIMIT %d", filter.Limit)
@@ -308,9 +351,9 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	query := fmt.Sprintf(`
 		SELECT id FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY %s
 		%s
-	`, whereSQL, limitSQL)
+	`, whereSQL, orderSQL, limitSQL)
 
 	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
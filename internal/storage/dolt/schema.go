@@ -244,6 +244,13 @@ CREATE TABLE IF NOT EXISTS interactions (
     INDEX idx_interactions_parent_id (parent_id)
 );
 
+-- WIP lock rows: each claim under a WIP limit rewrites its buckets' rows
+-- so that concurrent claims in the same bucket conflict at commit
+CREATE TABLE IF NOT EXISTS wip_locks (
+    bucket VARCHAR(512) PRIMARY KEY,
+    claim VARCHAR(255) NOT NULL
+);
+
 -- Federation peers table (for SQL user authentication)
 -- Stores credentials for peer-to-peer Dolt remotes between Gas Towns
 CREATE TABLE IF NOT EXISTS federation_peers (
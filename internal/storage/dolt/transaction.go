@@ -108,6 +108,11 @@ func (t *doltTransaction) GetIssue(ctx context.Context, id string) (*types.Issue
 
 // SearchIssues searches for issues within the transaction
 func (t *doltTransaction) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	cursor, err := types.DecodeCursor(filter.Cursor, types.PageOrderSearch)
+	if err != nil {
+		return nil, err
+	}
+
 	// Simplified search for transaction context
 	whereClauses := []string{}
 	args := []interface{}{}
@@ -138,6 +143,11 @@ func (t *doltTransaction) SearchIssues(ctx context.Context, query string, filter
 		whereClauses = append(whereClauses, "source_repo = ?")
 		args = append(args, *filter.SourceRepo)
 	}
+	if cursor != nil {
+		clause, keysetArgs := storage.SearchKeyset(cursor, "", "")
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
 
 	whereSQL := ""
 	if len(whereClauses) > 0 {
@@ -145,7 +155,7 @@ func (t *doltTransaction) SearchIssues(ctx context.Context, query string, filter
 	}
 
 	rows, err := t.tx.QueryContext(ctx, fmt.Sprintf(`
-		SELECT id FROM issues %s ORDER BY priority ASC, created_at DESC
+		SELECT id FROM issues %s ORDER BY priority ASC, created_at DESC, id ASC
 	`, whereSQL), args...)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// KeysetColumn is one column of a keyset pagination sort key.
+type KeysetColumn struct {
+	Expr  string      // SQL expression, e.g. "i.priority"
+	Param string      // SQL for the cursor value; "?" if empty
+	Value interface{} // The cursor's value for the column
+	Desc  bool        // Column is sorted descending
+}
+
+// KeysetClause builds a WHERE clause selecting rows that sort strictly after
+// the cursor values in the order given by cols:
+//
+//	a > ? OR (a = ? AND (b > ? OR (b = ? AND c > ?)))
+func KeysetClause(cols []KeysetColumn) (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+	for i, col := range cols {
+		param := col.Param
+		if param == "" {
+			param = "?"
+		}
+		op := " > "
+		if col.Desc {
+			op = " < "
+		}
+		if i == len(cols)-1 {
+			sb.WriteString(col.Expr + op + param)
+			args = append(args, col.Value)
+			break
+		}
+		sb.WriteString("(" + col.Expr + op + param + " OR (" + col.Expr + " = " + param + " AND ")
+		args = append(args, col.Value, col.Value)
+	}
+	sb.WriteString(strings.Repeat("))", len(cols)-1))
+	return "(" + sb.String() + ")", args
+}
+
+// createdAtColumn returns the created_at sort key column. timeExpr, if set,
+// is a format wrapping both the column and the cursor value (e.g.
+// "julianday(%s)"), for backends that store timestamps as text.
+func createdAtColumn(c *types.PageCursor, prefix, timeExpr string) KeysetColumn {
+	col := KeysetColumn{Expr: prefix + "created_at", Value: c.CreatedAt}
+	if timeExpr != "" {
+		col.Expr = fmt.Sprintf(timeExpr, col.Expr)
+		col.Param = fmt.Sprintf(timeExpr, "?")
+	}
+	return col
+}
+
+// SearchKeyset returns the WHERE clause for issues after c in
+// types.PageOrderSearch order. prefix qualifies column names (e.g. "i.");
+// timeExpr is described at createdAtColumn.
+func SearchKeyset(c *types.PageCursor, prefix, timeExpr string) (string, []interface{}) {
+	createdAt := createdAtColumn(c, prefix, timeExpr)
+	createdAt.Desc = true
+	return KeysetClause([]KeysetColumn{
+		{Expr: prefix + "priority", Value: c.Priority},
+		createdAt,
+		{Expr: prefix + "id", Value: c.ID},
+	})
+}
+
+// ReadyKeyset returns the WHERE clause for issues after c in its ready work
+// sort order. recentSQL is a boolean expression, with recentArgs, that is
+// true for issues in the hybrid sort's recent bucket at c.Cutoff.
+func ReadyKeyset(c *types.PageCursor, prefix, timeExpr, recentSQL string, recentArgs []interface{}) (string, []interface{}) {
+	priority := KeysetColumn{Expr: prefix + "priority", Value: c.Priority}
+	createdAt := createdAtColumn(c, prefix, timeExpr)
+	id := KeysetColumn{Expr: prefix + "id", Value: c.ID}
+
+	switch types.SortPolicy(c.Order) {
+	case types.SortPolicyOldest:
+		return KeysetClause([]KeysetColumn{createdAt, id})
+	case types.SortPolicyPriority:
+		return KeysetClause([]KeysetColumn{priority, createdAt, id})
+	}
+
+	// Hybrid: recent issues by priority, then older issues by age
+	if types.IsRecent(c.CreatedAt, c.Cutoff) {
+		after, args := KeysetClause([]KeysetColumn{priority, createdAt, id})
+		clause := "(NOT (" + recentSQL + ") OR ((" + recentSQL + ") AND " + after + "))"
+		all := append(append(append([]interface{}{}, recentArgs...), recentArgs...), args...)
+		return clause, all
+	}
+	after, args := KeysetClause([]KeysetColumn{createdAt, id})
+	return "(NOT (" + recentSQL + ") AND " + after + ")", append(append([]interface{}{}, recentArgs...), args...)
+}
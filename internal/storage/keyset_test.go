@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestKeysetClause(t *testing.T) {
+	clause, args := KeysetClause([]KeysetColumn{
+		{Expr: "priority", Value: 1},
+		{Expr: "julianday(created_at)", Param: "julianday(?)", Value: "t", Desc: true},
+		{Expr: "id", Value: "bd-1"},
+	})
+	want := "((priority > ? OR (priority = ? AND (julianday(created_at) < julianday(?) OR " +
+		"(julianday(created_at) = julianday(?) AND id > ?)))))"
+	if clause != want {
+		t.Errorf("clause =\n  %s\nwant\n  %s", clause, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 1, "t", "t", "bd-1"}) {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestReadyKeysetHybrid(t *testing.T) {
+	cutoff := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	recent := &types.PageCursor{Order: "hybrid", Priority: 2, CreatedAt: cutoff.Add(time.Hour), ID: "bd-1", Cutoff: cutoff}
+	old := &types.PageCursor{Order: "hybrid", Priority: 2, CreatedAt: cutoff.Add(-time.Hour), ID: "bd-1", Cutoff: cutoff}
+
+	// A recent cursor continues with later recent issues, then every old one
+	clause, args := ReadyKeyset(recent, "", "", "created_at >= ?", []interface{}{cutoff})
+	if want := "(NOT (created_at >= ?) OR ((created_at >= ?) AND ((priority > ? OR (priority = ? AND " +
+		"(created_at > ? OR (created_at = ? AND id > ?)))))))"; clause != want {
+		t.Errorf("recent clause =\n  %s\nwant\n  %s", clause, want)
+	}
+	if len(args) != 7 {
+		t.Errorf("recent args = %v", args)
+	}
+
+	// An old cursor stays in the old bucket, ordered by age
+	clause, args = ReadyKeyset(old, "", "", "created_at >= ?", []interface{}{cutoff})
+	if want := "(NOT (created_at >= ?) AND ((created_at > ? OR (created_at = ? AND id > ?))))"; clause != want {
+		t.Errorf("old clause =\n  %s\nwant\n  %s", clause, want)
+	}
+	if len(args) != 4 {
+		t.Errorf("old args = %v", args)
+	}
+}
@@ -529,7 +529,8 @@ func (m *MemoryStorage) CloseIssue(ctx context.Context, id string, reason string
 
 // ClaimIssue atomically claims an issue using compare-and-swap semantics.
 // It sets the assignee to actor and status to "in_progress" only if the issue
-// currently has no assignee. Returns storage.ErrAlreadyClaimed if already claimed.
+// currently has no assignee. Returns storage.ErrAlreadyClaimed if already claimed,
+// or a *storage.WIPLimitError if the claim would exceed a WIP limit.
 func (m *MemoryStorage) ClaimIssue(ctx context.Context, id string, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -544,6 +545,25 @@ func (m *MemoryStorage) ClaimIssue(ctx context.Context, id string, actor string)
 		return fmt.Errorf("%w by %s", storage.ErrAlreadyClaimed, issue.Assignee)
 	}
 
+	// Enforce WIP limits under the same lock
+	limits, err := storage.ParseWIPLimits(m.config)
+	if err != nil {
+		return err
+	}
+	if !limits.Empty() {
+		var inProgress []*types.Issue
+		for otherID, other := range m.issues {
+			if otherID != id && other.Status == types.StatusInProgress {
+				counted := *other
+				counted.Labels = m.labels[otherID]
+				inProgress = append(inProgress, &counted)
+			}
+		}
+		if err := limits.Check(actor, m.labels[id], storage.CountWIP(inProgress)); err != nil {
+			return err
+		}
+	}
+
 	// Perform the claim
 	now := time.Now()
 	issue.Assignee = actor
@@ -630,6 +650,11 @@ func (m *MemoryStorage) DeleteIssue(ctx context.Context, id string) error {
 
 // SearchIssues finds issues matching query and filters
 func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	cursor, err := types.DecodeCursor(filter.Cursor, types.PageOrderSearch)
+	if err != nil {
+		return nil, err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -759,13 +784,11 @@ func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter t
 		results = append(results, &issueCopy)
 	}
 
-	// Sort by priority, then by created_at
+	// Sort by priority, then newest first
 	sort.Slice(results, func(i, j int) bool {
-		if results[i].Priority != results[j].Priority {
-			return results[i].Priority < results[j].Priority
-		}
-		return results[i].CreatedAt.After(results[j].CreatedAt)
+		return types.SearchOrderLess(results[i], results[j])
 	})
+	results = pageAfter(results, cursor)
 
 	// Apply limit
 	if filter.Limit > 0 && len(results) > filter.Limit {
@@ -1235,6 +1258,11 @@ func (m *MemoryStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*
 
 // GetReadyWork returns issues that are ready to work on (no open blockers)
 func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	cursor, err := types.DecodeCursor(filter.Cursor, string(filter.EffectiveSortPolicy()))
+	if err != nil {
+		return nil, err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -1348,41 +1376,12 @@ func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 	}
 
 	// Default to hybrid sort for backwards compatibility
-	sortPolicy := filter.SortPolicy
-	if sortPolicy == "" {
-		sortPolicy = types.SortPolicyHybrid
-	}
-
-	switch sortPolicy {
-	case types.SortPolicyOldest:
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
-	case types.SortPolicyPriority:
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Priority != results[j].Priority {
-				return results[i].Priority < results[j].Priority
-			}
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
-	case types.SortPolicyHybrid:
-		fallthrough
-	default:
-		cutoff := time.Now().Add(-48 * time.Hour)
-		sort.Slice(results, func(i, j int) bool {
-			iRecent := results[i].CreatedAt.After(cutoff)
-			jRecent := results[j].CreatedAt.After(cutoff)
-			if iRecent != jRecent {
-				return iRecent // recent first
-			}
-			if iRecent {
-				if results[i].Priority != results[j].Priority {
-					return results[i].Priority < results[j].Priority
-				}
-			}
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
-	}
+	sortPolicy := filter.EffectiveSortPolicy()
+	cutoff := filter.HybridCutoff()
+	sort.Slice(results, func(i, j int) bool {
+		return types.ReadyOrderLess(sortPolicy, cutoff, results[i], results[j])
+	})
+	results = pageAfter(results, cursor)
 
 	// Apply limit
 	if filter.Limit > 0 && len(results) > filter.Limit {
@@ -1392,6 +1391,16 @@ func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 	return results, nil
 }
 
+// pageAfter drops the issues up to and including the cursor position from
+// sorted results.
+func pageAfter(results []*types.Issue, cursor *types.PageCursor) []*types.Issue {
+	if cursor == nil {
+		return results
+	}
+	i := sort.Search(len(results), func(i int) bool { return cursor.After(results[i]) })
+	return results[i:]
+}
+
 // getOpenBlockers returns the IDs of blockers that are currently open/in_progress/blocked/deferred/hooked.
 // The caller must hold at least a read lock.
 func (m *MemoryStorage) getOpenBlockers(issueID string) []string {
@@ -1623,6 +1632,23 @@ func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int
 	return events, nil
 }
 
+// GetLastEventID returns the ID of the newest event, or of the newest event
+// created before before when it is non-zero. Returns 0 if there is none.
+func (m *MemoryStorage) GetLastEventID(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var last int64
+	for _, issueEvents := range m.events {
+		for _, event := range issueEvents {
+			if event.ID > last && (before.IsZero() || event.CreatedAt.Before(before)) {
+				last = event.ID
+			}
+		}
+	}
+	return last, nil
+}
+
 // GetAllEventsSince returns all events with ID greater than sinceID, ordered by ID ascending.
 func (m *MemoryStorage) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
 	m.mu.RLock()
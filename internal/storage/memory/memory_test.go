@@ -2,11 +2,13 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/fastbeads/internal/config"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
@@ -1355,3 +1357,36 @@ func TestGetNextChildID_ConfigurableMaxDepth(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+func TestClaimIssueWIPLimit(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	if err := store.SetConfig(ctx, "wip.label.hot", "1"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	var ids []string
+	for i := 0; i < 2; i++ {
+		issue := &types.Issue{Title: "Hot", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, "hot", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	if err := store.ClaimIssue(ctx, ids[0], "alice"); err != nil {
+		t.Fatalf("first claim failed: %v", err)
+	}
+	err := store.ClaimIssue(ctx, ids[1], "bob")
+	var wipErr *storage.WIPLimitError
+	if !errors.As(err, &wipErr) || wipErr.Scope != storage.WIPScopeLabel || wipErr.Name != "hot" {
+		t.Fatalf("expected the hot label limit to be exceeded, got %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, ids[1]); got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("rejected claim changed the issue: status=%s assignee=%q", got.Status, got.Assignee)
+	}
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestCursorPagination(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i := 0; i < 11; i++ {
+		created := now.Add(-time.Duration(i) * 13 * time.Hour) // Spans the 48h hybrid window
+		if i%3 == 0 {
+			created = now.Add(-time.Hour) // Shared timestamps need the ID tie-breaker
+		}
+		issue := &types.Issue{
+			Title:     fmt.Sprintf("Issue %d", i),
+			Status:    types.StatusOpen,
+			Priority:  i % 3,
+			IssueType: types.TypeTask,
+			CreatedAt: created,
+		}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+
+	ids := func(issues []*types.Issue) []string {
+		out := make([]string, len(issues))
+		for i, issue := range issues {
+			out[i] = issue.ID
+		}
+		return out
+	}
+
+	t.Run("search", func(t *testing.T) {
+		all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			t.Fatalf("SearchIssues: %v", err)
+		}
+		var paged []*types.Issue
+		filter := types.IssueFilter{Limit: 4}
+		for {
+			page, err := store.SearchIssues(ctx, "", filter)
+			if err != nil {
+				t.Fatalf("SearchIssues: %v", err)
+			}
+			paged = append(paged, page...)
+			if filter.Cursor = filter.NextCursor(page); filter.Cursor == "" {
+				break
+			}
+		}
+		if fmt.Sprint(ids(paged)) != fmt.Sprint(ids(all)) {
+			t.Errorf("paged = %v, want %v", ids(paged), ids(all))
+		}
+	})
+
+	for _, policy := range []types.SortPolicy{types.SortPolicyHybrid, types.SortPolicyPriority, types.SortPolicyOldest} {
+		t.Run(string(policy), func(t *testing.T) {
+			all, err := store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: policy})
+			if err != nil {
+				t.Fatalf("GetReadyWork: %v", err)
+			}
+			var paged []*types.Issue
+			filter := types.WorkFilter{SortPolicy: policy, Limit: 3}
+			for {
+				page, err := store.GetReadyWork(ctx, filter)
+				if err != nil {
+					t.Fatalf("GetReadyWork: %v", err)
+				}
+				paged = append(paged, page...)
+				if filter.Cursor = filter.NextCursor(page); filter.Cursor == "" {
+					break
+				}
+			}
+			if fmt.Sprint(ids(paged)) != fmt.Sprint(ids(all)) {
+				t.Errorf("paged = %v, want %v", ids(paged), ids(all))
+			}
+		})
+	}
+}
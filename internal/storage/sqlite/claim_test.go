@@ -378,3 +378,179 @@ func TestClaimIssueMarksDirty(t *testing.T) {
 		t.Error("expected issue to be marked dirty after claim")
 	}
 }
+
+// TestClaimIssueWIPLimits tests that claims over a WIP limit fail with a typed error
+// and leave the issue untouched.
+func TestClaimIssueWIPLimits(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	create := func(title string, labels ...string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test-actor"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+		return issue
+	}
+	for key, value := range map[string]string{"wip.assignee": "2", "wip.assignee.lead": "1", "wip.label.frontend": "1", "wip.rig.alpha": "1"} {
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig failed: %v", err)
+		}
+	}
+
+	claim := func(issue *types.Issue, actor string) error {
+		return store.ClaimIssue(ctx, issue.ID, actor)
+	}
+	wantLimit := func(err error, scope, name string) {
+		t.Helper()
+		var wipErr *storage.WIPLimitError
+		if !errors.As(err, &wipErr) || !errors.Is(err, storage.ErrWIPLimitExceeded) {
+			t.Fatalf("expected a WIP limit error, got %v", err)
+		}
+		if wipErr.Scope != scope || wipErr.Name != name || wipErr.InProgress != wipErr.Limit {
+			t.Errorf("unexpected bucket %+v, want %s %s", wipErr.WIPBucket, scope, name)
+		}
+	}
+
+	// Default assignee limit
+	a1, a2, a3 := create("a1"), create("a2"), create("a3")
+	if err := claim(a1, "agent"); err != nil {
+		t.Fatalf("first claim failed: %v", err)
+	}
+	if err := claim(a2, "agent"); err != nil {
+		t.Fatalf("second claim failed: %v", err)
+	}
+	wantLimit(claim(a3, "agent"), storage.WIPScopeAssignee, "agent")
+	if got, _ := store.GetIssue(ctx, a3.ID); got.Assignee != "" || got.Status != types.StatusOpen {
+		t.Errorf("rejected claim changed the issue: assignee=%q status=%s", got.Assignee, got.Status)
+	}
+	// Per-assignee override
+	if err := claim(a3, "lead"); err != nil {
+		t.Fatalf("lead's first claim failed: %v", err)
+	}
+	wantLimit(claim(create("a4"), "lead"), storage.WIPScopeAssignee, "lead")
+
+	// Label and rig limits apply across assignees; the rig comes from the
+	// claiming agent's identity
+	if err := claim(create("f1", "frontend"), "x"); err != nil {
+		t.Fatalf("frontend claim failed: %v", err)
+	}
+	wantLimit(claim(create("f2", "frontend", "ui"), "y"), storage.WIPScopeLabel, "frontend")
+	if err := claim(create("r1"), "alpha/crew/x"); err != nil {
+		t.Fatalf("rig claim failed: %v", err)
+	}
+	wantLimit(claim(create("r2"), "alpha/polecats/y"), storage.WIPScopeRig, "alpha")
+	if err := claim(create("r3"), "beta/crew/y"); err != nil {
+		t.Errorf("rig without a limit should be claimable: %v", err)
+	}
+}
+
+// TestClaimIssueWIPLimitConcurrent tests that concurrent claims can't exceed a WIP limit.
+func TestClaimIssueWIPLimitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := store.SetConfig(ctx, "wip.assignee", "2"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	const numIssues = 8
+	ids := make([]string, numIssues)
+	for i := range ids {
+		issue := &types.Issue{Title: "Concurrent WIP", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids[i] = issue.ID
+	}
+
+	var wg sync.WaitGroup
+	var successCount atomic.Int32
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			err := store.ClaimIssue(ctx, id, "greedy-agent")
+			switch {
+			case err == nil:
+				successCount.Add(1)
+			case !errors.Is(err, storage.ErrWIPLimitExceeded):
+				t.Errorf("unexpected error claiming %s: %v", id, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if successCount.Load() != 2 {
+		t.Errorf("expected exactly 2 successful claims, got %d", successCount.Load())
+	}
+}
+
+// TestGetClaimableReadyWork tests that ready work the actor can't claim is hidden
+// and that limited pages are refilled.
+func TestGetClaimableReadyWork(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	blocked := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		issue := &types.Issue{Title: "Ready", Status: types.StatusOpen, Priority: i % 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		// P0 issues are labeled with a full bucket
+		if issue.Priority == 0 {
+			if err := store.AddLabel(ctx, issue.ID, "hot", "test-actor"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+			blocked[issue.ID] = true
+		}
+	}
+	hot := &types.Issue{Title: "Hot in progress", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, hot, "test-actor"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, hot.ID, "hot", "test-actor"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, hot.ID, "other"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.SetConfig(ctx, "wip.label.hot", "1"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	filter := types.WorkFilter{Status: types.StatusOpen, SortPolicy: types.SortPolicyPriority, Limit: 2}
+	page, hidden, err := storage.GetClaimableReadyWork(ctx, store, filter, "me")
+	if err != nil {
+		t.Fatalf("GetClaimableReadyWork failed: %v", err)
+	}
+	if len(page) != 2 || hidden != len(blocked) {
+		t.Fatalf("expected a full page of 2 with %d hidden, got %d with %d hidden", len(blocked), len(page), hidden)
+	}
+	seen := map[string]bool{}
+	for filter.Cursor = ""; ; {
+		for _, issue := range page {
+			if blocked[issue.ID] {
+				t.Errorf("issue %s should be hidden", issue.ID)
+			}
+			seen[issue.ID] = true
+		}
+		if filter.Cursor = filter.NextCursor(page); filter.Cursor == "" {
+			break
+		}
+		if page, _, err = storage.GetClaimableReadyWork(ctx, store, filter, "me"); err != nil {
+			t.Fatalf("GetClaimableReadyWork failed: %v", err)
+		}
+	}
+	if len(seen) != 5-len(blocked) {
+		t.Errorf("expected %d claimable issues across pages, got %d", 5-len(blocked), len(seen))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/fastbeads/internal/types"
@@ -185,18 +187,20 @@ func (s *SQLiteStorage) GetTier2Candidates(ctx context.Context) ([]*types.Compac
 // CheckEligibility checks if a specific issue is eligible for compaction at the given tier.
 // Returns (eligible, reason, error).
 // If not eligible, reason explains why.
+// At Tier 2, epics are checked for rollup (see checkEpicRollupEligibility).
 func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, tier int) (bool, string, error) {
 	// Get the issue
 	var status string
 	var closedAt sql.NullTime
 	var compactionLevel int
 	var pinned int
+	var issueType string
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT status, closed_at, COALESCE(compaction_level, 0), COALESCE(pinned, 0)
+		SELECT status, closed_at, COALESCE(compaction_level, 0), COALESCE(pinned, 0), issue_type
 		FROM issues
 		WHERE id = ?
-	`, issueID).Scan(&status, &closedAt, &compactionLevel, &pinned)
+	`, issueID).Scan(&status, &closedAt, &compactionLevel, &pinned, &issueType)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return false, "issue not found", nil
@@ -240,6 +244,11 @@ func (s *SQLiteStorage) CheckEligibility(ctx context.Context, issueID string, ti
 		return false, "issue has open dependents or not closed long enough", nil
 
 	case 2:
+		// Closed epics roll up together with everything beneath them
+		if issueType == string(types.TypeEpic) {
+			return s.checkEpicRollupEligibility(ctx, issueID, closedAt.Time, compactionLevel)
+		}
+
 		if compactionLevel != 1 {
 			return false, "issue must be at compaction level 1 for tier 2", nil
 		}
@@ -315,3 +324,322 @@ func (s *SQLiteStorage) ApplyCompaction(ctx context.Context, issueID string, lev
 		return nil
 	})
 }
+
+// GetEpicDescendants returns every issue below epicID in the parent-child
+// hierarchy, at any depth, ordered by ID. Tombstones are omitted: they have
+// no dependencies left to walk and nothing left to roll up.
+func (s *SQLiteStorage) GetEpicDescendants(ctx context.Context, epicID string) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH RECURSIVE descendants(id) AS (
+		  SELECT issue_id FROM dependencies
+		  WHERE depends_on_id = ? AND type = 'parent-child'
+		  UNION
+		  SELECT d.issue_id FROM dependencies d
+		  JOIN descendants ON d.depends_on_id = descendants.id
+		  WHERE d.type = 'parent-child'
+		)
+		SELECT id FROM descendants
+		WHERE id != ?
+		ORDER BY id
+	`, epicID, epicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epic descendants: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan descendant: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	descendants := make([]*types.Issue, 0, len(ids))
+	for _, id := range ids {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get descendant %s: %w", id, err)
+		}
+		if issue == nil || issue.Status == types.StatusTombstone {
+			continue
+		}
+		descendants = append(descendants, issue)
+	}
+	return descendants, nil
+}
+
+// checkEpicRollupEligibility reports whether a closed epic can be rolled up
+// at Tier 2. The epic and every descendant must have been closed for at least
+// compact_tier2_days and not be pinned, and no open issue outside the epic
+// may depend on any of them. Descendants may not have conditional-blocks or
+// waits-for edges crossing the epic's boundary either: those depend on the
+// issue's own close reason or children, so they can't be moved to the epic.
+func (s *SQLiteStorage) checkEpicRollupEligibility(ctx context.Context, epicID string, closedAt time.Time, compactionLevel int) (bool, string, error) {
+	if compactionLevel >= 2 {
+		return false, "epic is already rolled up", nil
+	}
+
+	daysStr, err := s.GetConfig(ctx, "compact_tier2_days")
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get compact_tier2_days: %w", err)
+	}
+	days := 90
+	if daysStr != "" {
+		if days, err = strconv.Atoi(daysStr); err != nil {
+			return false, "", fmt.Errorf("invalid compact_tier2_days %q: %w", daysStr, err)
+		}
+	}
+	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+
+	if closedAt.After(cutoff) {
+		return false, fmt.Sprintf("epic closed less than %d days ago", days), nil
+	}
+
+	descendants, err := s.GetEpicDescendants(ctx, epicID)
+	if err != nil {
+		return false, "", err
+	}
+	if len(descendants) == 0 {
+		return false, "epic has no children to roll up", nil
+	}
+
+	inRollup := map[string]bool{epicID: true}
+	for _, d := range descendants {
+		switch {
+		case d.Status != types.StatusClosed:
+			return false, fmt.Sprintf("descendant %s is %s", d.ID, d.Status), nil
+		case d.ClosedAt == nil || d.ClosedAt.After(cutoff):
+			return false, fmt.Sprintf("descendant %s closed less than %d days ago", d.ID, days), nil
+		case d.Pinned:
+			return false, fmt.Sprintf("descendant %s is pinned (protected from compaction)", d.ID), nil
+		}
+		inRollup[d.ID] = true
+	}
+
+	for id := range inRollup {
+		var dependentID string
+		err := s.db.QueryRowContext(ctx, `
+			SELECT dep.id FROM dependencies d
+			JOIN issues dep ON d.issue_id = dep.id
+			WHERE d.depends_on_id = ?
+			  AND d.type = 'blocks'
+			  AND dep.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			ORDER BY dep.id
+			LIMIT 1
+		`, id).Scan(&dependentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check dependents of %s: %w", id, err)
+		}
+		if !inRollup[dependentID] {
+			return false, fmt.Sprintf("open issue %s depends on %s", dependentID, id), nil
+		}
+	}
+
+	for _, d := range descendants {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT issue_id, depends_on_id, type FROM dependencies
+			WHERE (issue_id = ? OR depends_on_id = ?)
+			  AND type IN (?, ?)
+			ORDER BY issue_id, depends_on_id
+		`, d.ID, d.ID, types.DepConditionalBlocks, types.DepWaitsFor)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check dependencies of %s: %w", d.ID, err)
+		}
+		var reason string
+		for rows.Next() && reason == "" {
+			var from, to, depType string
+			if err := rows.Scan(&from, &to, &depType); err != nil {
+				_ = rows.Close()
+				return false, "", fmt.Errorf("failed to scan dependency: %w", err)
+			}
+			if !inRollup[from] || !inRollup[to] {
+				reason = fmt.Sprintf("%s has a %s dependency on %s outside the epic", from, depType, to)
+			}
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return false, "", fmt.Errorf("failed to check dependencies of %s: %w", d.ID, err)
+		}
+		if reason != "" {
+			return false, reason, nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// ApplyEpicRollup collapses a closed epic and its descendants into a single
+// Tier 2 summary on the epic, in one transaction. The epic's text is
+// replaced by summary; each descendant keeps its ID but becomes a tombstone.
+// Dependencies between issues in the rollup are removed, and those linking a
+// descendant to an issue outside it are moved to the epic, so outside issues
+// keep their blockers and provenance. Every issue records commitHash and its
+// original size, so `fbd restore` can recover the full text from git.
+func (s *SQLiteStorage) ApplyEpicRollup(ctx context.Context, epicID string, descendantIDs []string, summary string, commitHash string, actor string) error {
+	now := time.Now().UTC()
+	var commitHashPtr *string
+	if commitHash != "" {
+		commitHashPtr = &commitHash
+	}
+	reason := fmt.Sprintf("rolled up into %s", epicID)
+
+	rollupIDs := []interface{}{epicID}
+	for _, id := range descendantIDs {
+		rollupIDs = append(rollupIDs, id)
+	}
+	inRollup := "(" + strings.TrimSuffix(strings.Repeat("?,", len(rollupIDs)), ",") + ")"
+
+	return s.withTx(ctx, func(conn *sql.Conn) error {
+		var originalSize int
+		err := conn.QueryRowContext(ctx, `
+			SELECT COALESCE(original_size, LENGTH(description) + LENGTH(design) + LENGTH(notes) + LENGTH(acceptance_criteria))
+			FROM issues WHERE id = ?
+		`, epicID).Scan(&originalSize)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("issue %s not found", epicID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get epic: %w", err)
+		}
+
+		dirty := []string{epicID}
+		for _, id := range descendantIDs {
+			var issueType string
+			var size int
+			err := conn.QueryRowContext(ctx, `
+				SELECT issue_type, COALESCE(original_size, LENGTH(description) + LENGTH(design) + LENGTH(notes) + LENGTH(acceptance_criteria))
+				FROM issues WHERE id = ?
+			`, id).Scan(&issueType, &size)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("issue %s not found", id)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", id, err)
+			}
+			originalSize += size
+
+			// Issues outside the rollup have their links moved to the epic,
+			// so re-export them too
+			rows, err := conn.QueryContext(ctx, `
+				SELECT issue_id FROM dependencies WHERE depends_on_id = ?
+				UNION
+				SELECT depends_on_id FROM dependencies WHERE issue_id = ?
+			`, id, id)
+			if err != nil {
+				return fmt.Errorf("failed to query dependencies of %s: %w", id, err)
+			}
+			for rows.Next() {
+				var linked string
+				if err := rows.Scan(&linked); err != nil {
+					_ = rows.Close()
+					return fmt.Errorf("failed to scan dependency: %w", err)
+				}
+				dirty = append(dirty, linked)
+			}
+			_ = rows.Close()
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("failed to iterate dependencies of %s: %w", id, err)
+			}
+
+			// Move edges that cross the rollup's boundary to the epic. An
+			// edge the epic already has with that issue is kept as is.
+			args := append([]interface{}{id, epicID, id, epicID, id}, rollupIDs...)
+			args = append(append(args, id), rollupIDs...)
+			_, err = conn.ExecContext(ctx, `
+				INSERT OR IGNORE INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)
+				SELECT CASE WHEN issue_id = ? THEN ? ELSE issue_id END,
+				       CASE WHEN depends_on_id = ? THEN ? ELSE depends_on_id END,
+				       type, created_at, created_by, metadata, thread_id
+				FROM dependencies
+				WHERE (issue_id = ? AND depends_on_id NOT IN `+inRollup+`)
+				   OR (depends_on_id = ? AND issue_id NOT IN `+inRollup+`)
+			`, args...)
+			if err != nil {
+				return fmt.Errorf("failed to move dependencies of %s to %s: %w", id, epicID, err)
+			}
+			if _, err := conn.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = ? OR depends_on_id = ?`, id, id); err != nil {
+				return fmt.Errorf("failed to delete dependencies of %s: %w", id, err)
+			}
+
+			// Note: closed_at must be set to NULL because of CHECK constraint:
+			// (status = 'closed') = (closed_at IS NOT NULL)
+			_, err = conn.ExecContext(ctx, `
+				UPDATE issues
+				SET status = ?,
+				    closed_at = NULL,
+				    deleted_at = ?,
+				    deleted_by = ?,
+				    delete_reason = ?,
+				    original_type = ?,
+				    compaction_level = 2,
+				    compacted_at = ?,
+				    compacted_at_commit = ?,
+				    original_size = ?,
+				    updated_at = ?
+				WHERE id = ?
+			`, types.StatusTombstone, now, actor, reason, issueType, now, commitHashPtr, size, now, id)
+			if err != nil {
+				return fmt.Errorf("failed to create tombstone for %s: %w", id, err)
+			}
+
+			_, err = conn.ExecContext(ctx, `
+				INSERT INTO events (issue_id, event_type, actor, comment)
+				VALUES (?, ?, ?, ?)
+			`, id, "deleted", actor, reason)
+			if err != nil {
+				return fmt.Errorf("failed to record tombstone event for %s: %w", id, err)
+			}
+			dirty = append(dirty, id)
+		}
+
+		_, err = conn.ExecContext(ctx, `
+			UPDATE issues
+			SET description = ?,
+			    design = '',
+			    notes = '',
+			    acceptance_criteria = '',
+			    compaction_level = 2,
+			    compacted_at = ?,
+			    compacted_at_commit = ?,
+			    original_size = ?,
+			    updated_at = ?
+			WHERE id = ?
+		`, summary, now, commitHashPtr, originalSize, now, epicID)
+		if err != nil {
+			return fmt.Errorf("failed to apply rollup to epic: %w", err)
+		}
+
+		compressedSize := len(summary)
+		reductionPct := 0.0
+		if originalSize > 0 {
+			reductionPct = (1.0 - float64(compressedSize)/float64(originalSize)) * 100
+		}
+		eventData := fmt.Sprintf(`{"tier":2,"original_size":%d,"compressed_size":%d,"reduction_pct":%.1f,"rolled_up":%d}`,
+			originalSize, compressedSize, reductionPct, len(descendantIDs))
+		_, err = conn.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, comment)
+			VALUES (?, ?, ?, ?)
+		`, epicID, types.EventCompacted, actor, eventData)
+		if err != nil {
+			return fmt.Errorf("failed to record compaction event: %w", err)
+		}
+
+		if err := markIssuesDirtyTx(ctx, conn, dirty); err != nil {
+			return fmt.Errorf("failed to mark issues dirty: %w", err)
+		}
+
+		// Tombstones don't block others, so blocking calculations change
+		if err := s.invalidateBlockedCache(ctx, conn); err != nil {
+			return fmt.Errorf("failed to invalidate blocked cache: %w", err)
+		}
+		return nil
+	})
+}
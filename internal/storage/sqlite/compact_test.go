@@ -388,3 +388,217 @@ func TestApplyCompactionNotFound(t *testing.T) {
 		t.Errorf("Expected error to contain %q, got %q", expectedError, err.Error())
 	}
 }
+
+// setupClosedEpic creates closed epic bd-1 with child bd-1.1 and grandchild
+// bd-1.2, all closed for the given number of days.
+func setupClosedEpic(t *testing.T, store *SQLiteStorage, closedDaysAgo int) {
+	t.Helper()
+	ctx := context.Background()
+	closedAt := timePtr(time.Now().Add(-time.Duration(closedDaysAgo) * 24 * time.Hour))
+	for _, issue := range []*types.Issue{
+		{ID: "bd-1", Title: "Epic", Description: "Epic description", IssueType: types.TypeEpic},
+		{ID: "bd-1.1", Title: "Child", Description: "Child description", IssueType: types.TypeTask},
+		{ID: "bd-1.2", Title: "Grandchild", Description: "Grandchild description", IssueType: types.TypeTask},
+	} {
+		issue.Status = types.StatusClosed
+		issue.Priority = 2
+		issue.ClosedAt = closedAt
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create %s: %v", issue.ID, err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "bd-1.1", DependsOnID: "bd-1", Type: types.DepParentChild},
+		{IssueID: "bd-1.2", DependsOnID: "bd-1.1", Type: types.DepParentChild},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("Failed to add dependency: %v", err)
+		}
+	}
+}
+
+func TestCheckEligibilityTier2Epic(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	setupClosedEpic(t, store, 100)
+
+	eligible, reason, err := store.CheckEligibility(ctx, "bd-1", 2)
+	if err != nil {
+		t.Fatalf("CheckEligibility failed: %v", err)
+	}
+	if !eligible {
+		t.Fatalf("Expected epic to be eligible for rollup, got reason %q", reason)
+	}
+
+	descendants, err := store.GetEpicDescendants(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetEpicDescendants failed: %v", err)
+	}
+	if len(descendants) != 2 || descendants[0].ID != "bd-1.1" || descendants[1].ID != "bd-1.2" {
+		t.Errorf("Expected descendants bd-1.1 and bd-1.2, got %v", descendants)
+	}
+}
+
+func TestCheckEligibilityTier2EpicBlocked(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, store *SQLiteStorage)
+		reason string
+	}{
+		{
+			name: "recently closed descendant",
+			setup: func(t *testing.T, store *SQLiteStorage) {
+				if _, err := store.db.Exec(`UPDATE issues SET closed_at = datetime('now', '-1 days') WHERE id = 'bd-1.2'`); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "descendant bd-1.2 closed less than 90 days ago",
+		},
+		{
+			name: "pinned descendant",
+			setup: func(t *testing.T, store *SQLiteStorage) {
+				if _, err := store.db.Exec(`UPDATE issues SET pinned = 1 WHERE id = 'bd-1.1'`); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "descendant bd-1.1 is pinned",
+		},
+		{
+			name: "open dependent outside the epic",
+			setup: func(t *testing.T, store *SQLiteStorage) {
+				ctx := context.Background()
+				open := &types.Issue{ID: "bd-9", Title: "Open", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+				if err := store.CreateIssue(ctx, open, "test"); err != nil {
+					t.Fatal(err)
+				}
+				dep := &types.Dependency{IssueID: "bd-9", DependsOnID: "bd-1.2", Type: types.DepBlocks}
+				if err := store.AddDependency(ctx, dep, "test"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "open issue bd-9 depends on bd-1.2",
+		},
+		{
+			name: "waits-for edge crossing the epic",
+			setup: func(t *testing.T, store *SQLiteStorage) {
+				ctx := context.Background()
+				closed := &types.Issue{ID: "bd-9", Title: "Closed", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask, ClosedAt: timePtr(time.Now())}
+				if err := store.CreateIssue(ctx, closed, "test"); err != nil {
+					t.Fatal(err)
+				}
+				dep := &types.Dependency{IssueID: "bd-9", DependsOnID: "bd-1.1", Type: types.DepWaitsFor, Metadata: `{"gate":"all-children"}`}
+				if err := store.AddDependency(ctx, dep, "test"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reason: "bd-9 has a waits-for dependency on bd-1.1 outside the epic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, cleanup := setupTestDB(t)
+			defer cleanup()
+			setupClosedEpic(t, store, 100)
+			tt.setup(t, store)
+
+			eligible, reason, err := store.CheckEligibility(context.Background(), "bd-1", 2)
+			if err != nil {
+				t.Fatalf("CheckEligibility failed: %v", err)
+			}
+			if eligible || !strings.Contains(reason, tt.reason) {
+				t.Errorf("Expected ineligible with %q, got eligible=%v reason=%q", tt.reason, eligible, reason)
+			}
+		})
+	}
+}
+
+func TestApplyEpicRollup(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	setupClosedEpic(t, store, 100)
+
+	// Links to issues outside the epic survive the rollup on the epic
+	for _, id := range []string{"bd-8", "bd-9"} {
+		outside := &types.Issue{ID: id, Title: "Outside", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, outside, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "bd-9", DependsOnID: "bd-1.2", Type: types.DepDiscoveredFrom},
+		{IssueID: "bd-1.1", DependsOnID: "bd-8", Type: types.DepRelated},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	if err := store.ApplyEpicRollup(ctx, "bd-1", []string{"bd-1.1", "bd-1.2"}, "Rolled up", "abc123", "compactor"); err != nil {
+		t.Fatalf("ApplyEpicRollup failed: %v", err)
+	}
+
+	epic, err := store.GetIssue(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if epic.Description != "Rolled up" || epic.CompactionLevel != 2 {
+		t.Errorf("Expected rolled-up epic at level 2, got %q at level %d", epic.Description, epic.CompactionLevel)
+	}
+	wantSize := len("Epic description") + len("Child description") + len("Grandchild description")
+	if epic.OriginalSize != wantSize {
+		t.Errorf("Expected epic original_size %d, got %d", wantSize, epic.OriginalSize)
+	}
+
+	child, err := store.GetIssue(ctx, "bd-1.2")
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if child.Status != types.StatusTombstone || child.DeleteReason != "rolled up into bd-1" {
+		t.Errorf("Expected tombstone rolled up into bd-1, got status %s reason %q", child.Status, child.DeleteReason)
+	}
+	if child.CompactedAtCommit == nil || *child.CompactedAtCommit != "abc123" {
+		t.Errorf("Expected tombstone to keep the commit for restore, got %v", child.CompactedAtCommit)
+	}
+	if child.OriginalSize != len("Grandchild description") {
+		t.Errorf("Expected child original_size %d, got %d", len("Grandchild description"), child.OriginalSize)
+	}
+
+	deps, err := store.GetDependencyRecords(ctx, "bd-1.1")
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Errorf("Expected tombstone dependencies removed, got %v", deps)
+	}
+	deps, err = store.GetDependencyRecords(ctx, "bd-9")
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != "bd-1" || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("Expected bd-9 to be discovered from bd-1, got %+v", deps)
+	}
+	deps, err = store.GetDependencyRecords(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	var related bool
+	for _, dep := range deps {
+		if dep.DependsOnID == "bd-1.1" || dep.DependsOnID == "bd-1.2" {
+			t.Errorf("Expected internal dependency %s -> %s removed", dep.IssueID, dep.DependsOnID)
+		}
+		related = related || (dep.DependsOnID == "bd-8" && dep.Type == types.DepRelated)
+	}
+	if !related {
+		t.Errorf("Expected bd-1 to be related to bd-8, got %+v", deps)
+	}
+
+	eligible, reason, err := store.CheckEligibility(ctx, "bd-1", 2)
+	if err != nil {
+		t.Fatalf("CheckEligibility failed: %v", err)
+	}
+	if eligible || reason != "epic is already rolled up" {
+		t.Errorf("Expected rolled-up epic to be ineligible, got eligible=%v reason=%q", eligible, reason)
+	}
+}
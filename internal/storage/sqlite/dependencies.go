@@ -144,9 +144,9 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 
 		// Record event
 		_, err = conn.ExecContext(ctx, `
-			INSERT INTO events (issue_id, event_type, actor, comment)
-			VALUES (?, ?, ?, ?)
-		`, dep.IssueID, types.EventDependencyAdded, actor,
+			INSERT INTO events (issue_id, event_type, actor, new_value, comment)
+			VALUES (?, ?, ?, ?, ?)
+		`, dep.IssueID, types.EventDependencyAdded, actor, dep.DependsOnID,
 			fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID))
 		if err != nil {
 			return fmt.Errorf("failed to record event: %w", err)
@@ -206,9 +206,9 @@ func (s *SQLiteStorage) RemoveDependency(ctx context.Context, issueID, dependsOn
 		}
 
 		_, err = conn.ExecContext(ctx, `
-			INSERT INTO events (issue_id, event_type, actor, comment)
-			VALUES (?, ?, ?, ?)
-		`, issueID, types.EventDependencyRemoved, actor,
+			INSERT INTO events (issue_id, event_type, actor, old_value, comment)
+			VALUES (?, ?, ?, ?, ?)
+		`, issueID, types.EventDependencyRemoved, actor, dependsOnID,
 			fmt.Sprintf("Removed dependency on %s", dependsOnID))
 		if err != nil {
 			return fmt.Errorf("failed to record event: %w", err)
@@ -112,6 +112,26 @@ func (s *SQLiteStorage) GetEvents(ctx context.Context, issueID string, limit int
 	return events, nil
 }
 
+// GetLastEventID returns the ID of the newest event, or of the newest event
+// created before before when it is non-zero. Returns 0 if there is none.
+func (s *SQLiteStorage) GetLastEventID(ctx context.Context, before time.Time) (int64, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	query := `SELECT COALESCE(MAX(id), 0) FROM events`
+	var args []interface{}
+	if !before.IsZero() {
+		// created_at may be stored in either SQLite's or Go's timestamp format
+		query += ` WHERE julianday(created_at) < julianday(?)`
+		args = append(args, before.UTC().Format("2006-01-02 15:04:05.000"))
+	}
+	var id int64
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get last event id: %w", err)
+	}
+	return id, nil
+}
+
 // GetAllEventsSince returns all events with ID greater than sinceID, ordered by ID ascending.
 func (s *SQLiteStorage) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
 	s.reconnectMu.RLock()
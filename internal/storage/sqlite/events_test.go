@@ -504,3 +504,45 @@ func TestAddCommentNotFound(t *testing.T) {
 		t.Errorf("Expected error to contain %q, got %q", expectedError, err.Error())
 	}
 }
+
+func TestGetLastEventID(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if id, err := store.GetLastEventID(ctx, time.Time{}); err != nil || id != 0 {
+		t.Fatalf("GetLastEventID on empty log = %d, %v; want 0", id, err)
+	}
+
+	issue := &types.Issue{Title: "Events", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	// One event in SQLite's timestamp format and one as a Go time
+	noon := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO events (issue_id, event_type, actor, created_at) VALUES (?, ?, ?, ?), (?, ?, ?, ?)`,
+		issue.ID, types.EventUpdated, "alice", "2024-06-15 11:00:00",
+		issue.ID, types.EventUpdated, "alice", noon); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+	events, err := store.GetAllEventsSince(ctx, 0)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("GetAllEventsSince = %d events, %v; want 3", len(events), err)
+	}
+
+	// The creation event is recorded now, after every cutoff
+	for _, tt := range []struct {
+		before time.Time
+		want   int64
+	}{
+		{time.Time{}, events[2].ID},
+		{noon, events[1].ID},
+		{noon.Add(time.Second), events[2].ID},
+		{noon.Add(-time.Hour), 0},
+	} {
+		if id, err := store.GetLastEventID(ctx, tt.before); err != nil || id != tt.want {
+			t.Errorf("GetLastEventID(%v) = %d, %v; want %d", tt.before, id, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// issuesFTSDeleteSQL removes the index row (and its docid mapping) for an
+// issue ID expression. FTS5 rows are addressed by rowid, so issues_fts_docs
+// maps each issue ID to a stable integer docid.
+func issuesFTSDeleteSQL(idExpr string) string {
+	return fmt.Sprintf(`
+		DELETE FROM issues_fts WHERE rowid = (SELECT docid FROM issues_fts_docs WHERE issue_id = %[1]s);
+		DELETE FROM issues_fts_docs WHERE issue_id = %[1]s;`, idExpr)
+}
+
+// issuesFTSInsertSQL indexes the issue matching an ID expression, including all
+// of its comments concatenated into one column. It is a no-op if the issue
+// does not exist (e.g. comments cascading from a deleted issue).
+func issuesFTSInsertSQL(idExpr string) string {
+	return fmt.Sprintf(`
+		INSERT INTO issues_fts_docs (issue_id) SELECT id FROM issues WHERE id = %[1]s;
+		INSERT INTO issues_fts (rowid, title, description, design, acceptance_criteria, notes, comments)
+		SELECT d.docid, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), '')
+		FROM issues i JOIN issues_fts_docs d ON d.issue_id = i.id
+		WHERE i.id = %[1]s;`, idExpr)
+}
+
+// issuesFTSRefreshSQL rebuilds the index row for an issue ID expression.
+func issuesFTSRefreshSQL(idExpr string) string {
+	return issuesFTSDeleteSQL(idExpr) + issuesFTSInsertSQL(idExpr)
+}
+
+// MigrateIssuesFTS creates the issues_fts FTS5 index used by SearchIssues for
+// ranked full-text search over title, description, design, acceptance criteria,
+// notes and comments. The index is kept current by triggers on the issues and
+// comments tables, and is backfilled from existing rows when first created.
+func MigrateIssuesFTS(db *sql.DB) error {
+	var tableName string
+	err := db.QueryRow(`
+		SELECT name FROM sqlite_master
+		WHERE type='table' AND name='issues_fts'
+	`).Scan(&tableName)
+
+	created := false
+	if err == sql.ErrNoRows {
+		_, err = db.Exec(`
+			CREATE TABLE IF NOT EXISTS issues_fts_docs (
+				docid INTEGER PRIMARY KEY AUTOINCREMENT,
+				issue_id TEXT NOT NULL UNIQUE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create issues_fts_docs table: %w", err)
+		}
+		_, err = db.Exec(`
+			CREATE VIRTUAL TABLE issues_fts USING fts5(
+				title,
+				description,
+				design,
+				acceptance_criteria,
+				notes,
+				comments,
+				tokenize = 'unicode61 remove_diacritics 2'
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create issues_fts table: %w", err)
+		}
+		created = true
+	} else if err != nil {
+		return fmt.Errorf("failed to check for issues_fts table: %w", err)
+	}
+
+	triggers := []struct {
+		name string
+		sql  string
+	}{
+		{
+			name: "issues_fts_ai",
+			sql: `CREATE TRIGGER IF NOT EXISTS issues_fts_ai AFTER INSERT ON issues BEGIN` +
+				issuesFTSInsertSQL("NEW.id") + `
+			END`,
+		},
+		{
+			name: "issues_fts_au",
+			sql: `CREATE TRIGGER IF NOT EXISTS issues_fts_au
+			AFTER UPDATE OF id, title, description, design, acceptance_criteria, notes ON issues BEGIN` +
+				issuesFTSDeleteSQL("OLD.id") + issuesFTSRefreshSQL("NEW.id") + `
+			END`,
+		},
+		{
+			name: "issues_fts_ad",
+			sql: `CREATE TRIGGER IF NOT EXISTS issues_fts_ad AFTER DELETE ON issues BEGIN` +
+				issuesFTSDeleteSQL("OLD.id") + `
+			END`,
+		},
+		{
+			name: "comments_fts_ai",
+			sql: `CREATE TRIGGER IF NOT EXISTS comments_fts_ai AFTER INSERT ON comments BEGIN` +
+				issuesFTSRefreshSQL("NEW.issue_id") + `
+			END`,
+		},
+		{
+			name: "comments_fts_au",
+			sql: `CREATE TRIGGER IF NOT EXISTS comments_fts_au AFTER UPDATE ON comments BEGIN` +
+				issuesFTSRefreshSQL("OLD.issue_id") + issuesFTSRefreshSQL("NEW.issue_id") + `
+			END`,
+		},
+		{
+			name: "comments_fts_ad",
+			sql: `CREATE TRIGGER IF NOT EXISTS comments_fts_ad AFTER DELETE ON comments BEGIN` +
+				issuesFTSRefreshSQL("OLD.issue_id") + `
+			END`,
+		},
+	}
+
+	for _, trg := range triggers {
+		if _, err := db.Exec(trg.sql); err != nil {
+			return fmt.Errorf("failed to create trigger %s: %w", trg.name, err)
+		}
+	}
+
+	if created {
+		if _, err := db.Exec(`INSERT INTO issues_fts_docs (issue_id) SELECT id FROM issues`); err != nil {
+			return fmt.Errorf("failed to populate issues_fts_docs: %w", err)
+		}
+		_, err := db.Exec(`
+			INSERT INTO issues_fts (rowid, title, description, design, acceptance_criteria, notes, comments)
+			SELECT d.docid, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+			       COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), '')
+			FROM issues i JOIN issues_fts_docs d ON d.issue_id = i.id
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to populate issues_fts: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// createPaginationFixture creates issues with repeated priorities, shared
+// timestamps, old and recent creation times, and one legacy-format
+// created_at, so every tie-breaker in the sort keys is exercised.
+func createPaginationFixture(t *testing.T, env *testEnv) {
+	t.Helper()
+	now := time.Now().UTC()
+	shared := now.Add(-time.Hour)
+	for i := 0; i < 13; i++ {
+		created := now.Add(-time.Duration(i) * 11 * time.Hour) // Spans the 48h hybrid window
+		if i%4 == 0 {
+			created = shared
+		}
+		issue := &types.Issue{
+			Title:     fmt.Sprintf("Issue %d", i),
+			Status:    types.StatusOpen,
+			Priority:  i % 3,
+			IssueType: types.TypeTask,
+			CreatedAt: created,
+		}
+		if err := env.Store.CreateIssue(env.Ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		if i == 7 {
+			// Rows written by older versions use SQLite's CURRENT_TIMESTAMP format
+			legacy := now.Add(-100 * time.Hour).Format("2006-01-02 15:04:05")
+			if _, err := env.Store.db.ExecContext(env.Ctx, `UPDATE issues SET created_at = ? WHERE id = ?`, legacy, issue.ID); err != nil {
+				t.Fatalf("set legacy created_at: %v", err)
+			}
+		}
+	}
+}
+
+func issueIDs(issues []*types.Issue) string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestSearchIssuesCursorPagination(t *testing.T) {
+	env := newTestEnv(t)
+	createPaginationFixture(t, env)
+
+	all, err := env.Store.SearchIssues(env.Ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+
+	var paged []*types.Issue
+	filter := types.IssueFilter{Limit: 4}
+	for pages := 0; ; pages++ {
+		if pages > len(all) {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := env.Store.SearchIssues(env.Ctx, "", filter)
+		if err != nil {
+			t.Fatalf("SearchIssues page %d: %v", pages, err)
+		}
+		paged = append(paged, page...)
+		if filter.Cursor = filter.NextCursor(page); filter.Cursor == "" {
+			break
+		}
+	}
+	if got, want := issueIDs(paged), issueIDs(all); got != want {
+		t.Errorf("paged order:\n  %s\nwant:\n  %s", got, want)
+	}
+}
+
+func TestSearchIssuesCursorStableAcrossWrites(t *testing.T) {
+	env := newTestEnv(t)
+	createPaginationFixture(t, env)
+
+	filter := types.IssueFilter{Limit: 5}
+	first, err := env.Store.SearchIssues(env.Ctx, "", filter)
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	filter.Cursor = filter.NextCursor(first)
+
+	// An issue that sorts onto the first page must not shift the second page
+	env.CreateIssueWith("Urgent newcomer", types.StatusOpen, 0, types.TypeTask)
+	second, err := env.Store.SearchIssues(env.Ctx, "", filter)
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, issue := range first {
+		seen[issue.ID] = true
+	}
+	for _, issue := range second {
+		if seen[issue.ID] || issue.Title == "Urgent newcomer" {
+			t.Errorf("second page repeats or shifts: %s (%s)", issue.ID, issue.Title)
+		}
+	}
+	if len(second) != 5 {
+		t.Errorf("len(second) = %d, want 5", len(second))
+	}
+}
+
+func TestGetReadyWorkCursorPagination(t *testing.T) {
+	for _, policy := range []types.SortPolicy{types.SortPolicyHybrid, types.SortPolicyPriority, types.SortPolicyOldest} {
+		t.Run(string(policy), func(t *testing.T) {
+			env := newTestEnv(t)
+			createPaginationFixture(t, env)
+
+			all := env.GetReadyWork(types.WorkFilter{SortPolicy: policy})
+			var paged []*types.Issue
+			filter := types.WorkFilter{SortPolicy: policy, Limit: 3}
+			for pages := 0; ; pages++ {
+				if pages > len(all) {
+					t.Fatal("pagination did not terminate")
+				}
+				page := env.GetReadyWork(filter)
+				paged = append(paged, page...)
+				if filter.Cursor = filter.NextCursor(page); filter.Cursor == "" {
+					break
+				}
+			}
+			if got, want := issueIDs(paged), issueIDs(all); got != want {
+				t.Errorf("paged order:\n  %s\nwant:\n  %s", got, want)
+			}
+		})
+	}
+}
+
+func TestCursorOrderMismatch(t *testing.T) {
+	env := newTestEnv(t)
+	createPaginationFixture(t, env)
+
+	filter := types.WorkFilter{SortPolicy: types.SortPolicyOldest, Limit: 2}
+	page := env.GetReadyWork(filter)
+	cursor := filter.NextCursor(page)
+
+	if _, err := env.Store.GetReadyWork(env.Ctx, types.WorkFilter{SortPolicy: types.SortPolicyPriority, Cursor: cursor}); err == nil {
+		t.Error("expected an error for a cursor from another sort policy")
+	}
+	if _, err := env.Store.SearchIssues(env.Ctx, "", types.IssueFilter{Cursor: cursor}); err == nil {
+		t.Error("expected an error for a ready work cursor in SearchIssues")
+	}
+	if _, err := env.Store.SearchIssues(env.Ctx, "", types.IssueFilter{Cursor: "not a cursor"}); err == nil {
+		t.Error("expected an error for a malformed cursor")
+	}
+}
@@ -1051,7 +1051,8 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 // callers could both successfully claim the same issue.
 //
 // Returns storage.ErrAlreadyClaimed (wrapped with current assignee) if the issue
-// is already claimed. Returns an error if the issue doesn't exist.
+// is already claimed, and a *storage.WIPLimitError if the claim would exceed a
+// configured WIP limit. Returns an error if the issue doesn't exist.
 func (s *SQLiteStorage) ClaimIssue(ctx context.Context, id string, actor string) error {
 	// Get the issue first to check existence and get old data for event
 	oldIssue, err := s.GetIssue(ctx, id)
@@ -1117,6 +1118,12 @@ func (s *SQLiteStorage) ClaimIssue(ctx context.Context, id string, actor string)
 			return fmt.Errorf("already claimed")
 		}
 
+		// Enforce WIP limits in the same transaction; the claimed issue itself
+		// is excluded from the counts
+		if err := storage.CheckWIPLimitsTx(ctx, conn, id, actor, nil); err != nil {
+			return err
+		}
+
 		// Record the claim event
 		_, err = conn.ExecContext(ctx, `
 			INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/fastbeads/internal/config"
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
@@ -17,6 +18,13 @@ import (
 // If filter.Status is set (e.g., "open"), only shows that status.
 // Excludes pinned issues which are persistent anchors, not actionable work.
 func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	// Default to hybrid sort for backwards compatibility
+	sortPolicy := filter.EffectiveSortPolicy()
+	cursor, err := types.DecodeCursor(filter.Cursor, string(sortPolicy))
+	if err != nil {
+		return nil, err
+	}
+
 	whereClauses := []string{
 		"i.pinned = 0", // Exclude pinned issues
 		"(i.ephemeral = 0 OR i.ephemeral IS NULL)", // Exclude wisps by ephemeral flag
@@ -153,9 +161,23 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		whereClauses = append(whereClauses, "(i.defer_until IS NULL OR datetime(i.defer_until) <= datetime('now'))")
 	}
 
+	// The hybrid sort's recent bucket is fixed by the first page's cutoff,
+	// so later pages order issues the same way
+	cutoff := filter.HybridCutoff().Format("2006-01-02 15:04:05")
+
+	// Keyset pagination: resume after the cursor's sort key
+	if cursor != nil {
+		clause, keysetArgs := storage.ReadyKeyset(cursor, "i.", "julianday(%s)", hybridRecentSQL, []interface{}{cutoff})
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
+
 	// Build WHERE clause properly
 	whereSQL := strings.Join(whereClauses, " AND ")
 
+	orderBySQL, orderArgs := buildOrderByClause(sortPolicy, cutoff)
+	args = append(args, orderArgs...)
+
 	// Build LIMIT clause using parameter
 	limitSQL := ""
 	if filter.Limit > 0 {
@@ -163,13 +185,6 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		args = append(args, filter.Limit)
 	}
 
-	// Default to hybrid sort for backwards compatibility
-	sortPolicy := filter.SortPolicy
-	if sortPolicy == "" {
-		sortPolicy = types.SortPolicyHybrid
-	}
-	orderBySQL := buildOrderByClause(sortPolicy)
-
 	// Use blocked_issues_cache for performance
 	// This optimization replaces the recursive CTE that computed blocked issues on every query.
 	// Performance improvement: 752ms → 29ms on 10K issues (25x speedup).
@@ -858,32 +873,28 @@ func (s *SQLiteStorage) GetNewlyUnblockedByClose(ctx context.Context, closedIssu
 	return s.scanIssues(ctx, rows)
 }
 
-// buildOrderByClause generates the ORDER BY clause based on sort policy
-func buildOrderByClause(policy types.SortPolicy) string {
+// hybridRecentSQL is true for issues in the hybrid sort's recent bucket.
+// Its one argument is the cutoff as "YYYY-MM-DD HH:MM:SS" UTC.
+const hybridRecentSQL = "datetime(i.created_at) >= datetime(?)"
+
+// buildOrderByClause generates the ORDER BY clause based on sort policy.
+// Every order ends with the issue ID so keyset pagination is stable.
+func buildOrderByClause(policy types.SortPolicy, cutoff string) (string, []interface{}) {
 	switch policy {
 	case types.SortPolicyPriority:
-		return `ORDER BY i.priority ASC, i.created_at ASC`
+		return `ORDER BY i.priority ASC, julianday(i.created_at) ASC, i.id ASC`, nil
 
 	case types.SortPolicyOldest:
-		return `ORDER BY i.created_at ASC`
+		return `ORDER BY julianday(i.created_at) ASC, i.id ASC`, nil
 
 	case types.SortPolicyHybrid:
 		fallthrough
 	default:
 		return `ORDER BY
-			CASE
-				WHEN datetime(i.created_at) >= datetime('now', '-48 hours') THEN 0
-				ELSE 1
-			END ASC,
-			CASE
-				WHEN datetime(i.created_at) >= datetime('now', '-48 hours') THEN i.priority
-				ELSE NULL
-			END ASC,
-			CASE
-				WHEN datetime(i.created_at) < datetime('now', '-48 hours') THEN i.created_at
-				ELSE NULL
-			END ASC,
-			i.created_at ASC`
+			CASE WHEN ` + hybridRecentSQL + ` THEN 0 ELSE 1 END ASC,
+			CASE WHEN ` + hybridRecentSQL + ` THEN i.priority ELSE NULL END ASC,
+			julianday(i.created_at) ASC,
+			i.id ASC`, []interface{}{cutoff, cutoff}
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/fastbeads/internal/storage"
 	"github.com/steveyegge/fastbeads/internal/types"
 )
 
@@ -20,13 +21,44 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 	s.reconnectMu.RLock()
 	defer s.reconnectMu.RUnlock()
 
+	cursor, err := types.DecodeCursor(filter.Cursor, types.PageOrderSearch)
+	if err != nil {
+		return nil, err
+	}
+
 	whereClauses := []string{}
 	args := []interface{}{}
 
+	// Text queries use the FTS5 index when available so results are BM25-ranked
+	// instead of a LIKE scan. The ID arm keeps partial-ID lookups working
+	// (e.g. "bd-5q"), since IDs are not meaningful FTS tokens. The hits drive
+	// the query (CROSS JOIN keeps them as the outer loop), so issues rows are
+	// only read by primary key for the candidates.
+	fromSQL := "issues"
+	var fromArgs []interface{}
+	orderSQL := "priority ASC, julianday(created_at) DESC, id ASC"
 	if query != "" {
-		whereClauses = append(whereClauses, "(title LIKE ? OR description LIKE ? OR id LIKE ?)")
-		pattern := "%" + query + "%"
-		args = append(args, pattern, pattern, pattern)
+		ftsExpr := buildFTSQuery(query)
+		if ftsExpr != "" && s.hasSearchIndex(ctx) {
+			fromSQL = `(
+				SELECT fts_issue_id, MIN(fts_rank) AS fts_rank FROM (
+					SELECT d.issue_id AS fts_issue_id, ` + ftsRankSQL + ` AS fts_rank
+					FROM issues_fts JOIN issues_fts_docs d ON d.docid = issues_fts.rowid
+					WHERE issues_fts MATCH ?
+					UNION ALL
+					SELECT id, NULL FROM issues WHERE id LIKE ?
+				) GROUP BY fts_issue_id
+			) fts CROSS JOIN issues ON issues.id = fts.fts_issue_id`
+			fromArgs = append(fromArgs, ftsExpr, "%"+query+"%")
+			if cursor != nil {
+				return nil, fmt.Errorf("cursor pagination is not supported for ranked text search")
+			}
+			orderSQL = "COALESCE(fts.fts_rank, 0) ASC, " + orderSQL
+		} else {
+			whereClauses = append(whereClauses, "(title LIKE ? OR description LIKE ? OR id LIKE ?)")
+			pattern := "%" + query + "%"
+			args = append(args, pattern, pattern, pattern)
+		}
 	}
 
 	if filter.TitleSearch != "" {
@@ -265,11 +297,21 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		args = append(args, time.Now().Format(time.RFC3339), types.StatusClosed)
 	}
 
+	// Keyset pagination: resume after the cursor's sort key
+	if cursor != nil {
+		clause, keysetArgs := storage.SearchKeyset(cursor, "", "julianday(%s)")
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
+	// The FTS placeholders live in the FROM clause, ahead of every WHERE arg
+	args = append(fromArgs, args...)
+
 	limitSQL := ""
 	if filter.Limit > 0 {
 		limitSQL = " LIMIT ?"
@@ -286,11 +328,11 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		       await_type, await_id, timeout_ns, waiters,
 		       hook_bead, role_bead, agent_state, last_activity, role_type, rig, mol_type,
 		       due_at, defer_until, metadata
-		FROM issues
+		FROM %s
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY %s
 		%s
-	`, whereSQL, limitSQL)
+	`, fromSQL, whereSQL, orderSQL, limitSQL)
 
 	rows, err := s.db.QueryContext(ctx, querySQL, args...)
 	if err != nil {
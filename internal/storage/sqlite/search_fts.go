@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// ftsColumns are the issues_fts columns, in table order.
+var ftsColumns = []string{"title", "description", "design", "acceptance_criteria", "notes", "comments"}
+
+// ftsRankSQL scores matches with BM25, weighting title hits highest.
+const ftsRankSQL = "bm25(issues_fts, 10.0, 4.0, 2.0, 2.0, 2.0, 1.0)"
+
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+// buildFTSQuery converts free-form user input into an FTS5 MATCH expression.
+// Each whitespace-separated word becomes a quoted prefix term so punctuation
+// in the input can't be interpreted as FTS5 syntax, and all terms must match
+// (implicit AND). Returns "" if the input contains no searchable terms.
+func buildFTSQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.ReplaceAll(word, `"`, "")
+		if !strings.ContainsFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		terms = append(terms, `"`+word+`"*`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	return strings.Join(terms, " ")
+}
+
+// hasSearchIndex reports whether the issues_fts table exists. Read-only stores
+// open databases without running migrations, so older databases may lack it.
+func (s *SQLiteStorage) hasSearchIndex(ctx context.Context) bool {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'issues_fts'`).Scan(&n)
+	return err == nil && n > 0
+}
+
+// SearchIssuesRanked runs SearchIssues and attaches BM25 ranks and per-field
+// highlights from the full-text index. Issues matched only by ID (or all issues
+// when the database has no search index) are returned without highlights.
+func (s *SQLiteStorage) SearchIssuesRanked(ctx context.Context, query string, filter types.IssueFilter) ([]*types.SearchHit, error) {
+	issues, err := s.SearchIssues(ctx, query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*types.SearchHit, len(issues))
+	byID := make(map[string]*types.SearchHit, len(issues))
+	for i, issue := range issues {
+		hits[i] = &types.SearchHit{Issue: issue}
+		byID[issue.ID] = hits[i]
+	}
+
+	ftsExpr := buildFTSQuery(query)
+	if len(issues) == 0 || ftsExpr == "" || !s.hasSearchIndex(ctx) {
+		return hits, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	// Column 0 (title) is short enough to highlight whole; longer fields get
+	// a snippet around the best match.
+	selects := []string{"d.issue_id", ftsRankSQL,
+		fmt.Sprintf("highlight(issues_fts, 0, '%s', '%s')", highlightOpen, highlightClose)}
+	for col := 1; col < len(ftsColumns); col++ {
+		selects = append(selects,
+			fmt.Sprintf("snippet(issues_fts, %d, '%s', '%s', '…', 16)", col, highlightOpen, highlightClose))
+	}
+
+	args := []interface{}{ftsExpr}
+	placeholders := make([]string, len(issues))
+	for i, issue := range issues {
+		placeholders[i] = "?"
+		args = append(args, issue.ID)
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	querySQL := fmt.Sprintf(`
+		SELECT %s
+		FROM issues_fts JOIN issues_fts_docs d ON d.docid = issues_fts.rowid
+		WHERE issues_fts MATCH ? AND d.issue_id IN (%s)
+	`, strings.Join(selects, ", "), strings.Join(placeholders, ", "))
+
+	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search highlights: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID string
+		var rank float64
+		fields := make([]sql.NullString, len(ftsColumns))
+		dest := []interface{}{&issueID, &rank}
+		for i := range fields {
+			dest = append(dest, &fields[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan search highlights: %w", err)
+		}
+
+		hit := byID[issueID]
+		if hit == nil {
+			continue
+		}
+		hit.Rank = rank
+		for i, text := range fields {
+			if text.Valid && strings.Contains(text.String, highlightOpen) {
+				if hit.Highlights == nil {
+					hit.Highlights = make(map[string]string)
+				}
+				hit.Highlights[ftsColumns[i]] = text.String
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search highlights: %w", err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestBuildFTSQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"punctuation only", `* " -`, ""},
+		{"single word", "auth", `"auth"*`},
+		{"multiple words", "login bug", `"login"* "bug"*`},
+		{"strips quotes", `"OR" NEAR(x)`, `"OR"* "NEAR(x)"*`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildFTSQuery(tt.query); got != tt.want {
+				t.Errorf("buildFTSQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchIssuesFullText(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	titleHit := &types.Issue{Title: "Authentication timeout", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	designHit := &types.Issue{Title: "Session store", Design: "Move authentication tokens to redis", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	commentHit := &types.Issue{Title: "Flaky CI", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask}
+	unrelated := &types.Issue{Title: "Update docs", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{titleHit, designHit, commentHit, unrelated} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if _, err := store.AddIssueComment(ctx, commentHit.ID, "test", "root cause is the authentication mock"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	results, err := store.SearchIssues(ctx, "authentic", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	// Title matches are weighted highest, regardless of priority
+	if results[0].ID != titleHit.ID {
+		t.Errorf("expected title match %s ranked first, got %s", titleHit.ID, results[0].ID)
+	}
+
+	// Updates are reflected through the triggers
+	if err := store.UpdateIssue(ctx, titleHit.ID, map[string]interface{}{"title": "Login timeout"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	results, err = store.SearchIssues(ctx, "authentic", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected 2 results after retitle, got %d", len(results))
+	}
+
+	// Partial IDs still match even though they aren't indexed text
+	results, err = store.SearchIssues(ctx, unrelated.ID[:len(unrelated.ID)-1], types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	found := false
+	for _, r := range results {
+		if r.ID == unrelated.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected partial ID search to find %s", unrelated.ID)
+	}
+}
+
+func TestSearchIssuesRankedHighlights(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{
+		Title:       "Database migration fails",
+		Description: "Running the migration on a large database locks writers",
+		Status:      types.StatusOpen,
+		Priority:    1,
+		IssueType:   types.TypeBug,
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	hits, err := store.SearchIssuesRanked(ctx, "migration", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssuesRanked failed: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d", len(hits))
+	}
+	hit := hits[0]
+	if hit.Rank >= 0 {
+		t.Errorf("expected negative BM25 rank, got %f", hit.Rank)
+	}
+	if got := hit.Highlights["title"]; got != "Database <mark>migration</mark> fails" {
+		t.Errorf("unexpected title highlight: %q", got)
+	}
+	if !strings.Contains(hit.Highlights["description"], "<mark>migration</mark>") {
+		t.Errorf("expected description highlight, got %q", hit.Highlights["description"])
+	}
+	if _, ok := hit.Highlights["notes"]; ok {
+		t.Errorf("expected no highlight for unmatched notes field")
+	}
+}
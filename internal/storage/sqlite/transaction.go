@@ -862,9 +862,9 @@ func (t *sqliteTxStorage) AddDependency(ctx context.Context, dep *types.Dependen
 
 	// Record event
 	_, err = t.conn.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
-	`, dep.IssueID, types.EventDependencyAdded, actor,
+		INSERT INTO events (issue_id, event_type, actor, new_value, comment)
+		VALUES (?, ?, ?, ?, ?)
+	`, dep.IssueID, types.EventDependencyAdded, actor, dep.DependsOnID,
 		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
@@ -952,9 +952,9 @@ func (t *sqliteTxStorage) RemoveDependency(ctx context.Context, issueID, depends
 	}
 
 	_, err = t.conn.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, comment)
-		VALUES (?, ?, ?, ?)
-	`, issueID, types.EventDependencyRemoved, actor,
+		INSERT INTO events (issue_id, event_type, actor, old_value, comment)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, types.EventDependencyRemoved, actor, dependsOnID,
 		fmt.Sprintf("Removed dependency on %s", dependsOnID))
 	if err != nil {
 		return fmt.Errorf("failed to record event: %w", err)
@@ -1230,6 +1230,11 @@ func (t *sqliteTxStorage) GetIssueComments(ctx context.Context, issueID string)
 // SearchIssues finds issues matching query and filters within the transaction.
 // This enables read-your-writes semantics for searching within a transaction.
 func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	cursor, err := types.DecodeCursor(filter.Cursor, types.PageOrderSearch)
+	if err != nil {
+		return nil, err
+	}
+
 	whereClauses := []string{}
 	args := []interface{}{}
 
@@ -1423,6 +1428,13 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		args = append(args, parentID, parentID)
 	}
 
+	// Keyset pagination: resume after the cursor's sort key
+	if cursor != nil {
+		clause, keysetArgs := storage.SearchKeyset(cursor, "", "julianday(%s)")
+		whereClauses = append(whereClauses, clause)
+		args = append(args, keysetArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
@@ -1447,7 +1459,7 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		       due_at, defer_until, metadata
 		FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY priority ASC, julianday(created_at) DESC, id ASC
 		%s
 	`, whereSQL, limitSQL)
 
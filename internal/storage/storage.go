@@ -97,7 +97,9 @@ type Storage interface {
 	// ClaimIssue atomically claims an issue using compare-and-swap semantics.
 	// It sets the assignee to actor and status to "in_progress" only if the issue
 	// has no current assignee. Returns ErrAlreadyClaimed if the issue is already
-	// claimed by another user. This provides race-condition-free claiming for
+	// claimed by another user, or a *WIPLimitError (matching ErrWIPLimitExceeded)
+	// if the claim would put the actor, one of the issue's labels or its rig over
+	// a configured WIP limit. This provides race-condition-free claiming for
 	// concurrent agents.
 	ClaimIssue(ctx context.Context, id string, actor string) error
 	CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error
@@ -137,6 +139,9 @@ type Storage interface {
 	AddComment(ctx context.Context, issueID, actor, comment string) error
 	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
 	GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error)
+	// GetLastEventID returns the ID of the newest event, or of the newest
+	// event created before before when it is non-zero; 0 if there is none.
+	GetLastEventID(ctx context.Context, before time.Time) (int64, error)
 
 	// Comments
 	AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error)
@@ -255,6 +260,7 @@ type CompactableStorage interface {
 
 	// CheckEligibility determines if an issue can be compacted at the given tier.
 	// Returns (eligible, reason, error) where reason explains ineligibility.
+	// At Tier 2, an epic is checked for rollup together with its descendants.
 	CheckEligibility(ctx context.Context, issueID string, tier int) (bool, string, error)
 
 	// GetTier1Candidates returns issues eligible for Tier 1 (basic) compaction.
@@ -269,6 +275,14 @@ type CompactableStorage interface {
 	// Sets compaction_level, compacted_at, compacted_at_commit, and original_size fields.
 	ApplyCompaction(ctx context.Context, issueID string, level int, originalSize int, compressedSize int, commitHash string) error
 
+	// GetEpicDescendants returns all non-tombstone issues below an epic in the
+	// parent-child hierarchy, at any depth.
+	GetEpicDescendants(ctx context.Context, epicID string) ([]*types.Issue, error)
+
+	// ApplyEpicRollup replaces an epic's text with a Tier 2 rollup summary and
+	// turns its descendants into tombstones that keep their IDs, atomically.
+	ApplyEpicRollup(ctx context.Context, epicID string, descendantIDs []string, summary string, commitHash string, actor string) error
+
 	// MarkIssueDirty marks an issue as needing export to JSONL.
 	MarkIssueDirty(ctx context.Context, issueID string) error
 }
@@ -291,6 +305,18 @@ type MultiRepoStorage interface {
 	HydrateFromMultiRepo(ctx context.Context) (map[string]int, error)
 }
 
+// TextSearcher extends Storage with ranked full-text search.
+// Backends with a text index (e.g., SQLite FTS5) implement this so callers can
+// surface relevance scores and match highlights. Callers should type-assert and
+// fall back to SearchIssues when the backend does not support it.
+type TextSearcher interface {
+	Storage
+
+	// SearchIssuesRanked runs SearchIssues and annotates each result with its
+	// relevance rank and per-field highlights. Results keep SearchIssues order.
+	SearchIssuesRanked(ctx context.Context, query string, filter types.IssueFilter) ([]*types.SearchHit, error)
+}
+
 // BatchDeleter extends Storage with batch delete capabilities.
 // Supports cascade deletion and dry-run mode for safe bulk operations.
 type BatchDeleter interface {
@@ -129,6 +129,9 @@ func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int)
 func (m *mockStorage) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
 	return nil, nil
 }
+func (m *mockStorage) GetLastEventID(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
 func (m *mockStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
 	return nil, nil
 }
@@ -376,6 +379,7 @@ func TestInterfaceDocumentation(t *testing.T) {
 		_ = s.AddComment
 		_ = s.GetEvents
 		_ = s.GetAllEventsSince
+		_ = s.GetLastEventID
 		_ = s.AddIssueComment
 		_ = s.GetIssueComments
 		_ = s.GetCommentsForIssues
@@ -0,0 +1,506 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// ErrWIPLimitExceeded is returned when claiming an issue would put an
+// assignee, label or rig over its work-in-progress limit. The returned error
+// is a *WIPLimitError describing the full bucket.
+var ErrWIPLimitExceeded = errors.New("WIP limit exceeded")
+
+// WIPConfigPrefix is the config namespace for WIP limits:
+//
+//	wip.assignee          default limit for every assignee
+//	wip.assignee.<name>   limit for one assignee
+//	wip.label.<label>     limit for in-progress issues with the label
+//	wip.rig               default limit for every rig
+//	wip.rig.<name>        limit for one rig
+//
+// Values are non-negative integers; 0 means no limit. An issue counts
+// toward the rig of its assignee (see ActorRig).
+const WIPConfigPrefix = "wip."
+
+// WIP limit scopes.
+const (
+	WIPScopeAssignee = "assignee"
+	WIPScopeLabel    = "label"
+	WIPScopeRig      = "rig"
+)
+
+// WIPLimits holds the configured work-in-progress limits. A zero limit
+// means unlimited.
+type WIPLimits struct {
+	Assignee  int            // Default for assignees without their own limit
+	Assignees map[string]int // Per-assignee limits
+	Labels    map[string]int // Per-label limits
+	Rig       int            // Default for rigs without their own limit
+	Rigs      map[string]int // Per-rig limits
+}
+
+// ActorRig returns the rig an actor works in: the first component of a
+// compound agent identity such as "gastown/crew/max". Plain actor names
+// belong to no rig.
+func ActorRig(actor string) string {
+	rig, _, found := strings.Cut(actor, "/")
+	if !found {
+		return ""
+	}
+	return rig
+}
+
+// WIPBucket is the in-progress count of one assignee, label or rig
+// against its limit.
+type WIPBucket struct {
+	Scope      string `json:"scope"`
+	Name       string `json:"name"`
+	Limit      int    `json:"limit"`
+	InProgress int    `json:"in_progress"`
+}
+
+// WIPLimitError reports the bucket that a claim would overflow.
+type WIPLimitError struct {
+	WIPBucket
+}
+
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("%s: %s %s has %d of %d issues in progress", ErrWIPLimitExceeded, e.Scope, e.Name, e.InProgress, e.Limit)
+}
+
+// Unwrap lets errors.Is match ErrWIPLimitExceeded.
+func (e *WIPLimitError) Unwrap() error {
+	return ErrWIPLimitExceeded
+}
+
+// ValidateWIPConfig checks a wip.* config key and value.
+func ValidateWIPConfig(key, value string) error {
+	_, _, _, err := parseWIPConfig(key, value)
+	return err
+}
+
+// parseWIPConfig splits a wip.* key into its scope and name (empty for a
+// default) and parses the limit.
+func parseWIPConfig(key, value string) (scope, name string, limit int, err error) {
+	rest := strings.TrimPrefix(key, WIPConfigPrefix)
+	scope, name, _ = strings.Cut(rest, ".")
+	switch scope {
+	case WIPScopeAssignee, WIPScopeRig:
+	case WIPScopeLabel:
+		if name == "" {
+			return "", "", 0, fmt.Errorf("invalid WIP limit key %q (use wip.label.<label>)", key)
+		}
+	default:
+		return "", "", 0, fmt.Errorf("invalid WIP limit key %q (must be wip.assignee[.<name>], wip.label.<label> or wip.rig[.<name>])", key)
+	}
+	limit, err = strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || limit < 0 {
+		return "", "", 0, fmt.Errorf("invalid WIP limit %q for %s (must be a non-negative integer)", value, key)
+	}
+	return scope, name, limit, nil
+}
+
+// ParseWIPLimits reads the WIP limits from config key-value pairs. Keys
+// outside WIPConfigPrefix are ignored.
+func ParseWIPLimits(config map[string]string) (*WIPLimits, error) {
+	limits := &WIPLimits{Assignees: map[string]int{}, Labels: map[string]int{}, Rigs: map[string]int{}}
+	for key, value := range config {
+		if !strings.HasPrefix(key, WIPConfigPrefix) {
+			continue
+		}
+		scope, name, limit, err := parseWIPConfig(key, value)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case scope == WIPScopeAssignee && name == "":
+			limits.Assignee = limit
+		case scope == WIPScopeAssignee:
+			limits.Assignees[name] = limit
+		case scope == WIPScopeLabel:
+			limits.Labels[name] = limit
+		case name == "":
+			limits.Rig = limit
+		default:
+			limits.Rigs[name] = limit
+		}
+	}
+	return limits, nil
+}
+
+// Empty reports whether no limits are configured.
+func (l *WIPLimits) Empty() bool {
+	if l.Assignee > 0 || l.Rig > 0 {
+		return false
+	}
+	for _, m := range []map[string]int{l.Assignees, l.Labels, l.Rigs} {
+		for _, limit := range m {
+			if limit > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// limit returns the limit for a bucket, or 0 if it has none.
+func (l *WIPLimits) limit(scope, name string) int {
+	switch scope {
+	case WIPScopeAssignee:
+		if limit, ok := l.Assignees[name]; ok {
+			return limit
+		}
+		return l.Assignee
+	case WIPScopeRig:
+		if limit, ok := l.Rigs[name]; ok {
+			return limit
+		}
+		return l.Rig
+	}
+	return l.Labels[name]
+}
+
+// Buckets returns the limited buckets that actor claiming an issue with
+// the given labels would count toward.
+func (l *WIPLimits) Buckets(actor string, labels []string) []*WIPBucket {
+	var buckets []*WIPBucket
+	add := func(scope, name string) {
+		if name == "" {
+			return
+		}
+		if limit := l.limit(scope, name); limit > 0 {
+			buckets = append(buckets, &WIPBucket{Scope: scope, Name: name, Limit: limit})
+		}
+	}
+	add(WIPScopeAssignee, actor)
+	for _, label := range labels {
+		add(WIPScopeLabel, label)
+	}
+	add(WIPScopeRig, ActorRig(actor))
+	return buckets
+}
+
+// Check returns a *WIPLimitError for the first bucket that is already full
+// for actor claiming an issue with the given labels. counts must not
+// include the issue being claimed.
+func (l *WIPLimits) Check(actor string, labels []string, counts WIPCounts) error {
+	for _, b := range l.Buckets(actor, labels) {
+		b.InProgress = counts[b.Scope][b.Name]
+		if b.InProgress >= b.Limit {
+			return &WIPLimitError{WIPBucket: *b}
+		}
+	}
+	return nil
+}
+
+// Usage lists every limited bucket with its in-progress count: those with
+// their own limit, and those under a default limit that have work in
+// progress. Buckets are ordered by scope, then name.
+func (l *WIPLimits) Usage(counts WIPCounts) []*WIPBucket {
+	seen := make(map[[2]string]bool)
+	var usage []*WIPBucket
+	add := func(scope, name string) {
+		key := [2]string{scope, name}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if limit := l.limit(scope, name); limit > 0 {
+			usage = append(usage, &WIPBucket{Scope: scope, Name: name, Limit: limit, InProgress: counts[scope][name]})
+		}
+	}
+	for name := range l.Assignees {
+		add(WIPScopeAssignee, name)
+	}
+	for name := range l.Labels {
+		add(WIPScopeLabel, name)
+	}
+	for name := range l.Rigs {
+		add(WIPScopeRig, name)
+	}
+	for name := range counts[WIPScopeAssignee] {
+		add(WIPScopeAssignee, name)
+	}
+	for name := range counts[WIPScopeRig] {
+		add(WIPScopeRig, name)
+	}
+
+	order := map[string]int{WIPScopeAssignee: 0, WIPScopeLabel: 1, WIPScopeRig: 2}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Scope != usage[j].Scope {
+			return order[usage[i].Scope] < order[usage[j].Scope]
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	return usage
+}
+
+// WIPCounts counts in-progress issues by scope, then by assignee, label or
+// rig name.
+type WIPCounts map[string]map[string]int
+
+func (c WIPCounts) add(scope, name string, n int) {
+	if name == "" {
+		return
+	}
+	if c[scope] == nil {
+		c[scope] = make(map[string]int)
+	}
+	c[scope][name] += n
+}
+
+// CountWIP counts the in-progress issues among issues, whose Labels must be
+// populated.
+func CountWIP(issues []*types.Issue) WIPCounts {
+	counts := WIPCounts{}
+	for _, issue := range issues {
+		if issue.Status != types.StatusInProgress {
+			continue
+		}
+		counts.add(WIPScopeAssignee, issue.Assignee, 1)
+		for _, label := range issue.Labels {
+			counts.add(WIPScopeLabel, label, 1)
+		}
+		counts.add(WIPScopeRig, ActorRig(issue.Assignee), 1)
+	}
+	return counts
+}
+
+// LoadWIP loads the configured limits and, if there are any, the current
+// in-progress counts through the Storage API.
+func LoadWIP(ctx context.Context, s Storage) (*WIPLimits, WIPCounts, error) {
+	config, err := s.GetAllConfig(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	limits, err := ParseWIPLimits(config)
+	if err != nil || limits.Empty() {
+		return limits, WIPCounts{}, err
+	}
+
+	status := types.StatusInProgress
+	inProgress, err := s.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load in-progress issues: %w", err)
+	}
+	if err := attachLabels(ctx, s, inProgress); err != nil {
+		return nil, nil, err
+	}
+	return limits, CountWIP(inProgress), nil
+}
+
+// GetClaimableReadyWork returns the ready work that actor can claim under
+// the WIP limits, and how many ready issues were hidden. With a limit, the
+// page is refilled from later pages, so filter.NextCursor on the result
+// resumes after the last issue returned.
+func GetClaimableReadyWork(ctx context.Context, s Storage, filter types.WorkFilter, actor string) ([]*types.Issue, int, error) {
+	limits, counts, err := LoadWIP(ctx, s)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limits.Empty() {
+		issues, err := s.GetReadyWork(ctx, filter)
+		return issues, 0, err
+	}
+
+	var claimable []*types.Issue
+	hidden := 0
+	for {
+		page, err := s.GetReadyWork(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		kept, err := filterClaimable(ctx, s, limits, counts, page, actor)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, issue := range kept {
+			if filter.Limit > 0 && len(claimable) == filter.Limit {
+				// Issues after the page's last one are seen again on the next page
+				return claimable, hidden, nil
+			}
+			claimable = append(claimable, issue)
+		}
+		hidden += len(page) - len(kept)
+		next := filter.NextCursor(page)
+		if next == "" || len(claimable) == filter.Limit {
+			return claimable, hidden, nil
+		}
+		filter.Cursor = next
+	}
+}
+
+// filterClaimable drops the unassigned issues that actor could not claim
+// without exceeding a limit.
+func filterClaimable(ctx context.Context, s Storage, limits *WIPLimits, counts WIPCounts, issues []*types.Issue, actor string) ([]*types.Issue, error) {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load labels: %w", err)
+	}
+
+	kept := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Assignee != "" {
+			kept = append(kept, issue)
+			continue
+		}
+		issueCounts := counts
+		if issue.Status == types.StatusInProgress {
+			// Already counted toward its labels; a claim doesn't add to them
+			issueCounts = WIPCounts{}
+			for scope, names := range counts {
+				for name, n := range names {
+					issueCounts.add(scope, name, n)
+				}
+			}
+			for _, label := range labels[issue.ID] {
+				issueCounts.add(WIPScopeLabel, label, -1)
+			}
+		}
+		if limits.Check(actor, labels[issue.ID], issueCounts) == nil {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, nil
+}
+
+// WIPUtilization returns the usage of every limited bucket, or nil if no
+// limits are configured.
+func WIPUtilization(ctx context.Context, s Storage) ([]*WIPBucket, error) {
+	limits, counts, err := LoadWIP(ctx, s)
+	if err != nil || limits.Empty() {
+		return nil, err
+	}
+	return limits.Usage(counts), nil
+}
+
+func attachLabels(ctx context.Context, s Storage, issues []*types.Issue) error {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load labels: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+	}
+	return nil
+}
+
+// WIPQueryer is the query subset of *sql.Conn and *sql.Tx used to check WIP
+// limits inside a claim's transaction.
+type WIPQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// CheckWIPLimitsTx enforces WIP limits for actor claiming issueID within
+// the claim's transaction. It works on the SQLite and Dolt schemas and
+// returns a *WIPLimitError if a limit would be exceeded.
+//
+// The counts only stay valid until commit if the transaction keeps other
+// claims out: SQLite's write lock does, while backends with optimistic
+// transactions pass a lock func, called for each limited bucket before it
+// is counted, that makes concurrent claims on the bucket conflict.
+func CheckWIPLimitsTx(ctx context.Context, q WIPQueryer, issueID, actor string, lock func(b *WIPBucket) error) error {
+	rows, err := q.QueryContext(ctx, "SELECT `key`, value FROM config WHERE `key` LIKE 'wip.%'")
+	if err != nil {
+		return fmt.Errorf("failed to load WIP limits: %w", err)
+	}
+	config := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan WIP limit: %w", err)
+		}
+		config[key] = value
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load WIP limits: %w", err)
+	}
+	limits, err := ParseWIPLimits(config)
+	if err != nil || limits.Empty() {
+		return err
+	}
+
+	rows, err = q.QueryContext(ctx, `SELECT label FROM labels WHERE issue_id = ?`, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue labels: %w", err)
+	}
+	var labels []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan label: %w", err)
+		}
+		labels = append(labels, label)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get issue labels: %w", err)
+	}
+
+	counts := WIPCounts{}
+	for _, b := range limits.Buckets(actor, labels) {
+		if lock != nil {
+			if err := lock(b); err != nil {
+				return fmt.Errorf("failed to lock WIP limit for %s %s: %w", b.Scope, b.Name, err)
+			}
+		}
+		var n int
+		var err error
+		switch b.Scope {
+		case WIPScopeAssignee:
+			err = q.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE status = 'in_progress' AND assignee = ? AND id <> ?`,
+				b.Name, issueID).Scan(&n)
+		case WIPScopeLabel:
+			err = q.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues i JOIN labels l ON l.issue_id = i.id
+				WHERE i.status = 'in_progress' AND l.label = ? AND i.id <> ?`, b.Name, issueID).Scan(&n)
+		case WIPScopeRig:
+			n, err = countRigWIPTx(ctx, q, b.Name, issueID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to count in-progress issues for %s %s: %w", b.Scope, b.Name, err)
+		}
+		counts.add(b.Scope, b.Name, n)
+	}
+	return limits.Check(actor, labels, counts)
+}
+
+// countRigWIPTx counts the in-progress issues other than issueID whose
+// assignee works in rig.
+func countRigWIPTx(ctx context.Context, q WIPQueryer, rig, issueID string) (int, error) {
+	rows, err := q.QueryContext(ctx, `SELECT assignee FROM issues
+		WHERE status = 'in_progress' AND assignee LIKE '%/%' AND id <> ?`, issueID)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	n := 0
+	for rows.Next() {
+		var assignee string
+		if err := rows.Scan(&assignee); err != nil {
+			return 0, err
+		}
+		if ActorRig(assignee) == rig {
+			n++
+		}
+	}
+	return n, rows.Err()
+}
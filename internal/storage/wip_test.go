@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func TestParseWIPLimits(t *testing.T) {
+	limits, err := ParseWIPLimits(map[string]string{
+		"wip.assignee":       "3",
+		"wip.assignee.alice": "1",
+		"wip.label.frontend": " 2 ",
+		"wip.rig.alpha":      "0",
+		"issue_prefix":       "bd",
+	})
+	if err != nil {
+		t.Fatalf("ParseWIPLimits: %v", err)
+	}
+	if limits.Assignee != 3 || limits.Assignees["alice"] != 1 || limits.Labels["frontend"] != 2 || limits.Rig != 0 {
+		t.Errorf("unexpected limits %+v", limits)
+	}
+	if limits.Empty() {
+		t.Error("expected limits to be non-empty")
+	}
+	if empty, _ := ParseWIPLimits(map[string]string{"wip.rig.alpha": "0"}); !empty.Empty() {
+		t.Error("expected zero limits to be empty")
+	}
+
+	for key, value := range map[string]string{
+		"wip.label":      "1",
+		"wip.team.core":  "1",
+		"wip.assignee":   "-1",
+		"wip.rig.alpha":  "two",
+		"wip.assignee.x": "",
+	} {
+		if err := ValidateWIPConfig(key, value); err == nil {
+			t.Errorf("expected %s=%q to be rejected", key, value)
+		}
+	}
+}
+
+func TestActorRig(t *testing.T) {
+	for actor, want := range map[string]string{
+		"gastown/crew/max":   "gastown",
+		"beads/polecats/nux": "beads",
+		"alice":              "",
+		"":                   "",
+	} {
+		if got := ActorRig(actor); got != want {
+			t.Errorf("ActorRig(%q) = %q, want %q", actor, got, want)
+		}
+	}
+}
+
+func TestWIPLimitsCheck(t *testing.T) {
+	limits := &WIPLimits{
+		Assignee:  2,
+		Assignees: map[string]int{"lead": 0},
+		Labels:    map[string]int{"frontend": 1},
+		Rigs:      map[string]int{"alpha": 1},
+	}
+	counts := CountWIP([]*types.Issue{
+		{ID: "bd-1", Status: types.StatusInProgress, Assignee: "alice", Labels: []string{"frontend"}},
+		{ID: "bd-2", Status: types.StatusInProgress, Assignee: "alice"},
+		{ID: "bd-3", Status: types.StatusInProgress, Assignee: "lead"},
+		{ID: "bd-4", Status: types.StatusOpen, Assignee: "alpha/crew/bob"},
+		{ID: "bd-5", Status: types.StatusInProgress, Assignee: "alpha/crew/max"},
+	})
+
+	err := limits.Check("alice", nil, counts)
+	var wipErr *WIPLimitError
+	if !errors.As(err, &wipErr) || !errors.Is(err, ErrWIPLimitExceeded) {
+		t.Fatalf("expected a WIP limit error, got %v", err)
+	}
+	if want := (WIPBucket{Scope: WIPScopeAssignee, Name: "alice", Limit: 2, InProgress: 2}); wipErr.WIPBucket != want {
+		t.Errorf("bucket = %+v, want %+v", wipErr.WIPBucket, want)
+	}
+	if want := "WIP limit exceeded: assignee alice has 2 of 2 issues in progress"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if err := limits.Check("bob", []string{"backend"}, counts); err != nil {
+		t.Errorf("bob should be under every limit: %v", err)
+	}
+	if err := limits.Check("lead", nil, counts); err != nil {
+		t.Errorf("a zero override should lift the default limit: %v", err)
+	}
+	if err := limits.Check("bob", []string{"backend", "frontend"}, counts); !errors.As(err, &wipErr) || wipErr.Scope != WIPScopeLabel {
+		t.Errorf("expected the frontend label to be full, got %v", err)
+	}
+	if err := limits.Check("alpha/polecats/nux", nil, counts); !errors.As(err, &wipErr) || wipErr.Scope != WIPScopeRig {
+		t.Errorf("expected the alpha rig to be full, got %v", err)
+	}
+	if err := limits.Check("beta/crew/bob", nil, counts); err != nil {
+		t.Errorf("beta has no rig limit: %v", err)
+	}
+
+	var got []WIPBucket
+	for _, b := range limits.Usage(counts) {
+		got = append(got, *b)
+	}
+	want := []WIPBucket{
+		{Scope: WIPScopeAssignee, Name: "alice", Limit: 2, InProgress: 2},
+		{Scope: WIPScopeAssignee, Name: "alpha/crew/max", Limit: 2, InProgress: 1},
+		{Scope: WIPScopeLabel, Name: "frontend", Limit: 1, InProgress: 1},
+		{Scope: WIPScopeRig, Name: "alpha", Limit: 1, InProgress: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Usage = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Usage[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,621 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// ConflictStrategy decides which side wins when an issue changed both
+// locally and in the tracker since the last sync.
+type ConflictStrategy string
+
+const (
+	// PreferNewer keeps the most recently updated version (default).
+	PreferNewer ConflictStrategy = "prefer-newer"
+	// PreferLocal always keeps the local version.
+	PreferLocal ConflictStrategy = "prefer-local"
+	// PreferRemote always keeps the tracker version.
+	PreferRemote ConflictStrategy = "prefer-remote"
+)
+
+// ActionKind is the kind of change a sync plan makes.
+type ActionKind string
+
+const (
+	ActionPullCreate ActionKind = "pull-create" // Create a local issue from the tracker
+	ActionPullUpdate ActionKind = "pull-update" // Overwrite a local issue with the tracker version
+	ActionPushCreate ActionKind = "push-create" // Create a tracker issue from a local issue
+	ActionPushUpdate ActionKind = "push-update" // Overwrite a tracker issue with the local version
+)
+
+// Action is one planned change.
+type Action struct {
+	Kind    ActionKind `json:"kind"`
+	IssueID string     `json:"issue_id,omitempty"` // Empty for pull-create
+	Ref     string     `json:"ref,omitempty"`      // Empty for push-create
+	Title   string     `json:"title"`
+	Reason  string     `json:"reason"`
+
+	local  *types.Issue
+	remote *RemoteIssue
+}
+
+// Conflict is an issue that changed on both sides since the last sync.
+type Conflict struct {
+	IssueID       string     `json:"issue_id"`
+	Ref           string     `json:"ref"`
+	LocalUpdated  time.Time  `json:"local_updated"`
+	RemoteUpdated time.Time  `json:"remote_updated"`
+	Resolution    ActionKind `json:"resolution"` // pull-update or push-update
+}
+
+// Stats counts the outcome of a sync. In dry-run mode the counts are what
+// the plan would do.
+type Stats struct {
+	PullCreated  int `json:"pull_created"`
+	PullUpdated  int `json:"pull_updated"`
+	PushCreated  int `json:"push_created"`
+	PushUpdated  int `json:"push_updated"`
+	Unchanged    int `json:"unchanged"`
+	Skipped      int `json:"skipped"`
+	Conflicts    int `json:"conflicts"`
+	Dependencies int `json:"dependencies"`
+	Errors       int `json:"errors"`
+}
+
+// Result is the outcome of a sync.
+type Result struct {
+	Tracker     string     `json:"tracker"`
+	DryRun      bool       `json:"dry_run"`
+	Incremental bool       `json:"incremental"`
+	SyncedSince string     `json:"synced_since,omitempty"`
+	LastSync    string     `json:"last_sync,omitempty"` // The cursor saved by this sync, if it moved
+	Plan        []Action   `json:"plan"`
+	Conflicts   []Conflict `json:"conflicts,omitempty"`
+	Stats       Stats      `json:"stats"`
+	Warnings    []string   `json:"warnings,omitempty"`
+}
+
+// SyncOptions controls a sync.
+type SyncOptions struct {
+	Pull       bool             // Apply tracker changes locally
+	Push       bool             // Apply local changes to the tracker
+	DryRun     bool             // Plan without changing anything
+	CreateOnly bool             // Push only creates; never update tracker issues
+	Full       bool             // Ignore the incremental cursor
+	State      string           // Tracker issue state to fetch: open, closed or all
+	Strategy   ConflictStrategy // Empty means PreferNewer
+
+	// Push filters. Issues they exclude are left out of the sync's push
+	// side entirely; tracker changes to linked issues are still pulled.
+	Types            []string // Only push issues of these types (empty means all)
+	ExcludeTypes     []string // Never push issues of these types
+	IncludeEphemeral bool     // Push ephemeral issues (wisps) too
+
+	// NoLink leaves issues created in the tracker unlinked: external_ref
+	// is not set, so a later sync doesn't know they were pushed.
+	NoLink bool
+}
+
+// Engine syncs a tracker with the local store.
+//
+// The engine keeps two pieces of state per tracker: the incremental cursor
+// in the <name>.last_sync config key and the last-synced snapshot in
+// <name>.sync_snapshot metadata. The snapshot records a content hash of each
+// linked issue on both sides as of the last sync, so an issue that changed
+// on one side is synced in that direction and only an issue that changed on
+// both sides is a conflict.
+//
+// It also reads these optional config keys:
+//   - <name>.push_prefix: comma-separated ID prefixes; only matching issues
+//     are pushed
+//   - <name>.pull_prefix: prefix for pulled issues instead of issue_prefix
+//   - <name>.id_mode: hash (default) or db, to let the store assign IDs
+//   - <name>.hash_length: hash length of pulled issue IDs, 3-8 (default 6)
+type Engine struct {
+	Tracker Tracker
+	Store   storage.Storage
+	Actor   string
+}
+
+// NewEngine creates a sync engine.
+func NewEngine(t Tracker, store storage.Storage, actor string) *Engine {
+	return &Engine{Tracker: t, Store: store, Actor: actor}
+}
+
+// cursorKey is the config key holding the time of the last pull.
+func (e *Engine) cursorKey() string {
+	return e.Tracker.Name() + ".last_sync"
+}
+
+// Sync plans a sync and, unless opts.DryRun is set, applies it. Errors on
+// individual issues are counted and reported as warnings; only failures
+// that prevent planning are returned.
+func (e *Engine) Sync(ctx context.Context, opts SyncOptions) (*Result, error) {
+	result := &Result{Tracker: e.Tracker.Name(), DryRun: opts.DryRun}
+	startTime := time.Now().UTC()
+
+	fetchOpts := FetchOptions{State: opts.State}
+	if !opts.Full {
+		if cursor, _ := e.Store.GetConfig(ctx, e.cursorKey()); cursor != "" {
+			if since, err := time.Parse(time.RFC3339, cursor); err == nil {
+				fetchOpts.Since = since
+				result.Incremental = true
+				result.SyncedSince = cursor
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("invalid %s %q, doing full sync", e.cursorKey(), cursor))
+			}
+		}
+	}
+
+	remotes, err := e.Tracker.FetchIssues(ctx, fetchOpts)
+	if err != nil {
+		return result, fmt.Errorf("failed to fetch issues from %s: %w", e.Tracker.DisplayName(), err)
+	}
+
+	locals, err := e.localIssues(ctx)
+	if err != nil {
+		return result, err
+	}
+	snap, err := loadSnapshot(ctx, e.Store, e.Tracker.Name())
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+		snap = snapshot{}
+	}
+
+	ids, err := e.idAllocator(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	e.plan(result, opts, e.pushFilter(ctx, opts), remotes, locals, snap, fetchOpts.Since)
+	if opts.DryRun {
+		return result, nil
+	}
+
+	pullFailed := e.apply(ctx, result, opts, ids, snap)
+	if opts.Pull {
+		e.linkDependencies(ctx, result, remotes)
+	}
+
+	if err := snap.save(ctx, e.Store, e.Tracker.Name()); err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	// The cursor only moves when tracker changes were pulled; otherwise the
+	// next incremental fetch would miss them. A failed pull keeps it where it
+	// was, so the issue is fetched and retried on the next sync.
+	if opts.Pull && pullFailed {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("not advancing %s: some pulls failed", e.cursorKey()))
+	} else if opts.Pull {
+		cursor := startTime.Format(time.RFC3339)
+		if err := e.Store.SetConfig(ctx, e.cursorKey(), cursor); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to save %s: %v", e.cursorKey(), err))
+		} else {
+			result.LastSync = cursor
+		}
+	}
+	return result, nil
+}
+
+// localIssues returns the non-tombstone local issues with their labels.
+func (e *Engine) localIssues(ctx context.Context) ([]*types.Issue, error) {
+	issues, err := e.Store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local issues: %w", err)
+	}
+	ids := make([]string, 0, len(issues))
+	live := issues[:0]
+	for _, issue := range issues {
+		if issue.IsTombstone() {
+			continue
+		}
+		live = append(live, issue)
+		ids = append(ids, issue.ID)
+	}
+	labels, err := e.Store.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	for _, issue := range live {
+		issue.Labels = labels[issue.ID]
+	}
+	return live, nil
+}
+
+// pushFilter returns whether a local issue is in scope for pushing, given
+// the type and ephemeral options and <name>.push_prefix.
+func (e *Engine) pushFilter(ctx context.Context, opts SyncOptions) func(*types.Issue) bool {
+	lower := func(values []string) map[string]bool {
+		set := make(map[string]bool, len(values))
+		for _, value := range values {
+			set[strings.ToLower(value)] = true
+		}
+		return set
+	}
+	only := lower(opts.Types)
+	exclude := lower(opts.ExcludeTypes)
+
+	var prefixes []string
+	raw, _ := e.Store.GetConfig(ctx, e.Tracker.Name()+".push_prefix")
+	for _, prefix := range strings.Split(raw, ",") {
+		if prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "-"); prefix != "" {
+			prefixes = append(prefixes, prefix+"-")
+		}
+	}
+
+	return func(issue *types.Issue) bool {
+		issueType := strings.ToLower(string(issue.IssueType))
+		switch {
+		case issue.Ephemeral && !opts.IncludeEphemeral,
+			len(only) > 0 && !only[issueType],
+			exclude[issueType]:
+			return false
+		case len(prefixes) == 0:
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(issue.ID, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// plan fills result.Plan, result.Conflicts and the planned stats. Push
+// actions for issues pushable rejects are dropped without being counted.
+func (e *Engine) plan(result *Result, opts SyncOptions, pushable func(*types.Issue) bool, remotes []*RemoteIssue, locals []*types.Issue, snap snapshot, since time.Time) {
+	mapper := e.Tracker.Mapper()
+
+	byRef := make(map[string]*types.Issue)
+	var unlinked []*types.Issue
+	for _, issue := range locals {
+		if issue.ExternalRef == nil || *issue.ExternalRef == "" {
+			unlinked = append(unlinked, issue)
+			continue
+		}
+		// Issues linked to other trackers are not ours to sync
+		if ref, ok := e.Tracker.CanonicalRef(*issue.ExternalRef); ok {
+			byRef[ref] = issue
+		}
+	}
+
+	add := func(kind ActionKind, local *types.Issue, remote *RemoteIssue, reason string) {
+		action := Action{Kind: kind, Reason: reason, local: local, remote: remote}
+		if local != nil {
+			action.IssueID = local.ID
+			action.Title = local.Title
+		}
+		if remote != nil {
+			action.Ref = remote.Ref
+			if local == nil {
+				action.Title = remote.Issue.Title
+			}
+		} else if local != nil && local.ExternalRef != nil {
+			action.Ref, _ = e.Tracker.CanonicalRef(*local.ExternalRef)
+		}
+
+		push := kind == ActionPushCreate || kind == ActionPushUpdate
+		if push && !pushable(local) {
+			return
+		}
+		switch {
+		case !push && !opts.Pull,
+			push && !opts.Push,
+			kind == ActionPushUpdate && opts.CreateOnly:
+			result.Stats.Skipped++
+			return
+		}
+		result.Plan = append(result.Plan, action)
+		switch kind {
+		case ActionPullCreate:
+			result.Stats.PullCreated++
+		case ActionPullUpdate:
+			result.Stats.PullUpdated++
+		case ActionPushCreate:
+			result.Stats.PushCreated++
+		case ActionPushUpdate:
+			result.Stats.PushUpdated++
+		}
+	}
+
+	seen := make(map[string]bool, len(remotes))
+	for _, remote := range remotes {
+		seen[remote.Ref] = true
+		local, ok := byRef[remote.Ref]
+		if !ok {
+			add(ActionPullCreate, nil, remote, "new in "+e.Tracker.DisplayName())
+			continue
+		}
+
+		localHash := ContentHash(local, mapper)
+		remoteHash := ContentHash(remote.Issue, mapper)
+		entry, synced := snap[remote.Ref]
+		localChanged := !synced || localHash != entry.LocalHash
+		remoteChanged := !synced || remoteHash != entry.RemoteHash
+
+		switch {
+		case localHash == remoteHash || (!localChanged && !remoteChanged):
+			// Already in sync; refresh the snapshot so later edits compare
+			// against the current content.
+			snap[remote.Ref] = snapshotEntry{IssueID: local.ID, LocalHash: localHash, RemoteHash: remoteHash}
+			result.Stats.Unchanged++
+		case localChanged && !remoteChanged:
+			add(ActionPushUpdate, local, remote, "changed locally")
+		case remoteChanged && !localChanged:
+			add(ActionPullUpdate, local, remote, "changed in "+e.Tracker.DisplayName())
+		default:
+			kind := resolveConflict(opts.Strategy, local.UpdatedAt, remote.Issue.UpdatedAt)
+			result.Conflicts = append(result.Conflicts, Conflict{
+				IssueID:       local.ID,
+				Ref:           remote.Ref,
+				LocalUpdated:  local.UpdatedAt,
+				RemoteUpdated: remote.Issue.UpdatedAt,
+				Resolution:    kind,
+			})
+			result.Stats.Conflicts++
+			reason := "conflict, local wins"
+			if kind == ActionPullUpdate {
+				reason = "conflict, " + e.Tracker.DisplayName() + " wins"
+			}
+			add(kind, local, remote, reason)
+		}
+	}
+
+	// Linked issues the fetch didn't return are unchanged remotely since the
+	// cursor, so any local change is pushed.
+	refs := make([]string, 0, len(byRef))
+	for ref := range byRef {
+		if !seen[ref] {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		local := byRef[ref]
+		entry, synced := snap[ref]
+		switch {
+		case synced && ContentHash(local, mapper) != entry.LocalHash:
+			add(ActionPushUpdate, local, nil, "changed locally")
+		case !synced && !since.IsZero() && local.UpdatedAt.After(since):
+			add(ActionPushUpdate, local, nil, "changed locally since last sync")
+		}
+	}
+
+	for _, local := range unlinked {
+		add(ActionPushCreate, local, nil, "new local issue")
+	}
+}
+
+// resolveConflict picks the winning side of a conflict.
+func resolveConflict(strategy ConflictStrategy, localUpdated, remoteUpdated time.Time) ActionKind {
+	switch strategy {
+	case PreferLocal:
+		return ActionPushUpdate
+	case PreferRemote:
+		return ActionPullUpdate
+	default:
+		if remoteUpdated.After(localUpdated) {
+			return ActionPullUpdate
+		}
+		return ActionPushUpdate
+	}
+}
+
+// apply executes result.Plan, recording each synced issue in snap. Failed
+// actions are counted as errors and leave the snapshot untouched, so they
+// are retried on the next sync; it reports whether any pull failed, which
+// must also hold back the incremental cursor.
+func (e *Engine) apply(ctx context.Context, result *Result, opts SyncOptions, ids *idAllocator, snap snapshot) (pullFailed bool) {
+	mapper := e.Tracker.Mapper()
+	name := e.Tracker.DisplayName()
+
+	for i := range result.Plan {
+		action := &result.Plan[i]
+		var err error
+		switch action.Kind {
+		case ActionPullCreate:
+			err = e.pullCreate(ctx, action, ids)
+		case ActionPullUpdate:
+			err = e.pullUpdate(ctx, action)
+		case ActionPushCreate:
+			err = e.pushCreate(ctx, action, opts.NoLink)
+		case ActionPushUpdate:
+			err = e.pushUpdate(ctx, action)
+		}
+		if err != nil {
+			result.Stats.Errors++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s %s: %v", action.Kind, actionSubject(action), err))
+			if action.Kind == ActionPullCreate || action.Kind == ActionPullUpdate {
+				pullFailed = true
+			}
+			continue
+		}
+
+		switch action.Kind {
+		case ActionPullCreate, ActionPullUpdate:
+			// The local issue now holds the tracker's synced fields
+			hash := ContentHash(action.remote.Issue, mapper)
+			snap[action.remote.Ref] = snapshotEntry{IssueID: action.IssueID, LocalHash: hash, RemoteHash: hash}
+		case ActionPushCreate, ActionPushUpdate:
+			if action.remote == nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s returned no issue for %s", name, action.IssueID))
+				continue
+			}
+			if action.Kind == ActionPushCreate && opts.NoLink {
+				continue
+			}
+			snap[action.remote.Ref] = snapshotEntry{
+				IssueID:    action.IssueID,
+				LocalHash:  ContentHash(action.local, mapper),
+				RemoteHash: ContentHash(action.remote.Issue, mapper),
+			}
+		}
+	}
+	return pullFailed
+}
+
+// actionSubject names the issue an action is about, for warnings.
+func actionSubject(action *Action) string {
+	if action.IssueID != "" {
+		return action.IssueID
+	}
+	return action.Ref
+}
+
+// pullCreate creates a local issue from a tracker issue.
+func (e *Engine) pullCreate(ctx context.Context, action *Action, ids *idAllocator) error {
+	issue := *action.remote.Issue
+	issue.ID = ""
+	ref := action.remote.Ref
+	issue.ExternalRef = &ref
+	issue.Labels = nil
+	if err := ids.assign(ctx, &issue); err != nil {
+		return err
+	}
+	opts := storage.BatchCreateOptions{SkipPrefixValidation: ids.pullPrefix}
+	if err := e.Store.CreateIssuesWithFullOptions(ctx, []*types.Issue{&issue}, e.Actor, opts); err != nil {
+		return err
+	}
+	action.IssueID = issue.ID
+	return e.setLabels(ctx, issue.ID, nil, action.remote.Issue.Labels)
+}
+
+// pullUpdate overwrites a local issue's synced fields with the tracker's.
+func (e *Engine) pullUpdate(ctx context.Context, action *Action) error {
+	remote := action.remote.Issue
+	updates := map[string]interface{}{
+		"title":       remote.Title,
+		"description": remote.Description,
+		"status":      string(remote.Status),
+		"priority":    remote.Priority,
+		"issue_type":  string(remote.IssueType),
+		"assignee":    remote.Assignee,
+	}
+	if err := e.Store.UpdateIssue(ctx, action.IssueID, updates, e.Actor); err != nil {
+		return err
+	}
+	return e.setLabels(ctx, action.IssueID, action.local.Labels, remote.Labels)
+}
+
+// pushCreate creates a tracker issue and, unless noLink is set, links the
+// local issue to it.
+func (e *Engine) pushCreate(ctx context.Context, action *Action, noLink bool) error {
+	remote, err := e.Tracker.CreateIssue(ctx, action.local)
+	if err != nil {
+		return err
+	}
+	action.remote = remote
+	action.Ref = remote.Ref
+	if noLink {
+		return nil
+	}
+	updates := map[string]interface{}{"external_ref": remote.Ref}
+	if err := e.Store.UpdateIssue(ctx, action.IssueID, updates, e.Actor); err != nil {
+		return fmt.Errorf("created %s but failed to save external_ref: %w", remote.Ref, err)
+	}
+	return nil
+}
+
+// pushUpdate overwrites a tracker issue with the local version.
+func (e *Engine) pushUpdate(ctx context.Context, action *Action) error {
+	remote, err := e.Tracker.UpdateIssue(ctx, action.Ref, action.local)
+	if err != nil {
+		return err
+	}
+	action.remote = remote
+	return nil
+}
+
+// setLabels changes an issue's labels from old to labels.
+func (e *Engine) setLabels(ctx context.Context, issueID string, old, labels []string) error {
+	want := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		want[label] = true
+	}
+	have := make(map[string]bool, len(old))
+	for _, label := range old {
+		have[label] = true
+		if !want[label] {
+			if err := e.Store.RemoveLabel(ctx, issueID, label, e.Actor); err != nil {
+				return fmt.Errorf("failed to remove label %q: %w", label, err)
+			}
+		}
+	}
+	for _, label := range labels {
+		if !have[label] {
+			if err := e.Store.AddLabel(ctx, issueID, label, e.Actor); err != nil {
+				return fmt.Errorf("failed to add label %q: %w", label, err)
+			}
+			have[label] = true
+		}
+	}
+	return nil
+}
+
+// linkDependencies creates the local dependencies for tracker links whose
+// ends are both linked to local issues.
+func (e *Engine) linkDependencies(ctx context.Context, result *Result, remotes []*RemoteIssue) {
+	var deps []Dependency
+	for _, remote := range remotes {
+		deps = append(deps, remote.Dependencies...)
+	}
+	if len(deps) == 0 {
+		return
+	}
+
+	locals, err := e.Store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to map dependencies: %v", err))
+		return
+	}
+	idByRef := make(map[string]string)
+	for _, issue := range locals {
+		if issue.ExternalRef == nil {
+			continue
+		}
+		if ref, ok := e.Tracker.CanonicalRef(*issue.ExternalRef); ok {
+			idByRef[ref] = issue.ID
+		}
+	}
+
+	existing, err := e.Store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to read dependencies: %v", err))
+		return
+	}
+	exists := func(from, to string) bool {
+		for _, dep := range existing[from] {
+			if dep.DependsOnID == to {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, dep := range deps {
+		fromID, fromOK := idByRef[dep.FromRef]
+		toID, toOK := idByRef[dep.ToRef]
+		if !fromOK || !toOK || fromID == toID || exists(fromID, toID) {
+			continue
+		}
+		record := &types.Dependency{
+			IssueID:     fromID,
+			DependsOnID: toID,
+			Type:        dep.Type,
+			CreatedAt:   time.Now(),
+		}
+		if err := e.Store.AddDependency(ctx, record, e.Actor); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to create dependency %s -> %s (%s): %v", fromID, toID, dep.Type, err))
+			continue
+		}
+		existing[fromID] = append(existing[fromID], record)
+		result.Stats.Dependencies++
+	}
+}
@@ -0,0 +1,501 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// fakeTracker is an in-memory tracker. Issues are keyed by ref.
+type fakeTracker struct {
+	issues  map[string]*types.Issue
+	broken  map[string]bool // Refs returned without a title, so pulling them fails
+	deps    []Dependency
+	next    int
+	created int
+	updated int
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{issues: make(map[string]*types.Issue), broken: make(map[string]bool)}
+}
+
+func (f *fakeTracker) Name() string        { return "fake" }
+func (f *fakeTracker) DisplayName() string { return "Fake" }
+func (f *fakeTracker) Mapper() FieldMapper { return fakeMapper{} }
+
+func (f *fakeTracker) CanonicalRef(ref string) (string, bool) {
+	return strings.TrimSuffix(ref, "/"), strings.HasPrefix(ref, "fake://")
+}
+
+// add stores a tracker-side issue and returns its ref.
+func (f *fakeTracker) add(issue *types.Issue) string {
+	f.next++
+	ref := fmt.Sprintf("fake://%d", f.next)
+	stored := *issue
+	stored.ExternalRef = &ref
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = time.Now()
+	}
+	f.issues[ref] = &stored
+	return ref
+}
+
+func (f *fakeTracker) remote(ref string) *RemoteIssue {
+	issue := *f.issues[ref]
+	if f.broken[ref] {
+		issue.Title = ""
+	}
+	remote := &RemoteIssue{Ref: ref, Key: "#" + strings.TrimPrefix(ref, "fake://"), Issue: &issue}
+	for _, dep := range f.deps {
+		if dep.FromRef == ref {
+			remote.Dependencies = append(remote.Dependencies, dep)
+		}
+	}
+	return remote
+}
+
+func (f *fakeTracker) FetchIssues(_ context.Context, opts FetchOptions) ([]*RemoteIssue, error) {
+	var out []*RemoteIssue
+	for i := 1; i <= f.next; i++ {
+		ref := fmt.Sprintf("fake://%d", i)
+		if issue, ok := f.issues[ref]; ok && !issue.UpdatedAt.Before(opts.Since) {
+			out = append(out, f.remote(ref))
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTracker) CreateIssue(_ context.Context, issue *types.Issue) (*RemoteIssue, error) {
+	f.created++
+	return f.remote(f.add(syncedFields(issue))), nil
+}
+
+func (f *fakeTracker) UpdateIssue(_ context.Context, ref string, issue *types.Issue) (*RemoteIssue, error) {
+	if _, ok := f.issues[ref]; !ok {
+		return nil, fmt.Errorf("%s not found", ref)
+	}
+	f.updated++
+	stored := syncedFields(issue)
+	stored.ExternalRef = &ref
+	stored.UpdatedAt = time.Now()
+	f.issues[ref] = stored
+	return f.remote(ref), nil
+}
+
+// syncedFields copies the fields the fake tracker stores.
+func syncedFields(issue *types.Issue) *types.Issue {
+	return &types.Issue{
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.Status,
+		Priority:    issue.Priority,
+		IssueType:   issue.IssueType,
+		Labels:      append([]string(nil), issue.Labels...),
+	}
+}
+
+// fakeMapper stores priorities as "P<n>" and has no "deferred" status.
+type fakeMapper struct{}
+
+func (fakeMapper) StatusToTracker(s types.Status) string {
+	if s == types.StatusDeferred {
+		return string(types.StatusOpen)
+	}
+	return string(s)
+}
+func (fakeMapper) StatusToBeads(s string) types.Status    { return types.Status(s) }
+func (fakeMapper) PriorityToTracker(p int) string         { return "P" + strconv.Itoa(p) }
+func (fakeMapper) TypeToTracker(t types.IssueType) string { return string(t) }
+func (fakeMapper) TypeToBeads(t string) types.IssueType   { return types.IssueType(t) }
+func (fakeMapper) PriorityToBeads(p string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(p, "P"))
+	return n
+}
+
+func newEngineTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	ctx := context.Background()
+	store, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatalf("Failed to set prefix: %v", err)
+	}
+	return store
+}
+
+func createLocal(t *testing.T, store *sqlite.SQLiteStorage, title string) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	return issue
+}
+
+func planKinds(result *Result) map[ActionKind]int {
+	kinds := make(map[ActionKind]int)
+	for _, action := range result.Plan {
+		kinds[action.Kind]++
+	}
+	return kinds
+}
+
+func TestEngineSync_InitialAndIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	parentRef := fake.add(&types.Issue{Title: "Remote epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, Labels: []string{"ui"}})
+	childRef := fake.add(&types.Issue{Title: "Remote child", Status: types.StatusClosed, Priority: 3, IssueType: types.TypeTask})
+	fake.deps = []Dependency{{FromRef: childRef, ToRef: parentRef, Type: types.DepParentChild}}
+	local := createLocal(t, store, "Local only")
+
+	engine := NewEngine(fake, store, "test")
+	opts := SyncOptions{Pull: true, Push: true}
+
+	// Dry run plans without touching either side
+	result, err := engine.Sync(ctx, SyncOptions{Pull: true, Push: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if kinds := planKinds(result); kinds[ActionPullCreate] != 2 || kinds[ActionPushCreate] != 1 {
+		t.Errorf("dry-run plan = %v", kinds)
+	}
+	if fake.created != 0 {
+		t.Error("dry run created tracker issues")
+	}
+	if cursor, _ := store.GetConfig(ctx, "fake.last_sync"); cursor != "" {
+		t.Errorf("dry run saved cursor %q", cursor)
+	}
+
+	result, err = engine.Sync(ctx, opts)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.Stats.PullCreated != 2 || result.Stats.PushCreated != 1 || result.Stats.Errors != 0 {
+		t.Fatalf("stats = %+v, warnings = %v", result.Stats, result.Warnings)
+	}
+	if result.Stats.Dependencies != 1 {
+		t.Errorf("dependencies = %d, want 1", result.Stats.Dependencies)
+	}
+
+	pushed, err := store.GetIssue(ctx, local.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushed.ExternalRef == nil || fake.issues[*pushed.ExternalRef] == nil {
+		t.Errorf("local issue not linked: %v", pushed.ExternalRef)
+	}
+	epic, err := store.GetIssueByExternalRef(ctx, parentRef)
+	if err != nil || epic == nil {
+		t.Fatalf("pulled epic missing: %v", err)
+	}
+	if labels, _ := store.GetLabels(ctx, epic.ID); len(labels) != 1 || labels[0] != "ui" {
+		t.Errorf("epic labels = %v", labels)
+	}
+	if child, _ := store.GetIssueByExternalRef(ctx, childRef); child == nil || child.Status != types.StatusClosed {
+		t.Errorf("pulled child = %+v", child)
+	}
+	if cursor, _ := store.GetConfig(ctx, "fake.last_sync"); cursor == "" {
+		t.Error("cursor not saved")
+	}
+
+	// A second full sync finds nothing to do
+	result, err = engine.Sync(ctx, SyncOptions{Pull: true, Push: true, Full: true})
+	if err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if len(result.Plan) != 0 || result.Stats.Unchanged != 3 {
+		t.Errorf("second sync plan = %+v, stats = %+v", result.Plan, result.Stats)
+	}
+}
+
+func TestEngineSync_ThreeWay(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	localRef := fake.add(&types.Issue{Title: "Edited locally", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+	remoteRef := fake.add(&types.Issue{Title: "Edited remotely", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+	bothRef := fake.add(&types.Issue{Title: "Edited on both", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+	lossyRef := fake.add(&types.Issue{Title: "Lossy", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+
+	engine := NewEngine(fake, store, "test")
+	if _, err := engine.Sync(ctx, SyncOptions{Pull: true, Push: true}); err != nil {
+		t.Fatalf("initial Sync: %v", err)
+	}
+	idFor := func(ref string) string {
+		issue, err := store.GetIssueByExternalRef(ctx, ref)
+		if err != nil || issue == nil {
+			t.Fatalf("no local issue for %s: %v", ref, err)
+		}
+		return issue.ID
+	}
+
+	update := func(id string, updates map[string]interface{}) {
+		if err := store.UpdateIssue(ctx, id, updates, "test"); err != nil {
+			t.Fatalf("UpdateIssue: %v", err)
+		}
+	}
+	update(idFor(localRef), map[string]interface{}{"title": "Edited locally v2"})
+	// Deferred maps to open in the tracker, so both sides stay in sync
+	// even though the content differs.
+	update(idFor(lossyRef), map[string]interface{}{"status": string(types.StatusDeferred)})
+	if _, err := engine.Sync(ctx, SyncOptions{Pull: true, Push: true, Full: true}); err != nil {
+		t.Fatalf("lossy Sync: %v", err)
+	}
+
+	update(idFor(bothRef), map[string]interface{}{"priority": 0})
+	time.Sleep(10 * time.Millisecond)
+	fake.issues[remoteRef].Title = "Edited remotely v2"
+	fake.issues[remoteRef].UpdatedAt = time.Now()
+	fake.issues[bothRef].Title = "Edited on both, remote"
+	fake.issues[bothRef].UpdatedAt = time.Now()
+
+	fake.updated = 0
+	result, err := engine.Sync(ctx, SyncOptions{Pull: true, Push: true, Full: true})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.Stats.Errors != 0 {
+		t.Fatalf("warnings = %v", result.Warnings)
+	}
+
+	// Local edits were pushed in the lossy sync; now only the remote edits
+	// and the conflict remain.
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Ref != bothRef {
+		t.Fatalf("conflicts = %+v", result.Conflicts)
+	}
+	if result.Conflicts[0].Resolution != ActionPullUpdate {
+		t.Errorf("resolution = %s, want pull-update (remote is newer)", result.Conflicts[0].Resolution)
+	}
+	if got := fake.issues[localRef].Title; got != "Edited locally v2" {
+		t.Errorf("tracker title = %q, want pushed local edit", got)
+	}
+	if issue, _ := store.GetIssue(ctx, idFor(remoteRef)); issue.Title != "Edited remotely v2" {
+		t.Errorf("local title = %q, want pulled remote edit", issue.Title)
+	}
+	if issue, _ := store.GetIssue(ctx, idFor(bothRef)); issue.Title != "Edited on both, remote" || issue.Priority != 2 {
+		t.Errorf("conflicted issue = %q P%d, want remote version", issue.Title, issue.Priority)
+	}
+	if issue, _ := store.GetIssue(ctx, idFor(lossyRef)); issue.Status != types.StatusDeferred {
+		t.Errorf("lossy issue status = %s, want deferred kept", issue.Status)
+	}
+	if fake.updated != 0 {
+		t.Errorf("tracker updated %d times, want 0", fake.updated)
+	}
+}
+
+func TestEngineSync_DirectionAndStrategy(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	ref := fake.add(&types.Issue{Title: "Shared", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+
+	engine := NewEngine(fake, store, "test")
+	if _, err := engine.Sync(ctx, SyncOptions{Pull: true}); err != nil {
+		t.Fatalf("initial Sync: %v", err)
+	}
+	issue, _ := store.GetIssueByExternalRef(ctx, ref)
+	createLocal(t, store, "Not pushed")
+
+	// Remote edit is older than the local one, but prefer-remote wins
+	fake.issues[ref].Title = "Remote"
+	fake.issues[ref].UpdatedAt = time.Now().Add(-time.Hour)
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Local"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := engine.Sync(ctx, SyncOptions{Push: true, Full: true, Strategy: PreferRemote})
+	if err != nil {
+		t.Fatalf("push-only Sync: %v", err)
+	}
+	// The conflict resolves to a pull, which push-only skips; the new
+	// local issue is still created.
+	if result.Stats.Skipped != 1 || result.Stats.PushCreated != 1 || fake.created != 1 {
+		t.Errorf("push-only stats = %+v", result.Stats)
+	}
+
+	result, err = engine.Sync(ctx, SyncOptions{Pull: true, Push: true, Full: true, Strategy: PreferRemote})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Stats.PullUpdated != 1 {
+		t.Errorf("conflicts = %+v, stats = %+v", result.Conflicts, result.Stats)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Title != "Remote" {
+		t.Errorf("title = %q, want Remote", got.Title)
+	}
+}
+
+func TestEngineSync_PushFilters(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	task := createLocal(t, store, "Task")
+	bug := &types.Issue{Title: "Bug", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	wisp := &types.Issue{Title: "Wisp", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Ephemeral: true}
+	other := &types.Issue{ID: "ops-1", Title: "Other prefix", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{bug, wisp} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CreateIssuesWithFullOptions(ctx, []*types.Issue{other}, "test", storage.BatchCreateOptions{SkipPrefixValidation: true}); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(fake, store, "test")
+
+	pushed := func(opts SyncOptions) []string {
+		t.Helper()
+		opts.Push, opts.DryRun = true, true
+		result, err := engine.Sync(ctx, opts)
+		if err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+		var ids []string
+		for _, action := range result.Plan {
+			ids = append(ids, action.IssueID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	all := []string{bug.ID, other.ID, task.ID}
+	sort.Strings(all)
+	if got := pushed(SyncOptions{}); strings.Join(got, ",") != strings.Join(all, ",") {
+		t.Errorf("default push = %v, want %v (no wisp)", got, all)
+	}
+	if got := pushed(SyncOptions{Types: []string{"BUG"}}); len(got) != 1 || got[0] != bug.ID {
+		t.Errorf("--type bug push = %v", got)
+	}
+	if got := pushed(SyncOptions{ExcludeTypes: []string{"bug"}, IncludeEphemeral: true}); len(got) != 3 || strings.Contains(strings.Join(got, ","), bug.ID) {
+		t.Errorf("--exclude-type bug --include-ephemeral push = %v", got)
+	}
+
+	if err := store.SetConfig(ctx, "fake.push_prefix", "ops-, other"); err != nil {
+		t.Fatal(err)
+	}
+	if got := pushed(SyncOptions{}); len(got) != 1 || got[0] != other.ID {
+		t.Errorf("push_prefix push = %v, want [%s]", got, other.ID)
+	}
+
+	// NoLink creates tracker issues without linking them
+	if err := store.SetConfig(ctx, "fake.push_prefix", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Sync(ctx, SyncOptions{Push: true, Types: []string{"task"}, NoLink: true}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, task.ID); fake.created != 2 || got.ExternalRef != nil {
+		t.Errorf("created %d, external_ref = %v; want 2 unlinked", fake.created, got.ExternalRef)
+	}
+}
+
+func TestEngineSync_PulledIDs(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	ref := fake.add(&types.Issue{Title: "Imported", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedAt: time.Now()})
+	engine := NewEngine(fake, store, "test")
+
+	for key, value := range map[string]string{"fake.pull_prefix": "hippo-", "fake.hash_length": "4"} {
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := engine.Sync(ctx, SyncOptions{Pull: true}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	issue, err := store.GetIssueByExternalRef(ctx, ref)
+	if err != nil || issue == nil {
+		t.Fatalf("pulled issue missing: %v", err)
+	}
+	if !strings.HasPrefix(issue.ID, "hippo-") || len(issue.ID) != len("hippo-")+4 {
+		t.Errorf("ID = %q, want hippo- and a 4-character hash", issue.ID)
+	}
+
+	if err := store.SetConfig(ctx, "fake.id_mode", "sequential"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Sync(ctx, SyncOptions{Pull: true}); err == nil || !strings.Contains(err.Error(), "fake.id_mode") {
+		t.Errorf("unsupported id_mode err = %v", err)
+	}
+}
+
+func TestEngineSync_FailedPullIsRetried(t *testing.T) {
+	ctx := context.Background()
+	store := newEngineTestStore(t)
+	fake := newFakeTracker()
+	fake.add(&types.Issue{Title: "Synced", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+	engine := NewEngine(fake, store, "test")
+	pull := SyncOptions{Pull: true}
+
+	if _, err := engine.Sync(ctx, pull); err != nil {
+		t.Fatalf("initial Sync: %v", err)
+	}
+	cursor, _ := store.GetConfig(ctx, "fake.last_sync")
+	if cursor == "" {
+		t.Fatal("initial sync did not set the cursor")
+	}
+
+	// A change made after the cursor fails to pull
+	ref := fake.add(&types.Issue{Title: "Flaky", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask,
+		UpdatedAt: time.Now().Add(time.Second)})
+	fake.broken[ref] = true
+	time.Sleep(1100 * time.Millisecond) // Let a cursor advance past the change
+	result, err := engine.Sync(ctx, pull)
+	if err != nil {
+		t.Fatalf("failing Sync: %v", err)
+	}
+	if result.Stats.Errors != 1 {
+		t.Fatalf("errors = %d, want 1 (warnings %v)", result.Stats.Errors, result.Warnings)
+	}
+	if got, _ := store.GetConfig(ctx, "fake.last_sync"); got != cursor {
+		t.Errorf("cursor moved from %s to %s after a failed pull", cursor, got)
+	}
+
+	// The tracker issue hasn't changed since, but the next incremental
+	// sync still fetches and pulls it
+	delete(fake.broken, ref)
+	result, err = engine.Sync(ctx, pull)
+	if err != nil {
+		t.Fatalf("retry Sync: %v", err)
+	}
+	if !result.Incremental || result.Stats.PullCreated != 1 || result.Stats.Errors != 0 {
+		t.Errorf("retry: incremental = %v, stats = %+v, warnings = %v", result.Incremental, result.Stats, result.Warnings)
+	}
+	if got, _ := store.GetConfig(ctx, "fake.last_sync"); got == cursor {
+		t.Error("cursor did not advance after a clean sync")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	Register("fake", func(context.Context, Config) (Tracker, error) { return newFakeTracker(), nil })
+	defer delete(registry, "fake")
+
+	found := false
+	for _, name := range Trackers() {
+		found = found || name == "fake"
+	}
+	if !found {
+		t.Errorf("Trackers() = %v, want fake", Trackers())
+	}
+	if tr, err := New(context.Background(), "fake", nil); err != nil || tr.Name() != "fake" {
+		t.Errorf("New(fake) = %v, %v", tr, err)
+	}
+	if _, err := New(context.Background(), "nope", nil); err == nil || !strings.Contains(err.Error(), "unknown tracker") {
+		t.Errorf("New(nope) err = %v", err)
+	}
+}
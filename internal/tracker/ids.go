@@ -0,0 +1,158 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/idgen"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// IDGenerationOptions configures hash ID generation for imported issues.
+type IDGenerationOptions struct {
+	BaseLength int             // Starting hash length (3-8)
+	MaxLength  int             // Maximum hash length (3-8)
+	UsedIDs    map[string]bool // Pre-populated set to avoid collisions (e.g., DB IDs)
+}
+
+// GenerateIssueIDs generates unique hash-based IDs for issues that don't have one.
+// Tracks used IDs to prevent collisions within the batch (and optionally against existing IDs).
+// The creator parameter is used as part of the hash input (e.g., "linear-import").
+func GenerateIssueIDs(issues []*types.Issue, prefix, creator string, opts IDGenerationOptions) error {
+	usedIDs := opts.UsedIDs
+	if usedIDs == nil {
+		usedIDs = make(map[string]bool)
+	}
+
+	baseLength := opts.BaseLength
+	if baseLength == 0 {
+		baseLength = 6
+	}
+	maxLength := opts.MaxLength
+	if maxLength == 0 {
+		maxLength = 8
+	}
+	if baseLength < 3 {
+		baseLength = 3
+	}
+	if maxLength > 8 {
+		maxLength = 8
+	}
+	if baseLength > maxLength {
+		baseLength = maxLength
+	}
+
+	// First pass: record existing IDs
+	for _, issue := range issues {
+		if issue.ID != "" {
+			usedIDs[issue.ID] = true
+		}
+	}
+
+	// Second pass: generate IDs for issues without one
+	for _, issue := range issues {
+		if issue.ID != "" {
+			continue // Already has an ID
+		}
+
+		var generated bool
+		for length := baseLength; length <= maxLength && !generated; length++ {
+			for nonce := 0; nonce < 10; nonce++ {
+				candidate := idgen.GenerateHashID(
+					prefix,
+					issue.Title,
+					issue.Description,
+					creator,
+					issue.CreatedAt,
+					length,
+					nonce,
+				)
+
+				if !usedIDs[candidate] {
+					issue.ID = candidate
+					usedIDs[candidate] = true
+					generated = true
+					break
+				}
+			}
+		}
+
+		if !generated {
+			return fmt.Errorf("failed to generate unique ID for issue '%s' after trying lengths %d-%d with 10 nonces each",
+				issue.Title, baseLength, maxLength)
+		}
+	}
+
+	return nil
+}
+
+// idAllocator assigns IDs to issues pulled from a tracker, as configured by
+// <name>.id_mode, <name>.hash_length and <name>.pull_prefix.
+type idAllocator struct {
+	store      storage.Storage
+	hash       bool   // False in db mode: the store assigns IDs
+	prefix     string // pull_prefix, else issue_prefix
+	pullPrefix bool   // prefix differs from issue_prefix on purpose
+	creator    string
+	hashLength int
+	used       map[string]bool // Loaded on first use
+}
+
+// idAllocator reads the tracker's ID settings.
+func (e *Engine) idAllocator(ctx context.Context) (*idAllocator, error) {
+	name := e.Tracker.Name()
+	config := func(key string) string {
+		value, _ := e.Store.GetConfig(ctx, name+"."+key)
+		return strings.TrimSpace(value)
+	}
+
+	ids := &idAllocator{store: e.Store, creator: name + "-import", hashLength: 6}
+	switch mode := strings.ToLower(config("id_mode")); mode {
+	case "", "hash":
+		ids.hash = true
+	case "db":
+		return ids, nil
+	default:
+		return nil, fmt.Errorf("unsupported %s.id_mode %q (expected \"hash\" or \"db\")", name, mode)
+	}
+
+	if n, err := strconv.Atoi(config("hash_length")); err == nil {
+		ids.hashLength = min(max(n, 3), 8)
+	}
+	// <name>.pull_prefix deliberately imports under a second prefix
+	ids.prefix = strings.TrimSuffix(config("pull_prefix"), "-")
+	ids.pullPrefix = ids.prefix != ""
+	if !ids.pullPrefix {
+		ids.prefix, _ = e.Store.GetConfig(ctx, "issue_prefix")
+	}
+	if ids.prefix == "" {
+		ids.prefix = "fbd"
+	}
+	return ids, nil
+}
+
+// assign gives issue a hash ID that no local issue, tombstones included,
+// already uses. In db mode it leaves the ID empty.
+func (a *idAllocator) assign(ctx context.Context, issue *types.Issue) error {
+	if !a.hash {
+		return nil
+	}
+	if a.used == nil {
+		existing, err := a.store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+		if err != nil {
+			return fmt.Errorf("failed to fetch existing issues for ID collision avoidance: %w", err)
+		}
+		a.used = make(map[string]bool, len(existing))
+		for _, issue := range existing {
+			a.used[issue.ID] = true
+		}
+	}
+	return GenerateIssueIDs([]*types.Issue{issue}, a.prefix, a.creator, IDGenerationOptions{
+		BaseLength: a.hashLength,
+		MaxLength:  8,
+		UsedIDs:    a.used,
+	})
+}
@@ -0,0 +1,85 @@
+package tracker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// snapshotEntry records a linked issue's content hashes as of the last sync.
+// Local and tracker content are hashed separately because a mapping may be
+// lossy: the two sides can differ while still being in sync.
+type snapshotEntry struct {
+	IssueID    string `json:"issue_id"`
+	LocalHash  string `json:"local_hash"`
+	RemoteHash string `json:"remote_hash"`
+}
+
+// snapshot maps canonical external refs to their last-synced state.
+type snapshot map[string]snapshotEntry
+
+// snapshotKey is the metadata key holding a tracker's snapshot.
+func snapshotKey(name string) string {
+	return name + ".sync_snapshot"
+}
+
+// loadSnapshot reads a tracker's snapshot. A missing snapshot is empty.
+func loadSnapshot(ctx context.Context, store storage.Storage, name string) (snapshot, error) {
+	raw, err := store.GetMetadata(ctx, snapshotKey(name))
+	if err != nil || raw == "" {
+		return snapshot{}, nil
+	}
+	snap := snapshot{}
+	if err := json.Unmarshal([]byte(raw), &snap); err != nil {
+		return nil, fmt.Errorf("ignoring corrupt %s: %w", snapshotKey(name), err)
+	}
+	return snap, nil
+}
+
+// save writes the snapshot.
+func (s snapshot) save(ctx context.Context, store storage.Storage, name string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", snapshotKey(name), err)
+	}
+	if err := store.SetMetadata(ctx, snapshotKey(name), string(data)); err != nil {
+		return fmt.Errorf("failed to save %s: %w", snapshotKey(name), err)
+	}
+	return nil
+}
+
+// Normalize returns a copy of the issue reduced to the synced fields, with
+// status, priority and type round-tripped through the mapper so values the
+// tracker can't represent compare equal to what a pull would produce.
+func Normalize(issue *types.Issue, mapper FieldMapper) *types.Issue {
+	normalized := &types.Issue{
+		Title:       issue.Title,
+		Description: strings.TrimSpace(issue.Description),
+		Status:      mapper.StatusToBeads(mapper.StatusToTracker(issue.Status)),
+		Priority:    mapper.PriorityToBeads(mapper.PriorityToTracker(issue.Priority)),
+		IssueType:   mapper.TypeToBeads(mapper.TypeToTracker(issue.IssueType)),
+		Assignee:    issue.Assignee,
+	}
+	normalized.Labels = append([]string(nil), issue.Labels...)
+	sort.Strings(normalized.Labels)
+	return normalized
+}
+
+// ContentHash returns a hash of the issue's synced fields, including labels.
+func ContentHash(issue *types.Issue, mapper FieldMapper) string {
+	normalized := Normalize(issue, mapper)
+	h := sha256.New()
+	h.Write([]byte(normalized.ComputeContentHash()))
+	for _, label := range normalized.Labels {
+		h.Write([]byte{0})
+		h.Write([]byte(label))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
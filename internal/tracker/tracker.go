@@ -0,0 +1,167 @@
+// Package tracker defines the adapter interface for external issue trackers
+// (Jira, Linear, GitHub, GitLab, ...) and a shared engine that syncs any of
+// them with the local store.
+//
+// An adapter only knows how to talk to its tracker: fetch issues, create and
+// update them, map field values and recognize its own external refs. The
+// engine owns everything else: the incremental cursor, three-way conflict
+// detection against the last-synced snapshot, dry-run plans and stats.
+//
+// Adapters register themselves by name, usually from an init function:
+//
+//	func init() {
+//		tracker.Register("jira", newTracker)
+//	}
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// Tracker is an adapter for an external issue tracker.
+type Tracker interface {
+	// Name is the registry name and config namespace, e.g. "jira".
+	Name() string
+
+	// DisplayName is the human-readable tracker name, e.g. "Jira".
+	DisplayName() string
+
+	// FetchIssues returns the tracker's issues, converted to Beads issues.
+	// A non-zero Since limits the result to issues updated at or after it.
+	FetchIssues(ctx context.Context, opts FetchOptions) ([]*RemoteIssue, error)
+
+	// CreateIssue creates a tracker issue from a local issue and returns it
+	// as the tracker now stores it.
+	CreateIssue(ctx context.Context, issue *types.Issue) (*RemoteIssue, error)
+
+	// UpdateIssue overwrites the tracker issue identified by ref with the
+	// local issue's fields and returns it as the tracker now stores it.
+	UpdateIssue(ctx context.Context, ref string, issue *types.Issue) (*RemoteIssue, error)
+
+	// CanonicalRef reports whether an external_ref points at this tracker,
+	// and returns its stable form.
+	CanonicalRef(ref string) (string, bool)
+
+	// Mapper converts field values between Beads and the tracker.
+	Mapper() FieldMapper
+}
+
+// FieldMapper converts status, priority and type values between Beads and a
+// tracker. Tracker values are the tracker's own names ("In Progress", "P1",
+// "Story"); an empty string means the tracker has no value for it.
+type FieldMapper interface {
+	StatusToTracker(status types.Status) string
+	StatusToBeads(status string) types.Status
+	PriorityToTracker(priority int) string
+	PriorityToBeads(priority string) int
+	TypeToTracker(issueType types.IssueType) string
+	TypeToBeads(issueType string) types.IssueType
+}
+
+// FetchOptions filters a FetchIssues call.
+type FetchOptions struct {
+	State string    // "open", "closed" or "all" (empty means "all")
+	Since time.Time // Zero fetches everything
+}
+
+// RemoteIssue is a tracker issue converted to Beads.
+type RemoteIssue struct {
+	// Ref is the canonical external ref, e.g. a browse URL.
+	Ref string
+
+	// Key is the tracker's short identifier, e.g. "PROJ-12" or "#42".
+	Key string
+
+	// Issue holds the converted fields. Its ID is empty and its
+	// ExternalRef is set to Ref.
+	Issue *types.Issue
+
+	// Dependencies are links to other tracker issues.
+	Dependencies []Dependency
+}
+
+// Dependency is a link between two tracker issues, identified by ref.
+type Dependency struct {
+	FromRef string
+	ToRef   string
+	Type    types.DependencyType
+}
+
+// Config is the configuration source adapters are created from.
+// storage.Storage satisfies it.
+type Config interface {
+	GetConfig(ctx context.Context, key string) (string, error)
+	GetAllConfig(ctx context.Context) (map[string]string, error)
+}
+
+// ConfigValue reads a config key, falling back to an environment variable
+// when the key is unset. An empty envVar disables the fallback.
+func ConfigValue(ctx context.Context, cfg Config, key, envVar string) string {
+	if cfg != nil {
+		if value, _ := cfg.GetConfig(ctx, key); value != "" {
+			return value
+		}
+	}
+	if envVar != "" {
+		return os.Getenv(envVar)
+	}
+	return ""
+}
+
+// MappingLoader adapts a Config to the GetAllConfig loaders the tracker
+// packages load their mapping configs from.
+func MappingLoader(ctx context.Context, cfg Config) *ConfigLoader {
+	return &ConfigLoader{ctx: ctx, cfg: cfg}
+}
+
+// ConfigLoader reads all config values from a Config.
+type ConfigLoader struct {
+	ctx context.Context
+	cfg Config
+}
+
+// GetAllConfig returns all config values.
+func (l *ConfigLoader) GetAllConfig() (map[string]string, error) {
+	if l.cfg == nil {
+		return map[string]string{}, nil
+	}
+	return l.cfg.GetAllConfig(l.ctx)
+}
+
+// Factory creates a tracker adapter from configuration. It returns an error
+// if required settings are missing.
+type Factory func(ctx context.Context, cfg Config) (Tracker, error)
+
+// registry holds registered tracker factories
+var registry = make(map[string]Factory)
+
+// Register registers a tracker adapter, replacing any adapter of the same name.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// Trackers returns the names of all registered trackers, sorted.
+func Trackers() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the named tracker adapter.
+func New(ctx context.Context, name string, cfg Config) (Tracker, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tracker %q (available: %s)", name, strings.Join(Trackers(), ", "))
+	}
+	return factory(ctx, cfg)
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Page orders for PageCursor.Order. Ready work cursors use the SortPolicy.
+const (
+	// PageOrderSearch is the SearchIssues order: priority ASC, created_at DESC, id ASC.
+	PageOrderSearch = "search"
+)
+
+// HybridRecentWindow is how recently an issue must have been created to be
+// sorted by priority under SortPolicyHybrid.
+const HybridRecentWindow = 48 * time.Hour
+
+// PageCursor is the decoded form of IssueFilter.Cursor and WorkFilter.Cursor:
+// the sort key and ID of the last issue on the previous page. Queries resume
+// strictly after it (keyset pagination), so pages stay stable when issues
+// are created or closed between requests. Every order ends with id ASC as a
+// tie-breaker, making the key unique.
+//
+// Cursors are opaque to clients; use EncodeCursor and DecodeCursor.
+type PageCursor struct {
+	Order     string    `json:"o"`          // PageOrderSearch or a SortPolicy
+	Priority  int       `json:"p"`          // Sort key: priority
+	CreatedAt time.Time `json:"c"`          // Sort key: created_at
+	ID        string    `json:"i"`          // Tie-breaker
+	Cutoff    time.Time `json:"t,omitzero"` // Hybrid: recent-bucket cutoff, fixed by the first page
+}
+
+// EncodeCursor encodes a cursor as an opaque URL-safe string.
+func EncodeCursor(c PageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor and checks it was issued for order. An empty
+// string decodes to nil (first page).
+func DecodeCursor(s, order string) (*PageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var c PageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.ID == "" {
+		return nil, fmt.Errorf("invalid cursor: missing issue ID")
+	}
+	if c.Order != order {
+		return nil, fmt.Errorf("cursor was issued for %s order, not %s", c.Order, order)
+	}
+	return &c, nil
+}
+
+// EffectiveSortPolicy returns the ready work sort policy, defaulting to hybrid.
+func (f WorkFilter) EffectiveSortPolicy() SortPolicy {
+	if f.SortPolicy == "" {
+		return SortPolicyHybrid
+	}
+	return f.SortPolicy
+}
+
+// HybridCutoff returns the creation time at or after which an issue counts
+// as recent for SortPolicyHybrid: the cutoff stored in the filter's cursor,
+// or HybridRecentWindow before now for a first page. It is truncated to
+// whole seconds to match SQL datetime() comparisons.
+func (f WorkFilter) HybridCutoff() time.Time {
+	if c, err := DecodeCursor(f.Cursor, string(SortPolicyHybrid)); err == nil && c != nil && !c.Cutoff.IsZero() {
+		return c.Cutoff
+	}
+	return time.Now().UTC().Add(-HybridRecentWindow).Truncate(time.Second)
+}
+
+// IsRecent reports whether an issue created at createdAt is in the hybrid
+// sort's recent bucket for cutoff.
+func IsRecent(createdAt, cutoff time.Time) bool {
+	return !createdAt.Truncate(time.Second).Before(cutoff)
+}
+
+// NextCursor returns the cursor for the page after issues, a page returned
+// by SearchIssues with this filter, or "" if it was the last page (fewer
+// than Limit issues, or no limit).
+func (f IssueFilter) NextCursor(issues []*Issue) string {
+	if f.Limit <= 0 || len(issues) < f.Limit {
+		return ""
+	}
+	last := issues[len(issues)-1]
+	return EncodeCursor(PageCursor{
+		Order:     PageOrderSearch,
+		Priority:  last.Priority,
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+	})
+}
+
+// NextCursor returns the cursor for the page after issues, a page returned
+// by GetReadyWork with this filter, or "" if it was the last page.
+func (f WorkFilter) NextCursor(issues []*Issue) string {
+	if f.Limit <= 0 || len(issues) < f.Limit {
+		return ""
+	}
+	last := issues[len(issues)-1]
+	c := PageCursor{
+		Order:     string(f.EffectiveSortPolicy()),
+		Priority:  last.Priority,
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+	}
+	if c.Order == string(SortPolicyHybrid) {
+		c.Cutoff = f.HybridCutoff()
+	}
+	return EncodeCursor(c)
+}
+
+// SearchOrderLess reports whether a sorts before b in PageOrderSearch.
+func SearchOrderLess(a, b *Issue) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// ReadyOrderLess reports whether a sorts before b in a ready work order.
+// cutoff is only used by SortPolicyHybrid.
+func ReadyOrderLess(policy SortPolicy, cutoff time.Time, a, b *Issue) bool {
+	switch policy {
+	case SortPolicyOldest:
+	case SortPolicyPriority:
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+	default:
+		aRecent, bRecent := IsRecent(a.CreatedAt, cutoff), IsRecent(b.CreatedAt, cutoff)
+		if aRecent != bRecent {
+			return aRecent // Recent first
+		}
+		if aRecent && a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// After reports whether issue sorts strictly after the cursor, for
+// backends that paginate in memory.
+func (c *PageCursor) After(issue *Issue) bool {
+	key := &Issue{ID: c.ID, Priority: c.Priority, CreatedAt: c.CreatedAt}
+	if c.Order == PageOrderSearch {
+		return SearchOrderLess(key, issue)
+	}
+	return ReadyOrderLess(SortPolicy(c.Order), c.Cutoff, key, issue)
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 4, 5, 6, 7, 891, time.FixedZone("CET", 3600))
+	issues := []*Issue{{ID: "bd-1"}, {ID: "bd-2", Priority: 3, CreatedAt: created}}
+
+	filter := IssueFilter{Limit: 2}
+	encoded := filter.NextCursor(issues)
+	c, err := DecodeCursor(encoded, PageOrderSearch)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if c.ID != "bd-2" || c.Priority != 3 || !c.CreatedAt.Equal(created) || !c.Cutoff.IsZero() {
+		t.Errorf("cursor = %+v", c)
+	}
+	if _, err := DecodeCursor(encoded, string(SortPolicyHybrid)); err == nil {
+		t.Error("expected an error decoding a search cursor as hybrid")
+	}
+
+	if got := (IssueFilter{Limit: 3}).NextCursor(issues); got != "" {
+		t.Errorf("short page cursor = %q, want none", got)
+	}
+	if got := (IssueFilter{}).NextCursor(issues); got != "" {
+		t.Errorf("unlimited cursor = %q, want none", got)
+	}
+}
+
+func TestHybridCursorKeepsCutoff(t *testing.T) {
+	issues := []*Issue{{ID: "bd-1", CreatedAt: time.Now()}}
+	first := WorkFilter{Limit: 1}
+	next := WorkFilter{Limit: 1, Cursor: first.NextCursor(issues)}
+
+	cutoff := next.HybridCutoff()
+	if cutoff.IsZero() || cutoff.Nanosecond() != 0 {
+		t.Fatalf("cutoff = %v", cutoff)
+	}
+	// Later pages reuse the first page's cutoff
+	if got := (WorkFilter{Limit: 1, Cursor: next.NextCursor(issues)}).HybridCutoff(); !got.Equal(cutoff) {
+		t.Errorf("cutoff drifted: %v != %v", got, cutoff)
+	}
+}
@@ -724,6 +724,17 @@ type IssueWithCounts struct {
 	CommentCount    int `json:"comment_count"`
 }
 
+// SearchHit is a full-text search match with its relevance score and
+// per-field highlights. Rank is the BM25 score (lower is more relevant).
+// Highlights maps a field name (title, description, design,
+// acceptance_criteria, notes, comments) to a snippet with matched terms
+// wrapped in <mark></mark>; fields without a match are omitted.
+type SearchHit struct {
+	*Issue
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
 // IssueDetails extends Issue with labels, dependencies, dependents, and comments.
 // Used for JSON serialization in fbd show and RPC responses.
 type IssueDetails struct {
@@ -967,6 +978,7 @@ type IssueFilter struct {
 	IDPrefix     string   // Filter by ID prefix (e.g., "bd-" to match "bd-abc123")
 	SpecIDPrefix string   // Filter by spec_id prefix
 	Limit        int
+	Cursor       string // Resume after this page cursor (see PageCursor and NextCursor)
 
 	// Pattern matching
 	TitleContains       string
@@ -1069,6 +1081,7 @@ type WorkFilter struct {
 	LabelPattern string   // Glob pattern for label matching (e.g., "tech-*")
 	LabelRegex   string   // Regex pattern for label matching (e.g., "tech-(debt|legacy)")
 	Limit        int
+	Cursor       string // Resume after this page cursor (see PageCursor and NextCursor)
 	SortPolicy   SortPolicy
 
 	// Parent filtering: filter to descendants of a bead/epic (recursive)
@@ -0,0 +1,237 @@
+// Package views provides named, saved queries for fbd.
+// Views live in .beads/views.yaml so they are shared through git, giving the
+// whole team the same triage views.
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/fastbeads/internal/query"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the views file inside the .beads directory.
+const FileName = "views.yaml"
+
+// Output formats a view can request.
+const (
+	FormatCompact = "compact" // One line per issue (default)
+	FormatLong    = "long"    // Multi-line details per issue
+	FormatTable   = "table"   // Aligned columns, see View.Columns
+	FormatJSON    = "json"    // Same JSON as fbd list --json
+)
+
+// ValidFormats lists the accepted View.Format values.
+var ValidFormats = []string{FormatCompact, FormatLong, FormatTable, FormatJSON}
+
+// ValidColumns lists the accepted View.Columns values for table output.
+var ValidColumns = []string{
+	"id", "title", "status", "priority", "type", "assignee", "owner",
+	"labels", "created", "updated", "closed", "due",
+}
+
+// DefaultColumns are used for table output when a view sets no columns.
+var DefaultColumns = []string{"id", "priority", "type", "status", "assignee", "title"}
+
+// ValidSorts lists the accepted View.Sort values (same as fbd list --sort).
+var ValidSorts = []string{"priority", "created", "updated", "closed", "status", "id", "title", "type", "assignee"}
+
+// View is a saved query expression plus how to display its results.
+type View struct {
+	Name        string   `yaml:"-"`                     // Set from the map key
+	Description string   `yaml:"description,omitempty"` // Shown in fbd view list
+	Query       string   `yaml:"query"`                 // Query language expression (see fbd query)
+	Sort        string   `yaml:"sort,omitempty"`        // Sort field
+	Reverse     bool     `yaml:"reverse,omitempty"`     // Reverse sort order
+	Columns     []string `yaml:"columns,omitempty"`     // Columns for table output
+	Format      string   `yaml:"format,omitempty"`      // compact, long, table or json
+	Limit       int      `yaml:"limit,omitempty"`       // Max results (0 = unlimited)
+	All         bool     `yaml:"all,omitempty"`         // Include closed issues
+}
+
+// file is the on-disk layout of views.yaml.
+type file struct {
+	Views map[string]View `yaml:"views"`
+}
+
+// NormalizeName lowercases and trims a view name.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Validate checks that a view is well formed: the name is usable, the query
+// parses, and format, sort and columns are known values.
+func (v *View) Validate() error {
+	if v.Name == "" {
+		return fmt.Errorf("view name is required")
+	}
+	if strings.ContainsAny(v.Name, " \t\n/\\:") {
+		return fmt.Errorf("invalid view name %q: must not contain whitespace, slashes or colons", v.Name)
+	}
+	if strings.TrimSpace(v.Query) == "" {
+		return fmt.Errorf("view %s: query is required", v.Name)
+	}
+	if _, err := query.Parse(v.Query); err != nil {
+		return fmt.Errorf("view %s: invalid query: %w", v.Name, err)
+	}
+	if v.Format != "" && !contains(ValidFormats, v.Format) {
+		return fmt.Errorf("view %s: invalid format %q (valid: %s)", v.Name, v.Format, strings.Join(ValidFormats, ", "))
+	}
+	if v.Sort != "" && !contains(ValidSorts, v.Sort) {
+		return fmt.Errorf("view %s: invalid sort %q (valid: %s)", v.Name, v.Sort, strings.Join(ValidSorts, ", "))
+	}
+	for _, col := range v.Columns {
+		if !contains(ValidColumns, col) {
+			return fmt.Errorf("view %s: invalid column %q (valid: %s)", v.Name, col, strings.Join(ValidColumns, ", "))
+		}
+	}
+	if v.Limit < 0 {
+		return fmt.Errorf("view %s: limit must be >= 0", v.Name)
+	}
+	return nil
+}
+
+// EffectiveFormat returns the view's format, defaulting to compact.
+func (v *View) EffectiveFormat() string {
+	if v.Format == "" {
+		return FormatCompact
+	}
+	return v.Format
+}
+
+// EffectiveColumns returns the view's columns, defaulting to DefaultColumns.
+func (v *View) EffectiveColumns() []string {
+	if len(v.Columns) == 0 {
+		return DefaultColumns
+	}
+	return v.Columns
+}
+
+// LoadViews loads views from .beads/views.yaml if it exists.
+func LoadViews(beadsDir string) (map[string]View, error) {
+	f, err := readFile(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	for name, view := range f.Views {
+		view.Name = name
+		f.Views[name] = view
+	}
+	return f.Views, nil
+}
+
+// GetView looks up a view by name.
+func GetView(beadsDir, name string) (*View, error) {
+	name = NormalizeName(name)
+
+	views, err := LoadViews(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	view, ok := views[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown view: %s", name)
+	}
+	return &view, nil
+}
+
+// SaveView validates a view and adds or updates it in .beads/views.yaml.
+// It reports whether an existing view was replaced.
+func SaveView(beadsDir string, view View) (bool, error) {
+	view.Name = NormalizeName(view.Name)
+	if err := view.Validate(); err != nil {
+		return false, err
+	}
+
+	f, err := readFile(beadsDir)
+	if err != nil {
+		return false, err
+	}
+	if f.Views == nil {
+		f.Views = make(map[string]View)
+	}
+	_, existed := f.Views[view.Name]
+	f.Views[view.Name] = view
+
+	return existed, writeFile(beadsDir, f)
+}
+
+// DeleteView removes a view from .beads/views.yaml.
+func DeleteView(beadsDir, name string) error {
+	name = NormalizeName(name)
+
+	f, err := readFile(beadsDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.Views[name]; !ok {
+		return fmt.Errorf("unknown view: %s", name)
+	}
+	delete(f.Views, name)
+
+	return writeFile(beadsDir, f)
+}
+
+// ListViewNames returns a sorted list of all view names.
+func ListViewNames(beadsDir string) ([]string, error) {
+	views, err := LoadViews(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(views))
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func readFile(beadsDir string) (*file, error) {
+	path := filepath.Join(beadsDir, FileName)
+	data, err := os.ReadFile(path) // #nosec G304 -- path is constructed from validated beadsDir
+	if os.IsNotExist(err) {
+		return &file{}, nil // No views yet, that's fine
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", FileName, err)
+	}
+
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", FileName, err)
+	}
+	return &f, nil
+}
+
+func writeFile(beadsDir string, f *file) error {
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		return fmt.Errorf("create beads dir: %w", err)
+	}
+
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", FileName, err)
+	}
+
+	path := filepath.Join(beadsDir, FileName)
+	// #nosec G306 -- views.yaml is committed to git and meant to be shared
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", FileName, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
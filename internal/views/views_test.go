@@ -0,0 +1,153 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndGetView(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	view := View{
+		Name:    "Triage",
+		Query:   "status=open AND assignee=none",
+		Sort:    "priority",
+		Columns: []string{"id", "priority", "title"},
+		Format:  FormatTable,
+		Limit:   20,
+	}
+	replaced, err := SaveView(tmpDir, view)
+	if err != nil {
+		t.Fatalf("SaveView: %v", err)
+	}
+	if replaced {
+		t.Error("first save should not report a replaced view")
+	}
+
+	got, err := GetView(tmpDir, "triage")
+	if err != nil {
+		t.Fatalf("GetView: %v", err)
+	}
+	if got.Name != "triage" {
+		t.Errorf("got Name=%q, want normalized 'triage'", got.Name)
+	}
+	if got.Query != view.Query || got.Sort != "priority" || got.Limit != 20 {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if strings.Join(got.Columns, ",") != "id,priority,title" {
+		t.Errorf("got Columns=%v", got.Columns)
+	}
+
+	// Saving again replaces
+	view.Query = "status=blocked"
+	replaced, err = SaveView(tmpDir, view)
+	if err != nil {
+		t.Fatalf("SaveView (update): %v", err)
+	}
+	if !replaced {
+		t.Error("second save should report a replaced view")
+	}
+	got, _ = GetView(tmpDir, "triage")
+	if got.Query != "status=blocked" {
+		t.Errorf("got Query=%q after update", got.Query)
+	}
+
+	// File is human-readable YAML keyed by view name
+	data, err := os.ReadFile(filepath.Join(tmpDir, FileName))
+	if err != nil {
+		t.Fatalf("read views file: %v", err)
+	}
+	if !strings.Contains(string(data), "triage:") {
+		t.Errorf("expected views.yaml to contain 'triage:', got:\n%s", data)
+	}
+}
+
+func TestLoadViewsMissingFile(t *testing.T) {
+	views, err := LoadViews(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadViews: %v", err)
+	}
+	if len(views) != 0 {
+		t.Errorf("expected no views, got %d", len(views))
+	}
+}
+
+func TestLoadViewsHandWritten(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `views:
+  my-bugs:
+    description: Bugs assigned to me
+    query: type=bug AND assignee=alice
+    format: long
+  stale:
+    query: updated<30d
+    sort: updated
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write views file: %v", err)
+	}
+
+	names, err := ListViewNames(tmpDir)
+	if err != nil {
+		t.Fatalf("ListViewNames: %v", err)
+	}
+	if strings.Join(names, ",") != "my-bugs,stale" {
+		t.Errorf("got names %v, want [my-bugs stale]", names)
+	}
+
+	view, err := GetView(tmpDir, "stale")
+	if err != nil {
+		t.Fatalf("GetView: %v", err)
+	}
+	if view.EffectiveFormat() != FormatCompact {
+		t.Errorf("got format %q, want default compact", view.EffectiveFormat())
+	}
+	if strings.Join(view.EffectiveColumns(), ",") != strings.Join(DefaultColumns, ",") {
+		t.Errorf("got columns %v, want defaults", view.EffectiveColumns())
+	}
+}
+
+func TestDeleteView(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := SaveView(tmpDir, View{Name: "a", Query: "status=open"}); err != nil {
+		t.Fatalf("SaveView: %v", err)
+	}
+	if err := DeleteView(tmpDir, "a"); err != nil {
+		t.Fatalf("DeleteView: %v", err)
+	}
+	if _, err := GetView(tmpDir, "a"); err == nil {
+		t.Error("GetView after delete should return error")
+	}
+	if err := DeleteView(tmpDir, "a"); err == nil {
+		t.Error("deleting a missing view should return error")
+	}
+}
+
+func TestViewValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		view View
+	}{
+		{"missing name", View{Query: "status=open"}},
+		{"name with space", View{Name: "my view", Query: "status=open"}},
+		{"missing query", View{Name: "v"}},
+		{"bad query", View{Name: "v", Query: "status=open AND"}},
+		{"bad format", View{Name: "v", Query: "status=open", Format: "xml"}},
+		{"bad sort", View{Name: "v", Query: "status=open", Sort: "size"}},
+		{"bad column", View{Name: "v", Query: "status=open", Columns: []string{"id", "size"}}},
+		{"negative limit", View{Name: "v", Query: "status=open", Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.view.Validate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+
+	if _, err := SaveView(t.TempDir(), View{Name: "v", Query: "status=open AND"}); err == nil {
+		t.Error("SaveView should reject an invalid view")
+	}
+}
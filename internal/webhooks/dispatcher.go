@@ -0,0 +1,428 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/steveyegge/fastbeads/internal/query"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+// Request headers set on every delivery.
+const (
+	EventHeader     = "X-Fbd-Event"         // Event type
+	DeliveryHeader  = "X-Fbd-Delivery"      // Payload.Delivery
+	SignatureHeader = "X-Fbd-Signature-256" // "sha256=" + hex HMAC-SHA256 of the body, if the endpoint has a secret
+)
+
+// DefaultPollInterval is how often the daemon checks the outbox.
+const DefaultPollInterval = 5 * time.Second
+
+const (
+	requestTimeout    = 10 * time.Second
+	deliveryMaxTime   = 2 * time.Minute // Total time spent retrying one event
+	deliveryMaxErrors = 6               // Attempts per event before dead-lettering
+)
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Report summarizes a delivery run.
+type Report struct {
+	Delivered    int `json:"delivered"`
+	Skipped      int `json:"skipped"`       // Filtered out by event type or filter
+	DeadLettered int `json:"dead_lettered"` // Failed after retries
+}
+
+func (r *Report) add(o Report) {
+	r.Delivered += o.Delivered
+	r.Skipped += o.Skipped
+	r.DeadLettered += o.DeadLettered
+}
+
+// Dispatcher delivers outbox events to the configured endpoints.
+type Dispatcher struct {
+	store storage.Storage
+
+	// Client sends the requests.
+	Client *http.Client
+	// NewBackOff returns the retry policy for one delivery. BackOff
+	// implementations are stateful, so it must return a fresh instance.
+	NewBackOff func() backoff.BackOff
+	// UserAgent is sent with every request.
+	UserAgent string
+
+	deadMu sync.Mutex // Serializes dead-letter read-modify-write
+}
+
+// NewDispatcher creates a dispatcher with the default HTTP client and retry
+// policy (exponential backoff, up to six attempts within two minutes).
+func NewDispatcher(store storage.Storage) *Dispatcher {
+	return &Dispatcher{
+		store:      store,
+		Client:     &http.Client{Timeout: requestTimeout},
+		NewBackOff: newDeliveryBackOff,
+		UserAgent:  "fbd-webhooks",
+	}
+}
+
+func newDeliveryBackOff() backoff.BackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = deliveryMaxTime
+	return backoff.WithMaxRetries(bo, deliveryMaxErrors-1)
+}
+
+// Run delivers pending events every interval until ctx is cancelled.
+// Errors are passed to logf and delivery continues on the next tick.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration, logf func(format string, args ...interface{})) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := d.DeliverPending(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logf("webhooks: %v", err)
+		} else if report.DeadLettered > 0 {
+			logf("webhooks: %d event(s) dead-lettered; see 'fbd webhooks list --dead-letters'", report.DeadLettered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverPending offers every event after each endpoint's cursor to that
+// endpoint. Endpoints are served concurrently; within an endpoint, events
+// are delivered in order. The cursor advances past each event once it is
+// delivered, skipped or dead-lettered.
+func (d *Dispatcher) DeliverPending(ctx context.Context) (*Report, error) {
+	endpoints, err := LoadEndpoints(ctx, d.store)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		report Report
+		errs   []error
+		wg     sync.WaitGroup
+	)
+	for _, ep := range endpoints {
+		wg.Add(1)
+		go func(ep *Endpoint) {
+			defer wg.Done()
+			r, err := d.deliverEndpoint(ctx, ep)
+			mu.Lock()
+			defer mu.Unlock()
+			report.add(r)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ep.Name, err))
+			}
+		}(ep)
+	}
+	wg.Wait()
+	return &report, errors.Join(errs...)
+}
+
+func (d *Dispatcher) deliverEndpoint(ctx context.Context, ep *Endpoint) (Report, error) {
+	var report Report
+
+	var filter query.Node
+	if ep.Filter != "" {
+		var err error
+		if filter, err = compileFilter(ep.Filter); err != nil {
+			return report, err
+		}
+	}
+
+	cursor, err := Cursor(ctx, d.store, ep.Name)
+	if err != nil {
+		return report, err
+	}
+	events, err := d.store.GetAllEventsSince(ctx, cursor)
+	if err != nil {
+		return report, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		issue, err := d.issueFor(ctx, event.IssueID, filter != nil)
+		if err != nil {
+			return report, err
+		}
+		if !ep.wants(string(event.EventType)) || !matches(ctx, d.store, filter, issue) {
+			report.Skipped++
+		} else {
+			deliveryID := fmt.Sprintf("%s:%d", ep.Name, event.ID)
+			body, err := json.Marshal(&Payload{Delivery: deliveryID, Event: event, Issue: issue})
+			if err != nil {
+				return report, err
+			}
+			attempts, sendErr := d.send(ctx, ep, string(event.EventType), deliveryID, body, d.NewBackOff())
+			if ctx.Err() != nil {
+				return report, ctx.Err() // Leave the cursor; retry after restart
+			}
+			if sendErr != nil {
+				if err := d.addDeadLetter(ctx, ep, event, body, attempts, sendErr); err != nil {
+					return report, err
+				}
+				report.DeadLettered++
+			} else {
+				report.Delivered++
+			}
+		}
+
+		if err := setCursor(ctx, d.store, ep.Name, event.ID); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// issueFor loads the event's issue for the payload. With withRelations,
+// labels and dependencies are loaded too, for filter matching.
+func (d *Dispatcher) issueFor(ctx context.Context, issueID string, withRelations bool) (*types.Issue, error) {
+	if issueID == "" {
+		return nil, nil
+	}
+	issue, err := d.store.GetIssue(ctx, issueID)
+	if err != nil || issue == nil {
+		return nil, nil // Deleted since the event was recorded
+	}
+	if issue.Labels, err = d.store.GetLabels(ctx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to load labels for %s: %w", issueID, err)
+	}
+	if withRelations {
+		if issue.Dependencies, err = d.store.GetDependencyRecords(ctx, issueID); err != nil {
+			return nil, fmt.Errorf("failed to load dependencies for %s: %w", issueID, err)
+		}
+	}
+	return issue, nil
+}
+
+// matches evaluates filter against issue. Events whose issue no longer
+// exists, or is a tombstone, never match a filter.
+func matches(ctx context.Context, store storage.Storage, filter query.Node, issue *types.Issue) bool {
+	if filter == nil {
+		return true
+	}
+	if issue == nil || issue.Status == types.StatusTombstone {
+		return false
+	}
+	// A fresh evaluator per event, so relative times and graph operators
+	// see the current state
+	match, err := query.NewEvaluator(time.Now()).WithGraph(ctx, store).Matcher(filter)
+	if err != nil {
+		return false
+	}
+	return match(issue)
+}
+
+// statusError is a non-2xx response from an endpoint.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("endpoint returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("endpoint returned %d: %s", e.StatusCode, e.Body)
+}
+
+// send POSTs body to the endpoint, retrying according to bo. Client errors
+// other than 408 and 429 are not retried. It returns the number of attempts.
+func (d *Dispatcher) send(ctx context.Context, ep *Endpoint, eventType, deliveryID string, body []byte, bo backoff.BackOff) (int, error) {
+	attempts := 0
+	err := backoff.Retry(func() error {
+		attempts++
+		err := d.post(ctx, ep, eventType, deliveryID, body)
+		var se *statusError
+		if errors.As(err, &se) && se.StatusCode >= 400 && se.StatusCode < 500 &&
+			se.StatusCode != http.StatusRequestTimeout && se.StatusCode != http.StatusTooManyRequests {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithContext(bo, ctx))
+	return attempts, err
+}
+
+func (d *Dispatcher) post(ctx context.Context, ep *Endpoint, eventType, deliveryID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", d.UserAgent)
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	if ep.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(ep.Secret, body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	return nil
+}
+
+func (d *Dispatcher) addDeadLetter(ctx context.Context, ep *Endpoint, event *types.Event, body []byte, attempts int, sendErr error) error {
+	d.deadMu.Lock()
+	defer d.deadMu.Unlock()
+
+	letters, err := DeadLetters(ctx, d.store)
+	if err != nil {
+		return err
+	}
+	var nextID int64 = 1
+	if len(letters) > 0 {
+		nextID = letters[len(letters)-1].ID + 1
+	}
+	letters = append(letters, &DeadLetter{
+		ID:        nextID,
+		Endpoint:  ep.Name,
+		EventID:   event.ID,
+		EventType: string(event.EventType),
+		IssueID:   event.IssueID,
+		Attempts:  attempts,
+		Error:     sendErr.Error(),
+		FailedAt:  time.Now().UTC(),
+		Payload:   body,
+	})
+	return saveDeadLetters(ctx, d.store, letters)
+}
+
+// Test sends a single webhook.test event to the endpoint, without retries.
+func (d *Dispatcher) Test(ctx context.Context, ep *Endpoint) error {
+	deliveryID := fmt.Sprintf("%s:test-%d", ep.Name, time.Now().Unix())
+	body, err := json.Marshal(&Payload{
+		Delivery: deliveryID,
+		Event: &types.Event{
+			EventType: types.EventType(TestEventType),
+			Actor:     "fbd",
+			CreatedAt: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = d.send(ctx, ep, TestEventType, deliveryID, body, &backoff.StopBackOff{})
+	return err
+}
+
+// ReplayResult is the outcome of replaying one dead letter.
+type ReplayResult struct {
+	ID       int64  `json:"id"`
+	Endpoint string `json:"endpoint"`
+	EventID  int64  `json:"event_id"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Replay re-sends dead letters with their original payloads. With no IDs,
+// every dead letter is replayed (only those for endpoint, if set). Letters
+// that are delivered are removed from the list; the rest stay, with their
+// attempt count and error updated.
+func (d *Dispatcher) Replay(ctx context.Context, ids []int64, endpoint string) ([]ReplayResult, error) {
+	d.deadMu.Lock()
+	defer d.deadMu.Unlock()
+
+	letters, err := DeadLetters(ctx, d.store)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := LoadEndpoints(ctx, d.store)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Endpoint, len(endpoints))
+	for _, ep := range endpoints {
+		byName[ep.Name] = ep
+	}
+
+	selected := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+	if len(ids) > 0 {
+		existing := make(map[int64]bool, len(letters))
+		for _, letter := range letters {
+			existing[letter.ID] = true
+		}
+		for _, id := range ids {
+			if !existing[id] {
+				return nil, fmt.Errorf("dead letter %d not found", id)
+			}
+		}
+	}
+
+	var results []ReplayResult
+	var kept []*DeadLetter
+	for i, letter := range letters {
+		if ctx.Err() != nil {
+			// Keep whatever wasn't replayed, but record what was
+			kept = append(kept, letters[i:]...)
+			break
+		}
+		if (len(ids) > 0 && !selected[letter.ID]) || (endpoint != "" && letter.Endpoint != endpoint) {
+			kept = append(kept, letter)
+			continue
+		}
+		result := ReplayResult{ID: letter.ID, Endpoint: letter.Endpoint, EventID: letter.EventID}
+
+		ep := byName[letter.Endpoint]
+		if ep == nil {
+			result.Error = fmt.Sprintf("webhook %q not found", letter.Endpoint)
+			kept = append(kept, letter)
+			results = append(results, result)
+			continue
+		}
+		deliveryID := fmt.Sprintf("%s:%d", letter.Endpoint, letter.EventID)
+		attempts, sendErr := d.send(ctx, ep, letter.EventType, deliveryID, letter.Payload, d.NewBackOff())
+		if sendErr != nil {
+			result.Error = sendErr.Error()
+			letter.Attempts += attempts
+			letter.Error = sendErr.Error()
+			letter.FailedAt = time.Now().UTC()
+			kept = append(kept, letter)
+		}
+		results = append(results, result)
+	}
+
+	// Save even if cancelled, with a context that still works
+	if err := saveDeadLetters(context.WithoutCancel(ctx), d.store, kept); err != nil {
+		return results, err
+	}
+	return results, ctx.Err()
+}
@@ -0,0 +1,282 @@
+// Package webhooks delivers issue events to HTTP endpoints.
+//
+// The events table is the outbox. Each endpoint keeps a high-water mark of
+// the last event it was offered, so deliveries survive restarts and every
+// event is sent at least once. Events that still fail after retries are moved
+// to a dead-letter list, from which they can be replayed.
+//
+// Endpoints are stored in the database config table under webhooks.<name>.*
+// (url, secret, filter, events); delivery state is stored as metadata.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/fastbeads/internal/query"
+	"github.com/steveyegge/fastbeads/internal/storage"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+const (
+	configPrefix    = "webhooks."
+	cursorKeyPrefix = "webhook_last_event_id:"
+	deadLettersKey  = "webhook_dead_letters"
+
+	// maxDeadLetters bounds the dead-letter list; the oldest entries are
+	// dropped first.
+	maxDeadLetters = 500
+)
+
+// TestEventType is the event type of the payload sent by 'fbd webhooks test'.
+const TestEventType = "webhook.test"
+
+// Endpoint is a configured webhook.
+type Endpoint struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Secret string   `json:"-"`                // HMAC-SHA256 signing key; empty means unsigned
+	Filter string   `json:"filter,omitempty"` // Query expression matched against the event's issue
+	Events []string `json:"events,omitempty"` // Event types to deliver; empty means all
+}
+
+// Payload is the JSON body POSTed to an endpoint.
+type Payload struct {
+	Delivery string       `json:"delivery"` // <endpoint>:<event id>, stable across retries and replays
+	Event    *types.Event `json:"event"`
+	Issue    *types.Issue `json:"issue,omitempty"` // The issue as of delivery, if it still exists
+}
+
+// DeadLetter is an event that could not be delivered.
+type DeadLetter struct {
+	ID        int64           `json:"id"`
+	Endpoint  string          `json:"endpoint"`
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	IssueID   string          `json:"issue_id,omitempty"`
+	Attempts  int             `json:"attempts"`
+	Error     string          `json:"error"`
+	FailedAt  time.Time       `json:"failed_at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validate checks the endpoint's name, URL and filter.
+func (e *Endpoint) Validate() error {
+	if !validName.MatchString(e.Name) {
+		return fmt.Errorf("invalid webhook name %q: use lowercase letters, digits, '-' and '_'", e.Name)
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: must be an http or https URL", e.URL)
+	}
+	if e.Filter != "" {
+		if _, err := compileFilter(e.Filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wants reports whether the endpoint subscribes to an event type.
+func (e *Endpoint) wants(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// compileFilter parses a query expression and checks that it evaluates.
+func compileFilter(expr string) (query.Node, error) {
+	node, err := query.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if _, err := query.NewEvaluator(time.Now()).Matcher(node); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return node, nil
+}
+
+// LoadEndpoints returns the configured endpoints, sorted by name.
+func LoadEndpoints(ctx context.Context, store storage.Storage) ([]*Endpoint, error) {
+	all, err := store.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	byName := make(map[string]*Endpoint)
+	for key, value := range all {
+		rest, ok := strings.CutPrefix(key, configPrefix)
+		if !ok {
+			continue
+		}
+		name, field, ok := strings.Cut(rest, ".")
+		if !ok {
+			continue
+		}
+		ep := byName[name]
+		if ep == nil {
+			ep = &Endpoint{Name: name}
+			byName[name] = ep
+		}
+		switch field {
+		case "url":
+			ep.URL = value
+		case "secret":
+			ep.Secret = value
+		case "filter":
+			ep.Filter = value
+		case "events":
+			ep.Events = splitList(value)
+		}
+	}
+
+	endpoints := make([]*Endpoint, 0, len(byName))
+	for _, ep := range byName {
+		if ep.URL != "" { // Ignore stray keys without a URL
+			endpoints = append(endpoints, ep)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Name < endpoints[j].Name })
+	return endpoints, nil
+}
+
+// GetEndpoint returns the named endpoint, or an error if it isn't configured.
+func GetEndpoint(ctx context.Context, store storage.Storage, name string) (*Endpoint, error) {
+	endpoints, err := LoadEndpoints(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	for _, ep := range endpoints {
+		if ep.Name == name {
+			return ep, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook %q not found", name)
+}
+
+// SaveEndpoint validates and stores an endpoint, replacing any endpoint of
+// the same name. A new endpoint starts after the newest existing event, so
+// it only receives events recorded from now on.
+func SaveEndpoint(ctx context.Context, store storage.Storage, ep *Endpoint) error {
+	if err := ep.Validate(); err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"url":    ep.URL,
+		"secret": ep.Secret,
+		"filter": ep.Filter,
+		"events": strings.Join(ep.Events, ","),
+	}
+	for field, value := range fields {
+		key := configPrefix + ep.Name + "." + field
+		var err error
+		if value == "" {
+			err = store.DeleteConfig(ctx, key)
+		} else {
+			err = store.SetConfig(ctx, key, value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save %s: %w", key, err)
+		}
+	}
+
+	if current, err := store.GetMetadata(ctx, cursorKeyPrefix+ep.Name); err == nil && current != "" {
+		return nil
+	}
+	latest, err := store.GetLastEventID(ctx, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+	return setCursor(ctx, store, ep.Name, latest)
+}
+
+// RemoveEndpoint deletes an endpoint and its delivery cursor. Its dead
+// letters are kept until replayed or cleared.
+func RemoveEndpoint(ctx context.Context, store storage.Storage, name string) error {
+	if _, err := GetEndpoint(ctx, store, name); err != nil {
+		return err
+	}
+	for _, field := range []string{"url", "secret", "filter", "events"} {
+		if err := store.DeleteConfig(ctx, configPrefix+name+"."+field); err != nil {
+			return fmt.Errorf("failed to delete webhook %s: %w", name, err)
+		}
+	}
+	return store.SetMetadata(ctx, cursorKeyPrefix+name, "")
+}
+
+// Cursor returns the ID of the last event offered to the endpoint.
+func Cursor(ctx context.Context, store storage.Storage, name string) (int64, error) {
+	value, err := store.GetMetadata(ctx, cursorKeyPrefix+name)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s%s metadata %q: %w", cursorKeyPrefix, name, value, err)
+	}
+	return id, nil
+}
+
+func setCursor(ctx context.Context, store storage.Storage, name string, id int64) error {
+	if err := store.SetMetadata(ctx, cursorKeyPrefix+name, strconv.FormatInt(id, 10)); err != nil {
+		return fmt.Errorf("failed to update delivery cursor for %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeadLetters returns the dead-letter list, oldest first.
+func DeadLetters(ctx context.Context, store storage.Storage) ([]*DeadLetter, error) {
+	value, err := store.GetMetadata(ctx, deadLettersKey)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var letters []*DeadLetter
+	if err := json.Unmarshal([]byte(value), &letters); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %w", deadLettersKey, err)
+	}
+	return letters, nil
+}
+
+func saveDeadLetters(ctx context.Context, store storage.Storage, letters []*DeadLetter) error {
+	if len(letters) > maxDeadLetters {
+		letters = letters[len(letters)-maxDeadLetters:]
+	}
+	value := ""
+	if len(letters) > 0 {
+		data, err := json.Marshal(letters)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	if err := store.SetMetadata(ctx, deadLettersKey, value); err != nil {
+		return fmt.Errorf("failed to save dead letters: %w", err)
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
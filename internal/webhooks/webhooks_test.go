@@ -0,0 +1,272 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/steveyegge/fastbeads/internal/storage/sqlite"
+	"github.com/steveyegge/fastbeads/internal/types"
+)
+
+func newTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	store, err := sqlite.New(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "bd"); err != nil {
+		t.Fatalf("failed to set issue_prefix: %v", err)
+	}
+	return store
+}
+
+func createIssue(t *testing.T, store *sqlite.SQLiteStorage, id string, priority int) {
+	t.Helper()
+	issue := &types.Issue{ID: id, Title: "Issue " + id, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("failed to create %s: %v", id, err)
+	}
+}
+
+// receiver is an httptest stand-in for a webhook consumer.
+type receiver struct {
+	mu       sync.Mutex
+	payloads []Payload
+	headers  []http.Header
+	bodies   [][]byte
+	status   int // Response status; 0 means 200
+	srv      *httptest.Server
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.status != 0 {
+			w.WriteHeader(r.status)
+			return
+		}
+		var p Payload
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Errorf("bad payload: %v", err)
+		}
+		r.payloads = append(r.payloads, p)
+		r.headers = append(r.headers, req.Header.Clone())
+		r.bodies = append(r.bodies, body)
+	}))
+	t.Cleanup(r.srv.Close)
+	return r
+}
+
+func (r *receiver) setStatus(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = code
+}
+
+func testDispatcher(store *sqlite.SQLiteStorage) *Dispatcher {
+	d := NewDispatcher(store)
+	d.NewBackOff = func() backoff.BackOff { return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2) }
+	return d
+}
+
+func TestSaveAndLoadEndpoints(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ep := &Endpoint{Name: "ci", URL: "https://example.com/hook", Secret: "s3cret", Filter: "priority<=1", Events: []string{"created", "closed"}}
+	if err := SaveEndpoint(ctx, store, ep); err != nil {
+		t.Fatalf("SaveEndpoint: %v", err)
+	}
+	if err := SaveEndpoint(ctx, store, &Endpoint{Name: "audit", URL: "http://localhost:9000"}); err != nil {
+		t.Fatalf("SaveEndpoint: %v", err)
+	}
+
+	endpoints, err := LoadEndpoints(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadEndpoints: %v", err)
+	}
+	if len(endpoints) != 2 || endpoints[0].Name != "audit" || endpoints[1].Name != "ci" {
+		t.Fatalf("unexpected endpoints: %+v", endpoints)
+	}
+	got := endpoints[1]
+	if got.URL != ep.URL || got.Secret != "s3cret" || got.Filter != "priority<=1" || strings.Join(got.Events, ",") != "created,closed" {
+		t.Errorf("endpoint did not round-trip: %+v", got)
+	}
+
+	if err := RemoveEndpoint(ctx, store, "ci"); err != nil {
+		t.Fatalf("RemoveEndpoint: %v", err)
+	}
+	if _, err := GetEndpoint(ctx, store, "ci"); err == nil {
+		t.Error("expected removed endpoint to be gone")
+	}
+}
+
+func TestEndpointValidate(t *testing.T) {
+	tests := []struct {
+		ep   Endpoint
+		want string
+	}{
+		{Endpoint{Name: "Bad Name", URL: "https://x"}, "invalid webhook name"},
+		{Endpoint{Name: "ok", URL: "ftp://x"}, "invalid webhook URL"},
+		{Endpoint{Name: "ok", URL: "https://x", Filter: "nosuchfield=1"}, "invalid filter"},
+	}
+	for _, tt := range tests {
+		if err := tt.ep.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.ep, err, tt.want)
+		}
+	}
+}
+
+func TestDeliverPending(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	createIssue(t, store, "bd-1", 2) // Before the endpoint exists: never sent
+
+	recv := newReceiver(t)
+	if err := SaveEndpoint(ctx, store, &Endpoint{Name: "ci", URL: recv.srv.URL, Secret: "s3cret"}); err != nil {
+		t.Fatalf("SaveEndpoint: %v", err)
+	}
+	createIssue(t, store, "bd-2", 2)
+
+	d := testDispatcher(store)
+	report, err := d.DeliverPending(ctx)
+	if err != nil {
+		t.Fatalf("DeliverPending: %v", err)
+	}
+	if report.Delivered != 1 || len(recv.payloads) != 1 {
+		t.Fatalf("expected one delivery, got %+v (%d received)", report, len(recv.payloads))
+	}
+	p := recv.payloads[0]
+	if p.Event.IssueID != "bd-2" || p.Event.EventType != types.EventCreated || p.Issue == nil || p.Issue.ID != "bd-2" {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+	h := recv.headers[0]
+	if h.Get(EventHeader) != "created" || h.Get(DeliveryHeader) != p.Delivery {
+		t.Errorf("unexpected headers: %v", h)
+	}
+	if h.Get(SignatureHeader) != Sign("s3cret", recv.bodies[0]) {
+		t.Errorf("signature mismatch: %q", h.Get(SignatureHeader))
+	}
+
+	// The cursor is durable: a second run sends nothing new
+	report, err = NewDispatcher(store).DeliverPending(ctx)
+	if err != nil || report.Delivered != 0 {
+		t.Errorf("expected nothing pending, got %+v, %v", report, err)
+	}
+}
+
+func TestDeliverPending_Filters(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	recv := newReceiver(t)
+	ep := &Endpoint{Name: "urgent", URL: recv.srv.URL, Filter: "priority<=1", Events: []string{"created"}}
+	if err := SaveEndpoint(ctx, store, ep); err != nil {
+		t.Fatalf("SaveEndpoint: %v", err)
+	}
+	createIssue(t, store, "bd-1", 3)
+	createIssue(t, store, "bd-2", 0)
+	if err := store.AddComment(ctx, "bd-2", "alice", "not a created event"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := testDispatcher(store).DeliverPending(ctx)
+	if err != nil {
+		t.Fatalf("DeliverPending: %v", err)
+	}
+	if report.Delivered != 1 || report.Skipped != 2 {
+		t.Errorf("expected 1 delivered and 2 skipped, got %+v", report)
+	}
+	if len(recv.payloads) != 1 || recv.payloads[0].Event.IssueID != "bd-2" {
+		t.Errorf("expected only bd-2 to be delivered, got %+v", recv.payloads)
+	}
+}
+
+func TestDeadLetterAndReplay(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	recv := newReceiver(t)
+	if err := SaveEndpoint(ctx, store, &Endpoint{Name: "ci", URL: recv.srv.URL}); err != nil {
+		t.Fatalf("SaveEndpoint: %v", err)
+	}
+	recv.setStatus(http.StatusServiceUnavailable)
+	createIssue(t, store, "bd-1", 2)
+
+	d := testDispatcher(store)
+	report, err := d.DeliverPending(ctx)
+	if err != nil {
+		t.Fatalf("DeliverPending: %v", err)
+	}
+	if report.DeadLettered != 1 {
+		t.Fatalf("expected a dead letter, got %+v", report)
+	}
+	letters, err := DeadLetters(ctx, store)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("expected one dead letter, got %v, %v", letters, err)
+	}
+	if letters[0].Attempts != 3 || !strings.Contains(letters[0].Error, "503") {
+		t.Errorf("expected 3 attempts ending in 503, got %+v", letters[0])
+	}
+
+	// The outbox moved on; replay re-sends the original payload
+	recv.setStatus(0)
+	results, err := d.Replay(ctx, nil, "")
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(results) != 1 || results[0].Error != "" {
+		t.Fatalf("unexpected replay results: %+v", results)
+	}
+	if len(recv.payloads) != 1 || recv.payloads[0].Delivery != "ci:"+itoa(letters[0].EventID) {
+		t.Errorf("unexpected replayed payload: %+v", recv.payloads)
+	}
+	if letters, _ := DeadLetters(ctx, store); len(letters) != 0 {
+		t.Errorf("delivered dead letters should be removed, got %d", len(letters))
+	}
+
+	if _, err := d.Replay(ctx, []int64{42}, ""); err == nil {
+		t.Error("expected error replaying an unknown dead letter")
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	var calls int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer srv.Close()
+
+	ep := &Endpoint{Name: "ci", URL: srv.URL}
+	err := testDispatcher(store).Test(ctx, ep)
+	if err == nil || !strings.Contains(err.Error(), "410") {
+		t.Fatalf("expected 410 error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func itoa(n int64) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}